@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddAlertRuleControl(as service.AlertServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var rule domain.AlertRuleDTO
+		if !readJSON(w, r, &rule, "alert rule DTO") {
+			return
+		}
+
+		ruleData := domain.AlertRuleData{Rule: rule, Validator: validator}
+		saved, err := as.AddAlertRule(r.Context(), &ruleData)
+		if err != nil {
+			log.Println("Error adding the alert rule:", err)
+			http.Error(w, "Error adding the alert rule.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveAlertRulesControl(as service.AlertServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		rules, err := as.RetrieveAlertRules(userId)
+		if err != nil {
+			log.Println("Error retrieving alert rules:", err)
+			http.Error(w, "Error retrieving alert rules.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, rules)
+	}
+}
+
+func DeleteAlertRuleControl(as service.AlertServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		alertRuleId, ok := queryUUID(w, r, "alert-rule-id")
+		if !ok {
+			return
+		}
+
+		err := as.DeleteAlertRule(r.Context(), alertRuleId)
+		if err != nil {
+			log.Println("Error deleting the alert rule:", err)
+			http.Error(w, "Error deleting the alert rule.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RetrieveAlertsControl is the in-app inbox, with unread=true only unread alerts are returned.
+func RetrieveAlertsControl(as service.AlertServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		unreadOnly, ok := queryBool(w, r, "unread")
+		if !ok {
+			return
+		}
+
+		alerts, err := as.RetrieveAlerts(userId, unreadOnly)
+		if err != nil {
+			log.Println("Error retrieving alerts:", err)
+			http.Error(w, "Error retrieving alerts.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, alerts)
+	}
+}
+
+func MarkAlertReadControl(as service.AlertServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		alertId, ok := queryUUID(w, r, "alert-id")
+		if !ok {
+			return
+		}
+
+		err := as.MarkAlertRead(r.Context(), alertId)
+		if err != nil {
+			log.Println("Error marking the alert read:", err)
+			http.Error(w, "Error marking the alert read.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
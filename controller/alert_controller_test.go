@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAlertService struct {
+	mock.Mock
+}
+
+func (m *MockAlertService) AddAlertRule(ctx context.Context, ruleData *domain.AlertRuleData) (*domain.AlertRuleDTO, error) {
+	args := m.Called(ruleData)
+	return args.Get(0).(*domain.AlertRuleDTO), args.Error(1)
+}
+
+func (m *MockAlertService) RetrieveAlertRules(userId uuid.UUID) ([]domain.AlertRuleDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.AlertRuleDTO), args.Error(1)
+}
+
+func (m *MockAlertService) DeleteAlertRule(ctx context.Context, alertRuleId uuid.UUID) error {
+	args := m.Called(alertRuleId)
+	return args.Error(0)
+}
+
+func (m *MockAlertService) CheckTransaction(ctx context.Context, tm *domain.TransactionModel) ([]domain.AlertDTO, error) {
+	args := m.Called(tm)
+	return args.Get(0).([]domain.AlertDTO), args.Error(1)
+}
+
+func (m *MockAlertService) NotifyAnomalies(ctx context.Context, userId uuid.UUID, anomalies []domain.AnomalyDTO) ([]domain.AlertDTO, error) {
+	args := m.Called(userId, anomalies)
+	return args.Get(0).([]domain.AlertDTO), args.Error(1)
+}
+
+func (m *MockAlertService) RetrieveAlerts(userId uuid.UUID, unreadOnly bool) ([]domain.AlertDTO, error) {
+	args := m.Called(userId, unreadOnly)
+	return args.Get(0).([]domain.AlertDTO), args.Error(1)
+}
+
+func (m *MockAlertService) MarkAlertRead(ctx context.Context, alertId uuid.UUID) error {
+	args := m.Called(alertId)
+	return args.Error(0)
+}
+
+func TestRetrieveAlertsControl(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantUnread     bool
+		expectedStatus int
+	}{
+		{name: "All alerts", query: "?user-id=" + userId.String(), wantUnread: false, expectedStatus: http.StatusOK},
+		{name: "Unread alerts", query: "?user-id=" + userId.String() + "&unread=true", wantUnread: true, expectedStatus: http.StatusOK},
+		{name: "Bad unread", query: "?user-id=" + userId.String() + "&unread=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockAlertService)
+			mockService.On("RetrieveAlerts", userId, test.wantUnread).Return([]domain.AlertDTO{}, nil)
+
+			req, err := http.NewRequest("GET", "/alert/inbox"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveAlertsControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusOK {
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
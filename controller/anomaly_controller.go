@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/hld3/personal-finance-go/service"
+)
+
+// AnalyzeAnomaliesControl runs the anomaly analysis for the user right away instead of waiting
+// for the daily run, returning the anomalies it found.
+func AnalyzeAnomaliesControl(as service.AnomalyServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		anomalies, err := as.Analyze(r.Context(), userId)
+		if err != nil {
+			log.Println("Error analyzing transactions:", err)
+			http.Error(w, "Error analyzing transactions.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, anomalies)
+	}
+}
+
+func RetrieveAnomaliesControl(as service.AnomalyServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		anomalies, err := as.RetrieveAnomalies(userId)
+		if err != nil {
+			log.Println("Error retrieving anomalies:", err)
+			http.Error(w, "Error retrieving anomalies.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, anomalies)
+	}
+}
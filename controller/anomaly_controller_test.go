@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAnomalyService struct {
+	mock.Mock
+}
+
+func (m *MockAnomalyService) Analyze(ctx context.Context, userId uuid.UUID) ([]domain.AnomalyDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.AnomalyDTO), args.Error(1)
+}
+
+func (m *MockAnomalyService) RetrieveAnomalies(userId uuid.UUID) ([]domain.AnomalyDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.AnomalyDTO), args.Error(1)
+}
+
+func TestAnalyzeAnomaliesControl(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		expectedStatus int
+	}{
+		{name: "Analyze", method: "POST", query: "?user-id=" + userId.String(), expectedStatus: http.StatusOK},
+		{name: "Wrong method", method: "GET", query: "?user-id=" + userId.String(), expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Missing user", method: "POST", query: "", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockAnomalyService)
+			mockService.On("Analyze", userId).Return([]domain.AnomalyDTO{{UserId: userId, Kind: domain.REPEATED_CHARGE}}, nil)
+
+			req, err := http.NewRequest(test.method, "/anomaly/analyze"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AnalyzeAnomaliesControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusOK {
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/hld3/personal-finance-go/service"
+)
+
+// UploadAttachmentControl accepts a multipart form with the file in the "file" field.
+func UploadAttachmentControl(as service.AttachmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		transactionId, ok := queryUUID(w, r, "transaction-id")
+		if !ok {
+			return
+		}
+
+		// leave room for the multipart headers around the file.
+		r.Body = http.MaxBytesReader(w, r.Body, service.MaxAttachmentSize+1<<20)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, "Attachment is too large.", http.StatusRequestEntityTooLarge)
+				return
+			}
+			log.Println("Error reading the uploaded file:", err)
+			http.Error(w, "Error reading the uploaded file.", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, service.MaxAttachmentSize+1))
+		if err != nil {
+			log.Println("Error reading the uploaded file:", err)
+			http.Error(w, "Error reading the uploaded file.", http.StatusBadRequest)
+			return
+		}
+
+		attachment, err := as.AddAttachment(r.Context(), transactionId, header.Filename, data)
+		if err != nil {
+			log.Println("Error adding the attachment:", err)
+			switch {
+			case errors.Is(err, service.ErrAttachmentTooLarge):
+				http.Error(w, "Attachment is too large.", http.StatusRequestEntityTooLarge)
+			case errors.Is(err, service.ErrUnsupportedContentType):
+				http.Error(w, "Attachment type is not supported.", http.StatusUnsupportedMediaType)
+			case errors.Is(err, service.ErrAttachmentEmpty):
+				http.Error(w, "Attachment is empty.", http.StatusBadRequest)
+			default:
+				http.Error(w, "Error adding the attachment.", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		writeJSON(w, attachment)
+	}
+}
+
+func RetrieveAttachmentsControl(as service.AttachmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		transactionId, ok := queryUUID(w, r, "transaction-id")
+		if !ok {
+			return
+		}
+
+		attachments, err := as.RetrieveAttachments(transactionId)
+		if err != nil {
+			log.Println("Error retrieving attachments:", err)
+			http.Error(w, "Error retrieving attachments.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, attachments)
+	}
+}
+
+// DownloadAttachmentControl writes the attachment contents with the sniffed content type.
+func DownloadAttachmentControl(as service.AttachmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		attachmentId, ok := queryUUID(w, r, "attachment-id")
+		if !ok {
+			return
+		}
+
+		attachment, data, err := as.RetrieveAttachment(attachmentId)
+		if err != nil {
+			log.Println("Error retrieving the attachment:", err)
+			http.Error(w, "Error retrieving the attachment.", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", attachment.ContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Write(data)
+	}
+}
+
+func DeleteAttachmentControl(as service.AttachmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		attachmentId, ok := queryUUID(w, r, "attachment-id")
+		if !ok {
+			return
+		}
+
+		err := as.DeleteAttachment(r.Context(), attachmentId)
+		if err != nil {
+			log.Println("Error deleting the attachment:", err)
+			http.Error(w, "Error deleting the attachment.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
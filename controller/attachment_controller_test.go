@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAttachmentService struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentService) AddAttachment(ctx context.Context, transactionId uuid.UUID, fileName string, data []byte) (*domain.AttachmentDTO, error) {
+	args := m.Called(transactionId, fileName, data)
+	return args.Get(0).(*domain.AttachmentDTO), args.Error(1)
+}
+
+func (m *MockAttachmentService) RetrieveAttachments(transactionId uuid.UUID) ([]domain.AttachmentDTO, error) {
+	args := m.Called(transactionId)
+	return args.Get(0).([]domain.AttachmentDTO), args.Error(1)
+}
+
+func (m *MockAttachmentService) RetrieveAttachment(attachmentId uuid.UUID) (*domain.AttachmentDTO, []byte, error) {
+	args := m.Called(attachmentId)
+	return args.Get(0).(*domain.AttachmentDTO), args.Get(1).([]byte), args.Error(2)
+}
+
+func (m *MockAttachmentService) DeleteAttachment(ctx context.Context, attachmentId uuid.UUID) error {
+	args := m.Called(attachmentId)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentService) RemoveUnusedContents(checksums ...string) {
+	m.Called(checksums)
+}
+
+func TestUploadAttachmentControl(t *testing.T) {
+	transactionId := uuid.New()
+	data := []byte("%PDF-1.4 receipt")
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "Uploaded", serviceErr: nil, expectedStatus: http.StatusOK},
+		{name: "Too large", serviceErr: service.ErrAttachmentTooLarge, expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "Unsupported type", serviceErr: service.ErrUnsupportedContentType, expectedStatus: http.StatusUnsupportedMediaType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockAttachmentService)
+			mockService.On("AddAttachment", transactionId, "receipt.pdf", data).Return(&domain.AttachmentDTO{TransactionId: transactionId}, test.serviceErr)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile("file", "receipt.pdf")
+			if err != nil {
+				t.Fatal("Error building the form:", err)
+			}
+			part.Write(data)
+			form.Close()
+
+			req, err := http.NewRequest("POST", "/attachment/upload?transaction-id="+transactionId.String(), &body)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(UploadAttachmentControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDownloadAttachmentControl(t *testing.T) {
+	attachment := domain.AttachmentDTO{AttachmentId: uuid.New(), FileName: "receipt march.pdf", ContentType: "application/pdf"}
+	data := []byte("%PDF-1.4 receipt")
+	mockService := new(MockAttachmentService)
+	mockService.On("RetrieveAttachment", attachment.AttachmentId).Return(&attachment, data, nil)
+
+	req, err := http.NewRequest("GET", "/attachment/download?attachment-id="+attachment.AttachmentId.String(), nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(DownloadAttachmentControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+	if got := rr.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Wrong content type, got %s", got)
+	}
+	if got := rr.Header().Get("Content-Disposition"); got != `attachment; filename="receipt march.pdf"` {
+		t.Errorf("Wrong content disposition, got %s", got)
+	}
+	if !bytes.Equal(rr.Body.Bytes(), data) {
+		t.Errorf("Wrong body, got %q", rr.Body.Bytes())
+	}
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+const (
+	requestIdHeader = "X-Request-Id"
+	anonymousActor  = "anonymous"
+)
+
+// requestIdPattern is what a request ID given by the client may look like, others are replaced.
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// AuditRequests makes the caller the author of the writes made while serving the request in the
+// audit log, through the context of the request. The caller is the user of the bearer token, or
+// anonymous without a valid one. The request ID is taken from the X-Request-Id header or
+// generated, and returned in the response.
+func AuditRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(requestIdHeader)
+		if !requestIdPattern.MatchString(requestId) {
+			requestId = uuid.NewString()
+		}
+		w.Header().Set(requestIdHeader, requestId)
+
+		actor := anonymousActor
+		if r.Header.Get("Authorization") != "" {
+			userId, err := bearerUserId(r)
+			if err != nil {
+				log.Println("Error parsing the bearer token:", err)
+			} else {
+				actor = userId.String()
+			}
+		}
+
+		scope := domain.AuditScope{Actor: actor, RequestId: requestId}
+		next.ServeHTTP(w, r.WithContext(domain.WithAuditScope(r.Context(), scope)))
+	})
+}
+
+// RetrieveAuditHistoryControl is every recorded change of an entity of the signed in user, oldest
+// first. Entities with more than one key are identified by their key values joined with a slash.
+func RetrieveAuditHistoryControl(as service.AuditServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		userId, ok := requireUser(w, r)
+		if !ok {
+			return
+		}
+
+		entity, entityId := r.URL.Query().Get("entity"), r.URL.Query().Get("entity-id")
+		if entity == "" || entityId == "" {
+			http.Error(w, "The entity and entity-id are required.", http.StatusBadRequest)
+			return
+		}
+
+		history, err := as.RetrieveHistory(userId, entity, entityId)
+		if err != nil {
+			log.Println("Error retrieving the audit history:", err)
+			http.Error(w, "Error retrieving the audit history.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, history)
+	}
+}
+
+// VerifyAuditLogControl checks the hash chain of the whole audit log, for the admins only.
+func VerifyAuditLogControl(as service.AuditServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		userId, ok := requireUser(w, r)
+		if !ok {
+			return
+		}
+
+		verification, err := as.VerifyAuditLog(userId)
+		if errors.Is(err, service.ErrNotAuditAdmin) {
+			http.Error(w, "Only an admin may verify the audit log.", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			log.Println("Error verifying the audit log:", err)
+			http.Error(w, "Error verifying the audit log.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, verification)
+	}
+}
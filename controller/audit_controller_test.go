@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/hld3/personal-finance-go/utility"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) RetrieveHistory(userId uuid.UUID, entity string, entityId string) ([]domain.AuditEntryDTO, error) {
+	args := m.Called(userId, entity, entityId)
+	return args.Get(0).([]domain.AuditEntryDTO), args.Error(1)
+}
+
+func (m *MockAuditService) VerifyAuditLog(userId uuid.UUID) (*domain.AuditVerificationDTO, error) {
+	args := m.Called(userId)
+	verification, _ := args.Get(0).(*domain.AuditVerificationDTO)
+	return verification, args.Error(1)
+}
+
+func TestAuditRequests(t *testing.T) {
+	t.Setenv("JWT_KEY", "test-key")
+	userId := uuid.NewString()
+	token, err := utility.CreateJWTToken(userId, time.Hour)
+	if err != nil {
+		t.Fatal("Error creating the token:", err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		requestId     string
+		actor         string
+		echoed        bool
+	}{
+		{name: "Signed in", authorization: "Bearer " + token, requestId: "req-42", actor: userId, echoed: true},
+		{name: "Anonymous", actor: anonymousActor},
+		{name: "Bad token", authorization: "Bearer " + token + "x", actor: anonymousActor},
+		{name: "Bad request id", requestId: "<script>", actor: anonymousActor},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/transaction/list", nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			if test.requestId != "" {
+				req.Header.Set(requestIdHeader, test.requestId)
+			}
+			rr := httptest.NewRecorder()
+
+			var scope domain.AuditScope
+			handler := AuditRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				scope = domain.AuditScopeFrom(r.Context())
+			}))
+			handler.ServeHTTP(rr, req)
+
+			if scope.Actor != test.actor {
+				t.Errorf("Wrong actor: got %q, want %q", scope.Actor, test.actor)
+			}
+			requestId := rr.Header().Get(requestIdHeader)
+			_, err = uuid.Parse(requestId)
+			if requestId != scope.RequestId || (test.echoed && requestId != test.requestId) || (!test.echoed && err != nil) {
+				t.Errorf("Unexpected request id %q", requestId)
+			}
+		})
+	}
+}
+
+func TestRetrieveAuditHistoryControl(t *testing.T) {
+	t.Setenv("JWT_KEY", "test-key")
+	entityId := uuid.NewString()
+	userId := uuid.New()
+	token, err := utility.CreateJWTToken(userId.String(), time.Hour)
+	if err != nil {
+		t.Fatal("Error creating the token:", err)
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		authorization  string
+		err            error
+		expectedStatus int
+	}{
+		{name: "History", query: "?entity=transaction&entity-id=" + entityId, authorization: "Bearer " + token, err: nil, expectedStatus: http.StatusOK},
+		{name: "Store failure", query: "?entity=transaction&entity-id=" + entityId, authorization: "Bearer " + token, err: errors.New("database is locked"), expectedStatus: http.StatusInternalServerError},
+		{name: "Missing entity id", query: "?entity=transaction", authorization: "Bearer " + token, expectedStatus: http.StatusBadRequest},
+		{name: "No token", query: "?entity=transaction&entity-id=" + entityId, expectedStatus: http.StatusUnauthorized},
+		{name: "Bad token", query: "?entity=transaction&entity-id=" + entityId, authorization: "Bearer " + token + "x", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockAuditService)
+			mockService.On("RetrieveHistory", userId, "transaction", entityId).Return([]domain.AuditEntryDTO{{Seq: 1, Action: domain.AUDIT_CREATE}}, test.err)
+
+			req, err := http.NewRequest("GET", "/audit/history"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveAuditHistoryControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusOK || test.expectedStatus == http.StatusInternalServerError {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "RetrieveHistory", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditLogControl(t *testing.T) {
+	t.Setenv("JWT_KEY", "test-key")
+	admin, userId := uuid.New(), uuid.New()
+	adminToken, err := utility.CreateJWTToken(admin.String(), time.Hour)
+	if err != nil {
+		t.Fatal("Error creating the token:", err)
+	}
+	userToken, err := utility.CreateJWTToken(userId.String(), time.Hour)
+	if err != nil {
+		t.Fatal("Error creating the token:", err)
+	}
+
+	tests := []struct {
+		name           string
+		authorization  string
+		called         bool
+		expectedStatus int
+	}{
+		{name: "Verified", authorization: "Bearer " + adminToken, called: true, expectedStatus: http.StatusOK},
+		{name: "Not an admin", authorization: "Bearer " + userToken, called: true, expectedStatus: http.StatusForbidden},
+		{name: "No token", expectedStatus: http.StatusUnauthorized},
+		{name: "Bad token", authorization: "Bearer " + adminToken + "x", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockAuditService)
+			mockService.On("VerifyAuditLog", admin).Return(&domain.AuditVerificationDTO{Valid: true, Entries: 3, LastSeq: 3}, nil)
+			mockService.On("VerifyAuditLog", userId).Return(nil, service.ErrNotAuditAdmin)
+
+			req, err := http.NewRequest("GET", "/audit/verify", nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(VerifyAuditLogControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if !test.called {
+				mockService.AssertNotCalled(t, "VerifyAuditLog", mock.Anything)
+			}
+		})
+	}
+}
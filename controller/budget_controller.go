@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func SetBudgetControl(bs service.BudgetServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var budget domain.BudgetDTO
+		if !readJSON(w, r, &budget, "budget DTO") {
+			return
+		}
+
+		budgetData := domain.BudgetData{Budget: budget, Validator: validator}
+		saved, err := bs.SetBudget(r.Context(), &budgetData)
+		if err != nil {
+			log.Println("Error setting the budget:", err)
+			http.Error(w, "Error setting the budget.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveBudgetsControl(bs service.BudgetServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		budgets, err := bs.RetrieveBudgets(userId)
+		if err != nil {
+			log.Println("Error retrieving budgets:", err)
+			http.Error(w, "Error retrieving budgets.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, budgets)
+	}
+}
+
+func DeleteBudgetControl(bs service.BudgetServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		categoryId, ok := queryInt64(w, r, "category-id", 0)
+		if !ok {
+			return
+		}
+		month, ok := queryMonth(w, r, "month")
+		if !ok {
+			return
+		}
+
+		err := bs.DeleteBudget(r.Context(), userId, categoryId, month)
+		if err != nil {
+			log.Println("Error deleting the budget:", err)
+			http.Error(w, "Error deleting the budget.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RetrieveBudgetReportControl returns budgeted vs actual vs remaining per category for the month,
+// the current month when none is given.
+func RetrieveBudgetReportControl(bs service.BudgetServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		month, ok := queryMonth(w, r, "month")
+		if !ok {
+			return
+		}
+
+		report, err := bs.RetrieveBudgetReport(userId, month)
+		if err != nil {
+			log.Println("Error retrieving the budget report:", err)
+			http.Error(w, "Error retrieving the budget report.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, report)
+	}
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBudgetService struct {
+	mock.Mock
+}
+
+func (m *MockBudgetService) SetBudget(ctx context.Context, budgetData *domain.BudgetData) (*domain.BudgetDTO, error) {
+	args := m.Called(budgetData)
+	return args.Get(0).(*domain.BudgetDTO), args.Error(1)
+}
+
+func (m *MockBudgetService) RetrieveBudgets(userId uuid.UUID) ([]domain.BudgetDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.BudgetDTO), args.Error(1)
+}
+
+func (m *MockBudgetService) DeleteBudget(ctx context.Context, userId uuid.UUID, categoryId int64, month string) error {
+	args := m.Called(userId, categoryId, month)
+	return args.Error(0)
+}
+
+func (m *MockBudgetService) RetrieveBudgetReport(userId uuid.UUID, month string) (*domain.BudgetReportDTO, error) {
+	args := m.Called(userId, month)
+	return args.Get(0).(*domain.BudgetReportDTO), args.Error(1)
+}
+
+func TestRetrieveBudgetReportControl(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "Month", query: "?user-id=" + userId.String() + "&month=2026-03", expectedStatus: http.StatusOK},
+		{name: "Bad month", query: "?user-id=" + userId.String() + "&month=03-2026", expectedStatus: http.StatusBadRequest},
+		{name: "Bad user", query: "?user-id=someone&month=2026-03", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockBudgetService)
+			mockService.On("RetrieveBudgetReport", userId, "2026-03").Return(&domain.BudgetReportDTO{UserId: userId, Month: "2026-03"}, nil)
+
+			req, err := http.NewRequest("GET", "/budget/report"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveBudgetReportControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
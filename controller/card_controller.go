@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddCardControl(cs service.CardServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var card domain.CardAccountDTO
+		if !readJSON(w, r, &card, "card DTO") {
+			return
+		}
+
+		cardData := domain.CardAccountData{Card: card, Validator: validator}
+		saved, err := cs.AddCard(r.Context(), &cardData)
+		if err != nil {
+			log.Println("Error adding the card:", err)
+			if errors.Is(err, service.ErrCardExists) {
+				http.Error(w, "The account already has a card.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error adding the card.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveCardsControl(cs service.CardServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		cards, err := cs.RetrieveCards(userId)
+		if err != nil {
+			log.Println("Error retrieving cards:", err)
+			http.Error(w, "Error retrieving cards.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, cards)
+	}
+}
+
+func DeleteCardControl(cs service.CardServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		cardId, ok := queryUUID(w, r, "card-id")
+		if !ok {
+			return
+		}
+
+		err := cs.DeleteCard(r.Context(), cardId)
+		if err != nil {
+			log.Println("Error deleting the card:", err)
+			http.Error(w, "Error deleting the card.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RetrieveCardStatementsControl returns the closed statements of the card with their payments
+// and the balance of the cycle still open.
+func RetrieveCardStatementsControl(cs service.CardServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		cardId, ok := queryUUID(w, r, "card-id")
+		if !ok {
+			return
+		}
+
+		statements, err := cs.RetrieveStatements(cardId)
+		if err != nil {
+			log.Println("Error retrieving the card statements:", err)
+			http.Error(w, "Error retrieving the card statements.", http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, statements)
+	}
+}
+
+// RetrieveUpcomingPaymentsControl lists the card statements of the user still to be paid.
+func RetrieveUpcomingPaymentsControl(cs service.CardServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		upcoming, err := cs.RetrieveUpcomingPayments(userId)
+		if err != nil {
+			log.Println("Error retrieving upcoming card payments:", err)
+			http.Error(w, "Error retrieving upcoming card payments.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, upcoming)
+	}
+}
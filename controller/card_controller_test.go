@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCardService struct {
+	mock.Mock
+}
+
+func (m *MockCardService) AddCard(ctx context.Context, cardData *domain.CardAccountData) (*domain.CardAccountDTO, error) {
+	args := m.Called(cardData)
+	return args.Get(0).(*domain.CardAccountDTO), args.Error(1)
+}
+
+func (m *MockCardService) RetrieveCards(userId uuid.UUID) ([]domain.CardAccountDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.CardAccountDTO), args.Error(1)
+}
+
+func (m *MockCardService) DeleteCard(ctx context.Context, cardId uuid.UUID) error {
+	args := m.Called(cardId)
+	return args.Error(0)
+}
+
+func (m *MockCardService) RetrieveStatements(cardId uuid.UUID) (*domain.CardStatementsDTO, error) {
+	args := m.Called(cardId)
+	return args.Get(0).(*domain.CardStatementsDTO), args.Error(1)
+}
+
+func (m *MockCardService) RetrieveUpcomingPayments(userId uuid.UUID) ([]domain.UpcomingPaymentDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.UpcomingPaymentDTO), args.Error(1)
+}
+
+func TestAddCardControl(t *testing.T) {
+	card := domain.CardAccountDTO{UserId: uuid.New(), AccountId: 3, Name: "Visa", ClosingDay: 25, DueDay: 20}
+	cardJSON, err := json.Marshal(card)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Added", err: nil, expectedStatus: http.StatusOK},
+		{name: "Account has a card", err: service.ErrCardExists, expectedStatus: http.StatusConflict},
+		{name: "Invalid", err: errors.New("validation failed"), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockCardService)
+			mockService.On("AddCard", mock.Anything).Return(&card, test.err)
+
+			req, err := http.NewRequest("POST", "/card/add", bytes.NewBuffer(cardJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddCardControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetrieveUpcomingPaymentsControl(t *testing.T) {
+	userId := uuid.New()
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Retrieved", err: nil, expectedStatus: http.StatusOK},
+		{name: "Failed", err: errors.New("database down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockCardService)
+			mockService.On("RetrieveUpcomingPayments", userId).Return([]domain.UpcomingPaymentDTO{}, test.err)
+
+			req, err := http.NewRequest("GET", "/card/upcoming?user-id="+userId.String(), nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveUpcomingPaymentsControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
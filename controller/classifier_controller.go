@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func SuggestCategoriesControl(cs service.ClassifierServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		transactionId, ok := queryUUID(w, r, "transaction-id")
+		if !ok {
+			return
+		}
+		limit, ok := queryInt(w, r, "limit", 3)
+		if !ok {
+			return
+		}
+
+		suggestions, err := cs.SuggestForTransaction(transactionId, limit)
+		if err != nil {
+			log.Println("Error suggesting categories:", err)
+			http.Error(w, "Error suggesting categories.", http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, suggestions)
+	}
+}
+
+func CorrectCategoryControl(cs service.ClassifierServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var correction domain.CategoryCorrectionDTO
+		if !readJSON(w, r, &correction, "category correction DTO") {
+			return
+		}
+
+		correctionData := domain.CategoryCorrectionData{Correction: correction, Validator: validator}
+		err := cs.CorrectCategory(r.Context(), &correctionData)
+		if err != nil {
+			log.Println("Error correcting the category:", err)
+			if errors.Is(err, service.ErrTransactionReconciled) {
+				http.Error(w, "The transaction is reconciled.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error correcting the category.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// TrainClassifierControl rebuilds the users classifier from all of their categorized transactions.
+func TrainClassifierControl(cs service.ClassifierServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		err := cs.Train(r.Context(), userId)
+		if err != nil {
+			log.Println("Error training the classifier:", err)
+			http.Error(w, "Error training the classifier.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
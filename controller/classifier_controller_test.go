@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockClassifierService struct {
+	mock.Mock
+}
+
+func (m *MockClassifierService) Suggest(tm *domain.TransactionModel, limit int) ([]domain.CategorySuggestionDTO, error) {
+	args := m.Called(tm, limit)
+	return args.Get(0).([]domain.CategorySuggestionDTO), args.Error(1)
+}
+
+func (m *MockClassifierService) SuggestForTransaction(transactionId uuid.UUID, limit int) ([]domain.CategorySuggestionDTO, error) {
+	args := m.Called(transactionId, limit)
+	return args.Get(0).([]domain.CategorySuggestionDTO), args.Error(1)
+}
+
+func (m *MockClassifierService) Learn(ctx context.Context, transactions ...domain.TransactionModel) error {
+	args := m.Called(transactions)
+	return args.Error(0)
+}
+
+func (m *MockClassifierService) Unlearn(ctx context.Context, transactions ...domain.TransactionModel) error {
+	args := m.Called(transactions)
+	return args.Error(0)
+}
+
+func (m *MockClassifierService) CorrectCategory(ctx context.Context, correctionData *domain.CategoryCorrectionData) error {
+	args := m.Called(correctionData)
+	return args.Error(0)
+}
+
+func (m *MockClassifierService) Train(ctx context.Context, userId uuid.UUID) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+func TestSuggestCategoriesControl(t *testing.T) {
+	transactionId := uuid.New()
+	suggestions := []domain.CategorySuggestionDTO{{CategoryId: 2, Confidence: 0.8}, {CategoryId: 5, Confidence: 0.2}}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantLimit      int
+		expectedStatus int
+	}{
+		{name: "Default limit", query: "?transaction-id=" + transactionId.String(), wantLimit: 3, expectedStatus: http.StatusOK},
+		{name: "Given limit", query: "?transaction-id=" + transactionId.String() + "&limit=2", wantLimit: 2, expectedStatus: http.StatusOK},
+		{name: "Bad limit", query: "?transaction-id=" + transactionId.String() + "&limit=two", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockClassifierService)
+			mockService.On("SuggestForTransaction", transactionId, test.wantLimit).Return(suggestions, nil)
+
+			req, err := http.NewRequest("GET", "/category/suggest"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(SuggestCategoriesControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Fatalf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusOK {
+				var got []domain.CategorySuggestionDTO
+				if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil || len(got) != 2 {
+					t.Errorf("Wrong response body, got %s", rr.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+// ImportRatesControl saves the exchange rates of an ECB XML or CSV file sent as the request body.
+func ImportRatesControl(cs service.CurrencyServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, service.MaxRateFileSize)
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, "Exchange rate file is too large.", http.StatusRequestEntityTooLarge)
+				return
+			}
+			log.Println("Error reading request body:", err)
+			http.Error(w, "Error reading request body.", http.StatusBadRequest)
+			return
+		}
+
+		imported, err := cs.ImportRates(data)
+		if err != nil {
+			log.Println("Error importing exchange rates:", err)
+			http.Error(w, "Error importing exchange rates.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, imported)
+	}
+}
+
+// ConvertCurrencyControl converts the amount from one currency to another with the rates on
+// the date, by default now.
+func ConvertCurrencyControl(cs service.CurrencyServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		amountStr := r.URL.Query().Get("amount")
+		amount, err := strconv.ParseFloat(amountStr, 64)
+		if err != nil {
+			log.Println("Error converting the given amount:", err)
+			http.Error(w, fmt.Sprintf("Error converting the given amount: %s", amountStr), http.StatusBadRequest)
+			return
+		}
+		from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+		if from == "" || to == "" {
+			http.Error(w, "Both the from and to currencies are required.", http.StatusBadRequest)
+			return
+		}
+		date, ok := queryInt64(w, r, "date", time.Now().UnixMilli())
+		if !ok {
+			return
+		}
+
+		conversion, err := cs.Convert(amount, from, to, date)
+		if err != nil {
+			log.Println("Error converting the amount:", err)
+			if errors.Is(err, service.ErrNoExchangeRate) {
+				http.Error(w, "No exchange rate for the date.", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "Error converting the amount.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, conversion)
+	}
+}
+
+func RetrieveBaseCurrencyControl(cs service.CurrencyServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		currency, err := cs.RetrieveBaseCurrency(userId)
+		if err != nil {
+			log.Println("Error retrieving the base currency:", err)
+			http.Error(w, "Error retrieving the base currency.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, domain.BaseCurrencyDTO{UserId: userId, Currency: currency})
+	}
+}
+
+// SetBaseCurrencyControl changes the users base currency, converting their transactions again.
+func SetBaseCurrencyControl(cs service.CurrencyServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var baseCurrency domain.BaseCurrencyDTO
+		if !readJSON(w, r, &baseCurrency, "base currency DTO") {
+			return
+		}
+
+		baseData := domain.BaseCurrencyData{BaseCurrency: baseCurrency, Validator: validator}
+		change, err := cs.SetBaseCurrency(r.Context(), &baseData)
+		if err != nil {
+			log.Println("Error setting the base currency:", err)
+			if errors.Is(err, service.ErrTransactionReconciled) {
+				http.Error(w, "A transaction is reconciled.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error setting the base currency.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, change)
+	}
+}
+
+func SetAccountCurrencyControl(cs service.CurrencyServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var accountCurrency domain.AccountCurrencyDTO
+		if !readJSON(w, r, &accountCurrency, "account currency DTO") {
+			return
+		}
+
+		accountData := domain.AccountCurrencyData{AccountCurrency: accountCurrency, Validator: validator}
+		err := cs.SetAccountCurrency(r.Context(), &accountData)
+		if err != nil {
+			log.Println("Error setting the account currency:", err)
+			http.Error(w, "Error setting the account currency.", http.StatusBadRequest)
+			return
+		}
+	}
+}
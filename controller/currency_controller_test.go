@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCurrencyService struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyService) ImportRates(data []byte) (*domain.RateImportDTO, error) {
+	args := m.Called(data)
+	return args.Get(0).(*domain.RateImportDTO), args.Error(1)
+}
+
+func (m *MockCurrencyService) Convert(amount float64, from string, to string, date int64) (*domain.ConversionDTO, error) {
+	args := m.Called(amount, from, to, date)
+	return args.Get(0).(*domain.ConversionDTO), args.Error(1)
+}
+
+func (m *MockCurrencyService) RetrieveBaseCurrency(userId uuid.UUID) (string, error) {
+	args := m.Called(userId)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCurrencyService) SetBaseCurrency(ctx context.Context, baseData *domain.BaseCurrencyData) (*domain.BaseCurrencyChangeDTO, error) {
+	args := m.Called(baseData)
+	return args.Get(0).(*domain.BaseCurrencyChangeDTO), args.Error(1)
+}
+
+func (m *MockCurrencyService) SetAccountCurrency(ctx context.Context, accountData *domain.AccountCurrencyData) error {
+	args := m.Called(accountData)
+	return args.Error(0)
+}
+
+func (m *MockCurrencyService) RetrieveAccountCurrency(userId uuid.UUID, accountId int64) (string, error) {
+	args := m.Called(userId, accountId)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCurrencyService) ConvertTransaction(tm *domain.TransactionModel) error {
+	args := m.Called(tm)
+	return args.Error(0)
+}
+
+func TestImportRatesControl(t *testing.T) {
+	data := []byte("Date,USD\n2026-03-02,1.08\n")
+	mockService := new(MockCurrencyService)
+	mockService.On("ImportRates", data).Return(&domain.RateImportDTO{Rates: 1, Currencies: 1, From: "2026-03-02", To: "2026-03-02"}, nil)
+
+	req, err := http.NewRequest("POST", "/currency/rates/import", bytes.NewBuffer(data))
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(ImportRatesControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+	var imported domain.RateImportDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &imported); err != nil || imported.Rates != 1 {
+		t.Errorf("Unexpected response %s", rr.Body.String())
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertCurrencyControl(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Converted", query: "amount=10&from=USD&to=GBP&date=100", expectedStatus: http.StatusOK},
+		{name: "No rate", query: "amount=10&from=USD&to=GBP&date=100", err: fmt.Errorf("%w: USD", service.ErrNoExchangeRate), expectedStatus: http.StatusNotFound},
+		{name: "Failed", query: "amount=10&from=USD&to=GBP&date=100", err: errors.New("database down"), expectedStatus: http.StatusInternalServerError},
+		{name: "Bad amount", query: "amount=ten&from=USD&to=GBP", expectedStatus: http.StatusBadRequest},
+		{name: "Missing currency", query: "amount=10&from=USD", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockCurrencyService)
+			if test.expectedStatus != http.StatusBadRequest {
+				mockService.On("Convert", 10.0, "USD", "GBP", int64(100)).Return(&domain.ConversionDTO{Converted: 8.5}, test.err)
+			}
+
+			req, err := http.NewRequest("GET", "/currency/convert?"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(ConvertCurrencyControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetBaseCurrencyControl(t *testing.T) {
+	baseCurrency := domain.BaseCurrencyDTO{UserId: uuid.New(), Currency: "USD"}
+	baseJSON, err := json.Marshal(baseCurrency)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Changed", expectedStatus: http.StatusOK},
+		{name: "Reconciled transaction", err: service.ErrTransactionReconciled, expectedStatus: http.StatusConflict},
+		{name: "Missing rate", err: service.ErrNoExchangeRate, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockCurrencyService)
+			var change *domain.BaseCurrencyChangeDTO
+			if test.err == nil {
+				change = &domain.BaseCurrencyChangeDTO{UserId: baseCurrency.UserId, Currency: "USD", Transactions: 3}
+			}
+			mockService.On("SetBaseCurrency", mock.Anything).Return(change, test.err)
+
+			req, err := http.NewRequest("PUT", "/currency/base/set", bytes.NewBuffer(baseJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(SetBaseCurrencyControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
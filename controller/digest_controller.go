@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func SetDigestScheduleControl(ds service.DigestServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var schedule domain.DigestScheduleDTO
+		if !readJSON(w, r, &schedule, "digest schedule DTO") {
+			return
+		}
+
+		scheduleData := domain.DigestScheduleData{Schedule: schedule, Validator: validator}
+		saved, err := ds.SetDigestSchedule(r.Context(), &scheduleData)
+		if err != nil {
+			log.Println("Error setting the digest schedule:", err)
+			http.Error(w, "Error setting the digest schedule.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveDigestScheduleControl(ds service.DigestServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		schedule, err := ds.RetrieveDigestSchedule(userId)
+		if err != nil {
+			log.Println("Error retrieving the digest schedule:", err)
+			http.Error(w, "Digest schedule not found.", http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, schedule)
+	}
+}
+
+func DeleteDigestScheduleControl(ds service.DigestServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		err := ds.DeleteDigestSchedule(r.Context(), userId)
+		if err != nil {
+			log.Println("Error deleting the digest schedule:", err)
+			http.Error(w, "Error deleting the digest schedule.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// PreviewDigestControl composes the users digest as if it were sent now, as JSON or, with
+// format=html, as the HTML email.
+func PreviewDigestControl(ds service.DigestServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "json" && format != "html" {
+			http.Error(w, fmt.Sprintf("Unknown preview format: %s", format), http.StatusBadRequest)
+			return
+		}
+
+		digest, err := ds.PreviewDigest(userId)
+		if err != nil {
+			log.Println("Error previewing the digest:", err)
+			http.Error(w, "Error previewing the digest.", http.StatusInternalServerError)
+			return
+		}
+
+		if format == "html" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(digest.HTML))
+			return
+		}
+		writeJSON(w, digest)
+	}
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDigestService struct {
+	mock.Mock
+}
+
+func (m *MockDigestService) SetDigestSchedule(ctx context.Context, scheduleData *domain.DigestScheduleData) (*domain.DigestScheduleDTO, error) {
+	args := m.Called(scheduleData)
+	return args.Get(0).(*domain.DigestScheduleDTO), args.Error(1)
+}
+
+func (m *MockDigestService) RetrieveDigestSchedule(userId uuid.UUID) (*domain.DigestScheduleDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).(*domain.DigestScheduleDTO), args.Error(1)
+}
+
+func (m *MockDigestService) DeleteDigestSchedule(ctx context.Context, userId uuid.UUID) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+func (m *MockDigestService) PreviewDigest(userId uuid.UUID) (*domain.DigestDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).(*domain.DigestDTO), args.Error(1)
+}
+
+func TestSetDigestScheduleControl(t *testing.T) {
+	schedule := domain.DigestScheduleDTO{UserId: uuid.New(), Frequency: domain.DIGEST_MONTHLY, Day: 1, Hour: 7, Timezone: "Europe/Berlin"}
+	scheduleJSON, err := json.Marshal(schedule)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Set", method: "PUT", err: nil, expectedStatus: http.StatusOK},
+		{name: "Invalid", method: "PUT", err: errors.New("weekly digests need a day of the week from 0 to 6"), expectedStatus: http.StatusBadRequest},
+		{name: "Wrong method", method: "POST", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockDigestService)
+			mockService.On("SetDigestSchedule", mock.Anything).Return(&schedule, test.err)
+
+			req, err := http.NewRequest(test.method, "/digest/schedule/set", bytes.NewBuffer(scheduleJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(SetDigestScheduleControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestRetrieveDigestScheduleControl(t *testing.T) {
+	userId := uuid.New()
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Found", err: nil, expectedStatus: http.StatusOK},
+		{name: "No schedule", err: sql.ErrNoRows, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockDigestService)
+			mockService.On("RetrieveDigestSchedule", userId).Return(&domain.DigestScheduleDTO{UserId: userId}, test.err)
+
+			req, err := http.NewRequest("GET", "/digest/schedule?user-id="+userId.String(), nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveDigestScheduleControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPreviewDigestControl(t *testing.T) {
+	userId := uuid.New()
+	digest := domain.DigestDTO{UserId: userId, Subject: "Your weekly spending digest", HTML: "<h2>Your weekly spending digest</h2>"}
+
+	tests := []struct {
+		name           string
+		format         string
+		expectedStatus int
+		expectedType   string
+	}{
+		{name: "JSON", format: "", expectedStatus: http.StatusOK, expectedType: "application/json"},
+		{name: "HTML", format: "html", expectedStatus: http.StatusOK, expectedType: "text/html; charset=utf-8"},
+		{name: "Unknown format", format: "pdf", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockDigestService)
+			mockService.On("PreviewDigest", userId).Return(&digest, nil)
+
+			req, err := http.NewRequest("GET", "/digest/preview?user-id="+userId.String()+"&format="+test.format, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(PreviewDigestControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Fatalf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if contentType := rr.Header().Get("Content-Type"); test.expectedStatus == http.StatusOK && contentType != test.expectedType {
+				t.Errorf("Wrong content type: got %v, want %v", contentType, test.expectedType)
+			}
+		})
+	}
+}
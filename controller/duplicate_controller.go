@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func RetrieveSuspectedDuplicatesControl(ds service.DuplicateServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		duplicates, err := ds.RetrieveSuspectedDuplicates(userId)
+		if err != nil {
+			log.Println("Error retrieving suspected duplicates:", err)
+			http.Error(w, "Error retrieving suspected duplicates.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, duplicates)
+	}
+}
+
+func MergeDuplicateControl(ds service.DuplicateServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return resolveDuplicateControl(ds.MergeDuplicate, validator)
+}
+
+func DismissDuplicateControl(ds service.DuplicateServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return resolveDuplicateControl(ds.DismissDuplicate, validator)
+}
+
+func resolveDuplicateControl(resolve func(context.Context, *domain.DuplicateResolutionData) error, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var resolution domain.DuplicateResolutionDTO
+		if !readJSON(w, r, &resolution, "duplicate resolution DTO") {
+			return
+		}
+
+		resolutionData := domain.DuplicateResolutionData{Resolution: resolution, Validator: validator}
+		err := resolve(r.Context(), &resolutionData)
+		if err != nil {
+			log.Println("Error resolving the duplicate:", err)
+			http.Error(w, "Error resolving the duplicate.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
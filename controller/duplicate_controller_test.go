@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDuplicateService struct {
+	mock.Mock
+}
+
+func (m *MockDuplicateService) CheckTransaction(ctx context.Context, tm *domain.TransactionModel) ([]domain.DuplicateDTO, error) {
+	args := m.Called(tm)
+	return args.Get(0).([]domain.DuplicateDTO), args.Error(1)
+}
+
+func (m *MockDuplicateService) RetrieveSuspectedDuplicates(userId uuid.UUID) ([]domain.DuplicateDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.DuplicateDTO), args.Error(1)
+}
+
+func (m *MockDuplicateService) MergeDuplicate(ctx context.Context, data *domain.DuplicateResolutionData) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockDuplicateService) DismissDuplicate(ctx context.Context, data *domain.DuplicateResolutionData) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func TestRetrieveSuspectedDuplicatesControl(t *testing.T) {
+	userId := uuid.New()
+	duplicates := []domain.DuplicateDTO{{DuplicateId: uuid.New(), UserId: userId, Reason: "same amount"}}
+
+	tests := []struct {
+		name           string
+		userId         string
+		expectedStatus int
+	}{
+		{name: "Valid user id", userId: userId.String(), expectedStatus: http.StatusOK},
+		{name: "Invalid user id", userId: "not-a-uuid", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockDuplicateService)
+			mockService.On("RetrieveSuspectedDuplicates", userId).Return(duplicates, nil)
+
+			req, err := http.NewRequest("GET", "/duplicate/suspected?user-id="+test.userId, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveSuspectedDuplicatesControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestResolveDuplicateControl(t *testing.T) {
+	resolution := domain.DuplicateResolutionDTO{DuplicateId: uuid.New()}
+	resolutionJSON, err := json.Marshal(resolution)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		serviceMethod  string
+		mockReturnErr  error
+		expectedStatus int
+	}{
+		{name: "Merge", serviceMethod: "MergeDuplicate", expectedStatus: http.StatusOK},
+		{name: "Dismiss", serviceMethod: "DismissDuplicate", expectedStatus: http.StatusOK},
+		{name: "Service error", serviceMethod: "MergeDuplicate", mockReturnErr: errors.New("already resolved"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockDuplicateService)
+			mockService.On(test.serviceMethod, mock.AnythingOfType("*domain.DuplicateResolutionData")).Return(test.mockReturnErr)
+
+			req, err := http.NewRequest("PUT", "/duplicate", bytes.NewBuffer(resolutionJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			var handler http.HandlerFunc
+			if test.serviceMethod == "MergeDuplicate" {
+				handler = MergeDuplicateControl(mockService, validator.New())
+			} else {
+				handler = DismissDuplicateControl(mockService, validator.New())
+			}
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/export"
+)
+
+// exportDate formats epoch milliseconds as the UTC date.
+func exportDate(millis int64) string {
+	return time.UnixMilli(millis).UTC().Format(time.DateOnly)
+}
+
+// transactionsDocument lists the transactions as a statement, from and to are the queried
+// range and only shown when given.
+func transactionsDocument(transactions []domain.TransactionDTO, from, to int64) *export.Document {
+	title := "Transactions"
+	if from > 0 || to < math.MaxInt64 {
+		title += " " + exportDate(from) + " to " + exportDate(to)
+	}
+
+	sheet := export.Sheet{
+		Name:    "Transactions",
+		Columns: []string{"Date", "Description", "Type", "Payment method", "Status", "Category", "Account", "Tags", "Currency", "Original amount", "Amount"},
+		Rows:    make([][]any, 0, len(transactions)),
+	}
+	for i := range transactions {
+		t := &transactions[i]
+		sheet.Rows = append(sheet.Rows, []any{
+			exportDate(t.Date),
+			t.Description,
+			t.Type.String(),
+			t.PaymentMethod.String(),
+			t.Status.String(),
+			t.CategoryId,
+			t.AccountId,
+			strings.Join(t.Tags, "; "),
+			t.Currency,
+			t.OriginalAmount,
+			t.Amount,
+		})
+	}
+	return &export.Document{Title: title, Sheets: []export.Sheet{sheet}}
+}
+
+// reportDocument has a sheet for the totals and one for each breakdown of the report.
+func reportDocument(report *domain.ReportDTO) *export.Document {
+	totalsColumns := []string{"Income", "Expense", "Net", "Count"}
+	totalsRow := func(name string, totals domain.ReportTotalsDTO) []any {
+		return []any{name, totals.Income, totals.Expense, totals.Net, totals.Count}
+	}
+
+	summary := export.Sheet{
+		Name:    "Summary",
+		Columns: append([]string{"Range"}, totalsColumns...),
+		Rows: [][]any{
+			totalsRow("This range", report.Totals),
+			totalsRow("Previous range", report.Previous),
+			totalsRow("Change", report.Delta),
+		},
+	}
+	periods := export.Sheet{
+		Name:    "Periods",
+		Columns: []string{"Period", "From", "To", "Income", "Expense", "Net", "Count", "Net change"},
+	}
+	for _, p := range report.Periods {
+		periods.Rows = append(periods.Rows, []any{p.Period, exportDate(p.From), exportDate(p.To), p.Totals.Income, p.Totals.Expense, p.Totals.Net, p.Totals.Count, p.Delta.Net})
+	}
+	categories := export.Sheet{
+		Name:    "Categories",
+		Columns: []string{"Category", "Income", "Expense", "Income %", "Expense %", "Count"},
+	}
+	for _, c := range report.Categories {
+		categories.Rows = append(categories.Rows, []any{c.CategoryId, c.Income, c.Expense, c.IncomePercent, c.ExpensePercent, c.Count})
+	}
+	methods := export.Sheet{
+		Name:    "Payment methods",
+		Columns: append([]string{"Payment method"}, totalsColumns...),
+	}
+	for _, m := range report.PaymentMethods {
+		methods.Rows = append(methods.Rows, totalsRow(m.PaymentMethod.String(), m.Totals))
+	}
+	statuses := export.Sheet{
+		Name:    "Statuses",
+		Columns: append([]string{"Status"}, totalsColumns...),
+	}
+	for _, s := range report.Statuses {
+		statuses.Rows = append(statuses.Rows, totalsRow(s.Status.String(), s.Totals))
+	}
+
+	return &export.Document{
+		Title:  "Report " + exportDate(report.From) + " to " + exportDate(report.To),
+		Sheets: []export.Sheet{summary, periods, categories, methods, statuses},
+	}
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddScheduledItemControl(fs service.ForecastServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var item domain.ScheduledItemDTO
+		if !readJSON(w, r, &item, "scheduled item DTO") {
+			return
+		}
+
+		itemData := domain.ScheduledItemData{ScheduledItem: item, Validator: validator}
+		saved, err := fs.AddScheduledItem(r.Context(), &itemData)
+		if err != nil {
+			log.Println("Error adding the scheduled item:", err)
+			http.Error(w, "Error adding the scheduled item.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveScheduledItemsControl(fs service.ForecastServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		items, err := fs.RetrieveScheduledItems(userId)
+		if err != nil {
+			log.Println("Error retrieving scheduled items:", err)
+			http.Error(w, "Error retrieving scheduled items.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, items)
+	}
+}
+
+func DeleteScheduledItemControl(fs service.ForecastServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		scheduledItemId, ok := queryUUID(w, r, "scheduled-item-id")
+		if !ok {
+			return
+		}
+
+		err := fs.DeleteScheduledItem(r.Context(), scheduledItemId)
+		if err != nil {
+			log.Println("Error deleting the scheduled item:", err)
+			http.Error(w, "Error deleting the scheduled item.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// ForecastControl projects daily balances for the next days (30 by default) of the account,
+// or of every account when no account-id is given.
+func ForecastControl(fs service.ForecastServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		accountId, ok := queryInt64(w, r, "account-id", 0)
+		if !ok {
+			return
+		}
+		days, ok := queryInt(w, r, "days", service.DefaultForecastDays)
+		if !ok {
+			return
+		}
+		if days < 1 || days > service.MaxForecastDays {
+			http.Error(w, "Error converting the given days: out of range", http.StatusBadRequest)
+			return
+		}
+
+		forecasts, err := fs.Forecast(userId, accountId, days)
+		if err != nil {
+			log.Println("Error forecasting balances:", err)
+			http.Error(w, "Error forecasting balances.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, forecasts)
+	}
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockForecastService struct {
+	mock.Mock
+}
+
+func (m *MockForecastService) AddScheduledItem(ctx context.Context, itemData *domain.ScheduledItemData) (*domain.ScheduledItemDTO, error) {
+	args := m.Called(itemData)
+	return args.Get(0).(*domain.ScheduledItemDTO), args.Error(1)
+}
+
+func (m *MockForecastService) RetrieveScheduledItems(userId uuid.UUID) ([]domain.ScheduledItemDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.ScheduledItemDTO), args.Error(1)
+}
+
+func (m *MockForecastService) DeleteScheduledItem(ctx context.Context, scheduledItemId uuid.UUID) error {
+	args := m.Called(scheduledItemId)
+	return args.Error(0)
+}
+
+func (m *MockForecastService) Forecast(userId uuid.UUID, accountId int64, days int) ([]domain.ForecastDTO, error) {
+	args := m.Called(userId, accountId, days)
+	return args.Get(0).([]domain.ForecastDTO), args.Error(1)
+}
+
+func TestForecastControl(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantAccountId  int64
+		wantDays       int
+		expectedStatus int
+	}{
+		{name: "Defaults", query: "?user-id=" + userId.String(), wantAccountId: 0, wantDays: 30, expectedStatus: http.StatusOK},
+		{name: "Account and days", query: "?user-id=" + userId.String() + "&account-id=2&days=90", wantAccountId: 2, wantDays: 90, expectedStatus: http.StatusOK},
+		{name: "Too many days", query: "?user-id=" + userId.String() + "&days=1000", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockForecastService)
+			mockService.On("Forecast", userId, test.wantAccountId, test.wantDays).Return([]domain.ForecastDTO{}, nil)
+
+			req, err := http.NewRequest("GET", "/forecast"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(ForecastControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusOK {
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
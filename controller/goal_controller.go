@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddGoalControl(gs service.GoalServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var goal domain.GoalDTO
+		if !readJSON(w, r, &goal, "goal DTO") {
+			return
+		}
+
+		goalData := domain.GoalData{Goal: goal, Validator: validator}
+		saved, err := gs.AddGoal(r.Context(), &goalData)
+		if err != nil {
+			log.Println("Error adding the goal:", err)
+			http.Error(w, "Error adding the goal.", errorStatus(err))
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+// RetrieveGoalsControl lists the users goals with their progress.
+func RetrieveGoalsControl(gs service.GoalServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		goals, err := gs.RetrieveGoals(userId)
+		if err != nil {
+			log.Println("Error retrieving goals:", err)
+			http.Error(w, "Error retrieving goals.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, goals)
+	}
+}
+
+func RetrieveGoalProgressControl(gs service.GoalServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		goalId, ok := queryUUID(w, r, "goal-id")
+		if !ok {
+			return
+		}
+
+		progress, err := gs.RetrieveGoalProgress(goalId)
+		if err != nil {
+			log.Println("Error retrieving the goal progress:", err)
+			http.Error(w, "Error retrieving the goal progress.", http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, progress)
+	}
+}
+
+func DeleteGoalControl(gs service.GoalServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		goalId, ok := queryUUID(w, r, "goal-id")
+		if !ok {
+			return
+		}
+
+		err := gs.DeleteGoal(r.Context(), goalId)
+		if err != nil {
+			log.Println("Error deleting the goal:", err)
+			http.Error(w, "Error deleting the goal.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
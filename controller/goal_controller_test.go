@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGoalService struct {
+	mock.Mock
+}
+
+func (m *MockGoalService) AddGoal(ctx context.Context, goalData *domain.GoalData) (*domain.GoalDTO, error) {
+	args := m.Called(goalData)
+	return args.Get(0).(*domain.GoalDTO), args.Error(1)
+}
+
+func (m *MockGoalService) RetrieveGoals(userId uuid.UUID) ([]domain.GoalProgressDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.GoalProgressDTO), args.Error(1)
+}
+
+func (m *MockGoalService) RetrieveGoalProgress(goalId uuid.UUID) (*domain.GoalProgressDTO, error) {
+	args := m.Called(goalId)
+	return args.Get(0).(*domain.GoalProgressDTO), args.Error(1)
+}
+
+func (m *MockGoalService) DeleteGoal(ctx context.Context, goalId uuid.UUID) error {
+	args := m.Called(goalId)
+	return args.Error(0)
+}
+
+func TestAddGoalControl(t *testing.T) {
+	goal := domain.GoalDTO{UserId: uuid.New(), Name: "Emergency fund", TargetAmount: 10000, TargetDate: 1814400000000, AccountId: 1}
+	goalJSON, err := json.Marshal(goal)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	validationErr := validator.New().Struct(domain.GoalDTO{})
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "Added", serviceErr: nil, expectedStatus: http.StatusOK},
+		{name: "Invalid", serviceErr: validationErr, expectedStatus: http.StatusBadRequest},
+		{name: "Service error", serviceErr: errors.New("database is down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockGoalService)
+			mockService.On("AddGoal", mock.AnythingOfType("*domain.GoalData")).Return(&goal, test.serviceErr)
+
+			req, err := http.NewRequest("POST", "/goal/add", bytes.NewBuffer(goalJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddGoalControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
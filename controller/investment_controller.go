@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddSecurityControl(is service.InvestmentServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var security domain.SecurityDTO
+		if !readJSON(w, r, &security, "security DTO") {
+			return
+		}
+
+		securityData := domain.SecurityData{Security: security, Validator: validator}
+		saved, err := is.AddSecurity(r.Context(), &securityData)
+		if err != nil {
+			log.Println("Error adding the security:", err)
+			if errors.Is(err, service.ErrSecurityExists) {
+				http.Error(w, "A security with the symbol already exists.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error adding the security.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveSecuritiesControl(is service.InvestmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		securities, err := is.RetrieveSecurities(userId)
+		if err != nil {
+			log.Println("Error retrieving securities:", err)
+			http.Error(w, "Error retrieving securities.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, securities)
+	}
+}
+
+func AddInvestmentTransactionControl(is service.InvestmentServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var investment domain.InvestmentTransactionDTO
+		if !readJSON(w, r, &investment, "investment transaction DTO") {
+			return
+		}
+
+		investmentData := domain.InvestmentTransactionData{Investment: investment, Validator: validator}
+		saved, err := is.AddInvestmentTransaction(r.Context(), &investmentData)
+		if err != nil {
+			log.Println("Error adding the investment transaction:", err)
+			if errors.Is(err, service.ErrSecurityNotFound) {
+				http.Error(w, "Security not found.", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "Error adding the investment transaction.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveInvestmentTransactionsControl(is service.InvestmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		investments, err := is.RetrieveInvestmentTransactions(userId)
+		if err != nil {
+			log.Println("Error retrieving investment transactions:", err)
+			http.Error(w, "Error retrieving investment transactions.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, investments)
+	}
+}
+
+// ImportSecurityPricesControl saves the closing prices of a CSV file sent as the request body.
+func ImportSecurityPricesControl(is service.InvestmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, service.MaxPriceFileSize)
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, "Price file is too large.", http.StatusRequestEntityTooLarge)
+				return
+			}
+			log.Println("Error reading request body:", err)
+			http.Error(w, "Error reading request body.", http.StatusBadRequest)
+			return
+		}
+
+		imported, err := is.ImportPrices(data)
+		if err != nil {
+			log.Println("Error importing prices:", err)
+			http.Error(w, "Error importing prices.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, imported)
+	}
+}
+
+// RetrievePortfolioControl returns the users holdings valued on the date, by default now.
+func RetrievePortfolioControl(is service.InvestmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		date, ok := queryInt64(w, r, "date", time.Now().UnixMilli())
+		if !ok {
+			return
+		}
+
+		portfolio, err := is.RetrievePortfolio(userId, date)
+		if err != nil {
+			log.Println("Error retrieving the portfolio:", err)
+			http.Error(w, "Error retrieving the portfolio.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, portfolio)
+	}
+}
+
+// RetrieveRealizedGainsControl returns the gains on the sales between from and to, by default
+// everything up to now.
+func RetrieveRealizedGainsControl(is service.InvestmentServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		from, ok := queryInt64(w, r, "from", 0)
+		if !ok {
+			return
+		}
+		to, ok := queryInt64(w, r, "to", time.Now().UnixMilli())
+		if !ok {
+			return
+		}
+
+		gains, err := is.RetrieveRealizedGains(userId, from, to)
+		if err != nil {
+			log.Println("Error retrieving realized gains:", err)
+			http.Error(w, "Error retrieving realized gains.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, gains)
+	}
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInvestmentService struct {
+	mock.Mock
+}
+
+func (m *MockInvestmentService) AddSecurity(ctx context.Context, securityData *domain.SecurityData) (*domain.SecurityDTO, error) {
+	args := m.Called(securityData)
+	return args.Get(0).(*domain.SecurityDTO), args.Error(1)
+}
+
+func (m *MockInvestmentService) RetrieveSecurities(userId uuid.UUID) ([]domain.SecurityDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.SecurityDTO), args.Error(1)
+}
+
+func (m *MockInvestmentService) AddInvestmentTransaction(ctx context.Context, investmentData *domain.InvestmentTransactionData) (*domain.InvestmentTransactionDTO, error) {
+	args := m.Called(investmentData)
+	return args.Get(0).(*domain.InvestmentTransactionDTO), args.Error(1)
+}
+
+func (m *MockInvestmentService) RetrieveInvestmentTransactions(userId uuid.UUID) ([]domain.InvestmentTransactionDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.InvestmentTransactionDTO), args.Error(1)
+}
+
+func (m *MockInvestmentService) ImportPrices(data []byte) (*domain.PriceImportDTO, error) {
+	args := m.Called(data)
+	return args.Get(0).(*domain.PriceImportDTO), args.Error(1)
+}
+
+func (m *MockInvestmentService) RetrievePortfolio(userId uuid.UUID, date int64) (*domain.PortfolioDTO, error) {
+	args := m.Called(userId, date)
+	return args.Get(0).(*domain.PortfolioDTO), args.Error(1)
+}
+
+func (m *MockInvestmentService) RetrieveRealizedGains(userId uuid.UUID, from int64, to int64) ([]domain.RealizedGainDTO, error) {
+	args := m.Called(userId, from, to)
+	return args.Get(0).([]domain.RealizedGainDTO), args.Error(1)
+}
+
+func (m *MockInvestmentService) PortfolioValues(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error) {
+	args := m.Called(userId, from, to)
+	return args.Get(0).([]float64), args.Error(1)
+}
+
+func TestAddInvestmentTransactionControl(t *testing.T) {
+	investment := domain.InvestmentTransactionDTO{UserId: uuid.New(), SecurityId: uuid.New(), Kind: domain.INVESTMENT_SELL, Date: 10, Quantity: 5, Price: 20}
+	investmentJSON, err := json.Marshal(investment)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Added", err: nil, expectedStatus: http.StatusOK},
+		{name: "Unknown security", err: service.ErrSecurityNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Shares not held", err: fmt.Errorf("%w: 5 shares", service.ErrInsufficientShares), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockInvestmentService)
+			mockService.On("AddInvestmentTransaction", mock.Anything).Return(&investment, test.err)
+
+			req, err := http.NewRequest("POST", "/investment/add", bytes.NewBuffer(investmentJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddInvestmentTransactionControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddSecurityControl_Exists(t *testing.T) {
+	securityJSON, err := json.Marshal(domain.SecurityDTO{UserId: uuid.New(), Symbol: "VWCE"})
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+	mockService := new(MockInvestmentService)
+	mockService.On("AddSecurity", mock.Anything).Return(&domain.SecurityDTO{}, service.ErrSecurityExists)
+
+	req, err := http.NewRequest("POST", "/security/add", bytes.NewBuffer(securityJSON))
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(AddSecurityControl(mockService, validator.New()))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("Wrong status code: got %v, want %v", status, http.StatusConflict)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestRetrievePortfolioControl(t *testing.T) {
+	userId := uuid.New()
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Retrieved", err: nil, expectedStatus: http.StatusOK},
+		{name: "Failed", err: errors.New("database down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockInvestmentService)
+			mockService.On("RetrievePortfolio", userId, int64(1000)).Return(&domain.PortfolioDTO{UserId: userId}, test.err)
+
+			req, err := http.NewRequest("GET", "/portfolio?user-id="+userId.String()+"&date=1000", nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrievePortfolioControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
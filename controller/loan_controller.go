@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddLoanControl(ls service.LoanServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var loan domain.LoanDTO
+		if !readJSON(w, r, &loan, "loan DTO") {
+			return
+		}
+
+		loanData := domain.LoanData{Loan: loan, Validator: validator}
+		saved, err := ls.AddLoan(r.Context(), &loanData)
+		if err != nil {
+			log.Println("Error adding the loan:", err)
+			http.Error(w, "Error adding the loan.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveLoansControl(ls service.LoanServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		loans, err := ls.RetrieveLoans(userId)
+		if err != nil {
+			log.Println("Error retrieving loans:", err)
+			http.Error(w, "Error retrieving loans.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, loans)
+	}
+}
+
+func DeleteLoanControl(ls service.LoanServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		loanId, ok := queryUUID(w, r, "loan-id")
+		if !ok {
+			return
+		}
+
+		err := ls.DeleteLoan(r.Context(), loanId)
+		if err != nil {
+			log.Println("Error deleting the loan:", err)
+			http.Error(w, "Error deleting the loan.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RetrieveLoanScheduleControl returns the amortization schedule of the loan from its start.
+func RetrieveLoanScheduleControl(ls service.LoanServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		loanId, ok := queryUUID(w, r, "loan-id")
+		if !ok {
+			return
+		}
+
+		schedule, err := ls.RetrieveSchedule(loanId)
+		if err != nil {
+			log.Println("Error retrieving the loan schedule:", err)
+			http.Error(w, "Error retrieving the loan schedule.", http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, schedule)
+	}
+}
+
+// RetrieveLoanStatusControl returns the payments made on the loan and the balance left.
+func RetrieveLoanStatusControl(ls service.LoanServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		loanId, ok := queryUUID(w, r, "loan-id")
+		if !ok {
+			return
+		}
+
+		status, err := ls.RetrieveLoanStatus(loanId)
+		if err != nil {
+			log.Println("Error retrieving the loan status:", err)
+			http.Error(w, "Error retrieving the loan status.", http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, status)
+	}
+}
+
+// SimulateLoanPayoffControl compares paying the loan off as planned with paying extra-monthly
+// more every month and a lump-sum on the lump-sum-date.
+func SimulateLoanPayoffControl(ls service.LoanServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		loanId, ok := queryUUID(w, r, "loan-id")
+		if !ok {
+			return
+		}
+		extraMonthly, ok := queryFloat(w, r, "extra-monthly", 0)
+		if !ok {
+			return
+		}
+		lumpSum, ok := queryFloat(w, r, "lump-sum", 0)
+		if !ok {
+			return
+		}
+		lumpSumDate, ok := queryInt64(w, r, "lump-sum-date", 0)
+		if !ok {
+			return
+		}
+
+		simulation, err := ls.SimulatePayoff(loanId, extraMonthly, lumpSum, lumpSumDate)
+		if err != nil {
+			log.Println("Error simulating the loan payoff:", err)
+			http.Error(w, "Error simulating the loan payoff.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, simulation)
+	}
+}
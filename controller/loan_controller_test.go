@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoanService struct {
+	mock.Mock
+}
+
+func (m *MockLoanService) AddLoan(ctx context.Context, loanData *domain.LoanData) (*domain.LoanDTO, error) {
+	args := m.Called(loanData)
+	return args.Get(0).(*domain.LoanDTO), args.Error(1)
+}
+
+func (m *MockLoanService) RetrieveLoans(userId uuid.UUID) ([]domain.LoanDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.LoanDTO), args.Error(1)
+}
+
+func (m *MockLoanService) DeleteLoan(ctx context.Context, loanId uuid.UUID) error {
+	args := m.Called(loanId)
+	return args.Error(0)
+}
+
+func (m *MockLoanService) RetrieveSchedule(loanId uuid.UUID) (*domain.AmortizationScheduleDTO, error) {
+	args := m.Called(loanId)
+	return args.Get(0).(*domain.AmortizationScheduleDTO), args.Error(1)
+}
+
+func (m *MockLoanService) RetrieveLoanStatus(loanId uuid.UUID) (*domain.LoanStatusDTO, error) {
+	args := m.Called(loanId)
+	return args.Get(0).(*domain.LoanStatusDTO), args.Error(1)
+}
+
+func (m *MockLoanService) SimulatePayoff(loanId uuid.UUID, extraMonthly float64, lumpSum float64, lumpSumDate int64) (*domain.PayoffSimulationDTO, error) {
+	args := m.Called(loanId, extraMonthly, lumpSum, lumpSumDate)
+	return args.Get(0).(*domain.PayoffSimulationDTO), args.Error(1)
+}
+
+func (m *MockLoanService) LoanBalances(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error) {
+	args := m.Called(userId, from, to)
+	return args.Get(0).([]float64), args.Error(1)
+}
+
+func TestAddLoanControl(t *testing.T) {
+	loan := domain.LoanDTO{UserId: uuid.New(), Name: "Mortgage", Principal: 200000, AnnualRate: 6, TermMonths: 360, StartDate: 10, Match: "mortgage"}
+	loanJSON, err := json.Marshal(loan)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Added", err: nil, expectedStatus: http.StatusOK},
+		{name: "Invalid", err: errors.New("validation failed"), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockLoanService)
+			mockService.On("AddLoan", mock.Anything).Return(&loan, test.err)
+
+			req, err := http.NewRequest("POST", "/loan/add", bytes.NewBuffer(loanJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddLoanControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetrieveLoanStatusControl_NotFound(t *testing.T) {
+	loanId := uuid.New()
+	mockService := new(MockLoanService)
+	mockService.On("RetrieveLoanStatus", loanId).Return(&domain.LoanStatusDTO{}, errors.New("no rows"))
+
+	req, err := http.NewRequest("GET", "/loan/status?loan-id="+loanId.String(), nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(RetrieveLoanStatusControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("Wrong status code: got %v, want %v", status, http.StatusNotFound)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestSimulateLoanPayoffControl(t *testing.T) {
+	loanId := uuid.New()
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "Simulated", query: "&extra-monthly=250.5&lump-sum=1000&lump-sum-date=5000", expectedStatus: http.StatusOK},
+		{name: "Bad extra", query: "&extra-monthly=lots", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockLoanService)
+			if test.expectedStatus == http.StatusOK {
+				mockService.On("SimulatePayoff", loanId, 250.5, 1000.0, int64(5000)).Return(&domain.PayoffSimulationDTO{LoanId: loanId}, nil)
+			}
+
+			req, err := http.NewRequest("GET", "/loan/simulate?loan-id="+loanId.String()+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(SimulateLoanPayoffControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
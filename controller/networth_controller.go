@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddValuationControl(ns service.NetWorthServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var valuation domain.ValuationDTO
+		if !readJSON(w, r, &valuation, "valuation DTO") {
+			return
+		}
+
+		valuationData := domain.ValuationData{Valuation: valuation, Validator: validator}
+		saved, err := ns.AddValuation(r.Context(), &valuationData)
+		if err != nil {
+			log.Println("Error adding the valuation:", err)
+			http.Error(w, "Error adding the valuation.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveValuationsControl(ns service.NetWorthServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		valuations, err := ns.RetrieveValuations(userId)
+		if err != nil {
+			log.Println("Error retrieving valuations:", err)
+			http.Error(w, "Error retrieving valuations.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, valuations)
+	}
+}
+
+func DeleteValuationControl(ns service.NetWorthServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		valuationId, ok := queryUUID(w, r, "valuation-id")
+		if !ok {
+			return
+		}
+
+		err := ns.DeleteValuation(r.Context(), valuationId)
+		if err != nil {
+			log.Println("Error deleting the valuation:", err)
+			http.Error(w, "Error deleting the valuation.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// TakeNetWorthSnapshotControl records todays net worth of the user right away instead of
+// waiting for the daily snapshot.
+func TakeNetWorthSnapshotControl(ns service.NetWorthServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		point, err := ns.TakeSnapshot(r.Context(), userId)
+		if err != nil {
+			log.Println("Error taking the net worth snapshot:", err)
+			http.Error(w, "Error taking the net worth snapshot.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, point)
+	}
+}
+
+// BackfillNetWorthControl rebuilds the users daily net worth history from their transactions and valuations.
+func BackfillNetWorthControl(ns service.NetWorthServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		backfill, err := ns.BackfillNetWorth(r.Context(), userId)
+		if err != nil {
+			log.Println("Error backfilling the net worth history:", err)
+			http.Error(w, "Error backfilling the net worth history.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, backfill)
+	}
+}
+
+// RetrieveNetWorthControl returns the net worth history between from and to, by default
+// everything up to now, one point per day or per month (interval=day|month, month by default).
+func RetrieveNetWorthControl(ns service.NetWorthServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		from, ok := queryInt64(w, r, "from", 0)
+		if !ok {
+			return
+		}
+		to, ok := queryInt64(w, r, "to", time.Now().UnixMilli())
+		if !ok {
+			return
+		}
+		var monthly bool
+		switch interval := r.URL.Query().Get("interval"); interval {
+		case "", "month":
+			monthly = true
+		case "day":
+		default:
+			http.Error(w, fmt.Sprintf("Error converting the given interval: %s", interval), http.StatusBadRequest)
+			return
+		}
+		if to < from {
+			http.Error(w, "The net worth range ends before it starts.", http.StatusBadRequest)
+			return
+		}
+
+		netWorth, err := ns.RetrieveNetWorth(userId, from, to, monthly)
+		if err != nil {
+			log.Println("Error retrieving the net worth history:", err)
+			http.Error(w, "Error retrieving the net worth history.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, netWorth)
+	}
+}
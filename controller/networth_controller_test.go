@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNetWorthService struct {
+	mock.Mock
+}
+
+func (m *MockNetWorthService) AddValuation(ctx context.Context, valuationData *domain.ValuationData) (*domain.ValuationDTO, error) {
+	args := m.Called(valuationData)
+	return args.Get(0).(*domain.ValuationDTO), args.Error(1)
+}
+
+func (m *MockNetWorthService) RetrieveValuations(userId uuid.UUID) ([]domain.ValuationDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.ValuationDTO), args.Error(1)
+}
+
+func (m *MockNetWorthService) DeleteValuation(ctx context.Context, valuationId uuid.UUID) error {
+	args := m.Called(valuationId)
+	return args.Error(0)
+}
+
+func (m *MockNetWorthService) TakeSnapshot(ctx context.Context, userId uuid.UUID) (*domain.NetWorthPointDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).(*domain.NetWorthPointDTO), args.Error(1)
+}
+
+func (m *MockNetWorthService) BackfillNetWorth(ctx context.Context, userId uuid.UUID) (*domain.NetWorthBackfillDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).(*domain.NetWorthBackfillDTO), args.Error(1)
+}
+
+func (m *MockNetWorthService) RetrieveNetWorth(userId uuid.UUID, from int64, to int64, monthly bool) (*domain.NetWorthDTO, error) {
+	args := m.Called(userId, from, to, monthly)
+	return args.Get(0).(*domain.NetWorthDTO), args.Error(1)
+}
+
+func TestRetrieveNetWorthControl(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantMonthly    bool
+		expectedStatus int
+	}{
+		{name: "Monthly by default", query: "?user-id=" + userId.String() + "&from=10&to=20", wantMonthly: true, expectedStatus: http.StatusOK},
+		{name: "Daily", query: "?user-id=" + userId.String() + "&from=10&to=20&interval=day", wantMonthly: false, expectedStatus: http.StatusOK},
+		{name: "Unknown interval", query: "?user-id=" + userId.String() + "&interval=hour", expectedStatus: http.StatusBadRequest},
+		{name: "Backwards range", query: "?user-id=" + userId.String() + "&from=20&to=10", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockNetWorthService)
+			mockService.On("RetrieveNetWorth", userId, int64(10), int64(20), test.wantMonthly).Return(&domain.NetWorthDTO{}, nil)
+
+			req, err := http.NewRequest("GET", "/networth"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveNetWorthControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusOK {
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestBackfillNetWorthControl(t *testing.T) {
+	userId := uuid.New()
+	mockService := new(MockNetWorthService)
+	mockService.On("BackfillNetWorth", userId).Return(&domain.NetWorthBackfillDTO{UserId: userId, Snapshots: 30}, nil)
+
+	req, err := http.NewRequest("POST", "/networth/backfill?user-id="+userId.String(), nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(BackfillNetWorthControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+	mockService.AssertExpectations(t)
+}
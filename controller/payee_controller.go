@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddPayeeControl(ps service.PayeeServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var payee domain.PayeeDTO
+		if !readJSON(w, r, &payee, "payee DTO") {
+			return
+		}
+
+		payeeData := domain.PayeeData{Payee: payee, Validator: validator}
+		saved, err := ps.AddPayee(r.Context(), &payeeData)
+		if err != nil {
+			log.Println("Error adding the payee:", err)
+			http.Error(w, "Error adding the payee.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrievePayeesControl(ps service.PayeeServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		payees, err := ps.RetrievePayees(userId)
+		if err != nil {
+			log.Println("Error retrieving payees:", err)
+			http.Error(w, "Error retrieving payees.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, payees)
+	}
+}
+
+func MergePayeesControl(ps service.PayeeServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var merge domain.PayeeMergeDTO
+		if !readJSON(w, r, &merge, "payee merge DTO") {
+			return
+		}
+
+		mergeData := domain.PayeeMergeData{Merge: merge, Validator: validator}
+		err := ps.MergePayees(r.Context(), &mergeData)
+		if err != nil {
+			log.Println("Error merging payees:", err)
+			if errors.Is(err, service.ErrTransactionReconciled) {
+				http.Error(w, "A transaction of the payee is reconciled.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error merging payees.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RetrievePayeeSpendingControl returns the expense totals per payee, from and to default to all time.
+func RetrievePayeeSpendingControl(ps service.PayeeServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		from, ok := queryInt64(w, r, "from", 0)
+		if !ok {
+			return
+		}
+		to, ok := queryInt64(w, r, "to", math.MaxInt64)
+		if !ok {
+			return
+		}
+
+		spending, err := ps.RetrievePayeeSpending(userId, from, to)
+		if err != nil {
+			log.Println("Error retrieving payee spending:", err)
+			http.Error(w, "Error retrieving payee spending.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, spending)
+	}
+}
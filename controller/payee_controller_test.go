@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPayeeService struct {
+	mock.Mock
+}
+
+func (m *MockPayeeService) AddPayee(ctx context.Context, payeeData *domain.PayeeData) (*domain.PayeeDTO, error) {
+	args := m.Called(payeeData)
+	return args.Get(0).(*domain.PayeeDTO), args.Error(1)
+}
+
+func (m *MockPayeeService) RetrievePayees(userId uuid.UUID) ([]domain.PayeeDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.PayeeDTO), args.Error(1)
+}
+
+func (m *MockPayeeService) ResolvePayee(tm *domain.TransactionModel) (*domain.PayeeModel, func(saved bool), error) {
+	args := m.Called(tm)
+	return args.Get(0).(*domain.PayeeModel), func(saved bool) {}, args.Error(1)
+}
+
+func (m *MockPayeeService) MergePayees(ctx context.Context, mergeData *domain.PayeeMergeData) error {
+	args := m.Called(mergeData)
+	return args.Error(0)
+}
+
+func (m *MockPayeeService) RetrievePayeeSpending(userId uuid.UUID, from int64, to int64) ([]domain.PayeeSpendingDTO, error) {
+	args := m.Called(userId, from, to)
+	return args.Get(0).([]domain.PayeeSpendingDTO), args.Error(1)
+}
+
+func TestRetrievePayeeSpendingControl(t *testing.T) {
+	userId := uuid.New()
+	spending := []domain.PayeeSpendingDTO{{PayeeId: uuid.New(), Name: "Amazon", Count: 2, Total: 30}}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantFrom       int64
+		wantTo         int64
+		expectedStatus int
+	}{
+		{name: "All time", query: "?user-id=" + userId.String(), wantFrom: 0, wantTo: math.MaxInt64, expectedStatus: http.StatusOK},
+		{name: "Date range", query: "?user-id=" + userId.String() + "&from=10&to=20", wantFrom: 10, wantTo: 20, expectedStatus: http.StatusOK},
+		{name: "Bad date", query: "?user-id=" + userId.String() + "&from=yesterday", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockPayeeService)
+			mockService.On("RetrievePayeeSpending", userId, test.wantFrom, test.wantTo).Return(spending, nil)
+
+			req, err := http.NewRequest("GET", "/payee/spending"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrievePayeeSpendingControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestMergePayeesControl(t *testing.T) {
+	merge := domain.PayeeMergeDTO{SourcePayeeId: uuid.New(), TargetPayeeId: uuid.New()}
+	mergeJSON, err := json.Marshal(merge)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Merged", expectedStatus: http.StatusOK},
+		{name: "Reconciled transaction", err: service.ErrTransactionReconciled, expectedStatus: http.StatusConflict},
+		{name: "Failed", err: errors.New("payees belong to different users"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockPayeeService)
+			mockService.On("MergePayees", mock.MatchedBy(func(data *domain.PayeeMergeData) bool {
+				return data.Merge == merge
+			})).Return(test.err)
+
+			req, err := http.NewRequest("PUT", "/payee/merge", bytes.NewBuffer(mergeJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(MergePayeesControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
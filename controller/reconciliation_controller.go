@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+// StartReconciliationControl opens a reconciliation of the account against a statement.
+func StartReconciliationControl(rs service.ReconciliationServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var reconciliation domain.ReconciliationDTO
+		if !readJSON(w, r, &reconciliation, "reconciliation DTO") {
+			return
+		}
+
+		reconciliationData := domain.ReconciliationData{Reconciliation: reconciliation, Validator: validator}
+		saved, err := rs.StartReconciliation(r.Context(), &reconciliationData)
+		if err != nil {
+			log.Println("Error starting the reconciliation:", err)
+			if errors.Is(err, service.ErrReconciliationOpen) {
+				http.Error(w, "The account already has a reconciliation in progress.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error starting the reconciliation.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveReconciliationsControl(rs service.ReconciliationServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		accountId, ok := queryInt64(w, r, "account-id", 0)
+		if !ok {
+			return
+		}
+
+		reconciliations, err := rs.RetrieveReconciliations(userId, accountId)
+		if err != nil {
+			log.Println("Error retrieving reconciliations:", err)
+			http.Error(w, "Error retrieving reconciliations.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, reconciliations)
+	}
+}
+
+// RetrieveReconciliationControl returns the cleared and uncleared transactions of the
+// reconciliation with the difference left.
+func RetrieveReconciliationControl(rs service.ReconciliationServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		reconciliationId, ok := queryUUID(w, r, "reconciliation-id")
+		if !ok {
+			return
+		}
+
+		detail, err := rs.RetrieveReconciliation(reconciliationId)
+		if err != nil {
+			log.Println("Error retrieving the reconciliation:", err)
+			http.Error(w, "Error retrieving the reconciliation.", http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, detail)
+	}
+}
+
+func UpdateClearedControl(rs service.ReconciliationServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var update domain.ClearUpdateDTO
+		if !readJSON(w, r, &update, "clear update DTO") {
+			return
+		}
+
+		updateData := domain.ClearUpdateData{Update: update, Validator: validator}
+		detail, err := rs.UpdateCleared(r.Context(), &updateData)
+		if err != nil {
+			log.Println("Error clearing transactions:", err)
+			if errors.Is(err, service.ErrReconciliationLocked) {
+				http.Error(w, "The reconciliation is locked.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error clearing transactions.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, detail)
+	}
+}
+
+// FinishReconciliationControl locks the reconciliation when the cleared transactions add up to
+// the ending balance.
+func FinishReconciliationControl(rs service.ReconciliationServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		reconciliationId, ok := queryUUID(w, r, "reconciliation-id")
+		if !ok {
+			return
+		}
+
+		detail, err := rs.FinishReconciliation(r.Context(), reconciliationId)
+		if err != nil {
+			log.Println("Error finishing the reconciliation:", err)
+			if errors.Is(err, service.ErrReconciliationLocked) {
+				http.Error(w, "The reconciliation is locked.", http.StatusConflict)
+				return
+			}
+			if errors.Is(err, service.ErrReconciliationUnbalanced) {
+				http.Error(w, "The cleared transactions do not add up to the ending balance.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error finishing the reconciliation.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, detail)
+	}
+}
+
+func UnlockReconciliationControl(rs service.ReconciliationServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		reconciliationId, ok := queryUUID(w, r, "reconciliation-id")
+		if !ok {
+			return
+		}
+
+		unlocked, err := rs.UnlockReconciliation(r.Context(), reconciliationId)
+		if err != nil {
+			log.Println("Error unlocking the reconciliation:", err)
+			if errors.Is(err, service.ErrReconciliationNotLatest) {
+				http.Error(w, "Only the latest reconciliation of the account can be unlocked.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error unlocking the reconciliation.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, unlocked)
+	}
+}
+
+func DeleteReconciliationControl(rs service.ReconciliationServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		reconciliationId, ok := queryUUID(w, r, "reconciliation-id")
+		if !ok {
+			return
+		}
+
+		err := rs.DeleteReconciliation(r.Context(), reconciliationId)
+		if err != nil {
+			log.Println("Error deleting the reconciliation:", err)
+			if errors.Is(err, service.ErrReconciliationLocked) {
+				http.Error(w, "The reconciliation is locked.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error deleting the reconciliation.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
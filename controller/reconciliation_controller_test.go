@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReconciliationService struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationService) StartReconciliation(ctx context.Context, reconciliationData *domain.ReconciliationData) (*domain.ReconciliationDTO, error) {
+	args := m.Called(reconciliationData)
+	return args.Get(0).(*domain.ReconciliationDTO), args.Error(1)
+}
+
+func (m *MockReconciliationService) RetrieveReconciliations(userId uuid.UUID, accountId int64) ([]domain.ReconciliationDTO, error) {
+	args := m.Called(userId, accountId)
+	return args.Get(0).([]domain.ReconciliationDTO), args.Error(1)
+}
+
+func (m *MockReconciliationService) RetrieveReconciliation(reconciliationId uuid.UUID) (*domain.ReconciliationDetailDTO, error) {
+	args := m.Called(reconciliationId)
+	return args.Get(0).(*domain.ReconciliationDetailDTO), args.Error(1)
+}
+
+func (m *MockReconciliationService) UpdateCleared(ctx context.Context, updateData *domain.ClearUpdateData) (*domain.ReconciliationDetailDTO, error) {
+	args := m.Called(updateData)
+	return args.Get(0).(*domain.ReconciliationDetailDTO), args.Error(1)
+}
+
+func (m *MockReconciliationService) FinishReconciliation(ctx context.Context, reconciliationId uuid.UUID) (*domain.ReconciliationDetailDTO, error) {
+	args := m.Called(reconciliationId)
+	return args.Get(0).(*domain.ReconciliationDetailDTO), args.Error(1)
+}
+
+func (m *MockReconciliationService) UnlockReconciliation(ctx context.Context, reconciliationId uuid.UUID) (*domain.ReconciliationDTO, error) {
+	args := m.Called(reconciliationId)
+	return args.Get(0).(*domain.ReconciliationDTO), args.Error(1)
+}
+
+func (m *MockReconciliationService) DeleteReconciliation(ctx context.Context, reconciliationId uuid.UUID) error {
+	args := m.Called(reconciliationId)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationService) CheckUnlocked(transactionId uuid.UUID) error {
+	args := m.Called(transactionId)
+	return args.Error(0)
+}
+
+func TestStartReconciliationControl(t *testing.T) {
+	reconciliation := domain.ReconciliationDTO{UserId: uuid.New(), AccountId: 2, StatementDate: 1000, EndingBalance: 250}
+	reconciliationJSON, err := json.Marshal(reconciliation)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Started", err: nil, expectedStatus: http.StatusOK},
+		{name: "Already open", err: service.ErrReconciliationOpen, expectedStatus: http.StatusConflict},
+		{name: "Invalid", err: errors.New("validation failed"), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockReconciliationService)
+			mockService.On("StartReconciliation", mock.Anything).Return(&reconciliation, test.err)
+
+			req, err := http.NewRequest("POST", "/reconciliation/start", bytes.NewBuffer(reconciliationJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(StartReconciliationControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinishReconciliationControl(t *testing.T) {
+	reconciliationId := uuid.New()
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Finished", err: nil, expectedStatus: http.StatusOK},
+		{name: "Unbalanced", err: fmt.Errorf("%w: 12.50 left", service.ErrReconciliationUnbalanced), expectedStatus: http.StatusConflict},
+		{name: "Locked", err: service.ErrReconciliationLocked, expectedStatus: http.StatusConflict},
+		{name: "Failed", err: errors.New("database down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockReconciliationService)
+			mockService.On("FinishReconciliation", reconciliationId).Return(&domain.ReconciliationDetailDTO{}, test.err)
+
+			req, err := http.NewRequest("POST", "/reconciliation/finish?reconciliation-id="+reconciliationId.String(), nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(FinishReconciliationControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
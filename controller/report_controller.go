@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/export"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+// RetrieveReportControl reports on the transactions between from and to, by default the twelve
+// months up to now, that pass the tag filter, see queryTagFilter. Totals are grouped by period
+// (week, month or year, month by default), and pending transactions are left out with
+// exclude-pending=true. The format parameter or the Accept header exports the report as CSV,
+// XLSX with a sheet per section, or PDF.
+func RetrieveReportControl(rs service.ReportServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		now := time.Now().UTC()
+		to, ok := queryInt64(w, r, "to", now.UnixMilli())
+		if !ok {
+			return
+		}
+		from, ok := queryInt64(w, r, "from", time.Date(now.Year()-1, now.Month()+1, 1, 0, 0, 0, 0, time.UTC).UnixMilli())
+		if !ok {
+			return
+		}
+		period, err := domain.ParseReportPeriod(r.URL.Query().Get("period"))
+		if err != nil {
+			log.Println("Error converting the given period:", err)
+			http.Error(w, fmt.Sprintf("Error converting the given period: %s", r.URL.Query().Get("period")), http.StatusBadRequest)
+			return
+		}
+		excludePending, ok := queryBool(w, r, "exclude-pending")
+		if !ok {
+			return
+		}
+		tagFilter, ok := queryTagFilter(w, r)
+		if !ok {
+			return
+		}
+		format, ok := queryExportFormat(w, r)
+		if !ok {
+			return
+		}
+		if to < from {
+			http.Error(w, "The report range ends before it starts.", http.StatusBadRequest)
+			return
+		}
+
+		query := domain.ReportQueryDTO{UserId: userId, From: from, To: to, Period: period, ExcludePending: excludePending, TagFilter: tagFilter}
+		report, err := rs.RetrieveReport(&query)
+		if err != nil {
+			log.Println("Error retrieving the report:", err)
+			http.Error(w, "Error retrieving the report.", http.StatusInternalServerError)
+			return
+		}
+
+		if format != export.JSON {
+			writeExport(w, reportDocument(report), format, "report-"+exportDate(from)+"-"+exportDate(to))
+			return
+		}
+		writeJSON(w, report)
+	}
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) RetrieveReport(query *domain.ReportQueryDTO) (*domain.ReportDTO, error) {
+	args := m.Called(query)
+	return args.Get(0).(*domain.ReportDTO), args.Error(1)
+}
+
+func TestRetrieveReportControl(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantQuery      domain.ReportQueryDTO
+		expectedStatus int
+	}{
+		{
+			name:           "Weekly without pending",
+			query:          "?user-id=" + userId.String() + "&from=10&to=20&period=week&exclude-pending=true",
+			wantQuery:      domain.ReportQueryDTO{UserId: userId, From: 10, To: 20, Period: domain.REPORT_WEEK, ExcludePending: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Monthly by default",
+			query:          "?user-id=" + userId.String() + "&from=10&to=20",
+			wantQuery:      domain.ReportQueryDTO{UserId: userId, From: 10, To: 20, Period: domain.REPORT_MONTH},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Tagged",
+			query:          "?user-id=" + userId.String() + "&from=10&to=20&tags=Travel,work&tag-mode=all",
+			wantQuery:      domain.ReportQueryDTO{UserId: userId, From: 10, To: 20, Period: domain.REPORT_MONTH, TagFilter: domain.TagFilter{Tags: []string{"travel", "work"}, Mode: domain.TAG_ALL}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "Unknown tag mode", query: "?user-id=" + userId.String() + "&tags=travel&tag-mode=some", expectedStatus: http.StatusBadRequest},
+		{name: "Unknown period", query: "?user-id=" + userId.String() + "&period=decade", expectedStatus: http.StatusBadRequest},
+		{name: "Backwards range", query: "?user-id=" + userId.String() + "&from=20&to=10", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockReportService)
+			mockService.On("RetrieveReport", &test.wantQuery).Return(&domain.ReportDTO{}, nil)
+
+			req, err := http.NewRequest("GET", "/report"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveReportControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusOK {
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestRetrieveReportControl_Export(t *testing.T) {
+	userId := uuid.New()
+	report := domain.ReportDTO{
+		UserId:     userId,
+		From:       1735689600000,
+		To:         1767225599999,
+		Totals:     domain.ReportTotalsDTO{Income: 3000, Expense: 1200, Net: 1800, Count: 4},
+		Categories: []domain.ReportCategoryDTO{{CategoryId: 2, Expense: 1200, ExpensePercent: 100, Count: 3}},
+		Statuses:   []domain.ReportStatusDTO{{Status: domain.CLEARED, Totals: domain.ReportTotalsDTO{Income: 3000, Count: 1}}},
+	}
+	mockService := new(MockReportService)
+	mockService.On("RetrieveReport", mock.Anything).Return(&report, nil)
+
+	req, err := http.NewRequest("GET", "/report?user-id="+userId.String()+"&from=1735689600000&to=1767225599999&format=csv", nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(RetrieveReportControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+	if disposition := rr.Header().Get("Content-Disposition"); disposition != "attachment; filename=report-2025-01-01-2025-12-31.csv" {
+		t.Errorf("Wrong content disposition: %v", disposition)
+	}
+	for _, want := range []string{
+		"Summary\nRange,Income,Expense,Net,Count\nThis range,3000.00,1200.00,1800.00,4\n",
+		"\nCategories\nCategory,Income,Expense,Income %,Expense %,Count\n2,0.00,1200.00,0.00,100.00,3\n",
+		"\nStatuses\nStatus,Income,Expense,Net,Count\nCLEARED,3000.00,0.00,0.00,1\n",
+	} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Errorf("Expected %q in the CSV:\n%s", want, rr.Body.String())
+		}
+	}
+}
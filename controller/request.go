@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/export"
+	"github.com/hld3/personal-finance-go/utility"
+)
+
+// readJSON reads the request body into dst, writing a bad request response on failure.
+// name describes dst in the log and error messages.
+func readJSON(w http.ResponseWriter, r *http.Request, dst any, name string) bool {
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error reading request body:", err)
+		http.Error(w, "Error reading request body.", http.StatusBadRequest)
+		return false
+	}
+
+	err = json.Unmarshal(bodyBytes, dst)
+	if err != nil {
+		log.Printf("Error converting to %s: %v\n", name, err)
+		http.Error(w, fmt.Sprintf("Error converting to %s.", name), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// queryUUID parses the named query parameter, writing a bad request response on failure.
+func queryUUID(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, bool) {
+	idStr := r.URL.Query().Get(param)
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Printf("Error converting the given %s: %v\n", param, err)
+		http.Error(w, fmt.Sprintf("Error converting the given %s: %s", param, idStr), http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// queryInt parses the named query parameter, or returns def when it is missing.
+// It writes a bad request response on failure.
+func queryInt(w http.ResponseWriter, r *http.Request, param string, def int) (int, bool) {
+	valueStr := r.URL.Query().Get(param)
+	if valueStr == "" {
+		return def, true
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Printf("Error converting the given %s: %v\n", param, err)
+		http.Error(w, fmt.Sprintf("Error converting the given %s: %s", param, valueStr), http.StatusBadRequest)
+		return 0, false
+	}
+	return value, true
+}
+
+// queryInt64 parses the named query parameter, or returns def when it is missing.
+// It writes a bad request response on failure.
+func queryInt64(w http.ResponseWriter, r *http.Request, param string, def int64) (int64, bool) {
+	valueStr := r.URL.Query().Get(param)
+	if valueStr == "" {
+		return def, true
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		log.Printf("Error converting the given %s: %v\n", param, err)
+		http.Error(w, fmt.Sprintf("Error converting the given %s: %s", param, valueStr), http.StatusBadRequest)
+		return 0, false
+	}
+	return value, true
+}
+
+// queryFloat parses the named query parameter, or returns def when it is missing.
+// It writes a bad request response on failure.
+func queryFloat(w http.ResponseWriter, r *http.Request, param string, def float64) (float64, bool) {
+	valueStr := r.URL.Query().Get(param)
+	if valueStr == "" {
+		return def, true
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		log.Printf("Error converting the given %s: %v\n", param, err)
+		http.Error(w, fmt.Sprintf("Error converting the given %s: %s", param, valueStr), http.StatusBadRequest)
+		return 0, false
+	}
+	return value, true
+}
+
+// queryBool parses the named query parameter, or returns false when it is missing.
+// It writes a bad request response on failure.
+func queryBool(w http.ResponseWriter, r *http.Request, param string) (bool, bool) {
+	valueStr := r.URL.Query().Get(param)
+	if valueStr == "" {
+		return false, true
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Printf("Error converting the given %s: %v\n", param, err)
+		http.Error(w, fmt.Sprintf("Error converting the given %s: %s", param, valueStr), http.StatusBadRequest)
+		return false, false
+	}
+	return value, true
+}
+
+// queryMonth reads the named month query parameter, written as 2006-01, or returns the current
+// month when it is missing. It writes a bad request response on failure.
+func queryMonth(w http.ResponseWriter, r *http.Request, param string) (string, bool) {
+	month := r.URL.Query().Get(param)
+	if month == "" {
+		return time.Now().UTC().Format("2006-01"), true
+	}
+	_, err := time.Parse("2006-01", month)
+	if err != nil {
+		log.Printf("Error converting the given %s: %v\n", param, err)
+		http.Error(w, fmt.Sprintf("Error converting the given %s: %s", param, month), http.StatusBadRequest)
+		return "", false
+	}
+	return month, true
+}
+
+// queryTagFilter reads the comma separated tags and tag-mode (any, all or none) query parameters.
+// It writes a bad request response on failure.
+func queryTagFilter(w http.ResponseWriter, r *http.Request) (domain.TagFilter, bool) {
+	var filter domain.TagFilter
+	if tags := r.URL.Query().Get("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = domain.NormalizeTag(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	mode, err := domain.ParseTagFilterMode(r.URL.Query().Get("tag-mode"))
+	if err != nil {
+		log.Println("Error converting the given tag-mode:", err)
+		http.Error(w, fmt.Sprintf("Error converting the given tag-mode: %s", r.URL.Query().Get("tag-mode")), http.StatusBadRequest)
+		return filter, false
+	}
+	filter.Mode = mode
+	return filter, true
+}
+
+// writeJSON marshals data and writes it as the response body.
+func writeJSON(w http.ResponseWriter, data any) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		log.Println("Error marshaling response data:", err)
+		http.Error(w, "Error marshaling response data.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(dataJSON)
+}
+
+// queryExportFormat negotiates the response format from the format query parameter or else
+// the Accept header, JSON unless a CSV, XLSX or PDF export is asked for.
+func queryExportFormat(w http.ResponseWriter, r *http.Request) (export.Format, bool) {
+	w.Header().Add("Vary", "Accept")
+	format, err := export.Negotiate(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+	if err != nil {
+		log.Println("Error negotiating the export format:", err)
+		http.Error(w, fmt.Sprintf("Unknown export format: %s", r.URL.Query().Get("format")), http.StatusBadRequest)
+		return format, false
+	}
+	return format, true
+}
+
+// writeExport renders doc in the format as a download, name is the file name without extension.
+func writeExport(w http.ResponseWriter, doc *export.Document, format export.Format, name string) {
+	data, err := export.Render(doc, format)
+	if err != nil {
+		log.Println("Error rendering the export:", err)
+		http.Error(w, "Error rendering the export.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + "." + format.String()}))
+	w.Write(data)
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+// bearerUserId is the user of the bearer token of the request.
+func bearerUserId(r *http.Request) (uuid.UUID, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return uuid.Nil, errors.New("no bearer token")
+	}
+	userId, err := utility.ParseJWTToken(token)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(userId)
+}
+
+// errorStatus is bad request for an error of the validator and internal server error otherwise.
+func errorStatus(err error) int {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// requireUser returns the user of the bearer token of the request, writing an unauthorized
+// response without a valid one.
+func requireUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userId, err := bearerUserId(r)
+	if err != nil {
+		log.Println("Error authenticating the request:", err)
+		http.Error(w, "A valid bearer token is required.", http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+	return userId, true
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddRuleControl(rs service.RuleServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var rule domain.RuleDTO
+		if !readJSON(w, r, &rule, "rule DTO") {
+			return
+		}
+
+		ruleData := domain.RuleData{Rule: rule, Validator: validator}
+		saved, err := rs.AddRule(r.Context(), &ruleData)
+		if err != nil {
+			log.Println("Error adding the rule:", err)
+			http.Error(w, "Error adding the rule.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveRulesControl(rs service.RuleServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		rules, err := rs.RetrieveRules(userId)
+		if err != nil {
+			log.Println("Error retrieving rules:", err)
+			http.Error(w, "Error retrieving rules.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, rules)
+	}
+}
+
+func DeleteRuleControl(rs service.RuleServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		ruleId, ok := queryUUID(w, r, "rule-id")
+		if !ok {
+			return
+		}
+
+		err := rs.DeleteRule(r.Context(), ruleId)
+		if err != nil {
+			log.Println("Error deleting the rule:", err)
+			http.Error(w, "Error deleting the rule.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// ReapplyRulesControl returns the changes made, or with dryRun set the changes that would be made.
+func ReapplyRulesControl(rs service.RuleServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var reapply domain.RuleReapplyDTO
+		if !readJSON(w, r, &reapply, "rule reapply DTO") {
+			return
+		}
+
+		reapplyData := domain.RuleReapplyData{Reapply: reapply, Validator: validator}
+		changes, err := rs.ReapplyRules(r.Context(), &reapplyData)
+		if err != nil {
+			log.Println("Error reapplying rules:", err)
+			http.Error(w, "Error reapplying rules.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, changes)
+	}
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRuleService struct {
+	mock.Mock
+}
+
+func (m *MockRuleService) AddRule(ctx context.Context, ruleData *domain.RuleData) (*domain.RuleDTO, error) {
+	args := m.Called(ruleData)
+	return args.Get(0).(*domain.RuleDTO), args.Error(1)
+}
+
+func (m *MockRuleService) RetrieveRules(userId uuid.UUID) ([]domain.RuleDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.RuleDTO), args.Error(1)
+}
+
+func (m *MockRuleService) DeleteRule(ctx context.Context, ruleId uuid.UUID) error {
+	args := m.Called(ruleId)
+	return args.Error(0)
+}
+
+func (m *MockRuleService) ApplyRules(tm *domain.TransactionModel) (*domain.RuleChangeDTO, error) {
+	args := m.Called(tm)
+	return args.Get(0).(*domain.RuleChangeDTO), args.Error(1)
+}
+
+func (m *MockRuleService) ReapplyRules(ctx context.Context, reapplyData *domain.RuleReapplyData) ([]domain.RuleChangeDTO, error) {
+	args := m.Called(reapplyData)
+	return args.Get(0).([]domain.RuleChangeDTO), args.Error(1)
+}
+
+func TestAddRuleControl(t *testing.T) {
+	rule := domain.RuleDTO{
+		UserId:     uuid.New(),
+		Name:       "Coffee",
+		Conditions: []domain.RuleCondition{{Type: domain.DESCRIPTION_CONTAINS, Text: "coffee"}},
+		Actions:    []domain.RuleAction{{Type: domain.SET_CATEGORY, CategoryId: 4}},
+	}
+	ruleJSON, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockReturnErr  error
+		expectedStatus int
+	}{
+		{name: "Valid rule", body: string(ruleJSON), expectedStatus: http.StatusOK},
+		{name: "Malformed JSON", body: `{"name": }`, expectedStatus: http.StatusBadRequest},
+		{name: "Invalid rule", body: string(ruleJSON), mockReturnErr: errors.New("invalid pattern"), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockRuleService)
+			mockService.On("AddRule", mock.AnythingOfType("*domain.RuleData")).Return(&rule, test.mockReturnErr)
+
+			req, err := http.NewRequest("POST", "/rule/add", bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddRuleControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestDeleteRuleControl(t *testing.T) {
+	ruleId := uuid.New()
+	mockService := new(MockRuleService)
+	mockService.On("DeleteRule", ruleId).Return(nil)
+
+	req, err := http.NewRequest("DELETE", "/rule/delete?rule-id="+ruleId.String(), nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(DeleteRuleControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestReapplyRulesControl(t *testing.T) {
+	reapply := domain.RuleReapplyDTO{UserId: uuid.New(), To: 100, DryRun: true}
+	reapplyJSON, err := json.Marshal(reapply)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+	changes := []domain.RuleChangeDTO{{TransactionId: uuid.New(), Changes: []domain.RuleFieldChange{{Field: "categoryId", Before: "0", After: "4"}}}}
+
+	mockService := new(MockRuleService)
+	mockService.On("ReapplyRules", mock.MatchedBy(func(data *domain.RuleReapplyData) bool {
+		return data.Reapply.DryRun
+	})).Return(changes, nil)
+
+	req, err := http.NewRequest("POST", "/rule/reapply", bytes.NewBuffer(reapplyJSON))
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(ReapplyRulesControl(mockService, validator.New()))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+
+	var got []domain.RuleChangeDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal("Error converting the response:", err)
+	}
+	if len(got) != 1 || got[0].Changes[0].After != "4" {
+		t.Errorf("Wrong response body, got %v", got)
+	}
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+// DiscoverSubscriptionsControl lists the subscriptions detected in the users transactions,
+// dismissed ones only with include-dismissed=true.
+func DiscoverSubscriptionsControl(ss service.SubscriptionServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		includeDismissed, ok := queryBool(w, r, "include-dismissed")
+		if !ok {
+			return
+		}
+
+		subscriptions, err := ss.DiscoverSubscriptions(userId, includeDismissed)
+		if err != nil {
+			log.Println("Error discovering subscriptions:", err)
+			http.Error(w, "Error discovering subscriptions.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, subscriptions)
+	}
+}
+
+func ConfirmSubscriptionControl(ss service.SubscriptionServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decisionData, ok := readSubscriptionDecision(w, r, validator)
+		if !ok {
+			return
+		}
+
+		subscription, err := ss.ConfirmSubscription(r.Context(), decisionData)
+		if err != nil {
+			subscriptionDecisionError(w, err)
+			return
+		}
+
+		writeJSON(w, subscription)
+	}
+}
+
+func DismissSubscriptionControl(ss service.SubscriptionServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decisionData, ok := readSubscriptionDecision(w, r, validator)
+		if !ok {
+			return
+		}
+
+		err := ss.DismissSubscription(r.Context(), decisionData)
+		if err != nil {
+			subscriptionDecisionError(w, err)
+			return
+		}
+	}
+}
+
+func readSubscriptionDecision(w http.ResponseWriter, r *http.Request, validator *validator.Validate) (*domain.SubscriptionDecisionData, bool) {
+	if !allowMethod(w, r, http.MethodPut) {
+		return nil, false
+	}
+
+	var decision domain.SubscriptionDecisionDTO
+	if !readJSON(w, r, &decision, "subscription decision DTO") {
+		return nil, false
+	}
+	return &domain.SubscriptionDecisionData{Decision: decision, Validator: validator}, true
+}
+
+func subscriptionDecisionError(w http.ResponseWriter, err error) {
+	log.Println("Error deciding on the subscription:", err)
+	switch {
+	case errors.Is(err, service.ErrSubscriptionNotFound):
+		http.Error(w, "Subscription not found.", http.StatusNotFound)
+	case errors.Is(err, service.ErrSubscriptionDecided):
+		http.Error(w, "Subscription is already confirmed.", http.StatusConflict)
+	default:
+		http.Error(w, "Error deciding on the subscription.", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSubscriptionService struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionService) DiscoverSubscriptions(userId uuid.UUID, includeDismissed bool) ([]domain.SubscriptionDTO, error) {
+	args := m.Called(userId, includeDismissed)
+	return args.Get(0).([]domain.SubscriptionDTO), args.Error(1)
+}
+
+func (m *MockSubscriptionService) ConfirmSubscription(ctx context.Context, decisionData *domain.SubscriptionDecisionData) (*domain.SubscriptionDTO, error) {
+	args := m.Called(decisionData)
+	return args.Get(0).(*domain.SubscriptionDTO), args.Error(1)
+}
+
+func (m *MockSubscriptionService) DismissSubscription(ctx context.Context, decisionData *domain.SubscriptionDecisionData) error {
+	args := m.Called(decisionData)
+	return args.Error(0)
+}
+
+func TestConfirmSubscriptionControl(t *testing.T) {
+	decision := domain.SubscriptionDecisionDTO{UserId: uuid.New(), Key: "coffee club"}
+	decisionJSON, err := json.Marshal(decision)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Confirmed", err: nil, expectedStatus: http.StatusOK},
+		{name: "Not detected", err: service.ErrSubscriptionNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Already confirmed", err: service.ErrSubscriptionDecided, expectedStatus: http.StatusConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockSubscriptionService)
+			mockService.On("ConfirmSubscription", mock.Anything).Return(&domain.SubscriptionDTO{Key: decision.Key}, test.err)
+
+			req, err := http.NewRequest("PUT", "/subscription/confirm", bytes.NewBuffer(decisionJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(ConfirmSubscriptionControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiscoverSubscriptionsControl(t *testing.T) {
+	userId := uuid.New()
+	mockService := new(MockSubscriptionService)
+	mockService.On("DiscoverSubscriptions", userId, true).Return([]domain.SubscriptionDTO{}, nil)
+
+	req, err := http.NewRequest("GET", "/subscription/list?user-id="+userId.String()+"&include-dismissed=true", nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(DiscoverSubscriptionsControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func RetrieveTagsControl(ts service.TagServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		tags, err := ts.RetrieveTags(userId)
+		if err != nil {
+			log.Println("Error retrieving tags:", err)
+			http.Error(w, "Error retrieving tags.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, tags)
+	}
+}
+
+func TagTransactionsControl(ts service.TagServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return updateTagsControl(ts.TagTransactions, validator)
+}
+
+func UntagTransactionsControl(ts service.TagServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return updateTagsControl(ts.UntagTransactions, validator)
+}
+
+func updateTagsControl(update func(context.Context, *domain.TagUpdateData) error, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPut) {
+			return
+		}
+
+		var tagUpdate domain.TagUpdateDTO
+		if !readJSON(w, r, &tagUpdate, "tag update DTO") {
+			return
+		}
+
+		updateData := domain.TagUpdateData{Update: tagUpdate, Validator: validator}
+		err := update(r.Context(), &updateData)
+		if err != nil {
+			log.Println("Error updating transaction tags:", err)
+			http.Error(w, "Error updating transaction tags.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
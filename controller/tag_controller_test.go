@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) RetrieveTags(userId uuid.UUID) ([]domain.TagDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.TagDTO), args.Error(1)
+}
+
+func (m *MockTagService) TagTransactions(ctx context.Context, updateData *domain.TagUpdateData) error {
+	args := m.Called(updateData)
+	return args.Error(0)
+}
+
+func (m *MockTagService) UntagTransactions(ctx context.Context, updateData *domain.TagUpdateData) error {
+	args := m.Called(updateData)
+	return args.Error(0)
+}
+
+func (m *MockTagService) RetrieveTransactionTags(userId uuid.UUID) (map[uuid.UUID][]string, error) {
+	args := m.Called(userId)
+	return args.Get(0).(map[uuid.UUID][]string), args.Error(1)
+}
+
+func TestTagTransactionsControl(t *testing.T) {
+	update := domain.TagUpdateDTO{UserId: uuid.New(), TransactionIds: []uuid.UUID{uuid.New(), uuid.New()}, Tags: []string{"reimbursable"}}
+	updateJSON, err := json.Marshal(update)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	for _, method := range []string{"TagTransactions", "UntagTransactions"} {
+		t.Run(method, func(t *testing.T) {
+			mockService := new(MockTagService)
+			mockService.On(method, mock.MatchedBy(func(data *domain.TagUpdateData) bool {
+				return reflect.DeepEqual(data.Update, update)
+			})).Return(nil)
+
+			req, err := http.NewRequest("PUT", "/tag", bytes.NewBuffer(updateJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := TagTransactionsControl(mockService, validator.New())
+			if method == "UntagTransactions" {
+				handler = UntagTransactionsControl(mockService, validator.New())
+			}
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("Wrong status code: got %v, want %v", status, http.StatusOK)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetrieveTransactionsControl(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantFilter     domain.TagFilter
+		expectedStatus int
+	}{
+		{
+			name:           "No tag filter",
+			query:          "?user-id=" + userId.String(),
+			wantFilter:     domain.TagFilter{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "All tags",
+			query:          "?user-id=" + userId.String() + "&tags=Vacation-2026,%20reimbursable&tag-mode=all",
+			wantFilter:     domain.TagFilter{Tags: []string{"vacation-2026", "reimbursable"}, Mode: domain.TAG_ALL},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unknown tag mode",
+			query:          "?user-id=" + userId.String() + "&tags=a&tag-mode=some",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockTransactionService)
+			wantQuery := domain.TransactionQueryDTO{UserId: userId, From: 0, To: math.MaxInt64, TagFilter: test.wantFilter}
+			mockService.On("RetrieveTransactions", &wantQuery).Return([]domain.TransactionDTO{}, nil)
+
+			req, err := http.NewRequest("GET", "/transaction/list"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveTransactionsControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
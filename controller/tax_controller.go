@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddTaxLineControl(ts service.TaxServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var taxLine domain.TaxLineDTO
+		if !readJSON(w, r, &taxLine, "tax line DTO") {
+			return
+		}
+
+		taxLineData := domain.TaxLineData{TaxLine: taxLine, Validator: validator}
+		saved, err := ts.AddTaxLine(r.Context(), &taxLineData)
+		if err != nil {
+			log.Println("Error adding the tax line:", err)
+			if errors.Is(err, service.ErrCategoryMapped) {
+				http.Error(w, "A category is already on another tax line.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error adding the tax line.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveTaxLinesControl(ts service.TaxServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		taxLines, err := ts.RetrieveTaxLines(userId)
+		if err != nil {
+			log.Println("Error retrieving tax lines:", err)
+			http.Error(w, "Error retrieving tax lines.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, taxLines)
+	}
+}
+
+func DeleteTaxLineControl(ts service.TaxServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		taxLineId, ok := queryUUID(w, r, "tax-line-id")
+		if !ok {
+			return
+		}
+
+		err := ts.DeleteTaxLine(r.Context(), taxLineId)
+		if err != nil {
+			log.Println("Error deleting the tax line:", err)
+			http.Error(w, "Error deleting the tax line.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RetrieveTaxReportControl reports the tax year, by default last year, as JSON or, with
+// format=csv, as a CSV download.
+func RetrieveTaxReportControl(ts service.TaxServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		year, ok := queryInt(w, r, "year", time.Now().UTC().Year()-1)
+		if !ok {
+			return
+		}
+
+		switch format := r.URL.Query().Get("format"); format {
+		case "", "json":
+			report, err := ts.RetrieveTaxReport(userId, year)
+			if err != nil {
+				log.Println("Error retrieving the tax report:", err)
+				http.Error(w, "Error retrieving the tax report.", http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, report)
+		case "csv":
+			data, err := ts.ExportTaxReportCSV(userId, year)
+			if err != nil {
+				log.Println("Error exporting the tax report:", err)
+				http.Error(w, "Error exporting the tax report.", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fmt.Sprintf("tax-report-%d.csv", year)}))
+			w.Write(data)
+		default:
+			http.Error(w, fmt.Sprintf("Unknown report format: %s", format), http.StatusBadRequest)
+		}
+	}
+}
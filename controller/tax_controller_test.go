@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTaxService struct {
+	mock.Mock
+}
+
+func (m *MockTaxService) AddTaxLine(ctx context.Context, taxLineData *domain.TaxLineData) (*domain.TaxLineDTO, error) {
+	args := m.Called(taxLineData)
+	return args.Get(0).(*domain.TaxLineDTO), args.Error(1)
+}
+
+func (m *MockTaxService) RetrieveTaxLines(userId uuid.UUID) ([]domain.TaxLineDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.TaxLineDTO), args.Error(1)
+}
+
+func (m *MockTaxService) DeleteTaxLine(ctx context.Context, taxLineId uuid.UUID) error {
+	args := m.Called(taxLineId)
+	return args.Error(0)
+}
+
+func (m *MockTaxService) RetrieveTaxReport(userId uuid.UUID, year int) (*domain.TaxReportDTO, error) {
+	args := m.Called(userId, year)
+	return args.Get(0).(*domain.TaxReportDTO), args.Error(1)
+}
+
+func (m *MockTaxService) ExportTaxReportCSV(userId uuid.UUID, year int) ([]byte, error) {
+	args := m.Called(userId, year)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func TestAddTaxLineControl(t *testing.T) {
+	taxLine := domain.TaxLineDTO{UserId: uuid.New(), Name: "Medical", CategoryIds: []int64{5}}
+	taxLineJSON, err := json.Marshal(taxLine)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Added", err: nil, expectedStatus: http.StatusOK},
+		{name: "Category on another line", err: service.ErrCategoryMapped, expectedStatus: http.StatusConflict},
+		{name: "Invalid", err: errors.New("validation failed"), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockTaxService)
+			mockService.On("AddTaxLine", mock.Anything).Return(&taxLine, test.err)
+
+			req, err := http.NewRequest("POST", "/tax/line/add", bytes.NewBuffer(taxLineJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddTaxLineControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetrieveTaxReportControl(t *testing.T) {
+	userId := uuid.New()
+	tests := []struct {
+		name           string
+		format         string
+		expectedStatus int
+		expectedType   string
+	}{
+		{name: "JSON", format: "json", expectedStatus: http.StatusOK, expectedType: "application/json"},
+		{name: "CSV", format: "csv", expectedStatus: http.StatusOK, expectedType: "text/csv; charset=utf-8"},
+		{name: "Unknown format", format: "xml", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockTaxService)
+			switch test.format {
+			case "json":
+				mockService.On("RetrieveTaxReport", userId, 2025).Return(&domain.TaxReportDTO{UserId: userId, Year: 2025}, nil)
+			case "csv":
+				mockService.On("ExportTaxReportCSV", userId, 2025).Return([]byte("tax_line\n"), nil)
+			}
+
+			req, err := http.NewRequest("GET", "/tax/report?user-id="+userId.String()+"&year=2025&format="+test.format, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveTaxReportControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedType != "" && rr.Header().Get("Content-Type") != test.expectedType {
+				t.Errorf("Wrong content type: got %v, want %v", rr.Header().Get("Content-Type"), test.expectedType)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
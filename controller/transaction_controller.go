@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/export"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+func AddTransactionControl(ts service.TransactionServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var transaction domain.TransactionDTO
+		if !readJSON(w, r, &transaction, "transaction DTO") {
+			return
+		}
+
+		transactionData := domain.TransactionData{Transaction: transaction, Validator: validator}
+		result, err := ts.AddTransaction(r.Context(), &transactionData)
+		if err != nil {
+			log.Println("Error adding the transaction:", err)
+			http.Error(w, "Error adding the transaction.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, result)
+	}
+}
+
+func ImportTransactionsControl(ts service.TransactionServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var transactions []domain.TransactionDTO
+		if !readJSON(w, r, &transactions, "transaction DTOs") {
+			return
+		}
+
+		importData := domain.TransactionImportData{Transactions: transactions, Validator: validator}
+		results, err := ts.ImportTransactions(r.Context(), &importData)
+		if err != nil {
+			log.Println("Error importing transactions:", err)
+			http.Error(w, "Error importing transactions.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, results)
+	}
+}
+
+// RetrieveTransactionsControl lists the users transactions, optionally within from and to
+// and filtered by tags, see queryTagFilter. The list is exported as CSV, XLSX or PDF when
+// asked for by the format parameter or the Accept header.
+func RetrieveTransactionsControl(ts service.TransactionServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+		from, ok := queryInt64(w, r, "from", 0)
+		if !ok {
+			return
+		}
+		to, ok := queryInt64(w, r, "to", math.MaxInt64)
+		if !ok {
+			return
+		}
+		tagFilter, ok := queryTagFilter(w, r)
+		if !ok {
+			return
+		}
+		format, ok := queryExportFormat(w, r)
+		if !ok {
+			return
+		}
+
+		query := domain.TransactionQueryDTO{UserId: userId, From: from, To: to, TagFilter: tagFilter}
+		transactions, err := ts.RetrieveTransactions(&query)
+		if err != nil {
+			log.Println("Error retrieving transactions:", err)
+			http.Error(w, "Error retrieving transactions.", http.StatusInternalServerError)
+			return
+		}
+
+		if format != export.JSON {
+			writeExport(w, transactionsDocument(transactions, from, to), format, "transactions")
+			return
+		}
+		writeJSON(w, transactions)
+	}
+}
+
+func DeleteTransactionControl(ts service.TransactionServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		transactionId, ok := queryUUID(w, r, "transaction-id")
+		if !ok {
+			return
+		}
+
+		err := ts.DeleteTransaction(r.Context(), transactionId)
+		if err != nil {
+			log.Println("Error deleting the transaction:", err)
+			if errors.Is(err, service.ErrTransactionReconciled) {
+				http.Error(w, "The transaction is reconciled.", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error deleting the transaction.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
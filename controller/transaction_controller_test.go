@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionService struct {
+	mock.Mock
+}
+
+func (m *MockTransactionService) AddTransaction(ctx context.Context, transactionData *domain.TransactionData) (*domain.TransactionResultDTO, error) {
+	args := m.Called(transactionData)
+	return args.Get(0).(*domain.TransactionResultDTO), args.Error(1)
+}
+
+func (m *MockTransactionService) ImportTransactions(ctx context.Context, importData *domain.TransactionImportData) ([]domain.TransactionResultDTO, error) {
+	args := m.Called(importData)
+	return args.Get(0).([]domain.TransactionResultDTO), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransaction(transactionId uuid.UUID) (*domain.TransactionModel, error) {
+	args := m.Called(transactionId)
+	return args.Get(0).(*domain.TransactionModel), args.Error(1)
+}
+
+func (m *MockTransactionService) RetrieveTransactions(query *domain.TransactionQueryDTO) ([]domain.TransactionDTO, error) {
+	args := m.Called(query)
+	return args.Get(0).([]domain.TransactionDTO), args.Error(1)
+}
+
+func (m *MockTransactionService) DeleteTransaction(ctx context.Context, transactionId uuid.UUID) error {
+	args := m.Called(transactionId)
+	return args.Error(0)
+}
+
+func TestAddTransactionControl(t *testing.T) {
+	transaction := domain.TransactionDTOBuilder().Build()
+	validJSON, err := json.Marshal(transaction)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+	result := domain.TransactionResultDTO{Transaction: transaction, Duplicates: []domain.DuplicateDTO{}}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		mockReturnErr  error
+		expectedStatus int
+	}{
+		{
+			name:           "Valid transaction",
+			method:         "POST",
+			body:           string(validJSON),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Wrong method",
+			method:         "GET",
+			body:           string(validJSON),
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "Malformed JSON",
+			method:         "POST",
+			body:           `{"amount" 12,}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "TransactionService error",
+			method:         "POST",
+			body:           string(validJSON),
+			mockReturnErr:  errors.New("Some transaction service error."),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockTransactionService)
+			mockService.On("AddTransaction", mock.AnythingOfType("*domain.TransactionData")).Return(&result, test.mockReturnErr)
+
+			req, err := http.NewRequest(test.method, "/transaction/add", bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddTransactionControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code for %v: got %v, expected %v", test.name, status, test.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestImportTransactionsControl(t *testing.T) {
+	transactions := []domain.TransactionDTO{domain.TransactionDTOBuilder().Build(), domain.TransactionDTOBuilder().Build()}
+	transactionsJSON, err := json.Marshal(transactions)
+	if err != nil {
+		t.Fatal("Error marshaling DTOs:", err)
+	}
+	results := []domain.TransactionResultDTO{{Transaction: transactions[0]}, {Transaction: transactions[1]}}
+
+	mockService := new(MockTransactionService)
+	mockService.On("ImportTransactions", mock.AnythingOfType("*domain.TransactionImportData")).Return(results, nil)
+
+	req, err := http.NewRequest("POST", "/transaction/import", bytes.NewBuffer(transactionsJSON))
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(ImportTransactionsControl(mockService, validator.New()))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Wrong status code: got %v, want %v", status, http.StatusOK)
+	}
+
+	var got []domain.TransactionResultDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal("Error converting the response:", err)
+	}
+	if len(got) != len(results) {
+		t.Fatalf("Wrong number of results, got %d, want %d", len(got), len(results))
+	}
+}
+
+func TestRetrieveTransactionsControl_Export(t *testing.T) {
+	userId := uuid.New()
+	transactions := []domain.TransactionDTO{
+		{UserId: userId, TransactionId: uuid.New(), Amount: 12.5, Date: 1740787200000, Description: "Coffee", Type: domain.EXPENSE, Tags: []string{"food"}},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		accept              string
+		expectedStatus      int
+		expectedType        string
+		expectedDisposition string
+	}{
+		{name: "JSON by default", accept: "text/html", expectedStatus: http.StatusOK, expectedType: "application/json"},
+		{name: "CSV format", query: "&format=csv", expectedStatus: http.StatusOK, expectedType: "text/csv; charset=utf-8", expectedDisposition: "attachment; filename=transactions.csv"},
+		{name: "Format over Accept", query: "&format=json", accept: "application/pdf", expectedStatus: http.StatusOK, expectedType: "application/json"},
+		{name: "XLSX accepted", accept: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", expectedStatus: http.StatusOK, expectedType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", expectedDisposition: "attachment; filename=transactions.xlsx"},
+		{name: "PDF accepted", accept: "application/pdf", expectedStatus: http.StatusOK, expectedType: "application/pdf", expectedDisposition: "attachment; filename=transactions.pdf"},
+		{name: "Unknown format", query: "&format=docx", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockTransactionService)
+			mockService.On("RetrieveTransactions", mock.Anything).Return(transactions, nil)
+
+			req, err := http.NewRequest("GET", "/transaction/list?user-id="+userId.String()+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RetrieveTransactionsControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Fatalf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != test.expectedType {
+				t.Errorf("Wrong content type: got %v, want %v", contentType, test.expectedType)
+			}
+			if disposition := rr.Header().Get("Content-Disposition"); disposition != test.expectedDisposition {
+				t.Errorf("Wrong content disposition: got %v, want %v", disposition, test.expectedDisposition)
+			}
+		})
+	}
+}
+
+func TestRetrieveTransactionsControl_CSV(t *testing.T) {
+	userId := uuid.New()
+	transactions := []domain.TransactionDTO{
+		{UserId: userId, TransactionId: uuid.New(), CategoryId: 3, Amount: 12.5, Date: 1740787200000, Description: "Coffee, large", Type: domain.EXPENSE, PaymentMethod: domain.CASH, Status: domain.CLEARED, Tags: []string{"food", "work"}},
+	}
+	mockService := new(MockTransactionService)
+	mockService.On("RetrieveTransactions", mock.Anything).Return(transactions, nil)
+
+	req, err := http.NewRequest("GET", "/transaction/list?user-id="+userId.String()+"&format=csv", nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+	rr := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(RetrieveTransactionsControl(mockService))
+	handler.ServeHTTP(rr, req)
+
+	want := "Date,Description,Type,Payment method,Status,Category,Account,Tags,Currency,Original amount,Amount\n" +
+		"2025-03-01,\"Coffee, large\",EXPENSE,CASH,CLEARED,3,0,food; work,,0.00,12.50\n"
+	if rr.Body.String() != want {
+		t.Errorf("Unexpected CSV:\n%s", rr.Body.String())
+	}
+}
+
+func TestDeleteTransactionControl(t *testing.T) {
+	transactionId := uuid.New()
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Deleted", err: nil, expectedStatus: http.StatusOK},
+		{name: "Reconciled", err: service.ErrTransactionReconciled, expectedStatus: http.StatusConflict},
+		{name: "Failed", err: errors.New("database down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockTransactionService)
+			mockService.On("DeleteTransaction", transactionId).Return(test.err)
+
+			req, err := http.NewRequest("DELETE", "/transaction/delete?transaction-id="+transactionId.String(), nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(DeleteTransactionControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -31,7 +31,7 @@ func RegisterNewUserControl(us service.UserServiceInterface, validator *validato
 		}
 
 		userData := domain.UserData{User: &user, Validator: validator}
-		err = us.RegisterNewUser(&userData)
+		err = us.RegisterNewUser(r.Context(), &userData)
 		if err != nil {
 			log.Println("Error registering a new user:", err)
 			http.Error(w, "Error registering a new user", http.StatusInternalServerError)
@@ -132,7 +132,7 @@ func UpdateUserProfileDataControl(us service.UserServiceInterface) http.HandlerF
 			return
 		}
 
-		err = us.UpdateUserProfileData(&userDTO)
+		err = us.UpdateUserProfileData(r.Context(), &userDTO)
 		if err != nil {
 			log.Println("Error updating user profile data:", err)
 			http.Error(w, "Error updating user profile data.", http.StatusInternalServerError)
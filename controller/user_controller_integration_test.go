@@ -82,6 +82,7 @@ func TestRegisterNewUserControl_Integration(t *testing.T) {
 }
 
 func TestConfirmUserLoginControl_Integration(t *testing.T) {
+	t.Setenv("JWT_KEY", "test-key")
 	// Setup SQLite database.
 	db := setUpDatabase()
 	defer db.Close()
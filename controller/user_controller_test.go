@@ -2,6 +2,7 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -18,7 +19,7 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) RegisterNewUser(userData *domain.UserData) error {
+func (m *MockUserService) RegisterNewUser(ctx context.Context, userData *domain.UserData) error {
 	args := m.Called(userData)
 	return args.Error(0)
 }
@@ -33,7 +34,7 @@ func (m *MockUserService) RetrieveUserProfileData(userId uuid.UUID) (*domain.Use
 	return args.Get(0).(*domain.UserDTO), args.Error(1)
 }
 
-func (m *MockUserService) UpdateUserProfileData(user *domain.UserDTO) error {
+func (m *MockUserService) UpdateUserProfileData(ctx context.Context, user *domain.UserDTO) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
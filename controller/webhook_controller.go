@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/service"
+)
+
+// AddWebhookControl returns the subscription with its secret, the only time the secret is
+// returned.
+func AddWebhookControl(ws service.WebhookServiceInterface, validator *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var webhook domain.WebhookSubscriptionDTO
+		if !readJSON(w, r, &webhook, "webhook DTO") {
+			return
+		}
+
+		webhookData := domain.WebhookSubscriptionData{Webhook: webhook, Validator: validator}
+		saved, err := ws.AddWebhook(r.Context(), &webhookData)
+		if err != nil {
+			log.Println("Error adding the webhook:", err)
+			http.Error(w, "Error adding the webhook.", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, saved)
+	}
+}
+
+func RetrieveWebhooksControl(ws service.WebhookServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		userId, ok := queryUUID(w, r, "user-id")
+		if !ok {
+			return
+		}
+
+		webhooks, err := ws.RetrieveWebhooks(userId)
+		if err != nil {
+			log.Println("Error retrieving webhooks:", err)
+			http.Error(w, "Error retrieving webhooks.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, webhooks)
+	}
+}
+
+func DeleteWebhookControl(ws service.WebhookServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+
+		webhookId, ok := queryUUID(w, r, "webhook-id")
+		if !ok {
+			return
+		}
+
+		err := ws.DeleteWebhook(r.Context(), webhookId)
+		if err != nil {
+			log.Println("Error deleting the webhook:", err)
+			http.Error(w, "Error deleting the webhook.", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RetrieveWebhookDeliveriesControl is the delivery log of a webhook, newest first.
+func RetrieveWebhookDeliveriesControl(ws service.WebhookServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		webhookId, ok := queryUUID(w, r, "webhook-id")
+		if !ok {
+			return
+		}
+
+		deliveries, err := ws.RetrieveDeliveries(webhookId)
+		if err != nil {
+			log.Println("Error retrieving webhook deliveries:", err)
+			http.Error(w, "Error retrieving webhook deliveries.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, deliveries)
+	}
+}
+
+func RedeliverWebhookControl(ws service.WebhookServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		deliveryId, ok := queryUUID(w, r, "delivery-id")
+		if !ok {
+			return
+		}
+
+		delivery, err := ws.Redeliver(deliveryId)
+		if err != nil {
+			log.Println("Error redelivering the webhook delivery:", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Webhook delivery not found.", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "Error redelivering the webhook delivery.", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, delivery)
+	}
+}
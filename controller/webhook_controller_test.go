@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) AddWebhook(ctx context.Context, webhookData *domain.WebhookSubscriptionData) (*domain.WebhookSubscriptionDTO, error) {
+	args := m.Called(webhookData)
+	return args.Get(0).(*domain.WebhookSubscriptionDTO), args.Error(1)
+}
+
+func (m *MockWebhookService) RetrieveWebhooks(userId uuid.UUID) ([]domain.WebhookSubscriptionDTO, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]domain.WebhookSubscriptionDTO), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error {
+	args := m.Called(webhookId)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) RetrieveDeliveries(webhookId uuid.UUID) ([]domain.WebhookDeliveryDTO, error) {
+	args := m.Called(webhookId)
+	return args.Get(0).([]domain.WebhookDeliveryDTO), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(deliveryId uuid.UUID) (*domain.WebhookDeliveryDTO, error) {
+	args := m.Called(deliveryId)
+	return args.Get(0).(*domain.WebhookDeliveryDTO), args.Error(1)
+}
+
+func (m *MockWebhookService) HandleEvent(event *domain.EventDTO) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func TestAddWebhookControl(t *testing.T) {
+	webhook := domain.WebhookSubscriptionDTO{UserId: uuid.New(), URL: "https://example.com/hooks", Events: []domain.WebhookEvent{domain.TRANSACTION_CREATED}}
+	webhookJSON, err := json.Marshal(webhook)
+	if err != nil {
+		t.Fatal("Error marshaling DTO:", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Add", method: "POST", err: nil, expectedStatus: http.StatusOK},
+		{name: "Invalid", method: "POST", err: errors.New("Key: 'WebhookSubscriptionDTO.URL' Error:Field validation for 'URL' failed on the 'http_url' tag"), expectedStatus: http.StatusBadRequest},
+		{name: "Wrong method", method: "GET", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockWebhookService)
+			mockService.On("AddWebhook", mock.Anything).Return(&webhook, test.err)
+
+			req, err := http.NewRequest(test.method, "/webhook/add", bytes.NewBuffer(webhookJSON))
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(AddWebhookControl(mockService, validator.New()))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestRedeliverWebhookControl(t *testing.T) {
+	deliveryId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Redeliver", query: "?delivery-id=" + deliveryId.String(), err: nil, expectedStatus: http.StatusOK},
+		{name: "Unknown delivery", query: "?delivery-id=" + deliveryId.String(), err: sql.ErrNoRows, expectedStatus: http.StatusNotFound},
+		{name: "Store failure", query: "?delivery-id=" + deliveryId.String(), err: errors.New("database is locked"), expectedStatus: http.StatusInternalServerError},
+		{name: "Bad delivery id", query: "?delivery-id=42", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := new(MockWebhookService)
+			mockService.On("Redeliver", deliveryId).Return(&domain.WebhookDeliveryDTO{DeliveryId: uuid.New(), Status: domain.DELIVERY_PENDING}, test.err)
+
+			req, err := http.NewRequest("POST", "/webhook/redeliver"+test.query, nil)
+			if err != nil {
+				t.Fatal("Error building the request:", err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(RedeliverWebhookControl(mockService))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.expectedStatus {
+				t.Errorf("Wrong status code: got %v, want %v", status, test.expectedStatus)
+			}
+			if test.expectedStatus != http.StatusBadRequest {
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type AlertDatabaseInterface interface {
+	AddAlertRule(ctx context.Context, am *domain.AlertRuleModel) error
+	GetAlertRulesByUserId(userId uuid.UUID) ([]domain.AlertRuleModel, error)
+	DeleteAlertRule(ctx context.Context, alertRuleId uuid.UUID) error
+	AddAlert(ctx context.Context, am *domain.AlertModel, events ...domain.OutboxEventModel) (bool, error)
+	GetAlertsByUserId(userId uuid.UUID, unreadOnly bool) ([]domain.AlertModel, error)
+	MarkAlertRead(ctx context.Context, alertId uuid.UUID) error
+	GetAccountBalance(userId uuid.UUID, accountId int64) (float64, error)
+}
+
+// Channels are stored as JSON, they are only ever read together with the rule.
+func (db *SQLManager) AddAlertRule(ctx context.Context, am *domain.AlertRuleModel) error {
+	channels, err := json.Marshal(am.Channels)
+	if err != nil {
+		return err
+	}
+
+	stmt := `insert into alert_rule_model (alert_rule_id, user_id, type, category_id, account_id, percent, balance, channels, webhook_url, created_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.exec(ctx, rowsOf("alert_rule_model", "alert_rule_id = ?", am.AlertRuleId), stmt, am.AlertRuleId, am.UserId, am.Type, am.CategoryId, am.AccountId, am.Percent, am.Balance, string(channels), am.WebhookURL, am.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the alert rule to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetAlertRulesByUserId(userId uuid.UUID) ([]domain.AlertRuleModel, error) {
+	stmt := `select alert_rule_id, user_id, type, category_id, account_id, percent, balance, channels, webhook_url, created_at from alert_rule_model where user_id = ? order by created_at`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving alert rules:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.AlertRuleModel
+	for rows.Next() {
+		var am domain.AlertRuleModel
+		var channels string
+		err := rows.Scan(&am.AlertRuleId, &am.UserId, &am.Type, &am.CategoryId, &am.AccountId, &am.Percent, &am.Balance, &channels, &am.WebhookURL, &am.CreatedAt)
+		if err != nil {
+			log.Println("Error reading alert rule row:", err)
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(channels), &am.Channels); err != nil {
+			log.Println("Error reading alert rule channels:", err)
+			return nil, err
+		}
+		rules = append(rules, am)
+	}
+	return rules, rows.Err()
+}
+
+// DeleteAlertRule removes the rule, the alerts it already fired stay in the inbox.
+func (db *SQLManager) DeleteAlertRule(ctx context.Context, alertRuleId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("alert_rule_model", "alert_rule_id = ?", alertRuleId), `delete from alert_rule_model where alert_rule_id = ?`, alertRuleId)
+	if err != nil {
+		log.Println("Error deleting alert rule:", err)
+		return err
+	}
+	return nil
+}
+
+// AddAlert saves the alert with the events given, unless its rule already fired for the same
+// period, category and account, which is a unique key of alert_model. It reports whether the
+// alert was saved, the events are only saved with it.
+func (db *SQLManager) AddAlert(ctx context.Context, am *domain.AlertModel, events ...domain.OutboxEventModel) (bool, error) {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("alert_model", "alert_id = ?", am.AlertId))
+	if err != nil {
+		return false, err
+	}
+
+	stmt := db.insertIgnore() + ` into alert_model (alert_id, alert_rule_id, user_id, period, category_id, account_id, message, is_read, created_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.Exec(stmt, am.AlertId, am.AlertRuleId, am.UserId, am.Period, am.CategoryId, am.AccountId, am.Message, am.Read, am.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the alert to the database:", err)
+		return false, err
+	}
+	saved, err := result.RowsAffected()
+	if err != nil || saved == 0 {
+		return false, err
+	}
+	err = addEvents(tx, events)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// GetAlertsByUserId returns the users alerts, newest first.
+func (db *SQLManager) GetAlertsByUserId(userId uuid.UUID, unreadOnly bool) ([]domain.AlertModel, error) {
+	stmt := `select alert_id, alert_rule_id, user_id, period, category_id, account_id, message, is_read, created_at from alert_model where user_id = ?`
+	if unreadOnly {
+		stmt += ` and is_read = false`
+	}
+	stmt += ` order by created_at desc`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving alerts:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []domain.AlertModel
+	for rows.Next() {
+		var am domain.AlertModel
+		err := rows.Scan(&am.AlertId, &am.AlertRuleId, &am.UserId, &am.Period, &am.CategoryId, &am.AccountId, &am.Message, &am.Read, &am.CreatedAt)
+		if err != nil {
+			log.Println("Error reading alert row:", err)
+			return nil, err
+		}
+		alerts = append(alerts, am)
+	}
+	return alerts, rows.Err()
+}
+
+func (db *SQLManager) MarkAlertRead(ctx context.Context, alertId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("alert_model", "alert_id = ?", alertId), `update alert_model set is_read = true where alert_id = ?`, alertId)
+	if err != nil {
+		log.Println("Error marking alert read:", err)
+		return err
+	}
+	return nil
+}
+
+// GetAccountBalance is the income minus the expenses of the account, cancelled transactions are excluded.
+func (db *SQLManager) GetAccountBalance(userId uuid.UUID, accountId int64) (float64, error) {
+	stmt := `select coalesce(sum(case when type = ? then amount else -amount end), 0) from transaction_model
+		where user_id = ? and account_id = ? and status != ?`
+	var balance float64
+	err := db.DB.QueryRow(stmt, domain.INCOME, userId, accountId, domain.CANCELLED).Scan(&balance)
+	if err != nil {
+		log.Println("Error retrieving account balance:", err)
+		return 0, err
+	}
+	return balance, nil
+}
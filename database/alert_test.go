@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddAlert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	am := domain.AlertModel{AlertId: uuid.New(), AlertRuleId: uuid.New(), UserId: uuid.New(), Period: "2026-03", CategoryId: 3, Message: "Category 3 ...", CreatedAt: 1}
+	args := []driver.Value{am.AlertId, am.AlertRuleId, am.UserId, am.Period, am.CategoryId, am.AccountId, am.Message, am.Read, am.CreatedAt}
+
+	tests := []struct {
+		name      string
+		rows      int64
+		wantSaved bool
+	}{
+		{name: "New alert", rows: 1, wantSaved: true},
+		{name: "Already fired", rows: 0, wantSaved: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec("insert or ignore into alert_model").
+				WithArgs(args...).
+				WillReturnResult(sqlmock.NewResult(0, test.rows))
+			if test.wantSaved {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			saved, err := udb.AddAlert(context.Background(), &am)
+			if err != nil {
+				t.Fatal("Error adding the alert:", err)
+			}
+			if saved != test.wantSaved {
+				t.Errorf("Wrong saved, got %v, want %v", saved, test.wantSaved)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal("Expectations were not met:", err)
+			}
+		})
+	}
+}
+
+func TestGetAccountBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	mock.ExpectQuery("select coalesce\\(sum\\(case when type = \\? then amount else -amount end\\), 0\\) from transaction_model").
+		WithArgs(domain.INCOME, userId, 2, domain.CANCELLED).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(-42.5))
+
+	balance, err := udb.GetAccountBalance(userId, 2)
+	if err != nil || balance != -42.5 {
+		t.Fatalf("Wrong balance, got %v, err: %v", balance, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
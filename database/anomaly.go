@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type AnomalyDatabaseInterface interface {
+	AddAnomaly(ctx context.Context, am *domain.AnomalyModel) (bool, error)
+	GetAnomaliesByUserId(userId uuid.UUID) ([]domain.AnomalyModel, error)
+	GetActiveUserIds(since int64) ([]uuid.UUID, error)
+}
+
+const anomalyColumns = `anomaly_id, user_id, kind, transaction_id, category_id, payee_id, month, amount, expected, score, message, created_at`
+
+// AddAnomaly saves the anomaly unless the same transaction, or the same category month, was
+// already flagged for the same reason. It reports whether the anomaly was saved.
+func (db *SQLManager) AddAnomaly(ctx context.Context, am *domain.AnomalyModel) (bool, error) {
+	stmt := `insert into anomaly_model (` + anomalyColumns + `)
+		select ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? where not exists
+		(select 1 from anomaly_model where user_id = ? and kind = ? and transaction_id = ? and category_id = ? and month = ?)`
+	result, err := db.exec(ctx, rowsOf("anomaly_model", "anomaly_id = ?", am.AnomalyId), stmt, am.AnomalyId, am.UserId, am.Kind, am.TransactionId, am.CategoryId, am.PayeeId, am.Month, am.Amount, am.Expected, am.Score, am.Message, am.CreatedAt,
+		am.UserId, am.Kind, am.TransactionId, am.CategoryId, am.Month)
+	if err != nil {
+		log.Println("Error saving the anomaly to the database:", err)
+		return false, err
+	}
+	saved, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return saved > 0, nil
+}
+
+// GetAnomaliesByUserId returns the users anomalies, most recent month first.
+func (db *SQLManager) GetAnomaliesByUserId(userId uuid.UUID) ([]domain.AnomalyModel, error) {
+	stmt := `select ` + anomalyColumns + ` from anomaly_model where user_id = ? order by month desc, created_at desc`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving anomalies:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []domain.AnomalyModel
+	for rows.Next() {
+		var am domain.AnomalyModel
+		err := rows.Scan(&am.AnomalyId, &am.UserId, &am.Kind, &am.TransactionId, &am.CategoryId, &am.PayeeId, &am.Month, &am.Amount, &am.Expected, &am.Score, &am.Message, &am.CreatedAt)
+		if err != nil {
+			log.Println("Error reading anomaly row:", err)
+			return nil, err
+		}
+		anomalies = append(anomalies, am)
+	}
+	return anomalies, rows.Err()
+}
+
+// GetActiveUserIds returns every user with a transaction dated since the given time.
+func (db *SQLManager) GetActiveUserIds(since int64) ([]uuid.UUID, error) {
+	rows, err := db.DB.Query(`select distinct user_id from transaction_model where date >= ?`, since)
+	if err != nil {
+		log.Println("Error retrieving active users:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []uuid.UUID
+	for rows.Next() {
+		var userId uuid.UUID
+		if err := rows.Scan(&userId); err != nil {
+			log.Println("Error reading active user row:", err)
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+	return userIds, rows.Err()
+}
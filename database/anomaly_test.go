@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddAnomaly(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	am := domain.AnomalyModel{AnomalyId: uuid.New(), UserId: uuid.New(), Kind: domain.UNUSUAL_CATEGORY_MONTH, CategoryId: 3, Month: "2026-05", Amount: 600, Expected: 240, Score: 15, Message: "Category 3", CreatedAt: 10}
+	mock.ExpectExec("insert into anomaly_model (.+) where not exists").
+		WithArgs(am.AnomalyId, am.UserId, am.Kind, am.TransactionId, am.CategoryId, am.PayeeId, am.Month, am.Amount, am.Expected, am.Score, am.Message, am.CreatedAt,
+			am.UserId, am.Kind, am.TransactionId, am.CategoryId, am.Month).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	saved, err := udb.AddAnomaly(context.Background(), &am)
+	if err != nil {
+		t.Fatal("Error adding the anomaly:", err)
+	}
+	if saved {
+		t.Error("Expected an anomaly already flagged not to be saved again")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type AttachmentDatabaseInterface interface {
+	AddAttachment(ctx context.Context, am *domain.AttachmentModel) error
+	GetAttachment(attachmentId uuid.UUID) (domain.AttachmentModel, error)
+	GetAttachmentsByTransactionId(transactionId uuid.UUID) ([]domain.AttachmentModel, error)
+	DeleteAttachment(ctx context.Context, attachmentId uuid.UUID) error
+	CountAttachmentsByChecksum(checksum string) (int, error)
+}
+
+const attachmentColumns = `attachment_id, transaction_id, user_id, file_name, content_type, size, checksum, created_at`
+
+func (db *SQLManager) AddAttachment(ctx context.Context, am *domain.AttachmentModel) error {
+	stmt := `insert into attachment_model (` + attachmentColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("attachment_model", "attachment_id = ?", am.AttachmentId), stmt, am.AttachmentId, am.TransactionId, am.UserId, am.FileName, am.ContentType, am.Size, am.Checksum, am.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the attachment to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetAttachment(attachmentId uuid.UUID) (domain.AttachmentModel, error) {
+	stmt := `select ` + attachmentColumns + ` from attachment_model where attachment_id = ?`
+	attachment, err := scanAttachment(db.DB.QueryRow(stmt, attachmentId))
+	if err != nil {
+		log.Println("Error retrieving attachment:", err)
+		return attachment, err
+	}
+	return attachment, nil
+}
+
+func (db *SQLManager) GetAttachmentsByTransactionId(transactionId uuid.UUID) ([]domain.AttachmentModel, error) {
+	stmt := `select ` + attachmentColumns + ` from attachment_model where transaction_id = ? order by created_at`
+	rows, err := db.DB.Query(stmt, transactionId)
+	if err != nil {
+		log.Println("Error retrieving attachments:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []domain.AttachmentModel
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			log.Println("Error reading attachment row:", err)
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
+
+func (db *SQLManager) DeleteAttachment(ctx context.Context, attachmentId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("attachment_model", "attachment_id = ?", attachmentId), `delete from attachment_model where attachment_id = ?`, attachmentId)
+	if err != nil {
+		log.Println("Error deleting attachment:", err)
+		return err
+	}
+	return nil
+}
+
+// CountAttachmentsByChecksum counts the attachments sharing the stored contents.
+func (db *SQLManager) CountAttachmentsByChecksum(checksum string) (int, error) {
+	var count int
+	err := db.DB.QueryRow(`select count(*) from attachment_model where checksum = ?`, checksum).Scan(&count)
+	if err != nil {
+		log.Println("Error counting attachments:", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func scanAttachment(row rowScanner) (domain.AttachmentModel, error) {
+	var am domain.AttachmentModel
+	err := row.Scan(&am.AttachmentId, &am.TransactionId, &am.UserId, &am.FileName, &am.ContentType, &am.Size, &am.Checksum, &am.CreatedAt)
+	return am, err
+}
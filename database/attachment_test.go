@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	am := domain.AttachmentModel{AttachmentId: uuid.New(), TransactionId: uuid.New(), UserId: uuid.New(), FileName: "receipt.pdf", ContentType: "application/pdf", Size: 1024, Checksum: "ab12", CreatedAt: 1700000000000}
+	mock.ExpectExec("insert into attachment_model").
+		WithArgs(am.AttachmentId, am.TransactionId, am.UserId, am.FileName, am.ContentType, am.Size, am.Checksum, am.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddAttachment(context.Background(), &am)
+	if err != nil {
+		t.Fatal("Error adding attachment:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetAttachmentsByTransactionId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	transactionId := uuid.New()
+	rows := sqlmock.NewRows([]string{"attachment_id", "transaction_id", "user_id", "file_name", "content_type", "size", "checksum", "created_at"}).
+		AddRow(uuid.New(), transactionId, uuid.New(), "receipt.pdf", "application/pdf", 1024, "ab12", 1).
+		AddRow(uuid.New(), transactionId, uuid.New(), "invoice.png", "image/png", 2048, "cd34", 2)
+	mock.ExpectQuery("select (.+) from attachment_model where transaction_id = \\? order by created_at").
+		WithArgs(transactionId).
+		WillReturnRows(rows)
+
+	attachments, err := udb.GetAttachmentsByTransactionId(transactionId)
+	if err != nil {
+		t.Fatal("Error retrieving attachments:", err)
+	}
+	if len(attachments) != 2 || attachments[1].FileName != "invoice.png" || attachments[1].Size != 2048 {
+		t.Fatalf("Wrong attachments, got %v", attachments)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestCountAttachmentsByChecksum(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	mock.ExpectQuery("select count\\(\\*\\) from attachment_model where checksum = \\?").
+		WithArgs("ab12").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	count, err := udb.CountAttachmentsByChecksum("ab12")
+	if err != nil || count != 2 {
+		t.Fatalf("Wrong count, got %d, err: %v", count, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
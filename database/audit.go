@@ -0,0 +1,502 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type AuditDatabaseInterface interface {
+	GetEntityHistory(userId uuid.UUID, entity string, entityId string) ([]domain.AuditEntryModel, error)
+	GetAuditEntries(afterSeq int64, limit int) ([]domain.AuditEntryModel, error)
+}
+
+const auditColumns = `seq, audit_id, user_id, actor, action, entity, entity_id, diff, request_id, created_at, prev_hash, hash`
+
+// auditEntity is how the rows of an audited table are named in the audit log, Keys are the
+// columns that identify a row. Owner selects the user a row belongs to for the tables without a
+// user_id column.
+type auditEntity struct {
+	Name  string
+	Keys  []string
+	Owner string
+}
+
+// auditEntities are the audited tables, the data of the users. Writes to other tables, the
+// exchange rates and security prices shared by all users, the outbox, the webhook delivery log
+// and the audit log itself, are not audited.
+var auditEntities = map[string]auditEntity{
+	"account_currency":           {Name: "account_currency", Keys: []string{"user_id", "account_id"}},
+	"alert_model":                {Name: "alert", Keys: []string{"alert_id"}},
+	"alert_rule_model":           {Name: "alert_rule", Keys: []string{"alert_rule_id"}},
+	"anomaly_model":              {Name: "anomaly", Keys: []string{"anomaly_id"}},
+	"attachment_model":           {Name: "attachment", Keys: []string{"attachment_id"}},
+	"base_currency":              {Name: "base_currency", Keys: []string{"user_id"}},
+	"budget_model":               {Name: "budget", Keys: []string{"user_id", "category_id", "month"}},
+	"card_account":               {Name: "card", Keys: []string{"card_id"}},
+	"classifier_model":           {Name: "classifier", Keys: []string{"user_id"}},
+	"digest_schedule":            {Name: "digest_schedule", Keys: []string{"user_id"}},
+	"duplicate_model":            {Name: "duplicate", Keys: []string{"duplicate_id"}},
+	"goal_model":                 {Name: "goal", Keys: []string{"goal_id"}},
+	"investment_transaction":     {Name: "investment", Keys: []string{"investment_id"}},
+	"loan_model":                 {Name: "loan", Keys: []string{"loan_id"}},
+	"net_worth_snapshot":         {Name: "net_worth_snapshot", Keys: []string{"user_id", "date"}},
+	"payee_model":                {Name: "payee", Keys: []string{"payee_id"}},
+	"reconciliation_session":     {Name: "reconciliation", Keys: []string{"reconciliation_id"}},
+	"reconciliation_transaction": {Name: "reconciliation_transaction", Keys: []string{"reconciliation_id", "transaction_id"}, Owner: `(select user_id from reconciliation_session where reconciliation_session.reconciliation_id = reconciliation_transaction.reconciliation_id)`},
+	"rule_model":                 {Name: "rule", Keys: []string{"rule_id"}},
+	"scheduled_item_model":       {Name: "scheduled_item", Keys: []string{"scheduled_item_id"}},
+	"security_model":             {Name: "security", Keys: []string{"security_id"}},
+	"subscription_decision":      {Name: "subscription_decision", Keys: []string{"user_id", "subscription_key"}},
+	"tag_model":                  {Name: "tag", Keys: []string{"tag_id"}},
+	"tax_line":                   {Name: "tax_line", Keys: []string{"tax_line_id"}},
+	"transaction_model":          {Name: "transaction", Keys: []string{"transaction_id"}},
+	"transaction_tag":            {Name: "transaction_tag", Keys: []string{"transaction_id", "tag_id"}, Owner: `(select user_id from tag_model where tag_model.tag_id = transaction_tag.tag_id)`},
+	"user_model":                 {Name: "user", Keys: []string{"user_id"}},
+	"valuation_model":            {Name: "valuation", Keys: []string{"valuation_id"}},
+	"webhook_subscription":       {Name: "webhook", Keys: []string{"webhook_id"}},
+}
+
+// auditRedacted are the columns whose values are kept out of the audit log, only that they
+// changed is recorded. The classifier model is left out for its size, it is rebuilt from the
+// transactions anyway.
+var auditRedacted = map[string]bool{
+	"user_model.password_hash":    true,
+	"webhook_subscription.secret": true,
+	"classifier_model.model":      true,
+}
+
+const auditRedactedValue = "[redacted]"
+
+// GetEntityHistory returns the audit entries of the entity of the user, oldest first.
+func (db *SQLManager) GetEntityHistory(userId uuid.UUID, entity string, entityId string) ([]domain.AuditEntryModel, error) {
+	stmt := `select ` + auditColumns + ` from audit_log where user_id = ? and entity = ? and entity_id = ? order by seq`
+	return db.queryAuditEntries(stmt, userId, entity, entityId)
+}
+
+// GetAuditEntries returns the entries after afterSeq in the order of the chain.
+func (db *SQLManager) GetAuditEntries(afterSeq int64, limit int) ([]domain.AuditEntryModel, error) {
+	stmt := `select ` + auditColumns + ` from audit_log where seq > ? order by seq limit ?`
+	return db.queryAuditEntries(stmt, afterSeq, limit)
+}
+
+func (db *SQLManager) queryAuditEntries(stmt string, args ...any) ([]domain.AuditEntryModel, error) {
+	rows, err := db.DB.Query(stmt, args...)
+	if err != nil {
+		log.Println("Error retrieving audit entries:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntryModel
+	for rows.Next() {
+		var entry domain.AuditEntryModel
+		err = rows.Scan(&entry.Seq, &entry.AuditId, &entry.UserId, &entry.Actor, &entry.Action, &entry.Entity, &entry.EntityId, &entry.Diff, &entry.RequestId, &entry.CreatedAt, &entry.PrevHash, &entry.Hash)
+		if err != nil {
+			log.Println("Error reading audit entry row:", err)
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// AuditHash is the hash of the entry, chained to the entry before it through PrevHash.
+func AuditHash(entry *domain.AuditEntryModel) string {
+	fields, _ := json.Marshal([]any{entry.Seq, entry.AuditId, entry.UserId, entry.Actor, entry.Action, entry.Entity, entry.EntityId, entry.Diff, entry.RequestId, entry.CreatedAt})
+	sum := sha256.Sum256(append([]byte(entry.PrevHash+"\n"), fields...))
+	return hex.EncodeToString(sum[:])
+}
+
+// auditRows are the rows of an audited table matching where, the rows a write changes.
+type auditRows struct {
+	table string
+	where string
+	args  []any
+}
+
+func rowsOf(table string, where string, args ...any) auditRows {
+	return auditRows{table: table, where: where, args: args}
+}
+
+// txn is a SQL transaction that records the changes to the rows it tracks in the audit log
+// when auditing is on.
+type txn struct {
+	*sql.Tx
+	changes *auditChanges
+}
+
+// begin starts a SQL transaction whose changes are recorded as made by the audit scope of ctx.
+func (db *SQLManager) begin(ctx context.Context) (*txn, error) {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	t := txn{Tx: tx}
+	if db.Audit {
+		t.changes = &auditChanges{scope: domain.AuditScopeFrom(ctx), byKey: map[string]*auditChange{}}
+	}
+	return &t, nil
+}
+
+// exec runs a single write changing rows, in a SQL transaction of its own when auditing is on.
+func (db *SQLManager) exec(ctx context.Context, rows auditRows, query string, args ...any) (sql.Result, error) {
+	if !db.Audit {
+		return db.DB.Exec(query, args...)
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rows)
+	if err != nil {
+		return nil, err
+	}
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// track reads the rows as they are before the writes of the transaction, and again when it
+// commits to record what changed. Rows are tracked before they are written.
+func (t *txn) track(rows auditRows) error {
+	if t.changes == nil {
+		return nil
+	}
+	return t.changes.track(t.Tx, rows)
+}
+
+// Commit appends the changes of the transaction to the audit log before committing them.
+func (t *txn) Commit() error {
+	if t.changes != nil {
+		err := t.changes.append(t.Tx)
+		if err != nil {
+			return err
+		}
+	}
+	return t.Tx.Commit()
+}
+
+// auditKeyBatch is how many rows are read back by their keys at once.
+const auditKeyBatch = 100
+
+// auditChanges collects the changes of a SQL transaction per entity, from before its first
+// write to its commit, so a delete and insert replacing a row is one update.
+type auditChanges struct {
+	scope   domain.AuditScope
+	tracked []trackedRows
+	order   []*auditChange
+	byKey   map[string]*auditChange
+}
+
+// trackedRows are tracked rows and the changes of the rows they matched before the writes.
+type trackedRows struct {
+	auditRows
+	before []*auditChange
+}
+
+type auditChange struct {
+	entity   string
+	table    string
+	entityId string
+	owner    uuid.UUID
+	before   map[string]any
+	after    map[string]any
+}
+
+func (c *auditChanges) track(tx *sql.Tx, rows auditRows) error {
+	entity, ok := auditEntities[rows.table]
+	if !ok {
+		return fmt.Errorf("the table %s is not audited", rows.table)
+	}
+
+	before, err := selectRows(tx, rows.table, rows.where, rows.args)
+	if err != nil {
+		return err
+	}
+	tracked := trackedRows{auditRows: rows}
+	for _, row := range before {
+		change, first := c.change(entity, rows.table, row)
+		if first {
+			change.before = row.values
+		}
+		tracked.before = append(tracked.before, change)
+	}
+	c.tracked = append(c.tracked, tracked)
+	return nil
+}
+
+// readAfter reads the tracked rows as the transaction leaves them. Rows that no longer match
+// their condition are read back by their keys, and were deleted when they are not found.
+func (c *auditChanges) readAfter(tx *sql.Tx) error {
+	for _, tracked := range c.tracked {
+		entity := auditEntities[tracked.table]
+		after, err := selectRows(tx, tracked.table, tracked.where, tracked.args)
+		if err != nil {
+			return err
+		}
+
+		found := map[*auditChange]bool{}
+		for _, row := range after {
+			change, _ := c.change(entity, tracked.table, row)
+			change.after = row.values
+			found[change] = true
+		}
+		var moved []map[string]any
+		for _, change := range tracked.before {
+			if !found[change] {
+				moved = append(moved, change.before)
+			}
+		}
+
+		for len(moved) > 0 {
+			batch := moved[:min(len(moved), auditKeyBatch)]
+			moved = moved[len(batch):]
+			where, args := keyCondition(entity.Keys, batch)
+			after, err = selectRows(tx, tracked.table, where, args)
+			if err != nil {
+				return err
+			}
+			for _, row := range after {
+				change, _ := c.change(entity, tracked.table, row)
+				change.after = row.values
+			}
+		}
+	}
+	return nil
+}
+
+// change returns the change of the entity of the row, and whether the row is the first
+// seen of the entity. Entities first seen after the writes start from nothing.
+func (c *auditChanges) change(entity auditEntity, table string, row auditRow) (*auditChange, bool) {
+	entityId := auditEntityId(entity.Keys, row.values)
+	key := entity.Name + "\x00" + entityId
+	if change, ok := c.byKey[key]; ok {
+		if change.owner == uuid.Nil {
+			change.owner = row.owner
+		}
+		return change, false
+	}
+
+	change := &auditChange{entity: entity.Name, table: table, entityId: entityId, owner: row.owner}
+	c.byKey[key] = change
+	c.order = append(c.order, change)
+	return change, true
+}
+
+// append writes the entries of the changes after the end of the chain. The end is kept in the
+// single row of audit_chain, which the SQL transaction updates before reading it and so holds
+// locked until it commits. Concurrent writers, also from other processes, wait for each other
+// and append one after the other.
+func (c *auditChanges) append(tx *sql.Tx) error {
+	err := c.readAfter(tx)
+	if err != nil {
+		return err
+	}
+
+	var entries []domain.AuditEntryModel
+	now := time.Now().UnixMilli()
+	for _, change := range c.order {
+		action, diff, ok := change.diff()
+		if !ok {
+			continue
+		}
+		entries = append(entries, domain.AuditEntryModel{
+			AuditId:   uuid.New(),
+			UserId:    change.owner,
+			Actor:     c.scope.Actor,
+			Action:    action,
+			Entity:    change.entity,
+			EntityId:  change.entityId,
+			Diff:      diff,
+			RequestId: c.scope.RequestId,
+			CreatedAt: now,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	result, err := tx.Exec(`update audit_chain set seq = seq + ? where id = 1`, len(entries))
+	if err != nil {
+		log.Println("Error locking the end of the audit log:", err)
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return errors.New("the end of the audit log is missing from audit_chain")
+	}
+	var seq int64
+	var hash string
+	err = tx.QueryRow(`select seq, hash from audit_chain where id = 1`).Scan(&seq, &hash)
+	if err != nil {
+		log.Println("Error reading the end of the audit log:", err)
+		return err
+	}
+	seq -= int64(len(entries))
+
+	stmt := `insert into audit_log (` + auditColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	for _, entry := range entries {
+		entry.Seq, entry.PrevHash = seq+1, hash
+		entry.Hash = AuditHash(&entry)
+		_, err := tx.Exec(stmt, entry.Seq, entry.AuditId, entry.UserId, entry.Actor, entry.Action, entry.Entity, entry.EntityId, entry.Diff, entry.RequestId, entry.CreatedAt, entry.PrevHash, entry.Hash)
+		if err != nil {
+			log.Println("Error appending to the audit log:", err)
+			return err
+		}
+		seq, hash = entry.Seq, entry.Hash
+	}
+
+	_, err = tx.Exec(`update audit_chain set hash = ? where id = 1`, hash)
+	if err != nil {
+		log.Println("Error updating the end of the audit log:", err)
+	}
+	return err
+}
+
+// diff is the action and the JSON diff of the change, not ok when nothing changed.
+func (change *auditChange) diff() (domain.AuditAction, string, bool) {
+	var action domain.AuditAction
+	before, after := map[string]any{}, map[string]any{}
+	switch {
+	case change.before == nil && change.after == nil:
+		return "", "", false
+	case change.before == nil:
+		action, before, after = domain.AUDIT_CREATE, nil, change.after
+	case change.after == nil:
+		action, before, after = domain.AUDIT_DELETE, change.before, nil
+	default:
+		action = domain.AUDIT_UPDATE
+		for column, value := range change.after {
+			if !reflect.DeepEqual(change.before[column], value) {
+				before[column], after[column] = change.before[column], value
+			}
+		}
+		if len(after) == 0 {
+			return "", "", false
+		}
+	}
+
+	diff, err := json.Marshal(map[string]any{"before": change.redact(before), "after": change.redact(after)})
+	if err != nil {
+		log.Println("Error writing the audit diff:", err)
+		diff = []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+	}
+	return action, string(diff), true
+}
+
+func (change *auditChange) redact(row map[string]any) map[string]any {
+	if row == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(row))
+	for column, value := range row {
+		if auditRedacted[change.table+"."+column] {
+			value = auditRedactedValue
+		}
+		redacted[column] = value
+	}
+	return redacted
+}
+
+// auditRow is a row read to audit, its values column by column and the user it belongs to.
+type auditRow struct {
+	owner  uuid.UUID
+	values map[string]any
+}
+
+// auditOwnerColumn is the owner of the rows read to audit.
+const auditOwnerColumn = "audit_owner"
+
+// selectRows reads the rows matching where, column by column. The id column some tables
+// number their rows with is left out.
+func selectRows(tx *sql.Tx, table string, where string, args []any) ([]auditRow, error) {
+	owner := auditEntities[table].Owner
+	if owner == "" {
+		owner = table + ".user_id"
+	}
+	rows, err := tx.Query(`select `+table+`.*, `+owner+` as `+auditOwnerColumn+` from `+table+` where `+where, args...)
+	if err != nil {
+		log.Println("Error reading the rows to audit:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []auditRow
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		err = rows.Scan(pointers...)
+		if err != nil {
+			log.Println("Error reading the rows to audit:", err)
+			return nil, err
+		}
+
+		row := auditRow{values: make(map[string]any, len(columns))}
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			switch column {
+			case "id":
+			case auditOwnerColumn:
+				row.owner, _ = uuid.Parse(fmt.Sprint(values[i]))
+			default:
+				row.values[column] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// keyCondition selects the rows back by their keys.
+func keyCondition(keys []string, rows []map[string]any) (string, []any) {
+	var conditions []string
+	var args []any
+	for _, row := range rows {
+		var parts []string
+		for _, key := range keys {
+			parts = append(parts, key+" = ?")
+			args = append(args, row[key])
+		}
+		conditions = append(conditions, "("+strings.Join(parts, " and ")+")")
+	}
+	return strings.Join(conditions, " or "), args
+}
+
+// auditEntityId joins the key values of the row, like "<user id>/12" for two keys.
+func auditEntityId(keys []string, row map[string]any) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprint(row[key])
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestExec_Audited(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db, Audit: true}
+	ctx := domain.WithAuditScope(context.Background(), domain.AuditScope{Actor: "u1", RequestId: "req-1"})
+
+	goalId, userId := uuid.New(), uuid.New()
+	columns := []string{"id", "goal_id", "name", "audit_owner"}
+	selectGoal := regexp.QuoteMeta("select goal_model.*, goal_model.user_id as audit_owner from goal_model where goal_id = ?")
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectGoal).WithArgs(goalId).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, goalId.String(), "Car", userId.String()))
+	mock.ExpectExec("update goal_model").WithArgs("House", goalId).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(selectGoal).WithArgs(goalId).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, goalId.String(), "House", userId.String()))
+	mock.ExpectExec(regexp.QuoteMeta("update audit_chain set seq = seq + ? where id = 1")).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("select seq, hash from audit_chain where id = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "hash"}).AddRow(5, "abc"))
+	mock.ExpectExec("insert into audit_log").
+		WithArgs(5, sqlmock.AnyArg(), userId, "u1", domain.AUDIT_UPDATE, "goal", goalId.String(), `{"after":{"name":"House"},"before":{"name":"Car"}}`, "req-1", sqlmock.AnyArg(), "abc", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta("update audit_chain set hash = ? where id = 1")).WithArgs(sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, err = udb.exec(ctx, rowsOf("goal_model", "goal_id = ?", goalId), `update goal_model set name = ? where goal_id = ?`, "House", goalId)
+	if err != nil {
+		t.Fatal("Error updating the goal:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestTrack_RowsLeavingTheCondition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db, Audit: true}
+
+	// t1 moves to another payee and t2 is deleted, neither matches the condition afterwards.
+	sourceId, targetId, userId := uuid.NewString(), uuid.NewString(), uuid.New()
+	columns := []string{"transaction_id", "payee_id", "audit_owner"}
+	selectTransactions := regexp.QuoteMeta("select transaction_model.*, transaction_model.user_id as audit_owner from transaction_model where ")
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectTransactions + regexp.QuoteMeta("payee_id = ?")).WithArgs(sourceId).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("t1", sourceId, userId.String()).AddRow("t2", sourceId, userId.String()))
+	mock.ExpectQuery(selectTransactions + regexp.QuoteMeta("payee_id = ?")).WithArgs(sourceId).WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(selectTransactions+regexp.QuoteMeta("(transaction_id = ?) or (transaction_id = ?)")).WithArgs("t1", "t2").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("t1", targetId, userId.String()))
+	mock.ExpectExec("update audit_chain set seq").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("select seq, hash from audit_chain").WillReturnRows(sqlmock.NewRows([]string{"seq", "hash"}).AddRow(2, ""))
+	mock.ExpectExec("insert into audit_log").
+		WithArgs(1, sqlmock.AnyArg(), userId, domain.AuditSystemActor, domain.AUDIT_UPDATE, "transaction", "t1", sqlmock.AnyArg(), "", sqlmock.AnyArg(), "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("insert into audit_log").
+		WithArgs(2, sqlmock.AnyArg(), userId, domain.AuditSystemActor, domain.AUDIT_DELETE, "transaction", "t2", sqlmock.AnyArg(), "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("update audit_chain set hash").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := udb.begin(context.Background())
+	if err != nil {
+		t.Fatal("Error starting the transaction:", err)
+	}
+	defer tx.Rollback()
+	if err := tx.track(rowsOf("transaction_model", "payee_id = ?", sourceId)); err != nil {
+		t.Fatal("Error tracking the transactions:", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal("Error committing:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestAuditChangeDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		change auditChange
+		action domain.AuditAction
+		diff   string
+		ok     bool
+	}{
+		{
+			name:   "Create",
+			change: auditChange{table: "tag_model", after: map[string]any{"tag_id": "t1", "name": "travel"}},
+			action: domain.AUDIT_CREATE,
+			diff:   `{"after":{"name":"travel","tag_id":"t1"},"before":null}`,
+			ok:     true,
+		},
+		{
+			name:   "Update with a redacted column",
+			change: auditChange{table: "user_model", before: map[string]any{"user_id": "u1", "email": "a@test.com", "password_hash": "x"}, after: map[string]any{"user_id": "u1", "email": "b@test.com", "password_hash": "y"}},
+			action: domain.AUDIT_UPDATE,
+			diff:   `{"after":{"email":"b@test.com","password_hash":"[redacted]"},"before":{"email":"a@test.com","password_hash":"[redacted]"}}`,
+			ok:     true,
+		},
+		{
+			name:   "Delete",
+			change: auditChange{table: "tag_model", before: map[string]any{"tag_id": "t1"}},
+			action: domain.AUDIT_DELETE,
+			diff:   `{"after":null,"before":{"tag_id":"t1"}}`,
+			ok:     true,
+		},
+		{
+			name:   "Unchanged",
+			change: auditChange{table: "tag_model", before: map[string]any{"tag_id": "t1"}, after: map[string]any{"tag_id": "t1"}},
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			action, diff, ok := test.change.diff()
+			if ok != test.ok || action != test.action || diff != test.diff {
+				t.Errorf("Unexpected diff %v %s %v, want %v %s %v", action, diff, ok, test.action, test.diff, test.ok)
+			}
+		})
+	}
+}
+
+func TestAuditHash(t *testing.T) {
+	entry := domain.AuditEntryModel{Seq: 2, AuditId: uuid.New(), Actor: "system", Action: domain.AUDIT_UPDATE, Entity: "transaction", EntityId: "t1", Diff: `{}`, CreatedAt: 11, PrevHash: "abc"}
+	hash := AuditHash(&entry)
+	if len(hash) != 64 || hash != AuditHash(&entry) {
+		t.Fatalf("Unexpected hash %q", hash)
+	}
+
+	tampered := entry
+	tampered.Actor = "someone else"
+	moved := entry
+	moved.UserId = uuid.New()
+	relinked := entry
+	relinked.PrevHash = "abd"
+	if AuditHash(&tampered) == hash || AuditHash(&moved) == hash || AuditHash(&relinked) == hash {
+		t.Error("The hash does not cover the entry and the previous hash")
+	}
+}
+
+func TestAppend_MissingChain(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db, Audit: true}
+
+	tagId := uuid.NewString()
+	mock.ExpectBegin()
+	mock.ExpectQuery("select tag_model").WillReturnRows(sqlmock.NewRows([]string{"tag_id", "audit_owner"}))
+	mock.ExpectExec("insert into tag_model").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("select tag_model").WillReturnRows(sqlmock.NewRows([]string{"tag_id", "audit_owner"}).AddRow(tagId, uuid.NewString()))
+	mock.ExpectExec("update audit_chain set seq").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = udb.exec(context.Background(), rowsOf("tag_model", "tag_id = ?", tagId), `insert into tag_model (tag_id) values (?)`, tagId)
+	if err == nil {
+		t.Error("Expected the write to fail without the end of the audit log")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
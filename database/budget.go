@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type BudgetDatabaseInterface interface {
+	SaveBudget(ctx context.Context, bm *domain.BudgetModel) error
+	GetBudgetsByUserId(userId uuid.UUID) ([]domain.BudgetModel, error)
+	DeleteBudget(ctx context.Context, userId uuid.UUID, categoryId int64, month string) error
+	GetCategorySpending(userId uuid.UUID, from int64, to int64) (map[int64]float64, error)
+}
+
+// SaveBudget replaces the budget the category has for the month, if any.
+func (db *SQLManager) SaveBudget(ctx context.Context, bm *domain.BudgetModel) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("budget_model", "user_id = ? and category_id = ? and month = ?", bm.UserId, bm.CategoryId, bm.Month))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`delete from budget_model where user_id = ? and category_id = ? and month = ?`, bm.UserId, bm.CategoryId, bm.Month)
+	if err != nil {
+		log.Println("Error replacing the budget:", err)
+		return err
+	}
+
+	stmt := `insert into budget_model (budget_id, user_id, category_id, month, amount, rollover, created_at) values (?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.Exec(stmt, bm.BudgetId, bm.UserId, bm.CategoryId, bm.Month, bm.Amount, bm.Rollover, bm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the budget to the database:", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetBudgetsByUserId returns the users budgets ordered by category, then month.
+func (db *SQLManager) GetBudgetsByUserId(userId uuid.UUID) ([]domain.BudgetModel, error) {
+	stmt := `select budget_id, user_id, category_id, month, amount, rollover, created_at from budget_model where user_id = ? order by category_id, month`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving budgets:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []domain.BudgetModel
+	for rows.Next() {
+		var bm domain.BudgetModel
+		err := rows.Scan(&bm.BudgetId, &bm.UserId, &bm.CategoryId, &bm.Month, &bm.Amount, &bm.Rollover, &bm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading budget row:", err)
+			return nil, err
+		}
+		budgets = append(budgets, bm)
+	}
+	return budgets, rows.Err()
+}
+
+func (db *SQLManager) DeleteBudget(ctx context.Context, userId uuid.UUID, categoryId int64, month string) error {
+	_, err := db.exec(ctx, rowsOf("budget_model", "user_id = ? and category_id = ? and month = ?", userId, categoryId, month), `delete from budget_model where user_id = ? and category_id = ? and month = ?`, userId, categoryId, month)
+	if err != nil {
+		log.Println("Error deleting budget:", err)
+		return err
+	}
+	return nil
+}
+
+// GetCategorySpending totals the users expenses per category between from and to, inclusive.
+// Cancelled transactions are excluded.
+func (db *SQLManager) GetCategorySpending(userId uuid.UUID, from int64, to int64) (map[int64]float64, error) {
+	stmt := `select category_id, sum(amount) from transaction_model
+		where user_id = ? and date >= ? and date <= ? and type = ? and status != ?
+		group by category_id`
+	rows, err := db.DB.Query(stmt, userId, from, to, domain.EXPENSE, domain.CANCELLED)
+	if err != nil {
+		log.Println("Error retrieving category spending:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	spending := map[int64]float64{}
+	for rows.Next() {
+		var categoryId int64
+		var total float64
+		err := rows.Scan(&categoryId, &total)
+		if err != nil {
+			log.Println("Error reading category spending row:", err)
+			return nil, err
+		}
+		spending[categoryId] = total
+	}
+	return spending, rows.Err()
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestSaveBudget(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	bm := domain.BudgetModelBuilder().WithMonth("2026-03").Build()
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from budget_model where user_id = \\? and category_id = \\? and month = \\?").
+		WithArgs(bm.UserId, bm.CategoryId, bm.Month).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("insert into budget_model").
+		WithArgs(bm.BudgetId, bm.UserId, bm.CategoryId, bm.Month, bm.Amount, bm.Rollover, bm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.SaveBudget(context.Background(), &bm)
+	if err != nil {
+		t.Fatal("Error saving the budget:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetCategorySpending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	rows := sqlmock.NewRows([]string{"category_id", "sum"}).AddRow(3, 120.5).AddRow(7, 40)
+	mock.ExpectQuery("select category_id, sum\\(amount\\) from transaction_model (.+) group by category_id").
+		WithArgs(userId, 10, 20, domain.EXPENSE, domain.CANCELLED).
+		WillReturnRows(rows)
+
+	spending, err := udb.GetCategorySpending(userId, 10, 20)
+	if err != nil {
+		t.Fatal("Error retrieving category spending:", err)
+	}
+	if len(spending) != 2 || spending[3] != 120.5 || spending[7] != 40 {
+		t.Errorf("Wrong spending, got %v", spending)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
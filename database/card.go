@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type CardDatabaseInterface interface {
+	AddCard(ctx context.Context, cm *domain.CardAccountModel) error
+	GetCard(cardId uuid.UUID) (domain.CardAccountModel, error)
+	GetCardsByUserId(userId uuid.UUID) ([]domain.CardAccountModel, error)
+	DeleteCard(ctx context.Context, cardId uuid.UUID) error
+}
+
+const cardColumns = `card_id, user_id, account_id, name, closing_day, due_day, minimum_percent, minimum_amount, created_at`
+
+func (db *SQLManager) AddCard(ctx context.Context, cm *domain.CardAccountModel) error {
+	stmt := `insert into card_account (` + cardColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("card_account", "card_id = ?", cm.CardId), stmt, cm.CardId, cm.UserId, cm.AccountId, cm.Name, cm.ClosingDay, cm.DueDay, cm.MinimumPercent, cm.MinimumAmount, cm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the card to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetCard(cardId uuid.UUID) (domain.CardAccountModel, error) {
+	stmt := `select ` + cardColumns + ` from card_account where card_id = ?`
+	card, err := scanCard(db.DB.QueryRow(stmt, cardId))
+	if err != nil {
+		log.Println("Error retrieving card:", err)
+		return card, err
+	}
+	return card, nil
+}
+
+func (db *SQLManager) GetCardsByUserId(userId uuid.UUID) ([]domain.CardAccountModel, error) {
+	stmt := `select ` + cardColumns + ` from card_account where user_id = ? order by created_at`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving cards:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []domain.CardAccountModel
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			log.Println("Error reading card row:", err)
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+func (db *SQLManager) DeleteCard(ctx context.Context, cardId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("card_account", "card_id = ?", cardId), `delete from card_account where card_id = ?`, cardId)
+	if err != nil {
+		log.Println("Error deleting card:", err)
+		return err
+	}
+	return nil
+}
+
+func scanCard(row rowScanner) (domain.CardAccountModel, error) {
+	var card domain.CardAccountModel
+	err := row.Scan(&card.CardId, &card.UserId, &card.AccountId, &card.Name, &card.ClosingDay, &card.DueDay, &card.MinimumPercent, &card.MinimumAmount, &card.CreatedAt)
+	return card, err
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddCard(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	cm := domain.CardAccountModel{CardId: uuid.New(), UserId: uuid.New(), AccountId: 3, Name: "Visa", ClosingDay: 25, DueDay: 20, MinimumPercent: 2, MinimumAmount: 25, CreatedAt: 11}
+	mock.ExpectExec("insert into card_account").
+		WithArgs(cm.CardId, cm.UserId, cm.AccountId, cm.Name, cm.ClosingDay, cm.DueDay, cm.MinimumPercent, cm.MinimumAmount, cm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddCard(context.Background(), &cm)
+	if err != nil {
+		t.Fatal("Error saving the card:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetCardsByUserId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	rows := sqlmock.NewRows([]string{"card_id", "user_id", "account_id", "name", "closing_day", "due_day", "minimum_percent", "minimum_amount", "created_at"}).
+		AddRow(uuid.New(), userId, 3, "Visa", 25, 20, 2.0, 25.0, 11)
+	mock.ExpectQuery("select (.+) from card_account where user_id = \\? order by created_at").
+		WithArgs(userId).
+		WillReturnRows(rows)
+
+	cards, err := udb.GetCardsByUserId(userId)
+	if err != nil {
+		t.Fatal("Error retrieving the cards:", err)
+	}
+	if len(cards) != 1 || cards[0].AccountId != 3 || cards[0].ClosingDay != 25 || cards[0].DueDay != 20 || cards[0].MinimumAmount != 25 {
+		t.Errorf("Unexpected cards %+v", cards)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
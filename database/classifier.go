@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type ClassifierDatabaseInterface interface {
+	GetClassifier(userId uuid.UUID) (domain.ClassifierModel, error)
+	SaveClassifier(ctx context.Context, cm *domain.ClassifierModel) error
+}
+
+// classifierCounts is how the counts of a ClassifierModel are stored in the model column.
+type classifierCounts struct {
+	CategoryCounts map[int64]int            `json:"categoryCounts"`
+	TokenCounts    map[int64]map[string]int `json:"tokenCounts"`
+	TokenTotals    map[int64]int            `json:"tokenTotals"`
+	Vocabulary     map[string]int           `json:"vocabulary"`
+	Learned        map[uuid.UUID]int64      `json:"learned"`
+}
+
+func (db *SQLManager) GetClassifier(userId uuid.UUID) (domain.ClassifierModel, error) {
+	stmt := `select user_id, model, updated_at from classifier_model where user_id = ?`
+	cm := domain.NewClassifierModel(userId)
+	var model string
+	err := db.DB.QueryRow(stmt, userId).Scan(&cm.UserId, &model, &cm.UpdatedAt)
+	if err != nil {
+		return cm, err
+	}
+
+	counts := classifierCounts{CategoryCounts: cm.CategoryCounts, TokenCounts: cm.TokenCounts, TokenTotals: cm.TokenTotals, Vocabulary: cm.Vocabulary, Learned: cm.Learned}
+	err = json.Unmarshal([]byte(model), &counts)
+	if err != nil {
+		log.Println("Error reading classifier model:", err)
+		return cm, err
+	}
+	return cm, nil
+}
+
+// SaveClassifier replaces the users stored classifier.
+func (db *SQLManager) SaveClassifier(ctx context.Context, cm *domain.ClassifierModel) error {
+	model, err := json.Marshal(classifierCounts{CategoryCounts: cm.CategoryCounts, TokenCounts: cm.TokenCounts, TokenTotals: cm.TokenTotals, Vocabulary: cm.Vocabulary, Learned: cm.Learned})
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("classifier_model", "user_id = ?", cm.UserId))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`delete from classifier_model where user_id = ?`, cm.UserId)
+	if err != nil {
+		log.Println("Error removing the old classifier:", err)
+		return err
+	}
+	_, err = tx.Exec(`insert into classifier_model (user_id, model, updated_at) values (?, ?, ?)`, cm.UserId, string(model), cm.UpdatedAt)
+	if err != nil {
+		log.Println("Error saving the classifier to the database:", err)
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestSaveClassifier(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	cm := domain.NewClassifierModel(uuid.New())
+	cm.CategoryCounts[4] = 1
+	cm.UpdatedAt = 10
+
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from classifier_model where user_id = ?").
+		WithArgs(cm.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("insert into classifier_model").
+		WithArgs(cm.UserId, `{"categoryCounts":{"4":1},"tokenCounts":{},"tokenTotals":{},"vocabulary":{},"learned":{}}`, cm.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.SaveClassifier(context.Background(), &cm)
+	if err != nil {
+		t.Fatal("Error saving classifier:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
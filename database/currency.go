@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type CurrencyDatabaseInterface interface {
+	SaveExchangeRates(rates []domain.ExchangeRateModel) error
+	GetExchangeRate(currency string, date string) (domain.ExchangeRateModel, error)
+	SaveBaseCurrency(ctx context.Context, bm *domain.BaseCurrencyModel, converted []domain.TransactionModel, events ...domain.OutboxEventModel) error
+	GetBaseCurrency(userId uuid.UUID) (string, error)
+	SaveAccountCurrency(ctx context.Context, am *domain.AccountCurrencyModel) error
+	GetAccountCurrency(userId uuid.UUID, accountId int64) (string, error)
+}
+
+// SaveExchangeRates replaces the rates already saved for the same currency and day, so the
+// same file can be imported again.
+func (db *SQLManager) SaveExchangeRates(rates []domain.ExchangeRateModel) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		_, err = tx.Exec(`delete from exchange_rate where currency = ? and date = ?`, rate.Currency, rate.Date)
+		if err != nil {
+			log.Println("Error replacing the exchange rate:", err)
+			return err
+		}
+		_, err = tx.Exec(`insert into exchange_rate (currency, date, rate) values (?, ?, ?)`, rate.Currency, rate.Date, rate.Rate)
+		if err != nil {
+			log.Println("Error saving the exchange rate to the database:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetExchangeRate returns the latest rate of the currency published on or before date, rates
+// are not published on weekends and holidays. It returns sql.ErrNoRows when there is none.
+func (db *SQLManager) GetExchangeRate(currency string, date string) (domain.ExchangeRateModel, error) {
+	stmt := `select currency, date, rate from exchange_rate where currency = ? and date <= ? order by date desc limit 1`
+	var rate domain.ExchangeRateModel
+	err := db.DB.QueryRow(stmt, currency, date).Scan(&rate.Currency, &rate.Date, &rate.Rate)
+	return rate, err
+}
+
+// SaveBaseCurrency saves the base currency together with the users transactions converted into it
+// and their events, all in one transaction.
+func (db *SQLManager) SaveBaseCurrency(ctx context.Context, bm *domain.BaseCurrencyModel, converted []domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rows := range []auditRows{rowsOf("base_currency", "user_id = ?", bm.UserId), rowsOf("transaction_model", "user_id = ?", bm.UserId)} {
+		err = tx.track(rows)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`delete from base_currency where user_id = ?`, bm.UserId)
+	if err != nil {
+		log.Println("Error replacing the base currency:", err)
+		return err
+	}
+	_, err = tx.Exec(`insert into base_currency (user_id, currency) values (?, ?)`, bm.UserId, bm.Currency)
+	if err != nil {
+		log.Println("Error saving the base currency to the database:", err)
+		return err
+	}
+	for i := range converted {
+		err = updateTransaction(tx, &converted[i])
+		if err != nil {
+			return err
+		}
+	}
+	err = addEvents(tx, events)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetBaseCurrency returns sql.ErrNoRows when the user has not chosen a base currency.
+func (db *SQLManager) GetBaseCurrency(userId uuid.UUID) (string, error) {
+	var currency string
+	err := db.DB.QueryRow(`select currency from base_currency where user_id = ?`, userId).Scan(&currency)
+	return currency, err
+}
+
+func (db *SQLManager) SaveAccountCurrency(ctx context.Context, am *domain.AccountCurrencyModel) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("account_currency", "user_id = ? and account_id = ?", am.UserId, am.AccountId))
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`delete from account_currency where user_id = ? and account_id = ?`, am.UserId, am.AccountId)
+	if err != nil {
+		log.Println("Error replacing the account currency:", err)
+		return err
+	}
+	_, err = tx.Exec(`insert into account_currency (user_id, account_id, currency) values (?, ?, ?)`, am.UserId, am.AccountId, am.Currency)
+	if err != nil {
+		log.Println("Error saving the account currency to the database:", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetAccountCurrency returns sql.ErrNoRows when the account has no currency set.
+func (db *SQLManager) GetAccountCurrency(userId uuid.UUID, accountId int64) (string, error) {
+	var currency string
+	err := db.DB.QueryRow(`select currency from account_currency where user_id = ? and account_id = ?`, userId, accountId).Scan(&currency)
+	return currency, err
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestSaveExchangeRates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	rates := []domain.ExchangeRateModel{{Currency: "USD", Date: "2026-03-02", Rate: 1.08}, {Currency: "GBP", Date: "2026-03-02", Rate: 0.85}}
+	mock.ExpectBegin()
+	for _, rate := range rates {
+		mock.ExpectExec("delete from exchange_rate where currency = \\? and date = \\?").
+			WithArgs(rate.Currency, rate.Date).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into exchange_rate").
+			WithArgs(rate.Currency, rate.Date, rate.Rate).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	err = udb.SaveExchangeRates(rates)
+	if err != nil {
+		t.Fatal("Error saving the exchange rates:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestSaveBaseCurrency(t *testing.T) {
+	bm := domain.BaseCurrencyModel{UserId: uuid.New(), Currency: "USD"}
+	converted := domain.TransactionModelBuilder().Build()
+
+	tests := []struct {
+		name      string
+		updateErr error
+	}{
+		{name: "Saved together"},
+		{name: "Rolled back", updateErr: errors.New("lock wait timeout")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("Error creating SQL stub", err)
+			}
+			defer db.Close()
+			udb := SQLManager{DB: db}
+
+			mock.ExpectBegin()
+			mock.ExpectExec("delete from base_currency where user_id = \\?").
+				WithArgs(bm.UserId).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("insert into base_currency").
+				WithArgs(bm.UserId, bm.Currency).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			update := mock.ExpectExec("update transaction_model set").
+				WithArgs(converted.CategoryId, converted.AccountId, converted.PayeeId, converted.Amount, converted.Date, converted.Description, converted.UpdatedAt, converted.Type, converted.PaymentMethod, converted.Status, converted.Currency, converted.OriginalAmount, converted.TransactionId)
+			if test.updateErr != nil {
+				update.WillReturnError(test.updateErr)
+				mock.ExpectRollback()
+			} else {
+				update.WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			err = udb.SaveBaseCurrency(context.Background(), &bm, []domain.TransactionModel{converted})
+			if !errors.Is(err, test.updateErr) {
+				t.Fatalf("Wrong error, got %v, want %v", err, test.updateErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal("Expectations were not met:", err)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	expected := domain.ExchangeRateModel{Currency: "USD", Date: "2026-03-06", Rate: 1.09}
+	mock.ExpectQuery("select currency, date, rate from exchange_rate where currency = \\? and date <= \\? order by date desc limit 1").
+		WithArgs("USD", "2026-03-08").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "date", "rate"}).AddRow(expected.Currency, expected.Date, expected.Rate))
+
+	rate, err := udb.GetExchangeRate("USD", "2026-03-08")
+	if err != nil {
+		t.Fatal("Error retrieving the exchange rate:", err)
+	}
+	if rate != expected {
+		t.Errorf("Expected %+v, got %+v", expected, rate)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetBaseCurrency_NotChosen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	mock.ExpectQuery("select currency from base_currency where user_id = \\?").
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"currency"}))
+
+	_, err = udb.GetBaseCurrency(userId)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestSaveAccountCurrency(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	am := domain.AccountCurrencyModel{UserId: uuid.New(), AccountId: 3, Currency: "USD"}
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from account_currency where user_id = \\? and account_id = \\?").
+		WithArgs(am.UserId, am.AccountId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("insert into account_currency").
+		WithArgs(am.UserId, am.AccountId, am.Currency).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.SaveAccountCurrency(context.Background(), &am)
+	if err != nil {
+		t.Fatal("Error saving the account currency:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,18 @@
+package database
+
+import "github.com/go-sql-driver/mysql"
+
+// isMySQL reports whether the database is MySQL. The tests run against sqlite, which spells a
+// few statements differently.
+func (db *SQLManager) isMySQL() bool {
+	_, ok := db.DB.Driver().(*mysql.MySQLDriver)
+	return ok
+}
+
+// insertIgnore starts an insert that skips the rows breaking a unique key.
+func (db *SQLManager) insertIgnore() string {
+	if db.isMySQL() {
+		return "insert ignore"
+	}
+	return "insert or ignore"
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type DigestDatabaseInterface interface {
+	SaveDigestSchedule(ctx context.Context, dm *domain.DigestScheduleModel) error
+	GetDigestSchedule(userId uuid.UUID) (domain.DigestScheduleModel, error)
+	GetDueDigestSchedules(now int64) ([]domain.DigestScheduleModel, error)
+	UpdateDigestRun(ctx context.Context, userId uuid.UUID, lastSentAt int64, nextRunAt int64) error
+	DeleteDigestSchedule(ctx context.Context, userId uuid.UUID) error
+}
+
+const digestColumns = `user_id, frequency, day, hour, minute, timezone, next_run_at, last_sent_at, created_at`
+
+// SaveDigestSchedule replaces the users digest schedule, if any.
+func (db *SQLManager) SaveDigestSchedule(ctx context.Context, dm *domain.DigestScheduleModel) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("digest_schedule", "user_id = ?", dm.UserId))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`delete from digest_schedule where user_id = ?`, dm.UserId)
+	if err != nil {
+		log.Println("Error replacing the digest schedule:", err)
+		return err
+	}
+
+	stmt := `insert into digest_schedule (` + digestColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.Exec(stmt, dm.UserId, dm.Frequency, dm.Day, dm.Hour, dm.Minute, dm.Timezone, dm.NextRunAt, dm.LastSentAt, dm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the digest schedule to the database:", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *SQLManager) GetDigestSchedule(userId uuid.UUID) (domain.DigestScheduleModel, error) {
+	stmt := `select ` + digestColumns + ` from digest_schedule where user_id = ?`
+	schedule, err := scanDigestSchedule(db.DB.QueryRow(stmt, userId))
+	if err != nil {
+		log.Println("Error retrieving digest schedule:", err)
+		return schedule, err
+	}
+	return schedule, nil
+}
+
+// GetDueDigestSchedules returns the schedules with a digest due at now, the longest overdue first.
+func (db *SQLManager) GetDueDigestSchedules(now int64) ([]domain.DigestScheduleModel, error) {
+	stmt := `select ` + digestColumns + ` from digest_schedule where next_run_at <= ? order by next_run_at`
+	rows, err := db.DB.Query(stmt, now)
+	if err != nil {
+		log.Println("Error retrieving due digest schedules:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []domain.DigestScheduleModel
+	for rows.Next() {
+		schedule, err := scanDigestSchedule(rows)
+		if err != nil {
+			log.Println("Error reading digest schedule row:", err)
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (db *SQLManager) UpdateDigestRun(ctx context.Context, userId uuid.UUID, lastSentAt int64, nextRunAt int64) error {
+	_, err := db.exec(ctx, rowsOf("digest_schedule", "user_id = ?", userId), `update digest_schedule set last_sent_at = ?, next_run_at = ? where user_id = ?`, lastSentAt, nextRunAt, userId)
+	if err != nil {
+		log.Println("Error updating the digest run:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) DeleteDigestSchedule(ctx context.Context, userId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("digest_schedule", "user_id = ?", userId), `delete from digest_schedule where user_id = ?`, userId)
+	if err != nil {
+		log.Println("Error deleting digest schedule:", err)
+		return err
+	}
+	return nil
+}
+
+func scanDigestSchedule(row rowScanner) (domain.DigestScheduleModel, error) {
+	var schedule domain.DigestScheduleModel
+	err := row.Scan(&schedule.UserId, &schedule.Frequency, &schedule.Day, &schedule.Hour, &schedule.Minute, &schedule.Timezone, &schedule.NextRunAt, &schedule.LastSentAt, &schedule.CreatedAt)
+	return schedule, err
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestSaveDigestSchedule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	dm := domain.DigestScheduleModel{UserId: uuid.New(), Frequency: domain.DIGEST_WEEKLY, Day: 1, Hour: 8, Minute: 30, Timezone: "Europe/Berlin", NextRunAt: 200, CreatedAt: 100}
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from digest_schedule where user_id = \\?").
+		WithArgs(dm.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("insert into digest_schedule").
+		WithArgs(dm.UserId, dm.Frequency, dm.Day, dm.Hour, dm.Minute, dm.Timezone, dm.NextRunAt, dm.LastSentAt, dm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.SaveDigestSchedule(context.Background(), &dm)
+	if err != nil {
+		t.Fatal("Error saving the digest schedule:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetDueDigestSchedules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	rows := sqlmock.NewRows([]string{"user_id", "frequency", "day", "hour", "minute", "timezone", "next_run_at", "last_sent_at", "created_at"}).
+		AddRow(uuid.New(), domain.DIGEST_MONTHLY, 31, 7, 0, "UTC", 150, 0, 100)
+	mock.ExpectQuery("select (.+) from digest_schedule where next_run_at <= \\? order by next_run_at").
+		WithArgs(int64(200)).
+		WillReturnRows(rows)
+
+	schedules, err := udb.GetDueDigestSchedules(200)
+	if err != nil {
+		t.Fatal("Error retrieving the due digest schedules:", err)
+	}
+	if len(schedules) != 1 || schedules[0].Frequency != domain.DIGEST_MONTHLY || schedules[0].Day != 31 || schedules[0].NextRunAt != 150 {
+		t.Errorf("Unexpected schedules %+v", schedules)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestUpdateDigestRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	mock.ExpectExec("update digest_schedule set last_sent_at = \\?, next_run_at = \\? where user_id = \\?").
+		WithArgs(int64(150), int64(300), userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = udb.UpdateDigestRun(context.Background(), userId, 150, 300)
+	if err != nil {
+		t.Fatal("Error updating the digest run:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
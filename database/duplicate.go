@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type DuplicateDatabaseInterface interface {
+	AddDuplicate(ctx context.Context, dm *domain.DuplicateModel) error
+	GetDuplicate(duplicateId uuid.UUID) (domain.DuplicateModel, error)
+	GetDuplicatesByStatus(userId uuid.UUID, status domain.DuplicateStatus) ([]domain.DuplicateModel, error)
+	UpdateDuplicateStatus(ctx context.Context, duplicateId uuid.UUID, status domain.DuplicateStatus) error
+}
+
+func (db *SQLManager) AddDuplicate(ctx context.Context, dm *domain.DuplicateModel) error {
+	stmt := `insert into duplicate_model (duplicate_id, user_id, transaction_id, candidate_id, score, reason, status, created_at) values (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("duplicate_model", "duplicate_id = ?", dm.DuplicateId), stmt, dm.DuplicateId, dm.UserId, dm.TransactionId, dm.CandidateId, dm.Score, dm.Reason, dm.Status, dm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the duplicate to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetDuplicate(duplicateId uuid.UUID) (domain.DuplicateModel, error) {
+	stmt := `select duplicate_id, user_id, transaction_id, candidate_id, score, reason, status, created_at from duplicate_model where duplicate_id = ?`
+	var dm domain.DuplicateModel
+	err := db.DB.QueryRow(stmt, duplicateId).Scan(&dm.DuplicateId, &dm.UserId, &dm.TransactionId, &dm.CandidateId, &dm.Score, &dm.Reason, &dm.Status, &dm.CreatedAt)
+	if err != nil {
+		log.Println("Error retrieving duplicate:", err)
+		return dm, err
+	}
+	return dm, nil
+}
+
+func (db *SQLManager) GetDuplicatesByStatus(userId uuid.UUID, status domain.DuplicateStatus) ([]domain.DuplicateModel, error) {
+	stmt := `select duplicate_id, user_id, transaction_id, candidate_id, score, reason, status, created_at from duplicate_model where user_id = ? and status = ? order by created_at`
+	rows, err := db.DB.Query(stmt, userId, status)
+	if err != nil {
+		log.Println("Error retrieving duplicates:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var duplicates []domain.DuplicateModel
+	for rows.Next() {
+		var dm domain.DuplicateModel
+		err := rows.Scan(&dm.DuplicateId, &dm.UserId, &dm.TransactionId, &dm.CandidateId, &dm.Score, &dm.Reason, &dm.Status, &dm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading duplicate row:", err)
+			return nil, err
+		}
+		duplicates = append(duplicates, dm)
+	}
+	return duplicates, rows.Err()
+}
+
+func (db *SQLManager) UpdateDuplicateStatus(ctx context.Context, duplicateId uuid.UUID, status domain.DuplicateStatus) error {
+	stmt := `update duplicate_model set status = ? where duplicate_id = ?`
+	_, err := db.exec(ctx, rowsOf("duplicate_model", "duplicate_id = ?", duplicateId), stmt, status, duplicateId)
+	if err != nil {
+		log.Println("Error updating duplicate status:", err)
+		return err
+	}
+	return nil
+}
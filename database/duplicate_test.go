@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddDuplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	dm := domain.DuplicateModelBuilder().Build()
+	mock.ExpectExec("insert into duplicate_model").
+		WithArgs(dm.DuplicateId, dm.UserId, dm.TransactionId, dm.CandidateId, dm.Score, dm.Reason, dm.Status, dm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddDuplicate(context.Background(), &dm)
+	if err != nil {
+		t.Fatal("Error saving duplicate:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetDuplicatesByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	dm := domain.DuplicateModelBuilder().Build()
+	rows := sqlmock.NewRows([]string{"duplicate_id", "user_id", "transaction_id", "candidate_id", "score", "reason", "status", "created_at"}).
+		AddRow(dm.DuplicateId, dm.UserId, dm.TransactionId, dm.CandidateId, dm.Score, dm.Reason, dm.Status, dm.CreatedAt)
+	mock.ExpectQuery("select (.+) from duplicate_model where user_id = \\? and status = \\?").
+		WithArgs(dm.UserId, domain.SUSPECTED).
+		WillReturnRows(rows)
+
+	duplicates, err := udb.GetDuplicatesByStatus(dm.UserId, domain.SUSPECTED)
+	if err != nil {
+		t.Fatal("Error retrieving duplicates:", err)
+	}
+	if len(duplicates) != 1 || duplicates[0] != dm {
+		t.Fatalf("Retrieved duplicates do not match expected, got %v, want %v", duplicates, dm)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUpdateDuplicateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	dm := domain.DuplicateModelBuilder().Build()
+	mock.ExpectExec("update duplicate_model set status = \\? where duplicate_id = \\?").
+		WithArgs(domain.DISMISSED, dm.DuplicateId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = udb.UpdateDuplicateStatus(context.Background(), dm.DuplicateId, domain.DISMISSED)
+	if err != nil {
+		t.Fatal("Error updating duplicate status:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type GoalDatabaseInterface interface {
+	AddGoal(ctx context.Context, gm *domain.GoalModel) error
+	GetGoal(goalId uuid.UUID) (domain.GoalModel, error)
+	GetGoalsByUserId(userId uuid.UUID) ([]domain.GoalModel, error)
+	DeleteGoal(ctx context.Context, goalId uuid.UUID) error
+	GetGoalContributions(gm *domain.GoalModel, from int64, to int64) (float64, error)
+}
+
+const goalColumns = `goal_id, user_id, name, target_amount, target_date, account_id, category_id, created_at`
+
+func (db *SQLManager) AddGoal(ctx context.Context, gm *domain.GoalModel) error {
+	stmt := `insert into goal_model (` + goalColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("goal_model", "goal_id = ?", gm.GoalId), stmt, gm.GoalId, gm.UserId, gm.Name, gm.TargetAmount, gm.TargetDate, gm.AccountId, gm.CategoryId, gm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the goal to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetGoal(goalId uuid.UUID) (domain.GoalModel, error) {
+	stmt := `select ` + goalColumns + ` from goal_model where goal_id = ?`
+	goal, err := scanGoal(db.DB.QueryRow(stmt, goalId))
+	if err != nil {
+		log.Println("Error retrieving goal:", err)
+		return goal, err
+	}
+	return goal, nil
+}
+
+func (db *SQLManager) GetGoalsByUserId(userId uuid.UUID) ([]domain.GoalModel, error) {
+	stmt := `select ` + goalColumns + ` from goal_model where user_id = ? order by target_date`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving goals:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []domain.GoalModel
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			log.Println("Error reading goal row:", err)
+			return nil, err
+		}
+		goals = append(goals, goal)
+	}
+	return goals, rows.Err()
+}
+
+func (db *SQLManager) DeleteGoal(ctx context.Context, goalId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("goal_model", "goal_id = ?", goalId), `delete from goal_model where goal_id = ?`, goalId)
+	if err != nil {
+		log.Println("Error deleting goal:", err)
+		return err
+	}
+	return nil
+}
+
+// GetGoalContributions is the net amount saved toward the goal between from and to, inclusive.
+// For an account that is income minus expenses, for a category expenses minus income.
+// Cancelled transactions are excluded.
+func (db *SQLManager) GetGoalContributions(gm *domain.GoalModel, from int64, to int64) (float64, error) {
+	column, id, saving := "account_id", gm.AccountId, domain.INCOME
+	if gm.AccountId == 0 {
+		column, id, saving = "category_id", gm.CategoryId, domain.EXPENSE
+	}
+
+	stmt := `select coalesce(sum(case when type = ? then amount else -amount end), 0) from transaction_model
+		where user_id = ? and ` + column + ` = ? and date >= ? and date <= ? and status != ?`
+	var contributions float64
+	err := db.DB.QueryRow(stmt, saving, gm.UserId, id, from, to, domain.CANCELLED).Scan(&contributions)
+	if err != nil {
+		log.Println("Error retrieving goal contributions:", err)
+		return 0, err
+	}
+	return contributions, nil
+}
+
+func scanGoal(row rowScanner) (domain.GoalModel, error) {
+	var gm domain.GoalModel
+	err := row.Scan(&gm.GoalId, &gm.UserId, &gm.Name, &gm.TargetAmount, &gm.TargetDate, &gm.AccountId, &gm.CategoryId, &gm.CreatedAt)
+	return gm, err
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestGetGoalContributions(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name       string
+		goal       domain.GoalModel
+		wantColumn string
+		wantId     int64
+		wantSaving domain.TransactionType
+	}{
+		{name: "Account", goal: domain.GoalModel{UserId: userId, AccountId: 9}, wantColumn: "account_id", wantId: 9, wantSaving: domain.INCOME},
+		{name: "Category", goal: domain.GoalModel{UserId: userId, CategoryId: 7}, wantColumn: "category_id", wantId: 7, wantSaving: domain.EXPENSE},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("Error creating SQL stub", err)
+			}
+			defer db.Close()
+			udb := SQLManager{DB: db}
+
+			mock.ExpectQuery("select coalesce(.+) from transaction_model\\s+where user_id = \\? and "+test.wantColumn+" = \\?").
+				WithArgs(test.wantSaving, userId, test.wantId, 10, 20, domain.CANCELLED).
+				WillReturnRows(sqlmock.NewRows([]string{"contributions"}).AddRow(350))
+
+			contributions, err := udb.GetGoalContributions(&test.goal, 10, 20)
+			if err != nil || contributions != 350 {
+				t.Fatalf("Wrong contributions, got %v, err: %v", contributions, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal("Expectations were not met:", err)
+			}
+		})
+	}
+}
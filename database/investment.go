@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type InvestmentDatabaseInterface interface {
+	AddSecurity(ctx context.Context, sm *domain.SecurityModel) error
+	GetSecuritiesByUserId(userId uuid.UUID) ([]domain.SecurityModel, error)
+	AddInvestmentTransaction(ctx context.Context, im *domain.InvestmentTransactionModel) error
+	GetInvestmentTransactions(userId uuid.UUID, to int64) ([]domain.InvestmentTransactionModel, error)
+	SaveSecurityPrices(prices []domain.SecurityPriceModel) error
+	GetSecurityPrices(symbol string, to string) ([]domain.SecurityPriceModel, error)
+}
+
+const investmentColumns = `investment_id, user_id, account_id, security_id, kind, date, quantity, price, fees, amount, split_ratio, method, lots, created_at`
+
+func (db *SQLManager) AddSecurity(ctx context.Context, sm *domain.SecurityModel) error {
+	stmt := `insert into security_model (security_id, user_id, symbol, name, created_at) values (?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("security_model", "security_id = ?", sm.SecurityId), stmt, sm.SecurityId, sm.UserId, sm.Symbol, sm.Name, sm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the security to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetSecuritiesByUserId(userId uuid.UUID) ([]domain.SecurityModel, error) {
+	stmt := `select security_id, user_id, symbol, name, created_at from security_model where user_id = ? order by symbol`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving securities:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var securities []domain.SecurityModel
+	for rows.Next() {
+		var sm domain.SecurityModel
+		err := rows.Scan(&sm.SecurityId, &sm.UserId, &sm.Symbol, &sm.Name, &sm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading security row:", err)
+			return nil, err
+		}
+		securities = append(securities, sm)
+	}
+	return securities, rows.Err()
+}
+
+func (db *SQLManager) AddInvestmentTransaction(ctx context.Context, im *domain.InvestmentTransactionModel) error {
+	lots, err := json.Marshal(im.Lots)
+	if err != nil {
+		return err
+	}
+
+	stmt := `insert into investment_transaction (` + investmentColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.exec(ctx, rowsOf("investment_transaction", "investment_id = ?", im.InvestmentId), stmt, im.InvestmentId, im.UserId, im.AccountId, im.SecurityId, im.Kind, im.Date, im.Quantity, im.Price, im.Fees, im.Amount, im.SplitRatio, im.Method, string(lots), im.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the investment transaction to the database:", err)
+		return err
+	}
+	return nil
+}
+
+// GetInvestmentTransactions returns the users investment transactions up to to, in the order
+// they happened.
+func (db *SQLManager) GetInvestmentTransactions(userId uuid.UUID, to int64) ([]domain.InvestmentTransactionModel, error) {
+	stmt := `select ` + investmentColumns + ` from investment_transaction where user_id = ? and date <= ? order by date, created_at`
+	rows, err := db.DB.Query(stmt, userId, to)
+	if err != nil {
+		log.Println("Error retrieving investment transactions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var investments []domain.InvestmentTransactionModel
+	for rows.Next() {
+		var im domain.InvestmentTransactionModel
+		var lots string
+		err := rows.Scan(&im.InvestmentId, &im.UserId, &im.AccountId, &im.SecurityId, &im.Kind, &im.Date, &im.Quantity, &im.Price, &im.Fees, &im.Amount, &im.SplitRatio, &im.Method, &lots, &im.CreatedAt)
+		if err != nil {
+			log.Println("Error reading investment transaction row:", err)
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(lots), &im.Lots)
+		if err != nil {
+			log.Println("Error reading investment transaction lots:", err)
+			return nil, err
+		}
+		investments = append(investments, im)
+	}
+	return investments, rows.Err()
+}
+
+// SaveSecurityPrices replaces the prices already saved for the same symbol and day, so the same
+// file can be imported again.
+func (db *SQLManager) SaveSecurityPrices(prices []domain.SecurityPriceModel) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, price := range prices {
+		_, err = tx.Exec(`delete from security_price where symbol = ? and date = ?`, price.Symbol, price.Date)
+		if err != nil {
+			log.Println("Error replacing the security price:", err)
+			return err
+		}
+		_, err = tx.Exec(`insert into security_price (symbol, date, price) values (?, ?, ?)`, price.Symbol, price.Date, price.Price)
+		if err != nil {
+			log.Println("Error saving the security price to the database:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetSecurityPrices returns the price history of the symbol up to the day to, oldest first.
+func (db *SQLManager) GetSecurityPrices(symbol string, to string) ([]domain.SecurityPriceModel, error) {
+	stmt := `select symbol, date, price from security_price where symbol = ? and date <= ? order by date`
+	rows, err := db.DB.Query(stmt, symbol, to)
+	if err != nil {
+		log.Println("Error retrieving security prices:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []domain.SecurityPriceModel
+	for rows.Next() {
+		var pm domain.SecurityPriceModel
+		err := rows.Scan(&pm.Symbol, &pm.Date, &pm.Price)
+		if err != nil {
+			log.Println("Error reading security price row:", err)
+			return nil, err
+		}
+		prices = append(prices, pm)
+	}
+	return prices, rows.Err()
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddInvestmentTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	lotId := uuid.New()
+	im := domain.InvestmentTransactionModel{InvestmentId: uuid.New(), UserId: uuid.New(), AccountId: 2, SecurityId: uuid.New(), Kind: domain.INVESTMENT_SELL, Date: 10, Quantity: 3, Price: 50, Method: domain.SPECIFIC_ID, Lots: []domain.LotSelectionModel{{LotId: lotId, Quantity: 3}}, CreatedAt: 11}
+	mock.ExpectExec("insert into investment_transaction").
+		WithArgs(im.InvestmentId, im.UserId, im.AccountId, im.SecurityId, im.Kind, im.Date, im.Quantity, im.Price, im.Fees, im.Amount, im.SplitRatio, im.Method, `[{"LotId":"`+lotId.String()+`","Quantity":3}]`, im.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddInvestmentTransaction(context.Background(), &im)
+	if err != nil {
+		t.Fatal("Error saving the investment transaction:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetInvestmentTransactions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	lotId := uuid.New()
+	rows := sqlmock.NewRows([]string{"investment_id", "user_id", "account_id", "security_id", "kind", "date", "quantity", "price", "fees", "amount", "split_ratio", "method", "lots", "created_at"}).
+		AddRow(uuid.New(), userId, 2, uuid.New(), domain.INVESTMENT_SELL, 10, 3.0, 50.0, 1.0, 0.0, 0.0, domain.SPECIFIC_ID, `[{"LotId":"`+lotId.String()+`","Quantity":3}]`, 11)
+	mock.ExpectQuery("select (.+) from investment_transaction where user_id = \\? and date <= \\? order by date, created_at").
+		WithArgs(userId, int64(100)).
+		WillReturnRows(rows)
+
+	investments, err := udb.GetInvestmentTransactions(userId, 100)
+	if err != nil {
+		t.Fatal("Error retrieving the investment transactions:", err)
+	}
+	if len(investments) != 1 || investments[0].Method != domain.SPECIFIC_ID || len(investments[0].Lots) != 1 || investments[0].Lots[0].LotId != lotId {
+		t.Errorf("Unexpected investment transactions %+v", investments)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestSaveSecurityPrices(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	price := domain.SecurityPriceModel{Symbol: "VWCE", Date: "2026-03-02", Price: 112.5}
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from security_price where symbol = \\? and date = \\?").
+		WithArgs(price.Symbol, price.Date).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("insert into security_price").
+		WithArgs(price.Symbol, price.Date, price.Price).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.SaveSecurityPrices([]domain.SecurityPriceModel{price})
+	if err != nil {
+		t.Fatal("Error saving the prices:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
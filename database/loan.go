@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type LoanDatabaseInterface interface {
+	AddLoan(ctx context.Context, lm *domain.LoanModel) error
+	GetLoan(loanId uuid.UUID) (domain.LoanModel, error)
+	GetLoansByUserId(userId uuid.UUID) ([]domain.LoanModel, error)
+	DeleteLoan(ctx context.Context, loanId uuid.UUID) error
+}
+
+const loanColumns = `loan_id, user_id, name, principal, annual_rate, term_months, start_date, account_id, payee_id, match_text, extra_monthly, extra_payments, created_at`
+
+func (db *SQLManager) AddLoan(ctx context.Context, lm *domain.LoanModel) error {
+	extraPayments, err := json.Marshal(lm.ExtraPayments)
+	if err != nil {
+		return err
+	}
+
+	stmt := `insert into loan_model (` + loanColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.exec(ctx, rowsOf("loan_model", "loan_id = ?", lm.LoanId), stmt, lm.LoanId, lm.UserId, lm.Name, lm.Principal, lm.AnnualRate, lm.TermMonths, lm.StartDate, lm.AccountId, lm.PayeeId, lm.Match, lm.ExtraMonthly, string(extraPayments), lm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the loan to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetLoan(loanId uuid.UUID) (domain.LoanModel, error) {
+	stmt := `select ` + loanColumns + ` from loan_model where loan_id = ?`
+	lm, err := scanLoan(db.DB.QueryRow(stmt, loanId))
+	if err != nil {
+		log.Println("Error retrieving loan:", err)
+		return lm, err
+	}
+	return lm, nil
+}
+
+func (db *SQLManager) GetLoansByUserId(userId uuid.UUID) ([]domain.LoanModel, error) {
+	stmt := `select ` + loanColumns + ` from loan_model where user_id = ? order by created_at`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving loans:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []domain.LoanModel
+	for rows.Next() {
+		lm, err := scanLoan(rows)
+		if err != nil {
+			log.Println("Error reading loan row:", err)
+			return nil, err
+		}
+		loans = append(loans, lm)
+	}
+	return loans, rows.Err()
+}
+
+func (db *SQLManager) DeleteLoan(ctx context.Context, loanId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("loan_model", "loan_id = ?", loanId), `delete from loan_model where loan_id = ?`, loanId)
+	if err != nil {
+		log.Println("Error deleting loan:", err)
+		return err
+	}
+	return nil
+}
+
+func scanLoan(row rowScanner) (domain.LoanModel, error) {
+	var lm domain.LoanModel
+	var extraPayments string
+	err := row.Scan(&lm.LoanId, &lm.UserId, &lm.Name, &lm.Principal, &lm.AnnualRate, &lm.TermMonths, &lm.StartDate, &lm.AccountId, &lm.PayeeId, &lm.Match, &lm.ExtraMonthly, &extraPayments, &lm.CreatedAt)
+	if err != nil {
+		return lm, err
+	}
+	err = json.Unmarshal([]byte(extraPayments), &lm.ExtraPayments)
+	return lm, err
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddLoan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	lm := domain.LoanModel{LoanId: uuid.New(), UserId: uuid.New(), Name: "Mortgage", Principal: 200000, AnnualRate: 6, TermMonths: 360, StartDate: 10, Match: "home mortgage", ExtraPayments: []domain.ExtraPaymentModel{{Date: 20, Amount: 5000}}, CreatedAt: 11}
+	mock.ExpectExec("insert into loan_model").
+		WithArgs(lm.LoanId, lm.UserId, lm.Name, lm.Principal, lm.AnnualRate, lm.TermMonths, lm.StartDate, lm.AccountId, lm.PayeeId, lm.Match, lm.ExtraMonthly, `[{"Date":20,"Amount":5000}]`, lm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddLoan(context.Background(), &lm)
+	if err != nil {
+		t.Fatal("Error saving the loan:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetLoansByUserId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	rows := sqlmock.NewRows([]string{"loan_id", "user_id", "name", "principal", "annual_rate", "term_months", "start_date", "account_id", "payee_id", "match_text", "extra_monthly", "extra_payments", "created_at"}).
+		AddRow(uuid.New(), userId, "Mortgage", 200000.0, 6.0, 360, 10, 2, uuid.Nil, "home mortgage", 100.0, `[{"Date":20,"Amount":5000}]`, 11)
+	mock.ExpectQuery("select (.+) from loan_model where user_id = \\? order by created_at").
+		WithArgs(userId).
+		WillReturnRows(rows)
+
+	loans, err := udb.GetLoansByUserId(userId)
+	if err != nil {
+		t.Fatal("Error retrieving the loans:", err)
+	}
+	if len(loans) != 1 || loans[0].TermMonths != 360 || loans[0].ExtraMonthly != 100 || len(loans[0].ExtraPayments) != 1 || loans[0].ExtraPayments[0].Amount != 5000 {
+		t.Errorf("Unexpected loans %+v", loans)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+	"time"
+)
+
+// migrationFiles are the changes to the MySQL schema, applied in the order of their file names.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrate applies the migrations the database has not had yet and records each in
+// schema_migration. MySQL commits every schema change as it is made, so a migration that fails
+// halfway has to be finished by hand before starting again.
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`create table if not exists schema_migration (version varchar(255) not null primary key, applied_at bigint not null)`)
+	if err != nil {
+		log.Println("Error creating the migration table:", err)
+		return err
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		version := strings.TrimSuffix(path.Base(name), ".sql")
+		if applied[version] {
+			continue
+		}
+
+		content, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range splitStatements(string(content)) {
+			_, err = db.Exec(stmt)
+			if err != nil {
+				log.Printf("Error applying migration %s: %v\n", version, err)
+				return err
+			}
+		}
+
+		_, err = db.Exec(`insert into schema_migration (version, applied_at) values (?, ?)`, version, time.Now().UnixMilli())
+		if err != nil {
+			log.Println("Error recording the migration:", err)
+			return err
+		}
+		log.Println("Applied migration", version)
+	}
+	return nil
+}
+
+func appliedMigrations(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query(`select version from schema_migration`)
+	if err != nil {
+		log.Println("Error retrieving the applied migrations:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var version string
+		err = rows.Scan(&version)
+		if err != nil {
+			log.Println("Error reading migration row:", err)
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// splitStatements splits a migration into its statements, which end with a semicolon. Lines
+// starting with -- are comments.
+func splitStatements(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var stmts []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"io/fs"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMigrate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil || len(names) < 2 {
+		t.Fatalf("Expected the migrations to be embedded, got %v, err: %v", names, err)
+	}
+	// every migration but the last has been applied.
+	applied := sqlmock.NewRows([]string{"version"})
+	for _, name := range names[:len(names)-1] {
+		applied.AddRow(strings.TrimSuffix(path.Base(name), ".sql"))
+	}
+	last := names[len(names)-1]
+	content, err := migrationFiles.ReadFile(last)
+	if err != nil {
+		t.Fatal("Error reading the migration:", err)
+	}
+
+	mock.ExpectExec("create table if not exists schema_migration").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("select version from schema_migration").WillReturnRows(applied)
+	for _, stmt := range splitStatements(string(content)) {
+		mock.ExpectExec(regexp.QuoteMeta(stmt)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec("insert into schema_migration").
+		WithArgs(strings.TrimSuffix(path.Base(last), ".sql"), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = Migrate(db)
+	if err != nil {
+		t.Fatal("Error migrating:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	content := `-- a comment; with a semicolon
+create table a (
+	id bigint not null
+);
+
+alter table a add column b int not null default 0;
+`
+	want := []string{"create table a (\n\tid bigint not null\n)", "alter table a add column b int not null default 0"}
+	if got := splitStatements(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected statements %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+-- The user and transaction tables as they were before migrations, created only for new databases.
+create table if not exists user_model (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	first_name varchar(255) not null,
+	last_name varchar(255) not null,
+	email varchar(255) not null,
+	phone varchar(64) not null,
+	password_hash varchar(255) not null,
+	date_of_birth bigint not null,
+	creation_date bigint not null,
+	unique key user_model_user_id (user_id),
+	unique key user_model_email (email)
+);
+
+create table if not exists transaction_model (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	transaction_id char(36) not null,
+	category_id bigint not null,
+	amount double not null,
+	date bigint not null,
+	description varchar(255) not null,
+	created_at bigint not null,
+	updated_at bigint not null,
+	type int not null,
+	payment_method int not null,
+	status int not null,
+	unique key transaction_model_transaction_id (transaction_id),
+	key transaction_model_user_date (user_id, date)
+);
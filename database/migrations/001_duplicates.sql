@@ -0,0 +1,18 @@
+-- Transactions belong to an account, and likely duplicates are flagged in pairs.
+alter table transaction_model add column account_id bigint not null default 0 after category_id;
+
+create table duplicate_model (
+	id bigint not null auto_increment primary key,
+	duplicate_id char(36) not null,
+	user_id char(36) not null,
+	transaction_id char(36) not null,
+	candidate_id char(36) not null,
+	score double not null,
+	reason varchar(255) not null,
+	status int not null,
+	created_at bigint not null,
+	unique key duplicate_model_duplicate_id (duplicate_id),
+	key duplicate_model_user_status (user_id, status),
+	key duplicate_model_transaction_id (transaction_id),
+	key duplicate_model_candidate_id (candidate_id)
+);
@@ -0,0 +1,13 @@
+-- Categorization rules, their conditions and actions are JSON.
+create table rule_model (
+	id bigint not null auto_increment primary key,
+	rule_id char(36) not null,
+	user_id char(36) not null,
+	name varchar(255) not null,
+	priority int not null,
+	conditions text not null,
+	actions text not null,
+	created_at bigint not null,
+	unique key rule_model_rule_id (rule_id),
+	key rule_model_user_priority (user_id, priority)
+);
@@ -0,0 +1,8 @@
+-- The naive Bayes category model of each user, as JSON.
+create table classifier_model (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	model mediumtext not null,
+	updated_at bigint not null,
+	unique key classifier_model_user_id (user_id)
+);
@@ -0,0 +1,14 @@
+-- Payees normalized from transaction descriptions, their aliases are JSON.
+alter table transaction_model add column payee_id char(36) not null default '00000000-0000-0000-0000-000000000000' after account_id;
+create index transaction_model_payee_id on transaction_model (payee_id);
+
+create table payee_model (
+	id bigint not null auto_increment primary key,
+	payee_id char(36) not null,
+	user_id char(36) not null,
+	name varchar(255) not null,
+	aliases text not null,
+	created_at bigint not null,
+	unique key payee_model_payee_id (payee_id),
+	key payee_model_user_id (user_id)
+);
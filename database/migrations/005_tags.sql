@@ -0,0 +1,17 @@
+-- Free-form tags and the transactions they are on.
+create table tag_model (
+	id bigint not null auto_increment primary key,
+	tag_id char(36) not null,
+	user_id char(36) not null,
+	name varchar(255) not null,
+	created_at bigint not null,
+	unique key tag_model_tag_id (tag_id),
+	unique key tag_model_user_name (user_id, name)
+);
+
+create table transaction_tag (
+	transaction_id char(36) not null,
+	tag_id char(36) not null,
+	primary key (transaction_id, tag_id),
+	key transaction_tag_tag_id (tag_id)
+);
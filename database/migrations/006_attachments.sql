@@ -0,0 +1,15 @@
+-- Receipts and documents on transactions, the files themselves are kept in the attachment storage.
+create table attachment_model (
+	id bigint not null auto_increment primary key,
+	attachment_id char(36) not null,
+	transaction_id char(36) not null,
+	user_id char(36) not null,
+	file_name varchar(255) not null,
+	content_type varchar(255) not null,
+	size bigint not null,
+	checksum char(64) not null,
+	created_at bigint not null,
+	unique key attachment_model_attachment_id (attachment_id),
+	key attachment_model_transaction_id (transaction_id),
+	key attachment_model_checksum (checksum)
+);
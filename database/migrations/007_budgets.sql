@@ -0,0 +1,13 @@
+-- Monthly envelope budgets, one per category and month.
+create table budget_model (
+	id bigint not null auto_increment primary key,
+	budget_id char(36) not null,
+	user_id char(36) not null,
+	category_id bigint not null,
+	month char(7) not null,
+	amount double not null,
+	rollover int not null,
+	created_at bigint not null,
+	unique key budget_model_budget_id (budget_id),
+	unique key budget_model_user_category_month (user_id, category_id, month)
+);
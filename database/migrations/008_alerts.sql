@@ -0,0 +1,32 @@
+-- Alert rules and the alerts they raised, channels is JSON.
+create table alert_rule_model (
+	id bigint not null auto_increment primary key,
+	alert_rule_id char(36) not null,
+	user_id char(36) not null,
+	type int not null,
+	category_id bigint not null,
+	account_id bigint not null,
+	percent double not null,
+	balance double not null,
+	channels text not null,
+	webhook_url varchar(2048) not null,
+	created_at bigint not null,
+	unique key alert_rule_model_alert_rule_id (alert_rule_id),
+	key alert_rule_model_user_id (user_id)
+);
+
+create table alert_model (
+	id bigint not null auto_increment primary key,
+	alert_id char(36) not null,
+	alert_rule_id char(36) not null,
+	user_id char(36) not null,
+	period varchar(64) not null,
+	category_id bigint not null,
+	account_id bigint not null,
+	message varchar(1024) not null,
+	is_read boolean not null,
+	created_at bigint not null,
+	unique key alert_model_alert_id (alert_id),
+	unique key alert_model_rule_period (alert_rule_id, period, category_id, account_id),
+	key alert_model_user_id (user_id)
+);
@@ -0,0 +1,14 @@
+-- Savings goals.
+create table goal_model (
+	id bigint not null auto_increment primary key,
+	goal_id char(36) not null,
+	user_id char(36) not null,
+	name varchar(255) not null,
+	target_amount double not null,
+	target_date bigint not null,
+	account_id bigint not null,
+	category_id bigint not null,
+	created_at bigint not null,
+	unique key goal_model_goal_id (goal_id),
+	key goal_model_user_id (user_id)
+);
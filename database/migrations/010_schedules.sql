@@ -0,0 +1,15 @@
+-- Scheduled income and expenses for the cash flow forecast.
+create table scheduled_item_model (
+	id bigint not null auto_increment primary key,
+	scheduled_item_id char(36) not null,
+	user_id char(36) not null,
+	account_id bigint not null,
+	description varchar(255) not null,
+	amount double not null,
+	type int not null,
+	frequency int not null,
+	start_date bigint not null,
+	created_at bigint not null,
+	unique key scheduled_item_model_scheduled_item_id (scheduled_item_id),
+	key scheduled_item_model_user_id (user_id)
+);
@@ -0,0 +1,24 @@
+-- Valuations of assets and liabilities outside the accounts, and the daily net worth history.
+create table valuation_model (
+	id bigint not null auto_increment primary key,
+	valuation_id char(36) not null,
+	user_id char(36) not null,
+	name varchar(255) not null,
+	kind int not null,
+	amount double not null,
+	date bigint not null,
+	created_at bigint not null,
+	unique key valuation_model_valuation_id (valuation_id),
+	key valuation_model_user_date (user_id, date)
+);
+
+create table net_worth_snapshot (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	date bigint not null,
+	assets double not null,
+	liabilities double not null,
+	net_worth double not null,
+	created_at bigint not null,
+	unique key net_worth_snapshot_user_date (user_id, date)
+);
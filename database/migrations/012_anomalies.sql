@@ -0,0 +1,18 @@
+-- Spending anomalies, at most one per kind, transaction, category and month.
+create table anomaly_model (
+	id bigint not null auto_increment primary key,
+	anomaly_id char(36) not null,
+	user_id char(36) not null,
+	kind int not null,
+	transaction_id char(36) not null,
+	category_id bigint not null,
+	payee_id char(36) not null,
+	month char(7) not null,
+	amount double not null,
+	expected double not null,
+	score double not null,
+	message varchar(1024) not null,
+	created_at bigint not null,
+	unique key anomaly_model_anomaly_id (anomaly_id),
+	key anomaly_model_user_month (user_id, month)
+);
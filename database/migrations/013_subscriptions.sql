@@ -0,0 +1,10 @@
+-- Confirmed and dismissed recurring charges.
+create table subscription_decision (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	subscription_key varchar(255) not null,
+	status int not null,
+	scheduled_item_id char(36) not null,
+	created_at bigint not null,
+	unique key subscription_decision_user_key (user_id, subscription_key)
+);
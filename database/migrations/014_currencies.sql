@@ -0,0 +1,27 @@
+-- Transactions keep the amount as entered next to the amount in the base currency.
+alter table transaction_model add column currency char(3) not null default '';
+alter table transaction_model add column original_amount double not null default 0;
+update transaction_model set original_amount = amount;
+
+create table exchange_rate (
+	id bigint not null auto_increment primary key,
+	currency char(3) not null,
+	date char(10) not null,
+	rate double not null,
+	unique key exchange_rate_currency_date (currency, date)
+);
+
+create table base_currency (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	currency char(3) not null,
+	unique key base_currency_user_id (user_id)
+);
+
+create table account_currency (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	account_id bigint not null,
+	currency char(3) not null,
+	unique key account_currency_user_account (user_id, account_id)
+);
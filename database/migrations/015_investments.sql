@@ -0,0 +1,39 @@
+-- Securities, investment transactions with their lots as JSON, and imported prices.
+create table security_model (
+	id bigint not null auto_increment primary key,
+	security_id char(36) not null,
+	user_id char(36) not null,
+	symbol varchar(32) not null,
+	name varchar(255) not null,
+	created_at bigint not null,
+	unique key security_model_security_id (security_id),
+	key security_model_user_symbol (user_id, symbol)
+);
+
+create table investment_transaction (
+	id bigint not null auto_increment primary key,
+	investment_id char(36) not null,
+	user_id char(36) not null,
+	account_id bigint not null,
+	security_id char(36) not null,
+	kind int not null,
+	date bigint not null,
+	quantity double not null,
+	price double not null,
+	fees double not null,
+	amount double not null,
+	split_ratio double not null,
+	method int not null,
+	lots text not null,
+	created_at bigint not null,
+	unique key investment_transaction_investment_id (investment_id),
+	key investment_transaction_user_date (user_id, date)
+);
+
+create table security_price (
+	id bigint not null auto_increment primary key,
+	symbol varchar(32) not null,
+	date char(10) not null,
+	price double not null,
+	unique key security_price_symbol_date (symbol, date)
+);
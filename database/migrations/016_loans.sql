@@ -0,0 +1,19 @@
+-- Loans, their extra payments are JSON.
+create table loan_model (
+	id bigint not null auto_increment primary key,
+	loan_id char(36) not null,
+	user_id char(36) not null,
+	name varchar(255) not null,
+	principal double not null,
+	annual_rate double not null,
+	term_months int not null,
+	start_date bigint not null,
+	account_id bigint not null,
+	payee_id char(36) not null,
+	match_text varchar(255) not null,
+	extra_monthly double not null,
+	extra_payments text not null,
+	created_at bigint not null,
+	unique key loan_model_loan_id (loan_id),
+	key loan_model_user_id (user_id)
+);
@@ -0,0 +1,15 @@
+-- Credit card accounts and their statement cycles.
+create table card_account (
+	id bigint not null auto_increment primary key,
+	card_id char(36) not null,
+	user_id char(36) not null,
+	account_id bigint not null,
+	name varchar(255) not null,
+	closing_day int not null,
+	due_day int not null,
+	minimum_percent double not null,
+	minimum_amount double not null,
+	created_at bigint not null,
+	unique key card_account_card_id (card_id),
+	key card_account_user_id (user_id)
+);
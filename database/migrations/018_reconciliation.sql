@@ -0,0 +1,22 @@
+-- Reconciliation sessions and the transactions cleared in them.
+create table reconciliation_session (
+	id bigint not null auto_increment primary key,
+	reconciliation_id char(36) not null,
+	user_id char(36) not null,
+	account_id bigint not null,
+	statement_date bigint not null,
+	starting_balance double not null,
+	ending_balance double not null,
+	status int not null,
+	created_at bigint not null,
+	reconciled_at bigint not null,
+	unique key reconciliation_session_reconciliation_id (reconciliation_id),
+	key reconciliation_session_user_account (user_id, account_id)
+);
+
+create table reconciliation_transaction (
+	reconciliation_id char(36) not null,
+	transaction_id char(36) not null,
+	primary key (reconciliation_id, transaction_id),
+	key reconciliation_transaction_transaction_id (transaction_id)
+);
@@ -0,0 +1,12 @@
+-- Tax lines, the categories they cover are JSON.
+create table tax_line (
+	id bigint not null auto_increment primary key,
+	tax_line_id char(36) not null,
+	user_id char(36) not null,
+	name varchar(255) not null,
+	code varchar(64) not null,
+	category_ids text not null,
+	created_at bigint not null,
+	unique key tax_line_tax_line_id (tax_line_id),
+	key tax_line_user_id (user_id)
+);
@@ -0,0 +1,15 @@
+-- Spending digest schedules, one per user.
+create table digest_schedule (
+	id bigint not null auto_increment primary key,
+	user_id char(36) not null,
+	frequency int not null,
+	day int not null,
+	hour int not null,
+	minute int not null,
+	timezone varchar(64) not null,
+	next_run_at bigint not null,
+	last_sent_at bigint not null,
+	created_at bigint not null,
+	unique key digest_schedule_user_id (user_id),
+	key digest_schedule_next_run_at (next_run_at)
+);
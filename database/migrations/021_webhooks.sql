@@ -0,0 +1,31 @@
+-- Webhook subscriptions, their events are JSON, and the delivery log.
+create table webhook_subscription (
+	id bigint not null auto_increment primary key,
+	webhook_id char(36) not null,
+	user_id char(36) not null,
+	url varchar(2048) not null,
+	events text not null,
+	secret varchar(255) not null,
+	created_at bigint not null,
+	unique key webhook_subscription_webhook_id (webhook_id),
+	key webhook_subscription_user_id (user_id)
+);
+
+create table webhook_delivery (
+	id bigint not null auto_increment primary key,
+	delivery_id char(36) not null,
+	webhook_id char(36) not null,
+	user_id char(36) not null,
+	event varchar(64) not null,
+	payload mediumtext not null,
+	status int not null,
+	attempts int not null,
+	response_status int not null,
+	error text not null,
+	next_attempt_at bigint not null,
+	created_at bigint not null,
+	updated_at bigint not null,
+	unique key webhook_delivery_delivery_id (delivery_id),
+	key webhook_delivery_webhook_created (webhook_id, created_at),
+	key webhook_delivery_status_next_attempt (status, next_attempt_at)
+);
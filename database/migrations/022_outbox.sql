@@ -0,0 +1,23 @@
+-- Events saved with the changes they describe, and the subscribers that handled them.
+create table outbox_event (
+	id bigint not null auto_increment primary key,
+	event_id char(36) not null,
+	type varchar(64) not null,
+	user_id char(36) not null,
+	aggregate_id char(36) not null,
+	payload mediumtext not null,
+	attempts int not null,
+	next_attempt_at bigint not null,
+	last_error text not null,
+	created_at bigint not null,
+	published_at bigint not null,
+	unique key outbox_event_event_id (event_id),
+	key outbox_event_pending (published_at, next_attempt_at)
+);
+
+create table outbox_handled (
+	event_id char(36) not null,
+	subscriber varchar(64) not null,
+	handled_at bigint not null,
+	primary key (event_id, subscriber)
+);
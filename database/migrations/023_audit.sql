@@ -0,0 +1,27 @@
+-- The audit log of the changes to the data of the users, each entry chained to the one before it
+-- by its hash. user_id is the user the changed row belongs to.
+create table audit_log (
+	seq bigint not null primary key,
+	audit_id char(36) not null,
+	user_id char(36) not null,
+	actor varchar(64) not null,
+	action varchar(16) not null,
+	entity varchar(64) not null,
+	entity_id varchar(255) not null,
+	diff mediumtext not null,
+	request_id varchar(64) not null,
+	created_at bigint not null,
+	prev_hash char(64) not null,
+	hash char(64) not null,
+	unique key audit_log_audit_id (audit_id),
+	key audit_log_user_entity (user_id, entity, entity_id)
+);
+
+-- The end of the audit log in one row, locked by the writers appending to it.
+create table audit_chain (
+	id int not null primary key,
+	seq bigint not null,
+	hash char(64) not null
+);
+
+insert into audit_chain (id, seq, hash) values (1, 0, '');
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type NetWorthDatabaseInterface interface {
+	AddValuation(ctx context.Context, vm *domain.ValuationModel) error
+	GetValuationsByUserId(userId uuid.UUID) ([]domain.ValuationModel, error)
+	DeleteValuation(ctx context.Context, valuationId uuid.UUID) error
+	SaveNetWorthSnapshots(ctx context.Context, userId uuid.UUID, snapshots []domain.NetWorthSnapshotModel) error
+	GetNetWorthSnapshots(userId uuid.UUID, from int64, to int64) ([]domain.NetWorthSnapshotModel, error)
+	GetNetWorthUserIds() ([]uuid.UUID, error)
+}
+
+func (db *SQLManager) AddValuation(ctx context.Context, vm *domain.ValuationModel) error {
+	stmt := `insert into valuation_model (valuation_id, user_id, name, kind, amount, date, created_at) values (?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("valuation_model", "valuation_id = ?", vm.ValuationId), stmt, vm.ValuationId, vm.UserId, vm.Name, vm.Kind, vm.Amount, vm.Date, vm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the valuation to the database:", err)
+		return err
+	}
+	return nil
+}
+
+// GetValuationsByUserId returns the users valuations oldest first.
+func (db *SQLManager) GetValuationsByUserId(userId uuid.UUID) ([]domain.ValuationModel, error) {
+	stmt := `select valuation_id, user_id, name, kind, amount, date, created_at from valuation_model where user_id = ? order by date, created_at`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving valuations:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var valuations []domain.ValuationModel
+	for rows.Next() {
+		var vm domain.ValuationModel
+		err := rows.Scan(&vm.ValuationId, &vm.UserId, &vm.Name, &vm.Kind, &vm.Amount, &vm.Date, &vm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading valuation row:", err)
+			return nil, err
+		}
+		valuations = append(valuations, vm)
+	}
+	return valuations, rows.Err()
+}
+
+func (db *SQLManager) DeleteValuation(ctx context.Context, valuationId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("valuation_model", "valuation_id = ?", valuationId), `delete from valuation_model where valuation_id = ?`, valuationId)
+	if err != nil {
+		log.Println("Error deleting valuation:", err)
+		return err
+	}
+	return nil
+}
+
+// SaveNetWorthSnapshots replaces the users snapshots for the days given, which must be ordered by date.
+func (db *SQLManager) SaveNetWorthSnapshots(ctx context.Context, userId uuid.UUID, snapshots []domain.NetWorthSnapshotModel) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("net_worth_snapshot", "user_id = ? and date >= ? and date <= ?", userId, snapshots[0].Date, snapshots[len(snapshots)-1].Date))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`delete from net_worth_snapshot where user_id = ? and date >= ? and date <= ?`, userId, snapshots[0].Date, snapshots[len(snapshots)-1].Date)
+	if err != nil {
+		log.Println("Error replacing net worth snapshots:", err)
+		return err
+	}
+
+	stmt := `insert into net_worth_snapshot (user_id, date, assets, liabilities, net_worth, created_at) values (?, ?, ?, ?, ?, ?)`
+	for _, sm := range snapshots {
+		_, err = tx.Exec(stmt, userId, sm.Date, sm.Assets, sm.Liabilities, sm.NetWorth, sm.CreatedAt)
+		if err != nil {
+			log.Println("Error saving the net worth snapshot to the database:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetNetWorthSnapshots returns the users snapshots dated between from and to, inclusive, oldest first.
+func (db *SQLManager) GetNetWorthSnapshots(userId uuid.UUID, from int64, to int64) ([]domain.NetWorthSnapshotModel, error) {
+	stmt := `select user_id, date, assets, liabilities, net_worth, created_at from net_worth_snapshot where user_id = ? and date >= ? and date <= ? order by date`
+	rows, err := db.DB.Query(stmt, userId, from, to)
+	if err != nil {
+		log.Println("Error retrieving net worth snapshots:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []domain.NetWorthSnapshotModel
+	for rows.Next() {
+		var sm domain.NetWorthSnapshotModel
+		err := rows.Scan(&sm.UserId, &sm.Date, &sm.Assets, &sm.Liabilities, &sm.NetWorth, &sm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading net worth snapshot row:", err)
+			return nil, err
+		}
+		snapshots = append(snapshots, sm)
+	}
+	return snapshots, rows.Err()
+}
+
+// GetNetWorthUserIds returns every user with transactions or valuations to snapshot.
+func (db *SQLManager) GetNetWorthUserIds() ([]uuid.UUID, error) {
+	rows, err := db.DB.Query(`select user_id from transaction_model union select user_id from valuation_model`)
+	if err != nil {
+		log.Println("Error retrieving net worth users:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []uuid.UUID
+	for rows.Next() {
+		var userId uuid.UUID
+		if err := rows.Scan(&userId); err != nil {
+			log.Println("Error reading net worth user row:", err)
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+	return userIds, rows.Err()
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestSaveNetWorthSnapshots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	snapshots := []domain.NetWorthSnapshotModel{
+		{UserId: userId, Date: 100, Assets: 500, Liabilities: 200, NetWorth: 300, CreatedAt: 1},
+		{UserId: userId, Date: 200, Assets: 600, Liabilities: 200, NetWorth: 400, CreatedAt: 1},
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from net_worth_snapshot where user_id = \\? and date >= \\? and date <= \\?").
+		WithArgs(userId, int64(100), int64(200)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, sm := range snapshots {
+		mock.ExpectExec("insert into net_worth_snapshot").
+			WithArgs(userId, sm.Date, sm.Assets, sm.Liabilities, sm.NetWorth, sm.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	err = udb.SaveNetWorthSnapshots(context.Background(), userId, snapshots)
+	if err != nil {
+		t.Fatal("Error saving the snapshots:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetValuationsByUserId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	vm := domain.ValuationModel{ValuationId: uuid.New(), UserId: uuid.New(), Name: "House", Kind: domain.VALUATION_ASSET, Amount: 250000, Date: 10, CreatedAt: 20}
+	rows := sqlmock.NewRows([]string{"valuation_id", "user_id", "name", "kind", "amount", "date", "created_at"}).
+		AddRow(vm.ValuationId, vm.UserId, vm.Name, vm.Kind, vm.Amount, vm.Date, vm.CreatedAt)
+	mock.ExpectQuery("select (.+) from valuation_model where user_id = \\? order by date").
+		WithArgs(vm.UserId).
+		WillReturnRows(rows)
+
+	valuations, err := udb.GetValuationsByUserId(vm.UserId)
+	if err != nil {
+		t.Fatal("Error retrieving valuations:", err)
+	}
+	if len(valuations) != 1 || valuations[0] != vm {
+		t.Errorf("Wrong valuations, got %+v, want %+v", valuations, vm)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
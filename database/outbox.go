@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// OutboxDatabaseInterface reads the domain events saved with the changes through the other
+// database interfaces, and records their dispatch to the event bus subscribers.
+type OutboxDatabaseInterface interface {
+	GetPendingEvents(now int64, limit int) ([]domain.OutboxEventModel, error)
+	GetHandledSubscribers(eventId uuid.UUID) ([]string, error)
+	MarkEventHandled(eventId uuid.UUID, subscriber string, handledAt int64) error
+	MarkEventPublished(eventId uuid.UUID, publishedAt int64) error
+	UpdateEventRetry(eventId uuid.UUID, attempts int, nextAttemptAt int64, lastError string) error
+}
+
+const outboxColumns = `event_id, type, user_id, aggregate_id, payload, attempts, next_attempt_at, last_error, created_at, published_at`
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// withEvents runs the write changing rows on its own without events, and in one SQL transaction
+// with the insert of the events otherwise, so a change and its events are saved together or not
+// at all. Writes are always made in a SQL transaction when auditing is on.
+func (db *SQLManager) withEvents(ctx context.Context, rows auditRows, events []domain.OutboxEventModel, write func(exec execer) error) error {
+	if len(events) == 0 && !db.Audit {
+		return write(db.DB)
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rows)
+	if err != nil {
+		return err
+	}
+	err = write(tx)
+	if err != nil {
+		return err
+	}
+	err = addEvents(tx, events)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func addEvents(exec execer, events []domain.OutboxEventModel) error {
+	stmt := `insert into outbox_event (` + outboxColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	for _, event := range events {
+		_, err := exec.Exec(stmt, event.EventId, event.Type, event.UserId, event.AggregateId, event.Payload, event.Attempts, event.NextAttemptAt, event.LastError, event.CreatedAt, event.PublishedAt)
+		if err != nil {
+			log.Println("Error saving the event to the outbox:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPendingEvents returns the events not yet published with an attempt due at now, oldest first.
+func (db *SQLManager) GetPendingEvents(now int64, limit int) ([]domain.OutboxEventModel, error) {
+	stmt := `select ` + outboxColumns + ` from outbox_event where published_at = 0 and next_attempt_at <= ? order by created_at limit ?`
+	rows, err := db.DB.Query(stmt, now, limit)
+	if err != nil {
+		log.Println("Error retrieving pending events:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEventModel
+	for rows.Next() {
+		var event domain.OutboxEventModel
+		err = rows.Scan(&event.EventId, &event.Type, &event.UserId, &event.AggregateId, &event.Payload, &event.Attempts, &event.NextAttemptAt, &event.LastError, &event.CreatedAt, &event.PublishedAt)
+		if err != nil {
+			log.Println("Error reading event row:", err)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetHandledSubscribers returns the names of the subscribers that handled the event.
+func (db *SQLManager) GetHandledSubscribers(eventId uuid.UUID) ([]string, error) {
+	rows, err := db.DB.Query(`select subscriber from outbox_handled where event_id = ?`, eventId)
+	if err != nil {
+		log.Println("Error retrieving the event subscribers:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscribers []string
+	for rows.Next() {
+		var subscriber string
+		err = rows.Scan(&subscriber)
+		if err != nil {
+			log.Println("Error reading event subscriber row:", err)
+			return nil, err
+		}
+		subscribers = append(subscribers, subscriber)
+	}
+	return subscribers, rows.Err()
+}
+
+func (db *SQLManager) MarkEventHandled(eventId uuid.UUID, subscriber string, handledAt int64) error {
+	stmt := `insert into outbox_handled (event_id, subscriber, handled_at) select ?, ?, ? where not exists (select 1 from outbox_handled where event_id = ? and subscriber = ?)`
+	_, err := db.DB.Exec(stmt, eventId, subscriber, handledAt, eventId, subscriber)
+	if err != nil {
+		log.Println("Error marking the event handled:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) MarkEventPublished(eventId uuid.UUID, publishedAt int64) error {
+	_, err := db.DB.Exec(`update outbox_event set published_at = ?, last_error = '' where event_id = ?`, publishedAt, eventId)
+	if err != nil {
+		log.Println("Error marking the event published:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) UpdateEventRetry(eventId uuid.UUID, attempts int, nextAttemptAt int64, lastError string) error {
+	stmt := `update outbox_event set attempts = ?, next_attempt_at = ?, last_error = ? where event_id = ?`
+	_, err := db.DB.Exec(stmt, attempts, nextAttemptAt, lastError, eventId)
+	if err != nil {
+		log.Println("Error updating the event retry:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddTransaction_WithEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	tm := domain.TransactionModelBuilder().Build()
+	event := domain.OutboxEventModel{EventId: uuid.New(), Type: domain.EVENT_TRANSACTION_CREATED, UserId: tm.UserId, AggregateId: tm.TransactionId, Payload: `{"amount":12}`, NextAttemptAt: 11, CreatedAt: 11}
+	mock.ExpectBegin()
+	mock.ExpectExec("insert into transaction_model").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("insert into outbox_event").
+		WithArgs(event.EventId, event.Type, event.UserId, event.AggregateId, event.Payload, 0, event.NextAttemptAt, "", event.CreatedAt, 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.AddTransaction(context.Background(), &tm, event)
+	if err != nil {
+		t.Fatal("Error saving the transaction:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestAddTransaction_EventFailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	tm := domain.TransactionModelBuilder().Build()
+	event := domain.OutboxEventModel{EventId: uuid.New(), Type: domain.EVENT_TRANSACTION_CREATED, UserId: tm.UserId, AggregateId: tm.TransactionId, Payload: `{}`}
+	mock.ExpectBegin()
+	mock.ExpectExec("insert into transaction_model").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("insert into outbox_event").WillReturnError(errors.New("table outbox_event is full"))
+	mock.ExpectRollback()
+
+	err = udb.AddTransaction(context.Background(), &tm, event)
+	if err == nil {
+		t.Fatal("Expected the failed event to fail the transaction")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetPendingEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	eventId := uuid.New()
+	rows := sqlmock.NewRows([]string{"event_id", "type", "user_id", "aggregate_id", "payload", "attempts", "next_attempt_at", "last_error", "created_at", "published_at"}).
+		AddRow(eventId, "user.updated", uuid.New(), uuid.New(), `{}`, 2, 40, "webhooks: database is locked", 10, 0)
+	mock.ExpectQuery("select (.+) from outbox_event where published_at = 0 and next_attempt_at <= \\? order by created_at limit \\?").
+		WithArgs(50, 100).
+		WillReturnRows(rows)
+
+	events, err := udb.GetPendingEvents(50, 100)
+	if err != nil {
+		t.Fatal("Error retrieving the pending events:", err)
+	}
+	if len(events) != 1 || events[0].EventId != eventId || events[0].Type != domain.EVENT_USER_UPDATED || events[0].Attempts != 2 {
+		t.Errorf("Unexpected events %+v", events)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type PayeeDatabaseInterface interface {
+	AddPayee(ctx context.Context, pm *domain.PayeeModel) error
+	GetPayee(payeeId uuid.UUID) (domain.PayeeModel, error)
+	GetPayeesByUserId(userId uuid.UUID) ([]domain.PayeeModel, error)
+	MergePayees(ctx context.Context, sourceId uuid.UUID, target *domain.PayeeModel, moved []domain.TransactionModel, events ...domain.OutboxEventModel) error
+	GetPayeeSpending(userId uuid.UUID, from int64, to int64) ([]domain.PayeeSpendingDTO, error)
+}
+
+func (db *SQLManager) AddPayee(ctx context.Context, pm *domain.PayeeModel) error {
+	return db.withEvents(ctx, rowsOf("payee_model", "payee_id = ?", pm.PayeeId), nil, func(exec execer) error {
+		return insertPayee(exec, pm)
+	})
+}
+
+func insertPayee(exec execer, pm *domain.PayeeModel) error {
+	aliases, err := json.Marshal(pm.Aliases)
+	if err != nil {
+		return err
+	}
+
+	stmt := `insert into payee_model (payee_id, user_id, name, aliases, created_at) values (?, ?, ?, ?, ?)`
+	_, err = exec.Exec(stmt, pm.PayeeId, pm.UserId, pm.Name, string(aliases), pm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the payee to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetPayee(payeeId uuid.UUID) (domain.PayeeModel, error) {
+	stmt := `select payee_id, user_id, name, aliases, created_at from payee_model where payee_id = ?`
+	pm, err := scanPayee(db.DB.QueryRow(stmt, payeeId))
+	if err != nil {
+		log.Println("Error retrieving payee:", err)
+		return pm, err
+	}
+	return pm, nil
+}
+
+func (db *SQLManager) GetPayeesByUserId(userId uuid.UUID) ([]domain.PayeeModel, error) {
+	stmt := `select payee_id, user_id, name, aliases, created_at from payee_model where user_id = ? order by created_at`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving payees:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payees []domain.PayeeModel
+	for rows.Next() {
+		pm, err := scanPayee(rows)
+		if err != nil {
+			log.Println("Error reading payee row:", err)
+			return nil, err
+		}
+		payees = append(payees, pm)
+	}
+	return payees, rows.Err()
+}
+
+// MergePayees saves the transactions moved from sourceId to target, the targets aliases and the
+// events, and removes the source payee, all in one transaction.
+func (db *SQLManager) MergePayees(ctx context.Context, sourceId uuid.UUID, target *domain.PayeeModel, moved []domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	aliases, err := json.Marshal(target.Aliases)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rows := range []auditRows{rowsOf("transaction_model", "payee_id = ?", sourceId), rowsOf("payee_model", "payee_id = ? or payee_id = ?", sourceId, target.PayeeId)} {
+		err = tx.track(rows)
+		if err != nil {
+			return err
+		}
+	}
+	for i := range moved {
+		err = updateTransaction(tx, &moved[i])
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`update payee_model set aliases = ? where payee_id = ?`, string(aliases), target.PayeeId)
+	if err != nil {
+		log.Println("Error updating payee aliases:", err)
+		return err
+	}
+	_, err = tx.Exec(`delete from payee_model where payee_id = ?`, sourceId)
+	if err != nil {
+		log.Println("Error deleting merged payee:", err)
+		return err
+	}
+	err = addEvents(tx, events)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *SQLManager) GetPayeeSpending(userId uuid.UUID, from int64, to int64) ([]domain.PayeeSpendingDTO, error) {
+	stmt := `select p.payee_id, p.name, count(*), sum(t.amount) from transaction_model t
+		join payee_model p on p.payee_id = t.payee_id
+		where t.user_id = ? and t.date >= ? and t.date <= ? and t.type = ? and t.status != ?
+		group by p.payee_id, p.name
+		order by sum(t.amount) desc`
+	rows, err := db.DB.Query(stmt, userId, from, to, domain.EXPENSE, domain.CANCELLED)
+	if err != nil {
+		log.Println("Error retrieving payee spending:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	spending := []domain.PayeeSpendingDTO{}
+	for rows.Next() {
+		var ps domain.PayeeSpendingDTO
+		err := rows.Scan(&ps.PayeeId, &ps.Name, &ps.Count, &ps.Total)
+		if err != nil {
+			log.Println("Error reading payee spending row:", err)
+			return nil, err
+		}
+		spending = append(spending, ps)
+	}
+	return spending, rows.Err()
+}
+
+func scanPayee(row rowScanner) (domain.PayeeModel, error) {
+	var pm domain.PayeeModel
+	var aliases string
+	err := row.Scan(&pm.PayeeId, &pm.UserId, &pm.Name, &aliases, &pm.CreatedAt)
+	if err != nil {
+		return pm, err
+	}
+	err = json.Unmarshal([]byte(aliases), &pm.Aliases)
+	return pm, err
+}
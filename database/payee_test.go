@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddPayee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	pm := domain.PayeeModelBuilder().WithAliases("^amazon").Build()
+	mock.ExpectExec("insert into payee_model").
+		WithArgs(pm.PayeeId, pm.UserId, pm.Name, `["^amazon"]`, pm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddPayee(context.Background(), &pm)
+	if err != nil {
+		t.Fatal("Error saving payee:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestMergePayees(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	sourceId := uuid.New()
+	target := domain.PayeeModelBuilder().WithAliases("^amazon", "^amzn").Build()
+	moved := domain.TransactionModelBuilder().Build()
+	moved.PayeeId = target.PayeeId
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("update transaction_model set category_id = ?, account_id = ?, payee_id = ?")).
+		WithArgs(moved.CategoryId, moved.AccountId, target.PayeeId, moved.Amount, moved.Date, moved.Description, moved.UpdatedAt, moved.Type, moved.PaymentMethod, moved.Status, moved.Currency, moved.OriginalAmount, moved.TransactionId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("update payee_model set aliases = \\? where payee_id = \\?").
+		WithArgs(`["^amazon","^amzn"]`, target.PayeeId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("delete from payee_model where payee_id = \\?").
+		WithArgs(sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = udb.MergePayees(context.Background(), sourceId, &target, []domain.TransactionModel{moved})
+	if err != nil {
+		t.Fatal("Error merging payees:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
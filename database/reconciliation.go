@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type ReconciliationDatabaseInterface interface {
+	AddReconciliation(ctx context.Context, rm *domain.ReconciliationModel) error
+	GetReconciliation(reconciliationId uuid.UUID) (domain.ReconciliationModel, error)
+	GetReconciliationsByAccount(userId uuid.UUID, accountId int64) ([]domain.ReconciliationModel, error)
+	UpdateReconciliationStatus(ctx context.Context, reconciliationId uuid.UUID, status domain.ReconciliationStatus, reconciledAt int64) error
+	DeleteReconciliation(ctx context.Context, reconciliationId uuid.UUID) error
+	SetTransactionsCleared(ctx context.Context, reconciliationId uuid.UUID, transactionIds []uuid.UUID, cleared bool) error
+	GetClearedTransactionIds(reconciliationId uuid.UUID) ([]uuid.UUID, error)
+	GetReconciledTransactionIds(userId uuid.UUID, accountId int64) ([]uuid.UUID, error)
+	IsTransactionReconciled(transactionId uuid.UUID) (bool, error)
+}
+
+const reconciliationColumns = `reconciliation_id, user_id, account_id, statement_date, starting_balance, ending_balance, status, created_at, reconciled_at`
+
+func (db *SQLManager) AddReconciliation(ctx context.Context, rm *domain.ReconciliationModel) error {
+	stmt := `insert into reconciliation_session (` + reconciliationColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("reconciliation_session", "reconciliation_id = ?", rm.ReconciliationId), stmt, rm.ReconciliationId, rm.UserId, rm.AccountId, rm.StatementDate, rm.StartingBalance, rm.EndingBalance, rm.Status, rm.CreatedAt, rm.ReconciledAt)
+	if err != nil {
+		log.Println("Error saving the reconciliation to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetReconciliation(reconciliationId uuid.UUID) (domain.ReconciliationModel, error) {
+	stmt := `select ` + reconciliationColumns + ` from reconciliation_session where reconciliation_id = ?`
+	rm, err := scanReconciliation(db.DB.QueryRow(stmt, reconciliationId))
+	if err != nil {
+		log.Println("Error retrieving reconciliation:", err)
+		return rm, err
+	}
+	return rm, nil
+}
+
+// GetReconciliationsByAccount returns the reconciliations of the account, oldest statement first.
+func (db *SQLManager) GetReconciliationsByAccount(userId uuid.UUID, accountId int64) ([]domain.ReconciliationModel, error) {
+	stmt := `select ` + reconciliationColumns + ` from reconciliation_session where user_id = ? and account_id = ? order by statement_date, created_at`
+	rows, err := db.DB.Query(stmt, userId, accountId)
+	if err != nil {
+		log.Println("Error retrieving reconciliations:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reconciliations []domain.ReconciliationModel
+	for rows.Next() {
+		rm, err := scanReconciliation(rows)
+		if err != nil {
+			log.Println("Error reading reconciliation row:", err)
+			return nil, err
+		}
+		reconciliations = append(reconciliations, rm)
+	}
+	return reconciliations, rows.Err()
+}
+
+func (db *SQLManager) UpdateReconciliationStatus(ctx context.Context, reconciliationId uuid.UUID, status domain.ReconciliationStatus, reconciledAt int64) error {
+	stmt := `update reconciliation_session set status = ?, reconciled_at = ? where reconciliation_id = ?`
+	_, err := db.exec(ctx, rowsOf("reconciliation_session", "reconciliation_id = ?", reconciliationId), stmt, status, reconciledAt, reconciliationId)
+	if err != nil {
+		log.Println("Error updating the reconciliation status:", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteReconciliation removes the reconciliation and the transactions cleared in it.
+func (db *SQLManager) DeleteReconciliation(ctx context.Context, reconciliationId uuid.UUID) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rows := range []auditRows{rowsOf("reconciliation_transaction", "reconciliation_id = ?", reconciliationId), rowsOf("reconciliation_session", "reconciliation_id = ?", reconciliationId)} {
+		err = tx.track(rows)
+		if err != nil {
+			return err
+		}
+	}
+	for _, stmt := range []string{`delete from reconciliation_transaction where reconciliation_id = ?`, `delete from reconciliation_session where reconciliation_id = ?`} {
+		_, err = tx.Exec(stmt, reconciliationId)
+		if err != nil {
+			log.Println("Error deleting reconciliation:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SetTransactionsCleared adds the transactions to the reconciliation, or removes them from it
+// when cleared is false.
+func (db *SQLManager) SetTransactionsCleared(ctx context.Context, reconciliationId uuid.UUID, transactionIds []uuid.UUID, cleared bool) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("reconciliation_transaction", "reconciliation_id = ?", reconciliationId))
+	if err != nil {
+		return err
+	}
+
+	for _, transactionId := range transactionIds {
+		_, err = tx.Exec(`delete from reconciliation_transaction where reconciliation_id = ? and transaction_id = ?`, reconciliationId, transactionId)
+		if err != nil {
+			log.Println("Error clearing transaction:", err)
+			return err
+		}
+		if !cleared {
+			continue
+		}
+		_, err = tx.Exec(`insert into reconciliation_transaction (reconciliation_id, transaction_id) values (?, ?)`, reconciliationId, transactionId)
+		if err != nil {
+			log.Println("Error clearing transaction:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (db *SQLManager) GetClearedTransactionIds(reconciliationId uuid.UUID) ([]uuid.UUID, error) {
+	return db.queryTransactionIds(`select transaction_id from reconciliation_transaction where reconciliation_id = ?`, reconciliationId)
+}
+
+// GetReconciledTransactionIds returns the transactions of the account cleared in its locked reconciliations.
+func (db *SQLManager) GetReconciledTransactionIds(userId uuid.UUID, accountId int64) ([]uuid.UUID, error) {
+	stmt := `select rt.transaction_id from reconciliation_transaction rt join reconciliation_session rs on rs.reconciliation_id = rt.reconciliation_id where rs.user_id = ? and rs.account_id = ? and rs.status = ?`
+	return db.queryTransactionIds(stmt, userId, accountId, domain.RECONCILED)
+}
+
+func (db *SQLManager) IsTransactionReconciled(transactionId uuid.UUID) (bool, error) {
+	var count int
+	stmt := `select count(*) from reconciliation_transaction rt join reconciliation_session rs on rs.reconciliation_id = rt.reconciliation_id where rt.transaction_id = ? and rs.status = ?`
+	err := db.DB.QueryRow(stmt, transactionId, domain.RECONCILED).Scan(&count)
+	if err != nil {
+		log.Println("Error checking the transaction reconciliation:", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (db *SQLManager) queryTransactionIds(stmt string, args ...any) ([]uuid.UUID, error) {
+	rows, err := db.DB.Query(stmt, args...)
+	if err != nil {
+		log.Println("Error retrieving cleared transactions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactionIds []uuid.UUID
+	for rows.Next() {
+		var transactionId uuid.UUID
+		err := rows.Scan(&transactionId)
+		if err != nil {
+			log.Println("Error reading cleared transaction row:", err)
+			return nil, err
+		}
+		transactionIds = append(transactionIds, transactionId)
+	}
+	return transactionIds, rows.Err()
+}
+
+func scanReconciliation(row rowScanner) (domain.ReconciliationModel, error) {
+	var rm domain.ReconciliationModel
+	err := row.Scan(&rm.ReconciliationId, &rm.UserId, &rm.AccountId, &rm.StatementDate, &rm.StartingBalance, &rm.EndingBalance, &rm.Status, &rm.CreatedAt, &rm.ReconciledAt)
+	return rm, err
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestSetTransactionsCleared(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	reconciliationId, transactionId := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from reconciliation_transaction where reconciliation_id = \\? and transaction_id = \\?").
+		WithArgs(reconciliationId, transactionId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("insert into reconciliation_transaction").
+		WithArgs(reconciliationId, transactionId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.SetTransactionsCleared(context.Background(), reconciliationId, []uuid.UUID{transactionId}, true)
+	if err != nil {
+		t.Fatal("Error clearing the transaction:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestIsTransactionReconciled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	transactionId := uuid.New()
+	mock.ExpectQuery("select count\\(\\*\\) from reconciliation_transaction rt join reconciliation_session rs (.+) where rt.transaction_id = \\? and rs.status = \\?").
+		WithArgs(transactionId, domain.RECONCILED).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	reconciled, err := udb.IsTransactionReconciled(transactionId)
+	if err != nil {
+		t.Fatal("Error checking the transaction:", err)
+	}
+	if !reconciled {
+		t.Error("Expected the transaction to be reconciled")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type RuleDatabaseInterface interface {
+	AddRule(ctx context.Context, rm *domain.RuleModel) error
+	GetRulesByUserId(userId uuid.UUID) ([]domain.RuleModel, error)
+	DeleteRule(ctx context.Context, ruleId uuid.UUID) error
+}
+
+// Conditions and actions are stored as JSON, they are only ever read together with the rule.
+func (db *SQLManager) AddRule(ctx context.Context, rm *domain.RuleModel) error {
+	conditions, err := json.Marshal(rm.Conditions)
+	if err != nil {
+		return err
+	}
+	actions, err := json.Marshal(rm.Actions)
+	if err != nil {
+		return err
+	}
+
+	stmt := `insert into rule_model (rule_id, user_id, name, priority, conditions, actions, created_at) values (?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.exec(ctx, rowsOf("rule_model", "rule_id = ?", rm.RuleId), stmt, rm.RuleId, rm.UserId, rm.Name, rm.Priority, string(conditions), string(actions), rm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the rule to the database:", err)
+		return err
+	}
+	return nil
+}
+
+// GetRulesByUserId returns the users rules in the order they are evaluated.
+func (db *SQLManager) GetRulesByUserId(userId uuid.UUID) ([]domain.RuleModel, error) {
+	stmt := `select rule_id, user_id, name, priority, conditions, actions, created_at from rule_model where user_id = ? order by priority, created_at`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving rules:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.RuleModel
+	for rows.Next() {
+		var rm domain.RuleModel
+		var conditions, actions string
+		err := rows.Scan(&rm.RuleId, &rm.UserId, &rm.Name, &rm.Priority, &conditions, &actions, &rm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading rule row:", err)
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(conditions), &rm.Conditions); err != nil {
+			log.Println("Error reading rule conditions:", err)
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(actions), &rm.Actions); err != nil {
+			log.Println("Error reading rule actions:", err)
+			return nil, err
+		}
+		rules = append(rules, rm)
+	}
+	return rules, rows.Err()
+}
+
+func (db *SQLManager) DeleteRule(ctx context.Context, ruleId uuid.UUID) error {
+	stmt := `delete from rule_model where rule_id = ?`
+	_, err := db.exec(ctx, rowsOf("rule_model", "rule_id = ?", ruleId), stmt, ruleId)
+	if err != nil {
+		log.Println("Error deleting rule:", err)
+		return err
+	}
+	return nil
+}
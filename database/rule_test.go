@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddRule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	rm := domain.RuleModelBuilder().Build()
+	conditions, _ := json.Marshal(rm.Conditions)
+	actions, _ := json.Marshal(rm.Actions)
+	mock.ExpectExec("insert into rule_model").
+		WithArgs(rm.RuleId, rm.UserId, rm.Name, rm.Priority, string(conditions), string(actions), rm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddRule(context.Background(), &rm)
+	if err != nil {
+		t.Fatal("Error saving rule:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetRulesByUserId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	rm := domain.RuleModelBuilder().Build()
+	conditions, _ := json.Marshal(rm.Conditions)
+	actions, _ := json.Marshal(rm.Actions)
+	rows := sqlmock.NewRows([]string{"rule_id", "user_id", "name", "priority", "conditions", "actions", "created_at"}).
+		AddRow(rm.RuleId, rm.UserId, rm.Name, rm.Priority, string(conditions), string(actions), rm.CreatedAt)
+	mock.ExpectQuery("select (.+) from rule_model where user_id = \\? order by priority").
+		WithArgs(rm.UserId).
+		WillReturnRows(rows)
+
+	rules, err := udb.GetRulesByUserId(rm.UserId)
+	if err != nil {
+		t.Fatal("Error retrieving rules:", err)
+	}
+	if len(rules) != 1 || !reflect.DeepEqual(rules[0], rm) {
+		t.Fatalf("Retrieved rules do not match expected, got %v, want %v", rules, rm)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type ScheduleDatabaseInterface interface {
+	AddScheduledItem(ctx context.Context, sm *domain.ScheduledItemModel) error
+	GetScheduledItemsByUserId(userId uuid.UUID) ([]domain.ScheduledItemModel, error)
+	DeleteScheduledItem(ctx context.Context, scheduledItemId uuid.UUID) error
+}
+
+func (db *SQLManager) AddScheduledItem(ctx context.Context, sm *domain.ScheduledItemModel) error {
+	stmt := `insert into scheduled_item_model (scheduled_item_id, user_id, account_id, description, amount, type, frequency, start_date, created_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.exec(ctx, rowsOf("scheduled_item_model", "scheduled_item_id = ?", sm.ScheduledItemId), stmt, sm.ScheduledItemId, sm.UserId, sm.AccountId, sm.Description, sm.Amount, sm.Type, sm.Frequency, sm.StartDate, sm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the scheduled item to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetScheduledItemsByUserId(userId uuid.UUID) ([]domain.ScheduledItemModel, error) {
+	stmt := `select scheduled_item_id, user_id, account_id, description, amount, type, frequency, start_date, created_at from scheduled_item_model where user_id = ? order by start_date`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving scheduled items:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.ScheduledItemModel
+	for rows.Next() {
+		var sm domain.ScheduledItemModel
+		err := rows.Scan(&sm.ScheduledItemId, &sm.UserId, &sm.AccountId, &sm.Description, &sm.Amount, &sm.Type, &sm.Frequency, &sm.StartDate, &sm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading scheduled item row:", err)
+			return nil, err
+		}
+		items = append(items, sm)
+	}
+	return items, rows.Err()
+}
+
+func (db *SQLManager) DeleteScheduledItem(ctx context.Context, scheduledItemId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("scheduled_item_model", "scheduled_item_id = ?", scheduledItemId), `delete from scheduled_item_model where scheduled_item_id = ?`, scheduledItemId)
+	if err != nil {
+		log.Println("Error deleting scheduled item:", err)
+		return err
+	}
+	return nil
+}
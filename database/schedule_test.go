@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddScheduledItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	sm := domain.ScheduledItemModel{ScheduledItemId: uuid.New(), UserId: uuid.New(), AccountId: 1, Description: "Rent", Amount: 1200, Type: domain.EXPENSE, Frequency: domain.MONTHLY, StartDate: 10, CreatedAt: 20}
+	mock.ExpectExec("insert into scheduled_item_model").
+		WithArgs(sm.ScheduledItemId, sm.UserId, sm.AccountId, sm.Description, sm.Amount, sm.Type, sm.Frequency, sm.StartDate, sm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddScheduledItem(context.Background(), &sm)
+	if err != nil {
+		t.Fatal("Error adding the scheduled item:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type SubscriptionDatabaseInterface interface {
+	SaveSubscriptionDecision(ctx context.Context, sm *domain.SubscriptionDecisionModel) error
+	GetSubscriptionDecisions(userId uuid.UUID) ([]domain.SubscriptionDecisionModel, error)
+}
+
+// SaveSubscriptionDecision replaces any earlier decision on the same subscription.
+func (db *SQLManager) SaveSubscriptionDecision(ctx context.Context, sm *domain.SubscriptionDecisionModel) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("subscription_decision", "user_id = ? and subscription_key = ?", sm.UserId, sm.Key))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`delete from subscription_decision where user_id = ? and subscription_key = ?`, sm.UserId, sm.Key)
+	if err != nil {
+		log.Println("Error replacing the subscription decision:", err)
+		return err
+	}
+
+	stmt := `insert into subscription_decision (user_id, subscription_key, status, scheduled_item_id, created_at) values (?, ?, ?, ?, ?)`
+	_, err = tx.Exec(stmt, sm.UserId, sm.Key, sm.Status, sm.ScheduledItemId, sm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the subscription decision to the database:", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *SQLManager) GetSubscriptionDecisions(userId uuid.UUID) ([]domain.SubscriptionDecisionModel, error) {
+	stmt := `select user_id, subscription_key, status, scheduled_item_id, created_at from subscription_decision where user_id = ?`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving subscription decisions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decisions []domain.SubscriptionDecisionModel
+	for rows.Next() {
+		var sm domain.SubscriptionDecisionModel
+		err := rows.Scan(&sm.UserId, &sm.Key, &sm.Status, &sm.ScheduledItemId, &sm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading subscription decision row:", err)
+			return nil, err
+		}
+		decisions = append(decisions, sm)
+	}
+	return decisions, rows.Err()
+}
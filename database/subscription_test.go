@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestSaveSubscriptionDecision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	sm := domain.SubscriptionDecisionModel{UserId: uuid.New(), Key: "coffee club", Status: domain.SUBSCRIPTION_DISMISSED, CreatedAt: 10}
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from subscription_decision where user_id = \\? and subscription_key = \\?").
+		WithArgs(sm.UserId, sm.Key).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("insert into subscription_decision").
+		WithArgs(sm.UserId, sm.Key, sm.Status, sm.ScheduledItemId, sm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.SaveSubscriptionDecision(context.Background(), &sm)
+	if err != nil {
+		t.Fatal("Error saving the subscription decision:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type TagDatabaseInterface interface {
+	AddTag(ctx context.Context, tm *domain.TagModel) error
+	GetTagsByUserId(userId uuid.UUID) ([]domain.TagModel, error)
+	AddTransactionTags(ctx context.Context, transactionIds []uuid.UUID, tagIds []uuid.UUID) error
+	RemoveTransactionTags(ctx context.Context, transactionIds []uuid.UUID, tagIds []uuid.UUID) error
+	GetTransactionTags(userId uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+// AddTag saves the tag, unless the user already has a tag of the name, whose ID and creation time
+// are then set on tm. Two requests creating the same tag both end up with the one saved.
+func (db *SQLManager) AddTag(ctx context.Context, tm *domain.TagModel) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("tag_model", "user_id = ? and name = ?", tm.UserId, tm.Name))
+	if err != nil {
+		return err
+	}
+	err = db.addTag(tx, tm)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// queryExecer runs statements and single row queries.
+type queryExecer interface {
+	execer
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func (db *SQLManager) addTag(exec queryExecer, tm *domain.TagModel) error {
+	stmt := db.insertIgnore() + ` into tag_model (tag_id, user_id, name, created_at) values (?, ?, ?, ?)`
+	_, err := exec.Exec(stmt, tm.TagId, tm.UserId, tm.Name, tm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the tag to the database:", err)
+		return err
+	}
+
+	stmt = `select tag_id, created_at from tag_model where user_id = ? and name = ?`
+	err = exec.QueryRow(stmt, tm.UserId, tm.Name).Scan(&tm.TagId, &tm.CreatedAt)
+	if err != nil {
+		log.Println("Error retrieving the saved tag:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetTagsByUserId(userId uuid.UUID) ([]domain.TagModel, error) {
+	stmt := `select tag_id, user_id, name, created_at from tag_model where user_id = ? order by name`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving tags:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []domain.TagModel
+	for rows.Next() {
+		var tm domain.TagModel
+		err := rows.Scan(&tm.TagId, &tm.UserId, &tm.Name, &tm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading tag row:", err)
+			return nil, err
+		}
+		tags = append(tags, tm)
+	}
+	return tags, rows.Err()
+}
+
+// AddTransactionTags links every tag to every transaction, links that already exist are skipped.
+func (db *SQLManager) AddTransactionTags(ctx context.Context, transactionIds []uuid.UUID, tagIds []uuid.UUID) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt := `insert into transaction_tag (transaction_id, tag_id) select ?, ? where not exists (select 1 from transaction_tag where transaction_id = ? and tag_id = ?)`
+	for _, transactionId := range transactionIds {
+		err = tx.track(rowsOf("transaction_tag", "transaction_id = ?", transactionId))
+		if err != nil {
+			return err
+		}
+		for _, tagId := range tagIds {
+			_, err = tx.Exec(stmt, transactionId, tagId, transactionId, tagId)
+			if err != nil {
+				log.Println("Error tagging transaction:", err)
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (db *SQLManager) RemoveTransactionTags(ctx context.Context, transactionIds []uuid.UUID, tagIds []uuid.UUID) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt := `delete from transaction_tag where transaction_id = ? and tag_id = ?`
+	for _, transactionId := range transactionIds {
+		err = tx.track(rowsOf("transaction_tag", "transaction_id = ?", transactionId))
+		if err != nil {
+			return err
+		}
+		for _, tagId := range tagIds {
+			_, err = tx.Exec(stmt, transactionId, tagId)
+			if err != nil {
+				log.Println("Error untagging transaction:", err)
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTransactionTags returns the tag names of every tagged transaction of the user.
+func (db *SQLManager) GetTransactionTags(userId uuid.UUID) (map[uuid.UUID][]string, error) {
+	stmt := `select tt.transaction_id, t.name from transaction_tag tt join tag_model t on t.tag_id = tt.tag_id where t.user_id = ? order by t.name`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving transaction tags:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := map[uuid.UUID][]string{}
+	for rows.Next() {
+		var transactionId uuid.UUID
+		var name string
+		err := rows.Scan(&transactionId, &name)
+		if err != nil {
+			log.Println("Error reading transaction tag row:", err)
+			return nil, err
+		}
+		tags[transactionId] = append(tags[transactionId], name)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestAddTransactionTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	transactionIds := []uuid.UUID{uuid.New(), uuid.New()}
+	tagId := uuid.New()
+	mock.ExpectBegin()
+	for _, transactionId := range transactionIds {
+		mock.ExpectExec("insert into transaction_tag (.+) where not exists").
+			WithArgs(transactionId, tagId, transactionId, tagId).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	err = udb.AddTransactionTags(context.Background(), transactionIds, []uuid.UUID{tagId})
+	if err != nil {
+		t.Fatal("Error tagging transactions:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetTransactionTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	transactionId := uuid.New()
+	rows := sqlmock.NewRows([]string{"transaction_id", "name"}).
+		AddRow(transactionId, "reimbursable").
+		AddRow(transactionId, "vacation-2026")
+	mock.ExpectQuery("select (.+) from transaction_tag tt join tag_model t (.+) where t.user_id = \\?").
+		WithArgs(userId).
+		WillReturnRows(rows)
+
+	tags, err := udb.GetTransactionTags(userId)
+	if err != nil {
+		t.Fatal("Error retrieving transaction tags:", err)
+	}
+	if len(tags[transactionId]) != 2 || tags[transactionId][1] != "vacation-2026" {
+		t.Fatalf("Wrong tags, got %v", tags)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
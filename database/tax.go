@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type TaxDatabaseInterface interface {
+	AddTaxLine(ctx context.Context, tm *domain.TaxLineModel) error
+	GetTaxLinesByUserId(userId uuid.UUID) ([]domain.TaxLineModel, error)
+	DeleteTaxLine(ctx context.Context, taxLineId uuid.UUID) error
+}
+
+func (db *SQLManager) AddTaxLine(ctx context.Context, tm *domain.TaxLineModel) error {
+	categoryIds, err := json.Marshal(tm.CategoryIds)
+	if err != nil {
+		return err
+	}
+
+	stmt := `insert into tax_line (tax_line_id, user_id, name, code, category_ids, created_at) values (?, ?, ?, ?, ?, ?)`
+	_, err = db.exec(ctx, rowsOf("tax_line", "tax_line_id = ?", tm.TaxLineId), stmt, tm.TaxLineId, tm.UserId, tm.Name, tm.Code, string(categoryIds), tm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the tax line to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetTaxLinesByUserId(userId uuid.UUID) ([]domain.TaxLineModel, error) {
+	stmt := `select tax_line_id, user_id, name, code, category_ids, created_at from tax_line where user_id = ? order by name`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving tax lines:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxLines []domain.TaxLineModel
+	for rows.Next() {
+		var tm domain.TaxLineModel
+		var categoryIds string
+		err := rows.Scan(&tm.TaxLineId, &tm.UserId, &tm.Name, &tm.Code, &categoryIds, &tm.CreatedAt)
+		if err != nil {
+			log.Println("Error reading tax line row:", err)
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(categoryIds), &tm.CategoryIds)
+		if err != nil {
+			log.Println("Error reading tax line categories:", err)
+			return nil, err
+		}
+		taxLines = append(taxLines, tm)
+	}
+	return taxLines, rows.Err()
+}
+
+func (db *SQLManager) DeleteTaxLine(ctx context.Context, taxLineId uuid.UUID) error {
+	_, err := db.exec(ctx, rowsOf("tax_line", "tax_line_id = ?", taxLineId), `delete from tax_line where tax_line_id = ?`, taxLineId)
+	if err != nil {
+		log.Println("Error deleting tax line:", err)
+		return err
+	}
+	return nil
+}
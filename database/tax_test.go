@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddTaxLine(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	tm := domain.TaxLineModel{TaxLineId: uuid.New(), UserId: uuid.New(), Name: "Medical", Code: "Schedule A line 1", CategoryIds: []int64{5, 6}, CreatedAt: 11}
+	mock.ExpectExec("insert into tax_line").
+		WithArgs(tm.TaxLineId, tm.UserId, tm.Name, tm.Code, "[5,6]", tm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddTaxLine(context.Background(), &tm)
+	if err != nil {
+		t.Fatal("Error saving the tax line:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetTaxLinesByUserId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	rows := sqlmock.NewRows([]string{"tax_line_id", "user_id", "name", "code", "category_ids", "created_at"}).
+		AddRow(uuid.New(), userId, "Medical", "", "[5,6]", 11)
+	mock.ExpectQuery("select (.+) from tax_line where user_id = \\? order by name").
+		WithArgs(userId).
+		WillReturnRows(rows)
+
+	taxLines, err := udb.GetTaxLinesByUserId(userId)
+	if err != nil {
+		t.Fatal("Error retrieving the tax lines:", err)
+	}
+	if len(taxLines) != 1 || len(taxLines[0].CategoryIds) != 2 || taxLines[0].CategoryIds[1] != 6 {
+		t.Errorf("Unexpected tax lines %+v", taxLines)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
@@ -1,20 +1,97 @@
 package database
 
 import (
+	"context"
 	"log"
 
 	"github.com/google/uuid"
 	"github.com/hld3/personal-finance-go/domain"
 )
 
-type TransactionDatabaseInterface interface{
-	AddTransaction(tm *domain.TransactionModel) error
+type TransactionDatabaseInterface interface {
+	AddTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error
+	AddTransactionWith(ctx context.Context, tm *domain.TransactionModel, payee *domain.PayeeModel, tags []domain.TagModel, events ...domain.OutboxEventModel) error
 	GetTransaction(transactionId uuid.UUID) (domain.TransactionModel, error)
+	GetTransactionsByUserId(userId uuid.UUID, from int64, to int64) ([]domain.TransactionModel, error)
+	UpdateTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error
+	DeleteTransaction(ctx context.Context, transactionId uuid.UUID, events ...domain.OutboxEventModel) error
 }
 
-func (db *SQLManager) AddTransaction(tm *domain.TransactionModel) error {
-	stmt := `insert into transaction_model (user_id, transaction_id, category_id, amount, date, description, created_at, updated_at, type, payment_method, status) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := db.DB.Exec(stmt, tm.UserId, tm.TransactionId, tm.CategoryId, tm.Amount, tm.Date, tm.Description, tm.CreatedAt, tm.UpdatedAt, tm.Type, tm.PaymentMethod, tm.Status)
+const transactionColumns = `user_id, transaction_id, category_id, account_id, payee_id, amount, date, description, created_at, updated_at, type, payment_method, status, currency, original_amount`
+
+// AddTransaction saves the transaction, together with the events given.
+func (db *SQLManager) AddTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	return db.withEvents(ctx, rowsOf("transaction_model", "transaction_id = ?", tm.TransactionId), events, func(exec execer) error {
+		return insertTransaction(exec, tm)
+	})
+}
+
+// AddTransactionWith saves the transaction with the payee made for it, when not nil, and links it
+// to the tags, creating the tags the user does not have yet. It is all one SQL transaction with
+// the events, so a failed insert leaves no payee or tag behind.
+func (db *SQLManager) AddTransactionWith(ctx context.Context, tm *domain.TransactionModel, payee *domain.PayeeModel, tags []domain.TagModel, events ...domain.OutboxEventModel) error {
+	if payee == nil && len(tags) == 0 {
+		return db.AddTransaction(ctx, tm, events...)
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if payee != nil {
+		err = tx.track(rowsOf("payee_model", "payee_id = ?", payee.PayeeId))
+		if err != nil {
+			return err
+		}
+		err = insertPayee(tx, payee)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.track(rowsOf("transaction_model", "transaction_id = ?", tm.TransactionId))
+	if err != nil {
+		return err
+	}
+	err = insertTransaction(tx, tm)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) > 0 {
+		err = tx.track(rowsOf("transaction_tag", "transaction_id = ?", tm.TransactionId))
+		if err != nil {
+			return err
+		}
+	}
+	for i := range tags {
+		err = tx.track(rowsOf("tag_model", "user_id = ? and name = ?", tags[i].UserId, tags[i].Name))
+		if err != nil {
+			return err
+		}
+		err = db.addTag(tx, &tags[i])
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`insert into transaction_tag (transaction_id, tag_id) values (?, ?)`, tm.TransactionId, tags[i].TagId)
+		if err != nil {
+			log.Println("Error tagging the transaction:", err)
+			return err
+		}
+	}
+
+	err = addEvents(tx, events)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertTransaction(exec execer, tm *domain.TransactionModel) error {
+	stmt := `insert into transaction_model (` + transactionColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := exec.Exec(stmt, tm.UserId, tm.TransactionId, tm.CategoryId, tm.AccountId, tm.PayeeId, tm.Amount, tm.Date, tm.Description, tm.CreatedAt, tm.UpdatedAt, tm.Type, tm.PaymentMethod, tm.Status, tm.Currency, tm.OriginalAmount)
 	if err != nil {
 		log.Println("Error saving the transaction to the database:", err)
 		return err
@@ -23,12 +100,112 @@ func (db *SQLManager) AddTransaction(tm *domain.TransactionModel) error {
 }
 
 func (db *SQLManager) GetTransaction(transactionId uuid.UUID) (domain.TransactionModel, error) {
-	stmt := `select user_id, transaction_id, category_id, amount, date, description, created_at, updated_at, type, payment_method, status from transaction_model where transaction_id = ?`
-	var transaction domain.TransactionModel
-	err := db.DB.QueryRow(stmt, transactionId).Scan(&transaction.UserId, &transaction.TransactionId, &transaction.CategoryId, &transaction.Amount, &transaction.Date, &transaction.Description, &transaction.CreatedAt, &transaction.UpdatedAt, &transaction.Type, &transaction.PaymentMethod, &transaction.Status)
+	stmt := `select ` + transactionColumns + ` from transaction_model where transaction_id = ?`
+	transaction, err := scanTransaction(db.DB.QueryRow(stmt, transactionId))
 	if err != nil {
 		log.Println("Error retrieving transaction:", err)
 		return transaction, err
 	}
 	return transaction, nil
 }
+
+// GetTransactionsByUserId returns the users transactions dated between from and to, inclusive, oldest first.
+func (db *SQLManager) GetTransactionsByUserId(userId uuid.UUID, from int64, to int64) ([]domain.TransactionModel, error) {
+	stmt := `select ` + transactionColumns + ` from transaction_model where user_id = ? and date >= ? and date <= ? order by date`
+	rows, err := db.DB.Query(stmt, userId, from, to)
+	if err != nil {
+		log.Println("Error retrieving transactions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []domain.TransactionModel
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			log.Println("Error reading transaction row:", err)
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, rows.Err()
+}
+
+// UpdateTransaction saves the changed transaction, together with the events given.
+func (db *SQLManager) UpdateTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	return db.withEvents(ctx, rowsOf("transaction_model", "transaction_id = ?", tm.TransactionId), events, func(exec execer) error {
+		return updateTransaction(exec, tm)
+	})
+}
+
+// updateTransaction saves the changed transaction, its row is tracked by the caller.
+func updateTransaction(exec execer, tm *domain.TransactionModel) error {
+	stmt := `update transaction_model set category_id = ?, account_id = ?, payee_id = ?, amount = ?, date = ?, description = ?, updated_at = ?, type = ?, payment_method = ?, status = ?, currency = ?, original_amount = ? where transaction_id = ?`
+	_, err := exec.Exec(stmt, tm.CategoryId, tm.AccountId, tm.PayeeId, tm.Amount, tm.Date, tm.Description, tm.UpdatedAt, tm.Type, tm.PaymentMethod, tm.Status, tm.Currency, tm.OriginalAmount, tm.TransactionId)
+	if err != nil {
+		log.Println("Error updating transaction:", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTransaction removes the transaction along with its tags, and saves the events given. The
+// suspected duplicate pairs of the transaction are resolved as merged, one of each pair is left.
+func (db *SQLManager) DeleteTransaction(ctx context.Context, transactionId uuid.UUID, events ...domain.OutboxEventModel) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tracked := []auditRows{
+		rowsOf("transaction_tag", "transaction_id = ?", transactionId),
+		rowsOf("transaction_model", "transaction_id = ?", transactionId),
+		rowsOf("duplicate_model", "transaction_id = ? or candidate_id = ?", transactionId, transactionId),
+		rowsOf("attachment_model", "transaction_id = ?", transactionId),
+	}
+	for _, rows := range tracked {
+		err = tx.track(rows)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`delete from transaction_tag where transaction_id = ?`, transactionId)
+	if err != nil {
+		log.Println("Error deleting transaction tags:", err)
+		return err
+	}
+	_, err = tx.Exec(`delete from attachment_model where transaction_id = ?`, transactionId)
+	if err != nil {
+		log.Println("Error deleting transaction attachments:", err)
+		return err
+	}
+	_, err = tx.Exec(`delete from transaction_model where transaction_id = ?`, transactionId)
+	if err != nil {
+		log.Println("Error deleting transaction:", err)
+		return err
+	}
+	stmt := `update duplicate_model set status = ? where status = ? and (transaction_id = ? or candidate_id = ?)`
+	_, err = tx.Exec(stmt, domain.MERGED, domain.SUSPECTED, transactionId, transactionId)
+	if err != nil {
+		log.Println("Error resolving the duplicates of the transaction:", err)
+		return err
+	}
+	err = addEvents(tx, events)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTransaction(row rowScanner) (domain.TransactionModel, error) {
+	var transaction domain.TransactionModel
+	err := row.Scan(&transaction.UserId, &transaction.TransactionId, &transaction.CategoryId, &transaction.AccountId, &transaction.PayeeId, &transaction.Amount, &transaction.Date, &transaction.Description, &transaction.CreatedAt, &transaction.UpdatedAt, &transaction.Type, &transaction.PaymentMethod, &transaction.Status, &transaction.Currency, &transaction.OriginalAmount)
+	return transaction, err
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
@@ -32,7 +33,7 @@ func TestGetTransactionIntegration(t *testing.T) {
 
 	// Save the transaction.
 	expected := domain.TransactionModelBuilder().Build()
-	err = udb.AddTransaction(&expected)
+	err = udb.AddTransaction(context.Background(), &expected)
 	if err != nil {
 		t.Fatal("Error saving transaction:", err)
 	}
@@ -46,6 +47,8 @@ func TestGetTransactionIntegration(t *testing.T) {
 	if result.UserId != expected.UserId ||
 		result.TransactionId != expected.TransactionId ||
 		result.CategoryId != expected.CategoryId ||
+		result.AccountId != expected.AccountId ||
+		result.PayeeId != expected.PayeeId ||
 		result.Amount != expected.Amount ||
 		result.Date != expected.Date ||
 		result.Description != expected.Description ||
@@ -53,7 +56,9 @@ func TestGetTransactionIntegration(t *testing.T) {
 		result.UpdatedAt != expected.UpdatedAt ||
 		result.Type != expected.Type ||
 		result.PaymentMethod != expected.PaymentMethod ||
-		result.Status != expected.Status {
+		result.Status != expected.Status ||
+		result.Currency != expected.Currency ||
+		result.OriginalAmount != expected.OriginalAmount {
 		t.Errorf("Transaction data does not match. want %v, got %v", expected, result)
 	}
 }
@@ -1,9 +1,11 @@
 package database
 
 import (
+	"context"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
 	"github.com/hld3/personal-finance-go/domain"
 )
 
@@ -17,10 +19,50 @@ func TestAddTransaction(t *testing.T) {
 
 	tm := domain.TransactionModelBuilder().Build()
 	mock.ExpectExec("insert into transaction").
-		WithArgs(tm.UserId, tm.TransactionId, tm.CategoryId, tm.Amount, tm.Date, tm.Description, tm.CreatedAt, tm.UpdatedAt, tm.Type, tm.PaymentMethod, tm.Status).
+		WithArgs(tm.UserId, tm.TransactionId, tm.CategoryId, tm.AccountId, tm.PayeeId, tm.Amount, tm.Date, tm.Description, tm.CreatedAt, tm.UpdatedAt, tm.Type, tm.PaymentMethod, tm.Status, tm.Currency, tm.OriginalAmount).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = udb.AddTransaction(&tm)
+	err = udb.AddTransaction(context.Background(), &tm)
+	if err != nil {
+		t.Fatal("Error saving transaction:", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestAddTransactionWith(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	tm := domain.TransactionModelBuilder().Build()
+	payee := domain.PayeeModelBuilder().WithUserId(tm.UserId).Build()
+	tm.PayeeId = payee.PayeeId
+	tag := domain.TagModel{TagId: uuid.New(), UserId: tm.UserId, Name: "vacation-2026", CreatedAt: 1}
+	savedTagId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec("insert into payee_model").
+		WithArgs(payee.PayeeId, payee.UserId, payee.Name, sqlmock.AnyArg(), payee.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("insert into transaction_model").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("insert or ignore into tag_model").
+		WithArgs(tag.TagId, tag.UserId, tag.Name, tag.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("select tag_id, created_at from tag_model where user_id = \\? and name = \\?").
+		WithArgs(tag.UserId, tag.Name).
+		WillReturnRows(sqlmock.NewRows([]string{"tag_id", "created_at"}).AddRow(savedTagId, 0))
+	mock.ExpectExec("insert into transaction_tag").
+		WithArgs(tm.TransactionId, savedTagId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = udb.AddTransactionWith(context.Background(), &tm, &payee, []domain.TagModel{tag})
 	if err != nil {
 		t.Fatal("Error saving transaction:", err)
 	}
@@ -39,8 +81,8 @@ func TestGetTransaction(t *testing.T) {
 	udb := SQLManager{DB: db}
 
 	transaction := domain.TransactionModelBuilder().Build()
-	row := sqlmock.NewRows([]string{"user_id", "transaction_id", "category_id", "amount", "date", "description", "created_at", "updated_at", "type", "payment_method", "status"}).
-		AddRow(transaction.UserId, transaction.TransactionId, transaction.CategoryId, transaction.Amount, transaction.Date, transaction.Description, transaction.CreatedAt, transaction.UpdatedAt, transaction.Type, transaction.PaymentMethod, transaction.Status)
+	row := sqlmock.NewRows([]string{"user_id", "transaction_id", "category_id", "account_id", "payee_id", "amount", "date", "description", "created_at", "updated_at", "type", "payment_method", "status", "currency", "original_amount"}).
+		AddRow(transaction.UserId, transaction.TransactionId, transaction.CategoryId, transaction.AccountId, transaction.PayeeId, transaction.Amount, transaction.Date, transaction.Description, transaction.CreatedAt, transaction.UpdatedAt, transaction.Type, transaction.PaymentMethod, transaction.Status, transaction.Currency, transaction.OriginalAmount)
 
 	mock.ExpectQuery("select (.+) from transaction_model where transaction_id = ?").
 		WithArgs(transaction.TransactionId).
@@ -57,3 +99,67 @@ func TestGetTransaction(t *testing.T) {
 		t.Fatalf("There were unfulfilled expectations: %v", err)
 	}
 }
+
+func TestGetTransactionsByUserId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	first := domain.TransactionModelBuilder().Build()
+	second := domain.TransactionModelBuilder().Build()
+	second.UserId = first.UserId
+	rows := sqlmock.NewRows([]string{"user_id", "transaction_id", "category_id", "account_id", "payee_id", "amount", "date", "description", "created_at", "updated_at", "type", "payment_method", "status", "currency", "original_amount"})
+	for _, tm := range []domain.TransactionModel{first, second} {
+		rows.AddRow(tm.UserId, tm.TransactionId, tm.CategoryId, tm.AccountId, tm.PayeeId, tm.Amount, tm.Date, tm.Description, tm.CreatedAt, tm.UpdatedAt, tm.Type, tm.PaymentMethod, tm.Status, tm.Currency, tm.OriginalAmount)
+	}
+	mock.ExpectQuery("select (.+) from transaction_model where user_id = \\? and date >= \\? and date <= \\?").
+		WithArgs(first.UserId, int64(0), int64(100)).
+		WillReturnRows(rows)
+
+	transactions, err := udb.GetTransactionsByUserId(first.UserId, 0, 100)
+	if err != nil {
+		t.Fatal("Error retrieving transactions:", err)
+	}
+	if len(transactions) != 2 || transactions[0] != first || transactions[1] != second {
+		t.Fatalf("Retrieved transactions do not match expected, got %v, want %v", transactions, []domain.TransactionModel{first, second})
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDeleteTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	tm := domain.TransactionModelBuilder().Build()
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from transaction_tag where transaction_id = ?").
+		WithArgs(tm.TransactionId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("delete from attachment_model where transaction_id = ?").
+		WithArgs(tm.TransactionId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("delete from transaction_model where transaction_id = ?").
+		WithArgs(tm.TransactionId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("update duplicate_model set status = \\? where status = \\? and \\(transaction_id = \\? or candidate_id = \\?\\)").
+		WithArgs(domain.MERGED, domain.SUSPECTED, tm.TransactionId, tm.TransactionId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = udb.DeleteTransaction(context.Background(), tm.TransactionId)
+	if err != nil {
+		t.Fatal("Error deleting transaction:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
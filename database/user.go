@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
@@ -9,19 +10,23 @@ import (
 )
 
 type UserDatabaseInterface interface {
-	AddNewUser(user *domain.UserModel) error
+	AddNewUser(ctx context.Context, user *domain.UserModel, events ...domain.OutboxEventModel) error
 	RetrieveUserByEmail(email string) (domain.UserModel, error)
 	RetrieveUserByUserId(userId uuid.UUID) (domain.UserModel, error)
-	UpdateUserByUserId(user *domain.UserDTO) error
+	UpdateUserByUserId(ctx context.Context, user *domain.UserDTO, events ...domain.OutboxEventModel) error
 }
 
 type SQLManager struct {
-	DB *sql.DB
+	DB    *sql.DB
+	Audit bool // records the changes to the audited tables in the audit log.
 }
 
-func (db *SQLManager) AddNewUser(user *domain.UserModel) error {
-	stmt := `insert into user_model (user_id, first_name, last_name, email, phone, date_of_birth, creation_date, password_hash) values (?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := db.DB.Exec(stmt, user.UserId, user.FirstName, user.LastName, user.Email, user.Phone, user.DateOfBirth, user.CreationDate, user.PasswordHash)
+func (db *SQLManager) AddNewUser(ctx context.Context, user *domain.UserModel, events ...domain.OutboxEventModel) error {
+	err := db.withEvents(ctx, rowsOf("user_model", "user_id = ?", user.UserId), events, func(exec execer) error {
+		stmt := `insert into user_model (user_id, first_name, last_name, email, phone, date_of_birth, creation_date, password_hash) values (?, ?, ?, ?, ?, ?, ?, ?)`
+		_, err := exec.Exec(stmt, user.UserId, user.FirstName, user.LastName, user.Email, user.Phone, user.DateOfBirth, user.CreationDate, user.PasswordHash)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -49,11 +54,10 @@ func (db *SQLManager) RetrieveUserByUserId(userId uuid.UUID) (domain.UserModel,
 	return user, nil
 }
 
-func (db *SQLManager) UpdateUserByUserId(user *domain.UserDTO) error {
-	stmt := `update user_model set first_name = ?, last_name = ?, email = ?, phone = ?, date_of_birth = ? where user_id = ?`
-	_, err := db.DB.Exec(stmt, user.FirstName, user.LastName, user.Email, user.Phone, user.DateOfBirth, user.UserId)
-	if err != nil {
+func (db *SQLManager) UpdateUserByUserId(ctx context.Context, user *domain.UserDTO, events ...domain.OutboxEventModel) error {
+	return db.withEvents(ctx, rowsOf("user_model", "user_id = ?", user.UserId), events, func(exec execer) error {
+		stmt := `update user_model set first_name = ?, last_name = ?, email = ?, phone = ?, date_of_birth = ? where user_id = ?`
+		_, err := exec.Exec(stmt, user.FirstName, user.LastName, user.Email, user.Phone, user.DateOfBirth, user.UserId)
 		return err
-	}
-	return nil
+	})
 }
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
@@ -38,7 +39,7 @@ func TestAddNewUserIntegration(t *testing.T) {
 	expectedUser.DateOfBirth = dob
 	createEnd := time.Now().UnixMilli()
 
-	err = udb.AddNewUser(&expectedUser)
+	err = udb.AddNewUser(context.Background(), &expectedUser)
 	if err != nil {
 		t.Fatal("Error adding a new user:", err)
 	}
@@ -141,7 +142,7 @@ func TestUpdateUserByUserIdIntegration(t *testing.T) {
 	userDTO := domain.UserDTOBuilder().WithUserId(userModel.UserId).Build()
 
 	// Call the exercised function
-	err = udb.UpdateUserByUserId(&userDTO)
+	err = udb.UpdateUserByUserId(context.Background(), &userDTO)
 	if err != nil {
 		t.Error("Error with exercised function:", err)
 	}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -18,7 +19,7 @@ func TestAddNewUser(t *testing.T) {
 	user := domain.UserModelBuilder().Build()
 	mock.ExpectExec("insert into user").WithArgs(user.UserId, user.FirstName, user.LastName, user.Email, user.Phone, user.DateOfBirth, user.CreationDate, user.PasswordHash).WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = udb.AddNewUser(&user)
+	err = udb.AddNewUser(context.Background(), &user)
 	if err != nil {
 		t.Error("Error saving user", err)
 	}
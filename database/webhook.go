@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type WebhookDatabaseInterface interface {
+	AddWebhook(ctx context.Context, wm *domain.WebhookSubscriptionModel) error
+	GetWebhook(webhookId uuid.UUID) (domain.WebhookSubscriptionModel, error)
+	GetWebhooksByUserId(userId uuid.UUID) ([]domain.WebhookSubscriptionModel, error)
+	DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error
+	AddWebhookDelivery(dm *domain.WebhookDeliveryModel) error
+	GetWebhookDelivery(deliveryId uuid.UUID) (domain.WebhookDeliveryModel, error)
+	GetWebhookDeliveries(webhookId uuid.UUID, limit int) ([]domain.WebhookDeliveryModel, error)
+	GetDueWebhookDeliveries(now int64, limit int) ([]domain.WebhookDeliveryModel, error)
+	UpdateWebhookDelivery(dm *domain.WebhookDeliveryModel) error
+}
+
+const (
+	webhookColumns  = `webhook_id, user_id, url, events, secret, created_at`
+	deliveryColumns = `delivery_id, webhook_id, user_id, event, payload, status, attempts, response_status, error, next_attempt_at, created_at, updated_at`
+)
+
+func (db *SQLManager) AddWebhook(ctx context.Context, wm *domain.WebhookSubscriptionModel) error {
+	events, err := json.Marshal(wm.Events)
+	if err != nil {
+		return err
+	}
+
+	stmt := `insert into webhook_subscription (` + webhookColumns + `) values (?, ?, ?, ?, ?, ?)`
+	_, err = db.exec(ctx, rowsOf("webhook_subscription", "webhook_id = ?", wm.WebhookId), stmt, wm.WebhookId, wm.UserId, wm.URL, string(events), wm.Secret, wm.CreatedAt)
+	if err != nil {
+		log.Println("Error saving the webhook to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetWebhook(webhookId uuid.UUID) (domain.WebhookSubscriptionModel, error) {
+	stmt := `select ` + webhookColumns + ` from webhook_subscription where webhook_id = ?`
+	webhook, err := scanWebhook(db.DB.QueryRow(stmt, webhookId))
+	if err != nil {
+		log.Println("Error retrieving webhook:", err)
+		return webhook, err
+	}
+	return webhook, nil
+}
+
+func (db *SQLManager) GetWebhooksByUserId(userId uuid.UUID) ([]domain.WebhookSubscriptionModel, error) {
+	stmt := `select ` + webhookColumns + ` from webhook_subscription where user_id = ? order by created_at`
+	rows, err := db.DB.Query(stmt, userId)
+	if err != nil {
+		log.Println("Error retrieving webhooks:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []domain.WebhookSubscriptionModel
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			log.Println("Error reading webhook row:", err)
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook removes the subscription with its delivery log.
+func (db *SQLManager) DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.track(rowsOf("webhook_subscription", "webhook_id = ?", webhookId))
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range []string{`delete from webhook_delivery where webhook_id = ?`, `delete from webhook_subscription where webhook_id = ?`} {
+		_, err = tx.Exec(stmt, webhookId)
+		if err != nil {
+			log.Println("Error deleting webhook:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (db *SQLManager) AddWebhookDelivery(dm *domain.WebhookDeliveryModel) error {
+	stmt := `insert into webhook_delivery (` + deliveryColumns + `) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.DB.Exec(stmt, dm.DeliveryId, dm.WebhookId, dm.UserId, dm.Event, dm.Payload, dm.Status, dm.Attempts, dm.ResponseStatus, dm.Error, dm.NextAttemptAt, dm.CreatedAt, dm.UpdatedAt)
+	if err != nil {
+		log.Println("Error saving the webhook delivery to the database:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) GetWebhookDelivery(deliveryId uuid.UUID) (domain.WebhookDeliveryModel, error) {
+	stmt := `select ` + deliveryColumns + ` from webhook_delivery where delivery_id = ?`
+	delivery, err := scanDelivery(db.DB.QueryRow(stmt, deliveryId))
+	if err != nil {
+		log.Println("Error retrieving webhook delivery:", err)
+		return delivery, err
+	}
+	return delivery, nil
+}
+
+// GetWebhookDeliveries returns the latest deliveries to the webhook, newest first.
+func (db *SQLManager) GetWebhookDeliveries(webhookId uuid.UUID, limit int) ([]domain.WebhookDeliveryModel, error) {
+	stmt := `select ` + deliveryColumns + ` from webhook_delivery where webhook_id = ? order by created_at desc limit ?`
+	return db.queryDeliveries(stmt, webhookId, limit)
+}
+
+// GetDueWebhookDeliveries returns pending deliveries with an attempt due at now, the longest waiting first.
+func (db *SQLManager) GetDueWebhookDeliveries(now int64, limit int) ([]domain.WebhookDeliveryModel, error) {
+	stmt := `select ` + deliveryColumns + ` from webhook_delivery where status = ? and next_attempt_at <= ? order by next_attempt_at limit ?`
+	return db.queryDeliveries(stmt, domain.DELIVERY_PENDING, now, limit)
+}
+
+// UpdateWebhookDelivery saves the outcome of an attempt.
+func (db *SQLManager) UpdateWebhookDelivery(dm *domain.WebhookDeliveryModel) error {
+	stmt := `update webhook_delivery set status = ?, attempts = ?, response_status = ?, error = ?, next_attempt_at = ?, updated_at = ? where delivery_id = ?`
+	_, err := db.DB.Exec(stmt, dm.Status, dm.Attempts, dm.ResponseStatus, dm.Error, dm.NextAttemptAt, dm.UpdatedAt, dm.DeliveryId)
+	if err != nil {
+		log.Println("Error updating the webhook delivery:", err)
+		return err
+	}
+	return nil
+}
+
+func (db *SQLManager) queryDeliveries(stmt string, args ...any) ([]domain.WebhookDeliveryModel, error) {
+	rows, err := db.DB.Query(stmt, args...)
+	if err != nil {
+		log.Println("Error retrieving webhook deliveries:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDeliveryModel
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			log.Println("Error reading webhook delivery row:", err)
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanWebhook(row rowScanner) (domain.WebhookSubscriptionModel, error) {
+	var webhook domain.WebhookSubscriptionModel
+	var events string
+	err := row.Scan(&webhook.WebhookId, &webhook.UserId, &webhook.URL, &events, &webhook.Secret, &webhook.CreatedAt)
+	if err != nil {
+		return webhook, err
+	}
+	err = json.Unmarshal([]byte(events), &webhook.Events)
+	return webhook, err
+}
+
+func scanDelivery(row rowScanner) (domain.WebhookDeliveryModel, error) {
+	var delivery domain.WebhookDeliveryModel
+	err := row.Scan(&delivery.DeliveryId, &delivery.WebhookId, &delivery.UserId, &delivery.Event, &delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.ResponseStatus, &delivery.Error, &delivery.NextAttemptAt, &delivery.CreatedAt, &delivery.UpdatedAt)
+	return delivery, err
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestAddWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	wm := domain.WebhookSubscriptionModel{WebhookId: uuid.New(), UserId: uuid.New(), URL: "https://tools.finance.test/hook", Events: []domain.WebhookEvent{domain.TRANSACTION_CREATED, domain.USER_UPDATED}, Secret: "0123456789abcdef", CreatedAt: 11}
+	mock.ExpectExec("insert into webhook_subscription").
+		WithArgs(wm.WebhookId, wm.UserId, wm.URL, `["transaction.created","user.updated"]`, wm.Secret, wm.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = udb.AddWebhook(context.Background(), &wm)
+	if err != nil {
+		t.Fatal("Error saving the webhook:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetWebhooksByUserId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	userId := uuid.New()
+	rows := sqlmock.NewRows([]string{"webhook_id", "user_id", "url", "events", "secret", "created_at"}).
+		AddRow(uuid.New(), userId, "https://tools.finance.test/hook", `["transaction.deleted"]`, "0123456789abcdef", 11)
+	mock.ExpectQuery("select (.+) from webhook_subscription where user_id = \\? order by created_at").
+		WithArgs(userId).
+		WillReturnRows(rows)
+
+	webhooks, err := udb.GetWebhooksByUserId(userId)
+	if err != nil {
+		t.Fatal("Error retrieving the webhooks:", err)
+	}
+	if len(webhooks) != 1 || len(webhooks[0].Events) != 1 || webhooks[0].Events[0] != domain.TRANSACTION_DELETED {
+		t.Errorf("Unexpected webhooks %+v", webhooks)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	webhookId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from webhook_delivery where webhook_id = \\?").WithArgs(webhookId).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("delete from webhook_subscription where webhook_id = \\?").WithArgs(webhookId).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = udb.DeleteWebhook(context.Background(), webhookId)
+	if err != nil {
+		t.Fatal("Error deleting the webhook:", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
+
+func TestGetDueWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("Error creating SQL stub", err)
+	}
+	defer db.Close()
+	udb := SQLManager{DB: db}
+
+	rows := sqlmock.NewRows([]string{"delivery_id", "webhook_id", "user_id", "event", "payload", "status", "attempts", "response_status", "error", "next_attempt_at", "created_at", "updated_at"}).
+		AddRow(uuid.New(), uuid.New(), uuid.New(), "transaction.created", `{"id":"1"}`, domain.DELIVERY_PENDING, 2, 503, "webhook returned 503 Service Unavailable", 150, 100, 120)
+	mock.ExpectQuery("select (.+) from webhook_delivery where status = \\? and next_attempt_at <= \\? order by next_attempt_at limit \\?").
+		WithArgs(domain.DELIVERY_PENDING, int64(200), 50).
+		WillReturnRows(rows)
+
+	deliveries, err := udb.GetDueWebhookDeliveries(200, 50)
+	if err != nil {
+		t.Fatal("Error retrieving the due deliveries:", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Event != domain.TRANSACTION_CREATED || deliveries[0].Attempts != 2 || deliveries[0].ResponseStatus != 503 {
+		t.Errorf("Unexpected deliveries %+v", deliveries)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal("Expectations were not met:", err)
+	}
+}
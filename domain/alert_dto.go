@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type AlertRuleDTO struct {
+	AlertRuleId uuid.UUID      `json:"alertRuleId"`
+	UserId      uuid.UUID      `json:"userId" validate:"required"`
+	Type        AlertRuleType  `json:"type" validate:"gte=0,lte=2"`
+	CategoryId  int64          `json:"categoryId" validate:"gte=0"`
+	AccountId   int64          `json:"accountId" validate:"gte=0"`
+	Percent     float64        `json:"percent" validate:"gte=0"`
+	Balance     float64        `json:"balance"`
+	Channels    []AlertChannel `json:"channels" validate:"dive,gte=0,lte=1"`
+	WebhookURL  string         `json:"webhookUrl" validate:"omitempty,url"`
+	CreatedAt   int64          `json:"createdAt"`
+}
+
+type AlertRuleData struct {
+	Validator *validator.Validate
+	Rule      AlertRuleDTO
+}
+
+func (a *AlertRuleData) ValidateAlertRule() error {
+	err := a.Validator.Struct(a.Rule)
+	if err != nil {
+		log.Printf("Alert rule validation failed, %v. AlertRuleDTO: %v\n", err, a.Rule)
+		return err
+	}
+	return nil
+}
+
+type AlertDTO struct {
+	AlertId     uuid.UUID `json:"alertId"`
+	AlertRuleId uuid.UUID `json:"alertRuleId"`
+	UserId      uuid.UUID `json:"userId"`
+	Period      string    `json:"period"`
+	CategoryId  int64     `json:"categoryId"`
+	AccountId   int64     `json:"accountId"`
+	Message     string    `json:"message"`
+	Read        bool      `json:"read"`
+	CreatedAt   int64     `json:"createdAt"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "github.com/google/uuid"
+
+type AlertRuleType int
+
+const (
+	BUDGET_THRESHOLD AlertRuleType = iota
+	LOW_BALANCE
+	ANOMALY
+)
+
+// AlertChannel is where an alert is delivered besides the in-app inbox, which receives every alert.
+type AlertChannel int
+
+const (
+	ALERT_EMAIL AlertChannel = iota
+	ALERT_WEBHOOK
+)
+
+// AlertRuleModel fires when a category has used Percent of its monthly budget (BUDGET_THRESHOLD),
+// when the balance of an account drops below Balance (LOW_BALANCE), or when spending anomalies are
+// found (ANOMALY). A CategoryId of 0 watches every category.
+type AlertRuleModel struct {
+	AlertRuleId uuid.UUID
+	UserId      uuid.UUID
+	Type        AlertRuleType
+	CategoryId  int64
+	AccountId   int64
+	Percent     float64
+	Balance     float64
+	Channels    []AlertChannel
+	WebhookURL  string
+	CreatedAt   int64
+}
+
+// AlertModel is a fired alert. A rule fires at most once per Period for the same category or
+// account, the month ("2006-01") for budgets, the day ("2006-01-02") for balances and the anomaly
+// id for anomalies.
+type AlertModel struct {
+	AlertId     uuid.UUID
+	AlertRuleId uuid.UUID
+	UserId      uuid.UUID
+	Period      string
+	CategoryId  int64
+	AccountId   int64
+	Message     string
+	Read        bool
+	CreatedAt   int64
+}
+
+func (a AlertRuleType) String() string {
+	switch a {
+	case BUDGET_THRESHOLD:
+		return "BUDGET_THRESHOLD"
+	case LOW_BALANCE:
+		return "LOW_BALANCE"
+	case ANOMALY:
+		return "ANOMALY"
+	default:
+		return "Unknown"
+	}
+}
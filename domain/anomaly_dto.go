@@ -0,0 +1,18 @@
+package domain
+
+import "github.com/google/uuid"
+
+type AnomalyDTO struct {
+	AnomalyId     uuid.UUID   `json:"anomalyId"`
+	UserId        uuid.UUID   `json:"userId"`
+	Kind          AnomalyKind `json:"kind"`
+	TransactionId uuid.UUID   `json:"transactionId"`
+	CategoryId    int64       `json:"categoryId"`
+	PayeeId       uuid.UUID   `json:"payeeId"`
+	Month         string      `json:"month"`
+	Amount        float64     `json:"amount"`
+	Expected      float64     `json:"expected"`
+	Score         float64     `json:"score"`
+	Message       string      `json:"message"`
+	CreatedAt     int64       `json:"createdAt"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "github.com/google/uuid"
+
+type AnomalyKind int
+
+const (
+	// UNUSUAL_PAYEE_AMOUNT is a charge well above what the payee usually charges.
+	UNUSUAL_PAYEE_AMOUNT AnomalyKind = iota
+	// UNUSUAL_CATEGORY_AMOUNT is a single expense well above the usual ones in its category.
+	UNUSUAL_CATEGORY_AMOUNT
+	// REPEATED_CHARGE is a second charge in a month from a payee that charges once a month.
+	REPEATED_CHARGE
+	// UNUSUAL_CATEGORY_MONTH is a month where a category was spent well above its usual total.
+	UNUSUAL_CATEGORY_MONTH
+)
+
+// AnomalyModel is a transaction, or for UNUSUAL_CATEGORY_MONTH a month of a category, that stands
+// out from the users history. Expected is the baseline (the median of the history) and Score the
+// number of deviations Amount is above it, or for REPEATED_CHARGE the number of charges in the
+// month. TransactionId is uuid.Nil for months.
+type AnomalyModel struct {
+	AnomalyId     uuid.UUID
+	UserId        uuid.UUID
+	Kind          AnomalyKind
+	TransactionId uuid.UUID
+	CategoryId    int64
+	PayeeId       uuid.UUID
+	Month         string
+	Amount        float64
+	Expected      float64
+	Score         float64
+	Message       string
+	CreatedAt     int64
+}
+
+func (a AnomalyKind) String() string {
+	switch a {
+	case UNUSUAL_PAYEE_AMOUNT:
+		return "UNUSUAL_PAYEE_AMOUNT"
+	case UNUSUAL_CATEGORY_AMOUNT:
+		return "UNUSUAL_CATEGORY_AMOUNT"
+	case REPEATED_CHARGE:
+		return "REPEATED_CHARGE"
+	case UNUSUAL_CATEGORY_MONTH:
+		return "UNUSUAL_CATEGORY_MONTH"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "github.com/google/uuid"
+
+type AttachmentDTO struct {
+	AttachmentId  uuid.UUID `json:"attachmentId"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	UserId        uuid.UUID `json:"userId"`
+	FileName      string    `json:"fileName"`
+	ContentType   string    `json:"contentType"`
+	Size          int64     `json:"size"`
+	Checksum      string    `json:"checksum"`
+	CreatedAt     int64     `json:"createdAt"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "github.com/google/uuid"
+
+// AttachmentModel is a receipt or document kept with a transaction. The contents are stored
+// once per Checksum, the hex SHA-256 of the file, and shared by every attachment with that checksum.
+type AttachmentModel struct {
+	AttachmentId  uuid.UUID
+	TransactionId uuid.UUID
+	UserId        uuid.UUID
+	FileName      string
+	ContentType   string
+	Size          int64
+	Checksum      string
+	CreatedAt     int64
+}
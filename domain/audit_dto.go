@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+type AuditEntryDTO struct {
+	Seq       int64           `json:"seq"`
+	AuditId   uuid.UUID       `json:"auditId"`
+	UserId    uuid.UUID       `json:"userId"`
+	Actor     string          `json:"actor"`
+	Action    AuditAction     `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityId  string          `json:"entityId"`
+	Diff      json.RawMessage `json:"diff"`
+	RequestId string          `json:"requestId"`
+	CreatedAt int64           `json:"createdAt"`
+	PrevHash  string          `json:"prevHash"`
+	Hash      string          `json:"hash"`
+}
+
+// AuditVerificationDTO is the outcome of checking the hash chain of the audit log. When the
+// chain is broken, BrokenAt is the first entry that does not match and Reason says why. The
+// chain can not show entries removed from its end, LastSeq and LastHash are there to be
+// compared with an earlier verification.
+type AuditVerificationDTO struct {
+	Valid    bool   `json:"valid"`
+	Entries  int64  `json:"entries"`
+	LastSeq  int64  `json:"lastSeq"`
+	LastHash string `json:"lastHash"`
+	BrokenAt int64  `json:"brokenAt,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AUDIT_CREATE AuditAction = "create"
+	AUDIT_UPDATE AuditAction = "update"
+	AUDIT_DELETE AuditAction = "delete"
+)
+
+// AuditSystemActor is the actor of the writes made outside of a request, by the background jobs
+// and the event subscribers.
+const AuditSystemActor = "system"
+
+// AuditEntryModel records one change to one entity, the row EntityId of Entity belonging to the
+// user UserId. Diff is the JSON {"before": {...}, "after": {...}} of the columns that changed,
+// before is null for creates and after is null for deletes. Entries are numbered by Seq without gaps and chained
+// by Hash, which covers the entry and the Hash of the entry before it, PrevHash.
+type AuditEntryModel struct {
+	Seq       int64
+	AuditId   uuid.UUID
+	UserId    uuid.UUID
+	Actor     string
+	Action    AuditAction
+	Entity    string
+	EntityId  string
+	Diff      string
+	RequestId string
+	CreatedAt int64
+	PrevHash  string
+	Hash      string
+}
+
+// AuditScope is the author of the writes made with a context, the actor and the request it
+// served.
+type AuditScope struct {
+	Actor     string
+	RequestId string
+}
+
+type auditScopeKey struct{}
+
+// WithAuditScope returns a copy of ctx whose writes are recorded in the audit log as made by scope.
+func WithAuditScope(ctx context.Context, scope AuditScope) context.Context {
+	return context.WithValue(ctx, auditScopeKey{}, scope)
+}
+
+// AuditScopeFrom returns the scope of ctx, the system actor when it has none.
+func AuditScopeFrom(ctx context.Context) AuditScope {
+	if scope, ok := ctx.Value(auditScopeKey{}).(AuditScope); ok {
+		return scope
+	}
+	return AuditScope{Actor: AuditSystemActor}
+}
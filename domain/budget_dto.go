@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type BudgetDTO struct {
+	BudgetId   uuid.UUID    `json:"budgetId"`
+	UserId     uuid.UUID    `json:"userId" validate:"required"`
+	CategoryId int64        `json:"categoryId" validate:"required"`
+	Month      string       `json:"month" validate:"required,datetime=2006-01"`
+	Amount     float64      `json:"amount" validate:"gte=0"`
+	Rollover   RolloverMode `json:"rollover" validate:"gte=0,lte=3"`
+	CreatedAt  int64        `json:"createdAt"`
+}
+
+type BudgetData struct {
+	Validator *validator.Validate
+	Budget    BudgetDTO
+}
+
+func (b *BudgetData) ValidateBudget() error {
+	err := b.Validator.Struct(b.Budget)
+	if err != nil {
+		log.Printf("Budget validation failed, %v. BudgetDTO: %v\n", err, b.Budget)
+		return err
+	}
+	return nil
+}
+
+// BudgetCategoryDTO compares one categories budget with its spending for a month.
+// Available is Budgeted plus RolledOver, Remaining is Available minus Actual.
+type BudgetCategoryDTO struct {
+	CategoryId int64        `json:"categoryId"`
+	Budgeted   float64      `json:"budgeted"`
+	RolledOver float64      `json:"rolledOver"`
+	Available  float64      `json:"available"`
+	Actual     float64      `json:"actual"`
+	Remaining  float64      `json:"remaining"`
+	Rollover   RolloverMode `json:"rollover"`
+}
+
+// BudgetReportDTO is the budget of every category for a month. Categories with spending but
+// no budget are included with nothing budgeted.
+type BudgetReportDTO struct {
+	UserId     uuid.UUID           `json:"userId"`
+	Month      string              `json:"month"`
+	Categories []BudgetCategoryDTO `json:"categories"`
+	Budgeted   float64             `json:"budgeted"`
+	RolledOver float64             `json:"rolledOver"`
+	Actual     float64             `json:"actual"`
+	Remaining  float64             `json:"remaining"`
+}
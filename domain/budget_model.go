@@ -0,0 +1,40 @@
+package domain
+
+import "github.com/google/uuid"
+
+// RolloverMode decides what part of a months remaining budget is carried into the next month.
+type RolloverMode int
+
+const (
+	ROLLOVER_NONE RolloverMode = iota
+	ROLLOVER_UNSPENT
+	ROLLOVER_OVERSPENT
+	ROLLOVER_ALL
+)
+
+// BudgetModel assigns an amount to a category from Month ("2006-01") on. It applies to every
+// following month until another budget is assigned to the category.
+type BudgetModel struct {
+	BudgetId   uuid.UUID
+	UserId     uuid.UUID
+	CategoryId int64
+	Month      string
+	Amount     float64
+	Rollover   RolloverMode
+	CreatedAt  int64
+}
+
+func (r RolloverMode) String() string {
+	switch r {
+	case ROLLOVER_NONE:
+		return "ROLLOVER_NONE"
+	case ROLLOVER_UNSPENT:
+		return "ROLLOVER_UNSPENT"
+	case ROLLOVER_OVERSPENT:
+		return "ROLLOVER_OVERSPENT"
+	case ROLLOVER_ALL:
+		return "ROLLOVER_ALL"
+	default:
+		return "Unknown"
+	}
+}
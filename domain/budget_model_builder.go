@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	gen "github.com/pallinder/go-randomdata"
+)
+
+type BudgetModelBuild struct {
+	userId     uuid.UUID
+	categoryId int64
+	month      string
+	amount     float64
+	rollover   RolloverMode
+}
+
+func BudgetModelBuilder() *BudgetModelBuild {
+	return &BudgetModelBuild{
+		userId:     uuid.New(),
+		categoryId: int64(gen.Number(1, 15)),
+		month:      time.Now().UTC().Format("2006-01"),
+		amount:     float64(gen.Number(50, 1000)),
+		rollover:   ROLLOVER_NONE,
+	}
+}
+
+func (b *BudgetModelBuild) Build() BudgetModel {
+	return BudgetModel{
+		BudgetId:   uuid.New(),
+		UserId:     b.userId,
+		CategoryId: b.categoryId,
+		Month:      b.month,
+		Amount:     b.amount,
+		Rollover:   b.rollover,
+		CreatedAt:  time.Now().UnixMilli(),
+	}
+}
+
+func (b *BudgetModelBuild) WithUserId(userId uuid.UUID) *BudgetModelBuild {
+	b.userId = userId
+	return b
+}
+
+func (b *BudgetModelBuild) WithCategoryId(categoryId int64) *BudgetModelBuild {
+	b.categoryId = categoryId
+	return b
+}
+
+func (b *BudgetModelBuild) WithMonth(month string) *BudgetModelBuild {
+	b.month = month
+	return b
+}
+
+func (b *BudgetModelBuild) WithAmount(amount float64) *BudgetModelBuild {
+	b.amount = amount
+	return b
+}
+
+func (b *BudgetModelBuild) WithRollover(rollover RolloverMode) *BudgetModelBuild {
+	b.rollover = rollover
+	return b
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type CardAccountDTO struct {
+	CardId         uuid.UUID `json:"cardId"`
+	UserId         uuid.UUID `json:"userId" validate:"required"`
+	AccountId      int64     `json:"accountId" validate:"required"`
+	Name           string    `json:"name" validate:"required"`
+	ClosingDay     int       `json:"closingDay" validate:"gte=1,lte=31"`
+	DueDay         int       `json:"dueDay" validate:"gte=1,lte=31"`
+	MinimumPercent float64   `json:"minimumPercent" validate:"gte=0,lte=100"`
+	MinimumAmount  float64   `json:"minimumAmount" validate:"gte=0"`
+	CreatedAt      int64     `json:"createdAt"`
+}
+
+type CardAccountData struct {
+	Validator *validator.Validate
+	Card      CardAccountDTO
+}
+
+func (c *CardAccountData) ValidateCard() error {
+	err := c.Validator.Struct(c.Card)
+	if err != nil {
+		log.Printf("Card validation failed, %v. CardAccountDTO: %v\n", err, c.Card)
+		return err
+	}
+	return nil
+}
+
+// StatementDTO is one closed statement cycle of a card, from PeriodStart through the end of
+// the UTC day ClosingDate. Balance is what was owed when it closed, Paid the payments made
+// after it closed up to the end of DueDate and Remaining what is left of the balance.
+type StatementDTO struct {
+	CardId          uuid.UUID       `json:"cardId"`
+	PeriodStart     int64           `json:"periodStart"`
+	ClosingDate     int64           `json:"closingDate"`
+	DueDate         int64           `json:"dueDate"`
+	PreviousBalance float64         `json:"previousBalance"`
+	Charges         float64         `json:"charges"`
+	Credits         float64         `json:"credits"`
+	Balance         float64         `json:"balance"`
+	MinimumPayment  float64         `json:"minimumPayment"`
+	Paid            float64         `json:"paid"`
+	Remaining       float64         `json:"remaining"`
+	Status          StatementStatus `json:"status"`
+}
+
+// CardStatementsDTO is the statements of a card, newest first, and the balance of the cycle
+// still open.
+type CardStatementsDTO struct {
+	Card           CardAccountDTO `json:"card"`
+	Statements     []StatementDTO `json:"statements"`
+	CurrentBalance float64        `json:"currentBalance"`
+	NextClosing    int64          `json:"nextClosing"`
+}
+
+// UpcomingPaymentDTO is a card statement that still has a balance to pay.
+type UpcomingPaymentDTO struct {
+	CardId         uuid.UUID       `json:"cardId"`
+	Name           string          `json:"name"`
+	DueDate        int64           `json:"dueDate"`
+	Balance        float64         `json:"balance"`
+	MinimumPayment float64         `json:"minimumPayment"`
+	Paid           float64         `json:"paid"`
+	Remaining      float64         `json:"remaining"`
+	Status         StatementStatus `json:"status"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "github.com/google/uuid"
+
+// StatementStatus is how far a closed statement has been paid.
+type StatementStatus int
+
+const (
+	STATEMENT_DUE          StatementStatus = iota // not due yet and not paid in full.
+	STATEMENT_MINIMUM_PAID                        // at least the minimum payment was made by the due date.
+	STATEMENT_PAID                                // paid in full, or nothing was owed.
+	STATEMENT_OVERDUE                             // the due date passed without the minimum payment.
+)
+
+// CardAccountModel is a credit card billed through the account AccountId. A statement closes
+// at the end of ClosingDay every month, or the last day of shorter months, and is due on the
+// first DueDay after it. Expenses on the account are charges and income either payments or,
+// when paid with CREDIT_CARD, refunds. The minimum payment is MinimumPercent of the statement
+// balance but at least MinimumAmount.
+type CardAccountModel struct {
+	CardId         uuid.UUID
+	UserId         uuid.UUID
+	AccountId      int64
+	Name           string
+	ClosingDay     int
+	DueDay         int
+	MinimumPercent float64
+	MinimumAmount  float64
+	CreatedAt      int64
+}
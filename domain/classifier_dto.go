@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type CategorySuggestionDTO struct {
+	CategoryId int64   `json:"categoryId"`
+	Confidence float64 `json:"confidence"`
+}
+
+// CategoryCorrectionDTO sets the category of a saved transaction, the classifier learns from it.
+type CategoryCorrectionDTO struct {
+	TransactionId uuid.UUID `json:"transactionId" validate:"required"`
+	CategoryId    int64     `json:"categoryId" validate:"required"`
+}
+
+type CategoryCorrectionData struct {
+	Validator  *validator.Validate
+	Correction CategoryCorrectionDTO
+}
+
+func (c *CategoryCorrectionData) ValidateCategoryCorrection() error {
+	err := c.Validator.Struct(c.Correction)
+	if err != nil {
+		log.Printf("Category correction validation failed, %v. CorrectionDTO: %v\n", err, c.Correction)
+		return err
+	}
+	return nil
+}
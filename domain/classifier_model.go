@@ -0,0 +1,26 @@
+package domain
+
+import "github.com/google/uuid"
+
+// ClassifierModel holds the word counts of a users naive Bayes category classifier.
+// Tokens come from the transaction description plus a few derived tokens for the amount and type.
+type ClassifierModel struct {
+	UserId         uuid.UUID
+	CategoryCounts map[int64]int            // transactions seen per category
+	TokenCounts    map[int64]map[string]int // token occurrences per category
+	TokenTotals    map[int64]int            // total tokens per category
+	Vocabulary     map[string]int           // occurrences of every token across categories
+	Learned        map[uuid.UUID]int64      // the category each learned transaction is counted in
+	UpdatedAt      int64
+}
+
+func NewClassifierModel(userId uuid.UUID) ClassifierModel {
+	return ClassifierModel{
+		UserId:         userId,
+		CategoryCounts: map[int64]int{},
+		TokenCounts:    map[int64]map[string]int{},
+		TokenTotals:    map[int64]int{},
+		Vocabulary:     map[string]int{},
+		Learned:        map[uuid.UUID]int64{},
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type BaseCurrencyDTO struct {
+	UserId   uuid.UUID `json:"userId" validate:"required"`
+	Currency string    `json:"currency" validate:"required,iso4217"`
+}
+
+type BaseCurrencyData struct {
+	Validator    *validator.Validate
+	BaseCurrency BaseCurrencyDTO
+}
+
+func (b *BaseCurrencyData) ValidateBaseCurrency() error {
+	err := b.Validator.Struct(b.BaseCurrency)
+	if err != nil {
+		log.Printf("Base currency validation failed, %v. BaseCurrencyDTO: %v\n", err, b.BaseCurrency)
+		return err
+	}
+	return nil
+}
+
+type AccountCurrencyDTO struct {
+	UserId    uuid.UUID `json:"userId" validate:"required"`
+	AccountId int64     `json:"accountId" validate:"required"`
+	Currency  string    `json:"currency" validate:"required,iso4217"`
+}
+
+type AccountCurrencyData struct {
+	Validator       *validator.Validate
+	AccountCurrency AccountCurrencyDTO
+}
+
+func (a *AccountCurrencyData) ValidateAccountCurrency() error {
+	err := a.Validator.Struct(a.AccountCurrency)
+	if err != nil {
+		log.Printf("Account currency validation failed, %v. AccountCurrencyDTO: %v\n", err, a.AccountCurrency)
+		return err
+	}
+	return nil
+}
+
+// BaseCurrencyChangeDTO reports how many transactions were converted again into the new base currency.
+type BaseCurrencyChangeDTO struct {
+	UserId       uuid.UUID `json:"userId"`
+	Currency     string    `json:"currency"`
+	Transactions int       `json:"transactions"`
+}
+
+// RateImportDTO reports what an exchange rate import saved, the rates of how many currencies
+// over which days.
+type RateImportDTO struct {
+	Rates      int    `json:"rates"`
+	Currencies int    `json:"currencies"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// ConversionDTO is Amount in From converted into To with the rates published on or before Date.
+// Rate is the number of To one From buys.
+type ConversionDTO struct {
+	Amount    float64 `json:"amount"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Date      int64   `json:"date"`
+	Rate      float64 `json:"rate"`
+	Converted float64 `json:"converted"`
+}
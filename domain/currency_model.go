@@ -0,0 +1,35 @@
+package domain
+
+import "github.com/google/uuid"
+
+// RateBaseCurrency is the currency exchange rates are quoted against, the ECB publishes
+// euro reference rates.
+const RateBaseCurrency = "EUR"
+
+// DefaultBaseCurrency is the base currency of users who have not chosen one.
+const DefaultBaseCurrency = "EUR"
+
+// RateDateLayout is the format of ExchangeRateModel.Date, the day the rate was published.
+const RateDateLayout = "2006-01-02"
+
+// ExchangeRateModel is how many units of Currency one euro bought on Date.
+type ExchangeRateModel struct {
+	Currency string
+	Date     string
+	Rate     float64
+}
+
+// AccountCurrencyModel is the currency of an account, used for its transactions that do not
+// give one. Accounts without one are in the users base currency.
+type AccountCurrencyModel struct {
+	UserId    uuid.UUID
+	AccountId int64
+	Currency  string
+}
+
+// BaseCurrencyModel is the currency the users balances and reports are in. Transaction
+// amounts are stored converted into it, next to the original amount.
+type BaseCurrencyModel struct {
+	UserId   uuid.UUID
+	Currency string
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type DigestScheduleDTO struct {
+	UserId     uuid.UUID       `json:"userId" validate:"required"`
+	Frequency  DigestFrequency `json:"frequency" validate:"gte=0,lte=1"`
+	Day        int             `json:"day" validate:"gte=0,lte=31"`
+	Hour       int             `json:"hour" validate:"gte=0,lte=23"`
+	Minute     int             `json:"minute" validate:"gte=0,lte=59"`
+	Timezone   string          `json:"timezone" validate:"required,timezone"`
+	NextRunAt  int64           `json:"nextRunAt"`
+	LastSentAt int64           `json:"lastSentAt"`
+	CreatedAt  int64           `json:"createdAt"`
+}
+
+type DigestScheduleData struct {
+	Validator *validator.Validate
+	Schedule  DigestScheduleDTO
+}
+
+func (d *DigestScheduleData) ValidateDigestSchedule() error {
+	err := d.Validator.Struct(d.Schedule)
+	if err != nil {
+		log.Printf("Digest schedule validation failed, %v. DigestScheduleDTO: %v\n", err, d.Schedule)
+		return err
+	}
+	return nil
+}
+
+// DigestBudgetDTO is the budget status of the month the digest ends in, with the categories
+// that have spent more than they had available.
+type DigestBudgetDTO struct {
+	Month      string              `json:"month"`
+	Budgeted   float64             `json:"budgeted"`
+	Actual     float64             `json:"actual"`
+	Remaining  float64             `json:"remaining"`
+	OverBudget []BudgetCategoryDTO `json:"overBudget"`
+}
+
+// DigestDTO summarizes the users spending from From up to, not including, To. Budget is nil
+// when the user has no budgets. Subject, Text and HTML are the email as it is sent.
+type DigestDTO struct {
+	UserId        uuid.UUID           `json:"userId"`
+	Frequency     DigestFrequency     `json:"frequency"`
+	From          int64               `json:"from"`
+	To            int64               `json:"to"`
+	Totals        ReportTotalsDTO     `json:"totals"`
+	TopCategories []ReportCategoryDTO `json:"topCategories"`
+	Budget        *DigestBudgetDTO    `json:"budget"`
+	Unusual       []AnomalyDTO        `json:"unusual"`
+	Subject       string              `json:"subject"`
+	Text          string              `json:"text"`
+	HTML          string              `json:"html"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "github.com/google/uuid"
+
+// DigestFrequency is how often a spending digest is emailed.
+type DigestFrequency int
+
+const (
+	DIGEST_WEEKLY DigestFrequency = iota
+	DIGEST_MONTHLY
+)
+
+// DigestScheduleModel emails the user a digest of their spending every week on Day (0 for
+// Sunday to 6 for Saturday) or every month on Day (1 to 31, the last day of shorter months),
+// at Hour:Minute in Timezone, an IANA name like "Europe/Berlin". A user has one schedule.
+// NextRunAt is when the next digest is due and LastSentAt when the last one went out, 0 before.
+type DigestScheduleModel struct {
+	UserId     uuid.UUID
+	Frequency  DigestFrequency
+	Day        int
+	Hour       int
+	Minute     int
+	Timezone   string
+	NextRunAt  int64
+	LastSentAt int64
+	CreatedAt  int64
+}
+
+func (f DigestFrequency) String() string {
+	switch f {
+	case DIGEST_WEEKLY:
+		return "DIGEST_WEEKLY"
+	case DIGEST_MONTHLY:
+		return "DIGEST_MONTHLY"
+	}
+	return "UNKNOWN"
+}
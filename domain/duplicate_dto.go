@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type DuplicateDTO struct {
+	DuplicateId   uuid.UUID       `json:"duplicateId"`
+	UserId        uuid.UUID       `json:"userId"`
+	TransactionId uuid.UUID       `json:"transactionId"`
+	CandidateId   uuid.UUID       `json:"candidateId"`
+	Score         float64         `json:"score"`
+	Reason        string          `json:"reason"`
+	Status        DuplicateStatus `json:"status"`
+	CreatedAt     int64           `json:"createdAt"`
+}
+
+// DuplicateResolutionDTO is sent to merge or dismiss a suspected duplicate.
+// KeepTransactionId is only used when merging and must be one of the pair.
+type DuplicateResolutionDTO struct {
+	DuplicateId       uuid.UUID `json:"duplicateId" validate:"required"`
+	KeepTransactionId uuid.UUID `json:"keepTransactionId"`
+}
+
+type DuplicateResolutionData struct {
+	Validator  *validator.Validate
+	Resolution DuplicateResolutionDTO
+}
+
+func (d *DuplicateResolutionData) ValidateDuplicateResolution() error {
+	err := d.Validator.Struct(d.Resolution)
+	if err != nil {
+		log.Printf("Duplicate resolution validation failed, %v. ResolutionDTO: %v\n", err, d.Resolution)
+		return err
+	}
+	return nil
+}
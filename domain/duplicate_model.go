@@ -0,0 +1,24 @@
+package domain
+
+import "github.com/google/uuid"
+
+type DuplicateStatus int
+
+const (
+	SUSPECTED DuplicateStatus = iota
+	MERGED
+	DISMISSED
+)
+
+// DuplicateModel is a pair of transactions that look like the same purchase.
+// TransactionId is the newer of the two, CandidateId the one it was matched against.
+type DuplicateModel struct {
+	DuplicateId   uuid.UUID
+	UserId        uuid.UUID
+	TransactionId uuid.UUID
+	CandidateId   uuid.UUID
+	Score         float64
+	Reason        string
+	Status        DuplicateStatus
+	CreatedAt     int64
+}
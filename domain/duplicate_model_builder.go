@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	gen "github.com/pallinder/go-randomdata"
+)
+
+type DuplicateModelBuild struct {
+	userId uuid.UUID
+	status DuplicateStatus
+}
+
+func DuplicateModelBuilder() *DuplicateModelBuild {
+	return &DuplicateModelBuild{
+		userId: uuid.New(),
+		status: SUSPECTED,
+	}
+}
+
+func (b *DuplicateModelBuild) Build() DuplicateModel {
+	return DuplicateModel{
+		DuplicateId:   uuid.New(),
+		UserId:        b.userId,
+		TransactionId: uuid.New(),
+		CandidateId:   uuid.New(),
+		Score:         float64(gen.Number(70, 100)) / 100,
+		Reason:        "same amount, same day",
+		Status:        b.status,
+		CreatedAt:     time.Now().UnixMilli(),
+	}
+}
+
+func (b *DuplicateModelBuild) WithUserId(userId uuid.UUID) *DuplicateModelBuild {
+	b.userId = userId
+	return b
+}
+
+func (b *DuplicateModelBuild) WithStatus(status DuplicateStatus) *DuplicateModelBuild {
+	b.status = status
+	return b
+}
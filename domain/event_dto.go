@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// EventDTO is the domain event handed to the event bus subscribers. An event can be handed to
+// a subscriber more than once, EventId tells the repeats apart from new events.
+type EventDTO struct {
+	EventId     uuid.UUID       `json:"eventId"`
+	Type        EventType       `json:"type"`
+	UserId      uuid.UUID       `json:"userId"`
+	AggregateId uuid.UUID       `json:"aggregateId"`
+	Data        json.RawMessage `json:"data"`
+	CreatedAt   int64           `json:"createdAt"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "github.com/google/uuid"
+
+// EventType names a domain event. The transaction and user.updated events share their names
+// with the webhook events they are published as.
+type EventType string
+
+const (
+	EVENT_TRANSACTION_CREATED EventType = "transaction.created"
+	EVENT_TRANSACTION_UPDATED EventType = "transaction.updated"
+	EVENT_TRANSACTION_DELETED EventType = "transaction.deleted"
+	EVENT_USER_REGISTERED     EventType = "user.registered"
+	EVENT_USER_UPDATED        EventType = "user.updated"
+	EVENT_ALERT_RAISED        EventType = "alert.raised"
+)
+
+// OutboxEventModel is a domain event saved in the same SQL transaction as the change it
+// describes, about the entity AggregateId of the user. Payload is the JSON of the entity after
+// the change, or before it for deletes. The event stays pending, retried from NextAttemptAt
+// after a subscriber failed, until every subscriber handled it and PublishedAt is set.
+type OutboxEventModel struct {
+	EventId       uuid.UUID
+	Type          EventType
+	UserId        uuid.UUID
+	AggregateId   uuid.UUID
+	Payload       string
+	Attempts      int
+	NextAttemptAt int64
+	LastError     string
+	CreatedAt     int64
+	PublishedAt   int64
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type ScheduledItemDTO struct {
+	ScheduledItemId uuid.UUID         `json:"scheduledItemId"`
+	UserId          uuid.UUID         `json:"userId" validate:"required"`
+	AccountId       int64             `json:"accountId" validate:"required"`
+	Description     string            `json:"description" validate:"required"`
+	Amount          float64           `json:"amount" validate:"gt=0"`
+	Type            TransactionType   `json:"type" validate:"gte=0,lte=1"`
+	Frequency       ScheduleFrequency `json:"frequency" validate:"gte=0,lte=3"`
+	StartDate       int64             `json:"startDate" validate:"required"`
+	CreatedAt       int64             `json:"createdAt"`
+}
+
+type ScheduledItemData struct {
+	Validator     *validator.Validate
+	ScheduledItem ScheduledItemDTO
+}
+
+func (s *ScheduledItemData) ValidateScheduledItem() error {
+	err := s.Validator.Struct(s.ScheduledItem)
+	if err != nil {
+		log.Printf("Scheduled item validation failed, %v. ScheduledItemDTO: %v\n", err, s.ScheduledItem)
+		return err
+	}
+	return nil
+}
+
+// ForecastDayDTO is the projected end of day balance. Known is the net of scheduled items and
+// future dated transactions, Estimated the expected variable spending.
+type ForecastDayDTO struct {
+	Date      int64   `json:"date"`
+	Known     float64 `json:"known"`
+	Estimated float64 `json:"estimated"`
+	Balance   float64 `json:"balance"`
+}
+
+// ForecastDTO projects an accounts balance day by day, LowBalance is the lowest projected
+// balance and LowDate the first day it is reached.
+type ForecastDTO struct {
+	AccountId  int64            `json:"accountId"`
+	Balance    float64          `json:"balance"`
+	Days       []ForecastDayDTO `json:"days"`
+	LowBalance float64          `json:"lowBalance"`
+	LowDate    int64            `json:"lowDate"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type GoalDTO struct {
+	GoalId       uuid.UUID `json:"goalId"`
+	UserId       uuid.UUID `json:"userId" validate:"required"`
+	Name         string    `json:"name" validate:"required"`
+	TargetAmount float64   `json:"targetAmount" validate:"gt=0"`
+	TargetDate   int64     `json:"targetDate" validate:"required"`
+	AccountId    int64     `json:"accountId" validate:"required_without=CategoryId,excluded_with=CategoryId"`
+	CategoryId   int64     `json:"categoryId"`
+	CreatedAt    int64     `json:"createdAt"`
+}
+
+type GoalData struct {
+	Validator *validator.Validate
+	Goal      GoalDTO
+}
+
+func (g *GoalData) ValidateGoal() error {
+	err := g.Validator.Struct(g.Goal)
+	if err != nil {
+		log.Printf("Goal validation failed, %v. GoalDTO: %v\n", err, g.Goal)
+		return err
+	}
+	return nil
+}
+
+// GoalProgressDTO is how far a goal is and where it is heading at the trailing MonthlyRate.
+// ProjectedDate is 0 when nothing is being saved, RequiredMonthly is what is needed each month
+// to reach the target by the target date.
+type GoalProgressDTO struct {
+	Goal            GoalDTO `json:"goal"`
+	Saved           float64 `json:"saved"`
+	Remaining       float64 `json:"remaining"`
+	Percent         float64 `json:"percent"`
+	MonthlyRate     float64 `json:"monthlyRate"`
+	RequiredMonthly float64 `json:"requiredMonthly"`
+	ProjectedDate   int64   `json:"projectedDate"`
+	Completed       bool    `json:"completed"`
+	OnTrack         bool    `json:"onTrack"`
+}
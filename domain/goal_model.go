@@ -0,0 +1,17 @@
+package domain
+
+import "github.com/google/uuid"
+
+// GoalModel is a savings target, such as "Emergency fund: 10,000 by 2027-06". Progress is counted
+// from either the linked account, its balance, or the linked category, where expenses are money
+// set aside and income is money taken back out.
+type GoalModel struct {
+	GoalId       uuid.UUID
+	UserId       uuid.UUID
+	Name         string
+	TargetAmount float64
+	TargetDate   int64
+	AccountId    int64
+	CategoryId   int64
+	CreatedAt    int64
+}
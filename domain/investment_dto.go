@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type SecurityDTO struct {
+	SecurityId uuid.UUID `json:"securityId"`
+	UserId     uuid.UUID `json:"userId" validate:"required"`
+	Symbol     string    `json:"symbol" validate:"required,max=20"`
+	Name       string    `json:"name"`
+	CreatedAt  int64     `json:"createdAt"`
+}
+
+type SecurityData struct {
+	Validator *validator.Validate
+	Security  SecurityDTO
+}
+
+func (s *SecurityData) ValidateSecurity() error {
+	err := s.Validator.Struct(s.Security)
+	if err != nil {
+		log.Printf("Security validation failed, %v. SecurityDTO: %v\n", err, s.Security)
+		return err
+	}
+	return nil
+}
+
+type InvestmentTransactionDTO struct {
+	InvestmentId uuid.UUID         `json:"investmentId"`
+	UserId       uuid.UUID         `json:"userId" validate:"required"`
+	AccountId    int64             `json:"accountId"`
+	SecurityId   uuid.UUID         `json:"securityId" validate:"required"`
+	Kind         InvestmentKind    `json:"kind" validate:"gte=0,lte=3"`
+	Date         int64             `json:"date" validate:"required"`
+	Quantity     float64           `json:"quantity" validate:"gte=0"`
+	Price        float64           `json:"price" validate:"gte=0"`
+	Fees         float64           `json:"fees" validate:"gte=0"`
+	Amount       float64           `json:"amount" validate:"gte=0"`
+	SplitRatio   float64           `json:"splitRatio" validate:"gte=0"`
+	Method       CostBasisMethod   `json:"method" validate:"gte=0,lte=2"`
+	Lots         []LotSelectionDTO `json:"lots" validate:"dive"`
+	CreatedAt    int64             `json:"createdAt"`
+}
+
+type LotSelectionDTO struct {
+	LotId    uuid.UUID `json:"lotId" validate:"required"`
+	Quantity float64   `json:"quantity" validate:"gt=0"`
+}
+
+type InvestmentTransactionData struct {
+	Validator  *validator.Validate
+	Investment InvestmentTransactionDTO
+}
+
+func (i *InvestmentTransactionData) ValidateInvestmentTransaction() error {
+	err := i.Validator.Struct(i.Investment)
+	if err != nil {
+		log.Printf("Investment transaction validation failed, %v. InvestmentTransactionDTO: %v\n", err, i.Investment)
+		return err
+	}
+	return nil
+}
+
+// LotDTO is what is left of a lot, the shares not sold yet and their cost.
+type LotDTO struct {
+	LotId          uuid.UUID `json:"lotId"`
+	AcquiredAt     int64     `json:"acquiredAt"`
+	Quantity       float64   `json:"quantity"`
+	CostBasis      float64   `json:"costBasis"`
+	MarketValue    float64   `json:"marketValue"`
+	UnrealizedGain float64   `json:"unrealizedGain"`
+}
+
+// HoldingDTO is the open position in a security in one account. Price is the latest price on
+// or before the portfolio date, or the last trade price when none was imported.
+type HoldingDTO struct {
+	AccountId      int64     `json:"accountId"`
+	SecurityId     uuid.UUID `json:"securityId"`
+	Symbol         string    `json:"symbol"`
+	Name           string    `json:"name"`
+	Quantity       float64   `json:"quantity"`
+	CostBasis      float64   `json:"costBasis"`
+	Price          float64   `json:"price"`
+	PriceDate      string    `json:"priceDate"` // empty when Price is the last trade price.
+	MarketValue    float64   `json:"marketValue"`
+	UnrealizedGain float64   `json:"unrealizedGain"`
+	Lots           []LotDTO  `json:"lots"`
+}
+
+// PortfolioDTO is the users holdings on Date. RealizedGain and Dividends add up every sale and
+// dividend up to Date.
+type PortfolioDTO struct {
+	UserId         uuid.UUID    `json:"userId"`
+	Date           int64        `json:"date"`
+	Holdings       []HoldingDTO `json:"holdings"`
+	CostBasis      float64      `json:"costBasis"`
+	MarketValue    float64      `json:"marketValue"`
+	UnrealizedGain float64      `json:"unrealizedGain"`
+	RealizedGain   float64      `json:"realizedGain"`
+	Dividends      float64      `json:"dividends"`
+}
+
+// RealizedGainDTO is the part of a sale taken from one lot. It is long term when the shares
+// were held for more than a year.
+type RealizedGainDTO struct {
+	SellId     uuid.UUID `json:"sellId"`
+	LotId      uuid.UUID `json:"lotId"`
+	AccountId  int64     `json:"accountId"`
+	SecurityId uuid.UUID `json:"securityId"`
+	Symbol     string    `json:"symbol"`
+	Quantity   float64   `json:"quantity"`
+	AcquiredAt int64     `json:"acquiredAt"`
+	SoldAt     int64     `json:"soldAt"`
+	Proceeds   float64   `json:"proceeds"`
+	CostBasis  float64   `json:"costBasis"`
+	Gain       float64   `json:"gain"`
+	LongTerm   bool      `json:"longTerm"`
+}
+
+// PriceImportDTO reports what a price import saved, the prices of how many symbols over which days.
+type PriceImportDTO struct {
+	Prices  int    `json:"prices"`
+	Symbols int    `json:"symbols"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
@@ -0,0 +1,92 @@
+package domain
+
+import "github.com/google/uuid"
+
+type InvestmentKind int
+
+const (
+	INVESTMENT_BUY InvestmentKind = iota
+	INVESTMENT_SELL
+	INVESTMENT_DIVIDEND
+	INVESTMENT_SPLIT
+)
+
+// CostBasisMethod picks the lots a sale takes its shares from.
+type CostBasisMethod int
+
+const (
+	FIFO CostBasisMethod = iota
+	LIFO
+	SPECIFIC_ID // the sale lists the lots and quantities itself.
+)
+
+// SecurityModel is a stock, fund or other security the user holds, priced by Symbol.
+type SecurityModel struct {
+	SecurityId uuid.UUID
+	UserId     uuid.UUID
+	Symbol     string
+	Name       string
+	CreatedAt  int64
+}
+
+// InvestmentTransactionModel is a trade or corporate action on a security in a brokerage
+// account. Buys and sells use Quantity, Price and Fees, a dividend pays Amount in cash and a
+// split multiplies the shares held by SplitRatio, 2 for a 2-for-1 split. A buy opens a lot
+// identified by its InvestmentId, a sale closes shares of the lots picked by Method.
+type InvestmentTransactionModel struct {
+	InvestmentId uuid.UUID
+	UserId       uuid.UUID
+	AccountId    int64
+	SecurityId   uuid.UUID
+	Kind         InvestmentKind
+	Date         int64
+	Quantity     float64
+	Price        float64
+	Fees         float64
+	Amount       float64
+	SplitRatio   float64
+	Method       CostBasisMethod
+	Lots         []LotSelectionModel // only for SPECIFIC_ID sales.
+	CreatedAt    int64
+}
+
+// LotSelectionModel is the number of shares a sale takes from the lot opened by the buy LotId.
+type LotSelectionModel struct {
+	LotId    uuid.UUID
+	Quantity float64
+}
+
+// SecurityPriceModel is the closing price of Symbol on Date ("2006-01-02"), in the base currency.
+type SecurityPriceModel struct {
+	Symbol string
+	Date   string
+	Price  float64
+}
+
+func (i InvestmentKind) String() string {
+	switch i {
+	case INVESTMENT_BUY:
+		return "INVESTMENT_BUY"
+	case INVESTMENT_SELL:
+		return "INVESTMENT_SELL"
+	case INVESTMENT_DIVIDEND:
+		return "INVESTMENT_DIVIDEND"
+	case INVESTMENT_SPLIT:
+		return "INVESTMENT_SPLIT"
+	default:
+		return "Unknown"
+	}
+}
+
+func (c CostBasisMethod) String() string {
+	switch c {
+	case FIFO:
+		return "FIFO"
+	case LIFO:
+		return "LIFO"
+	case SPECIFIC_ID:
+		return "SPECIFIC_ID"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type LoanDTO struct {
+	LoanId        uuid.UUID         `json:"loanId"`
+	UserId        uuid.UUID         `json:"userId" validate:"required"`
+	Name          string            `json:"name" validate:"required"`
+	Principal     float64           `json:"principal" validate:"gt=0"`
+	AnnualRate    float64           `json:"annualRate" validate:"gte=0,lt=100"`
+	TermMonths    int               `json:"termMonths" validate:"gt=0,lte=600"`
+	StartDate     int64             `json:"startDate" validate:"required"`
+	AccountId     int64             `json:"accountId"`
+	PayeeId       uuid.UUID         `json:"payeeId" validate:"required_without=Match"`
+	Match         string            `json:"match"`
+	ExtraMonthly  float64           `json:"extraMonthly" validate:"gte=0"`
+	ExtraPayments []ExtraPaymentDTO `json:"extraPayments" validate:"dive"`
+	CreatedAt     int64             `json:"createdAt"`
+}
+
+type ExtraPaymentDTO struct {
+	Date   int64   `json:"date" validate:"required"`
+	Amount float64 `json:"amount" validate:"gt=0"`
+}
+
+type LoanData struct {
+	Validator *validator.Validate
+	Loan      LoanDTO
+}
+
+func (l *LoanData) ValidateLoan() error {
+	err := l.Validator.Struct(l.Loan)
+	if err != nil {
+		log.Printf("Loan validation failed, %v. LoanDTO: %v\n", err, l.Loan)
+		return err
+	}
+	return nil
+}
+
+// AmortizationRowDTO is one monthly payment of a schedule. Extra is principal paid on top of
+// the regular payment and Balance what is owed after it.
+type AmortizationRowDTO struct {
+	Number    int     `json:"number"`
+	Date      int64   `json:"date"`
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Extra     float64 `json:"extra"`
+	Balance   float64 `json:"balance"`
+}
+
+// AmortizationScheduleDTO is the planned repayment of a loan with its extra payments.
+type AmortizationScheduleDTO struct {
+	LoanId        uuid.UUID            `json:"loanId"`
+	Payment       float64              `json:"payment"`
+	Rows          []AmortizationRowDTO `json:"rows"`
+	TotalInterest float64              `json:"totalInterest"`
+	PayoffDate    int64                `json:"payoffDate"`
+}
+
+// LoanPaymentDTO is a matched payment split into the interest due and the principal repaid.
+type LoanPaymentDTO struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	Date          int64     `json:"date"`
+	Amount        float64   `json:"amount"`
+	Principal     float64   `json:"principal"`
+	Interest      float64   `json:"interest"`
+	Balance       float64   `json:"balance"`
+}
+
+// LoanStatusDTO is where the loan stands after the payments made so far. ScheduledBalance is
+// what the schedule expected to be owed by now and PayoffDate when the balance is paid off
+// with the regular payment.
+type LoanStatusDTO struct {
+	Loan             LoanDTO          `json:"loan"`
+	Payment          float64          `json:"payment"`
+	Payments         []LoanPaymentDTO `json:"payments"`
+	PrincipalPaid    float64          `json:"principalPaid"`
+	InterestPaid     float64          `json:"interestPaid"`
+	Balance          float64          `json:"balance"`
+	ScheduledBalance float64          `json:"scheduledBalance"`
+	PayoffDate       int64            `json:"payoffDate"`
+}
+
+// PayoffDTO is how a loan is paid off from its current balance, with the regular payment and
+// the loans own extra payments or with the extra payments of a scenario.
+type PayoffDTO struct {
+	PayoffDate    int64   `json:"payoffDate"`
+	Payments      int     `json:"payments"`
+	TotalInterest float64 `json:"totalInterest"`
+}
+
+// PayoffSimulationDTO compares paying off the loan as planned with a scenario paying
+// ExtraMonthly every month and LumpSum on LumpSumDate.
+type PayoffSimulationDTO struct {
+	LoanId        uuid.UUID `json:"loanId"`
+	Balance       float64   `json:"balance"`
+	ExtraMonthly  float64   `json:"extraMonthly"`
+	LumpSum       float64   `json:"lumpSum"`
+	LumpSumDate   int64     `json:"lumpSumDate"`
+	Baseline      PayoffDTO `json:"baseline"`
+	Scenario      PayoffDTO `json:"scenario"`
+	InterestSaved float64   `json:"interestSaved"`
+	MonthsSaved   int       `json:"monthsSaved"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "github.com/google/uuid"
+
+// LoanModel is a loan or mortgage repaid in equal monthly payments, the first one month after
+// StartDate. Payments are the users expenses to PayeeId, or with Match in their description
+// when there is no payee, from AccountId unless it is 0. ExtraMonthly is paid on top of every
+// payment and ExtraPayments once, both only in the planned schedule.
+type LoanModel struct {
+	LoanId        uuid.UUID
+	UserId        uuid.UUID
+	Name          string
+	Principal     float64
+	AnnualRate    float64 // in percent.
+	TermMonths    int
+	StartDate     int64
+	AccountId     int64
+	PayeeId       uuid.UUID
+	Match         string
+	ExtraMonthly  float64
+	ExtraPayments []ExtraPaymentModel
+	CreatedAt     int64
+}
+
+// ExtraPaymentModel is a one off payment of principal on Date.
+type ExtraPaymentModel struct {
+	Date   int64
+	Amount float64
+}
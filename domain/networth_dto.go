@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type ValuationDTO struct {
+	ValuationId uuid.UUID     `json:"valuationId"`
+	UserId      uuid.UUID     `json:"userId" validate:"required"`
+	Name        string        `json:"name" validate:"required"`
+	Kind        ValuationKind `json:"kind" validate:"gte=0,lte=1"`
+	Amount      float64       `json:"amount" validate:"gte=0"`
+	Date        int64         `json:"date" validate:"required"`
+	CreatedAt   int64         `json:"createdAt"`
+}
+
+type ValuationData struct {
+	Validator *validator.Validate
+	Valuation ValuationDTO
+}
+
+func (v *ValuationData) ValidateValuation() error {
+	err := v.Validator.Struct(v.Valuation)
+	if err != nil {
+		log.Printf("Valuation validation failed, %v. ValuationDTO: %v\n", err, v.Valuation)
+		return err
+	}
+	return nil
+}
+
+type NetWorthPointDTO struct {
+	Date        int64   `json:"date"`
+	Assets      float64 `json:"assets"`
+	Liabilities float64 `json:"liabilities"`
+	NetWorth    float64 `json:"netWorth"`
+}
+
+// NetWorthDTO is the net worth history between From and To, one point per day or the last
+// snapshot of each month. Change is the difference between the last and the first point.
+type NetWorthDTO struct {
+	UserId uuid.UUID          `json:"userId"`
+	From   int64              `json:"from"`
+	To     int64              `json:"to"`
+	Points []NetWorthPointDTO `json:"points"`
+	Change float64            `json:"change"`
+}
+
+// NetWorthBackfillDTO reports how many daily snapshots a backfill wrote, starting at From.
+type NetWorthBackfillDTO struct {
+	UserId    uuid.UUID `json:"userId"`
+	From      int64     `json:"from"`
+	Snapshots int       `json:"snapshots"`
+}
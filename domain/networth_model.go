@@ -0,0 +1,47 @@
+package domain
+
+import "github.com/google/uuid"
+
+type ValuationKind int
+
+const (
+	VALUATION_ASSET ValuationKind = iota
+	VALUATION_LIABILITY
+)
+
+// ValuationModel is the manually entered value of something not tracked through transactions,
+// like a house, a car or a mortgage. The latest valuation of a name on or before a day is its
+// value for that day, a valuation of 0 takes it out of the net worth.
+type ValuationModel struct {
+	ValuationId uuid.UUID
+	UserId      uuid.UUID
+	Name        string
+	Kind        ValuationKind
+	Amount      float64
+	Date        int64
+	CreatedAt   int64
+}
+
+// NetWorthSnapshotModel is the users net worth at the end of the UTC day starting at Date.
+// Accounts with a positive balance count as assets and accounts with a negative balance,
+// like credit cards, as liabilities. Investment holdings count as assets at their market value
+// and the balance owed on loans as liabilities.
+type NetWorthSnapshotModel struct {
+	UserId      uuid.UUID
+	Date        int64
+	Assets      float64
+	Liabilities float64
+	NetWorth    float64
+	CreatedAt   int64
+}
+
+func (v ValuationKind) String() string {
+	switch v {
+	case VALUATION_ASSET:
+		return "VALUATION_ASSET"
+	case VALUATION_LIABILITY:
+		return "VALUATION_LIABILITY"
+	default:
+		return "Unknown"
+	}
+}
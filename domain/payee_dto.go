@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type PayeeDTO struct {
+	PayeeId   uuid.UUID `json:"payeeId"`
+	UserId    uuid.UUID `json:"userId" validate:"required"`
+	Name      string    `json:"name" validate:"required"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt int64     `json:"createdAt"`
+}
+
+type PayeeData struct {
+	Validator *validator.Validate
+	Payee     PayeeDTO
+}
+
+func (p *PayeeData) ValidatePayee() error {
+	err := p.Validator.Struct(p.Payee)
+	if err != nil {
+		log.Printf("Payee validation failed, %v. PayeeDTO: %v\n", err, p.Payee)
+		return err
+	}
+	return nil
+}
+
+// PayeeMergeDTO moves the source payees aliases and transactions to the target and removes the source.
+type PayeeMergeDTO struct {
+	SourcePayeeId uuid.UUID `json:"sourcePayeeId" validate:"required"`
+	TargetPayeeId uuid.UUID `json:"targetPayeeId" validate:"required"`
+}
+
+type PayeeMergeData struct {
+	Validator *validator.Validate
+	Merge     PayeeMergeDTO
+}
+
+func (p *PayeeMergeData) ValidatePayeeMerge() error {
+	err := p.Validator.Struct(p.Merge)
+	if err != nil {
+		log.Printf("Payee merge validation failed, %v. PayeeMergeDTO: %v\n", err, p.Merge)
+		return err
+	}
+	return nil
+}
+
+// PayeeSpendingDTO is the expense total of one payee, cancelled transactions are excluded.
+type PayeeSpendingDTO struct {
+	PayeeId uuid.UUID `json:"payeeId"`
+	Name    string    `json:"name"`
+	Count   int       `json:"count"`
+	Total   float64   `json:"total"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "github.com/google/uuid"
+
+// PayeeModel is a normalized merchant. Aliases are case insensitive regular expressions
+// matched against the normalized transaction description.
+type PayeeModel struct {
+	PayeeId   uuid.UUID
+	UserId    uuid.UUID
+	Name      string
+	Aliases   []string
+	CreatedAt int64
+}
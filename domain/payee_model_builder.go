@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	gen "github.com/pallinder/go-randomdata"
+)
+
+type PayeeModelBuild struct {
+	userId  uuid.UUID
+	name    string
+	aliases []string
+}
+
+func PayeeModelBuilder() *PayeeModelBuild {
+	name := gen.SillyName()
+	return &PayeeModelBuild{
+		userId:  uuid.New(),
+		name:    name,
+		aliases: []string{"^" + strings.ToLower(name)},
+	}
+}
+
+func (b *PayeeModelBuild) Build() PayeeModel {
+	return PayeeModel{
+		PayeeId:   uuid.New(),
+		UserId:    b.userId,
+		Name:      b.name,
+		Aliases:   b.aliases,
+		CreatedAt: time.Now().UnixMilli(),
+	}
+}
+
+func (b *PayeeModelBuild) WithUserId(userId uuid.UUID) *PayeeModelBuild {
+	b.userId = userId
+	return b
+}
+
+func (b *PayeeModelBuild) WithName(name string) *PayeeModelBuild {
+	b.name = name
+	return b
+}
+
+func (b *PayeeModelBuild) WithAliases(aliases ...string) *PayeeModelBuild {
+	b.aliases = aliases
+	return b
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type ReconciliationDTO struct {
+	ReconciliationId uuid.UUID            `json:"reconciliationId"`
+	UserId           uuid.UUID            `json:"userId" validate:"required"`
+	AccountId        int64                `json:"accountId" validate:"required"`
+	StatementDate    int64                `json:"statementDate" validate:"required"`
+	StartingBalance  float64              `json:"startingBalance"`
+	EndingBalance    float64              `json:"endingBalance"`
+	Status           ReconciliationStatus `json:"status"`
+	CreatedAt        int64                `json:"createdAt"`
+	ReconciledAt     int64                `json:"reconciledAt"`
+}
+
+type ReconciliationData struct {
+	Validator      *validator.Validate
+	Reconciliation ReconciliationDTO
+}
+
+func (r *ReconciliationData) ValidateReconciliation() error {
+	err := r.Validator.Struct(r.Reconciliation)
+	if err != nil {
+		log.Printf("Reconciliation validation failed, %v. ReconciliationDTO: %v\n", err, r.Reconciliation)
+		return err
+	}
+	return nil
+}
+
+// ClearUpdateDTO marks the transactions as cleared in the reconciliation, or takes them back
+// out of it when Cleared is false.
+type ClearUpdateDTO struct {
+	ReconciliationId uuid.UUID   `json:"reconciliationId" validate:"required"`
+	TransactionIds   []uuid.UUID `json:"transactionIds" validate:"required,min=1"`
+	Cleared          bool        `json:"cleared"`
+}
+
+type ClearUpdateData struct {
+	Validator *validator.Validate
+	Update    ClearUpdateDTO
+}
+
+func (c *ClearUpdateData) ValidateClearUpdate() error {
+	err := c.Validator.Struct(c.Update)
+	if err != nil {
+		log.Printf("Clear update validation failed, %v. ClearUpdateDTO: %v\n", err, c.Update)
+		return err
+	}
+	return nil
+}
+
+// ReconciliationDetailDTO is a reconciliation with the transactions cleared in it and those of
+// the account up to the statement date still to be cleared. ClearedBalance is the starting
+// balance with the cleared transactions, Difference what is left to reach the ending balance.
+type ReconciliationDetailDTO struct {
+	Reconciliation ReconciliationDTO `json:"reconciliation"`
+	Cleared        []TransactionDTO  `json:"cleared"`
+	Uncleared      []TransactionDTO  `json:"uncleared"`
+	ClearedBalance float64           `json:"clearedBalance"`
+	Difference     float64           `json:"difference"`
+}
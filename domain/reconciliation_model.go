@@ -0,0 +1,35 @@
+package domain
+
+import "github.com/google/uuid"
+
+type ReconciliationStatus int
+
+const (
+	RECONCILIATION_IN_PROGRESS ReconciliationStatus = iota
+	RECONCILED                                      // locked, its transactions can not be changed until it is unlocked.
+)
+
+// ReconciliationModel matches the transactions of an account against a bank statement ending
+// on StatementDate with EndingBalance. It starts from the ending balance of the accounts last
+// reconciled statement and balances once the transactions cleared in it make up the difference.
+type ReconciliationModel struct {
+	ReconciliationId uuid.UUID
+	UserId           uuid.UUID
+	AccountId        int64
+	StatementDate    int64
+	StartingBalance  float64
+	EndingBalance    float64
+	Status           ReconciliationStatus
+	CreatedAt        int64
+	ReconciledAt     int64 // 0 while in progress.
+}
+
+func (s ReconciliationStatus) String() string {
+	switch s {
+	case RECONCILIATION_IN_PROGRESS:
+		return "IN_PROGRESS"
+	case RECONCILED:
+		return "RECONCILED"
+	}
+	return "UNKNOWN"
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "github.com/google/uuid"
+
+// ReportQueryDTO selects the transactions dated between From and To, inclusive, that pass the
+// tag filter. Cancelled transactions are never part of the totals, pending ones are unless
+// ExcludePending is set.
+type ReportQueryDTO struct {
+	UserId         uuid.UUID
+	From           int64
+	To             int64
+	Period         ReportPeriod
+	ExcludePending bool
+	TagFilter      TagFilter
+}
+
+type ReportTotalsDTO struct {
+	Income  float64 `json:"income"`
+	Expense float64 `json:"expense"`
+	Net     float64 `json:"net"`
+	Count   int     `json:"count"`
+}
+
+// ReportPeriodDTO is one week, month or year of the report, clipped to the report range.
+// Delta is the change from the period before it.
+type ReportPeriodDTO struct {
+	Period string          `json:"period"`
+	From   int64           `json:"from"`
+	To     int64           `json:"to"`
+	Totals ReportTotalsDTO `json:"totals"`
+	Delta  ReportTotalsDTO `json:"delta"`
+}
+
+// ReportCategoryDTO is a categories share of the total income and expense.
+type ReportCategoryDTO struct {
+	CategoryId     int64   `json:"categoryId"`
+	Income         float64 `json:"income"`
+	Expense        float64 `json:"expense"`
+	IncomePercent  float64 `json:"incomePercent"`
+	ExpensePercent float64 `json:"expensePercent"`
+	Count          int     `json:"count"`
+}
+
+type ReportPaymentMethodDTO struct {
+	PaymentMethod TransactionMethod `json:"paymentMethod"`
+	Totals        ReportTotalsDTO   `json:"totals"`
+}
+
+// ReportStatusDTO includes cancelled transactions, which are left out of every other total.
+type ReportStatusDTO struct {
+	Status TransactionStatus `json:"status"`
+	Totals ReportTotalsDTO   `json:"totals"`
+}
+
+// ReportDTO summarizes the transactions of a date range. Previous holds the totals of the
+// equally long range right before From, and Delta the change from it.
+type ReportDTO struct {
+	UserId         uuid.UUID                `json:"userId"`
+	From           int64                    `json:"from"`
+	To             int64                    `json:"to"`
+	Totals         ReportTotalsDTO          `json:"totals"`
+	Previous       ReportTotalsDTO          `json:"previous"`
+	Delta          ReportTotalsDTO          `json:"delta"`
+	Periods        []ReportPeriodDTO        `json:"periods"`
+	Categories     []ReportCategoryDTO      `json:"categories"`
+	PaymentMethods []ReportPaymentMethodDTO `json:"paymentMethods"`
+	Statuses       []ReportStatusDTO        `json:"statuses"`
+}
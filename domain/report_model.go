@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReportPeriod is how report totals are grouped. Weeks start on Monday and all periods are in UTC.
+type ReportPeriod int
+
+const (
+	REPORT_MONTH ReportPeriod = iota
+	REPORT_WEEK
+	REPORT_YEAR
+)
+
+func ParseReportPeriod(period string) (ReportPeriod, error) {
+	switch strings.ToLower(period) {
+	case "", "month":
+		return REPORT_MONTH, nil
+	case "week":
+		return REPORT_WEEK, nil
+	case "year":
+		return REPORT_YEAR, nil
+	}
+	return REPORT_MONTH, fmt.Errorf("unknown report period %q", period)
+}
+
+// Start returns the start of the period t falls in.
+func (p ReportPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	switch p {
+	case REPORT_WEEK:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case REPORT_YEAR:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Next returns the start of the period after the one starting at start.
+func (p ReportPeriod) Next(start time.Time) time.Time {
+	switch p {
+	case REPORT_WEEK:
+		return start.AddDate(0, 0, 7)
+	case REPORT_YEAR:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// Label names the period starting at start, e.g. "2026-03", "2026-W09" or "2026".
+func (p ReportPeriod) Label(start time.Time) string {
+	switch p {
+	case REPORT_WEEK:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case REPORT_YEAR:
+		return start.Format("2006")
+	default:
+		return start.Format("2006-01")
+	}
+}
+
+func (p ReportPeriod) String() string {
+	switch p {
+	case REPORT_MONTH:
+		return "REPORT_MONTH"
+	case REPORT_WEEK:
+		return "REPORT_WEEK"
+	case REPORT_YEAR:
+		return "REPORT_YEAR"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type RuleDTO struct {
+	RuleId     uuid.UUID       `json:"ruleId"`
+	UserId     uuid.UUID       `json:"userId" validate:"required"`
+	Name       string          `json:"name" validate:"required"`
+	Priority   int             `json:"priority"`
+	Conditions []RuleCondition `json:"conditions" validate:"required,min=1"`
+	Actions    []RuleAction    `json:"actions" validate:"required,min=1"`
+	CreatedAt  int64           `json:"createdAt"`
+}
+
+type RuleData struct {
+	Validator *validator.Validate
+	Rule      RuleDTO
+}
+
+func (r *RuleData) ValidateRule() error {
+	err := r.Validator.Struct(r.Rule)
+	if err != nil {
+		log.Printf("Rule validation failed, %v. RuleDTO: %v\n", err, r.Rule)
+		return err
+	}
+	return nil
+}
+
+// RuleReapplyDTO requests the users rules to be run again over the transactions between From and To.
+// With DryRun set the changes are only reported.
+type RuleReapplyDTO struct {
+	UserId uuid.UUID `json:"userId" validate:"required"`
+	From   int64     `json:"from"`
+	To     int64     `json:"to" validate:"required"`
+	DryRun bool      `json:"dryRun"`
+}
+
+type RuleReapplyData struct {
+	Validator *validator.Validate
+	Reapply   RuleReapplyDTO
+}
+
+func (r *RuleReapplyData) ValidateRuleReapply() error {
+	err := r.Validator.Struct(r.Reapply)
+	if err != nil {
+		log.Printf("Rule reapply validation failed, %v. RuleReapplyDTO: %v\n", err, r.Reapply)
+		return err
+	}
+	return nil
+}
+
+type RuleFieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// RuleChangeDTO describes what the rules changed, or would change, on one transaction.
+type RuleChangeDTO struct {
+	TransactionId uuid.UUID         `json:"transactionId"`
+	RuleIds       []uuid.UUID       `json:"ruleIds"`
+	Changes       []RuleFieldChange `json:"changes"`
+	Tags          []string          `json:"tags"`
+}
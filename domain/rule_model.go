@@ -0,0 +1,54 @@
+package domain
+
+import "github.com/google/uuid"
+
+type RuleConditionType int
+
+const (
+	DESCRIPTION_CONTAINS RuleConditionType = iota
+	DESCRIPTION_REGEX
+	AMOUNT_RANGE
+	PAYMENT_METHOD
+	ACCOUNT
+)
+
+type RuleActionType int
+
+const (
+	SET_CATEGORY RuleActionType = iota
+	SET_STATUS
+	ADD_TAG
+	RENAME_DESCRIPTION
+)
+
+// RuleCondition only uses the fields relevant to its Type.
+// Text is used by the description conditions, Min and Max by AMOUNT_RANGE (0 is unbounded).
+type RuleCondition struct {
+	Type          RuleConditionType `json:"type"`
+	Text          string            `json:"text"`
+	Min           float64           `json:"min"`
+	Max           float64           `json:"max"`
+	PaymentMethod TransactionMethod `json:"paymentMethod"`
+	AccountId     int64             `json:"accountId"`
+}
+
+// RuleAction only uses the fields relevant to its Type.
+type RuleAction struct {
+	Type        RuleActionType    `json:"type"`
+	CategoryId  int64             `json:"categoryId"`
+	Status      TransactionStatus `json:"status"`
+	Tag         string            `json:"tag"`
+	Description string            `json:"description"`
+}
+
+// RuleModel matches a transaction when all of its conditions match.
+// Rules run by ascending Priority, the first rule to set a field wins.
+type RuleModel struct {
+	RuleId     uuid.UUID
+	UserId     uuid.UUID
+	Name       string
+	Priority   int
+	Conditions []RuleCondition
+	Actions    []RuleAction
+	CreatedAt  int64
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	gen "github.com/pallinder/go-randomdata"
+)
+
+type RuleModelBuild struct {
+	userId     uuid.UUID
+	priority   int
+	conditions []RuleCondition
+	actions    []RuleAction
+}
+
+func RuleModelBuilder() *RuleModelBuild {
+	return &RuleModelBuild{
+		userId:     uuid.New(),
+		priority:   gen.Number(1, 10),
+		conditions: []RuleCondition{{Type: DESCRIPTION_CONTAINS, Text: gen.Letters(5)}},
+		actions:    []RuleAction{{Type: SET_CATEGORY, CategoryId: int64(gen.Number(1, 15))}},
+	}
+}
+
+func (b *RuleModelBuild) Build() RuleModel {
+	return RuleModel{
+		RuleId:     uuid.New(),
+		UserId:     b.userId,
+		Name:       gen.Letters(10),
+		Priority:   b.priority,
+		Conditions: b.conditions,
+		Actions:    b.actions,
+		CreatedAt:  time.Now().UnixMilli(),
+	}
+}
+
+func (b *RuleModelBuild) WithUserId(userId uuid.UUID) *RuleModelBuild {
+	b.userId = userId
+	return b
+}
+
+func (b *RuleModelBuild) WithPriority(priority int) *RuleModelBuild {
+	b.priority = priority
+	return b
+}
+
+func (b *RuleModelBuild) WithConditions(conditions ...RuleCondition) *RuleModelBuild {
+	b.conditions = conditions
+	return b
+}
+
+func (b *RuleModelBuild) WithActions(actions ...RuleAction) *RuleModelBuild {
+	b.actions = actions
+	return b
+}
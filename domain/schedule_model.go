@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ScheduleFrequency int
+
+const (
+	WEEKLY ScheduleFrequency = iota
+	BIWEEKLY
+	MONTHLY
+	YEARLY
+)
+
+// ScheduledItemModel is a known recurring income or expense on an account, such as a salary
+// or rent, starting on StartDate.
+type ScheduledItemModel struct {
+	ScheduledItemId uuid.UUID
+	UserId          uuid.UUID
+	AccountId       int64
+	Description     string
+	Amount          float64
+	Type            TransactionType
+	Frequency       ScheduleFrequency
+	StartDate       int64
+	CreatedAt       int64
+}
+
+// Occurrence returns the date of the nth occurrence, the first being n = 0. Monthly and yearly
+// items that start late in the month fall on the last day of shorter months.
+func (f ScheduleFrequency) Occurrence(start time.Time, n int) time.Time {
+	switch f {
+	case WEEKLY:
+		return start.AddDate(0, 0, 7*n)
+	case BIWEEKLY:
+		return start.AddDate(0, 0, 14*n)
+	case YEARLY:
+		return addMonthsClamped(start, 12*n)
+	default:
+		return addMonthsClamped(start, n)
+	}
+}
+
+func addMonthsClamped(start time.Time, months int) time.Time {
+	firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(months), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(start.Day(), lastDay)-1)
+}
+
+func (f ScheduleFrequency) String() string {
+	switch f {
+	case WEEKLY:
+		return "WEEKLY"
+	case BIWEEKLY:
+		return "BIWEEKLY"
+	case MONTHLY:
+		return "MONTHLY"
+	case YEARLY:
+		return "YEARLY"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// PriceChangeDTO is a charge on Date that changed a price that had held from the charge before.
+type PriceChangeDTO struct {
+	Date int64   `json:"date"`
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+}
+
+// SubscriptionDTO is a detected periodic charge. Amount is the latest charge and AnnualCost what
+// it comes to over a year at the detected Frequency. Active is false once the next charge is
+// more than half an interval overdue, which usually means the subscription was cancelled.
+type SubscriptionDTO struct {
+	Key             string             `json:"key"`
+	UserId          uuid.UUID          `json:"userId"`
+	PayeeId         uuid.UUID          `json:"payeeId"`
+	Description     string             `json:"description"`
+	AccountId       int64              `json:"accountId"`
+	CategoryId      int64              `json:"categoryId"`
+	Frequency       ScheduleFrequency  `json:"frequency"`
+	Amount          float64            `json:"amount"`
+	AnnualCost      float64            `json:"annualCost"`
+	Charges         int                `json:"charges"`
+	FirstDate       int64              `json:"firstDate"`
+	LastDate        int64              `json:"lastDate"`
+	NextDate        int64              `json:"nextDate"`
+	Active          bool               `json:"active"`
+	PriceChanges    []PriceChangeDTO   `json:"priceChanges"`
+	Status          SubscriptionStatus `json:"status"`
+	ScheduledItemId uuid.UUID          `json:"scheduledItemId"`
+}
+
+// SubscriptionDecisionDTO is sent to confirm or dismiss the detected subscription with the given Key.
+type SubscriptionDecisionDTO struct {
+	UserId uuid.UUID `json:"userId" validate:"required"`
+	Key    string    `json:"key" validate:"required"`
+}
+
+type SubscriptionDecisionData struct {
+	Validator *validator.Validate
+	Decision  SubscriptionDecisionDTO
+}
+
+func (s *SubscriptionDecisionData) ValidateSubscriptionDecision() error {
+	err := s.Validator.Struct(s.Decision)
+	if err != nil {
+		log.Printf("Subscription decision validation failed, %v. SubscriptionDecisionDTO: %v\n", err, s.Decision)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "github.com/google/uuid"
+
+type SubscriptionStatus int
+
+const (
+	SUBSCRIPTION_DETECTED SubscriptionStatus = iota
+	SUBSCRIPTION_CONFIRMED
+	SUBSCRIPTION_DISMISSED
+)
+
+// SubscriptionDecisionModel records the user confirming or dismissing a detected subscription.
+// Subscriptions are detected again on every request, Key identifies one across detections, the
+// payee id, or the normalized description for transactions without a payee. ScheduledItemId is
+// the recurring item a confirmed subscription is tracked as.
+type SubscriptionDecisionModel struct {
+	UserId          uuid.UUID
+	Key             string
+	Status          SubscriptionStatus
+	ScheduledItemId uuid.UUID
+	CreatedAt       int64
+}
+
+func (s SubscriptionStatus) String() string {
+	switch s {
+	case SUBSCRIPTION_DETECTED:
+		return "SUBSCRIPTION_DETECTED"
+	case SUBSCRIPTION_CONFIRMED:
+		return "SUBSCRIPTION_CONFIRMED"
+	case SUBSCRIPTION_DISMISSED:
+		return "SUBSCRIPTION_DISMISSED"
+	default:
+		return "Unknown"
+	}
+}
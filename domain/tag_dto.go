@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type TagDTO struct {
+	TagId     uuid.UUID `json:"tagId"`
+	UserId    uuid.UUID `json:"userId"`
+	Name      string    `json:"name"`
+	CreatedAt int64     `json:"createdAt"`
+}
+
+// TagUpdateDTO adds or removes every tag on every transaction listed.
+type TagUpdateDTO struct {
+	UserId         uuid.UUID   `json:"userId" validate:"required"`
+	TransactionIds []uuid.UUID `json:"transactionIds" validate:"required,min=1"`
+	Tags           []string    `json:"tags" validate:"required,min=1,dive,required"`
+}
+
+type TagUpdateData struct {
+	Validator *validator.Validate
+	Update    TagUpdateDTO
+}
+
+func (t *TagUpdateData) ValidateTagUpdate() error {
+	err := t.Validator.Struct(t.Update)
+	if err != nil {
+		log.Printf("Tag update validation failed, %v. TagUpdateDTO: %v\n", err, t.Update)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type TagModel struct {
+	TagId     uuid.UUID
+	UserId    uuid.UUID
+	Name      string
+	CreatedAt int64
+}
+
+type TagFilterMode int
+
+const (
+	TAG_ANY TagFilterMode = iota
+	TAG_ALL
+	TAG_NONE
+)
+
+func ParseTagFilterMode(mode string) (TagFilterMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "any":
+		return TAG_ANY, nil
+	case "all":
+		return TAG_ALL, nil
+	case "none":
+		return TAG_NONE, nil
+	}
+	return TAG_ANY, fmt.Errorf("unknown tag filter mode %q", mode)
+}
+
+// TagFilter selects transactions by their tags. A filter without tags matches everything.
+type TagFilter struct {
+	Tags []string
+	Mode TagFilterMode
+}
+
+func (f *TagFilter) Matches(tags []string) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+
+	switch f.Mode {
+	case TAG_ALL:
+		for _, tag := range f.Tags {
+			if !slices.Contains(tags, tag) {
+				return false
+			}
+		}
+		return true
+	case TAG_NONE:
+		for _, tag := range f.Tags {
+			if slices.Contains(tags, tag) {
+				return false
+			}
+		}
+		return true
+	default:
+		for _, tag := range f.Tags {
+			if slices.Contains(tags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NormalizeTag is how tag names are stored, "  Vacation-2026 " becomes "vacation-2026".
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
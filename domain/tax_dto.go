@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type TaxLineDTO struct {
+	TaxLineId   uuid.UUID `json:"taxLineId"`
+	UserId      uuid.UUID `json:"userId" validate:"required"`
+	Name        string    `json:"name" validate:"required"`
+	Code        string    `json:"code"`
+	CategoryIds []int64   `json:"categoryIds" validate:"required,min=1,dive,gt=0"`
+	CreatedAt   int64     `json:"createdAt"`
+}
+
+type TaxLineData struct {
+	Validator *validator.Validate
+	TaxLine   TaxLineDTO
+}
+
+func (t *TaxLineData) ValidateTaxLine() error {
+	err := t.Validator.Struct(t.TaxLine)
+	if err != nil {
+		log.Printf("Tax line validation failed, %v. TaxLineDTO: %v\n", err, t.TaxLine)
+		return err
+	}
+	return nil
+}
+
+// TaxTransactionDTO is a transaction supporting a tax line total. Amount is positive for
+// expenses and negative for refunds.
+type TaxTransactionDTO struct {
+	TransactionId uuid.UUID          `json:"transactionId"`
+	Date          int64              `json:"date"`
+	Description   string             `json:"description"`
+	CategoryId    int64              `json:"categoryId"`
+	Amount        float64            `json:"amount"`
+	Attachments   []TaxAttachmentDTO `json:"attachments"`
+}
+
+type TaxAttachmentDTO struct {
+	AttachmentId uuid.UUID `json:"attachmentId"`
+	FileName     string    `json:"fileName"`
+}
+
+type TaxLineTotalDTO struct {
+	TaxLineId    uuid.UUID           `json:"taxLineId"`
+	Name         string              `json:"name"`
+	Code         string              `json:"code"`
+	Total        float64             `json:"total"`
+	Count        int                 `json:"count"`
+	Transactions []TaxTransactionDTO `json:"transactions"`
+}
+
+// TaxReportDTO totals the transactions of the tax year, From through To, by tax line.
+// Cancelled transactions are left out.
+type TaxReportDTO struct {
+	UserId uuid.UUID         `json:"userId"`
+	Year   int               `json:"year"`
+	From   int64             `json:"from"`
+	To     int64             `json:"to"`
+	Lines  []TaxLineTotalDTO `json:"lines"`
+	Total  float64           `json:"total"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "github.com/google/uuid"
+
+// TaxLineModel is a line of the users tax return, such as "Charitable donations", that the
+// transactions in CategoryIds are reported under. A category maps to at most one line.
+type TaxLineModel struct {
+	TaxLineId   uuid.UUID
+	UserId      uuid.UUID
+	Name        string
+	Code        string // the form and line the total goes on, e.g. "Schedule A line 11", may be empty.
+	CategoryIds []int64
+	CreatedAt   int64
+}
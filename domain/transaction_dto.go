@@ -15,7 +15,9 @@ type TransactionData struct {
 type TransactionDTO struct {
 	UserId        uuid.UUID         `json:"userId" validate:"required"`
 	TransactionId uuid.UUID         `json:"transactionId" validate:"required"`
-	CategoryId    int64             `json:"categoryId" validate:"required"`
+	CategoryId    int64             `json:"categoryId"` // 0 until categorized, by hand or by a rule.
+	AccountId     int64             `json:"accountId"`
+	PayeeId       uuid.UUID         `json:"payeeId"` // set from the description when saved.
 	Amount        float64           `json:"amount" validate:"required"`
 	Date          int64             `json:"date" validate:"required"`
 	Description   string            `json:"description" validate:"required"`
@@ -24,6 +26,13 @@ type TransactionDTO struct {
 	Type          TransactionType   `json:"type"`
 	PaymentMethod TransactionMethod `json:"paymentMethod"`
 	Status        TransactionStatus `json:"status"`
+	Tags          []string          `json:"tags"`
+
+	// Amount is given in Currency, by default the accounts currency or else the users base
+	// currency. Saved transactions have Amount converted into the base currency and the
+	// amount as given in OriginalAmount.
+	Currency       string  `json:"currency" validate:"omitempty,iso4217"`
+	OriginalAmount float64 `json:"originalAmount"`
 }
 
 func (t *TransactionData) ValidateTransaction() error {
@@ -34,3 +43,29 @@ func (t *TransactionData) ValidateTransaction() error {
 	}
 	return nil
 }
+
+// TransactionImportData holds a batch of transactions, e.g. from a bank statement.
+type TransactionImportData struct {
+	Validator    *validator.Validate
+	Transactions []TransactionDTO
+}
+
+// TransactionQueryDTO selects a users transactions between From and To, inclusive.
+type TransactionQueryDTO struct {
+	UserId    uuid.UUID
+	From      int64
+	To        int64
+	TagFilter TagFilter
+}
+
+// TransactionResultDTO is returned after a transaction is saved.
+type TransactionResultDTO struct {
+	Transaction TransactionDTO `json:"transaction"`
+	Duplicates  []DuplicateDTO `json:"duplicates"`
+
+	// only when the transaction was saved without a category.
+	Suggestions []CategorySuggestionDTO `json:"suggestions"`
+
+	// alerts fired by the transaction, see AlertRuleModel.
+	Alerts []AlertDTO `json:"alerts"`
+}
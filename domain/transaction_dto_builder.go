@@ -22,6 +22,7 @@ func (b *TransactionDTOStruct) Build() TransactionDTO {
 		UserId:        uuid.New(),
 		TransactionId: uuid.New(),
 		CategoryId:    int64(gen.Number(1, 15)),
+		AccountId:     int64(gen.Number(1, 5)),
 		Amount:        float64(gen.Number(1, 5)),
 		Date:          int64(gen.Number(1, 13)),
 		Description:   b.description,
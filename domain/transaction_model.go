@@ -26,17 +26,21 @@ const (
 )
 
 type TransactionModel struct {
-	UserId        uuid.UUID
-	TransactionId uuid.UUID
-	CategoryId    int64
-	Amount        float64
-	Date          int64
-	Description   string
-	CreatedAt     int64
-	UpdatedAt     int64
-	Type          TransactionType
-	PaymentMethod TransactionMethod
-	Status        TransactionStatus
+	UserId         uuid.UUID
+	TransactionId  uuid.UUID
+	CategoryId     int64
+	AccountId      int64
+	PayeeId        uuid.UUID // uuid.Nil when the description could not be normalized.
+	Amount         float64   // in the users base currency when saved.
+	Date           int64
+	Description    string
+	CreatedAt      int64
+	UpdatedAt      int64
+	Type           TransactionType
+	PaymentMethod  TransactionMethod
+	Status         TransactionStatus
+	Currency       string  // ISO 4217 code of OriginalAmount, empty for transactions saved in the base currency before currencies.
+	OriginalAmount float64 // the amount as entered, in Currency.
 }
 
 type CategoryModel struct {
@@ -44,3 +48,37 @@ type CategoryModel struct {
 	Name        string
 	Description string
 }
+
+func (t TransactionType) String() string {
+	switch t {
+	case INCOME:
+		return "INCOME"
+	case EXPENSE:
+		return "EXPENSE"
+	}
+	return "UNKNOWN"
+}
+
+func (t TransactionMethod) String() string {
+	switch t {
+	case CASH:
+		return "CASH"
+	case CREDIT_CARD:
+		return "CREDIT_CARD"
+	case BANK_TRANSFER:
+		return "BANK_TRANSFER"
+	}
+	return "UNKNOWN"
+}
+
+func (t TransactionStatus) String() string {
+	switch t {
+	case PENDING:
+		return "PENDING"
+	case CLEARED:
+		return "CLEARED"
+	case CANCELLED:
+		return "CANCELLED"
+	}
+	return "UNKNOWN"
+}
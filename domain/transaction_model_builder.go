@@ -10,6 +10,7 @@ import (
 var types = []TransactionType{INCOME, EXPENSE}
 var payments = []TransactionMethod{CASH, CREDIT_CARD, BANK_TRANSFER}
 var statuses = []TransactionStatus{PENDING, CLEARED, CANCELLED}
+var currencies = []string{"EUR", "USD", "GBP", "JPY"}
 
 type TransactionModelBuild struct{}
 
@@ -19,17 +20,21 @@ func TransactionModelBuilder() *TransactionModelBuild {
 
 func (b *TransactionModelBuild) Build() TransactionModel {
 	return TransactionModel{
-		UserId:        uuid.New(),
-		TransactionId: uuid.New(),
-		CategoryId:    int64(gen.Number(15)),
-		Amount:        float64(gen.Number(3)),
-		Date:          int64(gen.Number(13)),
-		Description:   strings.Split(gen.Paragraph(), ".")[0],
-		CreatedAt:     int64(gen.Number(13)),
-		UpdatedAt:     int64(gen.Number(13)),
-		Type:          types[gen.Number(0, len(types))],
-		PaymentMethod: payments[gen.Number(0, len(payments))],
-		Status:        statuses[gen.Number(0, len(statuses))],
+		UserId:         uuid.New(),
+		TransactionId:  uuid.New(),
+		CategoryId:     int64(gen.Number(15)),
+		AccountId:      int64(gen.Number(5)),
+		PayeeId:        uuid.New(),
+		Amount:         float64(gen.Number(3)),
+		Date:           int64(gen.Number(13)),
+		Description:    strings.Split(gen.Paragraph(), ".")[0],
+		CreatedAt:      int64(gen.Number(13)),
+		UpdatedAt:      int64(gen.Number(13)),
+		Type:           types[gen.Number(0, len(types))],
+		PaymentMethod:  payments[gen.Number(0, len(payments))],
+		Status:         statuses[gen.Number(0, len(statuses))],
+		Currency:       currencies[gen.Number(0, len(currencies))],
+		OriginalAmount: float64(gen.Number(3)),
 	}
 }
 
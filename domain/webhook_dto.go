@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// WebhookSubscriptionDTO subscribes URL to the events. Secret is generated when not given and
+// is only returned when the subscription is added.
+type WebhookSubscriptionDTO struct {
+	WebhookId uuid.UUID      `json:"webhookId"`
+	UserId    uuid.UUID      `json:"userId" validate:"required"`
+	URL       string         `json:"url" validate:"required,http_url"`
+	Events    []WebhookEvent `json:"events" validate:"required,min=1,dive,oneof=transaction.created transaction.updated transaction.deleted user.updated"`
+	Secret    string         `json:"secret,omitempty" validate:"omitempty,min=16"`
+	CreatedAt int64          `json:"createdAt"`
+}
+
+type WebhookSubscriptionData struct {
+	Validator *validator.Validate
+	Webhook   WebhookSubscriptionDTO
+}
+
+func (w *WebhookSubscriptionData) ValidateWebhook() error {
+	err := w.Validator.Struct(w.Webhook)
+	if err != nil {
+		log.Printf("Webhook validation failed, %v. WebhookSubscriptionDTO: %v\n", err, w.Webhook)
+		return err
+	}
+	return nil
+}
+
+type WebhookDeliveryDTO struct {
+	DeliveryId     uuid.UUID       `json:"deliveryId"`
+	WebhookId      uuid.UUID       `json:"webhookId"`
+	UserId         uuid.UUID       `json:"userId"`
+	Event          WebhookEvent    `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus"`
+	Error          string          `json:"error"`
+	NextAttemptAt  int64           `json:"nextAttemptAt"`
+	CreatedAt      int64           `json:"createdAt"`
+	UpdatedAt      int64           `json:"updatedAt"`
+}
+
+// WebhookPayloadDTO is the body posted for an event. Id identifies the event and stays the same
+// when it is delivered again, Data is the transaction or the user after the change, or before
+// it for deletes.
+type WebhookPayloadDTO struct {
+	Id        uuid.UUID    `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	CreatedAt int64        `json:"createdAt"`
+	Data      any          `json:"data"`
+}
+
+// UserEventDTO is the user in user.updated events, the profile without the password.
+type UserEventDTO struct {
+	UserId       uuid.UUID `json:"userId"`
+	FirstName    string    `json:"firstName"`
+	LastName     string    `json:"lastName"`
+	Email        string    `json:"email"`
+	Phone        string    `json:"phone"`
+	DateOfBirth  int64     `json:"dateOfBirth"`
+	CreationDate int64     `json:"creationDate"`
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "github.com/google/uuid"
+
+// WebhookEvent is the type of event a webhook subscription receives, sent as the event of the payload.
+type WebhookEvent string
+
+const (
+	TRANSACTION_CREATED WebhookEvent = "transaction.created"
+	TRANSACTION_UPDATED WebhookEvent = "transaction.updated"
+	TRANSACTION_DELETED WebhookEvent = "transaction.deleted"
+	USER_UPDATED        WebhookEvent = "user.updated"
+)
+
+type DeliveryStatus int
+
+const (
+	DELIVERY_PENDING DeliveryStatus = iota
+	DELIVERY_SUCCEEDED
+	DELIVERY_FAILED
+)
+
+// WebhookSubscriptionModel posts the users Events to URL, signed with Secret.
+type WebhookSubscriptionModel struct {
+	WebhookId uuid.UUID
+	UserId    uuid.UUID
+	URL       string
+	Events    []WebhookEvent
+	Secret    string
+	CreatedAt int64
+}
+
+// WebhookDeliveryModel is one event sent to a subscription. Payload is the JSON body. Pending
+// deliveries are retried at NextAttemptAt until they succeed or run out of attempts.
+// ResponseStatus and Error are those of the last attempt, ResponseStatus is 0 without a response.
+type WebhookDeliveryModel struct {
+	DeliveryId     uuid.UUID
+	WebhookId      uuid.UUID
+	UserId         uuid.UUID
+	Event          WebhookEvent
+	Payload        string
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int
+	Error          string
+	NextAttemptAt  int64
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+func (s DeliveryStatus) String() string {
+	switch s {
+	case DELIVERY_PENDING:
+		return "DELIVERY_PENDING"
+	case DELIVERY_SUCCEEDED:
+		return "DELIVERY_SUCCEEDED"
+	case DELIVERY_FAILED:
+		return "DELIVERY_FAILED"
+	}
+	return "UNKNOWN"
+}
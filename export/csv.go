@@ -0,0 +1,50 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+)
+
+// WriteCSV writes the sheets one after another. With more than one sheet every section starts
+// with a row holding the sheet name and sections are separated by an empty row. Text cells that
+// a spreadsheet would run as a formula are escaped, see escapeFormula.
+func WriteCSV(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	for i, sheet := range doc.Sheets {
+		if len(doc.Sheets) > 1 {
+			if i > 0 {
+				writer.Write([]string{})
+			}
+			writer.Write([]string{sheet.Name})
+		}
+		writer.Write(sheet.Columns)
+		for _, row := range sheet.Rows {
+			record := make([]string, len(row))
+			for j, cell := range row {
+				record[j] = formatCell(cell)
+				if _, ok := cell.(string); ok {
+					record[j] = escapeFormula(record[j])
+				}
+			}
+			writer.Write(record)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeFormula prefixes text starting like a formula with a quote, so a description such as
+// "=HYPERLINK(...)" is shown as text when the CSV is opened in a spreadsheet. Numbers are not
+// text cells and keep their sign.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Document is a report or listing to export, its sheets become CSV sections, XLSX worksheets
+// or PDF tables. Title is only shown in PDFs.
+type Document struct {
+	Title  string
+	Sheets []Sheet
+}
+
+// Sheet is one table of the document. Cells are strings, or float64, int and int64 numbers,
+// floats are written with two decimals.
+type Sheet struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+type Format int
+
+const (
+	JSON Format = iota
+	CSV
+	XLSX
+	PDF
+)
+
+var formatNames = map[string]Format{"json": JSON, "csv": CSV, "xlsx": XLSX, "pdf": PDF}
+
+var mediaTypes = map[string]Format{
+	"application/json": JSON,
+	"text/csv":         CSV,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": XLSX,
+	"application/pdf": PDF,
+}
+
+func (f Format) String() string {
+	return [...]string{"json", "csv", "xlsx", "pdf"}[f]
+}
+
+func (f Format) ContentType() string {
+	return [...]string{
+		"application/json",
+		"text/csv; charset=utf-8",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"application/pdf",
+	}[f]
+}
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Negotiate picks the format named by the format query parameter or, when it is empty, the
+// Accept header media type with the highest quality that can be rendered. JSON is the
+// default for anything else, including */*.
+func Negotiate(format, accept string) (Format, error) {
+	if format != "" {
+		f, ok := formatNames[strings.ToLower(format)]
+		if !ok {
+			return JSON, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+		}
+		return f, nil
+	}
+
+	type accepted struct {
+		format  Format
+		quality float64
+	}
+	var candidates []accepted
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		f, ok := mediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil || quality <= 0 {
+				continue
+			}
+		}
+		candidates = append(candidates, accepted{f, quality})
+	}
+	if len(candidates) == 0 {
+		return JSON, nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].format, nil
+}
+
+// Render writes the document in the format, JSON is left to the caller.
+func Render(doc *Document, format Format) ([]byte, error) {
+	switch format {
+	case CSV:
+		return WriteCSV(doc)
+	case XLSX:
+		return WriteXLSX(doc)
+	case PDF:
+		return WritePDF(doc)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// formatCell is the text of a cell, as written to CSV and PDF.
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(cell)
+}
+
+func isNumber(cell any) bool {
+	switch cell.(type) {
+	case float64, int, int64:
+		return true
+	}
+	return false
+}
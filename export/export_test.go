@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+)
+
+func testDocument() *Document {
+	return &Document{
+		Title: "Report 2025",
+		Sheets: []Sheet{
+			{Name: "Summary", Columns: []string{"Range", "Income", "Count"}, Rows: [][]any{{"This range", 1250.5, 3}, {"Previous range", 900.0, int64(2)}}},
+			{Name: "Categories", Columns: []string{"Category", "Expense"}, Rows: [][]any{{"Rent, \"flat\"", 800.0}}},
+		},
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		accept string
+		want   Format
+		err    bool
+	}{
+		{"Default", "", "", JSON, false},
+		{"Format query", "xlsx", "application/pdf", XLSX, false},
+		{"Format query case", "PDF", "", PDF, false},
+		{"Unknown format", "docx", "", JSON, true},
+		{"Accept CSV", "", "text/csv", CSV, false},
+		{"Accept quality", "", "application/pdf;q=0.5, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", XLSX, false},
+		{"Accept unknown", "", "text/html, */*", JSON, false},
+		{"Accept zero quality", "", "application/pdf;q=0, text/csv;q=0.1", CSV, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Negotiate(tt.format, tt.accept)
+			if tt.err != errors.Is(err, ErrUnknownFormat) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	data, err := Render(testDocument(), CSV)
+	if err != nil {
+		t.Fatal("Error writing the CSV:", err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal("Error reading the CSV:", err)
+	}
+	// the empty row between the sections is skipped by the reader.
+	want := [][]string{
+		{"Summary"},
+		{"Range", "Income", "Count"},
+		{"This range", "1250.50", "3"},
+		{"Previous range", "900.00", "2"},
+		{"Categories"},
+		{"Category", "Expense"},
+		{"Rent, \"flat\"", "800.00"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Expected %d records, got %d: %v", len(want), len(records), records)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Errorf("Record %d: expected %v, got %v", i, want[i], records[i])
+				break
+			}
+		}
+	}
+}
+
+func TestWriteCSV_Formulas(t *testing.T) {
+	doc := Document{Sheets: []Sheet{{
+		Name:    "Transactions",
+		Columns: []string{"Description", "Amount"},
+		Rows: [][]any{
+			{"=HYPERLINK(\"https://evil.test\",\"refund\")", -12.3},
+			{"+1 555 0100", int64(-4)},
+			{"-2+3", -1},
+			{"@SUM(A1:A2)", 0.0},
+			{"\t=1+1", 1.0},
+			{"Coffee = tea", 2.0},
+		},
+	}}}
+	data, err := WriteCSV(&doc)
+	if err != nil {
+		t.Fatal("Error writing the CSV:", err)
+	}
+
+	want := "Description,Amount\n" +
+		"\"'=HYPERLINK(\"\"https://evil.test\"\",\"\"refund\"\")\",-12.30\n" +
+		"'+1 555 0100,-4\n" +
+		"'-2+3,-1\n" +
+		"'@SUM(A1:A2),0.00\n" +
+		"'\t=1+1,1.00\n" +
+		"Coffee = tea,2.00\n"
+	if string(data) != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestWriteCSV_SingleSheet(t *testing.T) {
+	doc := Document{Sheets: []Sheet{{Name: "Transactions", Columns: []string{"Date", "Amount"}, Rows: [][]any{{"2025-03-01", -12.3}}}}}
+	data, err := WriteCSV(&doc)
+	if err != nil {
+		t.Fatal("Error writing the CSV:", err)
+	}
+	if string(data) != "Date,Amount\n2025-03-01,-12.30\n" {
+		t.Errorf("Unexpected CSV: %q", data)
+	}
+}
@@ -0,0 +1,282 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 landscape in points, wide enough for transaction listings.
+const (
+	pageWidth   = 842.0
+	pageHeight  = 595.0
+	pageMargin  = 36.0
+	titleSize   = 14.0
+	headingSize = 11.0
+	textSize    = 9.0
+	footerSize  = 8.0
+	rowHeight   = 14.0
+	cellPadding = 4.0
+)
+
+// helveticaWidths are the glyph widths of Helvetica for the characters from space to tilde,
+// in thousandths of the font size. Helvetica-Bold is a little wider, see textWidth.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// pdfPage collects the content stream of one page.
+type pdfPage struct {
+	content strings.Builder
+}
+
+// pdfLayout places the tables on pages top to bottom, starting a new page whenever the next
+// row does not fit.
+type pdfLayout struct {
+	title string
+	pages []*pdfPage
+	y     float64
+}
+
+// WritePDF writes the document as a paginated PDF statement, the title on top of every page,
+// each sheet as a table with its column headers repeated on every page it spans and page
+// numbers in the footer. Only the standard Helvetica fonts are used, so text outside of
+// Windows-1252 is replaced by question marks.
+func WritePDF(doc *Document) ([]byte, error) {
+	layout := pdfLayout{title: doc.Title}
+	layout.newPage()
+	for i := range doc.Sheets {
+		layout.table(&doc.Sheets[i])
+	}
+	for i, page := range layout.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(layout.pages))
+		page.text(pageMargin, pageMargin/2, footerSize, false, doc.Title)
+		page.text(pageWidth-pageMargin-textWidth(footer, footerSize, false), pageMargin/2, footerSize, false, footer)
+	}
+	return layout.write(), nil
+}
+
+func (l *pdfLayout) newPage() {
+	page := &pdfPage{}
+	l.pages = append(l.pages, page)
+	l.y = pageHeight - pageMargin
+	if l.title != "" {
+		page.text(pageMargin, l.y-titleSize, titleSize, true, l.title)
+		l.y -= titleSize * 2
+	}
+}
+
+func (l *pdfLayout) page() *pdfPage {
+	return l.pages[len(l.pages)-1]
+}
+
+// fits reports whether height more points fit above the footer of the current page.
+func (l *pdfLayout) fits(height float64) bool {
+	return l.y-height >= pageMargin+footerSize
+}
+
+func (l *pdfLayout) table(sheet *Sheet) {
+	widths := columnWidths(sheet)
+	numeric := make([]bool, len(widths))
+	if len(sheet.Rows) > 0 {
+		for j := range numeric {
+			numeric[j] = j < len(sheet.Rows[0]) && isNumber(sheet.Rows[0][j])
+		}
+	}
+
+	// keep the heading together with the column headers and the first row.
+	if !l.fits(headingSize*2 + rowHeight*2) {
+		l.newPage()
+	}
+	l.heading(sheet.Name, sheet.Columns, widths, numeric)
+	for i, row := range sheet.Rows {
+		if !l.fits(rowHeight) {
+			l.newPage()
+			l.heading(sheet.Name+" (continued)", sheet.Columns, widths, numeric)
+		}
+		if i%2 == 1 {
+			fmt.Fprintf(&l.page().content, "0.94 g %.2f %.2f %.2f %.2f re f 0 g\n", pageMargin, l.y-rowHeight, pageWidth-2*pageMargin, rowHeight)
+		}
+		texts := make([]string, len(row))
+		for j, cell := range row {
+			texts[j] = formatCell(cell)
+		}
+		l.row(texts, widths, numeric, false)
+	}
+	l.y -= rowHeight
+}
+
+// heading writes the sheet name followed by the underlined column headers.
+func (l *pdfLayout) heading(name string, columns []string, widths []float64, numeric []bool) {
+	l.page().text(pageMargin, l.y-headingSize, headingSize, true, name)
+	l.y -= headingSize * 2
+	if len(widths) == 0 {
+		return
+	}
+	l.row(columns, widths, numeric, true)
+	fmt.Fprintf(&l.page().content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pageMargin, l.y, pageWidth-pageMargin, l.y)
+}
+
+// row writes one line of the table, numbers are aligned right and texts too wide for their
+// column are cut short.
+func (l *pdfLayout) row(texts []string, widths []float64, numeric []bool, bold bool) {
+	x := pageMargin
+	for j, width := range widths {
+		if j < len(texts) {
+			text := fitText(texts[j], width-2*cellPadding, textSize, bold)
+			textX := x + cellPadding
+			if numeric[j] {
+				textX = x + width - cellPadding - textWidth(text, textSize, bold)
+			}
+			l.page().text(textX, l.y-rowHeight+4, textSize, bold, text)
+		}
+		x += width
+	}
+	l.y -= rowHeight
+}
+
+// columnWidths shares the width of the page between the columns in proportion to their
+// widest text.
+func columnWidths(sheet *Sheet) []float64 {
+	columns := len(sheet.Columns)
+	for _, row := range sheet.Rows {
+		columns = max(columns, len(row))
+	}
+	widths := make([]float64, columns)
+	for j, column := range sheet.Columns {
+		widths[j] = textWidth(column, textSize, true)
+	}
+	for _, row := range sheet.Rows {
+		for j, cell := range row {
+			widths[j] = max(widths[j], textWidth(formatCell(cell), textSize, false))
+		}
+	}
+
+	total := 0.0
+	for j := range widths {
+		widths[j] += 2 * cellPadding
+		total += widths[j]
+	}
+	for j := range widths {
+		widths[j] *= (pageWidth - 2*pageMargin) / total
+	}
+	return widths
+}
+
+// fitText cuts the text short with an ellipsis when it is wider than width.
+func fitText(text string, width, size float64, bold bool) string {
+	if textWidth(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if cut := string(runes) + "..."; textWidth(cut, size, bold) <= width {
+			return cut
+		}
+	}
+	return ""
+}
+
+// textWidth is the width of the text in points. Helvetica-Bold is taken as a tenth wider than
+// Helvetica, which is never less than the actual width.
+func textWidth(text string, size float64, bold bool) float64 {
+	units := 0
+	for _, c := range winAnsi(text) {
+		if c >= ' ' && c <= '~' {
+			units += helveticaWidths[c-' ']
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if bold {
+		width *= 1.1
+	}
+	return width
+}
+
+func (p *pdfPage) text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(text))
+}
+
+// winAnsi encodes the text for the WinAnsiEncoding of the standard fonts. Latin-1 maps onto
+// it directly, the euro sign is the only other character kept.
+func winAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '€':
+			encoded = append(encoded, 0x80)
+		case r >= ' ' && r <= '~', r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		case r < ' ':
+			encoded = append(encoded, ' ')
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// pdfString escapes the encoded text for a literal string, bytes outside of ASCII as octal
+// so the content streams stay plain ASCII.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, c := range winAnsi(text) {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c > '~':
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// write numbers the objects as the catalog, the page tree, the two fonts and the document
+// info, followed by every page and its content stream, then writes the cross-reference table.
+func (l *pdfLayout) write() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(l.pages))
+	for i := range l.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(l.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (personal-finance-go) >>", pdfString(l.title)))
+	for i, page := range l.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 7+2*i))
+		content := page.content.String()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWritePDF(t *testing.T) {
+	rows := make([][]any, 80)
+	for i := range rows {
+		rows[i] = []any{fmt.Sprintf("2025-01-%02d", i%28+1), "Coffee (to go) € " + strings.Repeat("very long description ", 10), -3.5}
+	}
+	doc := testDocument()
+	doc.Sheets = append(doc.Sheets, Sheet{Name: "Transactions", Columns: []string{"Date", "Description", "Amount"}, Rows: rows})
+
+	data, err := Render(doc, PDF)
+	if err != nil {
+		t.Fatal("Error writing the PDF:", err)
+	}
+	pdf := string(data)
+
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("Missing the PDF header or trailer")
+	}
+	pages := strings.Count(pdf, "/Type /Page ")
+	if pages < 3 {
+		t.Fatalf("Expected the transactions to span pages, got %d pages", pages)
+	}
+	if !strings.Contains(pdf, fmt.Sprintf("/Count %d", pages)) || !strings.Contains(pdf, fmt.Sprintf("(Page %d of %d)", pages, pages)) {
+		t.Error("Expected the page count in the page tree and the footer")
+	}
+	if !strings.Contains(pdf, "(Transactions \\(continued\\))") {
+		t.Error("Expected the table heading repeated on the next page")
+	}
+	if !strings.Contains(pdf, "(Coffee \\(to go\\) \\200 very long") || !strings.Contains(pdf, "...) Tj") {
+		t.Error("Expected the escaped description cut short")
+	}
+
+	// the cross-reference table has to point at every object.
+	xref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	if xref == nil {
+		t.Fatal("Missing startxref")
+	}
+	start, _ := strconv.Atoi(xref[1])
+	if !strings.HasPrefix(pdf[start:], "xref\n") {
+		t.Fatal("startxref does not point at the cross-reference table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[start:], -1)
+	if len(entries) != 5+2*pages {
+		t.Errorf("Expected %d objects, got %d", 5+2*pages, len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if !bytes.HasPrefix(data[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))) {
+			t.Errorf("Object %d is not at offset %d", i+1, offset)
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	got := pdfString("a (b) \\ é ✓\n")
+	if got != "a \\(b\\) \\\\ \\351 ? " {
+		t.Errorf("Unexpected escaped string %q", got)
+	}
+}
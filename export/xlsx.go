@@ -0,0 +1,183 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	xlsxMain          = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelationships = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxPackageRels   = "http://schemas.openxmlformats.org/package/2006/relationships"
+	xmlHeader         = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	// cell styles, indexes into cellXfs of xlsxStyles.
+	styleHeader = 1
+	styleAmount = 2
+
+	maxSheetName = 31
+)
+
+// xlsxStyles has a bold font for the column headers and a #,##0.00 number format for amounts.
+const xlsxStyles = xmlHeader + `<styleSheet xmlns="` + xlsxMain + `">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// WriteXLSX writes an Office Open XML workbook with a worksheet for every sheet. Strings are
+// stored inline, so there is no shared strings part.
+func WriteXLSX(doc *Document) ([]byte, error) {
+	sheets := doc.Sheets
+	if len(sheets) == 0 {
+		// a workbook needs at least one worksheet.
+		sheets = []Sheet{{}}
+	}
+	names := sheetNames(sheets)
+
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xmlHeader + `<workbook xmlns="` + xlsxMain + `" xmlns:r="` + xlsxRelationships + `"><sheets>`)
+	workbookRels.WriteString(xmlHeader + `<Relationships xmlns="` + xlsxPackageRels + `">`)
+	for i := range sheets {
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(names[i]), i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, xlsxRelationships, i+1)
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="%s/styles" Target="styles.xml"/></Relationships>`, len(sheets)+1, xlsxRelationships)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="` + xlsxPackageRels + `">` +
+			`<Relationship Id="rId1" Type="` + xlsxRelationships + `/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i := range sheets {
+		parts = append(parts, struct{ name, content string }{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheetXML(&sheets[i])})
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// worksheetXML writes the columns as a bold header row followed by the rows.
+func worksheetXML(sheet *Sheet) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<worksheet xmlns="` + xlsxMain + `"><sheetData>`)
+	if len(sheet.Columns) > 0 {
+		b.WriteString(`<row r="1">`)
+		for j, column := range sheet.Columns {
+			writeCell(&b, j, 1, column, styleHeader)
+		}
+		b.WriteString(`</row>`)
+	}
+	for i, row := range sheet.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+2)
+		for j, cell := range row {
+			style := 0
+			if _, ok := cell.(float64); ok {
+				style = styleAmount
+			}
+			writeCell(&b, j, i+2, cell, style)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+func writeCell(b *strings.Builder, col, row int, cell any, style int) {
+	ref := columnName(col) + strconv.Itoa(row)
+	styleAttr := ""
+	if style != 0 {
+		styleAttr = fmt.Sprintf(` s="%d"`, style)
+	}
+	switch v := cell.(type) {
+	case float64:
+		fmt.Fprintf(b, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(v, 'f', -1, 64))
+	case int, int64:
+		fmt.Fprintf(b, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+	default:
+		fmt.Fprintf(b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, styleAttr, escapeXML(formatCell(cell)))
+	}
+}
+
+// columnName is the spreadsheet column letters of the zero based index, A to Z, then AA and on.
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// sheetNames makes the names valid worksheet names, at most 31 characters without []:*?/\,
+// and unique regardless of case.
+func sheetNames(sheets []Sheet) []string {
+	names := make([]string, len(sheets))
+	seen := make(map[string]bool)
+	for i := range sheets {
+		name := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '-'
+			}
+			return r
+		}, strings.TrimSpace(sheets[i].Name))
+		name = strings.Trim(name, "'")
+		if name == "" {
+			name = fmt.Sprintf("Sheet%d", i+1)
+		}
+		base := name
+		name = truncateRunes(base, maxSheetName)
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncateRunes(base, maxSheetName-len(suffix)) + suffix
+		}
+		seen[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
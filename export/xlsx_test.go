@@ -0,0 +1,121 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	data, err := Render(testDocument(), XLSX)
+	if err != nil {
+		t.Fatal("Error writing the workbook:", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal("Error opening the workbook:", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal("Error opening", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(content)
+
+		// every part has to be well formed.
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Invalid XML in %s: %v", f.Name, err)
+			}
+		}
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Missing part %s", name)
+		}
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal([]byte(parts["xl/workbook.xml"]), &workbook); err != nil {
+		t.Fatal("Error reading the workbook:", err)
+	}
+	if len(workbook.Sheets) != 2 || workbook.Sheets[0].Name != "Summary" || workbook.Sheets[1].Name != "Categories" {
+		t.Errorf("Unexpected sheets: %v", workbook.Sheets)
+	}
+
+	summary := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Range</t></is></c>`,
+		`<c r="B2" s="2"><v>1250.5</v></c>`,
+		`<c r="C3"><v>2</v></c>`,
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected %s in the summary sheet", want)
+		}
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet2.xml"], "Rent, &#34;flat&#34;") {
+		t.Error("Expected the escaped category in the categories sheet")
+	}
+}
+
+func TestWriteXLSX_NoSheets(t *testing.T) {
+	data, err := WriteXLSX(&Document{})
+	if err != nil {
+		t.Fatal("Error writing the workbook:", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal("Error opening the workbook:", err)
+	}
+	found := false
+	for _, f := range archive.File {
+		found = found || f.Name == "xl/worksheets/sheet1.xml"
+	}
+	if !found {
+		t.Error("Expected an empty worksheet")
+	}
+}
+
+func TestSheetNames(t *testing.T) {
+	sheets := []Sheet{
+		{Name: "Payment methods"},
+		{Name: "payment methods"},
+		{Name: "Q1/Q2 [draft]"},
+		{Name: ""},
+		{Name: strings.Repeat("x", 40)},
+		{Name: strings.Repeat("x", 40)},
+	}
+	want := []string{"Payment methods", "payment methods (2)", "Q1-Q2 -draft-", "Sheet4", strings.Repeat("x", 31), strings.Repeat("x", 27) + " (2)"}
+
+	got := sheetNames(sheets)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Sheet %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != want {
+			t.Errorf("Column %d: expected %s, got %s", index, want, got)
+		}
+	}
+}
@@ -3,11 +3,18 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	"strings"
+	_ "time/tzdata" // digest schedules use IANA timezones, also where the system has no zoneinfo.
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/hld3/personal-finance-go/controller"
 	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
 	"github.com/hld3/personal-finance-go/service"
+	"github.com/hld3/personal-finance-go/storage"
 	"github.com/joho/godotenv"
 )
 
@@ -16,17 +23,207 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to load env file:", err)
 	}
+
 	db := database.ConnectDB()
 	defer db.Close()
+	err = database.Migrate(db)
+	if err != nil {
+		log.Fatal("Failed to migrate the database:", err)
+	}
 
-	dbManager := database.SQLManager{DB: db} // implementation of UserDatabase interface
-	userService := service.UserService{UDBI: &dbManager} // implementation of UserServiceInterface
+	dbManager := database.SQLManager{DB: db, Audit: true} // implementation of UserDatabase interface
+	auditService := service.AuditService{AUDBI: &dbManager, Admins: auditAdmins()}
+	eventBus := service.EventBus{OBDBI: &dbManager}
+	webhookService := service.WebhookService{WHDBI: &dbManager, Channel: &notify.WebhookChannel{}}
+	for _, event := range []domain.EventType{domain.EVENT_TRANSACTION_CREATED, domain.EVENT_TRANSACTION_UPDATED, domain.EVENT_TRANSACTION_DELETED, domain.EVENT_USER_UPDATED} {
+		eventBus.Subscribe("webhooks", event, webhookService.HandleEvent)
+	}
+	userService := service.UserService{UDBI: &dbManager, Events: &eventBus} // implementation of UserServiceInterface
+	duplicateService := service.DuplicateService{DDBI: &dbManager, TDBI: &dbManager}
+	tagService := service.TagService{TGDBI: &dbManager}
+	reconciliationService := service.ReconciliationService{RCDBI: &dbManager, TDBI: &dbManager, Events: &eventBus}
+	payeeService := service.PayeeService{PDBI: &dbManager, TDBI: &dbManager, Reconciler: &reconciliationService, Events: &eventBus}
+	classifierService := service.ClassifierService{CDBI: &dbManager, TDBI: &dbManager, Reconciler: &reconciliationService, Events: &eventBus}
+	ruleService := service.RuleService{RDBI: &dbManager, TDBI: &dbManager, Tags: &tagService, Reconciler: &reconciliationService, Events: &eventBus, Classifier: &classifierService}
+	budgetService := service.BudgetService{BDBI: &dbManager}
+	goalService := service.GoalService{GDBI: &dbManager}
+	forecastService := service.ForecastService{SDBI: &dbManager, TDBI: &dbManager}
+	subscriptionService := service.SubscriptionService{SBDBI: &dbManager, TDBI: &dbManager, SDBI: &dbManager}
+	reportService := service.ReportService{TDBI: &dbManager, Tags: &tagService}
+	investmentService := service.InvestmentService{IDBI: &dbManager}
+	loanService := service.LoanService{LDBI: &dbManager, TDBI: &dbManager}
+	cardService := service.CardService{CCDBI: &dbManager, TDBI: &dbManager}
+	netWorthService := service.NetWorthService{NDBI: &dbManager, TDBI: &dbManager, Investments: &investmentService, Loans: &loanService}
+	alertChannels := map[domain.AlertChannel]notify.ChannelInterface{domain.ALERT_WEBHOOK: &notify.WebhookChannel{}}
+	var mailer notify.MailerInterface
+	if smtpChannel := notify.ConnectSMTP(); smtpChannel != nil {
+		alertChannels[domain.ALERT_EMAIL] = smtpChannel
+		mailer = smtpChannel
+	}
+	alertService := service.AlertService{ALDBI: &dbManager, UDBI: &dbManager, Budgets: &budgetService, Channels: alertChannels, Events: &eventBus}
+	eventBus.Subscribe("alert-email", domain.EVENT_ALERT_RAISED, alertService.DeliverAlerts(domain.ALERT_EMAIL))
+	eventBus.Subscribe("alert-webhook", domain.EVENT_ALERT_RAISED, alertService.DeliverAlerts(domain.ALERT_WEBHOOK))
+	anomalyService := service.AnomalyService{ANDBI: &dbManager, TDBI: &dbManager, Alerts: &alertService}
+	digestService := service.DigestService{DGDBI: &dbManager, UDBI: &dbManager, Reports: &reportService, Budgets: &budgetService, Anomalies: &anomalyService, Mailer: mailer}
+	attachmentService := service.AttachmentService{ADBI: &dbManager, TDBI: &dbManager, Storage: storage.ConnectStorage()}
+	currencyService := service.CurrencyService{CRDBI: &dbManager, TDBI: &dbManager, DefaultBase: os.Getenv("BASE_CURRENCY"), Reconciler: &reconciliationService, Events: &eventBus, Classifier: &classifierService}
+	taxService := service.TaxService{TXDBI: &dbManager, TDBI: &dbManager, Attachments: &attachmentService}
+	transactionService := service.TransactionService{UDBI: &dbManager, Payees: &payeeService, Rules: &ruleService, Tags: &tagService, Duplicates: &duplicateService, Classifier: &classifierService, Attachments: &attachmentService, Alerts: &alertService, Currencies: &currencyService, Reconciler: &reconciliationService, Events: &eventBus}
+	duplicateService.Transactions = &transactionService
+	reconciliationService.Currencies = &currencyService
 	newValidator := validator.New()
-	
+
 	http.HandleFunc("/user/register", controller.RegisterNewUserControl(&userService, newValidator))
 	http.HandleFunc("/user/login", controller.ConfirmUserLoginControl(&userService, newValidator))
 	http.HandleFunc("/user/profile", controller.RetrieveUserProfileDataControl(&userService))
 	http.HandleFunc("/user/update", controller.UpdateUserProfileDataControl(&userService))
-	log.Fatal(http.ListenAndServe(":8083", nil))
+
+	http.HandleFunc("/transaction/add", controller.AddTransactionControl(&transactionService, newValidator))
+	http.HandleFunc("/transaction/import", controller.ImportTransactionsControl(&transactionService, newValidator))
+	http.HandleFunc("/transaction/list", controller.RetrieveTransactionsControl(&transactionService))
+	http.HandleFunc("/transaction/delete", controller.DeleteTransactionControl(&transactionService))
+
+	http.HandleFunc("/duplicate/suspected", controller.RetrieveSuspectedDuplicatesControl(&duplicateService))
+	http.HandleFunc("/duplicate/merge", controller.MergeDuplicateControl(&duplicateService, newValidator))
+	http.HandleFunc("/duplicate/dismiss", controller.DismissDuplicateControl(&duplicateService, newValidator))
+
+	http.HandleFunc("/rule/add", controller.AddRuleControl(&ruleService, newValidator))
+	http.HandleFunc("/rule/list", controller.RetrieveRulesControl(&ruleService))
+	http.HandleFunc("/rule/delete", controller.DeleteRuleControl(&ruleService))
+	http.HandleFunc("/rule/reapply", controller.ReapplyRulesControl(&ruleService, newValidator))
+
+	http.HandleFunc("/category/suggest", controller.SuggestCategoriesControl(&classifierService))
+	http.HandleFunc("/category/correct", controller.CorrectCategoryControl(&classifierService, newValidator))
+	http.HandleFunc("/category/train", controller.TrainClassifierControl(&classifierService))
+
+	http.HandleFunc("/payee/add", controller.AddPayeeControl(&payeeService, newValidator))
+	http.HandleFunc("/payee/list", controller.RetrievePayeesControl(&payeeService))
+	http.HandleFunc("/payee/merge", controller.MergePayeesControl(&payeeService, newValidator))
+	http.HandleFunc("/payee/spending", controller.RetrievePayeeSpendingControl(&payeeService))
+
+	http.HandleFunc("/tag/list", controller.RetrieveTagsControl(&tagService))
+	http.HandleFunc("/tag/add", controller.TagTransactionsControl(&tagService, newValidator))
+	http.HandleFunc("/tag/remove", controller.UntagTransactionsControl(&tagService, newValidator))
+
+	http.HandleFunc("/attachment/upload", controller.UploadAttachmentControl(&attachmentService))
+	http.HandleFunc("/attachment/list", controller.RetrieveAttachmentsControl(&attachmentService))
+	http.HandleFunc("/attachment/download", controller.DownloadAttachmentControl(&attachmentService))
+	http.HandleFunc("/attachment/delete", controller.DeleteAttachmentControl(&attachmentService))
+
+	http.HandleFunc("/budget/set", controller.SetBudgetControl(&budgetService, newValidator))
+	http.HandleFunc("/budget/list", controller.RetrieveBudgetsControl(&budgetService))
+	http.HandleFunc("/budget/delete", controller.DeleteBudgetControl(&budgetService))
+	http.HandleFunc("/budget/report", controller.RetrieveBudgetReportControl(&budgetService))
+
+	http.HandleFunc("/alert/rule/add", controller.AddAlertRuleControl(&alertService, newValidator))
+	http.HandleFunc("/alert/rule/list", controller.RetrieveAlertRulesControl(&alertService))
+	http.HandleFunc("/alert/rule/delete", controller.DeleteAlertRuleControl(&alertService))
+	http.HandleFunc("/alert/inbox", controller.RetrieveAlertsControl(&alertService))
+	http.HandleFunc("/alert/read", controller.MarkAlertReadControl(&alertService))
+
+	http.HandleFunc("/goal/add", controller.AddGoalControl(&goalService, newValidator))
+	http.HandleFunc("/goal/list", controller.RetrieveGoalsControl(&goalService))
+	http.HandleFunc("/goal/progress", controller.RetrieveGoalProgressControl(&goalService))
+	http.HandleFunc("/goal/delete", controller.DeleteGoalControl(&goalService))
+
+	http.HandleFunc("/schedule/add", controller.AddScheduledItemControl(&forecastService, newValidator))
+	http.HandleFunc("/schedule/list", controller.RetrieveScheduledItemsControl(&forecastService))
+	http.HandleFunc("/schedule/delete", controller.DeleteScheduledItemControl(&forecastService))
+	http.HandleFunc("/forecast", controller.ForecastControl(&forecastService))
+
+	http.HandleFunc("/subscription/list", controller.DiscoverSubscriptionsControl(&subscriptionService))
+	http.HandleFunc("/subscription/confirm", controller.ConfirmSubscriptionControl(&subscriptionService, newValidator))
+	http.HandleFunc("/subscription/dismiss", controller.DismissSubscriptionControl(&subscriptionService, newValidator))
+
+	http.HandleFunc("/report", controller.RetrieveReportControl(&reportService))
+
+	http.HandleFunc("/digest/schedule/set", controller.SetDigestScheduleControl(&digestService, newValidator))
+	http.HandleFunc("/digest/schedule", controller.RetrieveDigestScheduleControl(&digestService))
+	http.HandleFunc("/digest/schedule/delete", controller.DeleteDigestScheduleControl(&digestService))
+	http.HandleFunc("/digest/preview", controller.PreviewDigestControl(&digestService))
+
+	http.HandleFunc("/anomaly/analyze", controller.AnalyzeAnomaliesControl(&anomalyService))
+	http.HandleFunc("/anomaly/list", controller.RetrieveAnomaliesControl(&anomalyService))
+
+	http.HandleFunc("/valuation/add", controller.AddValuationControl(&netWorthService, newValidator))
+	http.HandleFunc("/valuation/list", controller.RetrieveValuationsControl(&netWorthService))
+	http.HandleFunc("/valuation/delete", controller.DeleteValuationControl(&netWorthService))
+	http.HandleFunc("/networth", controller.RetrieveNetWorthControl(&netWorthService))
+	http.HandleFunc("/networth/snapshot", controller.TakeNetWorthSnapshotControl(&netWorthService))
+	http.HandleFunc("/networth/backfill", controller.BackfillNetWorthControl(&netWorthService))
+
+	http.HandleFunc("/security/add", controller.AddSecurityControl(&investmentService, newValidator))
+	http.HandleFunc("/security/list", controller.RetrieveSecuritiesControl(&investmentService))
+	http.HandleFunc("/investment/add", controller.AddInvestmentTransactionControl(&investmentService, newValidator))
+	http.HandleFunc("/investment/list", controller.RetrieveInvestmentTransactionsControl(&investmentService))
+	http.HandleFunc("/investment/prices/import", controller.ImportSecurityPricesControl(&investmentService))
+	http.HandleFunc("/investment/gains", controller.RetrieveRealizedGainsControl(&investmentService))
+	http.HandleFunc("/portfolio", controller.RetrievePortfolioControl(&investmentService))
+
+	http.HandleFunc("/loan/add", controller.AddLoanControl(&loanService, newValidator))
+	http.HandleFunc("/loan/list", controller.RetrieveLoansControl(&loanService))
+	http.HandleFunc("/loan/delete", controller.DeleteLoanControl(&loanService))
+	http.HandleFunc("/loan/schedule", controller.RetrieveLoanScheduleControl(&loanService))
+	http.HandleFunc("/loan/status", controller.RetrieveLoanStatusControl(&loanService))
+	http.HandleFunc("/loan/simulate", controller.SimulateLoanPayoffControl(&loanService))
+
+	http.HandleFunc("/card/add", controller.AddCardControl(&cardService, newValidator))
+	http.HandleFunc("/card/list", controller.RetrieveCardsControl(&cardService))
+	http.HandleFunc("/card/delete", controller.DeleteCardControl(&cardService))
+	http.HandleFunc("/card/statements", controller.RetrieveCardStatementsControl(&cardService))
+	http.HandleFunc("/card/upcoming", controller.RetrieveUpcomingPaymentsControl(&cardService))
+
+	http.HandleFunc("/reconciliation/start", controller.StartReconciliationControl(&reconciliationService, newValidator))
+	http.HandleFunc("/reconciliation/list", controller.RetrieveReconciliationsControl(&reconciliationService))
+	http.HandleFunc("/reconciliation/detail", controller.RetrieveReconciliationControl(&reconciliationService))
+	http.HandleFunc("/reconciliation/clear", controller.UpdateClearedControl(&reconciliationService, newValidator))
+	http.HandleFunc("/reconciliation/finish", controller.FinishReconciliationControl(&reconciliationService))
+	http.HandleFunc("/reconciliation/unlock", controller.UnlockReconciliationControl(&reconciliationService))
+	http.HandleFunc("/reconciliation/delete", controller.DeleteReconciliationControl(&reconciliationService))
+
+	http.HandleFunc("/tax/line/add", controller.AddTaxLineControl(&taxService, newValidator))
+	http.HandleFunc("/tax/line/list", controller.RetrieveTaxLinesControl(&taxService))
+	http.HandleFunc("/tax/line/delete", controller.DeleteTaxLineControl(&taxService))
+	http.HandleFunc("/tax/report", controller.RetrieveTaxReportControl(&taxService))
+
+	http.HandleFunc("/currency/rates/import", controller.ImportRatesControl(&currencyService))
+	http.HandleFunc("/currency/convert", controller.ConvertCurrencyControl(&currencyService))
+	http.HandleFunc("/currency/base", controller.RetrieveBaseCurrencyControl(&currencyService))
+	http.HandleFunc("/currency/base/set", controller.SetBaseCurrencyControl(&currencyService, newValidator))
+	http.HandleFunc("/currency/account", controller.SetAccountCurrencyControl(&currencyService, newValidator))
+
+	http.HandleFunc("/webhook/add", controller.AddWebhookControl(&webhookService, newValidator))
+	http.HandleFunc("/webhook/list", controller.RetrieveWebhooksControl(&webhookService))
+	http.HandleFunc("/webhook/delete", controller.DeleteWebhookControl(&webhookService))
+	http.HandleFunc("/webhook/deliveries", controller.RetrieveWebhookDeliveriesControl(&webhookService))
+	http.HandleFunc("/webhook/redeliver", controller.RedeliverWebhookControl(&webhookService))
+
+	http.HandleFunc("/audit/history", controller.RetrieveAuditHistoryControl(&auditService))
+	http.HandleFunc("/audit/verify", controller.VerifyAuditLogControl(&auditService))
+
+	go netWorthService.RunDailySnapshots()
+	go anomalyService.RunDailyAnalysis()
+	go eventBus.RunDispatcher()
+	go webhookService.RunDeliveries()
+	if mailer != nil {
+		go digestService.RunDigests()
+	}
+	log.Fatal(http.ListenAndServe(":8083", controller.AuditRequests(http.DefaultServeMux)))
 }
 
+// auditAdmins are the users allowed to verify the audit log, the comma separated user ids of
+// AUDIT_ADMINS.
+func auditAdmins() []uuid.UUID {
+	var admins []uuid.UUID
+	for _, id := range strings.Split(os.Getenv("AUDIT_ADMINS"), ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		admin, err := uuid.Parse(id)
+		if err != nil {
+			log.Fatal("Invalid user id in AUDIT_ADMINS:", err)
+		}
+		admins = append(admins, admin)
+	}
+	return admins
+}
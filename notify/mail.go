@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime/quotedprintable"
+	"strings"
+)
+
+// Mail is an email with a plain text body and, optionally, an HTML alternative of it.
+type Mail struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// MailerInterface sends emails that are more than a notification, like the spending digests.
+type MailerInterface interface {
+	Send(m *Mail) error
+}
+
+// Send delivers the mail, as multipart/alternative when it has an HTML body.
+func (s *SMTPChannel) Send(m *Mail) error {
+	if m.HTML == "" {
+		return s.send(m.To, m.Subject, "text/plain; charset=utf-8", crlf(m.Text))
+	}
+
+	var boundary [12]byte
+	rand.Read(boundary[:])
+	b := "alt-" + hex.EncodeToString(boundary[:])
+
+	var body bytes.Buffer
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		fmt.Fprintf(&body, "--%s\r\n", b)
+		fmt.Fprintf(&body, "Content-Type: %s\r\n", part.contentType)
+		body.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		writer := quotedprintable.NewWriter(&body)
+		writer.Write([]byte(crlf(part.content)))
+		writer.Close()
+		body.WriteString("\r\n")
+	}
+	fmt.Fprintf(&body, "--%s--", b)
+	return s.send(m.To, m.Subject, fmt.Sprintf("multipart/alternative; boundary=%q", b), body.String())
+}
+
+// crlf turns the line breaks of a body into the CRLF line breaks of SMTP.
+func crlf(body string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+}
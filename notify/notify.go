@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"log"
+	"os"
+)
+
+// Notification is a message for one recipient, an email address or a webhook URL
+// depending on the channel delivering it.
+type Notification struct {
+	Recipient string
+	Subject   string
+	Message   string
+	Payload   any // sent as the JSON body by webhooks.
+}
+
+type ChannelInterface interface {
+	Deliver(n *Notification) error
+}
+
+// ConnectSMTP configures email delivery from SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and
+// SMTP_FROM. It returns nil when SMTP_HOST is not set, email is then not delivered.
+func ConnectSMTP() *SMTPChannel {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("SMTP_HOST is not set, emails will not be sent.")
+		return nil
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return &SMTPChannel{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+}
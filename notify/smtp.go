@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPChannel sends notifications as plain text emails, and mails with an HTML alternative.
+// Without a Username no authentication is done.
+type SMTPChannel struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPChannel) Deliver(n *Notification) error {
+	return s.send(n.Recipient, n.Subject, "text/plain; charset=utf-8", crlf(n.Message))
+}
+
+// send writes the headers and sends the body, which has to use CRLF line breaks already.
+func (s *SMTPChannel) send(to string, subject string, contentType string, body string) error {
+	recipient := stripLineBreaks(to)
+	if recipient == "" {
+		return fmt.Errorf("email %q has no recipient", subject)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", stripLineBreaks(s.From))
+	fmt.Fprintf(&msg, "To: %s\r\n", recipient)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", stripLineBreaks(subject)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s\r\n", contentType)
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+	msg.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	err := smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, s.From, []string{recipient}, msg.Bytes())
+	if err != nil {
+		log.Printf("Error sending email %q: %v\n", subject, err)
+		return err
+	}
+	return nil
+}
+
+// stripLineBreaks keeps header values from adding headers of their own.
+func stripLineBreaks(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
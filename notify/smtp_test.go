@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer accepts a single mail and sends what it received on the returned channel.
+func fakeSMTPServer(t *testing.T) (string, string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Error starting the SMTP stand-in:", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+
+		var transcript strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+				transcript.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 Go ahead")
+				for {
+					data, err := reader.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+					transcript.WriteString(data)
+				}
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func TestSMTPChannel(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	channel := SMTPChannel{Host: host, Port: port, From: "alerts@finance.test"}
+
+	err := channel.Deliver(&Notification{
+		Recipient: "user@finance.test",
+		Subject:   "Budget alert\r\nBcc: someone@else.test",
+		Message:   "Category 3 budget 85% used.\nCheck your spending.",
+	})
+	if err != nil {
+		t.Fatal("Error delivering the email:", err)
+	}
+
+	mail := <-received
+	for _, want := range []string{
+		"MAIL FROM:<alerts@finance.test>",
+		"RCPT TO:<user@finance.test>",
+		"To: user@finance.test\r\n",
+		"Subject: Budget alertBcc: someone@else.test\r\n",
+		"Category 3 budget 85% used.\r\nCheck your spending.\r\n",
+	} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("Mail is missing %q, got:\n%s", want, mail)
+		}
+	}
+}
+
+func TestSMTPChannel_NoRecipient(t *testing.T) {
+	channel := SMTPChannel{Host: "127.0.0.1", Port: "1", From: "alerts@finance.test"}
+	if err := channel.Deliver(&Notification{Subject: "Budget alert"}); err == nil {
+		t.Error("Expected an error without a recipient")
+	}
+}
+
+func TestSMTPChannel_Send(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	channel := SMTPChannel{Host: host, Port: port, From: "digest@finance.test"}
+
+	err := channel.Send(&Mail{
+		To:      "user@finance.test",
+		Subject: "Your weekly digest",
+		Text:    "Spent 12.50 this week.\nTop category: 3",
+		HTML:    `<p class="total">Spent <b>12.50</b> this week.</p>`,
+	})
+	if err != nil {
+		t.Fatal("Error sending the mail:", err)
+	}
+
+	mail := <-received
+	for _, want := range []string{
+		"RCPT TO:<user@finance.test>",
+		"Content-Type: multipart/alternative; boundary=\"alt-",
+		"Content-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\nSpent 12.50 this week.\r\nTop category: 3\r\n",
+		"Content-Type: text/html; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n<p class=3D\"total\">Spent <b>12.50</b> this week.</p>\r\n",
+	} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("Mail is missing %q, got:\n%s", want, mail)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// WebhookChannel posts the notification payload as JSON to the recipient URL.
+type WebhookChannel struct {
+	Client *http.Client // defaults to a client with a 10 second timeout that only dials public addresses.
+}
+
+var defaultWebhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: 5 * time.Second, Control: dialPublicOnly}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+}
+
+// dialPublicOnly refuses connections to loopback, private, link-local and unspecified addresses,
+// so a user's webhook URL cannot reach the server itself or the network behind it. It checks the
+// address being dialed, after DNS resolution and for every redirect.
+func dialPublicOnly(network string, address string, c syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	ip := addrPort.Addr().Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("webhook address %s is not public", ip)
+	}
+	return nil
+}
+
+func (wc *WebhookChannel) Deliver(n *Notification) error {
+	payload := n.Payload
+	if payload == nil {
+		payload = map[string]string{"subject": n.Subject, "message": n.Message}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	res, err := wc.client().Post(n.Recipient, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Error calling webhook for %q: %v\n", n.Subject, err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		log.Printf("Webhook for %q returned %s\n", n.Subject, res.Status)
+		return fmt.Errorf("webhook returned %s", res.Status)
+	}
+	return nil
+}
+
+// SignatureHeader carries the signature of signed webhooks, see SignWebhook, and TimestampHeader
+// the time it was signed at in Unix seconds.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// SignWebhook is the signature of the body sent at timestamp, "sha256=" followed by the hex
+// HMAC-SHA256 of the timestamp, a dot and the body, keyed with the secret. Receivers compute the
+// same over the timestamp header and the raw body to verify it, and reject old timestamps so a
+// captured request cannot be replayed.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// PostSigned posts the JSON body to url with the headers, the time and the signature of the body.
+// It returns the response status code, or 0 when there was no response.
+func (wc *WebhookChannel) PostSigned(url string, secret string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, SignWebhook(secret, timestamp, body))
+
+	res, err := wc.client().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook returned %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+func (wc *WebhookChannel) client() *http.Client {
+	if wc.Client == nil {
+		return defaultWebhookClient
+	}
+	return wc.Client
+}
@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookChannel(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	channel := WebhookChannel{Client: server.Client()}
+	err := channel.Deliver(&Notification{Recipient: server.URL, Subject: "Low balance", Payload: map[string]any{"accountId": 2}})
+	if err != nil {
+		t.Fatal("Error delivering the webhook:", err)
+	}
+	if got["accountId"] != float64(2) {
+		t.Errorf("Wrong payload, got %v", got)
+	}
+}
+
+func TestWebhookChannel_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	channel := WebhookChannel{Client: server.Client()}
+	if err := channel.Deliver(&Notification{Recipient: server.URL, Subject: "Low balance"}); err == nil {
+		t.Error("Expected an error for a failed webhook")
+	}
+}
+
+func TestWebhookChannel_PostSigned(t *testing.T) {
+	body := []byte(`{"event":"transaction.created"}`)
+	var signature, timestamp, event string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+		timestamp = r.Header.Get(TimestampHeader)
+		event = r.Header.Get("X-Webhook-Event")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	channel := WebhookChannel{Client: server.Client()}
+	status, err := channel.PostSigned(server.URL, "0123456789abcdef", map[string]string{"X-Webhook-Event": "transaction.created"}, body)
+	if err != nil {
+		t.Fatal("Error posting the webhook:", err)
+	}
+	if status != http.StatusAccepted || event != "transaction.created" {
+		t.Errorf("Unexpected status %d or event %q", status, event)
+	}
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(sent, 0)) > time.Minute {
+		t.Fatalf("Unexpected timestamp %q", timestamp)
+	}
+	if signature != SignWebhook("0123456789abcdef", sent, body) {
+		t.Errorf("Unexpected signature %q", signature)
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"event":"transaction.created"}`)
+	// HMAC-SHA256 of "1700000000." and the body keyed with the secret, computed independently.
+	if signature := SignWebhook("0123456789abcdef", 1700000000, body); signature != "sha256=d9af1db06e5fb383c35b888a0be932fc9ae0af270ccc27e57199a7fbede04d4d" {
+		t.Errorf("Unexpected signature %q", signature)
+	}
+	if SignWebhook("0123456789abcdef", 1700000001, body) == SignWebhook("0123456789abcdef", 1700000000, body) {
+		t.Error("Expected the timestamp to be signed")
+	}
+}
+
+func TestWebhookChannel_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	channel := WebhookChannel{}
+	if err := channel.Deliver(&Notification{Recipient: server.URL, Subject: "Low balance"}); err == nil {
+		t.Error("Expected the default client to refuse a loopback address")
+	}
+
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{address: "127.0.0.1:80", wantErr: true},
+		{address: "10.1.2.3:443", wantErr: true},
+		{address: "192.168.0.10:443", wantErr: true},
+		{address: "169.254.169.254:80", wantErr: true},
+		{address: "0.0.0.0:80", wantErr: true},
+		{address: "[::1]:443", wantErr: true},
+		{address: "[fd00::1]:443", wantErr: true},
+		{address: "[::ffff:127.0.0.1]:80", wantErr: true},
+		{address: "93.184.216.34:443", wantErr: false},
+		{address: "[2606:2800:220:1::1]:443", wantErr: false},
+	}
+	for _, test := range tests {
+		err := dialPublicOnly("tcp", test.address, nil)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Dialing %s, got error %v, want error %v", test.address, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
+)
+
+type AlertServiceInterface interface {
+	AddAlertRule(ctx context.Context, ruleData *domain.AlertRuleData) (*domain.AlertRuleDTO, error)
+	RetrieveAlertRules(userId uuid.UUID) ([]domain.AlertRuleDTO, error)
+	DeleteAlertRule(ctx context.Context, alertRuleId uuid.UUID) error
+	CheckTransaction(ctx context.Context, tm *domain.TransactionModel) ([]domain.AlertDTO, error)
+	NotifyAnomalies(ctx context.Context, userId uuid.UUID, anomalies []domain.AnomalyDTO) ([]domain.AlertDTO, error)
+	RetrieveAlerts(userId uuid.UUID, unreadOnly bool) ([]domain.AlertDTO, error)
+	MarkAlertRead(ctx context.Context, alertId uuid.UUID) error
+}
+
+type AlertService struct {
+	ALDBI    database.AlertDatabaseInterface
+	UDBI     database.UserDatabaseInterface // optional, looks up the address to email alerts to.
+	Budgets  BudgetServiceInterface         // optional, budget thresholds are not checked without it.
+	Channels map[domain.AlertChannel]notify.ChannelInterface
+	Events   EventBusInterface // optional, delivers the alerts through the rules channels, alerts only go to the inbox without it.
+}
+
+func (as *AlertService) AddAlertRule(ctx context.Context, ruleData *domain.AlertRuleData) (*domain.AlertRuleDTO, error) {
+	err := ruleData.ValidateAlertRule()
+	if err != nil {
+		return nil, err
+	}
+	rule := ruleData.Rule
+
+	switch {
+	case rule.Type == domain.BUDGET_THRESHOLD && rule.Percent <= 0:
+		return nil, errors.New("budget threshold alerts need a percent")
+	case rule.Type == domain.LOW_BALANCE && rule.AccountId == 0:
+		return nil, errors.New("low balance alerts need an account")
+	case slices.Contains(rule.Channels, domain.ALERT_WEBHOOK) && rule.WebhookURL == "":
+		return nil, errors.New("webhook alerts need a webhook URL")
+	}
+
+	am := convertAlertRuleDTOToModel(&rule)
+	am.AlertRuleId = uuid.New()
+	am.CreatedAt = time.Now().UnixMilli()
+	err = as.ALDBI.AddAlertRule(ctx, &am)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertAlertRuleModelToDTO(&am)
+	return &saved, nil
+}
+
+func (as *AlertService) RetrieveAlertRules(userId uuid.UUID) ([]domain.AlertRuleDTO, error) {
+	rules, err := as.ALDBI.GetAlertRulesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleDTOs := make([]domain.AlertRuleDTO, 0, len(rules))
+	for _, am := range rules {
+		ruleDTOs = append(ruleDTOs, convertAlertRuleModelToDTO(&am))
+	}
+	return ruleDTOs, nil
+}
+
+func (as *AlertService) DeleteAlertRule(ctx context.Context, alertRuleId uuid.UUID) error {
+	return as.ALDBI.DeleteAlertRule(ctx, alertRuleId)
+}
+
+// CheckTransaction evaluates the users alert rules against the budget of the transactions category
+// and the balance of its account. Alerts are saved to the inbox with an alert.raised event, which
+// DeliverAlerts delivers through the rules channels, unless the rule already fired in the period.
+// It returns the alerts that fired.
+func (as *AlertService) CheckTransaction(ctx context.Context, tm *domain.TransactionModel) ([]domain.AlertDTO, error) {
+	rules, err := as.ALDBI.GetAlertRulesByUserId(tm.UserId)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	var budget *domain.BudgetCategoryDTO
+	var budgetMonth string
+	if as.Budgets != nil && tm.Type == domain.EXPENSE && tm.Status != domain.CANCELLED {
+		budgetMonth = time.UnixMilli(tm.Date).UTC().Format(monthLayout)
+		report, err := as.Budgets.RetrieveBudgetReport(tm.UserId, budgetMonth)
+		if err != nil {
+			return nil, err
+		}
+		for i, category := range report.Categories {
+			if category.CategoryId == tm.CategoryId {
+				budget = &report.Categories[i]
+			}
+		}
+	}
+
+	var balance *float64
+	fired := []domain.AlertDTO{}
+	for _, rule := range rules {
+		alert := domain.AlertModel{AlertId: uuid.New(), AlertRuleId: rule.AlertRuleId, UserId: tm.UserId, CreatedAt: time.Now().UnixMilli()}
+
+		switch rule.Type {
+		case domain.BUDGET_THRESHOLD:
+			if budget == nil || (rule.CategoryId != 0 && rule.CategoryId != budget.CategoryId) {
+				continue
+			}
+			used := budgetUsed(budget)
+			if used < rule.Percent {
+				continue
+			}
+			alert.Period = budgetMonth
+			alert.CategoryId = budget.CategoryId
+			if math.IsInf(used, 1) {
+				alert.Message = fmt.Sprintf("Category %d has spent %.2f with %.2f of its %s budget available.", budget.CategoryId, budget.Actual, budget.Available, budgetMonth)
+			} else {
+				alert.Message = fmt.Sprintf("Category %d has used %.0f%% of its %s budget, %.2f of %.2f.", budget.CategoryId, used, budgetMonth, budget.Actual, budget.Available)
+			}
+		case domain.LOW_BALANCE:
+			if rule.AccountId != tm.AccountId {
+				continue
+			}
+			if balance == nil {
+				accountBalance, err := as.ALDBI.GetAccountBalance(tm.UserId, tm.AccountId)
+				if err != nil {
+					return nil, err
+				}
+				balance = &accountBalance
+			}
+			if *balance >= rule.Balance {
+				continue
+			}
+			alert.Period = time.Now().UTC().Format(time.DateOnly)
+			alert.AccountId = tm.AccountId
+			alert.Message = fmt.Sprintf("Account %d balance is %.2f, below %.2f.", tm.AccountId, *balance, rule.Balance)
+		default:
+			continue
+		}
+
+		alertDTO, saved, err := as.addAlert(ctx, &alert)
+		if err != nil {
+			return nil, err
+		}
+		if saved {
+			fired = append(fired, alertDTO)
+		}
+	}
+	return fired, nil
+}
+
+// budgetUsed is the percentage of the available budget spent. Any spending uses all of a budget
+// with nothing available, such as one overspent last month.
+func budgetUsed(budget *domain.BudgetCategoryDTO) float64 {
+	switch {
+	case budget.Available > 0:
+		return budget.Actual / budget.Available * 100
+	case budget.Actual > 0:
+		return math.Inf(1)
+	}
+	return 0
+}
+
+// NotifyAnomalies raises an alert for every anomaly watched by one of the users anomaly rules.
+// It returns the alerts that fired.
+func (as *AlertService) NotifyAnomalies(ctx context.Context, userId uuid.UUID, anomalies []domain.AnomalyDTO) ([]domain.AlertDTO, error) {
+	rules, err := as.ALDBI.GetAlertRulesByUserId(userId)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	fired := []domain.AlertDTO{}
+	for _, rule := range rules {
+		if rule.Type != domain.ANOMALY {
+			continue
+		}
+		for _, anomaly := range anomalies {
+			if rule.CategoryId != 0 && rule.CategoryId != anomaly.CategoryId {
+				continue
+			}
+
+			alert := domain.AlertModel{
+				AlertId:     uuid.New(),
+				AlertRuleId: rule.AlertRuleId,
+				UserId:      userId,
+				Period:      anomaly.AnomalyId.String(),
+				CategoryId:  anomaly.CategoryId,
+				Message:     anomaly.Message,
+				CreatedAt:   time.Now().UnixMilli(),
+			}
+			alertDTO, saved, err := as.addAlert(ctx, &alert)
+			if err != nil {
+				return nil, err
+			}
+			if saved {
+				fired = append(fired, alertDTO)
+			}
+		}
+	}
+	return fired, nil
+}
+
+func (as *AlertService) RetrieveAlerts(userId uuid.UUID, unreadOnly bool) ([]domain.AlertDTO, error) {
+	alerts, err := as.ALDBI.GetAlertsByUserId(userId, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	alertDTOs := make([]domain.AlertDTO, 0, len(alerts))
+	for _, am := range alerts {
+		alertDTOs = append(alertDTOs, convertAlertModelToDTO(&am))
+	}
+	return alertDTOs, nil
+}
+
+func (as *AlertService) MarkAlertRead(ctx context.Context, alertId uuid.UUID) error {
+	return as.ALDBI.MarkAlertRead(ctx, alertId)
+}
+
+// DeliverAlerts is the event bus subscriber that delivers the raised alerts through the channel,
+// for the rules that use it. A failed delivery is handed over again by the event bus, the alert
+// is in the inbox either way.
+func (as *AlertService) DeliverAlerts(channelType domain.AlertChannel) EventHandler {
+	return func(event *domain.EventDTO) error {
+		var alert domain.AlertDTO
+		err := json.Unmarshal(event.Data, &alert)
+		if err != nil {
+			return err
+		}
+		rules, err := as.ALDBI.GetAlertRulesByUserId(alert.UserId)
+		if err != nil {
+			return err
+		}
+		// the rule may have been deleted since it fired.
+		i := slices.IndexFunc(rules, func(rule domain.AlertRuleModel) bool { return rule.AlertRuleId == alert.AlertRuleId })
+		if i < 0 || !slices.Contains(rules[i].Channels, channelType) {
+			return nil
+		}
+		return as.deliver(&rules[i], channelType, &alert)
+	}
+}
+
+// addAlert saves the alert with the event that delivers it, it reports whether the alert was saved.
+func (as *AlertService) addAlert(ctx context.Context, alert *domain.AlertModel) (domain.AlertDTO, bool, error) {
+	alertDTO := convertAlertModelToDTO(alert)
+	events, err := recordEvent(as.Events, domain.EVENT_ALERT_RAISED, alert.UserId, alert.AlertId, alertDTO)
+	if err != nil {
+		return alertDTO, false, err
+	}
+	saved, err := as.ALDBI.AddAlert(ctx, alert, events...)
+	return alertDTO, saved, err
+}
+
+func (as *AlertService) deliver(rule *domain.AlertRuleModel, channelType domain.AlertChannel, alert *domain.AlertDTO) error {
+	channel := as.Channels[channelType]
+	if channel == nil {
+		log.Printf("No channel configured for alert channel %d, alert %v not delivered.\n", channelType, alert.AlertId)
+		return nil
+	}
+
+	n := notify.Notification{Subject: rule.Type.String() + " alert", Message: alert.Message, Payload: alert}
+	switch channelType {
+	case domain.ALERT_EMAIL:
+		if as.UDBI == nil {
+			return nil
+		}
+		user, err := as.UDBI.RetrieveUserByUserId(rule.UserId)
+		if err != nil {
+			return err
+		}
+		n.Recipient = user.Email
+	case domain.ALERT_WEBHOOK:
+		n.Recipient = rule.WebhookURL
+	}
+	return channel.Deliver(&n)
+}
+
+func convertAlertRuleDTOToModel(from *domain.AlertRuleDTO) domain.AlertRuleModel {
+	return domain.AlertRuleModel{
+		AlertRuleId: from.AlertRuleId,
+		UserId:      from.UserId,
+		Type:        from.Type,
+		CategoryId:  from.CategoryId,
+		AccountId:   from.AccountId,
+		Percent:     from.Percent,
+		Balance:     from.Balance,
+		Channels:    from.Channels,
+		WebhookURL:  from.WebhookURL,
+		CreatedAt:   from.CreatedAt,
+	}
+}
+
+func convertAlertRuleModelToDTO(from *domain.AlertRuleModel) domain.AlertRuleDTO {
+	return domain.AlertRuleDTO{
+		AlertRuleId: from.AlertRuleId,
+		UserId:      from.UserId,
+		Type:        from.Type,
+		CategoryId:  from.CategoryId,
+		AccountId:   from.AccountId,
+		Percent:     from.Percent,
+		Balance:     from.Balance,
+		Channels:    from.Channels,
+		WebhookURL:  from.WebhookURL,
+		CreatedAt:   from.CreatedAt,
+	}
+}
+
+func convertAlertModelToDTO(from *domain.AlertModel) domain.AlertDTO {
+	return domain.AlertDTO{
+		AlertId:     from.AlertId,
+		AlertRuleId: from.AlertRuleId,
+		UserId:      from.UserId,
+		Period:      from.Period,
+		CategoryId:  from.CategoryId,
+		AccountId:   from.AccountId,
+		Message:     from.Message,
+		Read:        from.Read,
+		CreatedAt:   from.CreatedAt,
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
+)
+
+func setUpAlertModel(db *sql.DB) {
+	stmt := `create table alert_rule_model (
+		id integer primary key autoincrement,
+		alert_rule_id text not null,
+		user_id text not null,
+		type integer not null,
+		category_id integer not null,
+		account_id integer not null,
+		percent float not null,
+		balance float not null,
+		channels text not null,
+		webhook_url text not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating alert_rule_model table:", err)
+	}
+
+	stmt = `create table alert_model (
+		id integer primary key autoincrement,
+		alert_id text not null,
+		alert_rule_id text not null,
+		user_id text not null,
+		period text not null,
+		category_id integer not null,
+		account_id integer not null,
+		message text not null,
+		is_read boolean not null,
+		created_at integer not null,
+		unique (alert_rule_id, period, category_id, account_id)
+	)`
+
+	_, err = db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating alert_model table:", err)
+	}
+}
+
+type recordingChannel struct {
+	delivered []notify.Notification
+}
+
+// failingChannel fails the first delivery.
+type failingChannel struct {
+	recordingChannel
+	attempts int
+}
+
+func (c *failingChannel) Deliver(n *notify.Notification) error {
+	c.attempts++
+	if c.attempts == 1 {
+		return errors.New("connection refused")
+	}
+	return c.recordingChannel.Deliver(n)
+}
+
+func (c *recordingChannel) Deliver(n *notify.Notification) error {
+	c.delivered = append(c.delivered, *n)
+	return nil
+}
+
+func TestAlerts_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpBudgetModel(db)
+	setUpAlertModel(db)
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+	webhooks := &recordingChannel{}
+	budgetService := BudgetService{BDBI: &udb}
+	eventBus := EventBus{OBDBI: &udb}
+	alertService := AlertService{ALDBI: &udb, Budgets: &budgetService, Channels: map[domain.AlertChannel]notify.ChannelInterface{domain.ALERT_WEBHOOK: webhooks}, Events: &eventBus}
+	eventBus.Subscribe("alert-webhook", domain.EVENT_ALERT_RAISED, alertService.DeliverAlerts(domain.ALERT_WEBHOOK))
+	dispatch := func() {
+		if err := eventBus.DispatchPending(time.Now().Add(time.Second)); err != nil {
+			t.Fatal("Error dispatching events:", err)
+		}
+	}
+	transactionService := TransactionService{UDBI: &udb, Alerts: &alertService}
+
+	userId := uuid.New()
+	month := time.Now().UTC().Format(monthLayout)
+	budget := domain.BudgetDTO{UserId: userId, CategoryId: 3, Month: month, Amount: 100}
+	if _, err := budgetService.SetBudget(context.Background(), &domain.BudgetData{Budget: budget, Validator: validator.New()}); err != nil {
+		t.Fatal("Error setting the budget:", err)
+	}
+
+	rules := []domain.AlertRuleDTO{
+		{UserId: userId, Type: domain.BUDGET_THRESHOLD, Percent: 80, Channels: []domain.AlertChannel{domain.ALERT_WEBHOOK}, WebhookURL: "https://hooks.finance.test/alerts"},
+		{UserId: userId, Type: domain.BUDGET_THRESHOLD, CategoryId: 3, Percent: 100},
+		{UserId: userId, Type: domain.LOW_BALANCE, AccountId: 1, Balance: 50},
+	}
+	for _, rule := range rules {
+		if _, err := alertService.AddAlertRule(context.Background(), &domain.AlertRuleData{Rule: rule, Validator: validator.New()}); err != nil {
+			t.Fatal("Error adding the alert rule:", err)
+		}
+	}
+
+	add := func(amount float64, transactionType domain.TransactionType) []domain.AlertDTO {
+		transaction := domain.TransactionDTOBuilder().Build()
+		transaction.UserId = userId
+		transaction.CategoryId = 3
+		transaction.AccountId = 1
+		transaction.Amount = amount
+		transaction.Date = time.Now().UnixMilli()
+		transaction.Type = transactionType
+		transaction.Status = domain.CLEARED
+		result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+		if err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+		return result.Alerts
+	}
+
+	if alerts := add(200, domain.INCOME); len(alerts) != 0 {
+		t.Errorf("Expected no alerts for income, got %v", alerts)
+	}
+	if alerts := add(70, domain.EXPENSE); len(alerts) != 0 {
+		t.Errorf("Expected no alerts below the thresholds, got %v", alerts)
+	}
+
+	alerts := add(15, domain.EXPENSE)
+	if len(alerts) != 1 || alerts[0].CategoryId != 3 || alerts[0].Period != month {
+		t.Fatalf("Expected the 80%% alert, got %v", alerts)
+	}
+	// the alert is delivered by the event bus, not while adding the transaction.
+	if len(webhooks.delivered) != 0 {
+		t.Errorf("Expected no delivery before the events are dispatched, got %v", webhooks.delivered)
+	}
+	dispatch()
+	if len(webhooks.delivered) != 1 || webhooks.delivered[0].Recipient != "https://hooks.finance.test/alerts" {
+		t.Errorf("Expected the alert to be posted to the webhook, got %v", webhooks.delivered)
+	}
+
+	// 80% already fired this month, 100% and the low balance (200 - 70 - 15 - 80 = 35) fire now.
+	alerts = add(80, domain.EXPENSE)
+	if len(alerts) != 2 || alerts[0].CategoryId != 3 || alerts[1].AccountId != 1 {
+		t.Fatalf("Expected the 100%% and low balance alerts, got %v", alerts)
+	}
+	if alerts = add(5, domain.EXPENSE); len(alerts) != 0 {
+		t.Errorf("Expected no repeated alerts in the same period, got %v", alerts)
+	}
+	dispatch()
+	if len(webhooks.delivered) != 1 {
+		t.Errorf("Only the first rule posts to the webhook, got %v", webhooks.delivered)
+	}
+
+	inbox, err := alertService.RetrieveAlerts(userId, true)
+	if err != nil || len(inbox) != 3 {
+		t.Fatalf("Expected three unread alerts, got %v, err: %v", inbox, err)
+	}
+	err = alertService.MarkAlertRead(context.Background(), inbox[0].AlertId)
+	if err != nil {
+		t.Fatal("Error marking the alert read:", err)
+	}
+	if inbox, _ = alertService.RetrieveAlerts(userId, true); len(inbox) != 2 {
+		t.Errorf("Expected two unread alerts, got %v", inbox)
+	}
+}
+
+func TestAlerts_NothingAvailable_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpBudgetModel(db)
+	setUpAlertModel(db)
+	udb := database.SQLManager{DB: db}
+	budgetService := BudgetService{BDBI: &udb}
+	alertService := AlertService{ALDBI: &udb, Budgets: &budgetService}
+	transactionService := TransactionService{UDBI: &udb, Alerts: &alertService}
+
+	userId := uuid.New()
+	month := time.Now().UTC().Format(monthLayout)
+	budget := domain.BudgetDTO{UserId: userId, CategoryId: 3, Month: month, Amount: 0}
+	if _, err := budgetService.SetBudget(context.Background(), &domain.BudgetData{Budget: budget, Validator: validator.New()}); err != nil {
+		t.Fatal("Error setting the budget:", err)
+	}
+	rule := domain.AlertRuleDTO{UserId: userId, Type: domain.BUDGET_THRESHOLD, CategoryId: 3, Percent: 100}
+	if _, err := alertService.AddAlertRule(context.Background(), &domain.AlertRuleData{Rule: rule, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the alert rule:", err)
+	}
+
+	transaction := domain.TransactionDTOBuilder().Build()
+	transaction.UserId = userId
+	transaction.CategoryId = 3
+	transaction.Amount = 10
+	transaction.Date = time.Now().UnixMilli()
+	transaction.Type = domain.EXPENSE
+	transaction.Status = domain.CLEARED
+	result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+	if len(result.Alerts) != 1 || result.Alerts[0].CategoryId != 3 {
+		t.Fatalf("Expected spending a budget with nothing available to fire the rule, got %v", result.Alerts)
+	}
+}
+
+func TestDeliverAlerts_Retried(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpAlertModel(db)
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+	webhooks := &failingChannel{}
+	eventBus := EventBus{OBDBI: &udb}
+	alertService := AlertService{ALDBI: &udb, Channels: map[domain.AlertChannel]notify.ChannelInterface{domain.ALERT_WEBHOOK: webhooks}, Events: &eventBus}
+	eventBus.Subscribe("alert-webhook", domain.EVENT_ALERT_RAISED, alertService.DeliverAlerts(domain.ALERT_WEBHOOK))
+
+	userId := uuid.New()
+	rule := domain.AlertRuleDTO{UserId: userId, Type: domain.ANOMALY, Channels: []domain.AlertChannel{domain.ALERT_WEBHOOK}, WebhookURL: "https://hooks.finance.test/anomalies"}
+	if _, err := alertService.AddAlertRule(context.Background(), &domain.AlertRuleData{Rule: rule, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the alert rule:", err)
+	}
+	anomaly := domain.AnomalyDTO{AnomalyId: uuid.New(), CategoryId: 5, Message: "Unusual spending"}
+	fired, err := alertService.NotifyAnomalies(context.Background(), userId, []domain.AnomalyDTO{anomaly})
+	if err != nil || len(fired) != 1 {
+		t.Fatalf("Expected one alert, got %v, err: %v", fired, err)
+	}
+
+	now := time.Now().Add(time.Second)
+	if err := eventBus.DispatchPending(now); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	if webhooks.attempts != 1 || len(webhooks.delivered) != 0 {
+		t.Fatalf("Expected a failed delivery, got %d attempts", webhooks.attempts)
+	}
+	if err := eventBus.DispatchPending(now.Add(eventRetryDelay)); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	if len(webhooks.delivered) != 1 || webhooks.delivered[0].Message != anomaly.Message {
+		t.Errorf("Expected the alert to be delivered on the retry, got %v", webhooks.delivered)
+	}
+}
+
+func TestAddAlertRule_Invalid(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpAlertModel(db)
+	udb := database.SQLManager{DB: db}
+	alertService := AlertService{ALDBI: &udb}
+
+	tests := []struct {
+		name string
+		rule domain.AlertRuleDTO
+	}{
+		{name: "No percent", rule: domain.AlertRuleDTO{UserId: uuid.New(), Type: domain.BUDGET_THRESHOLD}},
+		{name: "No account", rule: domain.AlertRuleDTO{UserId: uuid.New(), Type: domain.LOW_BALANCE, Balance: 100}},
+		{name: "No webhook URL", rule: domain.AlertRuleDTO{UserId: uuid.New(), Percent: 80, Channels: []domain.AlertChannel{domain.ALERT_WEBHOOK}}},
+		{name: "Unknown channel", rule: domain.AlertRuleDTO{UserId: uuid.New(), Percent: 80, Channels: []domain.AlertChannel{7}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := alertService.AddAlertRule(context.Background(), &domain.AlertRuleData{Rule: test.rule, Validator: validator.New()})
+			if err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
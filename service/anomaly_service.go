@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+const (
+	// anomalyAnalysisDays of recent transactions, and the months they fall in, are checked.
+	anomalyAnalysisDays = 90
+	// anomalyBaselineMonths of history before a transaction or month make up its baseline.
+	anomalyBaselineMonths = 12
+	// anomalyMinHistory is the fewest amounts a baseline is built from.
+	anomalyMinHistory = 3
+	// anomalyThreshold is the modified z-score at or above which an amount is flagged.
+	anomalyThreshold = 3.5
+	// anomalyMinSpread is the smallest deviation as a fraction of the median, so a payee that
+	// always charges the same amount still has a spread to compare against.
+	anomalyMinSpread = 0.1
+)
+
+type AnomalyServiceInterface interface {
+	Analyze(ctx context.Context, userId uuid.UUID) ([]domain.AnomalyDTO, error)
+	RetrieveAnomalies(userId uuid.UUID) ([]domain.AnomalyDTO, error)
+}
+
+type AnomalyService struct {
+	ANDBI  database.AnomalyDatabaseInterface
+	TDBI   database.TransactionDatabaseInterface
+	Alerts AlertServiceInterface // optional, anomalies are only kept for the endpoint without it.
+}
+
+// Analyze checks the users recent expenses against their history and saves the anomalies not
+// found before, which are then raised through the users anomaly alert rules. It returns the new anomalies.
+func (as *AnomalyService) Analyze(ctx context.Context, userId uuid.UUID) ([]domain.AnomalyDTO, error) {
+	now := time.Now()
+	historyStart := startOfDay(now).AddDate(0, -anomalyBaselineMonths, -anomalyAnalysisDays)
+	transactions, err := as.TDBI.GetTransactionsByUserId(userId, historyStart.UnixMilli(), startOfDay(now).UnixMilli()+dayMillis-1)
+	if err != nil {
+		return nil, err
+	}
+
+	found := []domain.AnomalyDTO{}
+	for _, am := range findAnomalies(userId, transactions, now) {
+		saved, err := as.ANDBI.AddAnomaly(ctx, &am)
+		if err != nil {
+			return nil, err
+		}
+		if saved {
+			found = append(found, convertAnomalyModelToDTO(&am))
+		}
+	}
+
+	if as.Alerts != nil && len(found) > 0 {
+		if _, err := as.Alerts.NotifyAnomalies(ctx, userId, found); err != nil {
+			log.Println("Error raising alerts for anomalies:", err)
+		}
+	}
+	return found, nil
+}
+
+func (as *AnomalyService) RetrieveAnomalies(userId uuid.UUID) ([]domain.AnomalyDTO, error) {
+	anomalies, err := as.ANDBI.GetAnomaliesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	anomalyDTOs := make([]domain.AnomalyDTO, 0, len(anomalies))
+	for _, am := range anomalies {
+		anomalyDTOs = append(anomalyDTOs, convertAnomalyModelToDTO(&am))
+	}
+	return anomalyDTOs, nil
+}
+
+// AnalyzeAllUsers analyzes every user with recent transactions, as the system actor. A failure
+// for one user is logged and does not stop the others.
+func (as *AnomalyService) AnalyzeAllUsers() error {
+	since := startOfDay(time.Now()).AddDate(0, 0, -anomalyAnalysisDays)
+	userIds, err := as.ANDBI.GetActiveUserIds(since.UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for _, userId := range userIds {
+		if _, err := as.Analyze(context.Background(), userId); err != nil {
+			log.Printf("Error analyzing the transactions of user %v: %v\n", userId, err)
+		}
+	}
+	return nil
+}
+
+// RunDailyAnalysis analyzes every user right away and then shortly after each UTC midnight.
+// It never returns and is meant to run in its own goroutine.
+func (as *AnomalyService) RunDailyAnalysis() {
+	for {
+		if err := as.AnalyzeAllUsers(); err != nil {
+			log.Println("Error running the daily anomaly analysis:", err)
+		}
+		time.Sleep(untilNextDailyRun(time.Now()))
+	}
+}
+
+// findAnomalies flags the expenses of the last anomalyAnalysisDays, ordered by date, that stand
+// out from the same payee or category over the anomalyBaselineMonths before them, and the months
+// where a category total stands out from the months before. A transaction is flagged once, for
+// the first of a repeated charge, an unusual payee amount or an unusual category amount.
+func findAnomalies(userId uuid.UUID, transactions []domain.TransactionModel, now time.Time) []domain.AnomalyModel {
+	var expenses []domain.TransactionModel
+	for _, tm := range transactions {
+		if tm.Type == domain.EXPENSE && tm.Status != domain.CANCELLED && tm.Amount > 0 {
+			expenses = append(expenses, tm)
+		}
+	}
+	analysisStart := startOfDay(now).AddDate(0, 0, -anomalyAnalysisDays)
+	createdAt := now.UnixMilli()
+
+	anomalies := []domain.AnomalyModel{}
+	for i, tm := range expenses {
+		if tm.Date < analysisStart.UnixMilli() {
+			continue
+		}
+		date := time.UnixMilli(tm.Date).UTC()
+		month := date.Format(monthLayout)
+		windowStart := date.AddDate(0, -anomalyBaselineMonths, 0).UnixMilli()
+
+		var payeeHistory, categoryHistory []float64
+		payeeMonths := map[string]int{}
+		for _, earlier := range expenses[:i] {
+			if earlier.Date < windowStart {
+				continue
+			}
+			if tm.PayeeId != uuid.Nil && earlier.PayeeId == tm.PayeeId {
+				payeeHistory = append(payeeHistory, earlier.Amount)
+				payeeMonths[time.UnixMilli(earlier.Date).UTC().Format(monthLayout)]++
+			}
+			if earlier.CategoryId == tm.CategoryId {
+				categoryHistory = append(categoryHistory, earlier.Amount)
+			}
+		}
+
+		anomaly := domain.AnomalyModel{
+			AnomalyId:     uuid.New(),
+			UserId:        userId,
+			TransactionId: tm.TransactionId,
+			CategoryId:    tm.CategoryId,
+			PayeeId:       tm.PayeeId,
+			Month:         month,
+			Amount:        tm.Amount,
+			CreatedAt:     createdAt,
+		}
+		if charges := payeeMonths[month] + 1; charges > 1 && chargesMonthly(payeeMonths, month) {
+			anomaly.Kind = domain.REPEATED_CHARGE
+			anomaly.Expected = roundCents(median(payeeHistory))
+			anomaly.Score = float64(charges)
+			anomaly.Message = fmt.Sprintf("%q charged %d times in %s, usually once a month.", tm.Description, charges, month)
+		} else if score, expected, ok := anomalyScore(tm.Amount, payeeHistory); ok {
+			anomaly.Kind = domain.UNUSUAL_PAYEE_AMOUNT
+			anomaly.Expected = expected
+			anomaly.Score = score
+			anomaly.Message = fmt.Sprintf("%q charged %.2f, usually %.2f.", tm.Description, tm.Amount, expected)
+		} else if score, expected, ok := anomalyScore(tm.Amount, categoryHistory); ok {
+			anomaly.Kind = domain.UNUSUAL_CATEGORY_AMOUNT
+			anomaly.Expected = expected
+			anomaly.Score = score
+			anomaly.Message = fmt.Sprintf("%q is %.2f, expenses in category %d are usually around %.2f.", tm.Description, tm.Amount, tm.CategoryId, expected)
+		} else {
+			continue
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	flagged := make(map[uuid.UUID]bool, len(anomalies))
+	for _, am := range anomalies {
+		flagged[am.TransactionId] = true
+	}
+	return append(anomalies, findMonthAnomalies(userId, expenses, flagged, analysisStart, now)...)
+}
+
+// findMonthAnomalies compares the total of every category in the months from analysisStart up
+// to now against its totals in the months before, counting months without expenses as 0 once
+// the category has been used. Transactions flagged on their own are left out of the totals, so
+// a single large purchase is not reported again as an unusual month.
+func findMonthAnomalies(userId uuid.UUID, expenses []domain.TransactionModel, flagged map[uuid.UUID]bool, analysisStart time.Time, now time.Time) []domain.AnomalyModel {
+	totals := map[int64]map[string]float64{}
+	firstMonth := map[int64]time.Time{}
+	for _, tm := range expenses {
+		if flagged[tm.TransactionId] {
+			continue
+		}
+		date := time.UnixMilli(tm.Date).UTC()
+		if totals[tm.CategoryId] == nil {
+			totals[tm.CategoryId] = map[string]float64{}
+			firstMonth[tm.CategoryId] = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+		totals[tm.CategoryId][date.Format(monthLayout)] += tm.Amount
+	}
+
+	categoryIds := make([]int64, 0, len(totals))
+	for id := range totals {
+		categoryIds = append(categoryIds, id)
+	}
+	sort.Slice(categoryIds, func(i, j int) bool { return categoryIds[i] < categoryIds[j] })
+
+	var anomalies []domain.AnomalyModel
+	for _, categoryId := range categoryIds {
+		for m := time.Date(analysisStart.Year(), analysisStart.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(now); m = m.AddDate(0, 1, 0) {
+			month := m.Format(monthLayout)
+			total := totals[categoryId][month]
+			if total == 0 {
+				continue
+			}
+
+			var history []float64
+			for k := 1; k <= anomalyBaselineMonths; k++ {
+				earlier := m.AddDate(0, -k, 0)
+				if earlier.Before(firstMonth[categoryId]) {
+					break
+				}
+				history = append(history, totals[categoryId][earlier.Format(monthLayout)])
+			}
+			// categories not spent in most months have no usual monthly total to compare against.
+			score, expected, ok := anomalyScore(total, history)
+			if !ok || expected == 0 {
+				continue
+			}
+			anomalies = append(anomalies, domain.AnomalyModel{
+				AnomalyId:  uuid.New(),
+				UserId:     userId,
+				Kind:       domain.UNUSUAL_CATEGORY_MONTH,
+				CategoryId: categoryId,
+				Month:      month,
+				Amount:     roundCents(total),
+				Expected:   expected,
+				Score:      score,
+				Message:    fmt.Sprintf("Category %d spending in %s is %.2f, usually around %.2f.", categoryId, month, total, expected),
+				CreatedAt:  now.UnixMilli(),
+			})
+		}
+	}
+	return anomalies
+}
+
+// chargesMonthly reports whether the payee was charged exactly once in each of at least
+// anomalyMinHistory months, not counting month.
+func chargesMonthly(payeeMonths map[string]int, month string) bool {
+	months := 0
+	for m, charges := range payeeMonths {
+		if m == month {
+			continue
+		}
+		if charges != 1 {
+			return false
+		}
+		months++
+	}
+	return months >= anomalyMinHistory
+}
+
+// anomalyScore returns the modified z-score of amount against the history, using the median
+// and the median absolute deviation so one earlier outlier does not skew the baseline. Only
+// amounts above the baseline are flagged, ok is false for everything else.
+func anomalyScore(amount float64, history []float64) (float64, float64, bool) {
+	if len(history) < anomalyMinHistory {
+		return 0, 0, false
+	}
+
+	center := median(history)
+	deviations := make([]float64, len(history))
+	for i, h := range history {
+		deviations[i] = math.Abs(h - center)
+	}
+	spread := max(1.4826*median(deviations), anomalyMinSpread*math.Abs(center), 0.01)
+
+	score := (amount - center) / spread
+	if score < anomalyThreshold {
+		return 0, 0, false
+	}
+	return math.Round(score*100) / 100, roundCents(center), true
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func convertAnomalyModelToDTO(from *domain.AnomalyModel) domain.AnomalyDTO {
+	return domain.AnomalyDTO{
+		AnomalyId:     from.AnomalyId,
+		UserId:        from.UserId,
+		Kind:          from.Kind,
+		TransactionId: from.TransactionId,
+		CategoryId:    from.CategoryId,
+		PayeeId:       from.PayeeId,
+		Month:         from.Month,
+		Amount:        from.Amount,
+		Expected:      from.Expected,
+		Score:         from.Score,
+		Message:       from.Message,
+		CreatedAt:     from.CreatedAt,
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
+)
+
+func setUpAnomalyModel(db *sql.DB) {
+	stmt := `create table anomaly_model (
+		id integer primary key autoincrement,
+		anomaly_id text not null,
+		user_id text not null,
+		kind integer not null,
+		transaction_id text not null,
+		category_id integer not null,
+		payee_id text not null,
+		month text not null,
+		amount float not null,
+		expected float not null,
+		score float not null,
+		message text not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating anomaly_model table:", err)
+	}
+}
+
+func TestAnalyze_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpAlertModel(db)
+	setUpAnomalyModel(db)
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+	webhooks := &recordingChannel{}
+	eventBus := EventBus{OBDBI: &udb}
+	alertService := AlertService{ALDBI: &udb, Channels: map[domain.AlertChannel]notify.ChannelInterface{domain.ALERT_WEBHOOK: webhooks}, Events: &eventBus}
+	eventBus.Subscribe("alert-webhook", domain.EVENT_ALERT_RAISED, alertService.DeliverAlerts(domain.ALERT_WEBHOOK))
+	anomalyService := AnomalyService{ANDBI: &udb, TDBI: &udb, Alerts: &alertService}
+
+	userId := uuid.New()
+	rule := domain.AlertRuleDTO{UserId: userId, Type: domain.ANOMALY, Channels: []domain.AlertChannel{domain.ALERT_WEBHOOK}, WebhookURL: "https://hooks.finance.test/anomalies"}
+	if _, err := alertService.AddAlertRule(context.Background(), &domain.AlertRuleData{Rule: rule, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the alert rule:", err)
+	}
+
+	payeeId := uuid.New()
+	today := startOfDay(time.Now())
+	var doubled domain.TransactionModel
+	for months := 6; months >= 0; months-- {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.PayeeId = payeeId
+		tm.CategoryId = 5
+		tm.Amount = 12.50
+		tm.Date = time.Date(today.Year(), today.Month()-time.Month(months), 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+		tm.Type = domain.EXPENSE
+		tm.Status = domain.CLEARED
+		if months == 0 {
+			tm.Amount = 25
+			tm.Date = today.UnixMilli()
+			doubled = tm
+		}
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+
+	found, err := anomalyService.Analyze(context.Background(), userId)
+	if err != nil {
+		t.Fatal("Error analyzing transactions:", err)
+	}
+	if len(found) != 1 || found[0].Kind != domain.UNUSUAL_PAYEE_AMOUNT || found[0].TransactionId != doubled.TransactionId || found[0].Expected != 12.50 {
+		t.Fatalf("Expected the doubled charge, got %+v", found)
+	}
+	if err := eventBus.DispatchPending(time.Now().Add(time.Second)); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	if len(webhooks.delivered) != 1 || webhooks.delivered[0].Recipient != rule.WebhookURL || webhooks.delivered[0].Message != found[0].Message {
+		t.Errorf("Expected the anomaly delivered to the webhook, got %+v", webhooks.delivered)
+	}
+
+	again, err := anomalyService.Analyze(context.Background(), userId)
+	if err != nil {
+		t.Fatal("Error analyzing transactions:", err)
+	}
+	if len(again) != 0 || len(webhooks.delivered) != 1 {
+		t.Errorf("Expected the anomaly to be reported once, got %+v", again)
+	}
+
+	anomalies, err := anomalyService.RetrieveAnomalies(userId)
+	if err != nil {
+		t.Fatal("Error retrieving anomalies:", err)
+	}
+	if len(anomalies) != 1 || anomalies[0].AnomalyId != found[0].AnomalyId {
+		t.Errorf("Expected the saved anomaly, got %+v", anomalies)
+	}
+	alerts, err := alertService.RetrieveAlerts(userId, true)
+	if err != nil || len(alerts) != 1 || alerts[0].Period != found[0].AnomalyId.String() {
+		t.Errorf("Expected the anomaly in the inbox, got %+v, %v", alerts, err)
+	}
+}
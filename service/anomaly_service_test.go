@@ -0,0 +1,109 @@
+package service
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func anomalyTransaction(date time.Time, categoryId int64, payeeId uuid.UUID, amount float64, description string) domain.TransactionModel {
+	return domain.TransactionModel{
+		TransactionId: uuid.New(),
+		CategoryId:    categoryId,
+		PayeeId:       payeeId,
+		Amount:        amount,
+		Date:          date.UnixMilli(),
+		Description:   description,
+		Type:          domain.EXPENSE,
+		Status:        domain.CLEARED,
+	}
+}
+
+func TestFindAnomalies(t *testing.T) {
+	userId := uuid.New()
+	now := time.Date(2026, time.June, 15, 12, 0, 0, 0, time.UTC)
+	streaming, power := uuid.New(), uuid.New()
+
+	var transactions []domain.TransactionModel
+	// a year of a fixed subscription, a utility bill that varies a little and weekly groceries.
+	for m := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC); m.Before(now); m = m.AddDate(0, 1, 0) {
+		// this months charge is the doubled one below.
+		if m.Month() != now.Month() {
+			transactions = append(transactions, anomalyTransaction(m.AddDate(0, 0, 4), 5, streaming, 15.99, "Streaming"))
+		}
+		transactions = append(transactions, anomalyTransaction(m.AddDate(0, 0, 11), 2, power, 85+float64(m.Month()%3)*5, "Power company"))
+	}
+	for week, d := 0, time.Date(2025, time.June, 16, 0, 0, 0, 0, time.UTC); d.Before(now); week, d = week+1, d.AddDate(0, 0, 7) {
+		transactions = append(transactions, anomalyTransaction(d, 3, uuid.Nil, 55+float64(week%3)*5, "Groceries"))
+	}
+	// a few small purchases over the year in a category that is not used every month.
+	for _, d := range []time.Time{time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 4, 0, 0, 0, 0, time.UTC)} {
+		transactions = append(transactions, anomalyTransaction(d, 4, uuid.Nil, 20+float64(d.Month()), "Cables"))
+	}
+
+	doubled := anomalyTransaction(time.Date(2026, 6, 5, 0, 0, 0, 0, time.UTC), 5, streaming, 31.98, "Streaming")
+	repeated := anomalyTransaction(time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC), 2, power, 88, "Power company")
+	oneOff := anomalyTransaction(time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC), 4, uuid.Nil, 900, "Television")
+	transactions = append(transactions, doubled, repeated, oneOff)
+	// twice the usual grocery trips in May, none of them unusual on their own.
+	for _, day := range []int{2, 9, 16, 23, 27, 30} {
+		transactions = append(transactions, anomalyTransaction(time.Date(2026, 5, day, 0, 0, 0, 0, time.UTC), 3, uuid.Nil, 60, "Groceries"))
+	}
+	cancelled := anomalyTransaction(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), 3, uuid.Nil, 5000, "Groceries")
+	cancelled.Status = domain.CANCELLED
+	transactions = append(transactions, cancelled)
+
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].Date < transactions[j].Date })
+	anomalies := findAnomalies(userId, transactions, now)
+
+	want := []struct {
+		kind          domain.AnomalyKind
+		transactionId uuid.UUID
+		categoryId    int64
+		month         string
+	}{
+		{domain.REPEATED_CHARGE, repeated.TransactionId, 2, "2026-04"},
+		{domain.UNUSUAL_CATEGORY_AMOUNT, oneOff.TransactionId, 4, "2026-05"},
+		{domain.UNUSUAL_PAYEE_AMOUNT, doubled.TransactionId, 5, "2026-06"},
+		{domain.UNUSUAL_CATEGORY_MONTH, uuid.Nil, 3, "2026-05"},
+	}
+	if len(anomalies) != len(want) {
+		t.Fatalf("Expected %d anomalies, got %+v", len(want), anomalies)
+	}
+	for i, w := range want {
+		got := anomalies[i]
+		if got.UserId != userId || got.Kind != w.kind || got.TransactionId != w.transactionId || got.CategoryId != w.categoryId || got.Month != w.month {
+			t.Errorf("Wrong anomaly %d, got %+v, want %+v", i, got, w)
+		}
+	}
+	if doubledAnomaly := anomalies[2]; doubledAnomaly.Expected != 15.99 || doubledAnomaly.Score < anomalyThreshold {
+		t.Errorf("Expected the usual charge as the baseline, got %+v", doubledAnomaly)
+	}
+}
+
+func TestAnomalyScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		history []float64
+		flagged bool
+	}{
+		{name: "Too little history", amount: 1000, history: []float64{10, 10}, flagged: false},
+		{name: "Usual amount", amount: 12, history: []float64{10, 11, 9, 12, 10}, flagged: false},
+		{name: "Below the usual amount", amount: 1, history: []float64{10, 11, 9, 12, 10}, flagged: false},
+		{name: "Identical history", amount: 15, history: []float64{10, 10, 10}, flagged: true},
+		{name: "Earlier outlier", amount: 30, history: []float64{10, 11, 500, 9, 10}, flagged: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, flagged := anomalyScore(test.amount, test.history)
+			if flagged != test.flagged {
+				t.Errorf("Expected flagged to be %v", test.flagged)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/storage"
+)
+
+// MaxAttachmentSize is the largest file accepted, in bytes.
+const MaxAttachmentSize = 10 << 20
+
+var (
+	ErrAttachmentTooLarge     = errors.New("attachment is too large")
+	ErrAttachmentEmpty        = errors.New("attachment is empty")
+	ErrUnsupportedContentType = errors.New("attachment content type is not supported")
+)
+
+// allowedContentTypes are the sniffed types accepted, receipts and invoices are images, PDFs or text.
+var allowedContentTypes = map[string]bool{
+	"application/pdf":           true,
+	"image/jpeg":                true,
+	"image/png":                 true,
+	"image/gif":                 true,
+	"image/webp":                true,
+	"text/plain; charset=utf-8": true,
+}
+
+type AttachmentServiceInterface interface {
+	AddAttachment(ctx context.Context, transactionId uuid.UUID, fileName string, data []byte) (*domain.AttachmentDTO, error)
+	RetrieveAttachments(transactionId uuid.UUID) ([]domain.AttachmentDTO, error)
+	RetrieveAttachment(attachmentId uuid.UUID) (*domain.AttachmentDTO, []byte, error)
+	DeleteAttachment(ctx context.Context, attachmentId uuid.UUID) error
+	RemoveUnusedContents(checksums ...string)
+}
+
+type AttachmentService struct {
+	ADBI    database.AttachmentDatabaseInterface
+	TDBI    database.TransactionDatabaseInterface
+	Storage storage.BlobStorageInterface
+	mu      sync.Mutex // keeps a blob from being removed while another attachment starts using it.
+}
+
+// AddAttachment stores the file with the transaction. The content type is sniffed from the data,
+// whatever the client claims. Contents already stored are not written again, and uploading the same
+// file to the same transaction twice returns the existing attachment.
+func (as *AttachmentService) AddAttachment(ctx context.Context, transactionId uuid.UUID, fileName string, data []byte) (*domain.AttachmentDTO, error) {
+	if len(data) == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	if len(data) > MaxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !allowedContentTypes[contentType] {
+		log.Printf("Rejected attachment %q with content type %s\n", fileName, contentType)
+		return nil, ErrUnsupportedContentType
+	}
+
+	transaction, err := as.TDBI.GetTransaction(transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	existing, err := as.ADBI.GetAttachmentsByTransactionId(transactionId)
+	if err != nil {
+		return nil, err
+	}
+	for _, am := range existing {
+		if am.Checksum == checksum {
+			attachment := convertAttachmentModelToDTO(&am)
+			return &attachment, nil
+		}
+	}
+
+	count, err := as.ADBI.CountAttachmentsByChecksum(checksum)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		err = as.Storage.Put(checksum, data, contentType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	am := domain.AttachmentModel{
+		AttachmentId:  uuid.New(),
+		TransactionId: transactionId,
+		UserId:        transaction.UserId,
+		FileName:      cleanFileName(fileName),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+		Checksum:      checksum,
+		CreatedAt:     time.Now().UnixMilli(),
+	}
+	err = as.ADBI.AddAttachment(ctx, &am)
+	if err != nil {
+		if count == 0 {
+			as.removeBlob(checksum)
+		}
+		return nil, err
+	}
+
+	attachment := convertAttachmentModelToDTO(&am)
+	return &attachment, nil
+}
+
+func (as *AttachmentService) RetrieveAttachments(transactionId uuid.UUID) ([]domain.AttachmentDTO, error) {
+	attachments, err := as.ADBI.GetAttachmentsByTransactionId(transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentDTOs := make([]domain.AttachmentDTO, 0, len(attachments))
+	for _, am := range attachments {
+		attachmentDTOs = append(attachmentDTOs, convertAttachmentModelToDTO(&am))
+	}
+	return attachmentDTOs, nil
+}
+
+// RetrieveAttachment returns the attachment along with its contents.
+func (as *AttachmentService) RetrieveAttachment(attachmentId uuid.UUID) (*domain.AttachmentDTO, []byte, error) {
+	am, err := as.ADBI.GetAttachment(attachmentId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := as.Storage.Get(am.Checksum)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment := convertAttachmentModelToDTO(&am)
+	return &attachment, data, nil
+}
+
+// DeleteAttachment removes the attachment, and its contents once no other attachment shares them.
+func (as *AttachmentService) DeleteAttachment(ctx context.Context, attachmentId uuid.UUID) error {
+	am, err := as.ADBI.GetAttachment(attachmentId)
+	if err != nil {
+		return err
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	return as.deleteAttachment(ctx, &am)
+}
+
+// RemoveUnusedContents removes the stored contents of the checksums that no attachment refers to
+// anymore, used once the attachments of a deleted transaction are gone with it. Like removeBlob it
+// only logs failures, they leave unused blobs behind.
+func (as *AttachmentService) RemoveUnusedContents(checksums ...string) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	for _, checksum := range checksums {
+		count, err := as.ADBI.CountAttachmentsByChecksum(checksum)
+		if err != nil {
+			log.Printf("Error counting the attachments of contents %s: %v\n", checksum, err)
+			continue
+		}
+		if count == 0 {
+			as.removeBlob(checksum)
+		}
+	}
+}
+
+func (as *AttachmentService) deleteAttachment(ctx context.Context, am *domain.AttachmentModel) error {
+	err := as.ADBI.DeleteAttachment(ctx, am.AttachmentId)
+	if err != nil {
+		return err
+	}
+
+	count, err := as.ADBI.CountAttachmentsByChecksum(am.Checksum)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		as.removeBlob(am.Checksum)
+	}
+	return nil
+}
+
+// removeBlob deletes stored contents nothing refers to anymore. A failure only leaves an
+// unused blob behind, so it is logged rather than returned.
+func (as *AttachmentService) removeBlob(checksum string) {
+	err := as.Storage.Delete(checksum)
+	if err != nil {
+		log.Printf("Error removing unused attachment contents %s: %v\n", checksum, err)
+	}
+}
+
+// cleanFileName keeps only the base name of the uploaded file, without control characters.
+func cleanFileName(fileName string) string {
+	fileName = path.Base(strings.ReplaceAll(fileName, `\`, "/"))
+	fileName = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, fileName)
+	fileName = strings.TrimSpace(fileName)
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "attachment"
+	}
+	if len(fileName) > 255 {
+		fileName = strings.ToValidUTF8(fileName[:255], "")
+	}
+	return fileName
+}
+
+func convertAttachmentModelToDTO(from *domain.AttachmentModel) domain.AttachmentDTO {
+	return domain.AttachmentDTO{
+		AttachmentId:  from.AttachmentId,
+		TransactionId: from.TransactionId,
+		UserId:        from.UserId,
+		FileName:      from.FileName,
+		ContentType:   from.ContentType,
+		Size:          from.Size,
+		Checksum:      from.Checksum,
+		CreatedAt:     from.CreatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/storage"
+)
+
+func setUpAttachmentModel(db *sql.DB) {
+	stmt := `create table attachment_model (
+		id integer primary key autoincrement,
+		attachment_id text not null,
+		transaction_id text not null,
+		user_id text not null,
+		file_name text not null,
+		content_type text not null,
+		size integer not null,
+		checksum text not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating attachment_model table:", err)
+	}
+}
+
+var pdfReceipt = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n")
+
+func TestAttachments_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	udb := database.SQLManager{DB: db}
+	blobs := storage.LocalStorage{Dir: t.TempDir()}
+	attachmentService := AttachmentService{ADBI: &udb, TDBI: &udb, Storage: &blobs}
+	transactionService := TransactionService{UDBI: &udb, Attachments: &attachmentService}
+
+	first := domain.TransactionModelBuilder().Build()
+	second := domain.TransactionModelBuilder().Build()
+	for _, tm := range []domain.TransactionModel{first, second} {
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+
+	receipt, err := attachmentService.AddAttachment(context.Background(), first.TransactionId, `C:\scans\receipt.pdf`, pdfReceipt)
+	if err != nil {
+		t.Fatal("Error adding attachment:", err)
+	}
+	if receipt.ContentType != "application/pdf" || receipt.FileName != "receipt.pdf" || receipt.UserId != first.UserId || receipt.Size != int64(len(pdfReceipt)) {
+		t.Errorf("Wrong attachment, got %v", receipt)
+	}
+	blobPath := filepath.Join(blobs.Dir, receipt.Checksum[:2], receipt.Checksum)
+
+	// the same file on the same transaction is the same attachment.
+	again, err := attachmentService.AddAttachment(context.Background(), first.TransactionId, "receipt-copy.pdf", pdfReceipt)
+	if err != nil || again.AttachmentId != receipt.AttachmentId {
+		t.Errorf("Expected the existing attachment, got %v, err: %v", again, err)
+	}
+
+	// on another transaction the contents are shared.
+	shared, err := attachmentService.AddAttachment(context.Background(), second.TransactionId, "receipt.pdf", pdfReceipt)
+	if err != nil || shared.AttachmentId == receipt.AttachmentId || shared.Checksum != receipt.Checksum {
+		t.Fatalf("Expected a new attachment sharing the contents, got %v, err: %v", shared, err)
+	}
+
+	attachment, data, err := attachmentService.RetrieveAttachment(shared.AttachmentId)
+	if err != nil || !bytes.Equal(data, pdfReceipt) || attachment.FileName != "receipt.pdf" {
+		t.Fatalf("Wrong download, got %v %q, err: %v", attachment, data, err)
+	}
+
+	// deleting the first transaction keeps the contents the second one still uses.
+	err = transactionService.DeleteTransaction(context.Background(), first.TransactionId)
+	if err != nil {
+		t.Fatal("Error deleting transaction:", err)
+	}
+	if attachments, _ := attachmentService.RetrieveAttachments(first.TransactionId); len(attachments) != 0 {
+		t.Errorf("Expected the attachments of the deleted transaction to be gone, got %v", attachments)
+	}
+	if _, err := os.Stat(blobPath); err != nil {
+		t.Fatal("Shared contents were removed:", err)
+	}
+
+	err = attachmentService.DeleteAttachment(context.Background(), shared.AttachmentId)
+	if err != nil {
+		t.Fatal("Error deleting attachment:", err)
+	}
+	if _, err := os.Stat(blobPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the unused contents to be removed, got %v", err)
+	}
+
+	// the contents of the last transaction using them go after it is deleted.
+	if _, err := attachmentService.AddAttachment(context.Background(), second.TransactionId, "receipt.pdf", pdfReceipt); err != nil {
+		t.Fatal("Error adding attachment:", err)
+	}
+	err = transactionService.DeleteTransaction(context.Background(), second.TransactionId)
+	if err != nil {
+		t.Fatal("Error deleting transaction:", err)
+	}
+	if attachments, _ := attachmentService.RetrieveAttachments(second.TransactionId); len(attachments) != 0 {
+		t.Errorf("Expected the attachments of the deleted transaction to be gone, got %v", attachments)
+	}
+	if _, err := os.Stat(blobPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the contents of the deleted transaction to be removed, got %v", err)
+	}
+}
+
+func TestAddAttachment_Rejected_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	udb := database.SQLManager{DB: db}
+	blobs := storage.LocalStorage{Dir: t.TempDir()}
+	attachmentService := AttachmentService{ADBI: &udb, TDBI: &udb, Storage: &blobs}
+
+	tm := domain.TransactionModelBuilder().Build()
+	if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "Empty", data: []byte{}, wantErr: ErrAttachmentEmpty},
+		{name: "Too large", data: append(bytes.Clone(pdfReceipt), make([]byte, MaxAttachmentSize)...), wantErr: ErrAttachmentTooLarge},
+		{name: "Executable", data: []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff"), wantErr: ErrUnsupportedContentType},
+		{name: "HTML", data: []byte("<html><script>alert(1)</script></html>"), wantErr: ErrUnsupportedContentType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := attachmentService.AddAttachment(context.Background(), tm.TransactionId, "receipt.pdf", test.data)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Wrong error, got %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+
+	_, err := attachmentService.AddAttachment(context.Background(), domain.TransactionModelBuilder().Build().TransactionId, "receipt.pdf", pdfReceipt)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for an unknown transaction, got %v", err)
+	}
+	if entries, _ := os.ReadDir(blobs.Dir); len(entries) != 0 {
+		t.Errorf("Expected nothing stored, got %v", entries)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+const auditVerifyBatchSize = 500
+
+var ErrNotAuditAdmin = errors.New("the user may not verify the audit log")
+
+type AuditServiceInterface interface {
+	RetrieveHistory(userId uuid.UUID, entity string, entityId string) ([]domain.AuditEntryDTO, error)
+	VerifyAuditLog(userId uuid.UUID) (*domain.AuditVerificationDTO, error)
+}
+
+type AuditService struct {
+	AUDBI  database.AuditDatabaseInterface
+	Admins []uuid.UUID // the users allowed to verify the audit log, it covers the data of all users
+}
+
+// RetrieveHistory returns every recorded change of the entity of the user, oldest first.
+func (as *AuditService) RetrieveHistory(userId uuid.UUID, entity string, entityId string) ([]domain.AuditEntryDTO, error) {
+	entries, err := as.AUDBI.GetEntityHistory(userId, entity, entityId)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]domain.AuditEntryDTO, 0, len(entries))
+	for i := range entries {
+		history = append(history, *convertAuditEntryModelToDTO(&entries[i]))
+	}
+	return history, nil
+}
+
+// VerifyAuditLog walks the whole chain, checking that the entries are numbered without gaps,
+// that each links to the hash of the one before it and that its hash matches its contents. Only
+// the admins may verify it.
+func (as *AuditService) VerifyAuditLog(userId uuid.UUID) (*domain.AuditVerificationDTO, error) {
+	if !slices.Contains(as.Admins, userId) {
+		return nil, ErrNotAuditAdmin
+	}
+
+	verification := domain.AuditVerificationDTO{Valid: true}
+	for {
+		entries, err := as.AUDBI.GetAuditEntries(verification.LastSeq, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			reason := ""
+			switch {
+			case entry.Seq != verification.LastSeq+1:
+				reason = fmt.Sprintf("expected entry %d, found entry %d", verification.LastSeq+1, entry.Seq)
+			case entry.PrevHash != verification.LastHash:
+				reason = "the previous hash does not match the entry before it"
+			case entry.Hash != database.AuditHash(entry):
+				reason = "the hash does not match the contents of the entry"
+			}
+			if reason != "" {
+				verification.Valid = false
+				verification.BrokenAt = entry.Seq
+				verification.Reason = reason
+				return &verification, nil
+			}
+
+			verification.Entries++
+			verification.LastSeq, verification.LastHash = entry.Seq, entry.Hash
+		}
+
+		if len(entries) < auditVerifyBatchSize {
+			return &verification, nil
+		}
+	}
+}
+
+func convertAuditEntryModelToDTO(model *domain.AuditEntryModel) *domain.AuditEntryDTO {
+	return &domain.AuditEntryDTO{
+		Seq:       model.Seq,
+		AuditId:   model.AuditId,
+		UserId:    model.UserId,
+		Actor:     model.Actor,
+		Action:    model.Action,
+		Entity:    model.Entity,
+		EntityId:  model.EntityId,
+		Diff:      json.RawMessage(model.Diff),
+		RequestId: model.RequestId,
+		CreatedAt: model.CreatedAt,
+		PrevHash:  model.PrevHash,
+		Hash:      model.Hash,
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpAuditModel(db *sql.DB) {
+	stmt := `create table audit_log (
+		seq integer primary key,
+		audit_id text not null unique,
+		user_id text not null,
+		actor text not null,
+		action text not null,
+		entity text not null,
+		entity_id text not null,
+		diff text not null,
+		request_id text not null,
+		created_at integer not null,
+		prev_hash text not null,
+		hash text not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating audit_log table:", err)
+	}
+
+	_, err = db.Exec(`create table audit_chain (id integer primary key, seq integer not null, hash text not null)`)
+	if err == nil {
+		_, err = db.Exec(`insert into audit_chain (id, seq, hash) values (1, 0, '')`)
+	}
+	if err != nil {
+		log.Fatal("There was an error creating audit_chain table:", err)
+	}
+}
+
+type auditDiff struct {
+	Before map[string]any `json:"before"`
+	After  map[string]any `json:"after"`
+}
+
+func readAuditDiff(t *testing.T, entry domain.AuditEntryDTO) auditDiff {
+	t.Helper()
+	var diff auditDiff
+	if err := json.Unmarshal(entry.Diff, &diff); err != nil {
+		t.Fatal("Error reading the audit diff:", err)
+	}
+	return diff
+}
+
+func TestAuditLog_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpAuditModel(db)
+	setUpBudgetModel(db)
+	setUpTagModel(db)
+	udb := database.SQLManager{DB: db, Audit: true}
+	admin := uuid.New()
+	auditService := AuditService{AUDBI: &udb, Admins: []uuid.UUID{admin}}
+	transactionService := TransactionService{UDBI: &udb}
+
+	// the transaction is added while serving a request, the rest of the writes by the system.
+	actor, requestId := uuid.NewString(), uuid.NewString()
+	ctx := domain.WithAuditScope(context.Background(), domain.AuditScope{Actor: actor, RequestId: requestId})
+	transaction := domain.TransactionDTOBuilder().Build()
+	if _, err := transactionService.AddTransaction(ctx, &domain.TransactionData{Transaction: transaction, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the transaction:", err)
+	}
+
+	tm, err := udb.GetTransaction(transaction.TransactionId)
+	if err != nil {
+		t.Fatal("Error retrieving the transaction:", err)
+	}
+	tm.Description = "Groceries"
+	if err := udb.UpdateTransaction(context.Background(), &tm); err != nil {
+		t.Fatal("Error updating the transaction:", err)
+	}
+	// an update that changes nothing is not recorded.
+	if err := udb.UpdateTransaction(context.Background(), &tm); err != nil {
+		t.Fatal("Error updating the transaction:", err)
+	}
+	if err := transactionService.DeleteTransaction(context.Background(), transaction.TransactionId); err != nil {
+		t.Fatal("Error deleting the transaction:", err)
+	}
+
+	history, err := auditService.RetrieveHistory(transaction.UserId, "transaction", transaction.TransactionId.String())
+	if err != nil {
+		t.Fatal("Error retrieving the history:", err)
+	}
+	if len(history) != 3 || history[0].Action != domain.AUDIT_CREATE || history[1].Action != domain.AUDIT_UPDATE || history[2].Action != domain.AUDIT_DELETE {
+		t.Fatalf("Unexpected history %+v", history)
+	}
+	if history[0].Actor != actor || history[0].RequestId != requestId || history[1].Actor != domain.AuditSystemActor || history[1].RequestId != "" {
+		t.Errorf("Unexpected authors %+v", history)
+	}
+
+	// the history of the entities of other users is not theirs to see.
+	if others, err := auditService.RetrieveHistory(uuid.New(), "transaction", transaction.TransactionId.String()); err != nil || len(others) != 0 {
+		t.Errorf("Expected no history for another user, got %+v, err: %v", others, err)
+	}
+
+	created, updated, deleted := readAuditDiff(t, history[0]), readAuditDiff(t, history[1]), readAuditDiff(t, history[2])
+	if created.Before != nil || created.After["transaction_id"] != transaction.TransactionId.String() || created.After["amount"] != transaction.Amount {
+		t.Errorf("Unexpected create diff %s", history[0].Diff)
+	}
+	if len(updated.After) != 1 || updated.After["description"] != "Groceries" || updated.Before["description"] != transaction.Description {
+		t.Errorf("Unexpected update diff %s", history[1].Diff)
+	}
+	if deleted.After != nil || deleted.Before["description"] != "Groceries" {
+		t.Errorf("Unexpected delete diff %s", history[2].Diff)
+	}
+
+	// replacing a budget deletes and inserts it in one SQL transaction, an update of the budget.
+	userId := uuid.New()
+	budgetService := BudgetService{BDBI: &udb}
+	for _, amount := range []float64{200, 250} {
+		budget := domain.BudgetDTO{UserId: userId, CategoryId: 3, Month: "2026-03", Amount: amount, Rollover: domain.ROLLOVER_NONE}
+		if _, err := budgetService.SetBudget(context.Background(), &domain.BudgetData{Budget: budget, Validator: validator.New()}); err != nil {
+			t.Fatal("Error setting the budget:", err)
+		}
+	}
+	history, err = auditService.RetrieveHistory(userId, "budget", userId.String()+"/3/2026-03")
+	if err != nil {
+		t.Fatal("Error retrieving the history:", err)
+	}
+	if len(history) != 2 || history[0].Action != domain.AUDIT_CREATE || history[1].Action != domain.AUDIT_UPDATE {
+		t.Fatalf("Unexpected budget history %+v", history)
+	}
+	if budgetDiff := readAuditDiff(t, history[1]); budgetDiff.Before["amount"] != 200.0 || budgetDiff.After["amount"] != 250.0 {
+		t.Errorf("Unexpected budget diff %s", history[1].Diff)
+	}
+
+	verification, err := auditService.VerifyAuditLog(admin)
+	if err != nil {
+		t.Fatal("Error verifying the audit log:", err)
+	}
+	if !verification.Valid || verification.Entries != 5 || verification.LastSeq != 5 || verification.LastHash != history[1].Hash {
+		t.Errorf("Unexpected verification %+v", verification)
+	}
+	// the chain covers the data of all users, only the admins may walk it.
+	if _, err := auditService.VerifyAuditLog(transaction.UserId); !errors.Is(err, ErrNotAuditAdmin) {
+		t.Errorf("Expected a user to be refused the verification, got %v", err)
+	}
+}
+
+func TestAuditLog_UserRedacted(t *testing.T) {
+	db := setUpUserModel()
+	defer db.Close()
+	setUpAuditModel(db)
+	udb := database.SQLManager{DB: db, Audit: true}
+	auditService := AuditService{AUDBI: &udb}
+	userService := UserService{UDBI: &udb}
+
+	user := domain.UserDTOBuilder().Build()
+	if err := userService.RegisterNewUser(context.Background(), &domain.UserData{User: &user, Validator: validator.New()}); err != nil {
+		t.Fatal("Error registering the user:", err)
+	}
+	saved, err := udb.RetrieveUserByEmail(user.Email)
+	if err != nil {
+		t.Fatal("Error retrieving the user:", err)
+	}
+	update := domain.UserDTO{UserId: saved.UserId, FirstName: "Renamed", LastName: saved.LastName, Email: saved.Email, Phone: saved.Phone, DateOfBirth: saved.DateOfBirth}
+	if err := userService.UpdateUserProfileData(context.Background(), &update); err != nil {
+		t.Fatal("Error updating the user:", err)
+	}
+
+	history, err := auditService.RetrieveHistory(saved.UserId, "user", saved.UserId.String())
+	if err != nil {
+		t.Fatal("Error retrieving the history:", err)
+	}
+	if len(history) != 2 || history[0].Action != domain.AUDIT_CREATE || history[1].Action != domain.AUDIT_UPDATE {
+		t.Fatalf("Unexpected history %+v", history)
+	}
+	if created := readAuditDiff(t, history[0]); created.After["password_hash"] != "[redacted]" || strings.Contains(string(history[0].Diff), user.Password) {
+		t.Errorf("The password is in the audit log %s", history[0].Diff)
+	}
+	if updated := readAuditDiff(t, history[1]); updated.Before["first_name"] != user.FirstName || updated.After["first_name"] != "Renamed" {
+		t.Errorf("Unexpected update diff %s", history[1].Diff)
+	}
+}
+
+func TestAuditLog_Owner(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpAuditModel(db)
+	setUpTagModel(db)
+	udb := database.SQLManager{DB: db, Audit: true}
+	transactionService := TransactionService{UDBI: &udb, Tags: &TagService{TGDBI: &udb}}
+
+	transaction := domain.TransactionDTOBuilder().Build()
+	transaction.Tags = []string{"travel"}
+	if _, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the transaction:", err)
+	}
+
+	// the tags of a transaction have no user_id column, they belong to the user of the tag.
+	entries, err := udb.GetAuditEntries(0, 10)
+	if err != nil {
+		t.Fatal("Error retrieving the audit entries:", err)
+	}
+	entities := map[string]bool{}
+	for _, entry := range entries {
+		entities[entry.Entity] = true
+		if entry.UserId != transaction.UserId {
+			t.Errorf("The %s entry belongs to %v, want %v", entry.Entity, entry.UserId, transaction.UserId)
+		}
+	}
+	if !entities["transaction"] || !entities["tag"] || !entities["transaction_tag"] {
+		t.Errorf("Unexpected audit entries %+v", entries)
+	}
+}
+
+func TestAuditLog_Tampered(t *testing.T) {
+	rehash := func(db *sql.DB) string {
+		// a rewritten entry with a matching hash no longer links to the entry after it.
+		entries, err := (&database.SQLManager{DB: db}).GetAuditEntries(1, 1)
+		if err != nil || len(entries) != 1 {
+			log.Fatal("Error retrieving the audit entry:", err)
+		}
+		entries[0].Actor = "someone else"
+		return `update audit_log set actor = 'someone else', hash = '` + database.AuditHash(&entries[0]) + `' where seq = 2`
+	}
+
+	tests := []struct {
+		name   string
+		stmt   func(db *sql.DB) string
+		broken int64
+		reason string
+	}{
+		{"Edited", func(*sql.DB) string { return `update audit_log set actor = 'someone else' where seq = 2` }, 2, "hash does not match"},
+		{"Rehashed", rehash, 3, "previous hash"},
+		{"Removed", func(*sql.DB) string { return `delete from audit_log where seq = 2` }, 3, "expected entry 2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := setUpTransactionModel()
+			defer db.Close()
+			setUpAuditModel(db)
+			admin := uuid.New()
+			udb := database.SQLManager{DB: db, Audit: true}
+			auditService := AuditService{AUDBI: &udb, Admins: []uuid.UUID{admin}}
+
+			for i := 0; i < 3; i++ {
+				tm := domain.TransactionModelBuilder().Build()
+				if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+					t.Fatal("Error adding the transaction:", err)
+				}
+			}
+			verification, err := auditService.VerifyAuditLog(admin)
+			if err != nil {
+				t.Fatal("Error verifying the audit log:", err)
+			}
+			if !verification.Valid || verification.Entries != 3 {
+				t.Fatalf("Unexpected verification %+v", verification)
+			}
+
+			if _, err := db.Exec(test.stmt(db)); err != nil {
+				t.Fatal("Error tampering with the audit log:", err)
+			}
+			verification, err = auditService.VerifyAuditLog(admin)
+			if err != nil {
+				t.Fatal("Error verifying the audit log:", err)
+			}
+			if verification.Valid || verification.BrokenAt != test.broken || !strings.Contains(verification.Reason, test.reason) {
+				t.Errorf("Unexpected verification %+v", verification)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// monthLayout is how months are written in budgets and queries, e.g. "2026-03". Months are in UTC.
+const monthLayout = "2006-01"
+
+type BudgetServiceInterface interface {
+	SetBudget(ctx context.Context, budgetData *domain.BudgetData) (*domain.BudgetDTO, error)
+	RetrieveBudgets(userId uuid.UUID) ([]domain.BudgetDTO, error)
+	DeleteBudget(ctx context.Context, userId uuid.UUID, categoryId int64, month string) error
+	RetrieveBudgetReport(userId uuid.UUID, month string) (*domain.BudgetReportDTO, error)
+}
+
+type BudgetService struct {
+	BDBI database.BudgetDatabaseInterface
+}
+
+// SetBudget assigns the amount to the category from the month on, replacing the months previous assignment.
+func (bs *BudgetService) SetBudget(ctx context.Context, budgetData *domain.BudgetData) (*domain.BudgetDTO, error) {
+	err := budgetData.ValidateBudget()
+	if err != nil {
+		return nil, err
+	}
+
+	bm := convertBudgetDTOToModel(&budgetData.Budget)
+	bm.BudgetId = uuid.New()
+	bm.CreatedAt = time.Now().UnixMilli()
+	err = bs.BDBI.SaveBudget(ctx, &bm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertBudgetModelToDTO(&bm)
+	return &saved, nil
+}
+
+func (bs *BudgetService) RetrieveBudgets(userId uuid.UUID) ([]domain.BudgetDTO, error) {
+	budgets, err := bs.BDBI.GetBudgetsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	budgetDTOs := make([]domain.BudgetDTO, 0, len(budgets))
+	for _, bm := range budgets {
+		budgetDTOs = append(budgetDTOs, convertBudgetModelToDTO(&bm))
+	}
+	return budgetDTOs, nil
+}
+
+func (bs *BudgetService) DeleteBudget(ctx context.Context, userId uuid.UUID, categoryId int64, month string) error {
+	_, err := time.Parse(monthLayout, month)
+	if err != nil {
+		return err
+	}
+	return bs.BDBI.DeleteBudget(ctx, userId, categoryId, month)
+}
+
+// RetrieveBudgetReport compares every categories budget with its spending for the month. Starting
+// from the first month a category was budgeted, whatever its rollover mode allows is carried from
+// each month into the next.
+func (bs *BudgetService) RetrieveBudgetReport(userId uuid.UUID, month string) (*domain.BudgetReportDTO, error) {
+	reportMonth, err := time.Parse(monthLayout, month)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := bs.BDBI.GetBudgetsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	byCategory := map[int64][]domain.BudgetModel{}
+	for _, bm := range budgets {
+		if bm.Month <= month {
+			byCategory[bm.CategoryId] = append(byCategory[bm.CategoryId], bm)
+		}
+	}
+
+	spending := map[string]map[int64]float64{}
+	spendingFor := func(m time.Time) (map[int64]float64, error) {
+		key := m.Format(monthLayout)
+		if _, ok := spending[key]; !ok {
+			from, to := monthRange(m)
+			monthSpending, err := bs.BDBI.GetCategorySpending(userId, from, to)
+			if err != nil {
+				return nil, err
+			}
+			spending[key] = monthSpending
+		}
+		return spending[key], nil
+	}
+
+	report := domain.BudgetReportDTO{UserId: userId, Month: month, Categories: []domain.BudgetCategoryDTO{}}
+	for categoryId, categoryBudgets := range byCategory {
+		// budgets are ordered by month, the first one starts the envelope.
+		start, err := time.Parse(monthLayout, categoryBudgets[0].Month)
+		if err != nil {
+			return nil, err
+		}
+
+		var carry float64
+		var category domain.BudgetCategoryDTO
+		for m := start; !m.After(reportMonth); m = m.AddDate(0, 1, 0) {
+			monthSpending, err := spendingFor(m)
+			if err != nil {
+				return nil, err
+			}
+			effective := effectiveBudget(categoryBudgets, m.Format(monthLayout))
+			category = domain.BudgetCategoryDTO{
+				CategoryId: categoryId,
+				Budgeted:   effective.Amount,
+				RolledOver: carry,
+				Available:  roundCents(effective.Amount + carry),
+				Actual:     roundCents(monthSpending[categoryId]),
+				Rollover:   effective.Rollover,
+			}
+			category.Remaining = roundCents(category.Available - category.Actual)
+			carry = rolloverAmount(effective.Rollover, category.Remaining)
+		}
+		report.Categories = append(report.Categories, category)
+	}
+
+	// spending in categories without a budget is still reported.
+	monthSpending, err := spendingFor(reportMonth)
+	if err != nil {
+		return nil, err
+	}
+	for categoryId, actual := range monthSpending {
+		if _, ok := byCategory[categoryId]; !ok {
+			actual = roundCents(actual)
+			report.Categories = append(report.Categories, domain.BudgetCategoryDTO{CategoryId: categoryId, Actual: actual, Remaining: -actual})
+		}
+	}
+
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return report.Categories[i].CategoryId < report.Categories[j].CategoryId
+	})
+	for _, category := range report.Categories {
+		report.Budgeted += category.Budgeted
+		report.RolledOver += category.RolledOver
+		report.Actual += category.Actual
+		report.Remaining += category.Remaining
+	}
+	report.Budgeted = roundCents(report.Budgeted)
+	report.RolledOver = roundCents(report.RolledOver)
+	report.Actual = roundCents(report.Actual)
+	report.Remaining = roundCents(report.Remaining)
+	return &report, nil
+}
+
+// effectiveBudget is the latest budget assigned at or before the month, budgets are ordered by month.
+func effectiveBudget(budgets []domain.BudgetModel, month string) domain.BudgetModel {
+	effective := budgets[0]
+	for _, bm := range budgets {
+		if bm.Month > month {
+			break
+		}
+		effective = bm
+	}
+	return effective
+}
+
+// rolloverAmount is the part of the remaining amount carried into the next month.
+func rolloverAmount(mode domain.RolloverMode, remaining float64) float64 {
+	switch mode {
+	case domain.ROLLOVER_UNSPENT:
+		return math.Max(remaining, 0)
+	case domain.ROLLOVER_OVERSPENT:
+		return math.Min(remaining, 0)
+	case domain.ROLLOVER_ALL:
+		return remaining
+	default:
+		return 0
+	}
+}
+
+// monthRange returns the first and last millisecond of the month m starts in.
+func monthRange(m time.Time) (int64, int64) {
+	start := time.Date(m.Year(), m.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.UnixMilli(), start.AddDate(0, 1, 0).UnixMilli() - 1
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func convertBudgetDTOToModel(from *domain.BudgetDTO) domain.BudgetModel {
+	return domain.BudgetModel{
+		BudgetId:   from.BudgetId,
+		UserId:     from.UserId,
+		CategoryId: from.CategoryId,
+		Month:      from.Month,
+		Amount:     from.Amount,
+		Rollover:   from.Rollover,
+		CreatedAt:  from.CreatedAt,
+	}
+}
+
+func convertBudgetModelToDTO(from *domain.BudgetModel) domain.BudgetDTO {
+	return domain.BudgetDTO{
+		BudgetId:   from.BudgetId,
+		UserId:     from.UserId,
+		CategoryId: from.CategoryId,
+		Month:      from.Month,
+		Amount:     from.Amount,
+		Rollover:   from.Rollover,
+		CreatedAt:  from.CreatedAt,
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpBudgetModel(db *sql.DB) {
+	stmt := `create table budget_model (
+		id integer primary key autoincrement,
+		budget_id text not null,
+		user_id text not null,
+		category_id integer not null,
+		month text not null,
+		amount float not null,
+		rollover integer not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating budget_model table:", err)
+	}
+}
+
+func TestBudgetReport_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpBudgetModel(db)
+	udb := database.SQLManager{DB: db}
+	budgetService := BudgetService{BDBI: &udb}
+
+	userId := uuid.New()
+	for _, month := range []string{"2026-02", "2026-03"} {
+		budget := domain.BudgetDTO{UserId: userId, CategoryId: 3, Month: month, Amount: 200, Rollover: domain.ROLLOVER_UNSPENT}
+		_, err := budgetService.SetBudget(context.Background(), &domain.BudgetData{Budget: budget, Validator: validator.New()})
+		if err != nil {
+			t.Fatal("Error setting the budget:", err)
+		}
+	}
+	// setting March again replaces it.
+	budget := domain.BudgetDTO{UserId: userId, CategoryId: 3, Month: "2026-03", Amount: 250, Rollover: domain.ROLLOVER_UNSPENT}
+	_, err := budgetService.SetBudget(context.Background(), &domain.BudgetData{Budget: budget, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error setting the budget:", err)
+	}
+
+	february := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC).UnixMilli()
+	march := time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC).UnixMilli()
+	add := func(date int64, amount float64, transactionType domain.TransactionType, status domain.TransactionStatus) {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.CategoryId = 3
+		tm.Date = date
+		tm.Amount = amount
+		tm.Type = transactionType
+		tm.Status = status
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+	add(february, 150, domain.EXPENSE, domain.CLEARED)
+	add(march, 120, domain.EXPENSE, domain.PENDING)
+	add(march, 80, domain.EXPENSE, domain.CANCELLED)
+	add(march, 1000, domain.INCOME, domain.CLEARED)
+	add(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), 60, domain.EXPENSE, domain.CLEARED)
+
+	budgets, err := budgetService.RetrieveBudgets(userId)
+	if err != nil || len(budgets) != 2 {
+		t.Fatalf("Expected two budgets, got %v, err: %v", budgets, err)
+	}
+
+	report, err := budgetService.RetrieveBudgetReport(userId, "2026-03")
+	if err != nil {
+		t.Fatal("Error retrieving the budget report:", err)
+	}
+	want := domain.BudgetCategoryDTO{CategoryId: 3, Budgeted: 250, RolledOver: 50, Available: 300, Actual: 120, Remaining: 180, Rollover: domain.ROLLOVER_UNSPENT}
+	if len(report.Categories) != 1 || report.Categories[0] != want {
+		t.Errorf("Wrong report, got %+v, want %+v", report.Categories, want)
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type StubBudgetDatabase struct {
+	budgets  []domain.BudgetModel
+	spending map[string]map[int64]float64 // by month
+}
+
+func (m *StubBudgetDatabase) SaveBudget(ctx context.Context, bm *domain.BudgetModel) error {
+	m.budgets = append(m.budgets, *bm)
+	return nil
+}
+
+func (m *StubBudgetDatabase) GetBudgetsByUserId(userId uuid.UUID) ([]domain.BudgetModel, error) {
+	return m.budgets, nil
+}
+
+func (m *StubBudgetDatabase) DeleteBudget(ctx context.Context, userId uuid.UUID, categoryId int64, month string) error {
+	return nil
+}
+
+func (m *StubBudgetDatabase) GetCategorySpending(userId uuid.UUID, from int64, to int64) (map[int64]float64, error) {
+	return m.spending[time.UnixMilli(from).UTC().Format(monthLayout)], nil
+}
+
+func TestRetrieveBudgetReport_Rollover(t *testing.T) {
+	// groceries are budgeted 300 from January and 350 from March, spending is 250, 400 and 300.
+	spending := map[string]map[int64]float64{
+		"2026-01": {3: 250},
+		"2026-02": {3: 400},
+		"2026-03": {3: 300, 7: 45.5},
+	}
+
+	tests := []struct {
+		name           string
+		rollover       domain.RolloverMode
+		wantRolledOver float64
+		wantRemaining  float64
+	}{
+		{name: "None", rollover: domain.ROLLOVER_NONE, wantRolledOver: 0, wantRemaining: 50},
+		{name: "Unspent", rollover: domain.ROLLOVER_UNSPENT, wantRolledOver: 0, wantRemaining: 50},
+		{name: "Overspent", rollover: domain.ROLLOVER_OVERSPENT, wantRolledOver: -100, wantRemaining: -50},
+		{name: "All", rollover: domain.ROLLOVER_ALL, wantRolledOver: -50, wantRemaining: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userId := uuid.New()
+			budget := domain.BudgetModelBuilder().WithUserId(userId).WithCategoryId(3).WithRollover(test.rollover)
+			stubDB := &StubBudgetDatabase{
+				budgets: []domain.BudgetModel{
+					budget.WithMonth("2026-01").WithAmount(300).Build(),
+					budget.WithMonth("2026-03").WithAmount(350).Build(),
+				},
+				spending: spending,
+			}
+			budgetService := BudgetService{BDBI: stubDB}
+
+			report, err := budgetService.RetrieveBudgetReport(userId, "2026-03")
+			if err != nil {
+				t.Fatal("Error retrieving the budget report:", err)
+			}
+			if len(report.Categories) != 2 {
+				t.Fatalf("Expected the budgeted and the unbudgeted category, got %v", report.Categories)
+			}
+
+			groceries := report.Categories[0]
+			if groceries.Budgeted != 350 || groceries.Actual != 300 || groceries.RolledOver != test.wantRolledOver || groceries.Remaining != test.wantRemaining {
+				t.Errorf("Wrong groceries budget, got %+v", groceries)
+			}
+			unbudgeted := report.Categories[1]
+			if unbudgeted.CategoryId != 7 || unbudgeted.Budgeted != 0 || unbudgeted.Remaining != -45.5 {
+				t.Errorf("Wrong unbudgeted category, got %+v", unbudgeted)
+			}
+			if report.Actual != 345.5 || report.Remaining != test.wantRemaining-45.5 {
+				t.Errorf("Wrong totals, got actual %v, remaining %v", report.Actual, report.Remaining)
+			}
+		})
+	}
+}
+
+func TestRetrieveBudgetReport_BeforeFirstBudget(t *testing.T) {
+	userId := uuid.New()
+	stubDB := &StubBudgetDatabase{budgets: []domain.BudgetModel{domain.BudgetModelBuilder().WithUserId(userId).WithMonth("2026-05").Build()}}
+	budgetService := BudgetService{BDBI: stubDB}
+
+	report, err := budgetService.RetrieveBudgetReport(userId, "2026-04")
+	if err != nil {
+		t.Fatal("Error retrieving the budget report:", err)
+	}
+	if len(report.Categories) != 0 || report.Budgeted != 0 {
+		t.Errorf("Expected an empty report, got %+v", report)
+	}
+
+	_, err = budgetService.RetrieveBudgetReport(userId, "April")
+	if err == nil {
+		t.Error("Expected an error for an invalid month")
+	}
+}
+
+func TestSetBudget_Invalid(t *testing.T) {
+	budgetService := BudgetService{BDBI: &StubBudgetDatabase{}}
+
+	tests := []struct {
+		name   string
+		budget domain.BudgetDTO
+	}{
+		{name: "Bad month", budget: domain.BudgetDTO{UserId: uuid.New(), CategoryId: 3, Month: "2026-13", Amount: 100}},
+		{name: "Negative amount", budget: domain.BudgetDTO{UserId: uuid.New(), CategoryId: 3, Month: "2026-03", Amount: -1}},
+		{name: "Unknown rollover", budget: domain.BudgetDTO{UserId: uuid.New(), CategoryId: 3, Month: "2026-03", Rollover: 9}},
+		{name: "Uncategorized", budget: domain.BudgetDTO{UserId: uuid.New(), Month: "2026-03", Amount: 100}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := budgetService.SetBudget(context.Background(), &domain.BudgetData{Budget: test.budget, Validator: validator.New()})
+			if err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+var ErrCardExists = errors.New("the account already has a card")
+
+type CardServiceInterface interface {
+	AddCard(ctx context.Context, cardData *domain.CardAccountData) (*domain.CardAccountDTO, error)
+	RetrieveCards(userId uuid.UUID) ([]domain.CardAccountDTO, error)
+	DeleteCard(ctx context.Context, cardId uuid.UUID) error
+	RetrieveStatements(cardId uuid.UUID) (*domain.CardStatementsDTO, error)
+	RetrieveUpcomingPayments(userId uuid.UUID) ([]domain.UpcomingPaymentDTO, error)
+}
+
+type CardService struct {
+	CCDBI database.CardDatabaseInterface
+	TDBI  database.TransactionDatabaseInterface
+}
+
+func (cs *CardService) AddCard(ctx context.Context, cardData *domain.CardAccountData) (*domain.CardAccountDTO, error) {
+	err := cardData.ValidateCard()
+	if err != nil {
+		return nil, err
+	}
+
+	cm := convertCardDTOToModel(&cardData.Card)
+	cards, err := cs.CCDBI.GetCardsByUserId(cm.UserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		if card.AccountId == cm.AccountId {
+			return nil, ErrCardExists
+		}
+	}
+
+	cm.CardId = uuid.New()
+	cm.Name = strings.TrimSpace(cm.Name)
+	cm.CreatedAt = time.Now().UnixMilli()
+	err = cs.CCDBI.AddCard(ctx, &cm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertCardModelToDTO(&cm)
+	return &saved, nil
+}
+
+func (cs *CardService) RetrieveCards(userId uuid.UUID) ([]domain.CardAccountDTO, error) {
+	cards, err := cs.CCDBI.GetCardsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	cardDTOs := make([]domain.CardAccountDTO, 0, len(cards))
+	for _, cm := range cards {
+		cardDTOs = append(cardDTOs, convertCardModelToDTO(&cm))
+	}
+	return cardDTOs, nil
+}
+
+func (cs *CardService) DeleteCard(ctx context.Context, cardId uuid.UUID) error {
+	return cs.CCDBI.DeleteCard(ctx, cardId)
+}
+
+func (cs *CardService) RetrieveStatements(cardId uuid.UUID) (*domain.CardStatementsDTO, error) {
+	cm, err := cs.CCDBI.GetCard(cardId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	transactions, err := cs.TDBI.GetTransactionsByUserId(cm.UserId, 0, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	statements := cardStatements(&cm, transactions, now)
+	return &statements, nil
+}
+
+// RetrieveUpcomingPayments lists the latest statement of every card of the user that is not
+// paid in full, soonest due first. Older statements are left out as what is left of them is
+// carried into the latest balance.
+func (cs *CardService) RetrieveUpcomingPayments(userId uuid.UUID) ([]domain.UpcomingPaymentDTO, error) {
+	cards, err := cs.CCDBI.GetCardsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	transactions, err := cs.TDBI.GetTransactionsByUserId(userId, 0, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := []domain.UpcomingPaymentDTO{}
+	for i := range cards {
+		statements := cardStatements(&cards[i], transactions, now)
+		if len(statements.Statements) == 0 || statements.Statements[0].Remaining == 0 {
+			continue
+		}
+		latest := statements.Statements[0]
+		upcoming = append(upcoming, domain.UpcomingPaymentDTO{
+			CardId:         cards[i].CardId,
+			Name:           cards[i].Name,
+			DueDate:        latest.DueDate,
+			Balance:        latest.Balance,
+			MinimumPayment: latest.MinimumPayment,
+			Paid:           latest.Paid,
+			Remaining:      latest.Remaining,
+			Status:         latest.Status,
+		})
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].DueDate < upcoming[j].DueDate })
+	return upcoming, nil
+}
+
+// cardStatements closes a statement for every closing date from the first transaction on the
+// card up to now. Transactions must be ordered by date, those on other accounts and cancelled
+// ones are left out. Payments count towards a statement when made after it closed and by the
+// end of its due date, they lower the balance of the next statement as well.
+func cardStatements(cm *domain.CardAccountModel, transactions []domain.TransactionModel, now time.Time) domain.CardStatementsDTO {
+	var cardTransactions []domain.TransactionModel
+	for _, tm := range transactions {
+		if tm.AccountId == cm.AccountId && tm.Status != domain.CANCELLED {
+			cardTransactions = append(cardTransactions, tm)
+		}
+	}
+
+	first := now
+	if len(cardTransactions) > 0 {
+		first = time.UnixMilli(cardTransactions[0].Date)
+	}
+	closing := statementClosing(first, cm.ClosingDay)
+	periodStart := statementClosing(closing.AddDate(0, 0, -31), cm.ClosingDay).AddDate(0, 0, 1)
+
+	statements := domain.CardStatementsDTO{Card: convertCardModelToDTO(cm), Statements: []domain.StatementDTO{}}
+	var balance float64
+	t := 0
+	for ; closing.UnixMilli()+dayMillis <= now.UnixMilli(); closing = statementClosing(closing.AddDate(0, 0, 1), cm.ClosingDay) {
+		statement := domain.StatementDTO{CardId: cm.CardId, PeriodStart: periodStart.UnixMilli(), ClosingDate: closing.UnixMilli(), PreviousBalance: balance}
+		for ; t < len(cardTransactions) && cardTransactions[t].Date < closing.UnixMilli()+dayMillis; t++ {
+			if cardTransactions[t].Type == domain.EXPENSE {
+				statement.Charges += cardTransactions[t].Amount
+			} else {
+				statement.Credits += cardTransactions[t].Amount
+			}
+		}
+		balance = roundCents(balance + statement.Charges - statement.Credits)
+		statement.Charges = roundCents(statement.Charges)
+		statement.Credits = roundCents(statement.Credits)
+		statement.Balance = balance
+
+		due := statementDueDate(closing, cm.DueDay)
+		statement.DueDate = due.UnixMilli()
+		for p := t; p < len(cardTransactions) && cardTransactions[p].Date < due.UnixMilli()+dayMillis; p++ {
+			if cardTransactions[p].Type == domain.INCOME && cardTransactions[p].PaymentMethod != domain.CREDIT_CARD {
+				statement.Paid += cardTransactions[p].Amount
+			}
+		}
+		statement.Paid = roundCents(statement.Paid)
+		if balance > 0 {
+			statement.MinimumPayment = min(balance, max(cm.MinimumAmount, roundCents(balance*cm.MinimumPercent/100)))
+			statement.Remaining = max(0, roundCents(balance-statement.Paid))
+		}
+		statement.Status = statementStatus(&statement, now.UnixMilli() >= due.UnixMilli()+dayMillis)
+
+		statements.Statements = append(statements.Statements, statement)
+		periodStart = closing.AddDate(0, 0, 1)
+	}
+	for ; t < len(cardTransactions); t++ {
+		balance -= signedAmount(cardTransactions[t].Type, cardTransactions[t].Amount)
+	}
+
+	slices.Reverse(statements.Statements)
+	statements.CurrentBalance = roundCents(balance)
+	statements.NextClosing = closing.UnixMilli()
+	return statements
+}
+
+func statementStatus(statement *domain.StatementDTO, pastDue bool) domain.StatementStatus {
+	switch {
+	case statement.Remaining == 0:
+		return domain.STATEMENT_PAID
+	case statement.Paid >= statement.MinimumPayment:
+		return domain.STATEMENT_MINIMUM_PAID
+	case pastDue:
+		return domain.STATEMENT_OVERDUE
+	}
+	return domain.STATEMENT_DUE
+}
+
+// statementClosing is the first closing date, as a UTC day start, on or after the day of t.
+func statementClosing(t time.Time, closingDay int) time.Time {
+	day := startOfDay(t)
+	closing := dayOfMonth(day.Year(), day.Month(), closingDay)
+	if closing.Before(day) {
+		closing = dayOfMonth(day.Year(), day.Month()+1, closingDay)
+	}
+	return closing
+}
+
+// statementDueDate is the first due day after the closing date.
+func statementDueDate(closing time.Time, dueDay int) time.Time {
+	due := dayOfMonth(closing.Year(), closing.Month(), dueDay)
+	if !due.After(closing) {
+		due = dayOfMonth(closing.Year(), closing.Month()+1, dueDay)
+	}
+	return due
+}
+
+// dayOfMonth is the day of the month, or its last day when the month is shorter.
+func dayOfMonth(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(year, month, min(day, last), 0, 0, 0, 0, time.UTC)
+}
+
+func convertCardDTOToModel(from *domain.CardAccountDTO) domain.CardAccountModel {
+	return domain.CardAccountModel{
+		CardId:         from.CardId,
+		UserId:         from.UserId,
+		AccountId:      from.AccountId,
+		Name:           from.Name,
+		ClosingDay:     from.ClosingDay,
+		DueDay:         from.DueDay,
+		MinimumPercent: from.MinimumPercent,
+		MinimumAmount:  from.MinimumAmount,
+		CreatedAt:      from.CreatedAt,
+	}
+}
+
+func convertCardModelToDTO(from *domain.CardAccountModel) domain.CardAccountDTO {
+	return domain.CardAccountDTO{
+		CardId:         from.CardId,
+		UserId:         from.UserId,
+		AccountId:      from.AccountId,
+		Name:           from.Name,
+		ClosingDay:     from.ClosingDay,
+		DueDay:         from.DueDay,
+		MinimumPercent: from.MinimumPercent,
+		MinimumAmount:  from.MinimumAmount,
+		CreatedAt:      from.CreatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpCardModel(db *sql.DB) {
+	stmt := `create table card_account (
+		id integer primary key autoincrement,
+		card_id text not null,
+		user_id text not null,
+		account_id integer not null,
+		name text not null,
+		closing_day integer not null,
+		due_day integer not null,
+		minimum_percent float not null,
+		minimum_amount float not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating the card table:", err)
+	}
+}
+
+func TestCardStatements_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpCardModel(db)
+	udb := database.SQLManager{DB: db}
+	cardService := CardService{CCDBI: &udb, TDBI: &udb}
+
+	userId := uuid.New()
+	card := domain.CardAccountDTO{UserId: userId, AccountId: 3, Name: " Visa ", ClosingDay: 1, DueDay: 1, MinimumPercent: 5}
+	saved, err := cardService.AddCard(context.Background(), &domain.CardAccountData{Card: card, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding the card:", err)
+	}
+	if saved.Name != "Visa" {
+		t.Errorf("Expected the name to be trimmed, got %q", saved.Name)
+	}
+	if _, err := cardService.AddCard(context.Background(), &domain.CardAccountData{Card: card, Validator: validator.New()}); !errors.Is(err, ErrCardExists) {
+		t.Errorf("Expected ErrCardExists for a second card on the account, got %v", err)
+	}
+	paid, err := cardService.AddCard(context.Background(), &domain.CardAccountData{Card: domain.CardAccountDTO{UserId: userId, AccountId: 4, Name: "Amex", ClosingDay: 1, DueDay: 1}, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding the card:", err)
+	}
+
+	today := startOfDay(time.Now())
+	lastClosing := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if today.Equal(lastClosing) {
+		lastClosing = lastClosing.AddDate(0, -1, 0)
+	}
+	add := func(accountId int64, transactionType domain.TransactionType, method domain.TransactionMethod, amount float64, date time.Time) {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.AccountId = accountId
+		tm.Type = transactionType
+		tm.PaymentMethod = method
+		tm.Status = domain.CLEARED
+		tm.Amount = amount
+		tm.Date = date.UnixMilli()
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+	add(3, domain.EXPENSE, domain.CREDIT_CARD, 800, lastClosing.AddDate(0, 0, -10))
+	add(4, domain.EXPENSE, domain.CREDIT_CARD, 50, lastClosing.AddDate(0, 0, -10))
+	add(4, domain.INCOME, domain.BANK_TRANSFER, 50, lastClosing.AddDate(0, 0, 1))
+
+	statements, err := cardService.RetrieveStatements(saved.CardId)
+	if err != nil {
+		t.Fatal("Error retrieving the statements:", err)
+	}
+	if len(statements.Statements) != 1 || statements.Statements[0].Balance != 800 || statements.Statements[0].MinimumPayment != 40 || statements.CurrentBalance != 800 {
+		t.Errorf("Expected one statement of 800, got %+v", statements)
+	}
+
+	upcoming, err := cardService.RetrieveUpcomingPayments(userId)
+	if err != nil {
+		t.Fatal("Error retrieving upcoming payments:", err)
+	}
+	if len(upcoming) != 1 || upcoming[0].CardId != saved.CardId || upcoming[0].Remaining != 800 || upcoming[0].DueDate != lastClosing.AddDate(0, 1, 0).UnixMilli() {
+		t.Errorf("Expected only the unpaid Visa statement, got %+v", upcoming)
+	}
+
+	paidStatements, err := cardService.RetrieveStatements(paid.CardId)
+	if err != nil {
+		t.Fatal("Error retrieving the statements:", err)
+	}
+	if paidStatements.Statements[0].Status != domain.STATEMENT_PAID || paidStatements.CurrentBalance != 0 {
+		t.Errorf("Expected the Amex statement to be paid, got %+v", paidStatements)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestCardStatements(t *testing.T) {
+	cm := domain.CardAccountModel{CardId: uuid.New(), AccountId: 7, ClosingDay: 25, DueDay: 20, MinimumPercent: 2, MinimumAmount: 25}
+	day := func(month time.Month, d int, hour int) time.Time {
+		return time.Date(2026, month, d, hour, 0, 0, 0, time.UTC)
+	}
+	transaction := func(date time.Time, transactionType domain.TransactionType, method domain.TransactionMethod, amount float64) domain.TransactionModel {
+		return domain.TransactionModel{AccountId: 7, Date: date.UnixMilli(), Type: transactionType, PaymentMethod: method, Status: domain.CLEARED, Amount: amount}
+	}
+	cancelled := transaction(day(time.January, 12, 9), domain.EXPENSE, domain.CREDIT_CARD, 999)
+	cancelled.Status = domain.CANCELLED
+	otherAccount := transaction(day(time.January, 14, 9), domain.EXPENSE, domain.CREDIT_CARD, 999)
+	otherAccount.AccountId = 8
+	transactions := []domain.TransactionModel{
+		transaction(day(time.January, 10, 9), domain.EXPENSE, domain.CREDIT_CARD, 500),
+		cancelled,
+		otherAccount,
+		transaction(day(time.January, 20, 9), domain.EXPENSE, domain.CREDIT_CARD, 300),
+		transaction(day(time.January, 25, 23), domain.EXPENSE, domain.CREDIT_CARD, 200),
+		transaction(day(time.January, 26, 9), domain.INCOME, domain.CREDIT_CARD, 50),
+		transaction(day(time.February, 10, 9), domain.INCOME, domain.BANK_TRANSFER, 100),
+		transaction(day(time.February, 15, 9), domain.EXPENSE, domain.CREDIT_CARD, 400),
+		transaction(day(time.March, 5, 9), domain.EXPENSE, domain.CREDIT_CARD, 60),
+	}
+
+	statements := cardStatements(&cm, transactions, day(time.March, 22, 12))
+	if len(statements.Statements) != 2 {
+		t.Fatalf("Expected two closed statements, got %+v", statements.Statements)
+	}
+	latest, first := statements.Statements[0], statements.Statements[1]
+	if first.PeriodStart != time.Date(2025, time.December, 26, 0, 0, 0, 0, time.UTC).UnixMilli() || first.ClosingDate != day(time.January, 25, 0).UnixMilli() || first.DueDate != day(time.February, 20, 0).UnixMilli() {
+		t.Errorf("Unexpected first statement period %+v", first)
+	}
+	if first.Balance != 1000 || first.Paid != 100 || first.MinimumPayment != 25 || first.Remaining != 900 || first.Status != domain.STATEMENT_MINIMUM_PAID {
+		t.Errorf("Expected the first statement to have the minimum paid, got %+v", first)
+	}
+	if latest.PreviousBalance != 1000 || latest.Charges != 400 || latest.Credits != 150 || latest.Balance != 1250 || latest.Paid != 0 || latest.Status != domain.STATEMENT_OVERDUE {
+		t.Errorf("Expected the latest statement to be overdue, got %+v", latest)
+	}
+	if statements.CurrentBalance != 1310 || statements.NextClosing != day(time.March, 25, 0).UnixMilli() {
+		t.Errorf("Expected the open cycle to owe 1310, got %v closing %v", statements.CurrentBalance, statements.NextClosing)
+	}
+
+	statements = cardStatements(&cm, transactions, day(time.March, 12, 12))
+	if statements.Statements[0].Status != domain.STATEMENT_DUE {
+		t.Errorf("Expected the latest statement to be due before its due date, got %+v", statements.Statements[0])
+	}
+}
+
+func TestStatementDates(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        time.Time
+		closingDay  int
+		dueDay      int
+		wantClosing time.Time
+		wantDue     time.Time
+	}{
+		{"closes later in the month", time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC), 15, 5, time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC)},
+		{"closes on the day itself", time.Date(2026, time.March, 15, 22, 0, 0, 0, time.UTC), 15, 28, time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, time.March, 28, 0, 0, 0, 0, time.UTC)},
+		{"short month", time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC), 31, 31, time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC), time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{"year end", time.Date(2026, time.December, 29, 0, 0, 0, 0, time.UTC), 28, 20, time.Date(2027, time.January, 28, 0, 0, 0, 0, time.UTC), time.Date(2027, time.February, 20, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		closing := statementClosing(tt.from, tt.closingDay)
+		if !closing.Equal(tt.wantClosing) {
+			t.Errorf("%s: expected closing on %v, got %v", tt.name, tt.wantClosing, closing)
+		}
+		if due := statementDueDate(closing, tt.dueDay); !due.Equal(tt.wantDue) {
+			t.Errorf("%s: expected due on %v, got %v", tt.name, tt.wantDue, due)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// Number of suggestions returned with a transaction saved without a category.
+const defaultSuggestionCount = 3
+
+type ClassifierServiceInterface interface {
+	Suggest(tm *domain.TransactionModel, limit int) ([]domain.CategorySuggestionDTO, error)
+	SuggestForTransaction(transactionId uuid.UUID, limit int) ([]domain.CategorySuggestionDTO, error)
+	Learn(ctx context.Context, transactions ...domain.TransactionModel) error
+	Unlearn(ctx context.Context, transactions ...domain.TransactionModel) error
+	CorrectCategory(ctx context.Context, correctionData *domain.CategoryCorrectionData) error
+	Train(ctx context.Context, userId uuid.UUID) error
+}
+
+// ClassifierService suggests categories with a naive Bayes classifier trained on the users own
+// categorized transactions. Everything runs in process, the model is stored with the user.
+type ClassifierService struct {
+	CDBI database.ClassifierDatabaseInterface
+	TDBI database.TransactionDatabaseInterface
+
+	Reconciler ReconciliationServiceInterface // optional, refuses to recategorize reconciled transactions.
+	Events     EventBusInterface              // optional, saves a transaction.updated event with the new category.
+
+	mu sync.Mutex // guards the load, update and save of a model.
+}
+
+func (cs *ClassifierService) Suggest(tm *domain.TransactionModel, limit int) ([]domain.CategorySuggestionDTO, error) {
+	cm, err := cs.loadClassifier(tm.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return suggestCategories(&cm, tm, limit), nil
+}
+
+func (cs *ClassifierService) SuggestForTransaction(transactionId uuid.UUID, limit int) ([]domain.CategorySuggestionDTO, error) {
+	tm, err := cs.TDBI.GetTransaction(transactionId)
+	if err != nil {
+		return nil, err
+	}
+	return cs.Suggest(&tm, limit)
+}
+
+// Learn adds the categorized transactions to their users models, saving each model once so a
+// whole import is learned with one write.
+func (cs *ClassifierService) Learn(ctx context.Context, transactions ...domain.TransactionModel) error {
+	var categorized []domain.TransactionModel
+	for _, tm := range transactions {
+		if tm.CategoryId != 0 {
+			categorized = append(categorized, tm)
+		}
+	}
+	return cs.updateModels(ctx, categorized, false, learnTransaction)
+}
+
+// Unlearn removes the transactions from their users models, the transactions deleted and those
+// about to be learned again with new contents. The model keeps which transaction it learned, the
+// entries of transactions deleted without being unlearned are dropped at the same time. Their
+// counts stay until the model is trained again.
+func (cs *ClassifierService) Unlearn(ctx context.Context, transactions ...domain.TransactionModel) error {
+	return cs.updateModels(ctx, transactions, true, unlearnTransaction)
+}
+
+// updateModels changes the models of the users of the transactions, saving each once.
+func (cs *ClassifierService) updateModels(ctx context.Context, transactions []domain.TransactionModel, prune bool, update func(*domain.ClassifierModel, *domain.TransactionModel)) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	models := map[uuid.UUID]*domain.ClassifierModel{}
+	var users []uuid.UUID
+	for i := range transactions {
+		tm := &transactions[i]
+		cm, ok := models[tm.UserId]
+		if !ok {
+			loaded, err := cs.loadClassifier(tm.UserId)
+			if err != nil {
+				return err
+			}
+			cm = &loaded
+			models[tm.UserId] = cm
+			users = append(users, tm.UserId)
+		}
+		update(cm, tm)
+	}
+
+	for _, userId := range users {
+		if prune {
+			err := cs.pruneLearned(models[userId])
+			if err != nil {
+				return err
+			}
+		}
+		err := cs.saveClassifier(ctx, models[userId])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneLearned drops the learned entries of the transactions the user no longer has.
+func (cs *ClassifierService) pruneLearned(cm *domain.ClassifierModel) error {
+	if len(cm.Learned) == 0 {
+		return nil
+	}
+	transactions, err := cs.TDBI.GetTransactionsByUserId(cm.UserId, math.MinInt64, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[uuid.UUID]bool, len(transactions))
+	for _, tm := range transactions {
+		existing[tm.TransactionId] = true
+	}
+	for transactionId := range cm.Learned {
+		if !existing[transactionId] {
+			delete(cm.Learned, transactionId)
+		}
+	}
+	return nil
+}
+
+// CorrectCategory saves the users chosen category and moves the transaction to that category in the model.
+func (cs *ClassifierService) CorrectCategory(ctx context.Context, correctionData *domain.CategoryCorrectionData) error {
+	err := correctionData.ValidateCategoryCorrection()
+	if err != nil {
+		return err
+	}
+	correction := correctionData.Correction
+
+	tm, err := cs.TDBI.GetTransaction(correction.TransactionId)
+	if err != nil {
+		return err
+	}
+	if tm.CategoryId == correction.CategoryId {
+		return nil
+	}
+	if cs.Reconciler != nil {
+		err = cs.Reconciler.CheckUnlocked(tm.TransactionId)
+		if err != nil {
+			return err
+		}
+	}
+
+	tm.CategoryId = correction.CategoryId
+	tm.UpdatedAt = time.Now().UnixMilli()
+	events, err := recordEvent(cs.Events, domain.EVENT_TRANSACTION_UPDATED, tm.UserId, tm.TransactionId, convertTransactionModelToDTO(&tm))
+	if err != nil {
+		return err
+	}
+	err = cs.TDBI.UpdateTransaction(ctx, &tm, events...)
+	if err != nil {
+		return err
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cm, err := cs.loadClassifier(tm.UserId)
+	if err != nil {
+		return err
+	}
+	learnTransaction(&cm, &tm)
+	return cs.saveClassifier(ctx, &cm)
+}
+
+// Train rebuilds the users model from every categorized transaction.
+func (cs *ClassifierService) Train(ctx context.Context, userId uuid.UUID) error {
+	transactions, err := cs.TDBI.GetTransactionsByUserId(userId, math.MinInt64, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+
+	cm := domain.NewClassifierModel(userId)
+	for _, tm := range transactions {
+		if tm.CategoryId != 0 {
+			learnTransaction(&cm, &tm)
+		}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.saveClassifier(ctx, &cm)
+}
+
+func (cs *ClassifierService) loadClassifier(userId uuid.UUID) (domain.ClassifierModel, error) {
+	cm, err := cs.CDBI.GetClassifier(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewClassifierModel(userId), nil
+	}
+	return cm, err
+}
+
+func (cs *ClassifierService) saveClassifier(ctx context.Context, cm *domain.ClassifierModel) error {
+	cm.UpdatedAt = time.Now().UnixMilli()
+	return cs.CDBI.SaveClassifier(ctx, cm)
+}
+
+// classifierTokens are the features of a transaction: its normalized description words,
+// the order of magnitude of the amount and the transaction type.
+func classifierTokens(tm *domain.TransactionModel) []string {
+	tokens := normalizeDescription(tm.Description)
+	magnitude := 0
+	if amount := math.Abs(tm.Amount); amount >= 1 {
+		magnitude = int(math.Log10(amount)) + 1
+	}
+	return append(tokens, fmt.Sprintf("#amount:%d", magnitude), "#type:"+tm.Type.String())
+}
+
+// learnTransaction counts the transaction in its category, moving it out of the category it was
+// learned in before. Transactions never learned, like those saved before the model existed or
+// uncategorized, are only added.
+func learnTransaction(cm *domain.ClassifierModel, tm *domain.TransactionModel) {
+	unlearnTransaction(cm, tm)
+	if tm.CategoryId != 0 {
+		updateClassifier(cm, tm, tm.CategoryId, 1)
+		cm.Learned[tm.TransactionId] = tm.CategoryId
+	}
+}
+
+// unlearnTransaction removes the transaction from the category it was learned in.
+func unlearnTransaction(cm *domain.ClassifierModel, tm *domain.TransactionModel) {
+	if learned, ok := cm.Learned[tm.TransactionId]; ok {
+		updateClassifier(cm, tm, learned, -1)
+		delete(cm.Learned, tm.TransactionId)
+	}
+}
+
+// updateClassifier adds (delta 1) or removes (delta -1) a transaction from categoryId. Counts stop
+// at zero, the tokens of a transaction can have changed since it was learned.
+func updateClassifier(cm *domain.ClassifierModel, tm *domain.TransactionModel, categoryId int64, delta int) {
+	if delta < 0 && cm.CategoryCounts[categoryId] <= 0 {
+		return
+	}
+	if cm.TokenCounts[categoryId] == nil {
+		cm.TokenCounts[categoryId] = map[string]int{}
+	}
+
+	cm.CategoryCounts[categoryId] += delta
+	for _, token := range classifierTokens(tm) {
+		if delta < 0 && cm.TokenCounts[categoryId][token] <= 0 {
+			continue
+		}
+		cm.TokenCounts[categoryId][token] += delta
+		cm.TokenTotals[categoryId] += delta
+		cm.Vocabulary[token] += delta
+
+		if cm.TokenCounts[categoryId][token] <= 0 {
+			delete(cm.TokenCounts[categoryId], token)
+		}
+		if cm.Vocabulary[token] <= 0 {
+			delete(cm.Vocabulary, token)
+		}
+	}
+
+	if cm.CategoryCounts[categoryId] <= 0 {
+		delete(cm.CategoryCounts, categoryId)
+		delete(cm.TokenCounts, categoryId)
+		delete(cm.TokenTotals, categoryId)
+	}
+}
+
+// suggestCategories scores every known category with Laplace smoothed naive Bayes and
+// returns the best limit categories, confidence is the normalized posterior probability.
+func suggestCategories(cm *domain.ClassifierModel, tm *domain.TransactionModel, limit int) []domain.CategorySuggestionDTO {
+	suggestions := []domain.CategorySuggestionDTO{}
+	documents := 0
+	for _, count := range cm.CategoryCounts {
+		documents += count
+	}
+	if documents == 0 || limit <= 0 {
+		return suggestions
+	}
+
+	tokens := classifierTokens(tm)
+	vocabulary := float64(len(cm.Vocabulary) + 1)
+	logProbs := make(map[int64]float64, len(cm.CategoryCounts))
+	best := math.Inf(-1)
+	for categoryId, count := range cm.CategoryCounts {
+		logProb := math.Log(float64(count) / float64(documents))
+		total := float64(cm.TokenTotals[categoryId])
+		for _, token := range tokens {
+			logProb += math.Log((float64(cm.TokenCounts[categoryId][token]) + 1) / (total + vocabulary))
+		}
+		logProbs[categoryId] = logProb
+		best = math.Max(best, logProb)
+	}
+
+	var sum float64
+	for _, logProb := range logProbs {
+		sum += math.Exp(logProb - best)
+	}
+	for categoryId, logProb := range logProbs {
+		confidence := math.Exp(logProb-best) / sum
+		suggestions = append(suggestions, domain.CategorySuggestionDTO{CategoryId: categoryId, Confidence: math.Round(confidence*1000) / 1000})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].CategoryId < suggestions[j].CategoryId
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+)
+
+func TestClassifierPersistence_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	_, err := db.Exec(`create table classifier_model (
+		id integer primary key autoincrement,
+		user_id text not null,
+		model text not null,
+		updated_at integer not null
+	)`)
+	if err != nil {
+		log.Fatal("There was an error creating classifier_model table:", err)
+	}
+	udb := database.SQLManager{DB: db}
+	classifierService := ClassifierService{CDBI: &udb, TDBI: &udb}
+
+	userId := uuid.New()
+	for _, tm := range trainingHistory(userId) {
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error saving transaction:", err)
+		}
+	}
+
+	// training twice replaces the stored model.
+	for i := 0; i < 2; i++ {
+		if err := classifierService.Train(context.Background(), userId); err != nil {
+			t.Fatal("Error training the classifier:", err)
+		}
+	}
+
+	var rows int
+	if err := db.QueryRow(`select count(*) from classifier_model where user_id = ?`, userId).Scan(&rows); err != nil || rows != 1 {
+		t.Fatalf("Expected one stored model, got %d, err: %v", rows, err)
+	}
+
+	cm, err := udb.GetClassifier(userId)
+	if err != nil {
+		t.Fatal("Error loading the classifier:", err)
+	}
+	if !reflect.DeepEqual(cm.CategoryCounts, map[int64]int{1: 3, 2: 2, 3: 1}) || cm.TokenCounts[1]["starbucks"] != 2 {
+		t.Fatalf("The stored model does not match, got %v", cm)
+	}
+}
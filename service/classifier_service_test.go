@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type StubClassifierDatabase struct {
+	StubDatabase
+	classifiers  map[uuid.UUID]domain.ClassifierModel
+	transactions []domain.TransactionModel
+	saves        int
+}
+
+func newStubClassifierDatabase(transactions ...domain.TransactionModel) *StubClassifierDatabase {
+	return &StubClassifierDatabase{classifiers: map[uuid.UUID]domain.ClassifierModel{}, transactions: transactions}
+}
+
+func (m *StubClassifierDatabase) GetClassifier(userId uuid.UUID) (domain.ClassifierModel, error) {
+	cm, ok := m.classifiers[userId]
+	if !ok {
+		return cm, sql.ErrNoRows
+	}
+	return cm, nil
+}
+
+func (m *StubClassifierDatabase) SaveClassifier(ctx context.Context, cm *domain.ClassifierModel) error {
+	m.classifiers[cm.UserId] = *cm
+	m.saves++
+	return nil
+}
+
+func (m *StubClassifierDatabase) AddTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	m.transactions = append(m.transactions, *tm)
+	return nil
+}
+
+func (m *StubClassifierDatabase) AddTransactionWith(ctx context.Context, tm *domain.TransactionModel, payee *domain.PayeeModel, tags []domain.TagModel, events ...domain.OutboxEventModel) error {
+	return m.AddTransaction(ctx, tm, events...)
+}
+
+func (m *StubClassifierDatabase) GetTransaction(transactionId uuid.UUID) (domain.TransactionModel, error) {
+	for _, tm := range m.transactions {
+		if tm.TransactionId == transactionId {
+			return tm, nil
+		}
+	}
+	return domain.TransactionModel{}, sql.ErrNoRows
+}
+
+func (m *StubClassifierDatabase) GetTransactionsByUserId(userId uuid.UUID, from int64, to int64) ([]domain.TransactionModel, error) {
+	return m.transactions, nil
+}
+
+func (m *StubClassifierDatabase) UpdateTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	for i := range m.transactions {
+		if m.transactions[i].TransactionId == tm.TransactionId {
+			m.transactions[i] = *tm
+		}
+	}
+	return nil
+}
+
+func (m *StubClassifierDatabase) DeleteTransaction(ctx context.Context, transactionId uuid.UUID, events ...domain.OutboxEventModel) error {
+	m.transactions = slices.DeleteFunc(m.transactions, func(tm domain.TransactionModel) bool {
+		return tm.TransactionId == transactionId
+	})
+	return nil
+}
+
+func categorizedTransaction(userId uuid.UUID, description string, amount float64, categoryId int64) domain.TransactionModel {
+	tm := domain.TransactionModelBuilder().Build()
+	tm.UserId = userId
+	tm.Description = description
+	tm.Amount = amount
+	tm.CategoryId = categoryId
+	tm.Type = domain.EXPENSE
+	return tm
+}
+
+func trainingHistory(userId uuid.UUID) []domain.TransactionModel {
+	return []domain.TransactionModel{
+		categorizedTransaction(userId, "Starbucks coffee", 4.50, 1),
+		categorizedTransaction(userId, "Blue Bottle coffee", 5.25, 1),
+		categorizedTransaction(userId, "STARBUCKS STORE 1234", 6.10, 1),
+		categorizedTransaction(userId, "Shell gas station", 45, 2),
+		categorizedTransaction(userId, "Chevron gas", 52, 2),
+		categorizedTransaction(userId, "Kroger groceries", 86, 3),
+		categorizedTransaction(userId, "Uncategorized thing", 10, 0),
+	}
+}
+
+func TestClassifierTrainAndSuggest(t *testing.T) {
+	userId := uuid.New()
+	stubDB := newStubClassifierDatabase(trainingHistory(userId)...)
+	classifierService := ClassifierService{CDBI: stubDB, TDBI: stubDB}
+
+	err := classifierService.Train(context.Background(), userId)
+	if err != nil {
+		t.Fatal("Error training the classifier:", err)
+	}
+	if documents := stubDB.classifiers[userId].CategoryCounts; documents[1] != 3 || documents[2] != 2 || documents[3] != 1 || documents[0] != 0 {
+		t.Fatalf("Wrong training counts, got %v", documents)
+	}
+
+	tests := []struct {
+		name         string
+		description  string
+		amount       float64
+		wantCategory int64
+	}{
+		{name: "Coffee", description: "STARBUCKS #998", amount: 5, wantCategory: 1},
+		{name: "Gas", description: "Shell Oil 5543", amount: 48, wantCategory: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tm := categorizedTransaction(userId, test.description, test.amount, 0)
+			suggestions, err := classifierService.Suggest(&tm, 2)
+			if err != nil {
+				t.Fatal("Error suggesting categories:", err)
+			}
+			if len(suggestions) != 2 {
+				t.Fatalf("Expected 2 suggestions, got %v", suggestions)
+			}
+			if suggestions[0].CategoryId != test.wantCategory {
+				t.Errorf("Wrong top suggestion, got %v, want category %d", suggestions, test.wantCategory)
+			}
+			if suggestions[0].Confidence <= suggestions[1].Confidence || suggestions[0].Confidence > 1 {
+				t.Errorf("Suggestions are not ordered by confidence, got %v", suggestions)
+			}
+		})
+	}
+}
+
+func TestClassifierSuggest_NoModel(t *testing.T) {
+	stubDB := newStubClassifierDatabase()
+	classifierService := ClassifierService{CDBI: stubDB, TDBI: stubDB}
+
+	tm := categorizedTransaction(uuid.New(), "Anything", 10, 0)
+	suggestions, err := classifierService.Suggest(&tm, 3)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(suggestions) != 0 {
+		t.Fatalf("Expected no suggestions without a model, got %v", suggestions)
+	}
+}
+
+func TestClassifierCorrectCategory(t *testing.T) {
+	userId := uuid.New()
+	history := trainingHistory(userId)
+	stubDB := newStubClassifierDatabase(history...)
+	classifierService := ClassifierService{CDBI: stubDB, TDBI: stubDB}
+	if err := classifierService.Train(context.Background(), userId); err != nil {
+		t.Fatal("Error training the classifier:", err)
+	}
+
+	// the Kroger transaction was really fuel.
+	correction := domain.CategoryCorrectionDTO{TransactionId: history[5].TransactionId, CategoryId: 2}
+	err := classifierService.CorrectCategory(context.Background(), &domain.CategoryCorrectionData{Correction: correction, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error correcting the category:", err)
+	}
+
+	if stubDB.transactions[5].CategoryId != 2 {
+		t.Errorf("The transaction was not updated, got %v", stubDB.transactions[5])
+	}
+	cm := stubDB.classifiers[userId]
+	if _, ok := cm.CategoryCounts[3]; ok || cm.CategoryCounts[2] != 3 {
+		t.Errorf("The model was not moved to the new category, got %v", cm.CategoryCounts)
+	}
+	if _, ok := cm.Vocabulary["kroger"]; !ok || cm.TokenCounts[2]["kroger"] != 1 {
+		t.Errorf("The tokens were not moved to the new category, got %v", cm.TokenCounts)
+	}
+}
+
+func TestClassifierCorrectCategory_NotLearned(t *testing.T) {
+	userId := uuid.New()
+	stubDB := newStubClassifierDatabase(trainingHistory(userId)...)
+	classifierService := ClassifierService{CDBI: stubDB, TDBI: stubDB}
+	if err := classifierService.Train(context.Background(), userId); err != nil {
+		t.Fatal("Error training the classifier:", err)
+	}
+
+	// saved in category 3 without the model learning it, like before the model existed.
+	unlearned := categorizedTransaction(userId, "Whole Foods market", 64, 3)
+	stubDB.transactions = append(stubDB.transactions, unlearned)
+	correction := domain.CategoryCorrectionDTO{TransactionId: unlearned.TransactionId, CategoryId: 2}
+	err := classifierService.CorrectCategory(context.Background(), &domain.CategoryCorrectionData{Correction: correction, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error correcting the category:", err)
+	}
+
+	cm := stubDB.classifiers[userId]
+	if cm.CategoryCounts[3] != 1 || cm.TokenTotals[3] != len(classifierTokens(&trainingHistory(userId)[5])) || cm.CategoryCounts[2] != 3 {
+		t.Errorf("Only the new category should change, got %v with totals %v", cm.CategoryCounts, cm.TokenTotals)
+	}
+	if cm.Learned[unlearned.TransactionId] != 2 || cm.TokenCounts[2]["whole"] != 1 {
+		t.Errorf("The transaction was not learned in its new category, got %v", cm.TokenCounts[2])
+	}
+}
+
+func TestDeleteTransaction_Unlearns(t *testing.T) {
+	userId := uuid.New()
+	history := trainingHistory(userId)
+	stubDB := newStubClassifierDatabase(history...)
+	classifierService := ClassifierService{CDBI: stubDB, TDBI: stubDB}
+	transactionService := TransactionService{UDBI: stubDB, Classifier: &classifierService}
+	if err := classifierService.Train(context.Background(), userId); err != nil {
+		t.Fatal("Error training the classifier:", err)
+	}
+	// learned before deleted transactions were unlearned.
+	stale := uuid.New()
+	cm := stubDB.classifiers[userId]
+	cm.Learned[stale] = 1
+	stubDB.classifiers[userId] = cm
+
+	err := transactionService.DeleteTransaction(context.Background(), history[5].TransactionId)
+	if err != nil {
+		t.Fatal("Error deleting the transaction:", err)
+	}
+
+	cm = stubDB.classifiers[userId]
+	if _, ok := cm.CategoryCounts[3]; ok || cm.Vocabulary["kroger"] != 0 {
+		t.Errorf("The deleted transaction is still in the model, got %v and %v", cm.CategoryCounts, cm.Vocabulary)
+	}
+	if _, ok := cm.Learned[history[5].TransactionId]; ok || len(cm.Learned) != 5 {
+		t.Errorf("Expected the deleted and the stale transactions to be dropped, got %v", cm.Learned)
+	}
+	if _, ok := cm.Learned[stale]; ok {
+		t.Errorf("The stale transaction was not pruned, got %v", cm.Learned)
+	}
+}
+
+func TestUpdateClassifier_StopsAtZero(t *testing.T) {
+	userId := uuid.New()
+	cm := domain.NewClassifierModel(userId)
+	learned := categorizedTransaction(userId, "Starbucks coffee", 4.50, 1)
+	updateClassifier(&cm, &learned, 1, 1)
+
+	// the description changed since it was learned.
+	changed := learned
+	changed.Description = "Starbucks reserve roastery"
+	updateClassifier(&cm, &changed, 1, -1)
+	updateClassifier(&cm, &changed, 1, -1)
+
+	if len(cm.CategoryCounts) != 0 || len(cm.TokenTotals) != 0 || cm.Vocabulary["coffee"] != 1 {
+		t.Errorf("Unexpected counts %v, totals %v and vocabulary %v", cm.CategoryCounts, cm.TokenTotals, cm.Vocabulary)
+	}
+	for token, count := range cm.Vocabulary {
+		if count < 0 {
+			t.Errorf("Negative count %d for %q", count, token)
+		}
+	}
+}
+
+func TestImportTransactions_LearnsOnce(t *testing.T) {
+	userId := uuid.New()
+	stubDB := newStubClassifierDatabase()
+	classifierService := ClassifierService{CDBI: stubDB, TDBI: stubDB}
+	transactionService := TransactionService{UDBI: stubDB, Classifier: &classifierService}
+
+	var transactions []domain.TransactionDTO
+	for _, tm := range trainingHistory(userId) {
+		transaction := domain.TransactionDTOBuilder().WithDescription(tm.Description).Build()
+		transaction.UserId = userId
+		transaction.CategoryId = tm.CategoryId
+		transactions = append(transactions, transaction)
+	}
+	_, err := transactionService.ImportTransactions(context.Background(), &domain.TransactionImportData{Transactions: transactions, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error importing the transactions:", err)
+	}
+
+	if stubDB.saves != 1 {
+		t.Errorf("Expected the model to be saved once, got %d saves", stubDB.saves)
+	}
+	if cm := stubDB.classifiers[userId]; cm.CategoryCounts[1] != 3 || len(cm.Learned) != 6 {
+		t.Errorf("The import was not learned, got %v", cm.CategoryCounts)
+	}
+}
+
+func TestAddTransaction_SuggestsCategory(t *testing.T) {
+	userId := uuid.New()
+	stubDB := newStubClassifierDatabase(trainingHistory(userId)...)
+	classifierService := ClassifierService{CDBI: stubDB, TDBI: stubDB}
+	transactionService := TransactionService{UDBI: stubDB, Classifier: &classifierService}
+	if err := classifierService.Train(context.Background(), userId); err != nil {
+		t.Fatal("Error training the classifier:", err)
+	}
+
+	uncategorized := domain.TransactionDTOBuilder().WithDescription("Starbucks coffee").Build()
+	uncategorized.UserId = userId
+	uncategorized.CategoryId = 0
+	result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: uncategorized, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(result.Suggestions) == 0 || result.Suggestions[0].CategoryId != 1 {
+		t.Errorf("Expected category 1 to be suggested, got %v", result.Suggestions)
+	}
+
+	categorized := domain.TransactionDTOBuilder().WithDescription("Costco groceries").Build()
+	categorized.UserId = userId
+	categorized.CategoryId = 3
+	result, err = transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: categorized, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if result.Suggestions != nil {
+		t.Errorf("No suggestions expected for a categorized transaction, got %v", result.Suggestions)
+	}
+	if stubDB.classifiers[userId].TokenCounts[3]["costco"] != 1 {
+		t.Error("The categorized transaction was not learned")
+	}
+}
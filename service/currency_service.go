@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+var ErrNoExchangeRate = errors.New("no exchange rate was published on or before the date")
+
+type CurrencyServiceInterface interface {
+	ImportRates(data []byte) (*domain.RateImportDTO, error)
+	Convert(amount float64, from string, to string, date int64) (*domain.ConversionDTO, error)
+	RetrieveBaseCurrency(userId uuid.UUID) (string, error)
+	SetBaseCurrency(ctx context.Context, baseData *domain.BaseCurrencyData) (*domain.BaseCurrencyChangeDTO, error)
+	SetAccountCurrency(ctx context.Context, accountData *domain.AccountCurrencyData) error
+	RetrieveAccountCurrency(userId uuid.UUID, accountId int64) (string, error)
+	ConvertTransaction(tm *domain.TransactionModel) error
+}
+
+type CurrencyService struct {
+	CRDBI       database.CurrencyDatabaseInterface
+	TDBI        database.TransactionDatabaseInterface
+	DefaultBase string                         // optional, the base currency of users who have not chosen one, domain.DefaultBaseCurrency without it.
+	Reconciler  ReconciliationServiceInterface // optional, refuses to change the base currency while a transaction is reconciled.
+	Events      EventBusInterface              // optional, saves transaction.updated events for the transactions converted into a new base currency.
+	Classifier  ClassifierServiceInterface     // optional, trains the model again on the amounts converted into a new base currency.
+}
+
+// ImportRates saves the euro reference rates of an ECB XML or CSV file, replacing the rates
+// of the same currencies and days imported before.
+func (cs *CurrencyService) ImportRates(data []byte) (*domain.RateImportDTO, error) {
+	rates, err := parseECBRates(data)
+	if err != nil {
+		return nil, err
+	}
+	err = cs.CRDBI.SaveExchangeRates(rates)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := map[string]bool{}
+	imported := &domain.RateImportDTO{Rates: len(rates), From: rates[0].Date, To: rates[0].Date}
+	for _, rate := range rates {
+		currencies[rate.Currency] = true
+		imported.From = min(imported.From, rate.Date)
+		imported.To = max(imported.To, rate.Date)
+	}
+	imported.Currencies = len(currencies)
+	return imported, nil
+}
+
+// Convert converts the amount with the rates published on or before the date.
+func (cs *CurrencyService) Convert(amount float64, from string, to string, date int64) (*domain.ConversionDTO, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	converted, rate, err := cs.convert(amount, from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ConversionDTO{Amount: amount, From: from, To: to, Date: date, Rate: rate, Converted: converted}, nil
+}
+
+func (cs *CurrencyService) RetrieveBaseCurrency(userId uuid.UUID) (string, error) {
+	return cs.baseCurrency(userId)
+}
+
+// SetBaseCurrency changes the users base currency and converts the amounts of their
+// transactions again from the original amounts, each at the rate on its date. Transactions
+// saved before they had a currency are taken to be in the old base currency. The base currency
+// and the converted transactions are saved together, nothing is when a rate is missing for any
+// of them or one of them is reconciled.
+func (cs *CurrencyService) SetBaseCurrency(ctx context.Context, baseData *domain.BaseCurrencyData) (*domain.BaseCurrencyChangeDTO, error) {
+	err := baseData.ValidateBaseCurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	userId, base := baseData.BaseCurrency.UserId, baseData.BaseCurrency.Currency
+	previous, err := cs.baseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := cs.TDBI.GetTransactionsByUserId(userId, math.MinInt64, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	var events []domain.OutboxEventModel
+	for i := range transactions {
+		tm := &transactions[i]
+		if cs.Reconciler != nil {
+			err = cs.Reconciler.CheckUnlocked(tm.TransactionId)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// the amount of a transaction saved without a currency is in the old base currency, it
+		// becomes the original amount the new one is converted from.
+		if tm.Currency == "" {
+			tm.Currency = previous
+			tm.OriginalAmount = tm.Amount
+		}
+		amount, _, err := cs.convert(tm.OriginalAmount, tm.Currency, base, tm.Date)
+		if err != nil {
+			return nil, err
+		}
+		tm.Amount = amount
+		tm.UpdatedAt = now
+		event, err := recordEvent(cs.Events, domain.EVENT_TRANSACTION_UPDATED, tm.UserId, tm.TransactionId, convertTransactionModelToDTO(tm))
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event...)
+	}
+
+	err = cs.CRDBI.SaveBaseCurrency(ctx, &domain.BaseCurrencyModel{UserId: userId, Currency: base}, transactions, events...)
+	if err != nil {
+		return nil, err
+	}
+	// the amounts are features of the classifier, every transaction of the user has a new one.
+	if cs.Classifier != nil {
+		err = cs.Classifier.Train(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &domain.BaseCurrencyChangeDTO{UserId: userId, Currency: base, Transactions: len(transactions)}, nil
+}
+
+// SetAccountCurrency sets the currency of the transactions later added to the account without
+// one. Transactions already saved keep the currency they were saved in.
+func (cs *CurrencyService) SetAccountCurrency(ctx context.Context, accountData *domain.AccountCurrencyData) error {
+	err := accountData.ValidateAccountCurrency()
+	if err != nil {
+		return err
+	}
+
+	account := accountData.AccountCurrency
+	return cs.CRDBI.SaveAccountCurrency(ctx, &domain.AccountCurrencyModel{UserId: account.UserId, AccountId: account.AccountId, Currency: account.Currency})
+}
+
+// RetrieveAccountCurrency returns the currency of the account, empty when it has none.
+func (cs *CurrencyService) RetrieveAccountCurrency(userId uuid.UUID, accountId int64) (string, error) {
+	currency, err := cs.CRDBI.GetAccountCurrency(userId, accountId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return currency, err
+}
+
+// ConvertTransaction sets the amount of the transaction to its OriginalAmount converted into
+// the users base currency, at the rate on the transaction date. Without a currency the
+// transaction is in the currency of its account, or else already in the base currency.
+func (cs *CurrencyService) ConvertTransaction(tm *domain.TransactionModel) error {
+	currency := strings.ToUpper(tm.Currency)
+	if currency == "" && tm.AccountId != 0 {
+		accountCurrency, err := cs.RetrieveAccountCurrency(tm.UserId, tm.AccountId)
+		if err != nil {
+			return err
+		}
+		currency = accountCurrency
+	}
+	base, err := cs.baseCurrency(tm.UserId)
+	if err != nil {
+		return err
+	}
+	if currency == "" {
+		currency = base
+	}
+
+	amount, _, err := cs.convert(tm.OriginalAmount, currency, base, tm.Date)
+	if err != nil {
+		return err
+	}
+	tm.Currency = currency
+	tm.Amount = amount
+	return nil
+}
+
+func (cs *CurrencyService) baseCurrency(userId uuid.UUID) (string, error) {
+	base, err := cs.CRDBI.GetBaseCurrency(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		if cs.DefaultBase != "" {
+			return strings.ToUpper(cs.DefaultBase), nil
+		}
+		return domain.DefaultBaseCurrency, nil
+	}
+	return base, err
+}
+
+// convert returns the amount in to, rounded to cents, and the number of to one from buys.
+// Rates are quoted against the euro, so other pairs are crossed through it.
+func (cs *CurrencyService) convert(amount float64, from string, to string, date int64) (float64, float64, error) {
+	if from == to {
+		return amount, 1, nil
+	}
+
+	day := time.UnixMilli(date).UTC().Format(domain.RateDateLayout)
+	fromRate, err := cs.euroRate(from, day)
+	if err != nil {
+		return 0, 0, err
+	}
+	toRate, err := cs.euroRate(to, day)
+	if err != nil {
+		return 0, 0, err
+	}
+	rate := toRate / fromRate
+	return roundCents(amount * rate), rate, nil
+}
+
+func (cs *CurrencyService) euroRate(currency string, day string) (float64, error) {
+	if currency == domain.RateBaseCurrency {
+		return 1, nil
+	}
+	rate, err := cs.CRDBI.GetExchangeRate(currency, day)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: %s on %s", ErrNoExchangeRate, currency, day)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rate.Rate, nil
+}
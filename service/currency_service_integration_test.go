@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpCurrencyModel(db *sql.DB) {
+	stmts := []string{
+		`create table exchange_rate (
+			id integer primary key autoincrement,
+			currency text not null,
+			date text not null,
+			rate float not null
+		)`,
+		`create table base_currency (
+			id integer primary key autoincrement,
+			user_id text not null,
+			currency text not null
+		)`,
+		`create table account_currency (
+			id integer primary key autoincrement,
+			user_id text not null,
+			account_id integer not null,
+			currency text not null
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		if err != nil {
+			log.Fatal("There was an error creating the currency tables:", err)
+		}
+	}
+}
+
+func TestCurrencyConversion_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpCurrencyModel(db)
+	udb := database.SQLManager{DB: db}
+	currencyService := CurrencyService{CRDBI: &udb, TDBI: &udb}
+	transactionService := TransactionService{UDBI: &udb, Currencies: &currencyService}
+
+	imported, err := currencyService.ImportRates([]byte("Date,USD,GBP,\n2026-03-02,1.25,0.8,\n2026-03-09,1.5,0.75,\n"))
+	if err != nil {
+		t.Fatal("Error importing the rates:", err)
+	}
+	if imported.Rates != 4 || imported.Currencies != 2 || imported.From != "2026-03-02" || imported.To != "2026-03-09" {
+		t.Errorf("Unexpected import summary %+v", imported)
+	}
+
+	userId := uuid.New()
+	err = currencyService.SetAccountCurrency(context.Background(), &domain.AccountCurrencyData{AccountCurrency: domain.AccountCurrencyDTO{UserId: userId, AccountId: 2, Currency: "USD"}, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error setting the account currency:", err)
+	}
+
+	// a weekday after the first rates, before the second.
+	date := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC).UnixMilli()
+	add := func(accountId int64, currency string, amount float64) domain.TransactionDTO {
+		transaction := domain.TransactionDTOBuilder().Build()
+		transaction.UserId = userId
+		transaction.AccountId = accountId
+		transaction.Currency = currency
+		transaction.Amount = amount
+		transaction.Date = date
+		result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+		if err != nil {
+			t.Fatal("Error adding the transaction:", err)
+		}
+		return result.Transaction
+	}
+
+	fromAccount := add(2, "", 100)
+	if fromAccount.Currency != "USD" || fromAccount.OriginalAmount != 100 || fromAccount.Amount != 80 {
+		t.Errorf("Expected 100 USD saved as 80 EUR, got %+v", fromAccount)
+	}
+	given := add(2, "GBP", 40)
+	if given.Currency != "GBP" || given.OriginalAmount != 40 || given.Amount != 50 {
+		t.Errorf("Expected 40 GBP saved as 50 EUR, got %+v", given)
+	}
+	base := add(3, "", 20)
+	if base.Currency != "EUR" || base.OriginalAmount != 20 || base.Amount != 20 {
+		t.Errorf("Expected 20 EUR kept as is, got %+v", base)
+	}
+
+	// saved before transactions had a currency, so in the old base currency.
+	legacy := domain.TransactionModelBuilder().Build()
+	legacy.UserId = userId
+	legacy.Amount = 10
+	legacy.Date = date
+	legacy.Currency = ""
+	legacy.OriginalAmount = 0
+	if err := udb.AddTransaction(context.Background(), &legacy); err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+
+	// dated before 1970, with a rate of its time.
+	if _, err := currencyService.ImportRates([]byte("Date,USD\n1969-12-01,1.1\n")); err != nil {
+		t.Fatal("Error importing the rates:", err)
+	}
+	old := legacy
+	old.TransactionId = uuid.New()
+	old.Date = time.Date(1969, 12, 15, 12, 0, 0, 0, time.UTC).UnixMilli()
+	if err := udb.AddTransaction(context.Background(), &old); err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+
+	change, err := currencyService.SetBaseCurrency(context.Background(), &domain.BaseCurrencyData{BaseCurrency: domain.BaseCurrencyDTO{UserId: userId, Currency: "USD"}, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error setting the base currency:", err)
+	}
+	if change.Transactions != 5 {
+		t.Errorf("Expected all 5 transactions converted, got %+v", change)
+	}
+
+	expected := map[uuid.UUID]float64{fromAccount.TransactionId: 100, given.TransactionId: 62.5, base.TransactionId: 25, legacy.TransactionId: 12.5, old.TransactionId: 11}
+	for transactionId, amount := range expected {
+		tm, err := udb.GetTransaction(transactionId)
+		if err != nil {
+			t.Fatal("Error retrieving the transaction:", err)
+		}
+		if tm.Amount != amount {
+			t.Errorf("Expected %v in USD, got %+v", amount, tm)
+		}
+	}
+	saved, err := udb.GetTransaction(legacy.TransactionId)
+	if err != nil {
+		t.Fatal("Error retrieving the transaction:", err)
+	}
+	if saved.Currency != "EUR" || saved.OriginalAmount != 10 {
+		t.Errorf("Expected the legacy transaction to keep its original 10 EUR, got %+v", saved)
+	}
+
+	conversion, err := currencyService.Convert(30, "gbp", "usd", time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC).UnixMilli())
+	if err != nil {
+		t.Fatal("Error converting the amount:", err)
+	}
+	if conversion.Converted != 60 || conversion.Rate != 2 {
+		t.Errorf("Expected 30 GBP to be 60 USD with the later rates, got %+v", conversion)
+	}
+}
+
+func TestCurrencyConversion_NoRate_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpCurrencyModel(db)
+	udb := database.SQLManager{DB: db}
+	currencyService := CurrencyService{CRDBI: &udb, TDBI: &udb, DefaultBase: "usd"}
+	transactionService := TransactionService{UDBI: &udb, Currencies: &currencyService}
+
+	_, err := currencyService.ImportRates([]byte("Date,USD\n2026-03-02,1.25\n"))
+	if err != nil {
+		t.Fatal("Error importing the rates:", err)
+	}
+
+	transaction := domain.TransactionDTOBuilder().Build()
+	transaction.Currency = "EUR"
+	transaction.Date = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	_, err = transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+	if !errors.Is(err, ErrNoExchangeRate) {
+		t.Errorf("Expected ErrNoExchangeRate before the first rate, got %v", err)
+	}
+	if _, err := udb.GetTransaction(transaction.TransactionId); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected the transaction not to be saved, got %v", err)
+	}
+}
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
+)
+
+const (
+	digestTopCategories = 5
+	digestUnusualLimit  = 5
+	digestPollInterval  = time.Minute
+)
+
+type DigestServiceInterface interface {
+	SetDigestSchedule(ctx context.Context, scheduleData *domain.DigestScheduleData) (*domain.DigestScheduleDTO, error)
+	RetrieveDigestSchedule(userId uuid.UUID) (*domain.DigestScheduleDTO, error)
+	DeleteDigestSchedule(ctx context.Context, userId uuid.UUID) error
+	PreviewDigest(userId uuid.UUID) (*domain.DigestDTO, error)
+}
+
+type DigestService struct {
+	DGDBI     database.DigestDatabaseInterface
+	UDBI      database.UserDatabaseInterface // looks up the address to email digests to.
+	Reports   ReportServiceInterface
+	Budgets   BudgetServiceInterface  // optional, digests leave out the budget status without it.
+	Anomalies AnomalyServiceInterface // optional, digests leave out unusual transactions without it.
+	Mailer    notify.MailerInterface  // optional, digests can only be previewed without it.
+}
+
+// SetDigestSchedule replaces the users digest schedule. The next digest is due at the first
+// scheduled time from now.
+func (ds *DigestService) SetDigestSchedule(ctx context.Context, scheduleData *domain.DigestScheduleData) (*domain.DigestScheduleDTO, error) {
+	err := scheduleData.ValidateDigestSchedule()
+	if err != nil {
+		return nil, err
+	}
+	schedule := scheduleData.Schedule
+
+	switch {
+	case schedule.Frequency == domain.DIGEST_WEEKLY && schedule.Day > 6:
+		return nil, errors.New("weekly digests need a day of the week from 0 to 6")
+	case schedule.Frequency == domain.DIGEST_MONTHLY && schedule.Day < 1:
+		return nil, errors.New("monthly digests need a day of the month from 1 to 31")
+	}
+
+	dm := convertDigestScheduleDTOToModel(&schedule)
+	now := time.Now()
+	dm.CreatedAt = now.UnixMilli()
+	dm.LastSentAt = 0
+	existing, err := ds.DGDBI.GetDigestSchedule(dm.UserId)
+	if err == nil {
+		dm.LastSentAt = existing.LastSentAt
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	next, err := nextDigestRun(&dm, now)
+	if err != nil {
+		return nil, err
+	}
+	dm.NextRunAt = next.UnixMilli()
+
+	err = ds.DGDBI.SaveDigestSchedule(ctx, &dm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertDigestScheduleModelToDTO(&dm)
+	return &saved, nil
+}
+
+func (ds *DigestService) RetrieveDigestSchedule(userId uuid.UUID) (*domain.DigestScheduleDTO, error) {
+	dm, err := ds.DGDBI.GetDigestSchedule(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := convertDigestScheduleModelToDTO(&dm)
+	return &schedule, nil
+}
+
+func (ds *DigestService) DeleteDigestSchedule(ctx context.Context, userId uuid.UUID) error {
+	return ds.DGDBI.DeleteDigestSchedule(ctx, userId)
+}
+
+// PreviewDigest composes the digest the user would get if it were sent now, weekly in UTC for
+// users without a schedule.
+func (ds *DigestService) PreviewDigest(userId uuid.UUID) (*domain.DigestDTO, error) {
+	schedule := domain.DigestScheduleModel{UserId: userId, Frequency: domain.DIGEST_WEEKLY, Timezone: "UTC"}
+	dm, err := ds.DGDBI.GetDigestSchedule(userId)
+	if err == nil {
+		schedule = dm
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	return ds.composeDigest(&schedule, time.Now())
+}
+
+// SendDueDigests emails the digests due at now and schedules the next ones. A digest covers the
+// week or month up to the day it was due, however late it is sent. A failure for one user is
+// logged and the digest is tried again on the next run. The schedules are updated by the system actor.
+func (ds *DigestService) SendDueDigests(now time.Time) error {
+	if ds.Mailer == nil {
+		return errors.New("no mailer to send digests with")
+	}
+	schedules, err := ds.DGDBI.GetDueDigestSchedules(now.UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for i := range schedules {
+		if err := ds.sendDigest(context.Background(), &schedules[i], now); err != nil {
+			log.Printf("Error sending the digest of user %v: %v\n", schedules[i].UserId, err)
+		}
+	}
+	return nil
+}
+
+// RunDigests sends the due digests every digestPollInterval. It never returns and is meant to
+// run in its own goroutine.
+func (ds *DigestService) RunDigests() {
+	for {
+		if err := ds.SendDueDigests(time.Now()); err != nil {
+			log.Println("Error sending the due digests:", err)
+		}
+		time.Sleep(digestPollInterval)
+	}
+}
+
+func (ds *DigestService) sendDigest(ctx context.Context, schedule *domain.DigestScheduleModel, now time.Time) error {
+	user, err := ds.UDBI.RetrieveUserByUserId(schedule.UserId)
+	if err != nil {
+		return err
+	}
+	digest, err := ds.composeDigest(schedule, time.UnixMilli(schedule.NextRunAt))
+	if err != nil {
+		return err
+	}
+
+	err = ds.Mailer.Send(&notify.Mail{To: user.Email, Subject: digest.Subject, Text: digest.Text, HTML: digest.HTML})
+	if err != nil {
+		return err
+	}
+	next, err := nextDigestRun(schedule, now)
+	if err != nil {
+		return err
+	}
+	return ds.DGDBI.UpdateDigestRun(ctx, schedule.UserId, now.UnixMilli(), next.UnixMilli())
+}
+
+// composeDigest summarizes the whole days of the week or month of the schedule before the day
+// of end, in the schedules timezone.
+func (ds *DigestService) composeDigest(schedule *domain.DigestScheduleModel, end time.Time) (*domain.DigestDTO, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	end = end.In(location)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, location)
+	start := end.AddDate(0, 0, -7)
+	if schedule.Frequency == domain.DIGEST_MONTHLY {
+		start = end.AddDate(0, -1, 0)
+	}
+
+	digest := domain.DigestDTO{
+		UserId:        schedule.UserId,
+		Frequency:     schedule.Frequency,
+		From:          start.UnixMilli(),
+		To:            end.UnixMilli(),
+		TopCategories: []domain.ReportCategoryDTO{},
+		Unusual:       []domain.AnomalyDTO{},
+	}
+	report, err := ds.Reports.RetrieveReport(&domain.ReportQueryDTO{UserId: schedule.UserId, From: digest.From, To: digest.To - 1})
+	if err != nil {
+		return nil, err
+	}
+	digest.Totals = report.Totals
+	for _, category := range report.Categories {
+		if category.Expense > 0 && len(digest.TopCategories) < digestTopCategories {
+			digest.TopCategories = append(digest.TopCategories, category)
+		}
+	}
+
+	if ds.Budgets != nil {
+		month := time.UnixMilli(digest.To - 1).UTC().Format(monthLayout)
+		budgetReport, err := ds.Budgets.RetrieveBudgetReport(schedule.UserId, month)
+		if err != nil {
+			return nil, err
+		}
+		digest.Budget = digestBudget(budgetReport)
+	}
+
+	if ds.Anomalies != nil {
+		anomalies, err := ds.Anomalies.RetrieveAnomalies(schedule.UserId)
+		if err != nil {
+			return nil, err
+		}
+		digest.Unusual = digestUnusual(anomalies, digest.From, digest.To)
+	}
+
+	err = renderDigest(&digest, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+// digestBudget is nil for users without a budget in the month.
+func digestBudget(report *domain.BudgetReportDTO) *domain.DigestBudgetDTO {
+	if report.Budgeted <= 0 {
+		return nil
+	}
+
+	budget := domain.DigestBudgetDTO{
+		Month:      report.Month,
+		Budgeted:   report.Budgeted,
+		Actual:     report.Actual,
+		Remaining:  report.Remaining,
+		OverBudget: []domain.BudgetCategoryDTO{},
+	}
+	for _, category := range report.Categories {
+		if category.Budgeted > 0 && category.Remaining < 0 {
+			budget.OverBudget = append(budget.OverBudget, category)
+		}
+	}
+	sort.SliceStable(budget.OverBudget, func(i, j int) bool {
+		return budget.OverBudget[i].Remaining < budget.OverBudget[j].Remaining
+	})
+	return &budget
+}
+
+// digestUnusual picks the anomalies found from from up to to, the highest scores first.
+func digestUnusual(anomalies []domain.AnomalyDTO, from int64, to int64) []domain.AnomalyDTO {
+	unusual := []domain.AnomalyDTO{}
+	for _, anomaly := range anomalies {
+		if anomaly.CreatedAt >= from && anomaly.CreatedAt < to {
+			unusual = append(unusual, anomaly)
+		}
+	}
+	sort.SliceStable(unusual, func(i, j int) bool {
+		return unusual[i].Score > unusual[j].Score
+	})
+	return unusual[:min(len(unusual), digestUnusualLimit)]
+}
+
+// nextDigestRun is the first scheduled time after after, in the schedules timezone. Monthly
+// digests move to the last day of months shorter than their day.
+func nextDigestRun(schedule *domain.DigestScheduleModel, after time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	local := after.In(location)
+
+	if schedule.Frequency == domain.DIGEST_MONTHLY {
+		for months := 0; ; months++ {
+			first := time.Date(local.Year(), local.Month()+time.Month(months), 1, 0, 0, 0, 0, location)
+			day := dayOfMonth(first.Year(), first.Month(), schedule.Day).Day()
+			run := time.Date(first.Year(), first.Month(), day, schedule.Hour, schedule.Minute, 0, 0, location)
+			if run.After(after) {
+				return run, nil
+			}
+		}
+	}
+
+	days := (schedule.Day - int(local.Weekday()) + 7) % 7
+	for ; ; days += 7 {
+		run := time.Date(local.Year(), local.Month(), local.Day()+days, schedule.Hour, schedule.Minute, 0, 0, location)
+		if run.After(after) {
+			return run, nil
+		}
+	}
+}
+
+var digestFuncs = map[string]any{
+	"money": func(amount float64) string { return fmt.Sprintf("%.2f", amount) },
+	"category": func(categoryId int64) string {
+		if categoryId == 0 {
+			return "Uncategorized"
+		}
+		return fmt.Sprintf("Category %d", categoryId)
+	},
+	"neg": func(amount float64) float64 { return -amount },
+}
+
+const digestText = `Your {{.Period}} spending digest, {{.Range}}
+
+Income:   {{money .Totals.Income}}
+Expenses: {{money .Totals.Expense}}
+Net:      {{money .Totals.Net}} from {{.Totals.Count}} transactions
+{{if .TopCategories}}
+Top categories
+{{range .TopCategories}}  {{category .CategoryId}}: {{money .Expense}} ({{printf "%.0f" .ExpensePercent}}%)
+{{end}}{{end}}{{with .Budget}}
+Budget {{.Month}}: {{money .Actual}} spent of {{money .Budgeted}}, {{money .Remaining}} remaining
+{{range .OverBudget}}  {{category .CategoryId}} is {{money (neg .Remaining)}} over budget
+{{end}}{{end}}{{if .Unusual}}
+Unusual transactions
+{{range .Unusual}}  {{.Message}}
+{{end}}{{end}}`
+
+const digestHTML = `<!DOCTYPE html>
+<html><body style="font-family: sans-serif; color: #222;">
+<h2>Your {{.Period}} spending digest</h2>
+<p>{{.Range}}</p>
+<table cellpadding="4">
+<tr><td>Income</td><td align="right">{{money .Totals.Income}}</td></tr>
+<tr><td>Expenses</td><td align="right">{{money .Totals.Expense}}</td></tr>
+<tr><td><b>Net</b></td><td align="right"><b>{{money .Totals.Net}}</b></td></tr>
+</table>
+<p>{{.Totals.Count}} transactions</p>
+{{if .TopCategories}}<h3>Top categories</h3>
+<table cellpadding="4">
+{{range .TopCategories}}<tr><td>{{category .CategoryId}}</td><td align="right">{{money .Expense}}</td><td align="right">{{printf "%.0f" .ExpensePercent}}%</td></tr>
+{{end}}</table>
+{{end}}{{with .Budget}}<h3>Budget {{.Month}}</h3>
+<p>{{money .Actual}} spent of {{money .Budgeted}}, {{money .Remaining}} remaining.</p>
+{{if .OverBudget}}<ul>
+{{range .OverBudget}}<li style="color: #b00020;">{{category .CategoryId}} is {{money (neg .Remaining)}} over budget</li>
+{{end}}</ul>
+{{end}}{{end}}{{if .Unusual}}<h3>Unusual transactions</h3>
+<ul>
+{{range .Unusual}}<li>{{.Message}}</li>
+{{end}}</ul>
+{{end}}</body></html>
+`
+
+var (
+	digestTextTemplate = template.Must(template.New("digest").Funcs(digestFuncs).Parse(digestText))
+	digestHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest").Funcs(digestFuncs).Parse(digestHTML))
+)
+
+// renderDigest writes the subject and the text and HTML bodies of the digest, the range ends on
+// the day before end.
+func renderDigest(digest *domain.DigestDTO, start time.Time, end time.Time) error {
+	view := struct {
+		*domain.DigestDTO
+		Period string
+		Range  string
+	}{
+		DigestDTO: digest,
+		Period:    "weekly",
+		Range:     start.Format("Jan 2, 2006") + " to " + end.Add(-time.Millisecond).Format("Jan 2, 2006"),
+	}
+	if digest.Frequency == domain.DIGEST_MONTHLY {
+		view.Period = "monthly"
+	}
+
+	var text, html bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, view); err != nil {
+		return err
+	}
+	if err := digestHTMLTemplate.Execute(&html, view); err != nil {
+		return err
+	}
+	digest.Subject = fmt.Sprintf("Your %s spending digest, %s", view.Period, view.Range)
+	digest.Text = text.String()
+	digest.HTML = html.String()
+	return nil
+}
+
+func convertDigestScheduleDTOToModel(from *domain.DigestScheduleDTO) domain.DigestScheduleModel {
+	return domain.DigestScheduleModel{
+		UserId:     from.UserId,
+		Frequency:  from.Frequency,
+		Day:        from.Day,
+		Hour:       from.Hour,
+		Minute:     from.Minute,
+		Timezone:   from.Timezone,
+		NextRunAt:  from.NextRunAt,
+		LastSentAt: from.LastSentAt,
+		CreatedAt:  from.CreatedAt,
+	}
+}
+
+func convertDigestScheduleModelToDTO(from *domain.DigestScheduleModel) domain.DigestScheduleDTO {
+	return domain.DigestScheduleDTO{
+		UserId:     from.UserId,
+		Frequency:  from.Frequency,
+		Day:        from.Day,
+		Hour:       from.Hour,
+		Minute:     from.Minute,
+		Timezone:   from.Timezone,
+		NextRunAt:  from.NextRunAt,
+		LastSentAt: from.LastSentAt,
+		CreatedAt:  from.CreatedAt,
+	}
+}
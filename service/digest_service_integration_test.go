@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
+)
+
+func setUpDigestModel(db *sql.DB) {
+	stmt := `create table digest_schedule (
+		id integer primary key autoincrement,
+		user_id text not null,
+		frequency integer not null,
+		day integer not null,
+		hour integer not null,
+		minute integer not null,
+		timezone text not null,
+		next_run_at integer not null,
+		last_sent_at integer not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating the digest schedule table:", err)
+	}
+}
+
+type recordingMailer struct {
+	sent []notify.Mail
+}
+
+func (m *recordingMailer) Send(mail *notify.Mail) error {
+	m.sent = append(m.sent, *mail)
+	return nil
+}
+
+func TestDigests_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpBudgetModel(db)
+	setUpAnomalyModel(db)
+	setUpDigestModel(db)
+	udb := database.SQLManager{DB: db}
+	mailer := &recordingMailer{}
+	budgetService := BudgetService{BDBI: &udb}
+	digestService := DigestService{
+		DGDBI:     &udb,
+		UDBI:      &StubDatabase{},
+		Reports:   &ReportService{TDBI: &udb},
+		Budgets:   &budgetService,
+		Anomalies: &AnomalyService{ANDBI: &udb, TDBI: &udb},
+		Mailer:    mailer,
+	}
+
+	userId := uuid.New()
+	schedule := domain.DigestScheduleDTO{UserId: userId, Frequency: domain.DIGEST_WEEKLY, Day: 1, Hour: 8, Timezone: "Europe/Berlin"}
+	saved, err := digestService.SetDigestSchedule(context.Background(), &domain.DigestScheduleData{Schedule: schedule, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error setting the digest schedule:", err)
+	}
+	if next := time.UnixMilli(saved.NextRunAt); !next.After(time.Now()) || next.In(mustLoadLocation(t, "Europe/Berlin")).Weekday() != time.Monday {
+		t.Errorf("Unexpected next run %v", next)
+	}
+
+	// due Monday June 8th at 8:00 in Berlin, covering the week before that day.
+	due := time.Date(2026, 6, 8, 6, 0, 0, 0, time.UTC)
+	if err := udb.UpdateDigestRun(context.Background(), userId, 0, due.UnixMilli()); err != nil {
+		t.Fatal("Error moving the digest run:", err)
+	}
+
+	budget := domain.BudgetDTO{UserId: userId, CategoryId: 3, Month: "2026-06", Amount: 100}
+	if _, err := budgetService.SetBudget(context.Background(), &domain.BudgetData{Budget: budget, Validator: validator.New()}); err != nil {
+		t.Fatal("Error setting the budget:", err)
+	}
+	add := func(date time.Time, categoryId int64, amount float64, transactionType domain.TransactionType) {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.CategoryId = categoryId
+		tm.Date = date.UnixMilli()
+		tm.Amount = amount
+		tm.Type = transactionType
+		tm.Status = domain.CLEARED
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+	add(time.Date(2026, 6, 2, 12, 0, 0, 0, time.UTC), 3, 140, domain.EXPENSE)
+	add(time.Date(2026, 6, 3, 12, 0, 0, 0, time.UTC), 5, 60, domain.EXPENSE)
+	add(time.Date(2026, 6, 5, 12, 0, 0, 0, time.UTC), 0, 2000, domain.INCOME)
+	add(time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC), 5, 999, domain.EXPENSE) // the week before
+	add(time.Date(2026, 6, 7, 23, 0, 0, 0, time.UTC), 5, 999, domain.EXPENSE)  // Monday in Berlin, only part of the budget
+
+	anomaly := domain.AnomalyModel{AnomalyId: uuid.New(), UserId: userId, Kind: domain.UNUSUAL_CATEGORY_AMOUNT, CategoryId: 3, Amount: 140, Score: 4, Message: `"<Hardware store>" is 140.00, usually around 30.00.`, CreatedAt: time.Date(2026, 6, 3, 0, 5, 0, 0, time.UTC).UnixMilli()}
+	if _, err := udb.AddAnomaly(context.Background(), &anomaly); err != nil {
+		t.Fatal("Error adding the anomaly:", err)
+	}
+
+	// not due yet.
+	if err := digestService.SendDueDigests(due.Add(-time.Minute)); err != nil {
+		t.Fatal("Error sending the due digests:", err)
+	}
+	if len(mailer.sent) != 0 {
+		t.Fatalf("Expected no digest before it is due, got %d", len(mailer.sent))
+	}
+
+	sentAt := due.Add(3 * time.Minute)
+	if err := digestService.SendDueDigests(sentAt); err != nil {
+		t.Fatal("Error sending the due digests:", err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("Expected one digest, got %d", len(mailer.sent))
+	}
+	mail := mailer.sent[0]
+	if mail.To == "" || mail.Subject != "Your weekly spending digest, Jun 1, 2026 to Jun 7, 2026" {
+		t.Errorf("Unexpected mail %q to %q", mail.Subject, mail.To)
+	}
+	for _, want := range []string{
+		"Income:   2000.00\nExpenses: 200.00\nNet:      1800.00 from 3 transactions\n",
+		"  Category 3: 140.00 (70%)\n  Category 5: 60.00 (30%)\n",
+		"Budget 2026-06: 1199.00 spent of 100.00, -1099.00 remaining\n  Category 3 is 40.00 over budget\n",
+		"Unusual transactions\n  \"<Hardware store>\" is 140.00",
+	} {
+		if !strings.Contains(mail.Text, want) {
+			t.Errorf("Text is missing %q, got:\n%s", want, mail.Text)
+		}
+	}
+	if !strings.Contains(mail.HTML, "&#34;&lt;Hardware store&gt;&#34;") || strings.Contains(mail.HTML, "<Hardware store>") {
+		t.Errorf("Expected the transaction description escaped in the HTML, got:\n%s", mail.HTML)
+	}
+
+	schedules, err := udb.GetDueDigestSchedules(sentAt.UnixMilli())
+	if err != nil {
+		t.Fatal("Error retrieving the due digests:", err)
+	}
+	if len(schedules) != 0 {
+		t.Error("Expected the digest to be rescheduled")
+	}
+	rescheduled, err := digestService.RetrieveDigestSchedule(userId)
+	if err != nil {
+		t.Fatal("Error retrieving the digest schedule:", err)
+	}
+	if rescheduled.LastSentAt != sentAt.UnixMilli() || rescheduled.NextRunAt != due.AddDate(0, 0, 7).UnixMilli() {
+		t.Errorf("Unexpected schedule after sending %+v", rescheduled)
+	}
+
+	if err := digestService.DeleteDigestSchedule(context.Background(), userId); err != nil {
+		t.Fatal("Error deleting the digest schedule:", err)
+	}
+	if _, err := digestService.RetrieveDigestSchedule(userId); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows after deleting, got %v", err)
+	}
+}
+
+func TestSetDigestSchedule_Validation(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpDigestModel(db)
+	udb := database.SQLManager{DB: db}
+	digestService := DigestService{DGDBI: &udb}
+
+	tests := []struct {
+		name     string
+		schedule domain.DigestScheduleDTO
+	}{
+		{name: "Unknown timezone", schedule: domain.DigestScheduleDTO{UserId: uuid.New(), Day: 1, Timezone: "Mars/Olympus"}},
+		{name: "Weekday out of range", schedule: domain.DigestScheduleDTO{UserId: uuid.New(), Day: 7, Timezone: "UTC"}},
+		{name: "Monthly without a day", schedule: domain.DigestScheduleDTO{UserId: uuid.New(), Frequency: domain.DIGEST_MONTHLY, Timezone: "UTC"}},
+		{name: "Hour out of range", schedule: domain.DigestScheduleDTO{UserId: uuid.New(), Day: 1, Hour: 24, Timezone: "UTC"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := digestService.SetDigestSchedule(context.Background(), &domain.DigestScheduleData{Schedule: tt.schedule, Validator: validator.New()})
+			if err == nil {
+				t.Error("Expected the schedule to be rejected")
+			}
+		})
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal("Error loading the timezone:", err)
+	}
+	return location
+}
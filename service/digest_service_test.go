@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestNextDigestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule domain.DigestScheduleModel
+		after    time.Time
+		want     time.Time
+	}{
+		{
+			name:     "Weekly later today",
+			schedule: domain.DigestScheduleModel{Frequency: domain.DIGEST_WEEKLY, Day: 1, Hour: 8, Timezone: "UTC"},
+			after:    time.Date(2026, 6, 1, 7, 0, 0, 0, time.UTC), // a Monday
+			want:     time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Weekly just sent",
+			schedule: domain.DigestScheduleModel{Frequency: domain.DIGEST_WEEKLY, Day: 1, Hour: 8, Timezone: "UTC"},
+			after:    time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 6, 8, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Weekly in the timezone",
+			schedule: domain.DigestScheduleModel{Frequency: domain.DIGEST_WEEKLY, Day: 0, Hour: 18, Minute: 30, Timezone: "America/New_York"},
+			after:    time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC), // still Sunday evening in New York
+			want:     time.Date(2026, 6, 7, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Weekly across daylight saving",
+			schedule: domain.DigestScheduleModel{Frequency: domain.DIGEST_WEEKLY, Day: 1, Hour: 8, Timezone: "America/New_York"},
+			after:    time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Monthly on a short month",
+			schedule: domain.DigestScheduleModel{Frequency: domain.DIGEST_MONTHLY, Day: 31, Hour: 7, Timezone: "UTC"},
+			after:    time.Date(2026, 1, 31, 7, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 2, 28, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Monthly into the next year",
+			schedule: domain.DigestScheduleModel{Frequency: domain.DIGEST_MONTHLY, Day: 1, Timezone: "Europe/Berlin"},
+			after:    time.Date(2026, 12, 5, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextDigestRun(&tt.schedule, tt.after)
+			if err != nil {
+				t.Fatal("Error scheduling the digest:", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got.UTC())
+			}
+		})
+	}
+}
+
+func TestDigestBudget(t *testing.T) {
+	if budget := digestBudget(&domain.BudgetReportDTO{Month: "2026-05", Actual: 40}); budget != nil {
+		t.Errorf("Expected no budget status without budgets, got %+v", budget)
+	}
+
+	report := domain.BudgetReportDTO{
+		Month:    "2026-05",
+		Budgeted: 500,
+		Actual:   620,
+		Categories: []domain.BudgetCategoryDTO{
+			{CategoryId: 1, Budgeted: 200, Actual: 230, Remaining: -30},
+			{CategoryId: 2, Budgeted: 300, Actual: 290, Remaining: 10},
+			{CategoryId: 3, Budgeted: 0, Actual: 100, Remaining: -100}, // not budgeted
+			{CategoryId: 4, Budgeted: 50, Actual: 120, Remaining: -70},
+		},
+	}
+	budget := digestBudget(&report)
+	if budget == nil || len(budget.OverBudget) != 2 || budget.OverBudget[0].CategoryId != 4 || budget.OverBudget[1].CategoryId != 1 {
+		t.Errorf("Unexpected budget status %+v", budget)
+	}
+}
+
+func TestDigestUnusual(t *testing.T) {
+	var anomalies []domain.AnomalyDTO
+	for i := 0; i < 8; i++ {
+		anomalies = append(anomalies, domain.AnomalyDTO{AnomalyId: uuid.New(), Score: float64(i), CreatedAt: int64(100 + i*10)})
+	}
+
+	unusual := digestUnusual(anomalies, 110, 180)
+	if len(unusual) != digestUnusualLimit {
+		t.Fatalf("Expected %d unusual transactions, got %d", digestUnusualLimit, len(unusual))
+	}
+	// created at 110 to 170, the highest scores first.
+	for i, want := range []float64{7, 6, 5, 4, 3} {
+		if unusual[i].Score != want {
+			t.Errorf("Unusual %d: expected score %v, got %v", i, want, unusual[i].Score)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+const (
+	dayMillis = int64(24 * time.Hour / time.Millisecond)
+	// Transactions further apart than this are never considered duplicates.
+	duplicateWindowDays = 3
+	// Pairs scoring at or above this are flagged as suspected duplicates.
+	duplicateThreshold = 0.6
+	// Descriptions at least this similar count as the same merchant.
+	minDuplicateSimilarity = 0.5
+)
+
+type DuplicateServiceInterface interface {
+	CheckTransaction(ctx context.Context, tm *domain.TransactionModel) ([]domain.DuplicateDTO, error)
+	RetrieveSuspectedDuplicates(userId uuid.UUID) ([]domain.DuplicateDTO, error)
+	MergeDuplicate(ctx context.Context, data *domain.DuplicateResolutionData) error
+	DismissDuplicate(ctx context.Context, data *domain.DuplicateResolutionData) error
+}
+
+type DuplicateService struct {
+	DDBI         database.DuplicateDatabaseInterface
+	TDBI         database.TransactionDatabaseInterface
+	Transactions TransactionServiceInterface // deletes the merged transaction, with its attachments and events.
+}
+
+// CheckTransaction compares a saved transaction against the users other transactions
+// close to its date and records every pair that scores as a likely duplicate.
+func (d *DuplicateService) CheckTransaction(ctx context.Context, tm *domain.TransactionModel) ([]domain.DuplicateDTO, error) {
+	window := duplicateWindowDays * dayMillis
+	candidates, err := d.TDBI.GetTransactionsByUserId(tm.UserId, tm.Date-window, tm.Date+window)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := []domain.DuplicateDTO{}
+	for _, candidate := range candidates {
+		if candidate.TransactionId == tm.TransactionId {
+			continue
+		}
+		score, reason := scoreDuplicate(tm, &candidate)
+		if score < duplicateThreshold {
+			continue
+		}
+
+		dm := domain.DuplicateModel{
+			DuplicateId:   uuid.New(),
+			UserId:        tm.UserId,
+			TransactionId: tm.TransactionId,
+			CandidateId:   candidate.TransactionId,
+			Score:         score,
+			Reason:        reason,
+			Status:        domain.SUSPECTED,
+			CreatedAt:     time.Now().UnixMilli(),
+		}
+		err = d.DDBI.AddDuplicate(ctx, &dm)
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, convertDuplicateModelToDTO(&dm))
+	}
+	return duplicates, nil
+}
+
+func (d *DuplicateService) RetrieveSuspectedDuplicates(userId uuid.UUID) ([]domain.DuplicateDTO, error) {
+	models, err := d.DDBI.GetDuplicatesByStatus(userId, domain.SUSPECTED)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make([]domain.DuplicateDTO, 0, len(models))
+	for _, dm := range models {
+		duplicates = append(duplicates, convertDuplicateModelToDTO(&dm))
+	}
+	return duplicates, nil
+}
+
+// MergeDuplicate keeps one transaction of the pair and deletes the other, like any other delete
+// of a transaction. The delete resolves the pair, and the other pairs of the deleted transaction.
+// Without a KeepTransactionId the original (candidate) transaction is kept.
+func (d *DuplicateService) MergeDuplicate(ctx context.Context, data *domain.DuplicateResolutionData) error {
+	dm, err := d.suspectedDuplicate(data)
+	if err != nil {
+		return err
+	}
+
+	keep := data.Resolution.KeepTransactionId
+	if keep == uuid.Nil {
+		keep = dm.CandidateId
+	}
+
+	var remove uuid.UUID
+	switch keep {
+	case dm.CandidateId:
+		remove = dm.TransactionId
+	case dm.TransactionId:
+		remove = dm.CandidateId
+	default:
+		return fmt.Errorf("transaction %v is not part of duplicate %v", keep, dm.DuplicateId)
+	}
+
+	return d.Transactions.DeleteTransaction(ctx, remove)
+}
+
+// DismissDuplicate marks the pair as two genuine transactions.
+func (d *DuplicateService) DismissDuplicate(ctx context.Context, data *domain.DuplicateResolutionData) error {
+	dm, err := d.suspectedDuplicate(data)
+	if err != nil {
+		return err
+	}
+	return d.DDBI.UpdateDuplicateStatus(ctx, dm.DuplicateId, domain.DISMISSED)
+}
+
+func (d *DuplicateService) suspectedDuplicate(data *domain.DuplicateResolutionData) (domain.DuplicateModel, error) {
+	err := data.ValidateDuplicateResolution()
+	if err != nil {
+		return domain.DuplicateModel{}, err
+	}
+
+	dm, err := d.DDBI.GetDuplicate(data.Resolution.DuplicateId)
+	if err != nil {
+		return dm, err
+	}
+	if dm.Status != domain.SUSPECTED {
+		return dm, errors.New("duplicate has already been resolved")
+	}
+	return dm, nil
+}
+
+// scoreDuplicate returns a score between 0 and 1 for how likely a and b are the same purchase,
+// along with a human readable reason. Amount and type must match for a non zero score, and the
+// pair needs a similar description or the same payee, as the same amount on the same day is common
+// for unrelated purchases. Different payees are different purchases.
+func scoreDuplicate(a, b *domain.TransactionModel) (float64, string) {
+	if a.Type != b.Type || a.Status == domain.CANCELLED || b.Status == domain.CANCELLED {
+		return 0, ""
+	}
+	payeesKnown := a.PayeeId != uuid.Nil && b.PayeeId != uuid.Nil
+	if payeesKnown && a.PayeeId != b.PayeeId {
+		return 0, ""
+	}
+
+	var score float64
+	var reasons []string
+
+	diff := math.Abs(a.Amount - b.Amount)
+	largest := math.Max(math.Abs(a.Amount), math.Abs(b.Amount))
+	switch {
+	case diff == 0:
+		score += 0.4
+		reasons = append(reasons, "same amount")
+	case diff <= largest*0.01:
+		score += 0.2
+		reasons = append(reasons, "similar amount")
+	default:
+		return 0, ""
+	}
+
+	days := absInt64(a.Date-b.Date) / dayMillis
+	if days > duplicateWindowDays {
+		return 0, ""
+	}
+	score += 0.25 * (1 - float64(days)/duplicateWindowDays)
+	switch days {
+	case 0:
+		reasons = append(reasons, "same day")
+	case 1:
+		reasons = append(reasons, "1 day apart")
+	default:
+		reasons = append(reasons, fmt.Sprintf("%d days apart", days))
+	}
+
+	similarity := descriptionSimilarity(a.Description, b.Description)
+	score += 0.25 * similarity
+	switch {
+	case similarity >= minDuplicateSimilarity:
+		reasons = append(reasons, fmt.Sprintf("similar description (%.2f)", similarity))
+	case payeesKnown:
+		reasons = append(reasons, "same payee")
+	default:
+		return 0, ""
+	}
+
+	switch {
+	case a.AccountId == 0 || b.AccountId == 0:
+		score += 0.05
+	case a.AccountId == b.AccountId:
+		score += 0.1
+		reasons = append(reasons, "same account")
+	default:
+		reasons = append(reasons, "different account")
+	}
+
+	return math.Round(score*100) / 100, strings.Join(reasons, ", ")
+}
+
+// normalizeDescription lower cases a description and drops punctuation and
+// tokens containing digits, which are usually reference numbers.
+func normalizeDescription(description string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := []string{}
+	for _, field := range fields {
+		if strings.IndexFunc(field, unicode.IsDigit) >= 0 {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// descriptionSimilarity is the Jaccard index of the normalized description tokens.
+func descriptionSimilarity(a, b string) float64 {
+	tokensA := normalizeDescription(a)
+	tokensB := normalizeDescription(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(tokensA))
+	for _, token := range tokensA {
+		set[token] = true
+	}
+
+	intersection := 0
+	union := len(set)
+	seen := make(map[string]bool, len(tokensB))
+	for _, token := range tokensB {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		if set[token] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+	return float64(intersection) / float64(union)
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func convertDuplicateModelToDTO(from *domain.DuplicateModel) domain.DuplicateDTO {
+	return domain.DuplicateDTO{
+		DuplicateId:   from.DuplicateId,
+		UserId:        from.UserId,
+		TransactionId: from.TransactionId,
+		CandidateId:   from.CandidateId,
+		Score:         from.Score,
+		Reason:        from.Reason,
+		Status:        from.Status,
+		CreatedAt:     from.CreatedAt,
+	}
+}
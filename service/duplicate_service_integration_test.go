@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestImportFlagsAndMergesDuplicates_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	udb := database.SQLManager{DB: db}
+	duplicateService := DuplicateService{DDBI: &udb, TDBI: &udb}
+	transactionService := TransactionService{UDBI: &udb, Duplicates: &duplicateService}
+	duplicateService.Transactions = &transactionService
+
+	manual := domain.TransactionDTOBuilder().WithDescription("Amazon Marketplace").Build()
+	manual.Amount = 42.17
+	manual.Date = 20 * dayMillis
+	manual.Type = domain.EXPENSE
+	manual.Status = domain.CLEARED
+
+	statement := manual
+	statement.TransactionId = uuid.New()
+	statement.Date = manual.Date + dayMillis
+	statement.Description = "AMAZON.COM*MK12 Marketplace"
+
+	_, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: manual, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding the manual transaction:", err)
+	}
+
+	results, err := transactionService.ImportTransactions(context.Background(), &domain.TransactionImportData{Transactions: []domain.TransactionDTO{statement}, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error importing the statement:", err)
+	}
+	if len(results) != 1 || len(results[0].Duplicates) != 1 {
+		t.Fatalf("Expected the imported transaction to be flagged, got %v", results)
+	}
+
+	suspected, err := duplicateService.RetrieveSuspectedDuplicates(manual.UserId)
+	if err != nil || len(suspected) != 1 {
+		t.Fatalf("Expected one suspected duplicate, got %v, err: %v", suspected, err)
+	}
+
+	// a second copy of the statement is flagged against both.
+	copied := statement
+	copied.TransactionId = uuid.New()
+	results, err = transactionService.ImportTransactions(context.Background(), &domain.TransactionImportData{Transactions: []domain.TransactionDTO{copied}, Validator: validator.New()})
+	if err != nil || len(results[0].Duplicates) != 2 {
+		t.Fatalf("Expected the copy to be flagged twice, got %v, err: %v", results, err)
+	}
+
+	resolution := domain.DuplicateResolutionDTO{DuplicateId: suspected[0].DuplicateId}
+	err = duplicateService.MergeDuplicate(context.Background(), &domain.DuplicateResolutionData{Resolution: resolution, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error merging the duplicate:", err)
+	}
+
+	_, err = transactionService.GetTransaction(statement.TransactionId)
+	if err != sql.ErrNoRows {
+		t.Fatalf("Expected the imported transaction to be removed, got %v", err)
+	}
+	_, err = transactionService.GetTransaction(manual.TransactionId)
+	if err != nil {
+		t.Fatal("Expected the manual transaction to be kept:", err)
+	}
+
+	// the pair of the copy with the removed transaction is resolved with it.
+	suspected, err = duplicateService.RetrieveSuspectedDuplicates(manual.UserId)
+	if err != nil || len(suspected) != 1 || suspected[0].TransactionId != copied.TransactionId || suspected[0].CandidateId != manual.TransactionId {
+		t.Fatalf("Expected only the pair of the copy with the kept transaction, got %v, err: %v", suspected, err)
+	}
+}
+
+func setUpDuplicateModel(db *sql.DB) {
+	stmt := `create table duplicate_model (
+		id integer primary key autoincrement,
+		duplicate_id text not null,
+		user_id text not null,
+		transaction_id text not null,
+		candidate_id text not null,
+		score float not null,
+		reason text not null,
+		status integer not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating duplicate_model table:", err)
+	}
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// Keeps transactions and duplicates in memory so the merge results can be checked.
+type StubDuplicateDatabase struct {
+	StubDatabase
+	transactions []domain.TransactionModel
+	duplicates   map[uuid.UUID]domain.DuplicateModel
+}
+
+func newStubDuplicateDatabase(transactions ...domain.TransactionModel) *StubDuplicateDatabase {
+	return &StubDuplicateDatabase{transactions: transactions, duplicates: map[uuid.UUID]domain.DuplicateModel{}}
+}
+
+func (m *StubDuplicateDatabase) GetTransactionsByUserId(userId uuid.UUID, from int64, to int64) ([]domain.TransactionModel, error) {
+	var found []domain.TransactionModel
+	for _, tm := range m.transactions {
+		if tm.UserId == userId && tm.Date >= from && tm.Date <= to {
+			found = append(found, tm)
+		}
+	}
+	return found, nil
+}
+
+func (m *StubDuplicateDatabase) DeleteTransaction(ctx context.Context, transactionId uuid.UUID, events ...domain.OutboxEventModel) error {
+	for i, tm := range m.transactions {
+		if tm.TransactionId == transactionId {
+			m.transactions = append(m.transactions[:i], m.transactions[i+1:]...)
+			break
+		}
+	}
+	for id, dm := range m.duplicates {
+		if dm.Status == domain.SUSPECTED && (dm.TransactionId == transactionId || dm.CandidateId == transactionId) {
+			dm.Status = domain.MERGED
+			m.duplicates[id] = dm
+		}
+	}
+	return nil
+}
+
+func (m *StubDuplicateDatabase) AddDuplicate(ctx context.Context, dm *domain.DuplicateModel) error {
+	m.duplicates[dm.DuplicateId] = *dm
+	return nil
+}
+
+func (m *StubDuplicateDatabase) GetDuplicate(duplicateId uuid.UUID) (domain.DuplicateModel, error) {
+	return m.duplicates[duplicateId], nil
+}
+
+func (m *StubDuplicateDatabase) GetDuplicatesByStatus(userId uuid.UUID, status domain.DuplicateStatus) ([]domain.DuplicateModel, error) {
+	var found []domain.DuplicateModel
+	for _, dm := range m.duplicates {
+		if dm.UserId == userId && dm.Status == status {
+			found = append(found, dm)
+		}
+	}
+	return found, nil
+}
+
+func (m *StubDuplicateDatabase) UpdateDuplicateStatus(ctx context.Context, duplicateId uuid.UUID, status domain.DuplicateStatus) error {
+	dm := m.duplicates[duplicateId]
+	dm.Status = status
+	m.duplicates[duplicateId] = dm
+	return nil
+}
+
+func duplicatePair() (domain.TransactionModel, domain.TransactionModel) {
+	original := domain.TransactionModelBuilder().Build()
+	original.Amount = 42.17
+	original.Date = 20 * dayMillis
+	original.Description = "AMAZON.COM*MK12 Marketplace"
+	original.Type = domain.EXPENSE
+	original.Status = domain.CLEARED
+	original.AccountId = 1
+
+	imported := original
+	imported.TransactionId = uuid.New()
+	imported.Date = original.Date + dayMillis
+	imported.Description = "Amazon Marketplace 2K3L"
+	return original, imported
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	original, imported := duplicatePair()
+
+	differentAmount := imported
+	differentAmount.Amount = 50
+
+	differentType := imported
+	differentType.Type = domain.INCOME
+
+	tooFar := imported
+	tooFar.Date = original.Date + 5*dayMillis
+
+	differentAccount := imported
+	differentAccount.AccountId = 2
+	differentAccount.Description = "Card purchase"
+	differentAccount.PayeeId = uuid.Nil
+
+	samePayee := differentAccount
+	samePayee.PayeeId = original.PayeeId
+
+	differentPayee := imported
+	differentPayee.Date = original.Date
+	differentPayee.PayeeId = uuid.New()
+
+	tests := []struct {
+		name       string
+		candidate  domain.TransactionModel
+		wantScore  float64
+		wantReason string
+	}{
+		{
+			name:       "Likely duplicate",
+			candidate:  imported,
+			wantScore:  0.83,
+			wantReason: "same amount, 1 day apart, similar description (0.67), same account",
+		},
+		{
+			name:      "Different amount",
+			candidate: differentAmount,
+			wantScore: 0,
+		},
+		{
+			name:      "Different type",
+			candidate: differentType,
+			wantScore: 0,
+		},
+		{
+			name:      "Outside the window",
+			candidate: tooFar,
+			wantScore: 0,
+		},
+		{
+			name:      "Different description",
+			candidate: differentAccount,
+			wantScore: 0,
+		},
+		{
+			name:       "Different description, same payee",
+			candidate:  samePayee,
+			wantScore:  0.57,
+			wantReason: "same amount, 1 day apart, same payee, different account",
+		},
+		{
+			name:      "Same day, different payee",
+			candidate: differentPayee,
+			wantScore: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			score, reason := scoreDuplicate(&original, &test.candidate)
+			if score != test.wantScore {
+				t.Errorf("Wrong score, got %v, want %v", score, test.wantScore)
+			}
+			if reason != test.wantReason {
+				t.Errorf("Wrong reason, got %q, want %q", reason, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestNormalizeDescription(t *testing.T) {
+	got := normalizeDescription("AMZN Mktp US*2K3L")
+	want := []string{"amzn", "mktp", "us"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong tokens, got %v, want %v", got, want)
+	}
+}
+
+func TestCheckTransaction(t *testing.T) {
+	original, imported := duplicatePair()
+	unrelated := domain.TransactionModelBuilder().Build()
+	unrelated.UserId = original.UserId
+	unrelated.Date = original.Date
+	unrelated.Amount = 999
+
+	stubDB := newStubDuplicateDatabase(original, unrelated, imported)
+	duplicateService := DuplicateService{DDBI: stubDB, TDBI: stubDB}
+
+	duplicates, err := duplicateService.CheckTransaction(context.Background(), &imported)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(duplicates) != 1 {
+		t.Fatalf("Expected one duplicate, got %v", duplicates)
+	}
+	if duplicates[0].TransactionId != imported.TransactionId || duplicates[0].CandidateId != original.TransactionId {
+		t.Fatalf("Wrong pair flagged, got %v", duplicates[0])
+	}
+	if len(stubDB.duplicates) != 1 {
+		t.Fatalf("Expected the duplicate to be saved, got %v", stubDB.duplicates)
+	}
+}
+
+func TestResolveDuplicate(t *testing.T) {
+	tests := []struct {
+		name       string
+		merge      bool
+		keepNew    bool
+		wantStatus domain.DuplicateStatus
+		wantCount  int
+	}{
+		{name: "Merge keeps original", merge: true, wantStatus: domain.MERGED, wantCount: 1},
+		{name: "Merge keeps new", merge: true, keepNew: true, wantStatus: domain.MERGED, wantCount: 1},
+		{name: "Dismiss keeps both", merge: false, wantStatus: domain.DISMISSED, wantCount: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original, imported := duplicatePair()
+			stubDB := newStubDuplicateDatabase(original, imported)
+			duplicateService := DuplicateService{DDBI: stubDB, TDBI: stubDB, Transactions: &TransactionService{UDBI: stubDB}}
+
+			duplicates, err := duplicateService.CheckTransaction(context.Background(), &imported)
+			if err != nil || len(duplicates) != 1 {
+				t.Fatalf("Expected one duplicate, got %v, err: %v", duplicates, err)
+			}
+
+			resolution := domain.DuplicateResolutionDTO{DuplicateId: duplicates[0].DuplicateId}
+			if test.keepNew {
+				resolution.KeepTransactionId = imported.TransactionId
+			}
+			data := domain.DuplicateResolutionData{Resolution: resolution, Validator: validator.New()}
+			if test.merge {
+				err = duplicateService.MergeDuplicate(context.Background(), &data)
+			} else {
+				err = duplicateService.DismissDuplicate(context.Background(), &data)
+			}
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+
+			if status := stubDB.duplicates[resolution.DuplicateId].Status; status != test.wantStatus {
+				t.Errorf("Wrong status, got %v, want %v", status, test.wantStatus)
+			}
+			if len(stubDB.transactions) != test.wantCount {
+				t.Fatalf("Wrong number of transactions left, got %d, want %d", len(stubDB.transactions), test.wantCount)
+			}
+			if test.merge {
+				kept := original.TransactionId
+				if test.keepNew {
+					kept = imported.TransactionId
+				}
+				if stubDB.transactions[0].TransactionId != kept {
+					t.Errorf("Wrong transaction kept, got %v, want %v", stubDB.transactions[0].TransactionId, kept)
+				}
+			}
+
+			err = duplicateService.DismissDuplicate(context.Background(), &data)
+			if err == nil {
+				t.Error("Expected an error resolving the duplicate twice")
+			}
+		})
+	}
+}
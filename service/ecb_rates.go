@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// MaxRateFileSize is the largest exchange rate file accepted, the full ECB history is well below it.
+const MaxRateFileSize = 32 << 20
+
+// ecbDailyDateLayout is the date format of the ECB daily CSV, the history uses domain.RateDateLayout.
+const ecbDailyDateLayout = "02 January 2006"
+
+// ecbEnvelope is the ECB reference rates XML, one Cube per day holding one Cube per currency.
+// The namespaces are left out so both the daily and the history files match.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// parseECBRates reads euro reference rates in the ECB XML or CSV format, told apart by the
+// first character. Both the daily and the history files are accepted.
+func parseECBRates(data []byte) ([]domain.ExchangeRateModel, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	var rates []domain.ExchangeRateModel
+	var err error
+	if bytes.HasPrefix(data, []byte("<")) {
+		rates, err = parseECBXML(data)
+	} else {
+		rates, err = parseECBCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, errors.New("the file holds no exchange rates")
+	}
+	return rates, nil
+}
+
+func parseECBXML(data []byte) ([]domain.ExchangeRateModel, error) {
+	var envelope ecbEnvelope
+	err := xml.Unmarshal(data, &envelope)
+	if err != nil {
+		return nil, fmt.Errorf("reading the exchange rate XML: %w", err)
+	}
+
+	var rates []domain.ExchangeRateModel
+	for _, day := range envelope.Days {
+		date, err := time.Parse(domain.RateDateLayout, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("reading the exchange rate date %q: %w", day.Time, err)
+		}
+		for _, rate := range day.Rates {
+			rm, err := ecbRate(rate.Currency, date, rate.Rate)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, rm)
+		}
+	}
+	return rates, nil
+}
+
+// parseECBCSV reads a header of Date followed by the currency codes and a row per day.
+// Rates of currencies not quoted that day are N/A or empty, and rows may end with a comma.
+func parseECBCSV(data []byte) ([]domain.ExchangeRateModel, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading the exchange rate CSV header: %w", err)
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "date") {
+		return nil, errors.New("the exchange rate CSV does not start with a Date column")
+	}
+
+	var rates []domain.ExchangeRateModel
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading the exchange rate CSV: %w", err)
+		}
+
+		day := strings.TrimSpace(record[0])
+		date, err := time.Parse(domain.RateDateLayout, day)
+		if err != nil {
+			date, err = time.Parse(ecbDailyDateLayout, day)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading the exchange rate date %q: %w", day, err)
+		}
+		for i := 1; i < len(record) && i < len(header); i++ {
+			currency, value := strings.TrimSpace(header[i]), strings.TrimSpace(record[i])
+			if currency == "" || value == "" || value == "N/A" {
+				continue
+			}
+			rm, err := ecbRate(currency, date, value)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, rm)
+		}
+	}
+	return rates, nil
+}
+
+func ecbRate(currency string, date time.Time, value string) (domain.ExchangeRateModel, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return domain.ExchangeRateModel{}, fmt.Errorf("%q is not a currency code", currency)
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate <= 0 {
+		return domain.ExchangeRateModel{}, fmt.Errorf("the %s rate on %s is not a positive number: %q", currency, date.Format(domain.RateDateLayout), value)
+	}
+	return domain.ExchangeRateModel{Currency: currency, Date: date.Format(domain.RateDateLayout), Rate: rate}, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestParseECBRates_XML(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender>
+	<Cube>
+		<Cube time="2026-03-03">
+			<Cube currency="USD" rate="1.0850"/>
+			<Cube currency="JPY" rate="162.41"/>
+		</Cube>
+		<Cube time="2026-03-02">
+			<Cube currency="USD" rate="1.0812"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+
+	rates, err := parseECBRates(data)
+	if err != nil {
+		t.Fatal("Error parsing the rates:", err)
+	}
+	expected := []domain.ExchangeRateModel{
+		{Currency: "USD", Date: "2026-03-03", Rate: 1.085},
+		{Currency: "JPY", Date: "2026-03-03", Rate: 162.41},
+		{Currency: "USD", Date: "2026-03-02", Rate: 1.0812},
+	}
+	if len(rates) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, rates)
+	}
+	for i := range expected {
+		if rates[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], rates[i])
+		}
+	}
+}
+
+func TestParseECBRates_CSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []domain.ExchangeRateModel
+	}{
+		{
+			name: "history",
+			data: "Date,USD,JPY,CYP,\n2026-03-03,1.0850,162.41,N/A,\n2026-03-02,1.0812,,N/A,\n",
+			expected: []domain.ExchangeRateModel{
+				{Currency: "USD", Date: "2026-03-03", Rate: 1.085},
+				{Currency: "JPY", Date: "2026-03-03", Rate: 162.41},
+				{Currency: "USD", Date: "2026-03-02", Rate: 1.0812},
+			},
+		},
+		{
+			name: "daily",
+			data: "\xef\xbb\xbfDate, USD, GBP, \n03 March 2026, 1.0850, 0.8521, \n",
+			expected: []domain.ExchangeRateModel{
+				{Currency: "USD", Date: "2026-03-03", Rate: 1.085},
+				{Currency: "GBP", Date: "2026-03-03", Rate: 0.8521},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := parseECBRates([]byte(tt.data))
+			if err != nil {
+				t.Fatal("Error parsing the rates:", err)
+			}
+			if len(rates) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, rates)
+			}
+			for i := range tt.expected {
+				if rates[i] != tt.expected[i] {
+					t.Errorf("Expected %+v, got %+v", tt.expected[i], rates[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseECBRates_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":         "",
+		"no date":       "Currency,USD\n2026-03-02,1.08\n",
+		"bad date":      "Date,USD\nyesterday,1.08\n",
+		"bad rate":      "Date,USD\n2026-03-02,-1\n",
+		"bad currency":  "Date,US Dollar\n2026-03-02,1.08\n",
+		"no rates":      "Date,USD\n",
+		"malformed xml": "<Envelope><Cube>",
+	}
+
+	for name, data := range tests {
+		if _, err := parseECBRates([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
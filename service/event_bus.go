@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+const (
+	eventPollInterval  = time.Second
+	eventBatchSize     = 100
+	eventRetryDelay    = 5 * time.Second // doubled after every failed attempt, up to eventMaxRetryDelay.
+	eventMaxRetryDelay = time.Hour
+)
+
+// EventHandler handles an event for a subscriber. An event is handed to a subscriber until it
+// returns nil, so it can be handled more than once and handlers should be idempotent.
+type EventHandler func(event *domain.EventDTO) error
+
+type EventBusInterface interface {
+	NewEvent(eventType domain.EventType, userId uuid.UUID, aggregateId uuid.UUID, data any) (domain.OutboxEventModel, error)
+	Subscribe(subscriber string, eventType domain.EventType, handler EventHandler)
+}
+
+// EventBus dispatches the domain events saved in the outbox to the in-process subscribers,
+// after the change that raised them was committed. Services create the events with NewEvent
+// and save them through the database interfaces with their changes.
+type EventBus struct {
+	OBDBI database.OutboxDatabaseInterface
+
+	mu          sync.RWMutex
+	subscribers map[domain.EventType][]eventSubscriber
+}
+
+type eventSubscriber struct {
+	name    string
+	handler EventHandler
+}
+
+// NewEvent returns the event to save with the change, with data as its payload.
+func (eb *EventBus) NewEvent(eventType domain.EventType, userId uuid.UUID, aggregateId uuid.UUID, data any) (domain.OutboxEventModel, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.OutboxEventModel{}, err
+	}
+
+	now := time.Now().UnixMilli()
+	return domain.OutboxEventModel{
+		EventId:       uuid.New(),
+		Type:          eventType,
+		UserId:        userId,
+		AggregateId:   aggregateId,
+		Payload:       string(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Subscribe hands the events of the type to the handler. The subscriber name records which
+// subscribers handled an event, it has to be unique per event type and stay the same across
+// restarts.
+func (eb *EventBus) Subscribe(subscriber string, eventType domain.EventType, handler EventHandler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if eb.subscribers == nil {
+		eb.subscribers = map[domain.EventType][]eventSubscriber{}
+	}
+	eb.subscribers[eventType] = append(eb.subscribers[eventType], eventSubscriber{name: subscriber, handler: handler})
+}
+
+// DispatchPending hands the pending events due at now to their subscribers. An event is
+// published once every subscriber handled it. When a subscriber fails, only the subscribers
+// that have not handled the event yet get it again after eventRetryDelay, doubling with every
+// attempt. Events are retried until they are handled, there is no last attempt.
+func (eb *EventBus) DispatchPending(now time.Time) error {
+	events, err := eb.OBDBI.GetPendingEvents(now.UnixMilli(), eventBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		err = eb.dispatch(&events[i], now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDispatcher dispatches the pending events every eventPollInterval. It never returns and is
+// meant to run in its own goroutine.
+func (eb *EventBus) RunDispatcher() {
+	for {
+		if err := eb.DispatchPending(time.Now()); err != nil {
+			log.Println("Error dispatching events:", err)
+		}
+		time.Sleep(eventPollInterval)
+	}
+}
+
+func (eb *EventBus) dispatch(event *domain.OutboxEventModel, now time.Time) error {
+	eb.mu.RLock()
+	subscribers := slices.Clone(eb.subscribers[event.Type])
+	eb.mu.RUnlock()
+
+	handled, err := eb.OBDBI.GetHandledSubscribers(event.EventId)
+	if err != nil {
+		return err
+	}
+
+	dto := convertEventModelToDTO(event)
+	var failures []string
+	for _, subscriber := range subscribers {
+		if slices.Contains(handled, subscriber.name) {
+			continue
+		}
+		err = subscriber.handler(&dto)
+		if err != nil {
+			log.Printf("Error handling %s event %v in %s: %v\n", event.Type, event.EventId, subscriber.name, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", subscriber.name, err))
+			continue
+		}
+		err = eb.OBDBI.MarkEventHandled(event.EventId, subscriber.name, now.UnixMilli())
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		attempts := event.Attempts + 1
+		next := now.Add(eventRetryAfter(attempts)).UnixMilli()
+		return eb.OBDBI.UpdateEventRetry(event.EventId, attempts, next, strings.Join(failures, "; "))
+	}
+	return eb.OBDBI.MarkEventPublished(event.EventId, now.UnixMilli())
+}
+
+// eventRetryAfter is the delay after the failed attempt.
+func eventRetryAfter(attempts int) time.Duration {
+	delay := eventRetryDelay
+	for i := 1; i < attempts && delay < eventMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, eventMaxRetryDelay)
+}
+
+// recordEvent returns the event to save with a change, none without an event bus.
+func recordEvent(bus EventBusInterface, eventType domain.EventType, userId uuid.UUID, aggregateId uuid.UUID, data any) ([]domain.OutboxEventModel, error) {
+	if bus == nil {
+		return nil, nil
+	}
+	event, err := bus.NewEvent(eventType, userId, aggregateId, data)
+	if err != nil {
+		return nil, err
+	}
+	return []domain.OutboxEventModel{event}, nil
+}
+
+func convertEventModelToDTO(from *domain.OutboxEventModel) domain.EventDTO {
+	return domain.EventDTO{
+		EventId:     from.EventId,
+		Type:        from.Type,
+		UserId:      from.UserId,
+		AggregateId: from.AggregateId,
+		Data:        json.RawMessage(from.Payload),
+		CreatedAt:   from.CreatedAt,
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpOutboxModel(db *sql.DB) {
+	stmts := []string{
+		`create table outbox_event (
+			id integer primary key autoincrement,
+			event_id text not null unique,
+			type text not null,
+			user_id text not null,
+			aggregate_id text not null,
+			payload text not null,
+			attempts integer not null,
+			next_attempt_at integer not null,
+			last_error text not null,
+			created_at integer not null,
+			published_at integer not null
+		)`,
+		`create table outbox_handled (
+			event_id text not null,
+			subscriber text not null,
+			handled_at integer not null,
+			primary key (event_id, subscriber)
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		if err != nil {
+			log.Fatal("There was an error creating the outbox tables:", err)
+		}
+	}
+}
+
+func TestEventBus_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+
+	eventBus := EventBus{OBDBI: &udb}
+	var counted []domain.EventDTO
+	eventBus.Subscribe("counter", domain.EVENT_TRANSACTION_CREATED, func(event *domain.EventDTO) error {
+		counted = append(counted, *event)
+		return nil
+	})
+	flaky := 0
+	eventBus.Subscribe("flaky", domain.EVENT_TRANSACTION_CREATED, func(event *domain.EventDTO) error {
+		flaky++
+		if flaky == 1 {
+			return errors.New("search index unavailable")
+		}
+		return nil
+	})
+	transactionService := TransactionService{UDBI: &udb, Events: &eventBus}
+
+	transaction := domain.TransactionDTOBuilder().Build()
+	if _, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the transaction:", err)
+	}
+
+	// counter handles the event, flaky fails and only flaky gets it again.
+	now := time.Now().Add(time.Second).Truncate(time.Millisecond)
+	if err := eventBus.DispatchPending(now); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	pending, err := udb.GetPendingEvents(now.Add(time.Hour).UnixMilli(), 10)
+	if err != nil {
+		t.Fatal("Error retrieving the pending events:", err)
+	}
+	if len(pending) != 1 || pending[0].Attempts != 1 || pending[0].NextAttemptAt != now.Add(eventRetryDelay).UnixMilli() ||
+		!strings.Contains(pending[0].LastError, "flaky: search index unavailable") {
+		t.Fatalf("Unexpected pending events %+v", pending)
+	}
+
+	if err := eventBus.DispatchPending(now.Add(eventRetryDelay - time.Millisecond)); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	if flaky != 1 {
+		t.Fatalf("The event was retried before it was due, %d attempts", flaky)
+	}
+	if err := eventBus.DispatchPending(now.Add(eventRetryDelay)); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	if flaky != 2 || len(counted) != 1 {
+		t.Fatalf("Unexpected handling, flaky %d times and counter %d times", flaky, len(counted))
+	}
+	pending, err = udb.GetPendingEvents(now.Add(time.Hour).UnixMilli(), 10)
+	if err != nil {
+		t.Fatal("Error retrieving the pending events:", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("The handled event is still pending %+v", pending)
+	}
+
+	event := counted[0]
+	var data domain.TransactionDTO
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		t.Fatal("Error reading the event data:", err)
+	}
+	if event.Type != domain.EVENT_TRANSACTION_CREATED || event.UserId != transaction.UserId || event.AggregateId != transaction.TransactionId ||
+		data.TransactionId != transaction.TransactionId || data.Amount != transaction.Amount {
+		t.Errorf("Unexpected event %+v with data %s", event, event.Data)
+	}
+
+	// events without subscribers are published right away.
+	if err := transactionService.DeleteTransaction(context.Background(), transaction.TransactionId); err != nil {
+		t.Fatal("Error deleting the transaction:", err)
+	}
+	if err := eventBus.DispatchPending(time.Now().Add(time.Second)); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	pending, err = udb.GetPendingEvents(now.Add(time.Hour).UnixMilli(), 10)
+	if err != nil {
+		t.Fatal("Error retrieving the pending events:", err)
+	}
+	if len(pending) != 0 || len(counted) != 1 {
+		t.Errorf("Unexpected events after the delete, %d pending and %d counted", len(pending), len(counted))
+	}
+}
+
+func TestEventBus_SavedWithTheChange(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+	eventBus := EventBus{OBDBI: &udb}
+
+	tm := domain.TransactionModelBuilder().Build()
+	event, err := eventBus.NewEvent(domain.EVENT_TRANSACTION_CREATED, tm.UserId, tm.TransactionId, convertTransactionModelToDTO(&tm))
+	if err != nil {
+		t.Fatal("Error creating the event:", err)
+	}
+
+	// the second copy of the event breaks the unique event id, which rolls back the transaction.
+	if err := udb.AddTransaction(context.Background(), &tm, event, event); err == nil {
+		t.Fatal("Expected the duplicate event to fail the transaction")
+	}
+	if _, err := udb.GetTransaction(tm.TransactionId); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("The transaction was saved without its event, %v", err)
+	}
+	pending, err := udb.GetPendingEvents(time.Now().Add(time.Second).UnixMilli(), 10)
+	if err != nil {
+		t.Fatal("Error retrieving the pending events:", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("The event was saved without the transaction %+v", pending)
+	}
+}
+
+func TestUserEvents_Integration(t *testing.T) {
+	db := setUpUserModel()
+	defer db.Close()
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+	userService := UserService{UDBI: &udb, Events: &EventBus{OBDBI: &udb}}
+
+	user := domain.UserDTOBuilder().Build()
+	if err := userService.RegisterNewUser(context.Background(), &domain.UserData{User: &user, Validator: validator.New()}); err != nil {
+		t.Fatal("Error registering the user:", err)
+	}
+	saved, err := udb.RetrieveUserByEmail(user.Email)
+	if err != nil {
+		t.Fatal("Error retrieving the user:", err)
+	}
+	update := domain.UserDTO{UserId: saved.UserId, FirstName: "Renamed", LastName: saved.LastName, Email: saved.Email, Phone: saved.Phone, DateOfBirth: saved.DateOfBirth}
+	if err := userService.UpdateUserProfileData(context.Background(), &update); err != nil {
+		t.Fatal("Error updating the user:", err)
+	}
+
+	events, err := udb.GetPendingEvents(time.Now().Add(time.Second).UnixMilli(), 10)
+	if err != nil {
+		t.Fatal("Error retrieving the pending events:", err)
+	}
+	if len(events) != 2 || events[0].Type != domain.EVENT_USER_REGISTERED || events[1].Type != domain.EVENT_USER_UPDATED {
+		t.Fatalf("Unexpected events %+v", events)
+	}
+	for _, event := range events {
+		if event.UserId != saved.UserId || event.AggregateId != saved.UserId || strings.Contains(event.Payload, "assword") {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	}
+	var updated domain.UserEventDTO
+	if err := json.Unmarshal([]byte(events[1].Payload), &updated); err != nil {
+		t.Fatal("Error reading the event payload:", err)
+	}
+	if updated.FirstName != "Renamed" || updated.CreationDate != saved.CreationDate {
+		t.Errorf("Unexpected user in the updated event %+v", updated)
+	}
+}
+
+func TestEventRetryAfter(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 5 * time.Second},
+		{attempts: 2, want: 10 * time.Second},
+		{attempts: 5, want: 80 * time.Second},
+		{attempts: 11, want: time.Hour},
+		{attempts: 1000, want: time.Hour},
+	}
+
+	for _, test := range tests {
+		if got := eventRetryAfter(test.attempts); got != test.want {
+			t.Errorf("Wrong delay after attempt %d: got %v, want %v", test.attempts, got, test.want)
+		}
+	}
+}
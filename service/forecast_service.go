@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+const (
+	DefaultForecastDays = 30
+	MaxForecastDays     = 365
+
+	// forecastLookbackDays of past expenses estimate the variable spending.
+	forecastLookbackDays = 90
+)
+
+type ForecastServiceInterface interface {
+	AddScheduledItem(ctx context.Context, itemData *domain.ScheduledItemData) (*domain.ScheduledItemDTO, error)
+	RetrieveScheduledItems(userId uuid.UUID) ([]domain.ScheduledItemDTO, error)
+	DeleteScheduledItem(ctx context.Context, scheduledItemId uuid.UUID) error
+	Forecast(userId uuid.UUID, accountId int64, days int) ([]domain.ForecastDTO, error)
+}
+
+type ForecastService struct {
+	SDBI database.ScheduleDatabaseInterface
+	TDBI database.TransactionDatabaseInterface
+}
+
+func (fs *ForecastService) AddScheduledItem(ctx context.Context, itemData *domain.ScheduledItemData) (*domain.ScheduledItemDTO, error) {
+	err := itemData.ValidateScheduledItem()
+	if err != nil {
+		return nil, err
+	}
+
+	sm := convertScheduledItemDTOToModel(&itemData.ScheduledItem)
+	sm.ScheduledItemId = uuid.New()
+	sm.CreatedAt = time.Now().UnixMilli()
+	err = fs.SDBI.AddScheduledItem(ctx, &sm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertScheduledItemModelToDTO(&sm)
+	return &saved, nil
+}
+
+func (fs *ForecastService) RetrieveScheduledItems(userId uuid.UUID) ([]domain.ScheduledItemDTO, error) {
+	items, err := fs.SDBI.GetScheduledItemsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	itemDTOs := make([]domain.ScheduledItemDTO, 0, len(items))
+	for _, sm := range items {
+		itemDTOs = append(itemDTOs, convertScheduledItemModelToDTO(&sm))
+	}
+	return itemDTOs, nil
+}
+
+func (fs *ForecastService) DeleteScheduledItem(ctx context.Context, scheduledItemId uuid.UUID) error {
+	return fs.SDBI.DeleteScheduledItem(ctx, scheduledItemId)
+}
+
+// Forecast projects the daily balance of the account, or of every account when accountId is 0,
+// for the next days.
+func (fs *ForecastService) Forecast(userId uuid.UUID, accountId int64, days int) ([]domain.ForecastDTO, error) {
+	if days < 1 || days > MaxForecastDays {
+		return nil, fmt.Errorf("forecasts are between 1 and %d days", MaxForecastDays)
+	}
+
+	transactions, err := fs.TDBI.GetTransactionsByUserId(userId, 0, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+	items, err := fs.SDBI.GetScheduledItemsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return forecastBalances(transactions, items, accountId, time.Now(), days), nil
+}
+
+type accountForecast struct {
+	balance float64
+	known   map[int]float64 // net amount by day, 1 is tomorrow.
+	weekday [7]float64      // variable spending in the lookback window by day of the week.
+}
+
+// forecastBalances starts from the current balances and adds, day by day, the scheduled items
+// and future dated transactions, then takes off the variable spending expected for that day
+// of the week. Variable spending is the average spent on that weekday over the lookback
+// window, leaving out transactions that look like a scheduled item.
+func forecastBalances(transactions []domain.TransactionModel, items []domain.ScheduledItemModel, accountId int64, now time.Time, days int) []domain.ForecastDTO {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lookbackStart := today.AddDate(0, 0, -forecastLookbackDays).UnixMilli()
+
+	accounts := map[int64]*accountForecast{}
+	account := func(id int64) *accountForecast {
+		if accounts[id] == nil {
+			accounts[id] = &accountForecast{known: map[int]float64{}}
+		}
+		return accounts[id]
+	}
+
+	for _, tm := range transactions {
+		if tm.Status == domain.CANCELLED || (accountId != 0 && tm.AccountId != accountId) {
+			continue
+		}
+		a := account(tm.AccountId)
+		amount := signedAmount(tm.Type, tm.Amount)
+
+		if tm.Date > now.UnixMilli() {
+			// still to come today counts toward tomorrow, the first projected day.
+			if day := max(dayIndex(today, tm.Date), 1); day <= days {
+				a.known[day] += amount
+			}
+			continue
+		}
+		a.balance += amount
+		if tm.Type == domain.EXPENSE && tm.Date >= lookbackStart && tm.Date < today.UnixMilli() && !isScheduled(&tm, items) {
+			a.weekday[time.UnixMilli(tm.Date).UTC().Weekday()] += tm.Amount
+		}
+	}
+
+	end := today.AddDate(0, 0, days+1)
+	for _, item := range items {
+		if accountId != 0 && item.AccountId != accountId {
+			continue
+		}
+		a := account(item.AccountId)
+		start := time.UnixMilli(item.StartDate).UTC()
+		for n := 0; ; n++ {
+			occurrence := item.Frequency.Occurrence(start, n)
+			if !occurrence.Before(end) {
+				break
+			}
+			// occurrences up to today are assumed to be in the transactions already.
+			if day := dayIndex(today, occurrence.UnixMilli()); day >= 1 && day <= days {
+				a.known[day] += signedAmount(item.Type, item.Amount)
+			}
+		}
+	}
+
+	var weekdays [7]float64
+	for day := today.AddDate(0, 0, -forecastLookbackDays); day.Before(today); day = day.AddDate(0, 0, 1) {
+		weekdays[day.Weekday()]++
+	}
+
+	accountIds := make([]int64, 0, len(accounts))
+	for id := range accounts {
+		accountIds = append(accountIds, id)
+	}
+	sort.Slice(accountIds, func(i, j int) bool { return accountIds[i] < accountIds[j] })
+
+	forecasts := make([]domain.ForecastDTO, 0, len(accountIds))
+	for _, id := range accountIds {
+		a := accounts[id]
+		forecast := domain.ForecastDTO{AccountId: id, Balance: roundCents(a.balance), Days: make([]domain.ForecastDayDTO, 0, days)}
+		balance := a.balance
+		for day := 1; day <= days; day++ {
+			date := today.AddDate(0, 0, day)
+			estimated := a.weekday[date.Weekday()] / weekdays[date.Weekday()]
+			balance += a.known[day] - estimated
+
+			projected := domain.ForecastDayDTO{Date: date.UnixMilli(), Known: roundCents(a.known[day]), Estimated: roundCents(estimated), Balance: roundCents(balance)}
+			if day == 1 || projected.Balance < forecast.LowBalance {
+				forecast.LowBalance = projected.Balance
+				forecast.LowDate = projected.Date
+			}
+			forecast.Days = append(forecast.Days, projected)
+		}
+		forecasts = append(forecasts, forecast)
+	}
+	return forecasts
+}
+
+// isScheduled reports whether the transaction looks like an occurrence of a scheduled item,
+// those are projected from the schedule instead.
+func isScheduled(tm *domain.TransactionModel, items []domain.ScheduledItemModel) bool {
+	for _, item := range items {
+		if item.AccountId == tm.AccountId && descriptionSimilarity(item.Description, tm.Description) >= duplicateThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// dayIndex is the number of days from today to the day of date.
+func dayIndex(today time.Time, date int64) int {
+	return int(math.Floor(float64(date-today.UnixMilli()) / float64(dayMillis)))
+}
+
+func signedAmount(transactionType domain.TransactionType, amount float64) float64 {
+	if transactionType == domain.INCOME {
+		return amount
+	}
+	return -amount
+}
+
+func convertScheduledItemDTOToModel(from *domain.ScheduledItemDTO) domain.ScheduledItemModel {
+	return domain.ScheduledItemModel{
+		ScheduledItemId: from.ScheduledItemId,
+		UserId:          from.UserId,
+		AccountId:       from.AccountId,
+		Description:     from.Description,
+		Amount:          from.Amount,
+		Type:            from.Type,
+		Frequency:       from.Frequency,
+		StartDate:       from.StartDate,
+		CreatedAt:       from.CreatedAt,
+	}
+}
+
+func convertScheduledItemModelToDTO(from *domain.ScheduledItemModel) domain.ScheduledItemDTO {
+	return domain.ScheduledItemDTO{
+		ScheduledItemId: from.ScheduledItemId,
+		UserId:          from.UserId,
+		AccountId:       from.AccountId,
+		Description:     from.Description,
+		Amount:          from.Amount,
+		Type:            from.Type,
+		Frequency:       from.Frequency,
+		StartDate:       from.StartDate,
+		CreatedAt:       from.CreatedAt,
+	}
+}
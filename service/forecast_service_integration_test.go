@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpScheduledItemModel(db *sql.DB) {
+	stmt := `create table scheduled_item_model (
+		id integer primary key autoincrement,
+		scheduled_item_id text not null,
+		user_id text not null,
+		account_id integer not null,
+		description text not null,
+		amount float not null,
+		type integer not null,
+		frequency integer not null,
+		start_date integer not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating scheduled_item_model table:", err)
+	}
+}
+
+func TestForecast_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpScheduledItemModel(db)
+	udb := database.SQLManager{DB: db}
+	forecastService := ForecastService{SDBI: &udb, TDBI: &udb}
+
+	userId := uuid.New()
+	tm := forecastTransaction(1, time.Now().AddDate(0, -1, 0), 500, domain.INCOME, "Opening balance")
+	tm.UserId = userId
+	if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+
+	rent := domain.ScheduledItemDTO{UserId: userId, AccountId: 1, Description: "Rent", Amount: 800, Type: domain.EXPENSE, Frequency: domain.WEEKLY, StartDate: time.Now().AddDate(0, 0, 3).UnixMilli()}
+	if _, err := forecastService.AddScheduledItem(context.Background(), &domain.ScheduledItemData{ScheduledItem: rent, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the scheduled item:", err)
+	}
+	items, err := forecastService.RetrieveScheduledItems(userId)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("Expected one scheduled item, got %v, err: %v", items, err)
+	}
+
+	forecasts, err := forecastService.Forecast(userId, 0, 7)
+	if err != nil {
+		t.Fatal("Error forecasting:", err)
+	}
+	if len(forecasts) != 1 || forecasts[0].Balance != 500 || forecasts[0].LowBalance != -300 {
+		t.Fatalf("Wrong forecast, got %+v", forecasts)
+	}
+	if lowDay := time.UnixMilli(forecasts[0].LowDate).UTC(); lowDay.Day() != time.Now().UTC().AddDate(0, 0, 3).Day() {
+		t.Errorf("Expected the low point on rent day, got %v", lowDay)
+	}
+
+	if _, err := forecastService.Forecast(userId, 0, MaxForecastDays+1); err == nil {
+		t.Error("Expected an error for a forecast that is too long")
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func forecastTransaction(accountId int64, date time.Time, amount float64, transactionType domain.TransactionType, description string) domain.TransactionModel {
+	tm := domain.TransactionModelBuilder().Build()
+	tm.AccountId = accountId
+	tm.Date = date.UnixMilli()
+	tm.Amount = amount
+	tm.Type = transactionType
+	tm.Status = domain.CLEARED
+	tm.Description = description
+	return tm
+}
+
+func TestForecastBalances(t *testing.T) {
+	now := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 12, 0, 0, 0, time.UTC) }
+
+	// 10 a day of coffee over the lookback window, paid once and rent once: a balance of 900.
+	var transactions []domain.TransactionModel
+	for i := 1; i <= forecastLookbackDays; i++ {
+		transactions = append(transactions, forecastTransaction(1, now.AddDate(0, 0, -i), 10, domain.EXPENSE, "Coffee shop"))
+	}
+	transactions = append(transactions,
+		forecastTransaction(1, day(time.January, 23), 3000, domain.INCOME, "ACME payroll"),
+		forecastTransaction(1, day(time.February, 1), 1200, domain.EXPENSE, "Rent April Lane"),
+		forecastTransaction(1, day(time.February, 25), 50, domain.EXPENSE, "Dentist"),
+		forecastTransaction(2, day(time.February, 2), 75, domain.INCOME, "Interest"),
+	)
+	cancelled := forecastTransaction(1, day(time.February, 3), 5000, domain.INCOME, "Lottery")
+	cancelled.Status = domain.CANCELLED
+	transactions = append(transactions, cancelled)
+
+	items := []domain.ScheduledItemModel{
+		{ScheduledItemId: uuid.New(), AccountId: 1, Description: "Rent April Lane", Amount: 1200, Type: domain.EXPENSE, Frequency: domain.MONTHLY, StartDate: day(time.January, 31).UnixMilli()},
+		{ScheduledItemId: uuid.New(), AccountId: 1, Description: "ACME payroll", Amount: 2000, Type: domain.INCOME, Frequency: domain.BIWEEKLY, StartDate: day(time.January, 9).UnixMilli()},
+	}
+
+	forecasts := forecastBalances(transactions, items, 1, now, 14)
+	if len(forecasts) != 1 {
+		t.Fatalf("Expected only account 1, got %v", forecasts)
+	}
+	forecast := forecasts[0]
+	if forecast.Balance != 900 || len(forecast.Days) != 14 {
+		t.Fatalf("Wrong forecast, got balance %v and %d days", forecast.Balance, len(forecast.Days))
+	}
+
+	tests := []struct {
+		day         int
+		wantKnown   float64
+		wantBalance float64
+	}{
+		{day: 1, wantKnown: 0, wantBalance: 890},
+		{day: 5, wantKnown: -50, wantBalance: 800},    // the dentist
+		{day: 8, wantKnown: -1200, wantBalance: -430}, // rent on the last day of February
+		{day: 13, wantKnown: 0, wantBalance: -480},    // the day before payday
+		{day: 14, wantKnown: 2000, wantBalance: 1510}, // payday
+	}
+	for _, test := range tests {
+		projected := forecast.Days[test.day-1]
+		if projected.Known != test.wantKnown || projected.Estimated != 10 || projected.Balance != test.wantBalance {
+			t.Errorf("Wrong projection for day %d, got %+v", test.day, projected)
+		}
+	}
+
+	if forecast.LowBalance != -480 || forecast.LowDate != time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC).UnixMilli() {
+		t.Errorf("Wrong low point, got %v on %v", forecast.LowBalance, time.UnixMilli(forecast.LowDate).UTC())
+	}
+
+	if all := forecastBalances(transactions, items, 0, now, 14); len(all) != 2 || all[1].AccountId != 2 || all[1].Balance != 75 {
+		t.Errorf("Expected both accounts, got %v", all)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// goalTrailingMonths is how far back contributions are averaged into the monthly rate.
+const goalTrailingMonths = 3
+
+// daysPerMonth is the average length of a month, used to turn durations into months.
+const daysPerMonth = 365.25 / 12
+
+type GoalServiceInterface interface {
+	AddGoal(ctx context.Context, goalData *domain.GoalData) (*domain.GoalDTO, error)
+	RetrieveGoals(userId uuid.UUID) ([]domain.GoalProgressDTO, error)
+	RetrieveGoalProgress(goalId uuid.UUID) (*domain.GoalProgressDTO, error)
+	DeleteGoal(ctx context.Context, goalId uuid.UUID) error
+}
+
+type GoalService struct {
+	GDBI database.GoalDatabaseInterface
+}
+
+func (gs *GoalService) AddGoal(ctx context.Context, goalData *domain.GoalData) (*domain.GoalDTO, error) {
+	err := goalData.ValidateGoal()
+	if err != nil {
+		return nil, err
+	}
+
+	gm := convertGoalDTOToModel(&goalData.Goal)
+	gm.GoalId = uuid.New()
+	gm.CreatedAt = time.Now().UnixMilli()
+	err = gs.GDBI.AddGoal(ctx, &gm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertGoalModelToDTO(&gm)
+	return &saved, nil
+}
+
+// RetrieveGoals returns the users goals with their progress, nearest target date first.
+func (gs *GoalService) RetrieveGoals(userId uuid.UUID) ([]domain.GoalProgressDTO, error) {
+	goals, err := gs.GDBI.GetGoalsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make([]domain.GoalProgressDTO, 0, len(goals))
+	for _, gm := range goals {
+		goalProgress, err := gs.progress(&gm)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, *goalProgress)
+	}
+	return progress, nil
+}
+
+func (gs *GoalService) RetrieveGoalProgress(goalId uuid.UUID) (*domain.GoalProgressDTO, error) {
+	gm, err := gs.GDBI.GetGoal(goalId)
+	if err != nil {
+		return nil, err
+	}
+	return gs.progress(&gm)
+}
+
+func (gs *GoalService) DeleteGoal(ctx context.Context, goalId uuid.UUID) error {
+	return gs.GDBI.DeleteGoal(ctx, goalId)
+}
+
+func (gs *GoalService) progress(gm *domain.GoalModel) (*domain.GoalProgressDTO, error) {
+	now := time.Now()
+	saved, err := gs.GDBI.GetGoalContributions(gm, 0, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	trailing, err := gs.GDBI.GetGoalContributions(gm, now.AddDate(0, -goalTrailingMonths, 0).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	progress := goalProgress(gm, saved, trailing/goalTrailingMonths, now)
+	return &progress, nil
+}
+
+// goalProgress projects when the goal is reached if monthlyRate keeps being saved, and what
+// needs to be saved each month to reach it by the target date.
+func goalProgress(gm *domain.GoalModel, saved float64, monthlyRate float64, now time.Time) domain.GoalProgressDTO {
+	progress := domain.GoalProgressDTO{
+		Goal:        convertGoalModelToDTO(gm),
+		Saved:       roundCents(saved),
+		Remaining:   roundCents(math.Max(gm.TargetAmount-saved, 0)),
+		Percent:     math.Round(math.Min(saved/gm.TargetAmount*100, 100)*10) / 10,
+		MonthlyRate: roundCents(monthlyRate),
+	}
+	if progress.Remaining == 0 {
+		progress.Completed = true
+		progress.OnTrack = true
+		progress.ProjectedDate = now.UnixMilli()
+		return progress
+	}
+
+	// a target date that has passed needs the rest right away.
+	monthsLeft := float64(gm.TargetDate-now.UnixMilli()) / float64(dayMillis) / daysPerMonth
+	progress.RequiredMonthly = roundCents(progress.Remaining / math.Max(monthsLeft, 1))
+
+	if monthlyRate > 0 {
+		days := math.Ceil(progress.Remaining / monthlyRate * daysPerMonth)
+		progress.ProjectedDate = now.AddDate(0, 0, int(days)).UnixMilli()
+		progress.OnTrack = progress.ProjectedDate <= gm.TargetDate
+	}
+	return progress
+}
+
+func convertGoalDTOToModel(from *domain.GoalDTO) domain.GoalModel {
+	return domain.GoalModel{
+		GoalId:       from.GoalId,
+		UserId:       from.UserId,
+		Name:         from.Name,
+		TargetAmount: from.TargetAmount,
+		TargetDate:   from.TargetDate,
+		AccountId:    from.AccountId,
+		CategoryId:   from.CategoryId,
+		CreatedAt:    from.CreatedAt,
+	}
+}
+
+func convertGoalModelToDTO(from *domain.GoalModel) domain.GoalDTO {
+	return domain.GoalDTO{
+		GoalId:       from.GoalId,
+		UserId:       from.UserId,
+		Name:         from.Name,
+		TargetAmount: from.TargetAmount,
+		TargetDate:   from.TargetDate,
+		AccountId:    from.AccountId,
+		CategoryId:   from.CategoryId,
+		CreatedAt:    from.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpGoalModel(db *sql.DB) {
+	stmt := `create table goal_model (
+		id integer primary key autoincrement,
+		goal_id text not null,
+		user_id text not null,
+		name text not null,
+		target_amount float not null,
+		target_date integer not null,
+		account_id integer not null,
+		category_id integer not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating goal_model table:", err)
+	}
+}
+
+func TestGoals_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpGoalModel(db)
+	udb := database.SQLManager{DB: db}
+	goalService := GoalService{GDBI: &udb}
+
+	userId := uuid.New()
+	now := time.Now()
+	add := func(accountId int64, categoryId int64, date time.Time, amount float64, transactionType domain.TransactionType, status domain.TransactionStatus) {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.AccountId = accountId
+		tm.CategoryId = categoryId
+		tm.Date = date.UnixMilli()
+		tm.Amount = amount
+		tm.Type = transactionType
+		tm.Status = status
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+	// the savings account: 3000 a year ago, then 300 a month for the trailing months.
+	add(9, 1, now.AddDate(-1, 0, 0), 3000, domain.INCOME, domain.CLEARED)
+	for month := 0; month < goalTrailingMonths; month++ {
+		add(9, 1, now.AddDate(0, -month, -1), 300, domain.INCOME, domain.CLEARED)
+	}
+	add(9, 1, now.AddDate(0, 0, -2), 100, domain.EXPENSE, domain.CLEARED)
+	add(9, 1, now.AddDate(0, 0, -2), 5000, domain.INCOME, domain.CANCELLED)
+	// a savings category on another account: set aside 400, took 50 back.
+	add(2, 7, now.AddDate(0, 0, -3), 400, domain.EXPENSE, domain.CLEARED)
+	add(2, 7, now.AddDate(0, 0, -3), 50, domain.INCOME, domain.CLEARED)
+
+	goals := []domain.GoalDTO{
+		{UserId: userId, Name: "Emergency fund", TargetAmount: 10000, TargetDate: now.AddDate(2, 0, 0).UnixMilli(), AccountId: 9},
+		{UserId: userId, Name: "Holiday", TargetAmount: 350, TargetDate: now.AddDate(0, 6, 0).UnixMilli(), CategoryId: 7},
+	}
+	for _, goal := range goals {
+		if _, err := goalService.AddGoal(context.Background(), &domain.GoalData{Goal: goal, Validator: validator.New()}); err != nil {
+			t.Fatal("Error adding the goal:", err)
+		}
+	}
+
+	progress, err := goalService.RetrieveGoals(userId)
+	if err != nil || len(progress) != 2 {
+		t.Fatalf("Expected two goals, got %v, err: %v", progress, err)
+	}
+
+	holiday := progress[0]
+	if holiday.Goal.Name != "Holiday" || holiday.Saved != 350 || !holiday.Completed {
+		t.Errorf("Wrong holiday progress, got %+v", holiday)
+	}
+
+	emergency := progress[1]
+	if emergency.Saved != 3800 || emergency.Remaining != 6200 || emergency.MonthlyRate != roundCents(800.0/goalTrailingMonths) {
+		t.Errorf("Wrong emergency fund progress, got %+v", emergency)
+	}
+	// 6200 at 266.67 a month takes about 23 of the 24 months left.
+	if emergency.ProjectedDate == 0 || emergency.ProjectedDate > emergency.Goal.TargetDate || !emergency.OnTrack {
+		t.Errorf("Expected the emergency fund to be on track, got %+v", emergency)
+	}
+
+	single, err := goalService.RetrieveGoalProgress(emergency.Goal.GoalId)
+	if err != nil || single.Saved != emergency.Saved {
+		t.Errorf("Wrong single goal progress, got %+v, err: %v", single, err)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestGoalProgress(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 10,000 by about 18 months from now.
+	target := now.AddDate(0, 18, 0).UnixMilli()
+	goal := domain.GoalModel{GoalId: uuid.New(), Name: "Emergency fund", TargetAmount: 10000, TargetDate: target, AccountId: 1}
+
+	tests := []struct {
+		name          string
+		saved         float64
+		monthlyRate   float64
+		wantRemaining float64
+		wantPercent   float64
+		wantCompleted bool
+		wantOnTrack   bool
+		wantProjected time.Time
+	}{
+		{name: "On track", saved: 4000, monthlyRate: 500, wantRemaining: 6000, wantPercent: 40, wantOnTrack: true, wantProjected: now.AddDate(0, 0, 366)},
+		{name: "Behind", saved: 4000, monthlyRate: 200, wantRemaining: 6000, wantPercent: 40, wantOnTrack: false, wantProjected: now.AddDate(0, 0, 914)},
+		{name: "Not saving", saved: 4000, monthlyRate: -50, wantRemaining: 6000, wantPercent: 40, wantOnTrack: false},
+		{name: "Completed", saved: 10250, monthlyRate: 0, wantRemaining: 0, wantPercent: 100, wantCompleted: true, wantOnTrack: true, wantProjected: now},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			progress := goalProgress(&goal, test.saved, test.monthlyRate, now)
+
+			if progress.Remaining != test.wantRemaining || progress.Percent != test.wantPercent {
+				t.Errorf("Wrong progress, got remaining %v, percent %v", progress.Remaining, progress.Percent)
+			}
+			if progress.Completed != test.wantCompleted || progress.OnTrack != test.wantOnTrack {
+				t.Errorf("Wrong status, got completed %v, on track %v", progress.Completed, progress.OnTrack)
+			}
+			var wantProjected int64
+			if !test.wantProjected.IsZero() {
+				wantProjected = test.wantProjected.UnixMilli()
+			}
+			if progress.ProjectedDate != wantProjected {
+				t.Errorf("Wrong projected date, got %v, want %v", time.UnixMilli(progress.ProjectedDate).UTC(), test.wantProjected)
+			}
+			if !test.wantCompleted && (progress.RequiredMonthly < 330 || progress.RequiredMonthly > 335) {
+				t.Errorf("Expected about 6000 over 18 months, got %v", progress.RequiredMonthly)
+			}
+		})
+	}
+}
+
+func TestGoalProgress_TargetPassed(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	goal := domain.GoalModel{TargetAmount: 1000, TargetDate: now.AddDate(0, -1, 0).UnixMilli(), CategoryId: 4}
+
+	progress := goalProgress(&goal, 250, 100, now)
+	if progress.RequiredMonthly != 750 || progress.OnTrack {
+		t.Errorf("Expected the rest to be required right away, got %+v", progress)
+	}
+}
+
+func TestAddGoal_Invalid(t *testing.T) {
+	goalService := GoalService{}
+	target := time.Now().AddDate(1, 0, 0).UnixMilli()
+
+	tests := []struct {
+		name string
+		goal domain.GoalDTO
+	}{
+		{name: "No link", goal: domain.GoalDTO{UserId: uuid.New(), Name: "Car", TargetAmount: 5000, TargetDate: target}},
+		{name: "Account and category", goal: domain.GoalDTO{UserId: uuid.New(), Name: "Car", TargetAmount: 5000, TargetDate: target, AccountId: 1, CategoryId: 2}},
+		{name: "No target", goal: domain.GoalDTO{UserId: uuid.New(), Name: "Car", TargetDate: target, AccountId: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := goalService.AddGoal(context.Background(), &domain.GoalData{Goal: test.goal, Validator: validator.New()})
+			if err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,570 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// quantityEpsilon is how close to zero a number of shares counts as none, fractional shares
+// from splits and partial sales do not add up exactly.
+const quantityEpsilon = 1e-9
+
+var (
+	ErrSecurityExists     = errors.New("the user already has a security with the symbol")
+	ErrSecurityNotFound   = errors.New("the security does not belong to the user")
+	ErrInsufficientShares = errors.New("the sale is for more shares than are held")
+)
+
+type InvestmentServiceInterface interface {
+	AddSecurity(ctx context.Context, securityData *domain.SecurityData) (*domain.SecurityDTO, error)
+	RetrieveSecurities(userId uuid.UUID) ([]domain.SecurityDTO, error)
+	AddInvestmentTransaction(ctx context.Context, investmentData *domain.InvestmentTransactionData) (*domain.InvestmentTransactionDTO, error)
+	RetrieveInvestmentTransactions(userId uuid.UUID) ([]domain.InvestmentTransactionDTO, error)
+	ImportPrices(data []byte) (*domain.PriceImportDTO, error)
+	RetrievePortfolio(userId uuid.UUID, date int64) (*domain.PortfolioDTO, error)
+	RetrieveRealizedGains(userId uuid.UUID, from int64, to int64) ([]domain.RealizedGainDTO, error)
+	PortfolioValues(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error)
+}
+
+type InvestmentService struct {
+	IDBI database.InvestmentDatabaseInterface
+}
+
+func (is *InvestmentService) AddSecurity(ctx context.Context, securityData *domain.SecurityData) (*domain.SecurityDTO, error) {
+	err := securityData.ValidateSecurity()
+	if err != nil {
+		return nil, err
+	}
+
+	sm := convertSecurityDTOToModel(&securityData.Security)
+	sm.SecurityId = uuid.New()
+	sm.Symbol = strings.ToUpper(strings.TrimSpace(sm.Symbol))
+	sm.Name = strings.TrimSpace(sm.Name)
+	sm.CreatedAt = time.Now().UnixMilli()
+
+	securities, err := is.IDBI.GetSecuritiesByUserId(sm.UserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, security := range securities {
+		if security.Symbol == sm.Symbol {
+			return nil, ErrSecurityExists
+		}
+	}
+
+	err = is.IDBI.AddSecurity(ctx, &sm)
+	if err != nil {
+		return nil, err
+	}
+	saved := convertSecurityModelToDTO(&sm)
+	return &saved, nil
+}
+
+func (is *InvestmentService) RetrieveSecurities(userId uuid.UUID) ([]domain.SecurityDTO, error) {
+	securities, err := is.IDBI.GetSecuritiesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	securityDTOs := make([]domain.SecurityDTO, 0, len(securities))
+	for _, sm := range securities {
+		securityDTOs = append(securityDTOs, convertSecurityModelToDTO(&sm))
+	}
+	return securityDTOs, nil
+}
+
+// AddInvestmentTransaction saves the trade or corporate action once the users transactions,
+// with it added, still hold the shares every sale is for.
+func (is *InvestmentService) AddInvestmentTransaction(ctx context.Context, investmentData *domain.InvestmentTransactionData) (*domain.InvestmentTransactionDTO, error) {
+	err := investmentData.ValidateInvestmentTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	im := convertInvestmentTransactionDTOToModel(&investmentData.Investment)
+	im.InvestmentId = uuid.New()
+	im.CreatedAt = time.Now().UnixMilli()
+	err = checkInvestmentTransaction(&im)
+	if err != nil {
+		return nil, err
+	}
+
+	securities, err := is.securities(im.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := securities[im.SecurityId]; !ok {
+		return nil, ErrSecurityNotFound
+	}
+
+	investments, err := is.IDBI.GetInvestmentTransactions(im.UserId, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+	investments = append(investments, im)
+	sort.SliceStable(investments, func(i, j int) bool { return investments[i].Date < investments[j].Date })
+	_, err = replayInvestments(investments)
+	if err != nil {
+		return nil, err
+	}
+
+	err = is.IDBI.AddInvestmentTransaction(ctx, &im)
+	if err != nil {
+		return nil, err
+	}
+	saved := convertInvestmentTransactionModelToDTO(&im)
+	return &saved, nil
+}
+
+func (is *InvestmentService) RetrieveInvestmentTransactions(userId uuid.UUID) ([]domain.InvestmentTransactionDTO, error) {
+	investments, err := is.IDBI.GetInvestmentTransactions(userId, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	investmentDTOs := make([]domain.InvestmentTransactionDTO, 0, len(investments))
+	for _, im := range investments {
+		investmentDTOs = append(investmentDTOs, convertInvestmentTransactionModelToDTO(&im))
+	}
+	return investmentDTOs, nil
+}
+
+// ImportPrices saves the closing prices of a CSV file, replacing the prices of the same symbols
+// and days imported before. Prices are shared by every user holding the symbol.
+func (is *InvestmentService) ImportPrices(data []byte) (*domain.PriceImportDTO, error) {
+	prices, err := parseSecurityPrices(data)
+	if err != nil {
+		return nil, err
+	}
+	err = is.IDBI.SaveSecurityPrices(prices)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := map[string]bool{}
+	imported := &domain.PriceImportDTO{Prices: len(prices), From: prices[0].Date, To: prices[0].Date}
+	for _, price := range prices {
+		symbols[price.Symbol] = true
+		imported.From = min(imported.From, price.Date)
+		imported.To = max(imported.To, price.Date)
+	}
+	imported.Symbols = len(symbols)
+	return imported, nil
+}
+
+// RetrievePortfolio values the users open lots at the end of the day of date.
+func (is *InvestmentService) RetrievePortfolio(userId uuid.UUID, date int64) (*domain.PortfolioDTO, error) {
+	investments, err := is.IDBI.GetInvestmentTransactions(userId, date)
+	if err != nil {
+		return nil, err
+	}
+	securities, err := is.securities(userId)
+	if err != nil {
+		return nil, err
+	}
+	replay, err := replayInvestments(investments)
+	if err != nil {
+		return nil, err
+	}
+
+	day := time.UnixMilli(date).UTC().Format(domain.RateDateLayout)
+	portfolio := &domain.PortfolioDTO{UserId: userId, Date: date, Holdings: []domain.HoldingDTO{}, Dividends: roundCents(replay.dividends)}
+	for _, gain := range replay.realized {
+		portfolio.RealizedGain += gain.Gain
+	}
+	portfolio.RealizedGain = roundCents(portfolio.RealizedGain)
+
+	for _, key := range replay.keys {
+		lots := replay.lots[key]
+		if len(lots) == 0 {
+			continue
+		}
+
+		security := securities[key.securityId]
+		holding := domain.HoldingDTO{AccountId: key.accountId, SecurityId: key.securityId, Symbol: security.Symbol, Name: security.Name, Price: replay.lastPrice[key.securityId]}
+		prices, err := is.IDBI.GetSecurityPrices(security.Symbol, day)
+		if err != nil {
+			return nil, err
+		}
+		if len(prices) > 0 {
+			holding.Price = prices[len(prices)-1].Price
+			holding.PriceDate = prices[len(prices)-1].Date
+		}
+
+		for _, lot := range lots {
+			cost := lot.quantity * lot.costPerShare
+			value := lot.quantity * holding.Price
+			holding.Quantity += lot.quantity
+			holding.CostBasis += cost
+			holding.MarketValue += value
+			holding.Lots = append(holding.Lots, domain.LotDTO{
+				LotId:          lot.id,
+				AcquiredAt:     lot.acquiredAt,
+				Quantity:       lot.quantity,
+				CostBasis:      roundCents(cost),
+				MarketValue:    roundCents(value),
+				UnrealizedGain: roundCents(value - cost),
+			})
+		}
+		holding.UnrealizedGain = roundCents(holding.MarketValue - holding.CostBasis)
+		holding.CostBasis = roundCents(holding.CostBasis)
+		holding.MarketValue = roundCents(holding.MarketValue)
+
+		portfolio.CostBasis += holding.CostBasis
+		portfolio.MarketValue += holding.MarketValue
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+	}
+	portfolio.CostBasis = roundCents(portfolio.CostBasis)
+	portfolio.MarketValue = roundCents(portfolio.MarketValue)
+	portfolio.UnrealizedGain = roundCents(portfolio.MarketValue - portfolio.CostBasis)
+	return portfolio, nil
+}
+
+// RetrieveRealizedGains returns the gain on every lot sold between from and to, in the order
+// the sales happened.
+func (is *InvestmentService) RetrieveRealizedGains(userId uuid.UUID, from int64, to int64) ([]domain.RealizedGainDTO, error) {
+	investments, err := is.IDBI.GetInvestmentTransactions(userId, to)
+	if err != nil {
+		return nil, err
+	}
+	securities, err := is.securities(userId)
+	if err != nil {
+		return nil, err
+	}
+	replay, err := replayInvestments(investments)
+	if err != nil {
+		return nil, err
+	}
+
+	gains := []domain.RealizedGainDTO{}
+	for _, gain := range replay.realized {
+		if gain.SoldAt < from {
+			continue
+		}
+		gain.Symbol = securities[gain.SecurityId].Symbol
+		gains = append(gains, gain)
+	}
+	return gains, nil
+}
+
+// PortfolioValues returns the market value of the users holdings at the end of every day from
+// through to, both UTC day starts, for the net worth. It is nil when the user has no investments.
+func (is *InvestmentService) PortfolioValues(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error) {
+	investments, err := is.IDBI.GetInvestmentTransactions(userId, to.UnixMilli()+dayMillis-1)
+	if err != nil || len(investments) == 0 {
+		return nil, err
+	}
+	securities, err := is.securities(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := map[uuid.UUID][]domain.SecurityPriceModel{}
+	for _, im := range investments {
+		if _, ok := histories[im.SecurityId]; ok {
+			continue
+		}
+		prices, err := is.IDBI.GetSecurityPrices(securities[im.SecurityId].Symbol, to.Format(domain.RateDateLayout))
+		if err != nil {
+			return nil, err
+		}
+		histories[im.SecurityId] = prices
+	}
+	return portfolioValues(investments, histories, from, to), nil
+}
+
+func (is *InvestmentService) securities(userId uuid.UUID) (map[uuid.UUID]domain.SecurityModel, error) {
+	securities, err := is.IDBI.GetSecuritiesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uuid.UUID]domain.SecurityModel, len(securities))
+	for _, sm := range securities {
+		byId[sm.SecurityId] = sm
+	}
+	return byId, nil
+}
+
+// checkInvestmentTransaction checks the fields the kind of transaction needs are set.
+func checkInvestmentTransaction(im *domain.InvestmentTransactionModel) error {
+	switch im.Kind {
+	case domain.INVESTMENT_BUY:
+		if im.Quantity <= 0 {
+			return errors.New("a buy needs a quantity")
+		}
+	case domain.INVESTMENT_SELL:
+		if im.Quantity <= 0 {
+			return errors.New("a sale needs a quantity")
+		}
+		if im.Method != domain.SPECIFIC_ID {
+			if len(im.Lots) > 0 {
+				return errors.New("only a sale by specific identification lists its lots")
+			}
+			return nil
+		}
+		var selected float64
+		for _, lot := range im.Lots {
+			selected += lot.Quantity
+		}
+		if math.Abs(selected-im.Quantity) > quantityEpsilon {
+			return fmt.Errorf("the lots add up to %v shares, the sale is for %v", selected, im.Quantity)
+		}
+	case domain.INVESTMENT_DIVIDEND:
+		if im.Amount <= 0 {
+			return errors.New("a dividend needs an amount")
+		}
+	case domain.INVESTMENT_SPLIT:
+		if im.SplitRatio <= 0 {
+			return errors.New("a split needs a ratio")
+		}
+	}
+	return nil
+}
+
+type holdingKey struct {
+	accountId  int64
+	securityId uuid.UUID
+}
+
+// investmentLot is the part of a buy that has not been sold. Splits change the quantity and
+// the cost per share, never the cost of the lot.
+type investmentLot struct {
+	id           uuid.UUID
+	acquiredAt   int64
+	quantity     float64
+	costPerShare float64
+}
+
+type investmentReplay struct {
+	lots      map[holdingKey][]*investmentLot // in the order they were bought.
+	keys      []holdingKey                    // in the order they were first bought.
+	lastPrice map[uuid.UUID]float64           // of the last trade in each security.
+	realized  []domain.RealizedGainDTO
+	dividends float64
+}
+
+// replayInvestments applies the investment transactions, ordered by date, to work out the
+// open lots and the gains on every sale. It fails on a sale of shares that are not held.
+func replayInvestments(investments []domain.InvestmentTransactionModel) (*investmentReplay, error) {
+	replay := &investmentReplay{lots: map[holdingKey][]*investmentLot{}, lastPrice: map[uuid.UUID]float64{}}
+	for i := range investments {
+		im := &investments[i]
+		key := holdingKey{accountId: im.AccountId, securityId: im.SecurityId}
+		switch im.Kind {
+		case domain.INVESTMENT_BUY:
+			if _, ok := replay.lots[key]; !ok {
+				replay.keys = append(replay.keys, key)
+			}
+			lot := &investmentLot{id: im.InvestmentId, acquiredAt: im.Date, quantity: im.Quantity, costPerShare: (im.Quantity*im.Price + im.Fees) / im.Quantity}
+			replay.lots[key] = append(replay.lots[key], lot)
+			replay.lastPrice[im.SecurityId] = im.Price
+		case domain.INVESTMENT_SELL:
+			err := replay.sell(key, im)
+			if err != nil {
+				return nil, err
+			}
+			replay.lastPrice[im.SecurityId] = im.Price
+		case domain.INVESTMENT_DIVIDEND:
+			replay.dividends += im.Amount
+		case domain.INVESTMENT_SPLIT:
+			for held, lots := range replay.lots {
+				if held.securityId != im.SecurityId {
+					continue
+				}
+				for _, lot := range lots {
+					lot.quantity *= im.SplitRatio
+					lot.costPerShare /= im.SplitRatio
+				}
+			}
+			replay.lastPrice[im.SecurityId] /= im.SplitRatio
+		}
+	}
+	return replay, nil
+}
+
+// sell takes the shares of the sale from the lots its method picks, oldest first for FIFO,
+// newest first for LIFO or the listed lots, and records the gain on each. The proceeds, net of
+// fees, are shared out by the number of shares taken from each lot.
+func (r *investmentReplay) sell(key holdingKey, im *domain.InvestmentTransactionModel) error {
+	lots := r.lots[key]
+	var held float64
+	for _, lot := range lots {
+		held += lot.quantity
+	}
+	if im.Quantity > held+quantityEpsilon {
+		return fmt.Errorf("%w: %v shares sold on %s, %v held", ErrInsufficientShares, im.Quantity, time.UnixMilli(im.Date).UTC().Format(domain.RateDateLayout), held)
+	}
+
+	proceeds := im.Quantity*im.Price - im.Fees
+	take := func(lot *investmentLot, quantity float64) {
+		cost := quantity * lot.costPerShare
+		share := proceeds * quantity / im.Quantity
+		r.realized = append(r.realized, domain.RealizedGainDTO{
+			SellId:     im.InvestmentId,
+			LotId:      lot.id,
+			AccountId:  key.accountId,
+			SecurityId: key.securityId,
+			Quantity:   quantity,
+			AcquiredAt: lot.acquiredAt,
+			SoldAt:     im.Date,
+			Proceeds:   roundCents(share),
+			CostBasis:  roundCents(cost),
+			Gain:       roundCents(share - cost),
+			LongTerm:   time.UnixMilli(im.Date).After(time.UnixMilli(lot.acquiredAt).AddDate(1, 0, 0)),
+		})
+		lot.quantity -= quantity
+	}
+
+	switch im.Method {
+	case domain.SPECIFIC_ID:
+		for _, selection := range im.Lots {
+			index := slices.IndexFunc(lots, func(lot *investmentLot) bool { return lot.id == selection.LotId })
+			if index < 0 || lots[index].quantity < selection.Quantity-quantityEpsilon {
+				return fmt.Errorf("%w: lot %v does not hold %v shares", ErrInsufficientShares, selection.LotId, selection.Quantity)
+			}
+			take(lots[index], min(selection.Quantity, lots[index].quantity))
+		}
+	default:
+		remaining := im.Quantity
+		for i := 0; i < len(lots) && remaining > quantityEpsilon; i++ {
+			lot := lots[i]
+			if im.Method == domain.LIFO {
+				lot = lots[len(lots)-1-i]
+			}
+			quantity := min(remaining, lot.quantity)
+			take(lot, quantity)
+			remaining -= quantity
+		}
+	}
+
+	open := lots[:0]
+	for _, lot := range lots {
+		if lot.quantity > quantityEpsilon {
+			open = append(open, lot)
+		}
+	}
+	r.lots[key] = open
+	return nil
+}
+
+// portfolioValues works out the market value of the shares held at the end of every day from
+// through to. Each security is valued at its latest price on or before the day, or at its last
+// trade price before any price is known.
+func portfolioValues(investments []domain.InvestmentTransactionModel, histories map[uuid.UUID][]domain.SecurityPriceModel, from time.Time, to time.Time) []float64 {
+	quantities := map[uuid.UUID]float64{}
+	lastPrice := map[uuid.UUID]float64{}
+	next := map[uuid.UUID]int{}
+
+	var values []float64
+	i := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		end := day.UnixMilli() + dayMillis - 1
+		for ; i < len(investments) && investments[i].Date <= end; i++ {
+			im := &investments[i]
+			switch im.Kind {
+			case domain.INVESTMENT_BUY:
+				quantities[im.SecurityId] += im.Quantity
+				lastPrice[im.SecurityId] = im.Price
+			case domain.INVESTMENT_SELL:
+				quantities[im.SecurityId] -= im.Quantity
+				lastPrice[im.SecurityId] = im.Price
+			case domain.INVESTMENT_SPLIT:
+				quantities[im.SecurityId] *= im.SplitRatio
+				lastPrice[im.SecurityId] /= im.SplitRatio
+			}
+		}
+
+		date := day.Format(domain.RateDateLayout)
+		var value float64
+		for securityId, quantity := range quantities {
+			history := histories[securityId]
+			for next[securityId] < len(history) && history[next[securityId]].Date <= date {
+				next[securityId]++
+			}
+			price := lastPrice[securityId]
+			if next[securityId] > 0 {
+				price = history[next[securityId]-1].Price
+			}
+			value += quantity * price
+		}
+		values = append(values, roundCents(value))
+	}
+	return values
+}
+
+func convertSecurityDTOToModel(from *domain.SecurityDTO) domain.SecurityModel {
+	return domain.SecurityModel{
+		SecurityId: from.SecurityId,
+		UserId:     from.UserId,
+		Symbol:     from.Symbol,
+		Name:       from.Name,
+		CreatedAt:  from.CreatedAt,
+	}
+}
+
+func convertSecurityModelToDTO(from *domain.SecurityModel) domain.SecurityDTO {
+	return domain.SecurityDTO{
+		SecurityId: from.SecurityId,
+		UserId:     from.UserId,
+		Symbol:     from.Symbol,
+		Name:       from.Name,
+		CreatedAt:  from.CreatedAt,
+	}
+}
+
+func convertInvestmentTransactionDTOToModel(from *domain.InvestmentTransactionDTO) domain.InvestmentTransactionModel {
+	lots := make([]domain.LotSelectionModel, 0, len(from.Lots))
+	for _, lot := range from.Lots {
+		lots = append(lots, domain.LotSelectionModel{LotId: lot.LotId, Quantity: lot.Quantity})
+	}
+	return domain.InvestmentTransactionModel{
+		InvestmentId: from.InvestmentId,
+		UserId:       from.UserId,
+		AccountId:    from.AccountId,
+		SecurityId:   from.SecurityId,
+		Kind:         from.Kind,
+		Date:         from.Date,
+		Quantity:     from.Quantity,
+		Price:        from.Price,
+		Fees:         from.Fees,
+		Amount:       from.Amount,
+		SplitRatio:   from.SplitRatio,
+		Method:       from.Method,
+		Lots:         lots,
+		CreatedAt:    from.CreatedAt,
+	}
+}
+
+func convertInvestmentTransactionModelToDTO(from *domain.InvestmentTransactionModel) domain.InvestmentTransactionDTO {
+	lots := make([]domain.LotSelectionDTO, 0, len(from.Lots))
+	for _, lot := range from.Lots {
+		lots = append(lots, domain.LotSelectionDTO{LotId: lot.LotId, Quantity: lot.Quantity})
+	}
+	return domain.InvestmentTransactionDTO{
+		InvestmentId: from.InvestmentId,
+		UserId:       from.UserId,
+		AccountId:    from.AccountId,
+		SecurityId:   from.SecurityId,
+		Kind:         from.Kind,
+		Date:         from.Date,
+		Quantity:     from.Quantity,
+		Price:        from.Price,
+		Fees:         from.Fees,
+		Amount:       from.Amount,
+		SplitRatio:   from.SplitRatio,
+		Method:       from.Method,
+		Lots:         lots,
+		CreatedAt:    from.CreatedAt,
+	}
+}
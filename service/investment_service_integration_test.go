@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpInvestmentModel(db *sql.DB) {
+	stmts := []string{
+		`create table security_model (
+			id integer primary key autoincrement,
+			security_id text not null,
+			user_id text not null,
+			symbol text not null,
+			name text not null,
+			created_at integer not null
+		)`,
+		`create table investment_transaction (
+			id integer primary key autoincrement,
+			investment_id text not null,
+			user_id text not null,
+			account_id integer not null,
+			security_id text not null,
+			kind integer not null,
+			date integer not null,
+			quantity float not null,
+			price float not null,
+			fees float not null,
+			amount float not null,
+			split_ratio float not null,
+			method integer not null,
+			lots text not null,
+			created_at integer not null
+		)`,
+		`create table security_price (
+			id integer primary key autoincrement,
+			symbol text not null,
+			date text not null,
+			price float not null
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		if err != nil {
+			log.Fatal("There was an error creating the investment tables:", err)
+		}
+	}
+}
+
+func TestPortfolio_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpInvestmentModel(db)
+	setUpNetWorthModel(db)
+	udb := database.SQLManager{DB: db}
+	investmentService := InvestmentService{IDBI: &udb}
+	netWorthService := NetWorthService{NDBI: &udb, TDBI: &udb, Investments: &investmentService}
+
+	userId := uuid.New()
+	security, err := investmentService.AddSecurity(context.Background(), &domain.SecurityData{Security: domain.SecurityDTO{UserId: userId, Symbol: " vwce ", Name: "All-World ETF"}, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding the security:", err)
+	}
+	if security.Symbol != "VWCE" {
+		t.Errorf("Expected the symbol to be normalized, got %q", security.Symbol)
+	}
+	_, err = investmentService.AddSecurity(context.Background(), &domain.SecurityData{Security: domain.SecurityDTO{UserId: userId, Symbol: "VWCE"}, Validator: validator.New()})
+	if !errors.Is(err, ErrSecurityExists) {
+		t.Errorf("Expected ErrSecurityExists for the same symbol, got %v", err)
+	}
+
+	today := startOfDay(time.Now())
+	add := func(investment domain.InvestmentTransactionDTO) (*domain.InvestmentTransactionDTO, error) {
+		investment.UserId = userId
+		investment.AccountId = 4
+		investment.SecurityId = security.SecurityId
+		return investmentService.AddInvestmentTransaction(context.Background(), &domain.InvestmentTransactionData{Investment: investment, Validator: validator.New()})
+	}
+	if _, err := add(domain.InvestmentTransactionDTO{Kind: domain.INVESTMENT_BUY, Date: today.AddDate(0, 0, -20).UnixMilli(), Quantity: 10, Price: 100, Fees: 5}); err != nil {
+		t.Fatal("Error adding the buy:", err)
+	}
+	second, err := add(domain.InvestmentTransactionDTO{Kind: domain.INVESTMENT_BUY, Date: today.AddDate(0, 0, -10).UnixMilli(), Quantity: 10, Price: 120})
+	if err != nil {
+		t.Fatal("Error adding the buy:", err)
+	}
+	if _, err := add(domain.InvestmentTransactionDTO{Kind: domain.INVESTMENT_DIVIDEND, Date: today.AddDate(0, 0, -8).UnixMilli(), Amount: 7.5}); err != nil {
+		t.Fatal("Error adding the dividend:", err)
+	}
+	if _, err := add(domain.InvestmentTransactionDTO{Kind: domain.INVESTMENT_SELL, Date: today.AddDate(0, 0, -5).UnixMilli(), Quantity: 25, Price: 130}); !errors.Is(err, ErrInsufficientShares) {
+		t.Errorf("Expected ErrInsufficientShares selling more than held, got %v", err)
+	}
+	if _, err := add(domain.InvestmentTransactionDTO{Kind: domain.INVESTMENT_SELL, Date: today.AddDate(0, 0, -5).UnixMilli(), Quantity: 4, Price: 130, Method: domain.LIFO}); err != nil {
+		t.Fatal("Error adding the sale:", err)
+	}
+
+	_, err = investmentService.ImportPrices([]byte("symbol,date,price\nVWCE," + today.AddDate(0, 0, -1).Format(domain.RateDateLayout) + ",140\n"))
+	if err != nil {
+		t.Fatal("Error importing the prices:", err)
+	}
+
+	portfolio, err := investmentService.RetrievePortfolio(userId, time.Now().UnixMilli())
+	if err != nil {
+		t.Fatal("Error retrieving the portfolio:", err)
+	}
+	if len(portfolio.Holdings) != 1 {
+		t.Fatalf("Expected one holding, got %+v", portfolio.Holdings)
+	}
+	holding := portfolio.Holdings[0]
+	if holding.Symbol != "VWCE" || holding.Quantity != 16 || holding.Price != 140 || len(holding.Lots) != 2 || holding.Lots[1].LotId != second.InvestmentId || holding.Lots[1].Quantity != 6 {
+		t.Errorf("Expected 16 shares left, 6 of the second lot, got %+v", holding)
+	}
+	if portfolio.CostBasis != 1725 || portfolio.MarketValue != 2240 || portfolio.UnrealizedGain != 515 || portfolio.RealizedGain != 40 || portfolio.Dividends != 7.5 {
+		t.Errorf("Unexpected portfolio totals %+v", portfolio)
+	}
+
+	gains, err := investmentService.RetrieveRealizedGains(userId, today.AddDate(0, 0, -6).UnixMilli(), time.Now().UnixMilli())
+	if err != nil {
+		t.Fatal("Error retrieving the realized gains:", err)
+	}
+	if len(gains) != 1 || gains[0].Symbol != "VWCE" || gains[0].LotId != second.InvestmentId || gains[0].Gain != 40 {
+		t.Errorf("Expected the LIFO sale from the second lot, got %+v", gains)
+	}
+
+	point, err := netWorthService.TakeSnapshot(context.Background(), userId)
+	if err != nil {
+		t.Fatal("Error taking the snapshot:", err)
+	}
+	if point.Assets != 2240 || point.NetWorth != 2240 {
+		t.Errorf("Expected the holdings in the net worth, got %+v", point)
+	}
+}
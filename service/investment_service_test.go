@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestReplayInvestments(t *testing.T) {
+	securityId := uuid.New()
+	day := func(year int, month time.Month, d int) int64 {
+		return time.Date(year, month, d, 15, 0, 0, 0, time.UTC).UnixMilli()
+	}
+	buy := func(date int64, quantity float64, price float64, fees float64) domain.InvestmentTransactionModel {
+		return domain.InvestmentTransactionModel{InvestmentId: uuid.New(), AccountId: 1, SecurityId: securityId, Kind: domain.INVESTMENT_BUY, Date: date, Quantity: quantity, Price: price, Fees: fees}
+	}
+	sell := func(date int64, quantity float64, price float64, method domain.CostBasisMethod, lots ...domain.LotSelectionModel) domain.InvestmentTransactionModel {
+		return domain.InvestmentTransactionModel{InvestmentId: uuid.New(), AccountId: 1, SecurityId: securityId, Kind: domain.INVESTMENT_SELL, Date: date, Quantity: quantity, Price: price, Method: method, Lots: lots}
+	}
+	first := buy(day(2024, time.January, 10), 10, 100, 10)
+	second := buy(day(2025, time.June, 10), 10, 150, 0)
+
+	tests := []struct {
+		name      string
+		sale      domain.InvestmentTransactionModel
+		wantGains []domain.RealizedGainDTO
+		wantOpen  []float64
+	}{
+		{
+			name: "FIFO sells the oldest lot first",
+			sale: sell(day(2025, time.July, 1), 15, 200, domain.FIFO),
+			wantGains: []domain.RealizedGainDTO{
+				{LotId: first.InvestmentId, Quantity: 10, Proceeds: 2000, CostBasis: 1010, Gain: 990, LongTerm: true},
+				{LotId: second.InvestmentId, Quantity: 5, Proceeds: 1000, CostBasis: 750, Gain: 250, LongTerm: false},
+			},
+			wantOpen: []float64{5},
+		},
+		{
+			name: "LIFO sells the newest lot first",
+			sale: sell(day(2025, time.July, 1), 15, 200, domain.LIFO),
+			wantGains: []domain.RealizedGainDTO{
+				{LotId: second.InvestmentId, Quantity: 10, Proceeds: 2000, CostBasis: 1500, Gain: 500, LongTerm: false},
+				{LotId: first.InvestmentId, Quantity: 5, Proceeds: 1000, CostBasis: 505, Gain: 495, LongTerm: true},
+			},
+			wantOpen: []float64{5},
+		},
+		{
+			name: "specific identification sells the listed lots",
+			sale: sell(day(2025, time.July, 1), 4, 200, domain.SPECIFIC_ID, domain.LotSelectionModel{LotId: second.InvestmentId, Quantity: 3}, domain.LotSelectionModel{LotId: first.InvestmentId, Quantity: 1}),
+			wantGains: []domain.RealizedGainDTO{
+				{LotId: second.InvestmentId, Quantity: 3, Proceeds: 600, CostBasis: 450, Gain: 150, LongTerm: false},
+				{LotId: first.InvestmentId, Quantity: 1, Proceeds: 200, CostBasis: 101, Gain: 99, LongTerm: true},
+			},
+			wantOpen: []float64{9, 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, err := replayInvestments([]domain.InvestmentTransactionModel{first, second, tt.sale})
+			if err != nil {
+				t.Fatal("Error replaying the investments:", err)
+			}
+			if len(replay.realized) != len(tt.wantGains) {
+				t.Fatalf("Expected %d gains, got %+v", len(tt.wantGains), replay.realized)
+			}
+			for i, want := range tt.wantGains {
+				got := replay.realized[i]
+				if got.LotId != want.LotId || got.Quantity != want.Quantity || got.Proceeds != want.Proceeds || got.CostBasis != want.CostBasis || got.Gain != want.Gain || got.LongTerm != want.LongTerm || got.SellId != tt.sale.InvestmentId {
+					t.Errorf("Wrong gain %d, got %+v, want %+v", i, got, want)
+				}
+			}
+			lots := replay.lots[holdingKey{accountId: 1, securityId: securityId}]
+			if len(lots) != len(tt.wantOpen) {
+				t.Fatalf("Expected %d open lots, got %d", len(tt.wantOpen), len(lots))
+			}
+			for i, quantity := range tt.wantOpen {
+				if lots[i].quantity != quantity {
+					t.Errorf("Expected lot %d to hold %v, got %v", i, quantity, lots[i].quantity)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayInvestments_SplitAndDividend(t *testing.T) {
+	securityId := uuid.New()
+	lotId := uuid.New()
+	investments := []domain.InvestmentTransactionModel{
+		{InvestmentId: lotId, SecurityId: securityId, Kind: domain.INVESTMENT_BUY, Date: 1, Quantity: 10, Price: 100},
+		{SecurityId: securityId, Kind: domain.INVESTMENT_DIVIDEND, Date: 2, Amount: 12.5},
+		{SecurityId: securityId, Kind: domain.INVESTMENT_SPLIT, Date: 3, SplitRatio: 2},
+		{InvestmentId: uuid.New(), SecurityId: securityId, Kind: domain.INVESTMENT_SELL, Date: 4, Quantity: 5, Price: 60, Method: domain.SPECIFIC_ID, Lots: []domain.LotSelectionModel{{LotId: lotId, Quantity: 5}}},
+	}
+
+	replay, err := replayInvestments(investments)
+	if err != nil {
+		t.Fatal("Error replaying the investments:", err)
+	}
+	lots := replay.lots[holdingKey{securityId: securityId}]
+	if len(lots) != 1 || lots[0].quantity != 15 || lots[0].costPerShare != 50 {
+		t.Errorf("Expected 15 shares at 50 left after the split, got %+v", lots[0])
+	}
+	if len(replay.realized) != 1 || replay.realized[0].CostBasis != 250 || replay.realized[0].Gain != 50 {
+		t.Errorf("Expected a gain of 50 on the split adjusted cost, got %+v", replay.realized)
+	}
+	if replay.dividends != 12.5 || replay.lastPrice[securityId] != 60 {
+		t.Errorf("Expected dividends of 12.5 and a last price of 60, got %v and %v", replay.dividends, replay.lastPrice[securityId])
+	}
+}
+
+func TestReplayInvestments_InsufficientShares(t *testing.T) {
+	securityId := uuid.New()
+	lotId := uuid.New()
+	buy := domain.InvestmentTransactionModel{InvestmentId: lotId, AccountId: 1, SecurityId: securityId, Kind: domain.INVESTMENT_BUY, Date: 1, Quantity: 10, Price: 100}
+	sales := map[string]domain.InvestmentTransactionModel{
+		"more than held":        {AccountId: 1, SecurityId: securityId, Kind: domain.INVESTMENT_SELL, Date: 2, Quantity: 11, Price: 100},
+		"other account":         {AccountId: 2, SecurityId: securityId, Kind: domain.INVESTMENT_SELL, Date: 2, Quantity: 1, Price: 100},
+		"more than the lot":     {AccountId: 1, SecurityId: securityId, Kind: domain.INVESTMENT_SELL, Date: 2, Quantity: 5, Price: 100, Method: domain.SPECIFIC_ID, Lots: []domain.LotSelectionModel{{LotId: lotId, Quantity: 5}, {LotId: lotId, Quantity: 6}}},
+		"lot that was not held": {AccountId: 1, SecurityId: securityId, Kind: domain.INVESTMENT_SELL, Date: 2, Quantity: 1, Price: 100, Method: domain.SPECIFIC_ID, Lots: []domain.LotSelectionModel{{LotId: uuid.New(), Quantity: 1}}},
+	}
+
+	for name, sale := range sales {
+		_, err := replayInvestments([]domain.InvestmentTransactionModel{buy, sale})
+		if !errors.Is(err, ErrInsufficientShares) {
+			t.Errorf("Expected ErrInsufficientShares for a sale of the %s, got %v", name, err)
+		}
+	}
+}
+
+func TestPortfolioValues(t *testing.T) {
+	securityId := uuid.New()
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+	investments := []domain.InvestmentTransactionModel{
+		{SecurityId: securityId, Kind: domain.INVESTMENT_BUY, Date: day(2).Add(10 * time.Hour).UnixMilli(), Quantity: 10, Price: 20},
+		{SecurityId: securityId, Kind: domain.INVESTMENT_SPLIT, Date: day(4).UnixMilli(), SplitRatio: 2},
+		{SecurityId: securityId, Kind: domain.INVESTMENT_SELL, Date: day(5).UnixMilli(), Quantity: 5, Price: 11},
+	}
+	histories := map[uuid.UUID][]domain.SecurityPriceModel{
+		securityId: {{Date: "2026-03-03", Price: 22}, {Date: "2026-03-04", Price: 12}},
+	}
+
+	values := portfolioValues(investments, histories, day(1), day(5))
+
+	want := []float64{0, 200, 220, 240, 180}
+	if len(values) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, values)
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("Wrong value on day %d, got %v, want %v", i+1, values[i], want[i])
+		}
+	}
+}
+
+func TestParseSecurityPrices(t *testing.T) {
+	prices, err := parseSecurityPrices([]byte("Date,Ticker,Close\n2026-03-02, vwce ,112.5\n2026-03-03,VWCE,113\n"))
+	if err != nil {
+		t.Fatal("Error parsing the prices:", err)
+	}
+	want := []domain.SecurityPriceModel{{Symbol: "VWCE", Date: "2026-03-02", Price: 112.5}, {Symbol: "VWCE", Date: "2026-03-03", Price: 113}}
+	if len(prices) != len(want) || prices[0] != want[0] || prices[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, prices)
+	}
+
+	for name, data := range map[string]string{
+		"empty":          "",
+		"missing column": "symbol,date\nVWCE,2026-03-02\n",
+		"bad date":       "symbol,date,price\nVWCE,03/02/2026,1\n",
+		"bad price":      "symbol,date,price\nVWCE,2026-03-02,0\n",
+		"no prices":      "symbol,date,price\n",
+	} {
+		if _, err := parseSecurityPrices([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// loanMaxMonths caps schedules whose payment barely covers the interest.
+const loanMaxMonths = 1200
+
+type LoanServiceInterface interface {
+	AddLoan(ctx context.Context, loanData *domain.LoanData) (*domain.LoanDTO, error)
+	RetrieveLoans(userId uuid.UUID) ([]domain.LoanDTO, error)
+	DeleteLoan(ctx context.Context, loanId uuid.UUID) error
+	RetrieveSchedule(loanId uuid.UUID) (*domain.AmortizationScheduleDTO, error)
+	RetrieveLoanStatus(loanId uuid.UUID) (*domain.LoanStatusDTO, error)
+	SimulatePayoff(loanId uuid.UUID, extraMonthly float64, lumpSum float64, lumpSumDate int64) (*domain.PayoffSimulationDTO, error)
+	LoanBalances(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error)
+}
+
+type LoanService struct {
+	LDBI database.LoanDatabaseInterface
+	TDBI database.TransactionDatabaseInterface
+}
+
+func (ls *LoanService) AddLoan(ctx context.Context, loanData *domain.LoanData) (*domain.LoanDTO, error) {
+	err := loanData.ValidateLoan()
+	if err != nil {
+		return nil, err
+	}
+
+	lm := convertLoanDTOToModel(&loanData.Loan)
+	lm.LoanId = uuid.New()
+	lm.Name = strings.TrimSpace(lm.Name)
+	lm.Match = strings.Join(normalizeDescription(lm.Match), " ")
+	if lm.PayeeId == uuid.Nil && lm.Match == "" {
+		return nil, errors.New("the loan needs a payee or a description to match its payments")
+	}
+	lm.CreatedAt = time.Now().UnixMilli()
+	err = ls.LDBI.AddLoan(ctx, &lm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertLoanModelToDTO(&lm)
+	return &saved, nil
+}
+
+func (ls *LoanService) RetrieveLoans(userId uuid.UUID) ([]domain.LoanDTO, error) {
+	loans, err := ls.LDBI.GetLoansByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	loanDTOs := make([]domain.LoanDTO, 0, len(loans))
+	for _, lm := range loans {
+		loanDTOs = append(loanDTOs, convertLoanModelToDTO(&lm))
+	}
+	return loanDTOs, nil
+}
+
+func (ls *LoanService) DeleteLoan(ctx context.Context, loanId uuid.UUID) error {
+	return ls.LDBI.DeleteLoan(ctx, loanId)
+}
+
+// RetrieveSchedule plans the loan from its start, with the regular payment and its extra payments.
+func (ls *LoanService) RetrieveSchedule(loanId uuid.UUID) (*domain.AmortizationScheduleDTO, error) {
+	lm, err := ls.LDBI.GetLoan(loanId)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := loanPayment(lm.Principal, lm.AnnualRate, lm.TermMonths)
+	rows := amortize(lm.Principal, lm.AnnualRate, payment, time.UnixMilli(lm.StartDate).UTC(), lm.ExtraMonthly, lm.ExtraPayments)
+	payoff := loanPayoff(rows)
+	return &domain.AmortizationScheduleDTO{LoanId: lm.LoanId, Payment: payment, Rows: rows, TotalInterest: payoff.TotalInterest, PayoffDate: payoff.PayoffDate}, nil
+}
+
+// RetrieveLoanStatus splits the payments made so far into interest and principal, and projects
+// the payoff date from the balance left.
+func (ls *LoanService) RetrieveLoanStatus(loanId uuid.UUID) (*domain.LoanStatusDTO, error) {
+	lm, err := ls.LDBI.GetLoan(loanId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	payments, err := ls.payments(&lm, now)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := loanPayment(lm.Principal, lm.AnnualRate, lm.TermMonths)
+	status := &domain.LoanStatusDTO{Loan: convertLoanModelToDTO(&lm), Payment: payment, Payments: payments, Balance: lm.Principal, ScheduledBalance: lm.Principal}
+	for _, paid := range payments {
+		status.PrincipalPaid += paid.Principal
+		status.InterestPaid += paid.Interest
+		status.Balance = paid.Balance
+	}
+	status.PrincipalPaid = roundCents(status.PrincipalPaid)
+	status.InterestPaid = roundCents(status.InterestPaid)
+
+	start := time.UnixMilli(lm.StartDate).UTC()
+	for _, row := range amortize(lm.Principal, lm.AnnualRate, payment, start, lm.ExtraMonthly, lm.ExtraPayments) {
+		if row.Date > now.UnixMilli() {
+			break
+		}
+		status.ScheduledBalance = row.Balance
+	}
+
+	projected := amortize(status.Balance, lm.AnnualRate, payment, lastDueDate(start, now), lm.ExtraMonthly, lm.ExtraPayments)
+	status.PayoffDate = loanPayoff(projected).PayoffDate
+	return status, nil
+}
+
+// SimulatePayoff pays the current balance off with the regular payment, first with the loans
+// own extra payments and then with ExtraMonthly and a LumpSum paid on LumpSumDate in their place.
+func (ls *LoanService) SimulatePayoff(loanId uuid.UUID, extraMonthly float64, lumpSum float64, lumpSumDate int64) (*domain.PayoffSimulationDTO, error) {
+	if extraMonthly < 0 || lumpSum < 0 {
+		return nil, errors.New("extra payments can not be negative")
+	}
+	status, err := ls.RetrieveLoanStatus(loanId)
+	if err != nil {
+		return nil, err
+	}
+
+	lm := convertLoanDTOToModel(&status.Loan)
+	from := lastDueDate(time.UnixMilli(lm.StartDate).UTC(), time.Now())
+	baseline := loanPayoff(amortize(status.Balance, lm.AnnualRate, status.Payment, from, lm.ExtraMonthly, lm.ExtraPayments))
+	var lumpSums []domain.ExtraPaymentModel
+	if lumpSum > 0 {
+		lumpSums = append(lumpSums, domain.ExtraPaymentModel{Date: lumpSumDate, Amount: lumpSum})
+	}
+	scenario := loanPayoff(amortize(status.Balance, lm.AnnualRate, status.Payment, from, extraMonthly, lumpSums))
+
+	return &domain.PayoffSimulationDTO{
+		LoanId:        lm.LoanId,
+		Balance:       status.Balance,
+		ExtraMonthly:  extraMonthly,
+		LumpSum:       lumpSum,
+		LumpSumDate:   lumpSumDate,
+		Baseline:      baseline,
+		Scenario:      scenario,
+		InterestSaved: roundCents(baseline.TotalInterest - scenario.TotalInterest),
+		MonthsSaved:   baseline.Payments - scenario.Payments,
+	}, nil
+}
+
+// LoanBalances returns what the user owed on all their loans at the end of every day from
+// through to, both UTC day starts, for the net worth. It is nil when the user has no loans.
+func (ls *LoanService) LoanBalances(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error) {
+	loans, err := ls.LDBI.GetLoansByUserId(userId)
+	if err != nil || len(loans) == 0 {
+		return nil, err
+	}
+	transactions, err := ls.TDBI.GetTransactionsByUserId(userId, 0, to.UnixMilli()+dayMillis-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []float64
+	for i := range loans {
+		payments := splitLoanPayments(&loans[i], matchLoanPayments(&loans[i], transactions))
+		p := 0
+		for d, day := 0, from; !day.After(to); d, day = d+1, day.AddDate(0, 0, 1) {
+			if d == len(balances) {
+				balances = append(balances, 0)
+			}
+			end := day.UnixMilli() + dayMillis - 1
+			if end < loans[i].StartDate {
+				continue
+			}
+			for ; p < len(payments) && payments[p].Date <= end; p++ {
+			}
+			balance := loans[i].Principal
+			if p > 0 {
+				balance = payments[p-1].Balance
+			}
+			balances[d] = roundCents(balances[d] + balance)
+		}
+	}
+	return balances, nil
+}
+
+func (ls *LoanService) payments(lm *domain.LoanModel, now time.Time) ([]domain.LoanPaymentDTO, error) {
+	transactions, err := ls.TDBI.GetTransactionsByUserId(lm.UserId, lm.StartDate, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	return splitLoanPayments(lm, matchLoanPayments(lm, transactions)), nil
+}
+
+// matchLoanPayments keeps the expenses, ordered by date, that pay the loan.
+func matchLoanPayments(lm *domain.LoanModel, transactions []domain.TransactionModel) []domain.TransactionModel {
+	var payments []domain.TransactionModel
+	for _, tm := range transactions {
+		if tm.Type != domain.EXPENSE || tm.Status == domain.CANCELLED || tm.Date < lm.StartDate {
+			continue
+		}
+		if lm.AccountId != 0 && tm.AccountId != lm.AccountId {
+			continue
+		}
+		if lm.PayeeId != uuid.Nil {
+			if tm.PayeeId != lm.PayeeId {
+				continue
+			}
+		} else if !strings.Contains(" "+strings.Join(normalizeDescription(tm.Description), " ")+" ", " "+lm.Match+" ") {
+			continue
+		}
+		payments = append(payments, tm)
+	}
+	return payments
+}
+
+// splitLoanPayments charges a month of interest on the balance with the first payment of each
+// month, the rest of it and any further payments that month go to the principal. Paying more
+// than is owed does not take the balance below 0.
+func splitLoanPayments(lm *domain.LoanModel, payments []domain.TransactionModel) []domain.LoanPaymentDTO {
+	balance := lm.Principal
+	rate := lm.AnnualRate / 100 / 12
+	lastMonth := ""
+
+	split := make([]domain.LoanPaymentDTO, 0, len(payments))
+	for _, tm := range payments {
+		var interest float64
+		if month := time.UnixMilli(tm.Date).UTC().Format(monthLayout); month != lastMonth {
+			interest = min(roundCents(balance*rate), tm.Amount)
+			lastMonth = month
+		}
+		principal := min(roundCents(tm.Amount-interest), balance)
+		balance = roundCents(balance - principal)
+		split = append(split, domain.LoanPaymentDTO{
+			TransactionId: tm.TransactionId,
+			Date:          tm.Date,
+			Amount:        tm.Amount,
+			Principal:     principal,
+			Interest:      interest,
+			Balance:       balance,
+		})
+	}
+	return split
+}
+
+// loanPayment is the equal monthly payment that repays the principal over the term.
+func loanPayment(principal float64, annualRate float64, months int) float64 {
+	rate := annualRate / 100 / 12
+	if rate == 0 {
+		return roundCents(principal / float64(months))
+	}
+	return roundCents(principal * rate / (1 - math.Pow(1+rate, -float64(months))))
+}
+
+// amortize plans the monthly payments of the balance, the first one month after from. Extra
+// payments are added to the payment of the month they fall in. The schedule stops early when
+// the payment does not cover the interest.
+func amortize(balance float64, annualRate float64, payment float64, from time.Time, extraMonthly float64, extraPayments []domain.ExtraPaymentModel) []domain.AmortizationRowDTO {
+	rate := annualRate / 100 / 12
+	previous := from.UnixMilli()
+
+	rows := []domain.AmortizationRowDTO{}
+	for n := 1; balance > 0 && n <= loanMaxMonths; n++ {
+		date := from.AddDate(0, n, 0).UnixMilli()
+		interest := roundCents(balance * rate)
+		principal := min(roundCents(payment-interest), balance)
+		if principal <= 0 {
+			break
+		}
+
+		extra := extraMonthly
+		for _, ep := range extraPayments {
+			if ep.Date > previous && ep.Date <= date {
+				extra += ep.Amount
+			}
+		}
+		extra = min(extra, roundCents(balance-principal))
+		balance = roundCents(balance - principal - extra)
+		rows = append(rows, domain.AmortizationRowDTO{Number: n, Date: date, Payment: roundCents(principal + interest), Principal: principal, Interest: interest, Extra: extra, Balance: balance})
+		previous = date
+	}
+	return rows
+}
+
+// loanPayoff sums up a schedule, the payoff date is 0 when it never pays the loan off.
+func loanPayoff(rows []domain.AmortizationRowDTO) domain.PayoffDTO {
+	payoff := domain.PayoffDTO{Payments: len(rows)}
+	for _, row := range rows {
+		payoff.TotalInterest += row.Interest
+	}
+	payoff.TotalInterest = roundCents(payoff.TotalInterest)
+	if len(rows) > 0 && rows[len(rows)-1].Balance == 0 {
+		payoff.PayoffDate = rows[len(rows)-1].Date
+	}
+	return payoff
+}
+
+// lastDueDate is the latest monthly anniversary of the start on or before now, the next
+// payment is due a month after it.
+func lastDueDate(start time.Time, now time.Time) time.Time {
+	due := start
+	for n := 1; !start.AddDate(0, n, 0).After(now); n++ {
+		due = start.AddDate(0, n, 0)
+	}
+	return due
+}
+
+func convertLoanDTOToModel(from *domain.LoanDTO) domain.LoanModel {
+	extraPayments := make([]domain.ExtraPaymentModel, 0, len(from.ExtraPayments))
+	for _, ep := range from.ExtraPayments {
+		extraPayments = append(extraPayments, domain.ExtraPaymentModel{Date: ep.Date, Amount: ep.Amount})
+	}
+	return domain.LoanModel{
+		LoanId:        from.LoanId,
+		UserId:        from.UserId,
+		Name:          from.Name,
+		Principal:     from.Principal,
+		AnnualRate:    from.AnnualRate,
+		TermMonths:    from.TermMonths,
+		StartDate:     from.StartDate,
+		AccountId:     from.AccountId,
+		PayeeId:       from.PayeeId,
+		Match:         from.Match,
+		ExtraMonthly:  from.ExtraMonthly,
+		ExtraPayments: extraPayments,
+		CreatedAt:     from.CreatedAt,
+	}
+}
+
+func convertLoanModelToDTO(from *domain.LoanModel) domain.LoanDTO {
+	extraPayments := make([]domain.ExtraPaymentDTO, 0, len(from.ExtraPayments))
+	for _, ep := range from.ExtraPayments {
+		extraPayments = append(extraPayments, domain.ExtraPaymentDTO{Date: ep.Date, Amount: ep.Amount})
+	}
+	return domain.LoanDTO{
+		LoanId:        from.LoanId,
+		UserId:        from.UserId,
+		Name:          from.Name,
+		Principal:     from.Principal,
+		AnnualRate:    from.AnnualRate,
+		TermMonths:    from.TermMonths,
+		StartDate:     from.StartDate,
+		AccountId:     from.AccountId,
+		PayeeId:       from.PayeeId,
+		Match:         from.Match,
+		ExtraMonthly:  from.ExtraMonthly,
+		ExtraPayments: extraPayments,
+		CreatedAt:     from.CreatedAt,
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpLoanModel(db *sql.DB) {
+	stmt := `create table loan_model (
+		id integer primary key autoincrement,
+		loan_id text not null,
+		user_id text not null,
+		name text not null,
+		principal float not null,
+		annual_rate float not null,
+		term_months integer not null,
+		start_date integer not null,
+		account_id integer not null,
+		payee_id text not null,
+		match_text text not null,
+		extra_monthly float not null,
+		extra_payments text not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating the loan table:", err)
+	}
+}
+
+func TestLoanStatus_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpLoanModel(db)
+	setUpNetWorthModel(db)
+	udb := database.SQLManager{DB: db}
+	loanService := LoanService{LDBI: &udb, TDBI: &udb}
+	netWorthService := NetWorthService{NDBI: &udb, TDBI: &udb, Loans: &loanService}
+
+	userId := uuid.New()
+	today := startOfDay(time.Now())
+	start := time.Date(today.Year(), today.Month()-3, 1, 0, 0, 0, 0, time.UTC)
+	loan := domain.LoanDTO{UserId: userId, Name: " Car ", Principal: 10000, AnnualRate: 12, TermMonths: 12, StartDate: start.UnixMilli(), AccountId: 2, Match: "Car loan"}
+	saved, err := loanService.AddLoan(context.Background(), &domain.LoanData{Loan: loan, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding the loan:", err)
+	}
+	if saved.Name != "Car" || saved.Match != "car loan" {
+		t.Errorf("Expected the name and match to be normalized, got %+v", saved)
+	}
+	_, err = loanService.AddLoan(context.Background(), &domain.LoanData{Loan: domain.LoanDTO{UserId: userId, Name: "No payee", Principal: 100, TermMonths: 1, StartDate: start.UnixMilli()}, Validator: validator.New()})
+	if err == nil {
+		t.Error("Expected an error for a loan without a payee or match")
+	}
+
+	for _, description := range []string{"CAR LOAN 0001", "Car loan 0002", "Carwash"} {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.AccountId = 2
+		tm.Amount = 888.49
+		tm.Type = domain.EXPENSE
+		tm.Status = domain.CLEARED
+		tm.Description = description
+		tm.Date = start.AddDate(0, 1, 0).UnixMilli()
+		if description != "CAR LOAN 0001" {
+			tm.Date = start.AddDate(0, 2, 0).UnixMilli()
+		}
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+
+	status, err := loanService.RetrieveLoanStatus(saved.LoanId)
+	if err != nil {
+		t.Fatal("Error retrieving the loan status:", err)
+	}
+	if status.Payment != 888.49 || len(status.Payments) != 2 || status.InterestPaid != 192.12 || status.PrincipalPaid != 1584.86 || status.Balance != 8415.14 {
+		t.Errorf("Expected two payments leaving 8415.14, got %+v", status)
+	}
+	if status.ScheduledBalance != 7610.8 {
+		t.Errorf("Expected the schedule to be three payments in, got %v", status.ScheduledBalance)
+	}
+	if status.PayoffDate <= today.UnixMilli() {
+		t.Errorf("Expected a payoff date in the future, got %v", status.PayoffDate)
+	}
+
+	schedule, err := loanService.RetrieveSchedule(saved.LoanId)
+	if err != nil {
+		t.Fatal("Error retrieving the schedule:", err)
+	}
+	if len(schedule.Rows) != 12 || schedule.TotalInterest != 661.86 {
+		t.Errorf("Expected 12 payments and 661.86 interest, got %d rows and %v", len(schedule.Rows), schedule.TotalInterest)
+	}
+
+	simulation, err := loanService.SimulatePayoff(saved.LoanId, 500, 1000, today.AddDate(0, 1, 0).UnixMilli())
+	if err != nil {
+		t.Fatal("Error simulating the payoff:", err)
+	}
+	if simulation.Balance != 8415.14 || simulation.MonthsSaved <= 0 || simulation.InterestSaved <= 0 || simulation.Scenario.PayoffDate >= simulation.Baseline.PayoffDate {
+		t.Errorf("Expected paying extra to save time and interest, got %+v", simulation)
+	}
+
+	point, err := netWorthService.TakeSnapshot(context.Background(), userId)
+	if err != nil {
+		t.Fatal("Error taking the snapshot:", err)
+	}
+	if point.Liabilities != roundCents(3*888.49+8415.14) {
+		t.Errorf("Expected the loan balance in the liabilities, got %+v", point)
+	}
+
+	if err := loanService.DeleteLoan(context.Background(), saved.LoanId); err != nil {
+		t.Fatal("Error deleting the loan:", err)
+	}
+	if _, err := loanService.RetrieveLoanStatus(saved.LoanId); err == nil {
+		t.Error("Expected an error retrieving a deleted loan")
+	}
+}
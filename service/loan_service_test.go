@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestLoanPayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  float64
+		annualRate float64
+		months     int
+		want       float64
+	}{
+		{"one year at 12%", 10000, 12, 12, 888.49},
+		{"thirty year mortgage", 200000, 6, 360, 1199.10},
+		{"interest free", 1200, 0, 12, 100},
+	}
+
+	for _, tt := range tests {
+		if got := loanPayment(tt.principal, tt.annualRate, tt.months); got != tt.want {
+			t.Errorf("%s: expected a payment of %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAmortize(t *testing.T) {
+	start := time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)
+
+	rows := amortize(10000, 12, 888.49, start, 0, nil)
+	payoff := loanPayoff(rows)
+	if len(rows) != 12 || payoff.TotalInterest != 661.86 || payoff.PayoffDate != start.AddDate(0, 12, 0).UnixMilli() {
+		t.Errorf("Expected 12 payments and 661.86 interest, got %+v", payoff)
+	}
+	if rows[0].Interest != 100 || rows[0].Principal != 788.49 || rows[0].Balance != 9211.51 || rows[0].Date != start.AddDate(0, 1, 0).UnixMilli() {
+		t.Errorf("Unexpected first row %+v", rows[0])
+	}
+	if last := rows[len(rows)-1]; last.Balance != 0 || last.Payment > 888.49 {
+		t.Errorf("Expected the last payment to clear the balance, got %+v", last)
+	}
+
+	lumpSum := []domain.ExtraPaymentModel{{Date: start.AddDate(0, 2, 10).UnixMilli(), Amount: 1000}}
+	rows = amortize(10000, 12, 888.49, start, 0, lumpSum)
+	payoff = loanPayoff(rows)
+	if len(rows) != 11 || payoff.TotalInterest != 570.22 || rows[2].Extra != 1000 || rows[2].Balance != 6610.8 {
+		t.Errorf("Expected the lump sum with the third payment, got %+v %+v", payoff, rows[2])
+	}
+
+	if rows := amortize(10000, 12, 100, start, 0, nil); len(rows) != 0 || loanPayoff(rows).PayoffDate != 0 {
+		t.Errorf("Expected no schedule when the payment only covers the interest, got %+v", rows)
+	}
+}
+
+func TestSplitLoanPayments(t *testing.T) {
+	lm := domain.LoanModel{Principal: 1000, AnnualRate: 12}
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	day := func(month time.Month, d int) int64 {
+		return time.Date(2026, month, d, 12, 0, 0, 0, time.UTC).UnixMilli()
+	}
+	payments := []domain.TransactionModel{
+		{TransactionId: ids[0], Date: day(time.February, 1), Amount: 510},
+		{TransactionId: ids[1], Date: day(time.February, 20), Amount: 100},
+		{TransactionId: ids[2], Date: day(time.March, 1), Amount: 600},
+	}
+
+	split := splitLoanPayments(&lm, payments)
+	want := []domain.LoanPaymentDTO{
+		{TransactionId: ids[0], Date: payments[0].Date, Amount: 510, Principal: 500, Interest: 10, Balance: 500},
+		{TransactionId: ids[1], Date: payments[1].Date, Amount: 100, Principal: 100, Interest: 0, Balance: 400},
+		{TransactionId: ids[2], Date: payments[2].Date, Amount: 600, Principal: 400, Interest: 4, Balance: 0},
+	}
+	for i := range want {
+		if split[i] != want[i] {
+			t.Errorf("Payment %d: expected %+v, got %+v", i, want[i], split[i])
+		}
+	}
+}
+
+func TestMatchLoanPayments(t *testing.T) {
+	payeeId := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	byMatch := domain.LoanModel{StartDate: 100, AccountId: 2, Match: "home mortgage"}
+	byPayee := domain.LoanModel{StartDate: 100, PayeeId: payeeId}
+	transactions := []domain.TransactionModel{
+		{TransactionId: ids[0], AccountId: 2, Date: 150, Type: domain.EXPENSE, Description: "HOME MORTGAGE 12345"},
+		{TransactionId: ids[1], AccountId: 3, Date: 150, Type: domain.EXPENSE, Description: "Home mortgage"},
+		{TransactionId: ids[2], AccountId: 2, Date: 50, Type: domain.EXPENSE, Description: "Home mortgage"},
+		{TransactionId: ids[3], AccountId: 2, Date: 150, Type: domain.INCOME, Description: "Home mortgage"},
+		{TransactionId: ids[4], AccountId: 2, Date: 150, Type: domain.EXPENSE, Description: "Home mortgages", PayeeId: payeeId},
+		{TransactionId: ids[5], AccountId: 2, Date: 150, Type: domain.EXPENSE, Status: domain.CANCELLED, Description: "Home mortgage", PayeeId: payeeId},
+	}
+
+	if got := matchLoanPayments(&byMatch, transactions); len(got) != 1 || got[0].TransactionId != ids[0] {
+		t.Errorf("Expected only the first transaction to match the description, got %+v", got)
+	}
+	if got := matchLoanPayments(&byPayee, transactions); len(got) != 1 || got[0].TransactionId != ids[4] {
+		t.Errorf("Expected only the fifth transaction to match the payee, got %+v", got)
+	}
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type NetWorthServiceInterface interface {
+	AddValuation(ctx context.Context, valuationData *domain.ValuationData) (*domain.ValuationDTO, error)
+	RetrieveValuations(userId uuid.UUID) ([]domain.ValuationDTO, error)
+	DeleteValuation(ctx context.Context, valuationId uuid.UUID) error
+	TakeSnapshot(ctx context.Context, userId uuid.UUID) (*domain.NetWorthPointDTO, error)
+	BackfillNetWorth(ctx context.Context, userId uuid.UUID) (*domain.NetWorthBackfillDTO, error)
+	RetrieveNetWorth(userId uuid.UUID, from int64, to int64, monthly bool) (*domain.NetWorthDTO, error)
+}
+
+type NetWorthService struct {
+	NDBI        database.NetWorthDatabaseInterface
+	TDBI        database.TransactionDatabaseInterface
+	Investments InvestmentServiceInterface // optional, adds the market value of the users holdings to their assets.
+	Loans       LoanServiceInterface       // optional, adds the balance owed on the users loans to their liabilities.
+}
+
+func (ns *NetWorthService) AddValuation(ctx context.Context, valuationData *domain.ValuationData) (*domain.ValuationDTO, error) {
+	err := valuationData.ValidateValuation()
+	if err != nil {
+		return nil, err
+	}
+
+	vm := convertValuationDTOToModel(&valuationData.Valuation)
+	vm.ValuationId = uuid.New()
+	vm.Name = strings.TrimSpace(vm.Name)
+	vm.CreatedAt = time.Now().UnixMilli()
+	err = ns.NDBI.AddValuation(ctx, &vm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertValuationModelToDTO(&vm)
+	return &saved, nil
+}
+
+func (ns *NetWorthService) RetrieveValuations(userId uuid.UUID) ([]domain.ValuationDTO, error) {
+	valuations, err := ns.NDBI.GetValuationsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	valuationDTOs := make([]domain.ValuationDTO, 0, len(valuations))
+	for _, vm := range valuations {
+		valuationDTOs = append(valuationDTOs, convertValuationModelToDTO(&vm))
+	}
+	return valuationDTOs, nil
+}
+
+func (ns *NetWorthService) DeleteValuation(ctx context.Context, valuationId uuid.UUID) error {
+	return ns.NDBI.DeleteValuation(ctx, valuationId)
+}
+
+// TakeSnapshot records the users net worth for today. Yesterday is recorded again as well so
+// transactions entered after the last snapshot of the day are not missed.
+func (ns *NetWorthService) TakeSnapshot(ctx context.Context, userId uuid.UUID) (*domain.NetWorthPointDTO, error) {
+	today := startOfDay(time.Now())
+	snapshots, err := ns.snapshot(ctx, userId, today.AddDate(0, 0, -1), today)
+	if err != nil {
+		return nil, err
+	}
+
+	point := convertNetWorthSnapshotToPoint(&snapshots[len(snapshots)-1])
+	return &point, nil
+}
+
+// BackfillNetWorth reconstructs a snapshot for every day from the users first transaction or
+// valuation up to today, replacing the snapshots already recorded.
+func (ns *NetWorthService) BackfillNetWorth(ctx context.Context, userId uuid.UUID) (*domain.NetWorthBackfillDTO, error) {
+	today := startOfDay(time.Now())
+	transactions, err := ns.TDBI.GetTransactionsByUserId(userId, 0, today.UnixMilli()+dayMillis-1)
+	if err != nil {
+		return nil, err
+	}
+	valuations, err := ns.NDBI.GetValuationsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	first := int64(math.MaxInt64)
+	if len(transactions) > 0 {
+		first = transactions[0].Date
+	}
+	if len(valuations) > 0 {
+		first = min(first, valuations[0].Date)
+	}
+	backfill := &domain.NetWorthBackfillDTO{UserId: userId}
+	if first > today.UnixMilli() {
+		return backfill, nil
+	}
+
+	from := startOfDay(time.UnixMilli(first))
+	investments, err := ns.portfolioValues(userId, from, today)
+	if err != nil {
+		return nil, err
+	}
+	loans, err := ns.loanBalances(userId, from, today)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := netWorthSnapshots(userId, transactions, valuations, investments, loans, from, today)
+	err = ns.NDBI.SaveNetWorthSnapshots(ctx, userId, snapshots)
+	if err != nil {
+		return nil, err
+	}
+
+	backfill.From = from.UnixMilli()
+	backfill.Snapshots = len(snapshots)
+	return backfill, nil
+}
+
+// RetrieveNetWorth returns the recorded snapshots between from and to, every day or only the
+// last snapshot of each month when monthly is set.
+func (ns *NetWorthService) RetrieveNetWorth(userId uuid.UUID, from int64, to int64, monthly bool) (*domain.NetWorthDTO, error) {
+	if to < from {
+		return nil, errors.New("the net worth range ends before it starts")
+	}
+
+	snapshots, err := ns.NDBI.GetNetWorthSnapshots(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	netWorth := &domain.NetWorthDTO{UserId: userId, From: from, To: to, Points: []domain.NetWorthPointDTO{}}
+	for i := range snapshots {
+		if monthly && i+1 < len(snapshots) && sameMonth(snapshots[i].Date, snapshots[i+1].Date) {
+			continue
+		}
+		netWorth.Points = append(netWorth.Points, convertNetWorthSnapshotToPoint(&snapshots[i]))
+	}
+	if len(netWorth.Points) > 0 {
+		netWorth.Change = roundCents(netWorth.Points[len(netWorth.Points)-1].NetWorth - netWorth.Points[0].NetWorth)
+	}
+	return netWorth, nil
+}
+
+// SnapshotAllUsers takes todays snapshot for every user with transactions or valuations, as the
+// system actor. A failure for one user is logged and does not stop the others.
+func (ns *NetWorthService) SnapshotAllUsers() error {
+	userIds, err := ns.NDBI.GetNetWorthUserIds()
+	if err != nil {
+		return err
+	}
+
+	for _, userId := range userIds {
+		if _, err := ns.TakeSnapshot(context.Background(), userId); err != nil {
+			log.Printf("Error taking the net worth snapshot of user %v: %v\n", userId, err)
+		}
+	}
+	return nil
+}
+
+// RunDailySnapshots snapshots every user right away and then shortly after each UTC midnight.
+// It never returns and is meant to run in its own goroutine.
+func (ns *NetWorthService) RunDailySnapshots() {
+	for {
+		if err := ns.SnapshotAllUsers(); err != nil {
+			log.Println("Error taking the daily net worth snapshots:", err)
+		}
+		time.Sleep(untilNextDailyRun(time.Now()))
+	}
+}
+
+// snapshot records the snapshots of the days from through to.
+func (ns *NetWorthService) snapshot(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]domain.NetWorthSnapshotModel, error) {
+	transactions, err := ns.TDBI.GetTransactionsByUserId(userId, 0, to.UnixMilli()+dayMillis-1)
+	if err != nil {
+		return nil, err
+	}
+	valuations, err := ns.NDBI.GetValuationsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	investments, err := ns.portfolioValues(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	loans, err := ns.loanBalances(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := netWorthSnapshots(userId, transactions, valuations, investments, loans, from, to)
+	err = ns.NDBI.SaveNetWorthSnapshots(ctx, userId, snapshots)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (ns *NetWorthService) portfolioValues(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error) {
+	if ns.Investments == nil {
+		return nil, nil
+	}
+	return ns.Investments.PortfolioValues(userId, from, to)
+}
+
+func (ns *NetWorthService) loanBalances(userId uuid.UUID, from time.Time, to time.Time) ([]float64, error) {
+	if ns.Loans == nil {
+		return nil, nil
+	}
+	return ns.Loans.LoanBalances(userId, from, to)
+}
+
+// netWorthSnapshots works out the net worth at the end of every day from through to, both UTC
+// day starts. Transactions and valuations must be ordered by date. Account balances are the
+// sum of the transactions up to that day, cancelled ones left out, and each valuation name
+// counts with its latest value. investments holds the market value of the users holdings on
+// each day and loans the balance owed on their loans, either is nil for users without any.
+func netWorthSnapshots(userId uuid.UUID, transactions []domain.TransactionModel, valuations []domain.ValuationModel, investments []float64, loans []float64, from time.Time, to time.Time) []domain.NetWorthSnapshotModel {
+	balances := map[int64]float64{}
+	latest := map[string]domain.ValuationModel{}
+	createdAt := time.Now().UnixMilli()
+
+	var snapshots []domain.NetWorthSnapshotModel
+	t, v := 0, 0
+	for i, day := 0, from; !day.After(to); i, day = i+1, day.AddDate(0, 0, 1) {
+		end := day.UnixMilli() + dayMillis - 1
+		for ; t < len(transactions) && transactions[t].Date <= end; t++ {
+			if transactions[t].Status != domain.CANCELLED {
+				balances[transactions[t].AccountId] += signedAmount(transactions[t].Type, transactions[t].Amount)
+			}
+		}
+		for ; v < len(valuations) && valuations[v].Date <= end; v++ {
+			latest[strings.ToLower(strings.TrimSpace(valuations[v].Name))] = valuations[v]
+		}
+
+		var assets, liabilities float64
+		for _, balance := range balances {
+			if balance > 0 {
+				assets += balance
+			} else {
+				liabilities -= balance
+			}
+		}
+		for _, vm := range latest {
+			if vm.Kind == domain.VALUATION_LIABILITY {
+				liabilities += vm.Amount
+			} else {
+				assets += vm.Amount
+			}
+		}
+		if i < len(investments) {
+			assets += investments[i]
+		}
+		if i < len(loans) {
+			liabilities += loans[i]
+		}
+
+		snapshots = append(snapshots, domain.NetWorthSnapshotModel{
+			UserId:      userId,
+			Date:        day.UnixMilli(),
+			Assets:      roundCents(assets),
+			Liabilities: roundCents(liabilities),
+			NetWorth:    roundCents(assets - liabilities),
+			CreatedAt:   createdAt,
+		})
+	}
+	return snapshots
+}
+
+// untilNextDailyRun is how long daily jobs wait from now, they run a few minutes after UTC midnight.
+func untilNextDailyRun(now time.Time) time.Duration {
+	return startOfDay(now).AddDate(0, 0, 1).Add(5 * time.Minute).Sub(now)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func sameMonth(a, b int64) bool {
+	return time.UnixMilli(a).UTC().Format(monthLayout) == time.UnixMilli(b).UTC().Format(monthLayout)
+}
+
+func convertNetWorthSnapshotToPoint(from *domain.NetWorthSnapshotModel) domain.NetWorthPointDTO {
+	return domain.NetWorthPointDTO{
+		Date:        from.Date,
+		Assets:      from.Assets,
+		Liabilities: from.Liabilities,
+		NetWorth:    from.NetWorth,
+	}
+}
+
+func convertValuationDTOToModel(from *domain.ValuationDTO) domain.ValuationModel {
+	return domain.ValuationModel{
+		ValuationId: from.ValuationId,
+		UserId:      from.UserId,
+		Name:        from.Name,
+		Kind:        from.Kind,
+		Amount:      from.Amount,
+		Date:        from.Date,
+		CreatedAt:   from.CreatedAt,
+	}
+}
+
+func convertValuationModelToDTO(from *domain.ValuationModel) domain.ValuationDTO {
+	return domain.ValuationDTO{
+		ValuationId: from.ValuationId,
+		UserId:      from.UserId,
+		Name:        from.Name,
+		Kind:        from.Kind,
+		Amount:      from.Amount,
+		Date:        from.Date,
+		CreatedAt:   from.CreatedAt,
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpNetWorthModel(db *sql.DB) {
+	stmts := []string{
+		`create table valuation_model (
+			id integer primary key autoincrement,
+			valuation_id text not null,
+			user_id text not null,
+			name text not null,
+			kind integer not null,
+			amount float not null,
+			date integer not null,
+			created_at integer not null
+		)`,
+		`create table net_worth_snapshot (
+			id integer primary key autoincrement,
+			user_id text not null,
+			date integer not null,
+			assets float not null,
+			liabilities float not null,
+			net_worth float not null,
+			created_at integer not null
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		if err != nil {
+			log.Fatal("There was an error creating the net worth tables:", err)
+		}
+	}
+}
+
+func TestNetWorthHistory_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpNetWorthModel(db)
+	udb := database.SQLManager{DB: db}
+	netWorthService := NetWorthService{NDBI: &udb, TDBI: &udb}
+
+	userId := uuid.New()
+	today := startOfDay(time.Now())
+	start := time.Date(today.Year(), today.Month()-2, 1, 0, 0, 0, 0, time.UTC)
+	add := func(date time.Time, amount float64, transactionType domain.TransactionType) {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.AccountId = 1
+		tm.Date = date.Add(12 * time.Hour).UnixMilli()
+		tm.Amount = amount
+		tm.Type = transactionType
+		tm.Status = domain.CLEARED
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+	add(start, 1000, domain.INCOME)
+	add(start.AddDate(0, 1, 0), 400, domain.EXPENSE)
+
+	car := domain.ValuationDTO{UserId: userId, Name: "Car", Kind: domain.VALUATION_ASSET, Amount: 8000, Date: start.AddDate(0, 0, 10).UnixMilli()}
+	if _, err := netWorthService.AddValuation(context.Background(), &domain.ValuationData{Valuation: car, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the valuation:", err)
+	}
+
+	backfill, err := netWorthService.BackfillNetWorth(context.Background(), userId)
+	if err != nil {
+		t.Fatal("Error backfilling the net worth:", err)
+	}
+	wantDays := int((today.UnixMilli()-start.UnixMilli())/dayMillis) + 1
+	if backfill.From != start.UnixMilli() || backfill.Snapshots != wantDays {
+		t.Errorf("Expected %d snapshots from %v, got %+v", wantDays, start.UnixMilli(), backfill)
+	}
+
+	daily, err := netWorthService.RetrieveNetWorth(userId, 0, today.UnixMilli(), false)
+	if err != nil {
+		t.Fatal("Error retrieving the net worth:", err)
+	}
+	if len(daily.Points) != wantDays {
+		t.Fatalf("Expected a point for every day, got %d", len(daily.Points))
+	}
+	if first := daily.Points[0]; first.Date != start.UnixMilli() || first.NetWorth != 1000 {
+		t.Errorf("Wrong first point, got %+v", first)
+	}
+	if last := daily.Points[len(daily.Points)-1]; last.Assets != 8600 || last.NetWorth != 8600 || daily.Change != 7600 {
+		t.Errorf("Wrong last point, got %+v with change %v", last, daily.Change)
+	}
+
+	monthly, err := netWorthService.RetrieveNetWorth(userId, 0, today.UnixMilli(), true)
+	if err != nil {
+		t.Fatal("Error retrieving the net worth:", err)
+	}
+	if len(monthly.Points) != 3 {
+		t.Fatalf("Expected a point for each of the 3 months, got %+v", monthly.Points)
+	}
+	for _, point := range monthly.Points[:2] {
+		next := time.UnixMilli(point.Date).UTC().AddDate(0, 0, 1)
+		if next.Day() != 1 {
+			t.Errorf("Expected the last day of the month, got %v", time.UnixMilli(point.Date).UTC())
+		}
+	}
+
+	// a snapshot taken later replaces todays backfilled one instead of adding another.
+	add(today, 100, domain.EXPENSE)
+	point, err := netWorthService.TakeSnapshot(context.Background(), userId)
+	if err != nil {
+		t.Fatal("Error taking the snapshot:", err)
+	}
+	if point.Date != today.UnixMilli() || point.NetWorth != 8500 {
+		t.Errorf("Wrong snapshot, got %+v", point)
+	}
+	snapshots, err := udb.GetNetWorthSnapshots(userId, today.UnixMilli(), today.UnixMilli())
+	if err != nil {
+		t.Fatal("Error retrieving the snapshots:", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].NetWorth != 8500 {
+		t.Errorf("Expected todays snapshot to be replaced, got %+v", snapshots)
+	}
+
+	userIds, err := udb.GetNetWorthUserIds()
+	if err != nil || len(userIds) != 1 || userIds[0] != userId {
+		t.Errorf("Expected the user to be snapshotted daily, got %v, %v", userIds, err)
+	}
+}
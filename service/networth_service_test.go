@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestNetWorthSnapshots(t *testing.T) {
+	userId := uuid.New()
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+	transaction := func(d int, accountId int64, amount float64, transactionType domain.TransactionType, status domain.TransactionStatus) domain.TransactionModel {
+		return domain.TransactionModel{AccountId: accountId, Date: day(d).Add(15 * time.Hour).UnixMilli(), Amount: amount, Type: transactionType, Status: status}
+	}
+	transactions := []domain.TransactionModel{
+		transaction(1, 1, 2000, domain.INCOME, domain.CLEARED),
+		transaction(2, 2, 300, domain.EXPENSE, domain.CLEARED),
+		transaction(2, 1, 5000, domain.EXPENSE, domain.CANCELLED),
+		transaction(3, 2, 300, domain.INCOME, domain.PENDING),
+	}
+	valuations := []domain.ValuationModel{
+		{Name: "House", Kind: domain.VALUATION_ASSET, Amount: 250000, Date: day(2).UnixMilli()},
+		{Name: "Mortgage", Kind: domain.VALUATION_LIABILITY, Amount: 180000, Date: day(2).UnixMilli()},
+		{Name: "house ", Kind: domain.VALUATION_ASSET, Amount: 260000, Date: day(3).UnixMilli()},
+		{Name: "Mortgage", Kind: domain.VALUATION_LIABILITY, Amount: 0, Date: day(4).UnixMilli()},
+	}
+
+	snapshots := netWorthSnapshots(userId, transactions, valuations, nil, nil, day(1), day(4))
+
+	want := []domain.NetWorthSnapshotModel{
+		{Date: day(1).UnixMilli(), Assets: 2000, Liabilities: 0, NetWorth: 2000},
+		{Date: day(2).UnixMilli(), Assets: 252000, Liabilities: 180300, NetWorth: 71700},
+		{Date: day(3).UnixMilli(), Assets: 262000, Liabilities: 180000, NetWorth: 82000},
+		{Date: day(4).UnixMilli(), Assets: 262000, Liabilities: 0, NetWorth: 262000},
+	}
+	if len(snapshots) != len(want) {
+		t.Fatalf("Expected %d snapshots, got %+v", len(want), snapshots)
+	}
+	for i, w := range want {
+		got := snapshots[i]
+		if got.UserId != userId || got.Date != w.Date || got.Assets != w.Assets || got.Liabilities != w.Liabilities || got.NetWorth != w.NetWorth {
+			t.Errorf("Wrong snapshot for day %d, got %+v, want %+v", i+1, got, w)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type PayeeServiceInterface interface {
+	AddPayee(ctx context.Context, payeeData *domain.PayeeData) (*domain.PayeeDTO, error)
+	RetrievePayees(userId uuid.UUID) ([]domain.PayeeDTO, error)
+	ResolvePayee(tm *domain.TransactionModel) (*domain.PayeeModel, func(saved bool), error)
+	MergePayees(ctx context.Context, mergeData *domain.PayeeMergeData) error
+	RetrievePayeeSpending(userId uuid.UUID, from int64, to int64) ([]domain.PayeeSpendingDTO, error)
+}
+
+type PayeeService struct {
+	PDBI database.PayeeDatabaseInterface
+	TDBI database.TransactionDatabaseInterface
+
+	Reconciler ReconciliationServiceInterface // optional, refuses to merge payees with reconciled transactions.
+	Events     EventBusInterface              // optional, saves transaction.updated events for the transactions moved by a merge.
+
+	mu      sync.Mutex
+	aliases map[uuid.UUID]*userAliases // the compiled aliases of each user, loaded on first use.
+}
+
+// userAliases are the compiled aliases of a users payees. Its lock also stops two transactions
+// of the user from creating the same payee. Aliases changed by another process are only seen
+// after the payees of the user change in this one.
+type userAliases struct {
+	mu     sync.Mutex
+	loaded bool
+	payees []payeeAliases
+}
+
+type payeeAliases struct {
+	payeeId  uuid.UUID
+	patterns []*regexp.Regexp
+}
+
+func (ps *PayeeService) AddPayee(ctx context.Context, payeeData *domain.PayeeData) (*domain.PayeeDTO, error) {
+	err := payeeData.ValidatePayee()
+	if err != nil {
+		return nil, err
+	}
+
+	pm := convertPayeeDTOToModel(&payeeData.Payee)
+	if len(pm.Aliases) == 0 {
+		pm.Aliases = []string{exactAlias(descriptionKey(pm.Name))}
+	}
+	_, err = compileAliases(&pm)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ps.PDBI.AddPayee(ctx, &pm)
+	if err != nil {
+		return nil, err
+	}
+	ps.invalidateAliases(pm.UserId)
+	payeeDTO := convertPayeeModelToDTO(&pm)
+	return &payeeDTO, nil
+}
+
+func (ps *PayeeService) RetrievePayees(userId uuid.UUID) ([]domain.PayeeDTO, error) {
+	payees, err := ps.PDBI.GetPayeesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	payeeDTOs := make([]domain.PayeeDTO, 0, len(payees))
+	for _, pm := range payees {
+		payeeDTOs = append(payeeDTOs, convertPayeeModelToDTO(&pm))
+	}
+	return payeeDTOs, nil
+}
+
+// ResolvePayee sets the PayeeId of a new transaction from its raw description.
+// The first payee with a matching alias is used, otherwise a payee is made for the description and
+// returned for the caller to save with the transaction. The payees of the user stay locked until
+// done is called with whether the payee was saved, so two transactions do not make the same payee.
+func (ps *PayeeService) ResolvePayee(tm *domain.TransactionModel) (*domain.PayeeModel, func(saved bool), error) {
+	unlocked := func(saved bool) {}
+	key := descriptionKey(tm.Description)
+	if key == "" {
+		return nil, unlocked, nil
+	}
+
+	ua := ps.userAliases(tm.UserId)
+	ua.mu.Lock()
+
+	if !ua.loaded {
+		err := ps.loadAliases(tm.UserId, ua)
+		if err != nil {
+			ua.mu.Unlock()
+			return nil, nil, err
+		}
+	}
+	for _, payee := range ua.payees {
+		for _, pattern := range payee.patterns {
+			if pattern.MatchString(key) {
+				ua.mu.Unlock()
+				tm.PayeeId = payee.payeeId
+				return nil, unlocked, nil
+			}
+		}
+	}
+
+	pm := domain.PayeeModel{
+		PayeeId:   uuid.New(),
+		UserId:    tm.UserId,
+		Name:      payeeName(key),
+		Aliases:   []string{exactAlias(key)},
+		CreatedAt: time.Now().UnixMilli(),
+	}
+	patterns, err := compileAliases(&pm)
+	if err != nil {
+		ua.mu.Unlock()
+		return nil, nil, err
+	}
+	tm.PayeeId = pm.PayeeId
+	done := func(saved bool) {
+		if saved {
+			ua.payees = append(ua.payees, payeeAliases{payeeId: pm.PayeeId, patterns: patterns})
+		}
+		ua.mu.Unlock()
+	}
+	return &pm, done, nil
+}
+
+// MergePayees moves the transactions of the source payee to the target, which takes on the
+// aliases of the source, and removes the source. Nothing is merged while one of the transactions
+// is reconciled.
+func (ps *PayeeService) MergePayees(ctx context.Context, mergeData *domain.PayeeMergeData) error {
+	err := mergeData.ValidatePayeeMerge()
+	if err != nil {
+		return err
+	}
+	merge := mergeData.Merge
+	if merge.SourcePayeeId == merge.TargetPayeeId {
+		return errors.New("cannot merge a payee into itself")
+	}
+
+	source, err := ps.PDBI.GetPayee(merge.SourcePayeeId)
+	if err != nil {
+		return err
+	}
+	target, err := ps.PDBI.GetPayee(merge.TargetPayeeId)
+	if err != nil {
+		return err
+	}
+	if source.UserId != target.UserId {
+		return errors.New("payees belong to different users")
+	}
+
+	for _, alias := range source.Aliases {
+		if !slices.Contains(target.Aliases, alias) {
+			target.Aliases = append(target.Aliases, alias)
+		}
+	}
+
+	ua := ps.userAliases(target.UserId)
+	ua.mu.Lock()
+	defer ua.mu.Unlock()
+	ua.loaded = false
+
+	transactions, err := ps.TDBI.GetTransactionsByUserId(source.UserId, math.MinInt64, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	var moved []domain.TransactionModel
+	var events []domain.OutboxEventModel
+	for _, tm := range transactions {
+		if tm.PayeeId != source.PayeeId {
+			continue
+		}
+		if ps.Reconciler != nil {
+			err = ps.Reconciler.CheckUnlocked(tm.TransactionId)
+			if err != nil {
+				return err
+			}
+		}
+		tm.PayeeId = target.PayeeId
+		tm.UpdatedAt = now
+		event, err := recordEvent(ps.Events, domain.EVENT_TRANSACTION_UPDATED, tm.UserId, tm.TransactionId, convertTransactionModelToDTO(&tm))
+		if err != nil {
+			return err
+		}
+		moved = append(moved, tm)
+		events = append(events, event...)
+	}
+	return ps.PDBI.MergePayees(ctx, source.PayeeId, &target, moved, events...)
+}
+
+func (ps *PayeeService) RetrievePayeeSpending(userId uuid.UUID, from int64, to int64) ([]domain.PayeeSpendingDTO, error) {
+	return ps.PDBI.GetPayeeSpending(userId, from, to)
+}
+
+func (ps *PayeeService) userAliases(userId uuid.UUID) *userAliases {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.aliases == nil {
+		ps.aliases = map[uuid.UUID]*userAliases{}
+	}
+	ua, ok := ps.aliases[userId]
+	if !ok {
+		ua = &userAliases{}
+		ps.aliases[userId] = ua
+	}
+	return ua
+}
+
+// loadAliases compiles the aliases of the users payees, the caller holds the lock of ua.
+func (ps *PayeeService) loadAliases(userId uuid.UUID, ua *userAliases) error {
+	payees, err := ps.PDBI.GetPayeesByUserId(userId)
+	if err != nil {
+		return err
+	}
+	ua.payees = make([]payeeAliases, 0, len(payees))
+	for _, pm := range payees {
+		patterns, err := compileAliases(&pm)
+		if err != nil {
+			return err
+		}
+		ua.payees = append(ua.payees, payeeAliases{payeeId: pm.PayeeId, patterns: patterns})
+	}
+	ua.loaded = true
+	return nil
+}
+
+// invalidateAliases makes the next transaction of the user load the aliases again.
+func (ps *PayeeService) invalidateAliases(userId uuid.UUID) {
+	ua := ps.userAliases(userId)
+	ua.mu.Lock()
+	defer ua.mu.Unlock()
+	ua.loaded = false
+}
+
+// referenceMarkers start the reference part of a description, which is left out of its key
+// together with everything after it.
+var referenceMarkers = map[string]bool{"ref": true, "reference": true, "txn": true, "trx": true, "auth": true, "conf": true, "confirmation": true, "inv": true, "invoice": true}
+
+// descriptionKey is the normalized description that aliases are matched against,
+// without its reference codes. Those are words with digits, words of five letters or more
+// without a vowel, and everything from a reference marker on. "AMZN Mktp US*2K3L" becomes
+// "amzn mktp us", "SPOTIFY PXKQZT" becomes "spotify" and "NETFLIX.COM REF ABCDEF" becomes
+// "netflix com".
+func descriptionKey(description string) string {
+	var words []string
+	for _, word := range normalizeDescription(description) {
+		if referenceMarkers[word] {
+			break
+		}
+		if len(word) >= 5 && !strings.ContainsAny(word, "aeiouy") {
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+func exactAlias(key string) string {
+	return "^" + regexp.QuoteMeta(key) + "$"
+}
+
+// payeeName capitalizes each word of a description key.
+func payeeName(key string) string {
+	words := strings.Fields(key)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func compileAliases(pm *domain.PayeeModel) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(pm.Aliases))
+	for _, alias := range pm.Aliases {
+		pattern, err := regexp.Compile("(?i)" + alias)
+		if err != nil {
+			return nil, fmt.Errorf("payee %q has an invalid alias: %w", pm.Name, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+func convertPayeeDTOToModel(from *domain.PayeeDTO) domain.PayeeModel {
+	return domain.PayeeModel{
+		PayeeId:   uuid.New(),
+		UserId:    from.UserId,
+		Name:      from.Name,
+		Aliases:   from.Aliases,
+		CreatedAt: time.Now().UnixMilli(),
+	}
+}
+
+func convertPayeeModelToDTO(from *domain.PayeeModel) domain.PayeeDTO {
+	return domain.PayeeDTO{
+		PayeeId:   from.PayeeId,
+		UserId:    from.UserId,
+		Name:      from.Name,
+		Aliases:   from.Aliases,
+		CreatedAt: from.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestPayeeNormalizationAndSpending_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	_, err := db.Exec(`create table payee_model (
+		id integer primary key autoincrement,
+		payee_id text not null,
+		user_id text not null,
+		name text not null,
+		aliases text not null,
+		created_at integer not null
+	)`)
+	if err != nil {
+		log.Fatal("There was an error creating payee_model table:", err)
+	}
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+	eventBus := EventBus{OBDBI: &udb}
+	var updated []domain.EventDTO
+	eventBus.Subscribe("counter", domain.EVENT_TRANSACTION_UPDATED, func(event *domain.EventDTO) error {
+		updated = append(updated, *event)
+		return nil
+	})
+	payeeService := PayeeService{PDBI: &udb, TDBI: &udb, Events: &eventBus}
+	transactionService := TransactionService{UDBI: &udb, Payees: &payeeService}
+
+	first := domain.TransactionDTOBuilder().WithDescription("AMZN Mktp US*2K3L").Build()
+	second := domain.TransactionDTOBuilder().WithDescription("AMAZON.COM*MK12").Build()
+	cancelled := domain.TransactionDTOBuilder().WithDescription("AMAZON.COM*ZZ99").Build()
+	for i, transaction := range []*domain.TransactionDTO{&first, &second, &cancelled} {
+		transaction.UserId = first.UserId
+		transaction.Amount = float64(10 * (i + 1))
+		transaction.Type = domain.EXPENSE
+		transaction.Status = domain.CLEARED
+	}
+	cancelled.Status = domain.CANCELLED
+
+	var results []*domain.TransactionResultDTO
+	for _, transaction := range []domain.TransactionDTO{first, second, cancelled} {
+		result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+		if err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+		results = append(results, result)
+	}
+
+	// two payees were created, the second amazon description reused the first.
+	if results[0].Transaction.PayeeId == results[1].Transaction.PayeeId || results[1].Transaction.PayeeId != results[2].Transaction.PayeeId {
+		t.Fatalf("Unexpected payees, got %v", results)
+	}
+
+	merge := domain.PayeeMergeDTO{SourcePayeeId: results[0].Transaction.PayeeId, TargetPayeeId: results[1].Transaction.PayeeId}
+	err = payeeService.MergePayees(context.Background(), &domain.PayeeMergeData{Merge: merge, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error merging payees:", err)
+	}
+
+	// only the transaction of the source payee moved, with an event.
+	if err := eventBus.DispatchPending(time.Now().Add(time.Second)); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	var data domain.TransactionDTO
+	if len(updated) != 1 || updated[0].AggregateId != results[0].Transaction.TransactionId {
+		t.Fatalf("Unexpected updated events %+v", updated)
+	}
+	if err := json.Unmarshal(updated[0].Data, &data); err != nil || data.PayeeId != merge.TargetPayeeId {
+		t.Errorf("Expected the event to have the target payee, got %s", updated[0].Data)
+	}
+
+	spending, err := payeeService.RetrievePayeeSpending(first.UserId, 0, 100)
+	if err != nil {
+		t.Fatal("Error retrieving payee spending:", err)
+	}
+	if len(spending) != 1 || spending[0].Count != 2 || spending[0].Total != 30 || spending[0].Name != "Amazon Com" {
+		t.Fatalf("Wrong payee spending, got %v", spending)
+	}
+
+	// the merged alias now matches new transactions.
+	third := domain.TransactionDTOBuilder().WithDescription("AMZN Mktp US*9QQ1").Build()
+	third.UserId = first.UserId
+	result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: third, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+	if result.Transaction.PayeeId != merge.TargetPayeeId {
+		t.Errorf("Expected the merged payee, got %v", result.Transaction.PayeeId)
+	}
+}
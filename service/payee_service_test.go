@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type StubPayeeDatabase struct {
+	StubDatabase
+	payees       []domain.PayeeModel
+	loads        int
+	transactions []domain.TransactionModel
+	moved        []domain.TransactionModel
+	insertErr    error
+}
+
+func (m *StubPayeeDatabase) AddPayee(ctx context.Context, pm *domain.PayeeModel) error {
+	m.payees = append(m.payees, *pm)
+	return nil
+}
+
+func (m *StubPayeeDatabase) GetPayee(payeeId uuid.UUID) (domain.PayeeModel, error) {
+	for _, pm := range m.payees {
+		if pm.PayeeId == payeeId {
+			return pm, nil
+		}
+	}
+	return domain.PayeeModel{}, nil
+}
+
+func (m *StubPayeeDatabase) GetPayeesByUserId(userId uuid.UUID) ([]domain.PayeeModel, error) {
+	m.loads++
+	return m.payees, nil
+}
+
+func (m *StubPayeeDatabase) MergePayees(ctx context.Context, sourceId uuid.UUID, target *domain.PayeeModel, moved []domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	m.moved = moved
+	var payees []domain.PayeeModel
+	for _, pm := range m.payees {
+		switch pm.PayeeId {
+		case sourceId:
+			continue
+		case target.PayeeId:
+			pm = *target
+		}
+		payees = append(payees, pm)
+	}
+	m.payees = payees
+	return nil
+}
+
+func (m *StubPayeeDatabase) AddTransactionWith(ctx context.Context, tm *domain.TransactionModel, payee *domain.PayeeModel, tags []domain.TagModel, events ...domain.OutboxEventModel) error {
+	if m.insertErr != nil {
+		return m.insertErr
+	}
+	if payee != nil {
+		m.payees = append(m.payees, *payee)
+	}
+	m.transactions = append(m.transactions, *tm)
+	return nil
+}
+
+func (m *StubPayeeDatabase) GetTransactionsByUserId(userId uuid.UUID, from int64, to int64) ([]domain.TransactionModel, error) {
+	return m.transactions, nil
+}
+
+func (m *StubPayeeDatabase) GetPayeeSpending(userId uuid.UUID, from int64, to int64) ([]domain.PayeeSpendingDTO, error) {
+	return []domain.PayeeSpendingDTO{}, nil
+}
+
+func TestResolvePayee(t *testing.T) {
+	userId := uuid.New()
+	amazon := domain.PayeeModelBuilder().WithUserId(userId).WithName("Amazon").WithAliases(`^amzn\b`, `^amazon`).Build()
+	stubDB := &StubPayeeDatabase{payees: []domain.PayeeModel{amazon}}
+	payeeService := PayeeService{PDBI: stubDB}
+
+	tests := []struct {
+		name        string
+		description string
+		wantPayee   string
+		wantPayees  int
+	}{
+		{name: "First alias", description: "AMZN Mktp US*2K3L", wantPayee: "Amazon", wantPayees: 1},
+		{name: "Second alias", description: "AMAZON.COM*MK12", wantPayee: "Amazon", wantPayees: 1},
+		{name: "New payee", description: "TST* Blue Bottle 0042", wantPayee: "Tst Blue Bottle", wantPayees: 2},
+		{name: "Created payee reused", description: "TST*BLUE BOTTLE 9913", wantPayee: "Tst Blue Bottle", wantPayees: 2},
+		{name: "Only reference numbers", description: "12345 #6789", wantPayee: "", wantPayees: 2},
+		{name: "Reference marker", description: "NETFLIX.COM REF QXBWJT", wantPayee: "Netflix Com", wantPayees: 3},
+		{name: "Reference marker with vowels", description: "NETFLIX.COM REF ABCDEF", wantPayee: "Netflix Com", wantPayees: 3},
+		{name: "Reference code", description: "SPOTIFY PXKQZT", wantPayee: "Spotify", wantPayees: 4},
+		{name: "Other reference code", description: "Spotify HJKLMN", wantPayee: "Spotify", wantPayees: 4},
+		{name: "Transaction marker", description: "TST* BLUE BOTTLE TXN AUXQEI", wantPayee: "Tst Blue Bottle", wantPayees: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tm := domain.TransactionModelBuilder().Build()
+			tm.UserId = userId
+			tm.PayeeId = uuid.Nil
+			tm.Description = test.description
+
+			payee, done, err := payeeService.ResolvePayee(&tm)
+			if err != nil {
+				t.Fatal("Error resolving payee:", err)
+			}
+			if payee != nil {
+				stubDB.payees = append(stubDB.payees, *payee)
+			}
+			done(true)
+
+			name := ""
+			for _, pm := range stubDB.payees {
+				if pm.PayeeId == tm.PayeeId {
+					name = pm.Name
+				}
+			}
+			if name != test.wantPayee {
+				t.Errorf("Wrong payee, got %q, want %q", name, test.wantPayee)
+			}
+			if len(stubDB.payees) != test.wantPayees {
+				t.Errorf("Wrong number of payees, got %d, want %d", len(stubDB.payees), test.wantPayees)
+			}
+		})
+	}
+}
+
+func TestResolvePayee_CachesAliases(t *testing.T) {
+	userId := uuid.New()
+	stubDB := &StubPayeeDatabase{}
+	payeeService := PayeeService{PDBI: stubDB}
+
+	resolve := func(description string) uuid.UUID {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.Description = description
+		payee, done, err := payeeService.ResolvePayee(&tm)
+		if err != nil {
+			t.Fatal("Error resolving payee:", err)
+		}
+		if payee != nil {
+			stubDB.payees = append(stubDB.payees, *payee)
+		}
+		done(true)
+		return tm.PayeeId
+	}
+
+	created := resolve("BLUE BOTTLE 0042")
+	if resolve("BLUE BOTTLE 9913") != created {
+		t.Error("Expected the created payee to be cached")
+	}
+	if stubDB.loads != 1 {
+		t.Errorf("Expected the aliases to be loaded once, got %d", stubDB.loads)
+	}
+
+	payee := domain.PayeeDTO{UserId: userId, Name: "Coffee", Aliases: []string{"^blue bottle"}}
+	_, err := payeeService.AddPayee(context.Background(), &domain.PayeeData{Payee: payee, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding payee:", err)
+	}
+	resolve("BLUE BOTTLE 7731")
+	if stubDB.loads != 2 {
+		t.Errorf("Expected adding a payee to reload the aliases, got %d loads", stubDB.loads)
+	}
+}
+
+func TestAddTransaction_FailedInsertLeavesNoPayee(t *testing.T) {
+	stubDB := &StubPayeeDatabase{insertErr: errors.New("insert failed")}
+	payeeService := PayeeService{PDBI: stubDB}
+	transactionService := TransactionService{UDBI: stubDB, Payees: &payeeService}
+
+	add := func() error {
+		transaction := domain.TransactionDTOBuilder().WithDescription("BLUE BOTTLE 0042").Build()
+		_, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+		return err
+	}
+
+	if add() == nil {
+		t.Fatal("Expected the failed insert to fail the transaction")
+	}
+	if len(stubDB.payees) != 0 {
+		t.Fatalf("Expected no payee to be saved, got %v", stubDB.payees)
+	}
+
+	stubDB.insertErr = nil
+	err := add()
+	if err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+	if len(stubDB.payees) != 1 || stubDB.transactions[0].PayeeId != stubDB.payees[0].PayeeId {
+		t.Fatalf("Expected the payee to be saved with the transaction, got %v", stubDB.payees)
+	}
+}
+
+func TestMergePayees(t *testing.T) {
+	userId := uuid.New()
+	source := domain.PayeeModelBuilder().WithUserId(userId).WithAliases("^amzn mktp us$").Build()
+	target := domain.PayeeModelBuilder().WithUserId(userId).WithAliases("^amazon").Build()
+	otherUser := domain.PayeeModelBuilder().Build()
+	moved := domain.TransactionModelBuilder().Build()
+	moved.PayeeId = source.PayeeId
+	stubDB := &StubPayeeDatabase{payees: []domain.PayeeModel{source, target, otherUser}, transactions: []domain.TransactionModel{moved, domain.TransactionModelBuilder().Build()}}
+	payeeService := PayeeService{PDBI: stubDB, TDBI: stubDB}
+
+	merge := domain.PayeeMergeDTO{SourcePayeeId: otherUser.PayeeId, TargetPayeeId: target.PayeeId}
+	err := payeeService.MergePayees(context.Background(), &domain.PayeeMergeData{Merge: merge, Validator: validator.New()})
+	if err == nil {
+		t.Fatal("Expected an error merging payees of different users")
+	}
+
+	merge = domain.PayeeMergeDTO{SourcePayeeId: source.PayeeId, TargetPayeeId: source.PayeeId}
+	err = payeeService.MergePayees(context.Background(), &domain.PayeeMergeData{Merge: merge, Validator: validator.New()})
+	if err == nil {
+		t.Fatal("Expected an error merging a payee into itself")
+	}
+
+	merge = domain.PayeeMergeDTO{SourcePayeeId: source.PayeeId, TargetPayeeId: target.PayeeId}
+	err = payeeService.MergePayees(context.Background(), &domain.PayeeMergeData{Merge: merge, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error merging payees:", err)
+	}
+	if len(stubDB.payees) != 2 {
+		t.Fatalf("Expected the source payee to be removed, got %v", stubDB.payees)
+	}
+	merged, _ := stubDB.GetPayee(target.PayeeId)
+	if len(merged.Aliases) != 2 || merged.Aliases[1] != "^amzn mktp us$" {
+		t.Errorf("Expected the source aliases on the target, got %v", merged.Aliases)
+	}
+	if len(stubDB.moved) != 1 || stubDB.moved[0].TransactionId != moved.TransactionId || stubDB.moved[0].PayeeId != target.PayeeId {
+		t.Errorf("Expected only the source transaction to move to the target, got %v", stubDB.moved)
+	}
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+var (
+	ErrReconciliationOpen       = errors.New("the account already has a reconciliation in progress")
+	ErrReconciliationLocked     = errors.New("the reconciliation is locked")
+	ErrReconciliationUnbalanced = errors.New("the cleared transactions do not add up to the ending balance")
+	ErrReconciliationNotLatest  = errors.New("only the latest reconciliation of the account can be unlocked")
+	ErrTransactionReconciled    = errors.New("the transaction is reconciled, unlock its reconciliation to change it")
+)
+
+type ReconciliationServiceInterface interface {
+	StartReconciliation(ctx context.Context, reconciliationData *domain.ReconciliationData) (*domain.ReconciliationDTO, error)
+	RetrieveReconciliations(userId uuid.UUID, accountId int64) ([]domain.ReconciliationDTO, error)
+	RetrieveReconciliation(reconciliationId uuid.UUID) (*domain.ReconciliationDetailDTO, error)
+	UpdateCleared(ctx context.Context, updateData *domain.ClearUpdateData) (*domain.ReconciliationDetailDTO, error)
+	FinishReconciliation(ctx context.Context, reconciliationId uuid.UUID) (*domain.ReconciliationDetailDTO, error)
+	UnlockReconciliation(ctx context.Context, reconciliationId uuid.UUID) (*domain.ReconciliationDTO, error)
+	DeleteReconciliation(ctx context.Context, reconciliationId uuid.UUID) error
+	CheckUnlocked(transactionId uuid.UUID) error
+}
+
+type ReconciliationService struct {
+	RCDBI database.ReconciliationDatabaseInterface
+	TDBI  database.TransactionDatabaseInterface
+
+	Currencies CurrencyServiceInterface // optional, balances accounts with a currency in that currency, the base currency is used without it.
+	Events     EventBusInterface        // optional, saves transaction.updated events for the transactions cleared or uncleared.
+}
+
+// StartReconciliation opens a reconciliation of the account against a new statement. It starts
+// from the ending balance of the last reconciled statement, or 0 for the first one.
+func (rs *ReconciliationService) StartReconciliation(ctx context.Context, reconciliationData *domain.ReconciliationData) (*domain.ReconciliationDTO, error) {
+	err := reconciliationData.ValidateReconciliation()
+	if err != nil {
+		return nil, err
+	}
+
+	rm := convertReconciliationDTOToModel(&reconciliationData.Reconciliation)
+	reconciliations, err := rs.RCDBI.GetReconciliationsByAccount(rm.UserId, rm.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	rm.StartingBalance = 0
+	for _, previous := range reconciliations {
+		if previous.Status == domain.RECONCILIATION_IN_PROGRESS {
+			return nil, ErrReconciliationOpen
+		}
+		if previous.StatementDate >= rm.StatementDate {
+			return nil, errors.New("the statement must end after the last reconciled statement")
+		}
+		rm.StartingBalance = previous.EndingBalance
+	}
+
+	rm.ReconciliationId = uuid.New()
+	rm.Status = domain.RECONCILIATION_IN_PROGRESS
+	rm.CreatedAt = time.Now().UnixMilli()
+	rm.ReconciledAt = 0
+	err = rs.RCDBI.AddReconciliation(ctx, &rm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertReconciliationModelToDTO(&rm)
+	return &saved, nil
+}
+
+func (rs *ReconciliationService) RetrieveReconciliations(userId uuid.UUID, accountId int64) ([]domain.ReconciliationDTO, error) {
+	reconciliations, err := rs.RCDBI.GetReconciliationsByAccount(userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliationDTOs := make([]domain.ReconciliationDTO, 0, len(reconciliations))
+	for _, rm := range reconciliations {
+		reconciliationDTOs = append(reconciliationDTOs, convertReconciliationModelToDTO(&rm))
+	}
+	return reconciliationDTOs, nil
+}
+
+func (rs *ReconciliationService) RetrieveReconciliation(reconciliationId uuid.UUID) (*domain.ReconciliationDetailDTO, error) {
+	rm, err := rs.RCDBI.GetReconciliation(reconciliationId)
+	if err != nil {
+		return nil, err
+	}
+	return rs.detail(&rm)
+}
+
+// UpdateCleared marks transactions of the account up to the statement date as cleared in the
+// reconciliation, or takes them back out. Their status follows, CLEARED or back to PENDING.
+func (rs *ReconciliationService) UpdateCleared(ctx context.Context, updateData *domain.ClearUpdateData) (*domain.ReconciliationDetailDTO, error) {
+	err := updateData.ValidateClearUpdate()
+	if err != nil {
+		return nil, err
+	}
+	update := updateData.Update
+
+	rm, err := rs.RCDBI.GetReconciliation(update.ReconciliationId)
+	if err != nil {
+		return nil, err
+	}
+	if rm.Status != domain.RECONCILIATION_IN_PROGRESS {
+		return nil, ErrReconciliationLocked
+	}
+	detail, err := rs.detail(&rm)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := map[uuid.UUID]domain.TransactionStatus{}
+	for _, transactions := range [][]domain.TransactionDTO{detail.Cleared, detail.Uncleared} {
+		for _, transaction := range transactions {
+			candidates[transaction.TransactionId] = transaction.Status
+		}
+	}
+	for _, transactionId := range update.TransactionIds {
+		if _, ok := candidates[transactionId]; !ok {
+			return nil, fmt.Errorf("transaction %v can not be cleared in this reconciliation", transactionId)
+		}
+	}
+
+	err = rs.RCDBI.SetTransactionsCleared(ctx, rm.ReconciliationId, update.TransactionIds, update.Cleared)
+	if err != nil {
+		return nil, err
+	}
+
+	status := domain.PENDING
+	if update.Cleared {
+		status = domain.CLEARED
+	}
+	for _, transactionId := range update.TransactionIds {
+		if candidates[transactionId] == status {
+			continue
+		}
+		tm, err := rs.TDBI.GetTransaction(transactionId)
+		if err != nil {
+			return nil, err
+		}
+		tm.Status = status
+		tm.UpdatedAt = time.Now().UnixMilli()
+		events, err := recordEvent(rs.Events, domain.EVENT_TRANSACTION_UPDATED, tm.UserId, tm.TransactionId, convertTransactionModelToDTO(&tm))
+		if err != nil {
+			return nil, err
+		}
+		err = rs.TDBI.UpdateTransaction(ctx, &tm, events...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rs.detail(&rm)
+}
+
+// FinishReconciliation locks the reconciliation once the difference is 0.
+func (rs *ReconciliationService) FinishReconciliation(ctx context.Context, reconciliationId uuid.UUID) (*domain.ReconciliationDetailDTO, error) {
+	rm, err := rs.RCDBI.GetReconciliation(reconciliationId)
+	if err != nil {
+		return nil, err
+	}
+	if rm.Status != domain.RECONCILIATION_IN_PROGRESS {
+		return nil, ErrReconciliationLocked
+	}
+	detail, err := rs.detail(&rm)
+	if err != nil {
+		return nil, err
+	}
+	if math.Abs(detail.Difference) >= 0.005 {
+		return nil, fmt.Errorf("%w: %.2f left", ErrReconciliationUnbalanced, detail.Difference)
+	}
+
+	rm.Status = domain.RECONCILED
+	rm.ReconciledAt = time.Now().UnixMilli()
+	err = rs.RCDBI.UpdateReconciliationStatus(ctx, rm.ReconciliationId, rm.Status, rm.ReconciledAt)
+	if err != nil {
+		return nil, err
+	}
+	detail.Reconciliation = convertReconciliationModelToDTO(&rm)
+	return detail, nil
+}
+
+// UnlockReconciliation puts a locked reconciliation back in progress so its transactions can be
+// changed. Later reconciliations start from its ending balance, so only the latest one of the
+// account can be unlocked.
+func (rs *ReconciliationService) UnlockReconciliation(ctx context.Context, reconciliationId uuid.UUID) (*domain.ReconciliationDTO, error) {
+	rm, err := rs.RCDBI.GetReconciliation(reconciliationId)
+	if err != nil {
+		return nil, err
+	}
+	if rm.Status != domain.RECONCILED {
+		return nil, errors.New("the reconciliation is not locked")
+	}
+	reconciliations, err := rs.RCDBI.GetReconciliationsByAccount(rm.UserId, rm.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if reconciliations[len(reconciliations)-1].ReconciliationId != rm.ReconciliationId {
+		return nil, ErrReconciliationNotLatest
+	}
+
+	rm.Status = domain.RECONCILIATION_IN_PROGRESS
+	rm.ReconciledAt = 0
+	err = rs.RCDBI.UpdateReconciliationStatus(ctx, rm.ReconciliationId, rm.Status, rm.ReconciledAt)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked := convertReconciliationModelToDTO(&rm)
+	return &unlocked, nil
+}
+
+// DeleteReconciliation abandons a reconciliation in progress. The transactions keep their status.
+func (rs *ReconciliationService) DeleteReconciliation(ctx context.Context, reconciliationId uuid.UUID) error {
+	rm, err := rs.RCDBI.GetReconciliation(reconciliationId)
+	if err != nil {
+		return err
+	}
+	if rm.Status != domain.RECONCILIATION_IN_PROGRESS {
+		return ErrReconciliationLocked
+	}
+	return rs.RCDBI.DeleteReconciliation(ctx, reconciliationId)
+}
+
+// CheckUnlocked returns ErrTransactionReconciled when the transaction is cleared in a locked reconciliation.
+func (rs *ReconciliationService) CheckUnlocked(transactionId uuid.UUID) error {
+	reconciled, err := rs.RCDBI.IsTransactionReconciled(transactionId)
+	if err != nil {
+		return err
+	}
+	if reconciled {
+		return ErrTransactionReconciled
+	}
+	return nil
+}
+
+// detail splits the transactions of the account up to the end of the statement date into those
+// cleared in the reconciliation and those still to clear. Cancelled transactions and those
+// reconciled against an earlier statement are left out. The balance is in the currency of the
+// statement, see statementAmount.
+func (rs *ReconciliationService) detail(rm *domain.ReconciliationModel) (*domain.ReconciliationDetailDTO, error) {
+	end := startOfDay(time.UnixMilli(rm.StatementDate)).UnixMilli() + dayMillis - 1
+	transactions, err := rs.TDBI.GetTransactionsByUserId(rm.UserId, math.MinInt64, end)
+	if err != nil {
+		return nil, err
+	}
+	var currency, base string
+	if rs.Currencies != nil {
+		currency, err = rs.Currencies.RetrieveAccountCurrency(rm.UserId, rm.AccountId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if currency != "" {
+		base, err = rs.Currencies.RetrieveBaseCurrency(rm.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	clearedIds, err := rs.RCDBI.GetClearedTransactionIds(rm.ReconciliationId)
+	if err != nil {
+		return nil, err
+	}
+	reconciledIds, err := rs.RCDBI.GetReconciledTransactionIds(rm.UserId, rm.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	cleared := map[uuid.UUID]bool{}
+	for _, transactionId := range clearedIds {
+		cleared[transactionId] = true
+	}
+	reconciled := map[uuid.UUID]bool{}
+	for _, transactionId := range reconciledIds {
+		reconciled[transactionId] = true
+	}
+
+	detail := &domain.ReconciliationDetailDTO{
+		Reconciliation: convertReconciliationModelToDTO(rm),
+		Cleared:        []domain.TransactionDTO{},
+		Uncleared:      []domain.TransactionDTO{},
+		ClearedBalance: rm.StartingBalance,
+	}
+	for _, tm := range transactions {
+		if tm.AccountId != rm.AccountId || tm.Status == domain.CANCELLED {
+			continue
+		}
+		switch {
+		case cleared[tm.TransactionId]:
+			amount, err := rs.statementAmount(&tm, currency, base)
+			if err != nil {
+				return nil, err
+			}
+			detail.Cleared = append(detail.Cleared, convertTransactionModelToDTO(&tm))
+			detail.ClearedBalance += signedAmount(tm.Type, amount)
+		case !reconciled[tm.TransactionId]:
+			detail.Uncleared = append(detail.Uncleared, convertTransactionModelToDTO(&tm))
+		}
+	}
+	detail.ClearedBalance = roundCents(detail.ClearedBalance)
+	detail.Difference = roundCents(rm.EndingBalance - detail.ClearedBalance)
+	return detail, nil
+}
+
+// statementAmount is the amount of the transaction in the currency of the account statement. In
+// an account with a currency that is the original amount, converted at the transaction date when
+// the transaction is in another currency. Otherwise it is the amount in the base currency.
+func (rs *ReconciliationService) statementAmount(tm *domain.TransactionModel, accountCurrency string, base string) (float64, error) {
+	if accountCurrency == "" {
+		return tm.Amount, nil
+	}
+	// transactions saved before they had a currency are in the base currency.
+	from, amount := tm.Currency, tm.OriginalAmount
+	if from == "" {
+		from, amount = base, tm.Amount
+	}
+	if from == accountCurrency {
+		return amount, nil
+	}
+	conversion, err := rs.Currencies.Convert(amount, from, accountCurrency, tm.Date)
+	if err != nil {
+		return 0, err
+	}
+	return conversion.Converted, nil
+}
+
+func convertReconciliationDTOToModel(from *domain.ReconciliationDTO) domain.ReconciliationModel {
+	return domain.ReconciliationModel{
+		ReconciliationId: from.ReconciliationId,
+		UserId:           from.UserId,
+		AccountId:        from.AccountId,
+		StatementDate:    from.StatementDate,
+		StartingBalance:  from.StartingBalance,
+		EndingBalance:    from.EndingBalance,
+		Status:           from.Status,
+		CreatedAt:        from.CreatedAt,
+		ReconciledAt:     from.ReconciledAt,
+	}
+}
+
+func convertReconciliationModelToDTO(from *domain.ReconciliationModel) domain.ReconciliationDTO {
+	return domain.ReconciliationDTO{
+		ReconciliationId: from.ReconciliationId,
+		UserId:           from.UserId,
+		AccountId:        from.AccountId,
+		StatementDate:    from.StatementDate,
+		StartingBalance:  from.StartingBalance,
+		EndingBalance:    from.EndingBalance,
+		Status:           from.Status,
+		CreatedAt:        from.CreatedAt,
+		ReconciledAt:     from.ReconciledAt,
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpReconciliationModel(db *sql.DB) {
+	stmts := []string{
+		`create table reconciliation_session (
+			id integer primary key autoincrement,
+			reconciliation_id text not null,
+			user_id text not null,
+			account_id integer not null,
+			statement_date integer not null,
+			starting_balance float not null,
+			ending_balance float not null,
+			status integer not null,
+			created_at integer not null,
+			reconciled_at integer not null
+		)`,
+		`create table reconciliation_transaction (
+			reconciliation_id text not null,
+			transaction_id text not null,
+			primary key (reconciliation_id, transaction_id)
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		if err != nil {
+			log.Fatal("There was an error creating the reconciliation tables:", err)
+		}
+	}
+}
+
+func TestReconciliation_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpReconciliationModel(db)
+	setUpCurrencyModel(db)
+	udb := database.SQLManager{DB: db}
+	reconciliationService := ReconciliationService{RCDBI: &udb, TDBI: &udb}
+	transactionService := TransactionService{UDBI: &udb, Reconciler: &reconciliationService}
+	currencyService := CurrencyService{CRDBI: &udb, TDBI: &udb, Reconciler: &reconciliationService}
+
+	userId := uuid.New()
+	statementDate := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)
+	add := func(transactionType domain.TransactionType, amount float64, date time.Time, accountId int64) domain.TransactionModel {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.AccountId = accountId
+		tm.Type = transactionType
+		tm.Status = domain.PENDING
+		tm.Amount = amount
+		tm.Date = date.UnixMilli()
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+		return tm
+	}
+	salary := add(domain.INCOME, 1000, statementDate.AddDate(0, 0, -20), 2)
+	rent := add(domain.EXPENSE, 600, statementDate.AddDate(0, 0, -10), 2)
+	lastDay := add(domain.EXPENSE, 50, statementDate.Add(20*time.Hour), 2)
+	add(domain.EXPENSE, 70, statementDate.AddDate(0, 0, 2), 2)
+	add(domain.EXPENSE, 80, statementDate.AddDate(0, 0, -5), 3)
+
+	start := func(date time.Time, endingBalance float64) (*domain.ReconciliationDTO, error) {
+		reconciliation := domain.ReconciliationDTO{UserId: userId, AccountId: 2, StatementDate: date.UnixMilli(), EndingBalance: endingBalance}
+		return reconciliationService.StartReconciliation(context.Background(), &domain.ReconciliationData{Reconciliation: reconciliation, Validator: validator.New()})
+	}
+	clear := func(reconciliationId uuid.UUID, cleared bool, transactionIds ...uuid.UUID) (*domain.ReconciliationDetailDTO, error) {
+		update := domain.ClearUpdateDTO{ReconciliationId: reconciliationId, TransactionIds: transactionIds, Cleared: cleared}
+		return reconciliationService.UpdateCleared(context.Background(), &domain.ClearUpdateData{Update: update, Validator: validator.New()})
+	}
+
+	march, err := start(statementDate, 400)
+	if err != nil {
+		t.Fatal("Error starting the reconciliation:", err)
+	}
+	if _, err := start(statementDate.AddDate(0, 1, 0), 0); !errors.Is(err, ErrReconciliationOpen) {
+		t.Errorf("Expected ErrReconciliationOpen with one in progress, got %v", err)
+	}
+
+	detail, err := reconciliationService.RetrieveReconciliation(march.ReconciliationId)
+	if err != nil {
+		t.Fatal("Error retrieving the reconciliation:", err)
+	}
+	if len(detail.Cleared) != 0 || len(detail.Uncleared) != 3 || detail.Difference != 400 {
+		t.Errorf("Expected the three March transactions of the account to clear, got %+v", detail)
+	}
+
+	detail, err = clear(march.ReconciliationId, true, salary.TransactionId, rent.TransactionId, lastDay.TransactionId)
+	if err != nil {
+		t.Fatal("Error clearing transactions:", err)
+	}
+	if len(detail.Cleared) != 3 || detail.ClearedBalance != 350 || detail.Difference != 50 {
+		t.Errorf("Expected 50 left to clear, got %+v", detail)
+	}
+	if _, err := reconciliationService.FinishReconciliation(context.Background(), march.ReconciliationId); !errors.Is(err, ErrReconciliationUnbalanced) {
+		t.Errorf("Expected ErrReconciliationUnbalanced, got %v", err)
+	}
+
+	detail, err = clear(march.ReconciliationId, false, lastDay.TransactionId)
+	if err != nil {
+		t.Fatal("Error unclearing the transaction:", err)
+	}
+	if detail.Difference != 0 || len(detail.Uncleared) != 1 {
+		t.Errorf("Expected the reconciliation to balance, got %+v", detail)
+	}
+	cleared, err := udb.GetTransaction(salary.TransactionId)
+	if err != nil || cleared.Status != domain.CLEARED {
+		t.Errorf("Expected the salary to be cleared, got %v %v", cleared.Status, err)
+	}
+
+	detail, err = reconciliationService.FinishReconciliation(context.Background(), march.ReconciliationId)
+	if err != nil {
+		t.Fatal("Error finishing the reconciliation:", err)
+	}
+	if detail.Reconciliation.Status != domain.RECONCILED || detail.Reconciliation.ReconciledAt == 0 {
+		t.Errorf("Expected the reconciliation to be locked, got %+v", detail.Reconciliation)
+	}
+	if _, err := clear(march.ReconciliationId, true, lastDay.TransactionId); !errors.Is(err, ErrReconciliationLocked) {
+		t.Errorf("Expected ErrReconciliationLocked clearing in a locked reconciliation, got %v", err)
+	}
+	if err := transactionService.DeleteTransaction(context.Background(), rent.TransactionId); !errors.Is(err, ErrTransactionReconciled) {
+		t.Errorf("Expected ErrTransactionReconciled deleting a reconciled transaction, got %v", err)
+	}
+	base := domain.BaseCurrencyDTO{UserId: userId, Currency: "EUR"}
+	if _, err := currencyService.SetBaseCurrency(context.Background(), &domain.BaseCurrencyData{BaseCurrency: base, Validator: validator.New()}); !errors.Is(err, ErrTransactionReconciled) {
+		t.Errorf("Expected ErrTransactionReconciled converting a reconciled transaction, got %v", err)
+	}
+	landlord := domain.PayeeModelBuilder().WithUserId(userId).Build()
+	landlord.PayeeId = rent.PayeeId
+	payees := &StubPayeeDatabase{payees: []domain.PayeeModel{landlord, domain.PayeeModelBuilder().WithUserId(userId).Build()}}
+	payeeService := PayeeService{PDBI: payees, TDBI: &udb, Reconciler: &reconciliationService}
+	merge := domain.PayeeMergeDTO{SourcePayeeId: landlord.PayeeId, TargetPayeeId: payees.payees[1].PayeeId}
+	if err := payeeService.MergePayees(context.Background(), &domain.PayeeMergeData{Merge: merge, Validator: validator.New()}); !errors.Is(err, ErrTransactionReconciled) {
+		t.Errorf("Expected ErrTransactionReconciled moving a reconciled transaction to another payee, got %v", err)
+	}
+
+	april, err := start(statementDate.AddDate(0, 0, 30), 280)
+	if err != nil {
+		t.Fatal("Error starting the next reconciliation:", err)
+	}
+	if april.StartingBalance != 400 {
+		t.Errorf("Expected April to start from the March ending balance, got %v", april.StartingBalance)
+	}
+	detail, err = reconciliationService.RetrieveReconciliation(april.ReconciliationId)
+	if err != nil {
+		t.Fatal("Error retrieving the reconciliation:", err)
+	}
+	if len(detail.Uncleared) != 2 || detail.Difference != -120 {
+		t.Errorf("Expected only the unreconciled transactions to clear, got %+v", detail)
+	}
+	if _, err := clear(april.ReconciliationId, true, salary.TransactionId); err == nil {
+		t.Error("Expected an error clearing a transaction reconciled in March")
+	}
+
+	if _, err := reconciliationService.UnlockReconciliation(context.Background(), march.ReconciliationId); !errors.Is(err, ErrReconciliationNotLatest) {
+		t.Errorf("Expected ErrReconciliationNotLatest unlocking March, got %v", err)
+	}
+	if err := reconciliationService.DeleteReconciliation(context.Background(), april.ReconciliationId); err != nil {
+		t.Fatal("Error deleting the reconciliation:", err)
+	}
+	if _, err := reconciliationService.UnlockReconciliation(context.Background(), march.ReconciliationId); err != nil {
+		t.Fatal("Error unlocking the reconciliation:", err)
+	}
+	if err := transactionService.DeleteTransaction(context.Background(), rent.TransactionId); err != nil {
+		t.Errorf("Expected the transaction to be deletable once unlocked, got %v", err)
+	}
+}
+
+func TestReconciliation_AccountCurrency_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpReconciliationModel(db)
+	setUpCurrencyModel(db)
+	udb := database.SQLManager{DB: db}
+	currencyService := CurrencyService{CRDBI: &udb, TDBI: &udb}
+	reconciliationService := ReconciliationService{RCDBI: &udb, TDBI: &udb, Currencies: &currencyService}
+	transactionService := TransactionService{UDBI: &udb, Currencies: &currencyService}
+
+	if _, err := currencyService.ImportRates([]byte("Date,USD,GBP\n2026-03-02,1.25,0.8\n")); err != nil {
+		t.Fatal("Error importing the rates:", err)
+	}
+	userId := uuid.New()
+	accountCurrency := domain.AccountCurrencyDTO{UserId: userId, AccountId: 4, Currency: "USD"}
+	if err := currencyService.SetAccountCurrency(context.Background(), &domain.AccountCurrencyData{AccountCurrency: accountCurrency, Validator: validator.New()}); err != nil {
+		t.Fatal("Error setting the account currency:", err)
+	}
+
+	date := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	add := func(transactionType domain.TransactionType, currency string, amount float64) uuid.UUID {
+		transaction := domain.TransactionDTOBuilder().Build()
+		transaction.UserId = userId
+		transaction.AccountId = 4
+		transaction.Type = transactionType
+		transaction.Status = domain.PENDING
+		transaction.Currency = currency
+		transaction.Amount = amount
+		transaction.Date = date.UnixMilli()
+		result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+		if err != nil {
+			t.Fatal("Error adding the transaction:", err)
+		}
+		return result.Transaction.TransactionId
+	}
+	// 1000 USD is saved as 800 EUR, 40 GBP as 50 EUR which is 62.50 USD.
+	salary := add(domain.INCOME, "", 1000)
+	hotel := add(domain.EXPENSE, "GBP", 40)
+
+	reconciliation := domain.ReconciliationDTO{UserId: userId, AccountId: 4, StatementDate: date.UnixMilli(), EndingBalance: 937.5}
+	started, err := reconciliationService.StartReconciliation(context.Background(), &domain.ReconciliationData{Reconciliation: reconciliation, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error starting the reconciliation:", err)
+	}
+	update := domain.ClearUpdateDTO{ReconciliationId: started.ReconciliationId, TransactionIds: []uuid.UUID{salary, hotel}, Cleared: true}
+	detail, err := reconciliationService.UpdateCleared(context.Background(), &domain.ClearUpdateData{Update: update, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error clearing transactions:", err)
+	}
+	if detail.ClearedBalance != 937.5 || detail.Difference != 0 {
+		t.Errorf("Expected the USD statement to balance, got %+v", detail)
+	}
+}
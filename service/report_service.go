@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type ReportServiceInterface interface {
+	RetrieveReport(query *domain.ReportQueryDTO) (*domain.ReportDTO, error)
+}
+
+type ReportService struct {
+	TDBI database.TransactionDatabaseInterface
+	Tags TagServiceInterface // optional, looks up the tags of the transactions to filter by.
+}
+
+// RetrieveReport totals the users transactions in the query range by period, category, payment
+// method and status, and compares them with the equally long range before it. Only the
+// transactions passing the tag filter are counted, in the range before too.
+func (rs *ReportService) RetrieveReport(query *domain.ReportQueryDTO) (*domain.ReportDTO, error) {
+	if query.To < query.From {
+		return nil, errors.New("report range ends before it starts")
+	}
+
+	// the range before is loaded too, as is the period before the first one for its delta.
+	previousFrom := query.From - (query.To - query.From + 1)
+	firstStart := query.Period.Start(time.UnixMilli(query.From))
+	priorStart := query.Period.Start(firstStart.Add(-time.Millisecond))
+	transactions, err := rs.TDBI.GetTransactionsByUserId(query.UserId, min(previousFrom, priorStart.UnixMilli()), query.To)
+	if err != nil {
+		return nil, err
+	}
+	tags := map[uuid.UUID][]string{}
+	if rs.Tags != nil && len(query.TagFilter.Tags) > 0 {
+		tags, err = rs.Tags.RetrieveTransactionTags(query.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := domain.ReportDTO{UserId: query.UserId, From: query.From, To: query.To}
+	prior := domain.ReportTotalsDTO{}
+	periods := []domain.ReportPeriodDTO{}
+	periodIndex := map[int64]int{}
+	for start := firstStart; start.UnixMilli() <= query.To; start = query.Period.Next(start) {
+		periodIndex[start.UnixMilli()] = len(periods)
+		end := query.Period.Next(start).UnixMilli() - 1
+		periods = append(periods, domain.ReportPeriodDTO{Period: query.Period.Label(start), From: max(start.UnixMilli(), query.From), To: min(end, query.To)})
+	}
+	categories := map[int64]*domain.ReportCategoryDTO{}
+	methods := map[domain.TransactionMethod]*domain.ReportTotalsDTO{}
+	statuses := map[domain.TransactionStatus]*domain.ReportTotalsDTO{}
+
+	for _, tm := range transactions {
+		if !query.TagFilter.Matches(tags[tm.TransactionId]) {
+			continue
+		}
+		inRange := tm.Date >= query.From && tm.Date <= query.To
+		if inRange {
+			if statuses[tm.Status] == nil {
+				statuses[tm.Status] = &domain.ReportTotalsDTO{}
+			}
+			addToTotals(statuses[tm.Status], &tm)
+		}
+		if tm.Status == domain.CANCELLED || (query.ExcludePending && tm.Status == domain.PENDING) {
+			continue
+		}
+
+		if tm.Date >= previousFrom && tm.Date < query.From {
+			addToTotals(&report.Previous, &tm)
+		}
+		if tm.Date >= priorStart.UnixMilli() && tm.Date < firstStart.UnixMilli() {
+			addToTotals(&prior, &tm)
+		}
+		if !inRange {
+			continue
+		}
+
+		addToTotals(&report.Totals, &tm)
+		addToTotals(&periods[periodIndex[query.Period.Start(time.UnixMilli(tm.Date)).UnixMilli()]].Totals, &tm)
+		if methods[tm.PaymentMethod] == nil {
+			methods[tm.PaymentMethod] = &domain.ReportTotalsDTO{}
+		}
+		addToTotals(methods[tm.PaymentMethod], &tm)
+		if categories[tm.CategoryId] == nil {
+			categories[tm.CategoryId] = &domain.ReportCategoryDTO{CategoryId: tm.CategoryId}
+		}
+		category := categories[tm.CategoryId]
+		category.Count++
+		if tm.Type == domain.INCOME {
+			category.Income += tm.Amount
+		} else {
+			category.Expense += tm.Amount
+		}
+	}
+
+	roundTotals(&report.Totals)
+	roundTotals(&report.Previous)
+	report.Delta = totalsDelta(&report.Totals, &report.Previous)
+
+	roundTotals(&prior)
+	for i := range periods {
+		roundTotals(&periods[i].Totals)
+		previous := prior
+		if i > 0 {
+			previous = periods[i-1].Totals
+		}
+		periods[i].Delta = totalsDelta(&periods[i].Totals, &previous)
+	}
+	report.Periods = periods
+
+	report.Categories = make([]domain.ReportCategoryDTO, 0, len(categories))
+	for _, category := range categories {
+		category.Income = roundCents(category.Income)
+		category.Expense = roundCents(category.Expense)
+		category.IncomePercent = percentOf(category.Income, report.Totals.Income)
+		category.ExpensePercent = percentOf(category.Expense, report.Totals.Expense)
+		report.Categories = append(report.Categories, *category)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if a.Expense != b.Expense {
+			return a.Expense > b.Expense
+		}
+		return a.CategoryId < b.CategoryId
+	})
+
+	report.PaymentMethods = make([]domain.ReportPaymentMethodDTO, 0, len(methods))
+	for method, totals := range methods {
+		roundTotals(totals)
+		report.PaymentMethods = append(report.PaymentMethods, domain.ReportPaymentMethodDTO{PaymentMethod: method, Totals: *totals})
+	}
+	sort.Slice(report.PaymentMethods, func(i, j int) bool {
+		return report.PaymentMethods[i].PaymentMethod < report.PaymentMethods[j].PaymentMethod
+	})
+
+	report.Statuses = make([]domain.ReportStatusDTO, 0, len(statuses))
+	for status, totals := range statuses {
+		roundTotals(totals)
+		report.Statuses = append(report.Statuses, domain.ReportStatusDTO{Status: status, Totals: *totals})
+	}
+	sort.Slice(report.Statuses, func(i, j int) bool {
+		return report.Statuses[i].Status < report.Statuses[j].Status
+	})
+	return &report, nil
+}
+
+func addToTotals(totals *domain.ReportTotalsDTO, tm *domain.TransactionModel) {
+	if tm.Type == domain.INCOME {
+		totals.Income += tm.Amount
+	} else {
+		totals.Expense += tm.Amount
+	}
+	totals.Net = totals.Income - totals.Expense
+	totals.Count++
+}
+
+func roundTotals(totals *domain.ReportTotalsDTO) {
+	totals.Income = roundCents(totals.Income)
+	totals.Expense = roundCents(totals.Expense)
+	totals.Net = roundCents(totals.Net)
+}
+
+func totalsDelta(current *domain.ReportTotalsDTO, previous *domain.ReportTotalsDTO) domain.ReportTotalsDTO {
+	return domain.ReportTotalsDTO{
+		Income:  roundCents(current.Income - previous.Income),
+		Expense: roundCents(current.Expense - previous.Expense),
+		Net:     roundCents(current.Net - previous.Net),
+		Count:   current.Count - previous.Count,
+	}
+}
+
+// percentOf is part as a percentage of total, rounded to one decimal.
+func percentOf(part float64, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(part/total*1000) / 10
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestRetrieveReport_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	udb := database.SQLManager{DB: db}
+	reportService := ReportService{TDBI: &udb}
+
+	userId := uuid.New()
+	day := func(month time.Month, d int) int64 {
+		return time.Date(2026, month, d, 12, 0, 0, 0, time.UTC).UnixMilli()
+	}
+	add := func(date int64, amount float64, transactionType domain.TransactionType, categoryId int64, method domain.TransactionMethod, status domain.TransactionStatus) {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.Date = date
+		tm.Amount = amount
+		tm.Type = transactionType
+		tm.CategoryId = categoryId
+		tm.PaymentMethod = method
+		tm.Status = status
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+	add(day(time.January, 15), 100, domain.EXPENSE, 1, domain.CREDIT_CARD, domain.CLEARED)
+	add(day(time.February, 10), 3000, domain.INCOME, 9, domain.BANK_TRANSFER, domain.CLEARED)
+	add(day(time.February, 12), 200, domain.EXPENSE, 1, domain.CASH, domain.CLEARED)
+	add(day(time.February, 20), 300, domain.EXPENSE, 2, domain.CREDIT_CARD, domain.PENDING)
+	add(day(time.March, 5), 150, domain.EXPENSE, 1, domain.CREDIT_CARD, domain.CLEARED)
+	add(day(time.March, 6), 999, domain.EXPENSE, 2, domain.CREDIT_CARD, domain.CANCELLED)
+	add(day(time.March, 7), 3000, domain.INCOME, 9, domain.BANK_TRANSFER, domain.CLEARED)
+	add(day(time.April, 2), 50, domain.EXPENSE, 1, domain.CASH, domain.CLEARED)
+
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).UnixMilli() - 1
+	report, err := reportService.RetrieveReport(&domain.ReportQueryDTO{UserId: userId, From: from, To: to, Period: domain.REPORT_MONTH})
+	if err != nil {
+		t.Fatal("Error retrieving the report:", err)
+	}
+
+	if want := (domain.ReportTotalsDTO{Income: 6000, Expense: 650, Net: 5350, Count: 5}); report.Totals != want {
+		t.Errorf("Wrong totals, got %+v, want %+v", report.Totals, want)
+	}
+	if want := (domain.ReportTotalsDTO{Income: 6000, Expense: 550, Net: 5450, Count: 4}); report.Delta != want {
+		t.Errorf("Wrong delta from the previous range, got %+v, want %+v", report.Delta, want)
+	}
+
+	wantPeriods := []domain.ReportPeriodDTO{
+		{Period: "2026-02", From: from, To: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli() - 1,
+			Totals: domain.ReportTotalsDTO{Income: 3000, Expense: 500, Net: 2500, Count: 3},
+			Delta:  domain.ReportTotalsDTO{Income: 3000, Expense: 400, Net: 2600, Count: 2}},
+		{Period: "2026-03", From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), To: to,
+			Totals: domain.ReportTotalsDTO{Income: 3000, Expense: 150, Net: 2850, Count: 2},
+			Delta:  domain.ReportTotalsDTO{Income: 0, Expense: -350, Net: 350, Count: -1}},
+	}
+	if len(report.Periods) != len(wantPeriods) {
+		t.Fatalf("Wrong periods, got %+v", report.Periods)
+	}
+	for i, want := range wantPeriods {
+		if report.Periods[i] != want {
+			t.Errorf("Wrong period %d, got %+v, want %+v", i, report.Periods[i], want)
+		}
+	}
+
+	wantCategories := []domain.ReportCategoryDTO{
+		{CategoryId: 1, Expense: 350, ExpensePercent: 53.8, Count: 2},
+		{CategoryId: 2, Expense: 300, ExpensePercent: 46.2, Count: 1},
+		{CategoryId: 9, Income: 6000, IncomePercent: 100, Count: 2},
+	}
+	for i, want := range wantCategories {
+		if i >= len(report.Categories) || report.Categories[i] != want {
+			t.Errorf("Wrong categories, got %+v, want %+v", report.Categories, wantCategories)
+			break
+		}
+	}
+
+	if len(report.PaymentMethods) != 3 || report.PaymentMethods[0].PaymentMethod != domain.CASH || report.PaymentMethods[0].Totals.Expense != 200 ||
+		report.PaymentMethods[1].Totals.Expense != 450 || report.PaymentMethods[2].Totals.Income != 6000 {
+		t.Errorf("Wrong payment methods, got %+v", report.PaymentMethods)
+	}
+
+	wantStatuses := []domain.ReportStatusDTO{
+		{Status: domain.PENDING, Totals: domain.ReportTotalsDTO{Expense: 300, Net: -300, Count: 1}},
+		{Status: domain.CLEARED, Totals: domain.ReportTotalsDTO{Income: 6000, Expense: 350, Net: 5650, Count: 4}},
+		{Status: domain.CANCELLED, Totals: domain.ReportTotalsDTO{Expense: 999, Net: -999, Count: 1}},
+	}
+	for i, want := range wantStatuses {
+		if i >= len(report.Statuses) || report.Statuses[i] != want {
+			t.Errorf("Wrong statuses, got %+v, want %+v", report.Statuses, wantStatuses)
+			break
+		}
+	}
+
+	cleared, err := reportService.RetrieveReport(&domain.ReportQueryDTO{UserId: userId, From: from, To: to, Period: domain.REPORT_WEEK, ExcludePending: true})
+	if err != nil {
+		t.Fatal("Error retrieving the report:", err)
+	}
+	if cleared.Totals.Expense != 350 || cleared.Totals.Count != 4 || len(cleared.Statuses) != 3 {
+		t.Errorf("Expected pending transactions left out of the totals only, got %+v", cleared)
+	}
+	// February 1st is a Sunday, the first week starts the Monday before and is clipped to the range.
+	if first := cleared.Periods[0]; first.Period != "2026-W05" || first.From != from || len(cleared.Periods) != 10 {
+		t.Errorf("Wrong weeks, got %+v", cleared.Periods)
+	}
+}
+
+func TestRetrieveReport_TagFilter(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpTagModel(db)
+	udb := database.SQLManager{DB: db}
+	tagService := TagService{TGDBI: &udb}
+	transactionService := TransactionService{UDBI: &udb, Tags: &tagService}
+	reportService := ReportService{TDBI: &udb, Tags: &tagService}
+
+	userId := uuid.New()
+	add := func(amount float64, tags ...string) {
+		transaction := domain.TransactionDTOBuilder().Build()
+		transaction.UserId = userId
+		transaction.Amount = amount
+		transaction.Date = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC).UnixMilli()
+		transaction.Type = domain.EXPENSE
+		transaction.Status = domain.CLEARED
+		transaction.Tags = tags
+		if _, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()}); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+	}
+	add(100, "travel", "work")
+	add(40, "travel")
+	add(7)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).UnixMilli() - 1
+	tests := []struct {
+		name        string
+		filter      domain.TagFilter
+		wantExpense float64
+		wantCount   int
+	}{
+		{name: "No filter", wantExpense: 147, wantCount: 3},
+		{name: "Any", filter: domain.TagFilter{Tags: []string{"travel"}}, wantExpense: 140, wantCount: 2},
+		{name: "All", filter: domain.TagFilter{Tags: []string{"travel", "work"}, Mode: domain.TAG_ALL}, wantExpense: 100, wantCount: 1},
+		{name: "None", filter: domain.TagFilter{Tags: []string{"work"}, Mode: domain.TAG_NONE}, wantExpense: 47, wantCount: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := reportService.RetrieveReport(&domain.ReportQueryDTO{UserId: userId, From: from, To: to, Period: domain.REPORT_MONTH, TagFilter: test.filter})
+			if err != nil {
+				t.Fatal("Error retrieving the report:", err)
+			}
+			if report.Totals.Expense != test.wantExpense || report.Totals.Count != test.wantCount {
+				t.Errorf("Wrong totals, got %+v, want %.2f in %d transactions", report.Totals, test.wantExpense, test.wantCount)
+			}
+		})
+	}
+}
+
+func TestRetrieveReport_InvalidRange(t *testing.T) {
+	reportService := ReportService{TDBI: &StubDatabase{}}
+
+	_, err := reportService.RetrieveReport(&domain.ReportQueryDTO{UserId: uuid.New(), From: 20, To: 10})
+	if err == nil {
+		t.Error("Expected an error for a range that ends before it starts")
+	}
+}
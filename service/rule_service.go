@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type RuleServiceInterface interface {
+	AddRule(ctx context.Context, ruleData *domain.RuleData) (*domain.RuleDTO, error)
+	RetrieveRules(userId uuid.UUID) ([]domain.RuleDTO, error)
+	DeleteRule(ctx context.Context, ruleId uuid.UUID) error
+	ApplyRules(tm *domain.TransactionModel) (*domain.RuleChangeDTO, error)
+	ReapplyRules(ctx context.Context, reapplyData *domain.RuleReapplyData) ([]domain.RuleChangeDTO, error)
+}
+
+type RuleService struct {
+	RDBI       database.RuleDatabaseInterface
+	TDBI       database.TransactionDatabaseInterface
+	Tags       TagServiceInterface            // optional, saves the tags added when reapplying.
+	Reconciler ReconciliationServiceInterface // optional, leaves reconciled transactions out when reapplying.
+	Events     EventBusInterface              // optional, saves transaction.updated events for the transactions changed when reapplying.
+	Classifier ClassifierServiceInterface     // optional, relearns the transactions changed when reapplying.
+}
+
+func (rs *RuleService) AddRule(ctx context.Context, ruleData *domain.RuleData) (*domain.RuleDTO, error) {
+	err := ruleData.ValidateRule()
+	if err != nil {
+		return nil, err
+	}
+
+	rm := convertRuleDTOToModel(&ruleData.Rule)
+	// catch bad patterns now rather than on every transaction.
+	_, err = newRuleEngine([]domain.RuleModel{rm})
+	if err != nil {
+		return nil, err
+	}
+
+	err = rs.RDBI.AddRule(ctx, &rm)
+	if err != nil {
+		return nil, err
+	}
+	ruleDTO := convertRuleModelToDTO(&rm)
+	return &ruleDTO, nil
+}
+
+func (rs *RuleService) RetrieveRules(userId uuid.UUID) ([]domain.RuleDTO, error) {
+	rules, err := rs.RDBI.GetRulesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleDTOs := make([]domain.RuleDTO, 0, len(rules))
+	for _, rm := range rules {
+		ruleDTOs = append(ruleDTOs, convertRuleModelToDTO(&rm))
+	}
+	return ruleDTOs, nil
+}
+
+func (rs *RuleService) DeleteRule(ctx context.Context, ruleId uuid.UUID) error {
+	return rs.RDBI.DeleteRule(ctx, ruleId)
+}
+
+// ApplyRules runs the users rules over a new transaction before it is saved.
+// A category chosen by the user is never replaced.
+func (rs *RuleService) ApplyRules(tm *domain.TransactionModel) (*domain.RuleChangeDTO, error) {
+	engine, err := rs.userRuleEngine(tm.UserId)
+	if err != nil {
+		return nil, err
+	}
+	change := engine.apply(tm, tm.CategoryId != 0)
+	return &change, nil
+}
+
+// ReapplyRules runs the current rules over existing transactions and returns every transaction that changes.
+// Unless it is a dry run the changes are saved.
+func (rs *RuleService) ReapplyRules(ctx context.Context, reapplyData *domain.RuleReapplyData) ([]domain.RuleChangeDTO, error) {
+	err := reapplyData.ValidateRuleReapply()
+	if err != nil {
+		return nil, err
+	}
+	reapply := reapplyData.Reapply
+
+	engine, err := rs.userRuleEngine(reapply.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := rs.TDBI.GetTransactionsByUserId(reapply.UserId, reapply.From, reapply.To)
+	if err != nil {
+		return nil, err
+	}
+
+	existingTags := map[uuid.UUID][]string{}
+	if rs.Tags != nil {
+		existingTags, err = rs.Tags.RetrieveTransactionTags(reapply.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes := []domain.RuleChangeDTO{}
+	var before, after []domain.TransactionModel
+	for _, tm := range transactions {
+		original := tm
+		change := engine.apply(&tm, false)
+		// only report tags the transaction does not have yet.
+		change.Tags = slices.DeleteFunc(change.Tags, func(tag string) bool {
+			return slices.Contains(existingTags[tm.TransactionId], domain.NormalizeTag(tag))
+		})
+		if len(change.Changes) == 0 && len(change.Tags) == 0 {
+			continue
+		}
+		if rs.Reconciler != nil {
+			err = rs.Reconciler.CheckUnlocked(tm.TransactionId)
+			if errors.Is(err, ErrTransactionReconciled) {
+				continue
+			}
+			if err != nil {
+				return changes, errors.Join(err, rs.relearn(ctx, before, after))
+			}
+		}
+		changes = append(changes, change)
+
+		if reapply.DryRun {
+			continue
+		}
+		if len(change.Changes) > 0 {
+			tm.UpdatedAt = time.Now().UnixMilli()
+			events, err := recordEvent(rs.Events, domain.EVENT_TRANSACTION_UPDATED, tm.UserId, tm.TransactionId, convertTransactionModelToDTO(&tm))
+			if err != nil {
+				return changes, errors.Join(err, rs.relearn(ctx, before, after))
+			}
+			err = rs.TDBI.UpdateTransaction(ctx, &tm, events...)
+			if err != nil {
+				return changes, errors.Join(err, rs.relearn(ctx, before, after))
+			}
+			before, after = append(before, original), append(after, tm)
+		}
+		if len(change.Tags) > 0 && rs.Tags != nil {
+			update := domain.TagUpdateDTO{UserId: tm.UserId, TransactionIds: []uuid.UUID{tm.TransactionId}, Tags: change.Tags}
+			err = rs.Tags.TagTransactions(ctx, &domain.TagUpdateData{Validator: reapplyData.Validator, Update: update})
+			if err != nil {
+				return changes, errors.Join(err, rs.relearn(ctx, before, after))
+			}
+		}
+	}
+	return changes, rs.relearn(ctx, before, after)
+}
+
+// relearn moves the transactions changed when reapplying in the classifier, out of what it
+// learned from them before.
+func (rs *RuleService) relearn(ctx context.Context, before []domain.TransactionModel, after []domain.TransactionModel) error {
+	if rs.Classifier == nil || len(after) == 0 {
+		return nil
+	}
+	err := rs.Classifier.Unlearn(ctx, before...)
+	if err != nil {
+		return err
+	}
+	return rs.Classifier.Learn(ctx, after...)
+}
+
+func (rs *RuleService) userRuleEngine(userId uuid.UUID) (*ruleEngine, error) {
+	rules, err := rs.RDBI.GetRulesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return newRuleEngine(rules)
+}
+
+// ruleEngine holds a users rules, in priority order, with their patterns compiled.
+type ruleEngine struct {
+	rules    []domain.RuleModel
+	patterns map[string]*regexp.Regexp
+}
+
+func newRuleEngine(rules []domain.RuleModel) (*ruleEngine, error) {
+	engine := ruleEngine{rules: rules, patterns: map[string]*regexp.Regexp{}}
+	for _, rule := range rules {
+		for _, condition := range rule.Conditions {
+			if condition.Type != domain.DESCRIPTION_REGEX {
+				continue
+			}
+			pattern, err := regexp.Compile(condition.Text)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q has an invalid pattern: %w", rule.Name, err)
+			}
+			engine.patterns[condition.Text] = pattern
+		}
+	}
+	return &engine, nil
+}
+
+// apply changes tm in place. Conditions are always checked against the transaction as it
+// was before any rule ran, and the first matching rule to set a field wins.
+func (e *ruleEngine) apply(tm *domain.TransactionModel, keepCategory bool) domain.RuleChangeDTO {
+	original := *tm
+	change := domain.RuleChangeDTO{TransactionId: tm.TransactionId, RuleIds: []uuid.UUID{}, Changes: []domain.RuleFieldChange{}, Tags: []string{}}
+	set := map[domain.RuleActionType]bool{}
+	if keepCategory {
+		set[domain.SET_CATEGORY] = true
+	}
+
+	for _, rule := range e.rules {
+		if !e.matches(&rule, &original) {
+			continue
+		}
+		change.RuleIds = append(change.RuleIds, rule.RuleId)
+
+		for _, action := range rule.Actions {
+			if action.Type == domain.ADD_TAG {
+				if action.Tag != "" && !slices.Contains(change.Tags, action.Tag) {
+					change.Tags = append(change.Tags, action.Tag)
+				}
+				continue
+			}
+			if set[action.Type] {
+				continue
+			}
+			set[action.Type] = true
+
+			switch action.Type {
+			case domain.SET_CATEGORY:
+				tm.CategoryId = action.CategoryId
+			case domain.SET_STATUS:
+				tm.Status = action.Status
+			case domain.RENAME_DESCRIPTION:
+				tm.Description = action.Description
+			}
+		}
+	}
+
+	if tm.CategoryId != original.CategoryId {
+		change.Changes = append(change.Changes, domain.RuleFieldChange{Field: "categoryId", Before: strconv.FormatInt(original.CategoryId, 10), After: strconv.FormatInt(tm.CategoryId, 10)})
+	}
+	if tm.Status != original.Status {
+		change.Changes = append(change.Changes, domain.RuleFieldChange{Field: "status", Before: original.Status.String(), After: tm.Status.String()})
+	}
+	if tm.Description != original.Description {
+		change.Changes = append(change.Changes, domain.RuleFieldChange{Field: "description", Before: original.Description, After: tm.Description})
+	}
+	return change
+}
+
+func (e *ruleEngine) matches(rule *domain.RuleModel, tm *domain.TransactionModel) bool {
+	for _, condition := range rule.Conditions {
+		if !e.conditionMatches(&condition, tm) {
+			return false
+		}
+	}
+	return len(rule.Conditions) > 0
+}
+
+func (e *ruleEngine) conditionMatches(condition *domain.RuleCondition, tm *domain.TransactionModel) bool {
+	switch condition.Type {
+	case domain.DESCRIPTION_CONTAINS:
+		return strings.Contains(strings.ToLower(tm.Description), strings.ToLower(condition.Text))
+	case domain.DESCRIPTION_REGEX:
+		return e.patterns[condition.Text].MatchString(tm.Description)
+	case domain.AMOUNT_RANGE:
+		amount := math.Abs(tm.Amount)
+		return (condition.Min == 0 || amount >= condition.Min) && (condition.Max == 0 || amount <= condition.Max)
+	case domain.PAYMENT_METHOD:
+		return tm.PaymentMethod == condition.PaymentMethod
+	case domain.ACCOUNT:
+		return tm.AccountId == condition.AccountId
+	}
+	return false
+}
+
+func convertRuleDTOToModel(from *domain.RuleDTO) domain.RuleModel {
+	return domain.RuleModel{
+		RuleId:     uuid.New(),
+		UserId:     from.UserId,
+		Name:       from.Name,
+		Priority:   from.Priority,
+		Conditions: from.Conditions,
+		Actions:    from.Actions,
+		CreatedAt:  time.Now().UnixMilli(),
+	}
+}
+
+func convertRuleModelToDTO(from *domain.RuleModel) domain.RuleDTO {
+	return domain.RuleDTO{
+		RuleId:     from.RuleId,
+		UserId:     from.UserId,
+		Name:       from.Name,
+		Priority:   from.Priority,
+		Conditions: from.Conditions,
+		Actions:    from.Actions,
+		CreatedAt:  from.CreatedAt,
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type StubRuleDatabase struct {
+	StubDatabase
+	rules        []domain.RuleModel
+	transactions []domain.TransactionModel
+	updated      []domain.TransactionModel
+}
+
+func (m *StubRuleDatabase) AddRule(ctx context.Context, rm *domain.RuleModel) error {
+	m.rules = append(m.rules, *rm)
+	return nil
+}
+
+func (m *StubRuleDatabase) GetRulesByUserId(userId uuid.UUID) ([]domain.RuleModel, error) {
+	return m.rules, nil
+}
+
+func (m *StubRuleDatabase) DeleteRule(ctx context.Context, ruleId uuid.UUID) error {
+	return nil
+}
+
+func (m *StubRuleDatabase) GetTransactionsByUserId(userId uuid.UUID, from int64, to int64) ([]domain.TransactionModel, error) {
+	return m.transactions, nil
+}
+
+func (m *StubRuleDatabase) UpdateTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	m.updated = append(m.updated, *tm)
+	return nil
+}
+
+func groceryRules() []domain.RuleModel {
+	return []domain.RuleModel{
+		domain.RuleModelBuilder().WithPriority(1).
+			WithConditions(
+				domain.RuleCondition{Type: domain.DESCRIPTION_REGEX, Text: `(?i)^whole\s*foods`},
+				domain.RuleCondition{Type: domain.AMOUNT_RANGE, Min: 10, Max: 500},
+			).
+			WithActions(
+				domain.RuleAction{Type: domain.SET_CATEGORY, CategoryId: 3},
+				domain.RuleAction{Type: domain.RENAME_DESCRIPTION, Description: "Whole Foods"},
+				domain.RuleAction{Type: domain.ADD_TAG, Tag: "groceries"},
+			).Build(),
+		domain.RuleModelBuilder().WithPriority(2).
+			WithConditions(domain.RuleCondition{Type: domain.DESCRIPTION_CONTAINS, Text: "FOODS"}).
+			WithActions(
+				domain.RuleAction{Type: domain.SET_CATEGORY, CategoryId: 9},
+				domain.RuleAction{Type: domain.SET_STATUS, Status: domain.CLEARED},
+				domain.RuleAction{Type: domain.ADD_TAG, Tag: "food"},
+			).Build(),
+		domain.RuleModelBuilder().WithPriority(3).
+			WithConditions(domain.RuleCondition{Type: domain.PAYMENT_METHOD, PaymentMethod: domain.CASH}).
+			WithActions(domain.RuleAction{Type: domain.ADD_TAG, Tag: "cash"}).Build(),
+	}
+}
+
+func TestRuleEngineApply(t *testing.T) {
+	engine, err := newRuleEngine(groceryRules())
+	if err != nil {
+		t.Fatal("Error building the rule engine:", err)
+	}
+
+	tests := []struct {
+		name             string
+		description      string
+		amount           float64
+		categoryId       int64
+		keepCategory     bool
+		wantCategoryId   int64
+		wantDescription  string
+		wantStatus       domain.TransactionStatus
+		wantTags         []string
+		wantChangeFields []string
+	}{
+		{
+			name:             "Both rules match, first wins",
+			description:      "WHOLEFOODS #1234",
+			amount:           54.20,
+			wantCategoryId:   3,
+			wantDescription:  "Whole Foods",
+			wantStatus:       domain.CLEARED,
+			wantTags:         []string{"groceries", "food"},
+			wantChangeFields: []string{"categoryId", "status", "description"},
+		},
+		{
+			name:             "Amount out of range",
+			description:      "Whole Foods Market",
+			amount:           800,
+			wantCategoryId:   9,
+			wantDescription:  "Whole Foods Market",
+			wantStatus:       domain.CLEARED,
+			wantTags:         []string{"food"},
+			wantChangeFields: []string{"categoryId", "status"},
+		},
+		{
+			name:             "Manual category kept",
+			description:      "Whole Foods Market",
+			amount:           20,
+			categoryId:       12,
+			keepCategory:     true,
+			wantCategoryId:   12,
+			wantDescription:  "Whole Foods",
+			wantStatus:       domain.CLEARED,
+			wantTags:         []string{"groceries", "food"},
+			wantChangeFields: []string{"status", "description"},
+		},
+		{
+			name:             "No rule matches",
+			description:      "Gas station",
+			amount:           40,
+			wantDescription:  "Gas station",
+			wantStatus:       domain.PENDING,
+			wantTags:         []string{},
+			wantChangeFields: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tm := domain.TransactionModelBuilder().Build()
+			tm.Description = test.description
+			tm.Amount = test.amount
+			tm.CategoryId = test.categoryId
+			tm.Status = domain.PENDING
+			tm.PaymentMethod = domain.CREDIT_CARD
+
+			change := engine.apply(&tm, test.keepCategory)
+
+			if tm.CategoryId != test.wantCategoryId || tm.Description != test.wantDescription || tm.Status != test.wantStatus {
+				t.Errorf("Wrong transaction after rules, got %v", tm)
+			}
+			if !reflect.DeepEqual(change.Tags, test.wantTags) {
+				t.Errorf("Wrong tags, got %v, want %v", change.Tags, test.wantTags)
+			}
+			fields := []string{}
+			for _, fieldChange := range change.Changes {
+				fields = append(fields, fieldChange.Field)
+			}
+			if !reflect.DeepEqual(fields, test.wantChangeFields) {
+				t.Errorf("Wrong changes, got %v, want %v", fields, test.wantChangeFields)
+			}
+		})
+	}
+}
+
+func TestAddRule_InvalidPattern(t *testing.T) {
+	stubDB := new(StubRuleDatabase)
+	ruleService := RuleService{RDBI: stubDB, TDBI: stubDB}
+
+	rule := domain.RuleDTO{
+		UserId:     uuid.New(),
+		Name:       "Broken",
+		Conditions: []domain.RuleCondition{{Type: domain.DESCRIPTION_REGEX, Text: "(unclosed"}},
+		Actions:    []domain.RuleAction{{Type: domain.SET_CATEGORY, CategoryId: 1}},
+	}
+	_, err := ruleService.AddRule(context.Background(), &domain.RuleData{Rule: rule, Validator: validator.New()})
+	if err == nil {
+		t.Fatal("Expected an error for an invalid pattern")
+	}
+	if len(stubDB.rules) != 0 {
+		t.Fatal("The invalid rule should not be saved")
+	}
+}
+
+func TestReapplyRules(t *testing.T) {
+	matching := domain.TransactionModelBuilder().Build()
+	matching.Description = "Whole Foods Market"
+	matching.Amount = 30
+	matching.CategoryId = 12
+	other := domain.TransactionModelBuilder().Build()
+	other.Description = "Gas station"
+	other.PaymentMethod = domain.CREDIT_CARD
+
+	for _, dryRun := range []bool{true, false} {
+		stubDB := &StubRuleDatabase{rules: groceryRules(), transactions: []domain.TransactionModel{matching, other}}
+		ruleService := RuleService{RDBI: stubDB, TDBI: stubDB}
+
+		reapply := domain.RuleReapplyDTO{UserId: matching.UserId, To: 100, DryRun: dryRun}
+		changes, err := ruleService.ReapplyRules(context.Background(), &domain.RuleReapplyData{Reapply: reapply, Validator: validator.New()})
+		if err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+		if len(changes) != 1 || changes[0].TransactionId != matching.TransactionId {
+			t.Fatalf("Expected only the matching transaction to change, got %v", changes)
+		}
+		// existing categories are replaced when reapplying.
+		if changes[0].Changes[0] != (domain.RuleFieldChange{Field: "categoryId", Before: "12", After: "3"}) {
+			t.Errorf("Wrong category change, got %v", changes[0].Changes[0])
+		}
+
+		wantUpdated := 1
+		if dryRun {
+			wantUpdated = 0
+		}
+		if len(stubDB.updated) != wantUpdated {
+			t.Errorf("dryRun %v: wrong number of updates, got %d, want %d", dryRun, len(stubDB.updated), wantUpdated)
+		}
+	}
+}
+
+func TestReapplyRules_Relearns(t *testing.T) {
+	matching := domain.TransactionModelBuilder().Build()
+	matching.Description = "Whole Foods Market"
+	matching.Amount = 30
+	matching.CategoryId = 12
+	classifierDB := newStubClassifierDatabase(matching)
+	classifierService := ClassifierService{CDBI: classifierDB, TDBI: classifierDB}
+	if err := classifierService.Train(context.Background(), matching.UserId); err != nil {
+		t.Fatal("Error training the classifier:", err)
+	}
+
+	stubDB := &StubRuleDatabase{rules: groceryRules(), transactions: []domain.TransactionModel{matching}}
+	ruleService := RuleService{RDBI: stubDB, TDBI: stubDB, Classifier: &classifierService}
+	reapply := domain.RuleReapplyDTO{UserId: matching.UserId, To: 100}
+	_, err := ruleService.ReapplyRules(context.Background(), &domain.RuleReapplyData{Reapply: reapply, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	// the recategorized transaction moves to its new category in the model.
+	cm := classifierDB.classifiers[matching.UserId]
+	if _, ok := cm.CategoryCounts[12]; ok || cm.CategoryCounts[3] != 1 || cm.Learned[matching.TransactionId] != 3 {
+		t.Errorf("The transaction was not relearned, got %v and %v", cm.CategoryCounts, cm.Learned)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+// MaxPriceFileSize is the largest price file accepted.
+const MaxPriceFileSize = 32 << 20
+
+// parseSecurityPrices reads a CSV of closing prices with a header naming the symbol (or
+// ticker), date and price (or close) columns, in any order. Dates are written as 2006-01-02.
+func parseSecurityPrices(data []byte) ([]domain.SecurityPriceModel, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the price file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading the price CSV header: %w", err)
+	}
+	symbolColumn, dateColumn, priceColumn := -1, -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "symbol", "ticker":
+			symbolColumn = i
+		case "date":
+			dateColumn = i
+		case "price", "close":
+			priceColumn = i
+		}
+	}
+	if symbolColumn < 0 || dateColumn < 0 || priceColumn < 0 {
+		return nil, errors.New("the price CSV needs symbol, date and price columns")
+	}
+
+	var prices []domain.SecurityPriceModel
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading the price CSV: %w", err)
+		}
+
+		symbol := strings.ToUpper(strings.TrimSpace(record[symbolColumn]))
+		if symbol == "" {
+			return nil, errors.New("a price is missing its symbol")
+		}
+		date, err := time.Parse(domain.RateDateLayout, strings.TrimSpace(record[dateColumn]))
+		if err != nil {
+			return nil, fmt.Errorf("reading the price date %q: %w", record[dateColumn], err)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[priceColumn]), 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("the %s price on %s is not a positive number: %q", symbol, date.Format(domain.RateDateLayout), record[priceColumn])
+		}
+		prices = append(prices, domain.SecurityPriceModel{Symbol: symbol, Date: date.Format(domain.RateDateLayout), Price: price})
+	}
+	if len(prices) == 0 {
+		return nil, errors.New("the file holds no prices")
+	}
+	return prices, nil
+}
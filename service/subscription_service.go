@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+const (
+	// subscriptionMinCharges is the fewest charges a subscription is detected from.
+	subscriptionMinCharges = 3
+	// subscriptionRegularity is the share of intervals between charges that must match the cadence.
+	subscriptionRegularity = 0.75
+	// subscriptionAmountTolerance is how far, as a fraction, a charge may be from the one before.
+	// One larger jump is allowed for a price change.
+	subscriptionAmountTolerance = 0.25
+)
+
+// subscriptionCadence is the usual number of days between charges of a frequency, how many days
+// off a charge may be, and the number of charges in a year.
+type subscriptionCadence struct {
+	frequency domain.ScheduleFrequency
+	days      float64
+	tolerance float64
+	perYear   float64
+}
+
+var subscriptionCadences = []subscriptionCadence{
+	{frequency: domain.WEEKLY, days: 7, tolerance: 1, perYear: 52},
+	{frequency: domain.BIWEEKLY, days: 14, tolerance: 2, perYear: 26},
+	{frequency: domain.MONTHLY, days: 365.25 / 12, tolerance: 3.5, perYear: 12},
+	{frequency: domain.YEARLY, days: 365.25, tolerance: 10, perYear: 1},
+}
+
+var (
+	ErrSubscriptionNotFound = errors.New("no subscription was detected for the key")
+	ErrSubscriptionDecided  = errors.New("the subscription is already confirmed")
+)
+
+type SubscriptionServiceInterface interface {
+	DiscoverSubscriptions(userId uuid.UUID, includeDismissed bool) ([]domain.SubscriptionDTO, error)
+	ConfirmSubscription(ctx context.Context, decisionData *domain.SubscriptionDecisionData) (*domain.SubscriptionDTO, error)
+	DismissSubscription(ctx context.Context, decisionData *domain.SubscriptionDecisionData) error
+}
+
+type SubscriptionService struct {
+	SBDBI database.SubscriptionDatabaseInterface
+	TDBI  database.TransactionDatabaseInterface
+	SDBI  database.ScheduleDatabaseInterface
+}
+
+// DiscoverSubscriptions finds the periodic charges in the users transactions, most expensive per
+// year first, along with the users decision on each. Dismissed ones are left out unless asked for.
+func (ss *SubscriptionService) DiscoverSubscriptions(userId uuid.UUID, includeDismissed bool) ([]domain.SubscriptionDTO, error) {
+	subscriptions, err := ss.discover(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	discovered := make([]domain.SubscriptionDTO, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.Status == domain.SUBSCRIPTION_DISMISSED && !includeDismissed {
+			continue
+		}
+		discovered = append(discovered, subscription)
+	}
+	return discovered, nil
+}
+
+// ConfirmSubscription tracks the detected subscription as a scheduled item on the account it
+// was last charged to, starting from the last charge, so it shows up in forecasts.
+func (ss *SubscriptionService) ConfirmSubscription(ctx context.Context, decisionData *domain.SubscriptionDecisionData) (*domain.SubscriptionDTO, error) {
+	subscription, err := ss.detected(decisionData)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Status == domain.SUBSCRIPTION_CONFIRMED {
+		return nil, ErrSubscriptionDecided
+	}
+
+	now := time.Now().UnixMilli()
+	item := domain.ScheduledItemModel{
+		ScheduledItemId: uuid.New(),
+		UserId:          subscription.UserId,
+		AccountId:       subscription.AccountId,
+		Description:     subscription.Description,
+		Amount:          subscription.Amount,
+		Type:            domain.EXPENSE,
+		Frequency:       subscription.Frequency,
+		StartDate:       subscription.LastDate,
+		CreatedAt:       now,
+	}
+	err = ss.SDBI.AddScheduledItem(ctx, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := domain.SubscriptionDecisionModel{UserId: subscription.UserId, Key: subscription.Key, Status: domain.SUBSCRIPTION_CONFIRMED, ScheduledItemId: item.ScheduledItemId, CreatedAt: now}
+	err = ss.SBDBI.SaveSubscriptionDecision(ctx, &decision)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Status = decision.Status
+	subscription.ScheduledItemId = decision.ScheduledItemId
+	return subscription, nil
+}
+
+// DismissSubscription hides the detected subscription from future discoveries.
+func (ss *SubscriptionService) DismissSubscription(ctx context.Context, decisionData *domain.SubscriptionDecisionData) error {
+	subscription, err := ss.detected(decisionData)
+	if err != nil {
+		return err
+	}
+	if subscription.Status == domain.SUBSCRIPTION_CONFIRMED {
+		return ErrSubscriptionDecided
+	}
+
+	decision := domain.SubscriptionDecisionModel{UserId: subscription.UserId, Key: subscription.Key, Status: domain.SUBSCRIPTION_DISMISSED, CreatedAt: time.Now().UnixMilli()}
+	return ss.SBDBI.SaveSubscriptionDecision(ctx, &decision)
+}
+
+func (ss *SubscriptionService) detected(decisionData *domain.SubscriptionDecisionData) (*domain.SubscriptionDTO, error) {
+	err := decisionData.ValidateSubscriptionDecision()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := ss.discover(decisionData.Decision.UserId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		if subscriptions[i].Key == decisionData.Decision.Key {
+			return &subscriptions[i], nil
+		}
+	}
+	return nil, ErrSubscriptionNotFound
+}
+
+func (ss *SubscriptionService) discover(userId uuid.UUID) ([]domain.SubscriptionDTO, error) {
+	transactions, err := ss.TDBI.GetTransactionsByUserId(userId, 0, time.Now().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := ss.SBDBI.GetSubscriptionDecisions(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := discoverSubscriptions(userId, transactions, time.Now())
+	for i := range subscriptions {
+		for _, decision := range decisions {
+			if decision.Key == subscriptions[i].Key {
+				subscriptions[i].Status = decision.Status
+				subscriptions[i].ScheduledItemId = decision.ScheduledItemId
+			}
+		}
+	}
+	return subscriptions, nil
+}
+
+// discoverSubscriptions groups the expenses, ordered by date, by payee, or by normalized
+// description for those without one, and keeps the groups charged at a regular cadence with
+// similar amounts.
+func discoverSubscriptions(userId uuid.UUID, transactions []domain.TransactionModel, now time.Time) []domain.SubscriptionDTO {
+	groups := map[string][]domain.TransactionModel{}
+	var keys []string
+	for _, tm := range transactions {
+		if tm.Type != domain.EXPENSE || tm.Status == domain.CANCELLED {
+			continue
+		}
+		key := subscriptionKey(&tm)
+		if key == "" {
+			continue
+		}
+		if groups[key] == nil {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], tm)
+	}
+
+	subscriptions := []domain.SubscriptionDTO{}
+	for _, key := range keys {
+		charges := groups[key]
+		if len(charges) < subscriptionMinCharges {
+			continue
+		}
+		cadence, ok := matchCadence(charges)
+		if !ok {
+			continue
+		}
+		priceChanges, ok := subscriptionPriceChanges(charges)
+		if !ok {
+			continue
+		}
+
+		first, last := charges[0], charges[len(charges)-1]
+		next := cadence.frequency.Occurrence(time.UnixMilli(last.Date).UTC(), 1)
+		overdue := next.Add(time.Duration(cadence.days / 2 * float64(24*time.Hour)))
+		subscriptions = append(subscriptions, domain.SubscriptionDTO{
+			Key:          key,
+			UserId:       userId,
+			PayeeId:      last.PayeeId,
+			Description:  last.Description,
+			AccountId:    last.AccountId,
+			CategoryId:   last.CategoryId,
+			Frequency:    cadence.frequency,
+			Amount:       last.Amount,
+			AnnualCost:   roundCents(last.Amount * cadence.perYear),
+			Charges:      len(charges),
+			FirstDate:    first.Date,
+			LastDate:     last.Date,
+			NextDate:     next.UnixMilli(),
+			Active:       now.Before(overdue),
+			PriceChanges: priceChanges,
+			Status:       domain.SUBSCRIPTION_DETECTED,
+		})
+	}
+
+	sort.SliceStable(subscriptions, func(i, j int) bool { return subscriptions[i].AnnualCost > subscriptions[j].AnnualCost })
+	return subscriptions
+}
+
+// subscriptionKey is the payee id, or the normalized description when there is no payee.
+func subscriptionKey(tm *domain.TransactionModel) string {
+	if tm.PayeeId != uuid.Nil {
+		return tm.PayeeId.String()
+	}
+	return strings.Join(normalizeDescription(tm.Description), " ")
+}
+
+// matchCadence returns the cadence closest to the median interval between the charges, as long
+// as enough of the intervals are within its tolerance.
+func matchCadence(charges []domain.TransactionModel) (subscriptionCadence, bool) {
+	intervals := make([]float64, 0, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		intervals = append(intervals, float64(charges[i].Date-charges[i-1].Date)/float64(dayMillis))
+	}
+	typical := median(intervals)
+
+	for _, cadence := range subscriptionCadences {
+		if math.Abs(typical-cadence.days) > cadence.tolerance {
+			continue
+		}
+		regular := 0
+		for _, interval := range intervals {
+			if math.Abs(interval-cadence.days) <= cadence.tolerance {
+				regular++
+			}
+		}
+		if float64(regular) >= subscriptionRegularity*float64(len(intervals)) {
+			return cadence, true
+		}
+	}
+	return subscriptionCadence{}, false
+}
+
+// subscriptionPriceChanges checks the charges are similar, each within the tolerance of the one
+// before apart from a single price change, and returns the changes from or to a price that held
+// for more than one charge. Bills that vary every time have no price changes.
+func subscriptionPriceChanges(charges []domain.TransactionModel) ([]domain.PriceChangeDTO, bool) {
+	changes := []domain.PriceChangeDTO{}
+	jumps := 0
+	for i := 1; i < len(charges); i++ {
+		previous, current := charges[i-1].Amount, charges[i].Amount
+		if math.Abs(current-previous) > subscriptionAmountTolerance*previous {
+			jumps++
+			if jumps > 1 {
+				return nil, false
+			}
+		}
+		heldBefore := i > 1 && math.Abs(previous-charges[i-2].Amount) < 0.01
+		holdsAfter := i+1 < len(charges) && math.Abs(current-charges[i+1].Amount) < 0.01
+		if (heldBefore || holdsAfter) && math.Abs(current-previous) >= 0.01 {
+			changes = append(changes, domain.PriceChangeDTO{Date: charges[i].Date, From: previous, To: current})
+		}
+	}
+	return changes, true
+}
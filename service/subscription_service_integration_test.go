@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpSubscriptionModel(db *sql.DB) {
+	stmt := `create table subscription_decision (
+		id integer primary key autoincrement,
+		user_id text not null,
+		subscription_key text not null,
+		status integer not null,
+		scheduled_item_id text not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating subscription_decision table:", err)
+	}
+}
+
+func TestSubscriptionDecisions_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpScheduledItemModel(db)
+	setUpSubscriptionModel(db)
+	udb := database.SQLManager{DB: db}
+	subscriptionService := SubscriptionService{SBDBI: &udb, TDBI: &udb, SDBI: &udb}
+
+	userId := uuid.New()
+	today := startOfDay(time.Now())
+	payees := []uuid.UUID{uuid.New(), uuid.New()}
+	for _, payeeId := range payees {
+		for weeks := 4; weeks >= 1; weeks-- {
+			tm := domain.TransactionModelBuilder().Build()
+			tm.UserId = userId
+			tm.AccountId = 2
+			tm.PayeeId = payeeId
+			tm.Amount = 7
+			tm.Date = today.AddDate(0, 0, -7*weeks).UnixMilli()
+			tm.Type = domain.EXPENSE
+			tm.Status = domain.CLEARED
+			if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+				t.Fatal("Error adding transaction:", err)
+			}
+		}
+	}
+
+	subscriptions, err := subscriptionService.DiscoverSubscriptions(userId, false)
+	if err != nil {
+		t.Fatal("Error discovering subscriptions:", err)
+	}
+	if len(subscriptions) != 2 {
+		t.Fatalf("Expected both weekly charges, got %+v", subscriptions)
+	}
+
+	decide := func(key string) *domain.SubscriptionDecisionData {
+		return &domain.SubscriptionDecisionData{Decision: domain.SubscriptionDecisionDTO{UserId: userId, Key: key}, Validator: validator.New()}
+	}
+	confirmed, err := subscriptionService.ConfirmSubscription(context.Background(), decide(payees[0].String()))
+	if err != nil {
+		t.Fatal("Error confirming the subscription:", err)
+	}
+	if confirmed.Status != domain.SUBSCRIPTION_CONFIRMED || confirmed.ScheduledItemId == uuid.Nil {
+		t.Errorf("Expected the subscription to be confirmed, got %+v", confirmed)
+	}
+	items, err := udb.GetScheduledItemsByUserId(userId)
+	if err != nil {
+		t.Fatal("Error retrieving scheduled items:", err)
+	}
+	if len(items) != 1 || items[0].ScheduledItemId != confirmed.ScheduledItemId || items[0].AccountId != 2 || items[0].Frequency != domain.WEEKLY || items[0].Amount != 7 {
+		t.Errorf("Expected the subscription tracked as a weekly scheduled item, got %+v", items)
+	}
+	if _, err := subscriptionService.ConfirmSubscription(context.Background(), decide(payees[0].String())); !errors.Is(err, ErrSubscriptionDecided) {
+		t.Errorf("Expected confirming twice to fail, got %v", err)
+	}
+
+	if err := subscriptionService.DismissSubscription(context.Background(), decide(payees[1].String())); err != nil {
+		t.Fatal("Error dismissing the subscription:", err)
+	}
+	if err := subscriptionService.DismissSubscription(context.Background(), decide("unknown")); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Expected an unknown subscription not to be found, got %v", err)
+	}
+
+	subscriptions, err = subscriptionService.DiscoverSubscriptions(userId, false)
+	if err != nil {
+		t.Fatal("Error discovering subscriptions:", err)
+	}
+	if len(subscriptions) != 1 || subscriptions[0].Key != payees[0].String() || subscriptions[0].Status != domain.SUBSCRIPTION_CONFIRMED {
+		t.Errorf("Expected only the confirmed subscription, got %+v", subscriptions)
+	}
+	all, err := subscriptionService.DiscoverSubscriptions(userId, true)
+	if err != nil || len(all) != 2 {
+		t.Errorf("Expected the dismissed subscription when asked for, got %+v, %v", all, err)
+	}
+}
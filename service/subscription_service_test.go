@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestDiscoverSubscriptions(t *testing.T) {
+	userId := uuid.New()
+	now := time.Date(2026, time.June, 20, 12, 0, 0, 0, time.UTC)
+	streaming, power, gym := uuid.New(), uuid.New(), uuid.New()
+
+	var transactions []domain.TransactionModel
+	add := func(date time.Time, payeeId uuid.UUID, amount float64, description string) {
+		transactions = append(transactions, domain.TransactionModel{TransactionId: uuid.New(), UserId: userId, AccountId: 1, PayeeId: payeeId, Amount: amount, Date: date.UnixMilli(), Description: description, Type: domain.EXPENSE, Status: domain.CLEARED})
+	}
+	for m := 0; m < 8; m++ {
+		month := time.Date(2025, time.November+time.Month(m), 1, 9, 0, 0, 0, time.UTC)
+		price := 9.99
+		if m >= 5 {
+			price = 12.99
+		}
+		// charged a day or two late now and then.
+		add(month.AddDate(0, 0, 14+m%2), streaming, price, "Streaming")
+		add(month.AddDate(0, 0, 2), power, 80+float64(m%3)*7, "Power company")
+	}
+	// weekly without a payee, the reference number changes every time.
+	for w := 0; w < 6; w++ {
+		add(time.Date(2026, time.May, 8, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 7*w), uuid.Nil, 4.50, fmt.Sprintf("Coffee club ref %d", 1000+w))
+	}
+	// a gym cancelled in March.
+	for m := 0; m < 4; m++ {
+		add(time.Date(2025, time.December+time.Month(m), 3, 0, 0, 0, 0, time.UTC), gym, 30, "Gym")
+	}
+	// irregular shopping at one store is not a subscription.
+	for _, day := range []int{1, 3, 12, 13, 29} {
+		add(time.Date(2026, time.May, day, 0, 0, 0, 0, time.UTC), uuid.Nil, 40, "Hardware store")
+	}
+	cancelled := domain.TransactionModel{UserId: userId, PayeeId: streaming, Amount: 500, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), Type: domain.EXPENSE, Status: domain.CANCELLED}
+	transactions = append(transactions, cancelled)
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].Date < transactions[j].Date })
+
+	subscriptions := discoverSubscriptions(userId, transactions, now)
+
+	byKey := map[string]domain.SubscriptionDTO{}
+	for _, subscription := range subscriptions {
+		byKey[subscription.Key] = subscription
+	}
+	if len(subscriptions) != 4 {
+		t.Fatalf("Expected 4 subscriptions, got %+v", subscriptions)
+	}
+
+	bill := byKey[power.String()]
+	if bill.Frequency != domain.MONTHLY || len(bill.PriceChanges) != 0 || bill.AnnualCost != roundCents(bill.Amount*12) {
+		t.Errorf("Expected a monthly bill without price changes, got %+v", bill)
+	}
+	if subscriptions[0].Key != power.String() {
+		t.Errorf("Expected the most expensive subscription first, got %v", subscriptions[0].Description)
+	}
+
+	s := byKey[streaming.String()]
+	wantNext := time.Date(2026, time.July, 16, 9, 0, 0, 0, time.UTC).UnixMilli()
+	if s.Frequency != domain.MONTHLY || s.Charges != 8 || s.Amount != 12.99 || s.AnnualCost != 155.88 || s.NextDate != wantNext || !s.Active {
+		t.Errorf("Wrong streaming subscription, got %+v", s)
+	}
+	if len(s.PriceChanges) != 1 || s.PriceChanges[0].From != 9.99 || s.PriceChanges[0].To != 12.99 {
+		t.Errorf("Expected the price change from 9.99 to 12.99, got %+v", s.PriceChanges)
+	}
+
+	coffee, ok := byKey["coffee club ref"]
+	if !ok || coffee.Frequency != domain.WEEKLY || coffee.AnnualCost != 234 || coffee.PayeeId != uuid.Nil {
+		t.Errorf("Expected the weekly coffee club by description, got %+v", coffee)
+	}
+
+	if g := byKey[gym.String()]; g.Active || g.Charges != 4 {
+		t.Errorf("Expected the gym to no longer be active, got %+v", g)
+	}
+}
+
+func TestSubscriptionPriceChanges(t *testing.T) {
+	charges := func(amounts ...float64) []domain.TransactionModel {
+		var transactions []domain.TransactionModel
+		for i, amount := range amounts {
+			transactions = append(transactions, domain.TransactionModel{Amount: amount, Date: int64(i)})
+		}
+		return transactions
+	}
+
+	if changes, ok := subscriptionPriceChanges(charges(9.99, 12.99, 12.99)); !ok || len(changes) != 1 {
+		t.Errorf("Expected a change to a price that held, got %+v", changes)
+	}
+	if _, ok := subscriptionPriceChanges(charges(10, 20, 40)); ok {
+		t.Error("Expected charges that keep jumping not to be similar")
+	}
+	if changes, ok := subscriptionPriceChanges(charges(85, 90, 95, 88)); !ok || len(changes) != 0 {
+		t.Errorf("Expected a bill that varies to have no price changes, got %+v", changes)
+	}
+}
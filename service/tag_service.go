@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+type TagServiceInterface interface {
+	RetrieveTags(userId uuid.UUID) ([]domain.TagDTO, error)
+	TagTransactions(ctx context.Context, updateData *domain.TagUpdateData) error
+	UntagTransactions(ctx context.Context, updateData *domain.TagUpdateData) error
+	RetrieveTransactionTags(userId uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+type TagService struct {
+	TGDBI database.TagDatabaseInterface
+}
+
+func (ts *TagService) RetrieveTags(userId uuid.UUID) ([]domain.TagDTO, error) {
+	tags, err := ts.TGDBI.GetTagsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tagDTOs := make([]domain.TagDTO, 0, len(tags))
+	for _, tm := range tags {
+		tagDTOs = append(tagDTOs, convertTagModelToDTO(&tm))
+	}
+	return tagDTOs, nil
+}
+
+// TagTransactions adds the tags to the transactions, creating any tag the user does not have yet.
+func (ts *TagService) TagTransactions(ctx context.Context, updateData *domain.TagUpdateData) error {
+	err := updateData.ValidateTagUpdate()
+	if err != nil {
+		return err
+	}
+	update := updateData.Update
+
+	tagIds, err := ts.tagIds(ctx, update.UserId, update.Tags, true)
+	if err != nil {
+		return err
+	}
+	return ts.TGDBI.AddTransactionTags(ctx, update.TransactionIds, tagIds)
+}
+
+// UntagTransactions removes the tags from the transactions, unknown tags are ignored.
+func (ts *TagService) UntagTransactions(ctx context.Context, updateData *domain.TagUpdateData) error {
+	err := updateData.ValidateTagUpdate()
+	if err != nil {
+		return err
+	}
+	update := updateData.Update
+
+	tagIds, err := ts.tagIds(ctx, update.UserId, update.Tags, false)
+	if err != nil || len(tagIds) == 0 {
+		return err
+	}
+	return ts.TGDBI.RemoveTransactionTags(ctx, update.TransactionIds, tagIds)
+}
+
+func (ts *TagService) RetrieveTransactionTags(userId uuid.UUID) (map[uuid.UUID][]string, error) {
+	return ts.TGDBI.GetTransactionTags(userId)
+}
+
+// tagIds looks up the users tags by name, with create set missing tags are saved.
+func (ts *TagService) tagIds(ctx context.Context, userId uuid.UUID, names []string, create bool) ([]uuid.UUID, error) {
+	existing, err := ts.TGDBI.GetTagsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]uuid.UUID, len(existing))
+	for _, tm := range existing {
+		byName[tm.Name] = tm.TagId
+	}
+
+	var tagIds []uuid.UUID
+	for _, name := range normalizeTags(names) {
+		tagId, ok := byName[name]
+		if !ok {
+			if !create {
+				continue
+			}
+			tm := newTag(userId, name)
+			err = ts.TGDBI.AddTag(ctx, &tm)
+			if err != nil {
+				return nil, err
+			}
+			tagId = tm.TagId
+		}
+		tagIds = append(tagIds, tagId)
+	}
+	return tagIds, nil
+}
+
+// newTag makes a tag to save, it takes the ID of a tag of the same name saved in the meantime.
+func newTag(userId uuid.UUID, name string) domain.TagModel {
+	return domain.TagModel{TagId: uuid.New(), UserId: userId, Name: name, CreatedAt: time.Now().UnixMilli()}
+}
+
+// normalizeTags normalizes each name and drops blanks and repeats.
+func normalizeTags(names []string) []string {
+	tags := []string{}
+	for _, name := range names {
+		tag := domain.NormalizeTag(name)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func convertTagModelToDTO(from *domain.TagModel) domain.TagDTO {
+	return domain.TagDTO{
+		TagId:     from.TagId,
+		UserId:    from.UserId,
+		Name:      from.Name,
+		CreatedAt: from.CreatedAt,
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func setUpTagModel(db *sql.DB) {
+	stmt := `create table tag_model (
+		id integer primary key autoincrement,
+		tag_id text not null,
+		user_id text not null,
+		name text not null,
+		created_at integer not null,
+		unique (user_id, name)
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating tag_model table:", err)
+	}
+}
+
+func TestTagFilters_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpTagModel(db)
+	udb := database.SQLManager{DB: db}
+	tagService := TagService{TGDBI: &udb}
+	transactionService := TransactionService{UDBI: &udb, Tags: &tagService}
+
+	userId := uuid.New()
+	add := func(description string, tags ...string) uuid.UUID {
+		transaction := domain.TransactionDTOBuilder().WithDescription(description).Build()
+		transaction.UserId = userId
+		transaction.Tags = tags
+		result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+		if err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+		return result.Transaction.TransactionId
+	}
+	hotel := add("Hotel", "Vacation-2026", "reimbursable")
+	dinner := add("Dinner", "vacation-2026")
+	office := add("Office chair", "tax-deductible")
+	groceries := add("Groceries")
+
+	tests := []struct {
+		name   string
+		filter domain.TagFilter
+		want   []uuid.UUID
+	}{
+		{name: "No filter", filter: domain.TagFilter{}, want: []uuid.UUID{hotel, dinner, office, groceries}},
+		{name: "Any", filter: domain.TagFilter{Tags: []string{"reimbursable", "tax-deductible"}, Mode: domain.TAG_ANY}, want: []uuid.UUID{hotel, office}},
+		{name: "All", filter: domain.TagFilter{Tags: []string{"vacation-2026", "reimbursable"}, Mode: domain.TAG_ALL}, want: []uuid.UUID{hotel}},
+		{name: "None", filter: domain.TagFilter{Tags: []string{"vacation-2026"}, Mode: domain.TAG_NONE}, want: []uuid.UUID{office, groceries}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := domain.TransactionQueryDTO{UserId: userId, From: 0, To: math.MaxInt64, TagFilter: test.filter}
+			transactions, err := transactionService.RetrieveTransactions(&query)
+			if err != nil {
+				t.Fatal("Error retrieving transactions:", err)
+			}
+			if !sameTransactionIds(transactions, test.want) {
+				t.Errorf("Wrong transactions, got %v, want %v", transactions, test.want)
+			}
+		})
+	}
+
+	// bulk removal, then the tag no longer matches.
+	update := domain.TagUpdateDTO{UserId: userId, TransactionIds: []uuid.UUID{hotel, dinner}, Tags: []string{"VACATION-2026"}}
+	err := tagService.UntagTransactions(context.Background(), &domain.TagUpdateData{Update: update, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error removing tags:", err)
+	}
+	query := domain.TransactionQueryDTO{UserId: userId, From: 0, To: math.MaxInt64, TagFilter: domain.TagFilter{Tags: []string{"vacation-2026"}}}
+	transactions, err := transactionService.RetrieveTransactions(&query)
+	if err != nil || len(transactions) != 0 {
+		t.Fatalf("Expected no vacation transactions, got %v, err: %v", transactions, err)
+	}
+
+	// tags are removed with their transaction.
+	err = udb.DeleteTransaction(context.Background(), hotel)
+	if err != nil {
+		t.Fatal("Error deleting transaction:", err)
+	}
+	tags, err := tagService.RetrieveTransactionTags(userId)
+	if err != nil {
+		t.Fatal("Error retrieving transaction tags:", err)
+	}
+	if _, ok := tags[hotel]; ok || len(tags) != 1 {
+		t.Errorf("Expected only the office chair to be tagged, got %v", tags)
+	}
+}
+
+func TestAddTag_Existing_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpTagModel(db)
+	udb := database.SQLManager{DB: db}
+
+	userId := uuid.New()
+	first := newTag(userId, "vacation-2026")
+	err := udb.AddTag(context.Background(), &first)
+	if err != nil {
+		t.Fatal("Error adding tag:", err)
+	}
+	// a second request creating the tag it did not find gets the saved one.
+	second := newTag(userId, "vacation-2026")
+	err = udb.AddTag(context.Background(), &second)
+	if err != nil {
+		t.Fatal("Error adding the tag again:", err)
+	}
+	if second.TagId != first.TagId || second.CreatedAt != first.CreatedAt {
+		t.Errorf("Expected the saved tag, got %v, want %v", second, first)
+	}
+
+	tags, err := udb.GetTagsByUserId(userId)
+	if err != nil {
+		t.Fatal("Error retrieving tags:", err)
+	}
+	if len(tags) != 1 {
+		t.Errorf("Expected one tag, got %v", tags)
+	}
+}
+
+func TestRuleTagsAreSaved_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpTagModel(db)
+	udb := database.SQLManager{DB: db}
+	tagService := TagService{TGDBI: &udb}
+	stubRules := &StubRuleDatabase{rules: groceryRules()}
+	ruleService := RuleService{RDBI: stubRules, TDBI: &udb, Tags: &tagService}
+	transactionService := TransactionService{UDBI: &udb, Rules: &ruleService, Tags: &tagService}
+
+	transaction := domain.TransactionDTOBuilder().WithDescription("Whole Foods Market").Build()
+	transaction.Amount = 25
+	transaction.CategoryId = 0
+	transaction.PaymentMethod = domain.CREDIT_CARD
+	transaction.Tags = []string{"Food"}
+	result, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding transaction:", err)
+	}
+	if len(result.Transaction.Tags) != 2 || result.Transaction.Tags[0] != "food" || result.Transaction.Tags[1] != "groceries" {
+		t.Fatalf("Wrong tags saved, got %v", result.Transaction.Tags)
+	}
+
+	// nothing left to do when the rules run again.
+	reapply := domain.RuleReapplyDTO{UserId: transaction.UserId, To: math.MaxInt64}
+	changes, err := ruleService.ReapplyRules(context.Background(), &domain.RuleReapplyData{Reapply: reapply, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error reapplying rules:", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func sameTransactionIds(transactions []domain.TransactionDTO, want []uuid.UUID) bool {
+	if len(transactions) != len(want) {
+		return false
+	}
+	found := map[uuid.UUID]bool{}
+	for _, transaction := range transactions {
+		found[transaction.TransactionId] = true
+	}
+	for _, id := range want {
+		if !found[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/export"
+)
+
+var ErrCategoryMapped = errors.New("the category is already on a tax line")
+
+type TaxServiceInterface interface {
+	AddTaxLine(ctx context.Context, taxLineData *domain.TaxLineData) (*domain.TaxLineDTO, error)
+	RetrieveTaxLines(userId uuid.UUID) ([]domain.TaxLineDTO, error)
+	DeleteTaxLine(ctx context.Context, taxLineId uuid.UUID) error
+	RetrieveTaxReport(userId uuid.UUID, year int) (*domain.TaxReportDTO, error)
+	ExportTaxReportCSV(userId uuid.UUID, year int) ([]byte, error)
+}
+
+type TaxService struct {
+	TXDBI       database.TaxDatabaseInterface
+	TDBI        database.TransactionDatabaseInterface
+	Attachments AttachmentServiceInterface // optional, lists the receipts attached to each supporting transaction.
+}
+
+func (ts *TaxService) AddTaxLine(ctx context.Context, taxLineData *domain.TaxLineData) (*domain.TaxLineDTO, error) {
+	err := taxLineData.ValidateTaxLine()
+	if err != nil {
+		return nil, err
+	}
+
+	tm := convertTaxLineDTOToModel(&taxLineData.TaxLine)
+	tm.Name = strings.TrimSpace(tm.Name)
+	tm.Code = strings.TrimSpace(tm.Code)
+	slices.Sort(tm.CategoryIds)
+	tm.CategoryIds = slices.Compact(tm.CategoryIds)
+
+	taxLines, err := ts.TXDBI.GetTaxLinesByUserId(tm.UserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range taxLines {
+		for _, categoryId := range tm.CategoryIds {
+			if slices.Contains(existing.CategoryIds, categoryId) {
+				return nil, fmt.Errorf("%w: category %d is on %q", ErrCategoryMapped, categoryId, existing.Name)
+			}
+		}
+	}
+
+	tm.TaxLineId = uuid.New()
+	tm.CreatedAt = time.Now().UnixMilli()
+	err = ts.TXDBI.AddTaxLine(ctx, &tm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertTaxLineModelToDTO(&tm)
+	return &saved, nil
+}
+
+func (ts *TaxService) RetrieveTaxLines(userId uuid.UUID) ([]domain.TaxLineDTO, error) {
+	taxLines, err := ts.TXDBI.GetTaxLinesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	taxLineDTOs := make([]domain.TaxLineDTO, 0, len(taxLines))
+	for _, tm := range taxLines {
+		taxLineDTOs = append(taxLineDTOs, convertTaxLineModelToDTO(&tm))
+	}
+	return taxLineDTOs, nil
+}
+
+func (ts *TaxService) DeleteTaxLine(ctx context.Context, taxLineId uuid.UUID) error {
+	return ts.TXDBI.DeleteTaxLine(ctx, taxLineId)
+}
+
+// RetrieveTaxReport totals the transactions of the calendar year, in UTC, under the tax lines
+// their categories map to. Expenses add to a line and income, such as refunds, takes from it.
+func (ts *TaxService) RetrieveTaxReport(userId uuid.UUID, year int) (*domain.TaxReportDTO, error) {
+	taxLines, err := ts.TXDBI.GetTaxLinesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0).UnixMilli() - 1
+	transactions, err := ts.TDBI.GetTransactionsByUserId(userId, from.UnixMilli(), to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.TaxReportDTO{UserId: userId, Year: year, From: from.UnixMilli(), To: to, Lines: []domain.TaxLineTotalDTO{}}
+	lineIndex := map[int64]int{}
+	for i, tm := range taxLines {
+		report.Lines = append(report.Lines, domain.TaxLineTotalDTO{TaxLineId: tm.TaxLineId, Name: tm.Name, Code: tm.Code, Transactions: []domain.TaxTransactionDTO{}})
+		for _, categoryId := range tm.CategoryIds {
+			lineIndex[categoryId] = i
+		}
+	}
+
+	for _, tm := range transactions {
+		i, ok := lineIndex[tm.CategoryId]
+		if !ok || tm.Status == domain.CANCELLED {
+			continue
+		}
+		transaction := domain.TaxTransactionDTO{
+			TransactionId: tm.TransactionId,
+			Date:          tm.Date,
+			Description:   tm.Description,
+			CategoryId:    tm.CategoryId,
+			Amount:        -signedAmount(tm.Type, tm.Amount),
+			Attachments:   []domain.TaxAttachmentDTO{},
+		}
+		if ts.Attachments != nil {
+			attachments, err := ts.Attachments.RetrieveAttachments(tm.TransactionId)
+			if err != nil {
+				return nil, err
+			}
+			for _, attachment := range attachments {
+				transaction.Attachments = append(transaction.Attachments, domain.TaxAttachmentDTO{AttachmentId: attachment.AttachmentId, FileName: attachment.FileName})
+			}
+		}
+
+		line := &report.Lines[i]
+		line.Transactions = append(line.Transactions, transaction)
+		line.Total += transaction.Amount
+		line.Count++
+	}
+
+	for i := range report.Lines {
+		report.Lines[i].Total = roundCents(report.Lines[i].Total)
+		report.Total += report.Lines[i].Total
+	}
+	report.Total = roundCents(report.Total)
+	return report, nil
+}
+
+// ExportTaxReportCSV writes the tax report with a row for every supporting transaction, each
+// line followed by a row with its total and the report by a row with the grand total.
+func (ts *TaxService) ExportTaxReportCSV(userId uuid.UUID, year int) ([]byte, error) {
+	report, err := ts.RetrieveTaxReport(userId, year)
+	if err != nil {
+		return nil, err
+	}
+	return taxReportCSV(report)
+}
+
+// taxReportCSV goes through export.WriteCSV, which escapes descriptions, tax line names and file
+// names that a spreadsheet would run as a formula.
+func taxReportCSV(report *domain.TaxReportDTO) ([]byte, error) {
+	sheet := export.Sheet{
+		Name:    "Tax report",
+		Columns: []string{"tax_line", "code", "date", "description", "category_id", "amount", "transaction_id", "attachments"},
+	}
+	for _, line := range report.Lines {
+		for _, transaction := range line.Transactions {
+			fileNames := make([]string, 0, len(transaction.Attachments))
+			for _, attachment := range transaction.Attachments {
+				fileNames = append(fileNames, attachment.FileName)
+			}
+			sheet.Rows = append(sheet.Rows, []any{
+				line.Name,
+				line.Code,
+				time.UnixMilli(transaction.Date).UTC().Format(time.DateOnly),
+				transaction.Description,
+				transaction.CategoryId,
+				transaction.Amount,
+				transaction.TransactionId.String(),
+				strings.Join(fileNames, "; "),
+			})
+		}
+		sheet.Rows = append(sheet.Rows, []any{line.Name, line.Code, "", "Total", "", line.Total, "", ""})
+	}
+	sheet.Rows = append(sheet.Rows, []any{"Total", "", "", "", "", report.Total, "", ""})
+	return export.WriteCSV(&export.Document{Title: "Tax report", Sheets: []export.Sheet{sheet}})
+}
+
+func convertTaxLineDTOToModel(from *domain.TaxLineDTO) domain.TaxLineModel {
+	return domain.TaxLineModel{
+		TaxLineId:   from.TaxLineId,
+		UserId:      from.UserId,
+		Name:        from.Name,
+		Code:        from.Code,
+		CategoryIds: slices.Clone(from.CategoryIds),
+		CreatedAt:   from.CreatedAt,
+	}
+}
+
+func convertTaxLineModelToDTO(from *domain.TaxLineModel) domain.TaxLineDTO {
+	return domain.TaxLineDTO{
+		TaxLineId:   from.TaxLineId,
+		UserId:      from.UserId,
+		Name:        from.Name,
+		Code:        from.Code,
+		CategoryIds: from.CategoryIds,
+		CreatedAt:   from.CreatedAt,
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/storage"
+)
+
+func setUpTaxModel(db *sql.DB) {
+	stmt := `create table tax_line (
+		id integer primary key autoincrement,
+		tax_line_id text not null,
+		user_id text not null,
+		name text not null,
+		code text not null,
+		category_ids text not null,
+		created_at integer not null
+	)`
+
+	_, err := db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating the tax line table:", err)
+	}
+}
+
+func TestTaxReport_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpTaxModel(db)
+	udb := database.SQLManager{DB: db}
+	blobs := storage.LocalStorage{Dir: t.TempDir()}
+	attachmentService := AttachmentService{ADBI: &udb, TDBI: &udb, Storage: &blobs}
+	taxService := TaxService{TXDBI: &udb, TDBI: &udb, Attachments: &attachmentService}
+
+	userId := uuid.New()
+	addLine := func(name string, code string, categoryIds ...int64) (*domain.TaxLineDTO, error) {
+		taxLine := domain.TaxLineDTO{UserId: userId, Name: name, Code: code, CategoryIds: categoryIds}
+		return taxService.AddTaxLine(context.Background(), &domain.TaxLineData{TaxLine: taxLine, Validator: validator.New()})
+	}
+	if _, err := addLine(" Charitable donations ", "Schedule A line 11", 4, 4, 9); err != nil {
+		t.Fatal("Error adding the tax line:", err)
+	}
+	if _, err := addLine("Medical", "Schedule A line 1", 5); err != nil {
+		t.Fatal("Error adding the tax line:", err)
+	}
+	if _, err := addLine("Gifts", "", 9); !errors.Is(err, ErrCategoryMapped) {
+		t.Errorf("Expected ErrCategoryMapped for a category on another line, got %v", err)
+	}
+
+	add := func(categoryId int64, transactionType domain.TransactionType, status domain.TransactionStatus, amount float64, date time.Time) domain.TransactionModel {
+		tm := domain.TransactionModelBuilder().Build()
+		tm.UserId = userId
+		tm.CategoryId = categoryId
+		tm.Type = transactionType
+		tm.Status = status
+		tm.Amount = amount
+		tm.Date = date.UnixMilli()
+		tm.Description = "Red Cross, annual"
+		if err := udb.AddTransaction(context.Background(), &tm); err != nil {
+			t.Fatal("Error adding transaction:", err)
+		}
+		return tm
+	}
+	donation := add(4, domain.EXPENSE, domain.CLEARED, 250, time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC))
+	add(9, domain.EXPENSE, domain.PENDING, 40, time.Date(2025, time.December, 31, 23, 0, 0, 0, time.UTC))
+	add(5, domain.EXPENSE, domain.CLEARED, 120, time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC))
+	add(5, domain.INCOME, domain.CLEARED, 20, time.Date(2025, time.June, 9, 12, 0, 0, 0, time.UTC))
+	add(5, domain.EXPENSE, domain.CANCELLED, 999, time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC))
+	add(5, domain.EXPENSE, domain.CLEARED, 999, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+	add(7, domain.EXPENSE, domain.CLEARED, 999, time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC))
+	if _, err := attachmentService.AddAttachment(context.Background(), donation.TransactionId, "receipt.pdf", pdfReceipt); err != nil {
+		t.Fatal("Error adding the attachment:", err)
+	}
+
+	report, err := taxService.RetrieveTaxReport(userId, 2025)
+	if err != nil {
+		t.Fatal("Error retrieving the tax report:", err)
+	}
+	if len(report.Lines) != 2 || report.Total != 390 {
+		t.Fatalf("Expected two lines totalling 390, got %+v", report)
+	}
+	charity, medical := report.Lines[0], report.Lines[1]
+	if charity.Name != "Charitable donations" || charity.Total != 290 || charity.Count != 2 || len(charity.Transactions[0].Attachments) != 1 || charity.Transactions[0].Attachments[0].FileName != "receipt.pdf" {
+		t.Errorf("Unexpected charity line %+v", charity)
+	}
+	if medical.Total != 100 || medical.Count != 2 || medical.Transactions[1].Amount != -20 {
+		t.Errorf("Expected the refund to reduce the medical line, got %+v", medical)
+	}
+
+	data, err := taxService.ExportTaxReportCSV(userId, 2025)
+	if err != nil {
+		t.Fatal("Error exporting the tax report:", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatal("Error reading the exported CSV:", err)
+	}
+	if len(rows) != 8 {
+		t.Fatalf("Expected a header, four transactions, two line totals and the total, got %v", rows)
+	}
+	if rows[1][0] != "Charitable donations" || rows[1][2] != "2025-03-03" || rows[1][3] != "Red Cross, annual" || rows[1][5] != "250.00" || rows[1][7] != "receipt.pdf" {
+		t.Errorf("Unexpected transaction row %v", rows[1])
+	}
+	if rows[3][3] != "Total" || rows[3][5] != "290.00" || rows[7][0] != "Total" || rows[7][5] != "390.00" {
+		t.Errorf("Unexpected total rows %v %v", rows[3], rows[7])
+	}
+}
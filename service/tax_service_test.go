@@ -0,0 +1,43 @@
+package service
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/domain"
+)
+
+func TestTaxReportCSV_EscapesFormulas(t *testing.T) {
+	transaction := domain.TaxTransactionDTO{
+		TransactionId: uuid.New(),
+		Description:   `=HYPERLINK("http://example.com","Refund")`,
+		CategoryId:    5,
+		Amount:        -20,
+		Attachments:   []domain.TaxAttachmentDTO{{AttachmentId: uuid.New(), FileName: "+receipt.pdf"}},
+	}
+	line := domain.TaxLineTotalDTO{Name: "@Medical", Code: "A1", Total: -20, Count: 1, Transactions: []domain.TaxTransactionDTO{transaction}}
+	report := domain.TaxReportDTO{Lines: []domain.TaxLineTotalDTO{line}, Total: -20}
+
+	data, err := taxReportCSV(&report)
+	if err != nil {
+		t.Fatal("Error writing the tax report:", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatal("Error reading the exported CSV:", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Expected a header, the transaction, the line total and the total, got %v", rows)
+	}
+	want := []string{"'@Medical", "A1", "1970-01-01", `'=HYPERLINK("http://example.com","Refund")`, "5", "-20.00", transaction.TransactionId.String(), "'+receipt.pdf"}
+	for i := range want {
+		if rows[1][i] != want[i] {
+			t.Errorf("Wrong cell %d, got %q, want %q", i, rows[1][i], want[i])
+		}
+	}
+	if rows[2][5] != "-20.00" || rows[3][5] != "-20.00" {
+		t.Errorf("Expected the totals to keep their sign, got %v %v", rows[2], rows[3])
+	}
+}
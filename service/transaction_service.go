@@ -1,32 +1,76 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/hld3/personal-finance-go/database"
 	"github.com/hld3/personal-finance-go/domain"
 )
 
 type TransactionServiceInterface interface {
+	AddTransaction(ctx context.Context, transactionData *domain.TransactionData) (*domain.TransactionResultDTO, error)
+	ImportTransactions(ctx context.Context, importData *domain.TransactionImportData) ([]domain.TransactionResultDTO, error)
 	GetTransaction(transactionId uuid.UUID) (*domain.TransactionModel, error)
+	RetrieveTransactions(query *domain.TransactionQueryDTO) ([]domain.TransactionDTO, error)
+	DeleteTransaction(ctx context.Context, transactionId uuid.UUID) error
 }
 
 type TransactionService struct {
-	UDBI database.TransactionDatabaseInterface
+	UDBI        database.TransactionDatabaseInterface
+	Payees      PayeeServiceInterface          // optional, links the transaction to a payee before saving.
+	Rules       RuleServiceInterface           // optional, categorizes the transaction before saving.
+	Tags        TagServiceInterface            // optional, saves the transaction and rule tags.
+	Duplicates  DuplicateServiceInterface      // optional, flags likely duplicates after saving.
+	Classifier  ClassifierServiceInterface     // optional, learns categories, suggests them when missing and unlearns deleted transactions.
+	Attachments AttachmentServiceInterface     // optional, removes the attachments of deleted transactions.
+	Alerts      AlertServiceInterface          // optional, checks budgets and balances after saving.
+	Currencies  CurrencyServiceInterface       // optional, converts the amount into the users base currency before saving.
+	Reconciler  ReconciliationServiceInterface // optional, refuses to delete reconciled transactions.
+	Events      EventBusInterface              // optional, saves transaction.created and transaction.deleted events with the changes.
 }
 
-func (t *TransactionService) AddTransaction(transactionData *domain.TransactionData) error {
+func (t *TransactionService) AddTransaction(ctx context.Context, transactionData *domain.TransactionData) (*domain.TransactionResultDTO, error) {
 	err := transactionData.ValidateTransaction()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	tm := convertTransactionDTOToModel(&transactionData.Transaction)
-	err = t.UDBI.AddTransaction(&tm)
+	result, err := t.saveTransaction(ctx, &tm, transactionData.Transaction.Tags, transactionData.Validator)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return result, t.learn(ctx, tm)
+}
 
-	return nil
+// ImportTransactions validates the whole batch before saving any of it, then saves
+// each transaction the same way AddTransaction does.
+func (t *TransactionService) ImportTransactions(ctx context.Context, importData *domain.TransactionImportData) ([]domain.TransactionResultDTO, error) {
+	for _, transaction := range importData.Transactions {
+		transactionData := domain.TransactionData{Validator: importData.Validator, Transaction: transaction}
+		err := transactionData.ValidateTransaction()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]domain.TransactionResultDTO, 0, len(importData.Transactions))
+	saved := make([]domain.TransactionModel, 0, len(importData.Transactions))
+	for _, transaction := range importData.Transactions {
+		tm := convertTransactionDTOToModel(&transaction)
+		result, err := t.saveTransaction(ctx, &tm, transaction.Tags, importData.Validator)
+		if err != nil {
+			// what was saved before the failure is still learned.
+			return results, errors.Join(err, t.learn(ctx, saved...))
+		}
+		results = append(results, *result)
+		saved = append(saved, tm)
+	}
+	return results, t.learn(ctx, saved...)
 }
 
 func (t *TransactionService) GetTransaction(transactionId uuid.UUID) (*domain.TransactionModel, error) {
@@ -37,18 +81,208 @@ func (t *TransactionService) GetTransaction(transactionId uuid.UUID) (*domain.Tr
 	return &transaction, nil
 }
 
+// RetrieveTransactions returns the users transactions in the date range, with their tags, that pass the tag filter.
+func (t *TransactionService) RetrieveTransactions(query *domain.TransactionQueryDTO) ([]domain.TransactionDTO, error) {
+	transactions, err := t.UDBI.GetTransactionsByUserId(query.UserId, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[uuid.UUID][]string{}
+	if t.Tags != nil {
+		tags, err = t.Tags.RetrieveTransactionTags(query.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transactionDTOs := []domain.TransactionDTO{}
+	for _, tm := range transactions {
+		if !query.TagFilter.Matches(tags[tm.TransactionId]) {
+			continue
+		}
+		transactionDTO := convertTransactionModelToDTO(&tm)
+		transactionDTO.Tags = tags[tm.TransactionId]
+		transactionDTOs = append(transactionDTOs, transactionDTO)
+	}
+	return transactionDTOs, nil
+}
+
+// DeleteTransaction removes the transaction with its tags and attachments. Attachments go first,
+// so a failure leaves the transaction in place to retry the delete.
+func (t *TransactionService) DeleteTransaction(ctx context.Context, transactionId uuid.UUID) error {
+	if t.Reconciler != nil {
+		err := t.Reconciler.CheckUnlocked(transactionId)
+		if err != nil {
+			return err
+		}
+	}
+	// the payload of the deleted event is the transaction as it was, and the classifier unlearns
+	// what it learned from it.
+	var deleted domain.TransactionModel
+	var events []domain.OutboxEventModel
+	if t.Events != nil || t.Classifier != nil {
+		var err error
+		deleted, err = t.UDBI.GetTransaction(transactionId)
+		if err != nil {
+			return err
+		}
+		events, err = recordEvent(t.Events, domain.EVENT_TRANSACTION_DELETED, deleted.UserId, deleted.TransactionId, convertTransactionModelToDTO(&deleted))
+		if err != nil {
+			return err
+		}
+	}
+	// the attachments are deleted with the transaction, their contents once nothing refers to them.
+	var checksums []string
+	if t.Attachments != nil {
+		attachments, err := t.Attachments.RetrieveAttachments(transactionId)
+		if err != nil {
+			return err
+		}
+		for _, attachment := range attachments {
+			checksums = append(checksums, attachment.Checksum)
+		}
+	}
+	err := t.UDBI.DeleteTransaction(ctx, transactionId, events...)
+	if err != nil {
+		return err
+	}
+	if t.Attachments != nil {
+		t.Attachments.RemoveUnusedContents(checksums...)
+	}
+	if t.Classifier != nil {
+		return t.Classifier.Unlearn(ctx, deleted)
+	}
+	return nil
+}
+
+func (t *TransactionService) saveTransaction(ctx context.Context, tm *domain.TransactionModel, tags []string, validator *validator.Validate) (*domain.TransactionResultDTO, error) {
+	// rules, budgets and alerts all work on the amount in the base currency.
+	if t.Currencies != nil {
+		err := t.Currencies.ConvertTransaction(tm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// payees are matched on the raw description, before any rule renames it. A new payee is saved
+	// with the transaction, so a failed insert leaves none behind.
+	var payee *domain.PayeeModel
+	payeeSaved := false
+	if t.Payees != nil {
+		var done func(saved bool)
+		var err error
+		payee, done, err = t.Payees.ResolvePayee(tm)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { done(payeeSaved) }()
+	}
+
+	if t.Rules != nil {
+		change, err := t.Rules.ApplyRules(tm)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(slices.Clip(tags), change.Tags...)
+	}
+
+	result := domain.TransactionResultDTO{Transaction: convertTransactionModelToDTO(tm), Duplicates: []domain.DuplicateDTO{}}
+	// the tags are created and linked with the transaction insert.
+	var tagModels []domain.TagModel
+	if t.Tags != nil && len(tags) > 0 {
+		tags = normalizeTags(tags)
+		update := domain.TagUpdateDTO{UserId: tm.UserId, TransactionIds: []uuid.UUID{tm.TransactionId}, Tags: tags}
+		updateData := domain.TagUpdateData{Validator: validator, Update: update}
+		err := updateData.ValidateTagUpdate()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range tags {
+			tagModels = append(tagModels, newTag(tm.UserId, name))
+		}
+		result.Transaction.Tags = tags
+	}
+
+	events, err := recordEvent(t.Events, domain.EVENT_TRANSACTION_CREATED, tm.UserId, tm.TransactionId, result.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	err = t.UDBI.AddTransactionWith(ctx, tm, payee, tagModels, events...)
+	if err != nil {
+		return nil, err
+	}
+	payeeSaved = true
+
+	if t.Duplicates != nil {
+		duplicates, err := t.Duplicates.CheckTransaction(ctx, tm)
+		if err != nil {
+			return nil, err
+		}
+		result.Duplicates = duplicates
+	}
+
+	// categorized transactions are learned by the callers, once for a whole import.
+	if t.Classifier != nil && tm.CategoryId == 0 {
+		result.Suggestions, err = t.Classifier.Suggest(tm, defaultSuggestionCount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t.Alerts != nil {
+		result.Alerts, err = t.Alerts.CheckTransaction(ctx, tm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+// learn adds the categorized transactions to the classifier.
+func (t *TransactionService) learn(ctx context.Context, transactions ...domain.TransactionModel) error {
+	if t.Classifier == nil {
+		return nil
+	}
+	return t.Classifier.Learn(ctx, transactions...)
+}
+
 func convertTransactionDTOToModel(from *domain.TransactionDTO) domain.TransactionModel {
 	return domain.TransactionModel{
-		UserId:        from.UserId,
-		TransactionId: from.TransactionId,
-		CategoryId:    from.CategoryId,
-		Amount:        from.Amount,
-		Date:          from.Date,
-		Description:   from.Description,
-		CreatedAt:     from.CreatedAt,
-		UpdatedAt:     from.UpdatedAt,
-		Type:          from.Type,
-		PaymentMethod: from.PaymentMethod,
-		Status:        from.Status,
+		UserId:         from.UserId,
+		TransactionId:  from.TransactionId,
+		CategoryId:     from.CategoryId,
+		AccountId:      from.AccountId,
+		PayeeId:        from.PayeeId,
+		Amount:         from.Amount,
+		Date:           from.Date,
+		Description:    from.Description,
+		CreatedAt:      from.CreatedAt,
+		UpdatedAt:      from.UpdatedAt,
+		Type:           from.Type,
+		PaymentMethod:  from.PaymentMethod,
+		Status:         from.Status,
+		Currency:       from.Currency,
+		OriginalAmount: from.Amount,
+	}
+}
+
+func convertTransactionModelToDTO(from *domain.TransactionModel) domain.TransactionDTO {
+	return domain.TransactionDTO{
+		UserId:         from.UserId,
+		TransactionId:  from.TransactionId,
+		CategoryId:     from.CategoryId,
+		AccountId:      from.AccountId,
+		PayeeId:        from.PayeeId,
+		Amount:         from.Amount,
+		Date:           from.Date,
+		Description:    from.Description,
+		CreatedAt:      from.CreatedAt,
+		UpdatedAt:      from.UpdatedAt,
+		Type:           from.Type,
+		PaymentMethod:  from.PaymentMethod,
+		Status:         from.Status,
+		Currency:       from.Currency,
+		OriginalAmount: from.OriginalAmount,
 	}
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"testing"
@@ -19,7 +20,7 @@ func TestServiceAddGetTransaction(t *testing.T) {
 	transactionDTO := domain.TransactionDTOBuilder().Build()
 	transactionData := domain.TransactionData{Transaction: transactionDTO, Validator: validator.New()}
 
-	err := transactionService.AddTransaction(&transactionData)
+	_, err := transactionService.AddTransaction(context.Background(), &transactionData)
 	if err != nil {
 		t.Fatal("Error adding the transaction:", err)
 	}
@@ -32,6 +33,8 @@ func TestServiceAddGetTransaction(t *testing.T) {
 	if transactionDTO.UserId != transactionModel.UserId ||
 		transactionDTO.TransactionId != transactionModel.TransactionId ||
 		transactionDTO.CategoryId != transactionModel.CategoryId ||
+		transactionDTO.AccountId != transactionModel.AccountId ||
+		transactionDTO.PayeeId != transactionModel.PayeeId ||
 		transactionDTO.Amount != transactionModel.Amount ||
 		transactionDTO.Date != transactionModel.Date ||
 		transactionDTO.Description != transactionModel.Description ||
@@ -55,6 +58,8 @@ func setUpTransactionModel() *sql.DB {
 		user_id text not null,
 		transaction_id text not null,
 		category_id integer not null,
+		account_id integer not null,
+		payee_id text not null,
 		amount float not null,
 		date integer not null,
 		description text not null,
@@ -62,7 +67,9 @@ func setUpTransactionModel() *sql.DB {
 		updated_at integer not null,
 		type integer not null,
 		payment_method integer not null,
-		status integer not null
+		status integer not null,
+		currency text not null default '',
+		original_amount float not null default 0
 	)`
 
 	_, err = db.Exec(stmt)
@@ -70,5 +77,19 @@ func setUpTransactionModel() *sql.DB {
 		log.Fatal("There was an error creating transaction_model table:", err)
 	}
 
+	stmt = `create table transaction_tag (
+		transaction_id text not null,
+		tag_id text not null,
+		primary key (transaction_id, tag_id)
+	)`
+
+	_, err = db.Exec(stmt)
+	if err != nil {
+		log.Fatal("There was an error creating transaction_tag table:", err)
+	}
+
+	// deleting a transaction resolves its duplicates and removes its attachments.
+	setUpDuplicateModel(db)
+	setUpAttachmentModel(db)
 	return db
 }
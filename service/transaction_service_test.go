@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -9,7 +10,11 @@ import (
 	"github.com/hld3/personal-finance-go/domain"
 )
 
-func (m *StubDatabase) AddTransaction(tm *domain.TransactionModel) error {
+func (m *StubDatabase) AddTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	return nil
+}
+
+func (m *StubDatabase) AddTransactionWith(ctx context.Context, tm *domain.TransactionModel, payee *domain.PayeeModel, tags []domain.TagModel, events ...domain.OutboxEventModel) error {
 	return nil
 }
 
@@ -18,6 +23,18 @@ func (m *StubDatabase) GetTransaction(transactionId uuid.UUID) (domain.Transacti
 	return transaction, nil
 }
 
+func (m *StubDatabase) GetTransactionsByUserId(userId uuid.UUID, from int64, to int64) ([]domain.TransactionModel, error) {
+	return []domain.TransactionModel{}, nil
+}
+
+func (m *StubDatabase) UpdateTransaction(ctx context.Context, tm *domain.TransactionModel, events ...domain.OutboxEventModel) error {
+	return nil
+}
+
+func (m *StubDatabase) DeleteTransaction(ctx context.Context, transactionId uuid.UUID, events ...domain.OutboxEventModel) error {
+	return nil
+}
+
 // useful, mostly for validation.
 func TestAddTransaction(t *testing.T) {
 	stubDB := new(StubDatabase)
@@ -46,7 +63,7 @@ func TestAddTransaction(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			transactionData := domain.TransactionData{Transaction: test.transaction, Validator: validator.New()}
-			err := transactionService.AddTransaction(&transactionData)
+			_, err := transactionService.AddTransaction(context.Background(), &transactionData)
 
 			if test.wantErr && err == nil {
 				t.Fatal("Expected error, but it was nil")
@@ -62,3 +79,51 @@ func TestAddTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestImportTransactions(t *testing.T) {
+	stubDB := new(StubDatabase)
+	transactionService := TransactionService{UDBI: stubDB}
+
+	valid := []domain.TransactionDTO{domain.TransactionDTOBuilder().Build(), domain.TransactionDTOBuilder().Build()}
+	importData := domain.TransactionImportData{Transactions: valid, Validator: validator.New()}
+	results, err := transactionService.ImportTransactions(context.Background(), &importData)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(results) != len(valid) {
+		t.Fatalf("Wrong number of results, got %d, want %d", len(results), len(valid))
+	}
+
+	invalid := []domain.TransactionDTO{domain.TransactionDTOBuilder().Build(), domain.TransactionDTOBuilder().WithDescription("").Build()}
+	importData = domain.TransactionImportData{Transactions: invalid, Validator: validator.New()}
+	results, err = transactionService.ImportTransactions(context.Background(), &importData)
+	if err == nil {
+		t.Fatal("Expected a validation error, but it was nil")
+	}
+	if results != nil {
+		t.Fatalf("Nothing should be saved when the batch is invalid, got %v", results)
+	}
+}
+
+func TestAddTransaction_AppliesRules(t *testing.T) {
+	stubDB := &StubRuleDatabase{rules: groceryRules()}
+	ruleService := RuleService{RDBI: stubDB, TDBI: stubDB}
+	transactionService := TransactionService{UDBI: stubDB, Rules: &ruleService}
+
+	transaction := domain.TransactionDTOBuilder().WithDescription("Whole Foods Market").Build()
+	transaction.CategoryId = 0
+	transaction.Amount = 25
+	transaction.PaymentMethod = domain.CREDIT_CARD
+	transactionData := domain.TransactionData{Transaction: transaction, Validator: validator.New()}
+
+	result, err := transactionService.AddTransaction(context.Background(), &transactionData)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if result.Transaction.CategoryId != 3 || result.Transaction.Description != "Whole Foods" {
+		t.Errorf("Rules were not applied, got %v", result.Transaction)
+	}
+	if len(result.Transaction.Tags) != 0 {
+		t.Errorf("Tags are only returned once saved, got %v", result.Transaction.Tags)
+	}
+}
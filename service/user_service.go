@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -12,17 +13,18 @@ import (
 )
 
 type UserServiceInterface interface {
-	RegisterNewUser(userData *domain.UserData) error
+	RegisterNewUser(ctx context.Context, userData *domain.UserData) error
 	ConfirmUserLogin(userData *domain.UserData) (*domain.UserProfileDTO, error)
 	RetrieveUserProfileData(userId uuid.UUID) (*domain.UserDTO, error)
-	UpdateUserProfileData(user *domain.UserDTO) error
+	UpdateUserProfileData(ctx context.Context, user *domain.UserDTO) error
 }
 
 type UserService struct {
-	UDBI database.UserDatabaseInterface
+	UDBI   database.UserDatabaseInterface
+	Events EventBusInterface // optional, saves user.registered and user.updated events with the changes.
 }
 
-func (us *UserService) RegisterNewUser(userData *domain.UserData) error {
+func (us *UserService) RegisterNewUser(ctx context.Context, userData *domain.UserData) error {
 	// validate the user fields.
 	err := userData.ValidateUserDTO()
 	if err != nil {
@@ -30,9 +32,13 @@ func (us *UserService) RegisterNewUser(userData *domain.UserData) error {
 	}
 
 	userModel := convertUserDTOToModel(userData.User)
+	events, err := recordEvent(us.Events, domain.EVENT_USER_REGISTERED, userModel.UserId, userModel.UserId, convertUserModelToEventDTO(&userModel))
+	if err != nil {
+		return err
+	}
 
 	// save the user to the database
-	err = us.UDBI.AddNewUser(&userModel)
+	err = us.UDBI.AddNewUser(ctx, &userModel, events...)
 	if err != nil {
 		return err
 	}
@@ -75,8 +81,24 @@ func (us *UserService) RetrieveUserProfileData(userId uuid.UUID) (*domain.UserDT
 	return &userDTO, nil
 }
 
-func (us *UserService) UpdateUserProfileData(user *domain.UserDTO) error {
-	err := us.UDBI.UpdateUserByUserId(user)
+func (us *UserService) UpdateUserProfileData(ctx context.Context, user *domain.UserDTO) error {
+	// the payload of the updated event is the whole profile, so the fields the update leaves
+	// alone are read first.
+	var events []domain.OutboxEventModel
+	if us.Events != nil {
+		userModel, err := us.UDBI.RetrieveUserByUserId(user.UserId)
+		if err != nil {
+			return err
+		}
+		userModel.FirstName, userModel.LastName, userModel.Email = user.FirstName, user.LastName, user.Email
+		userModel.Phone, userModel.DateOfBirth = user.Phone, user.DateOfBirth
+		events, err = recordEvent(us.Events, domain.EVENT_USER_UPDATED, userModel.UserId, userModel.UserId, convertUserModelToEventDTO(&userModel))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := us.UDBI.UpdateUserByUserId(ctx, user, events...)
 	if err != nil {
 		return err
 	}
@@ -110,6 +132,18 @@ func convertUserModelToDTO(from *domain.UserModel) domain.UserDTO {
 	}
 }
 
+func convertUserModelToEventDTO(from *domain.UserModel) domain.UserEventDTO {
+	return domain.UserEventDTO{
+		UserId:       from.UserId,
+		FirstName:    from.FirstName,
+		LastName:     from.LastName,
+		Email:        from.Email,
+		Phone:        from.Phone,
+		DateOfBirth:  from.DateOfBirth,
+		CreationDate: from.CreationDate,
+	}
+}
+
 // TODO move this to utility some day.
 func HashPassword(password string, userId uuid.UUID) string {
 	hashedPass, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
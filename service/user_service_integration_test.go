@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"strings"
@@ -35,7 +36,7 @@ func TestRegisterNewUser_Integration(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			userData := domain.UserData{User: &tt.userDTO, Validator: validator.New()}
-			err := userService.RegisterNewUser(&userData)
+			err := userService.RegisterNewUser(context.Background(), &userData)
 			if (err != nil) && !tt.wantErr {
 				t.Errorf("RegisterNewUser: %s, unexpected error: %v", tt.name, err)
 			}
@@ -44,6 +45,7 @@ func TestRegisterNewUser_Integration(t *testing.T) {
 }
 
 func TestConfirmUserLogin_Integration(t *testing.T) {
+	t.Setenv("JWT_KEY", "test-key")
 	db := setUpUserModel()
 	defer db.Close()
 	udb := database.SQLManager{DB: db}
@@ -161,7 +163,7 @@ func TestUpdateUserProfileData_Integration(t *testing.T) {
 
 	// The data to update the user is sent
 	expected := domain.UserDTOBuilder().WithUserId(savedUser.UserId).Build()
-	err = userService.UpdateUserProfileData(&expected)
+	err = userService.UpdateUserProfileData(context.Background(), &expected)
 	if err != nil {
 		t.Error("Error updating user data:", err)
 	}
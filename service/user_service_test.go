@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -14,7 +15,7 @@ var pw = "super_private_password"
 
 type StubDatabase struct{}
 
-func (m *StubDatabase) AddNewUser(user *domain.UserModel) error {
+func (m *StubDatabase) AddNewUser(ctx context.Context, user *domain.UserModel, events ...domain.OutboxEventModel) error {
 	return nil
 }
 
@@ -30,7 +31,7 @@ func (m *StubDatabase) RetrieveUserByUserId(userId uuid.UUID) (domain.UserModel,
 	return domain.UserModelBuilder().Build(), nil
 }
 
-func (m *StubDatabase) UpdateUserByUserId(user *domain.UserDTO) error {
+func (m *StubDatabase) UpdateUserByUserId(ctx context.Context, user *domain.UserDTO, events ...domain.OutboxEventModel) error {
 	return nil
 }
 
@@ -61,7 +62,7 @@ func TestRegisterNewUser_Validation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			userData := domain.UserData{User: &tt.user, Validator: validator.New()}
-			err := userService.RegisterNewUser(&userData)
+			err := userService.RegisterNewUser(context.Background(), &userData)
 
 			if tt.wantErr {
 				if err == nil {
@@ -79,6 +80,7 @@ func TestRegisterNewUser_Validation(t *testing.T) {
 }
 
 func TestConfirmUserLogin(t *testing.T) {
+	t.Setenv("JWT_KEY", "test-key")
 	stubDB := new(StubDatabase)
 	userService := UserService{UDBI: stubDB}
 
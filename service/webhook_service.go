@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
+)
+
+const (
+	webhookMaxAttempts  = 8
+	webhookRetryDelay   = 30 * time.Second // doubled after every failed attempt.
+	webhookPollInterval = 5 * time.Second
+	webhookBatchSize    = 50
+	webhookLogLimit     = 100
+	webhookSecretBytes  = 24
+)
+
+type WebhookServiceInterface interface {
+	AddWebhook(ctx context.Context, webhookData *domain.WebhookSubscriptionData) (*domain.WebhookSubscriptionDTO, error)
+	RetrieveWebhooks(userId uuid.UUID) ([]domain.WebhookSubscriptionDTO, error)
+	DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error
+	RetrieveDeliveries(webhookId uuid.UUID) ([]domain.WebhookDeliveryDTO, error)
+	Redeliver(deliveryId uuid.UUID) (*domain.WebhookDeliveryDTO, error)
+	HandleEvent(event *domain.EventDTO) error
+}
+
+type WebhookService struct {
+	WHDBI   database.WebhookDatabaseInterface
+	Channel *notify.WebhookChannel
+}
+
+// AddWebhook subscribes the URL to the events, with a generated secret unless one is given.
+// The secret is only ever returned here.
+func (ws *WebhookService) AddWebhook(ctx context.Context, webhookData *domain.WebhookSubscriptionData) (*domain.WebhookSubscriptionDTO, error) {
+	err := webhookData.ValidateWebhook()
+	if err != nil {
+		return nil, err
+	}
+
+	wm := convertWebhookDTOToModel(&webhookData.Webhook)
+	wm.WebhookId = uuid.New()
+	wm.CreatedAt = time.Now().UnixMilli()
+	slices.Sort(wm.Events)
+	wm.Events = slices.Compact(wm.Events)
+	if wm.Secret == "" {
+		secret := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		wm.Secret = hex.EncodeToString(secret)
+	}
+
+	err = ws.WHDBI.AddWebhook(ctx, &wm)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := convertWebhookModelToDTO(&wm)
+	saved.Secret = wm.Secret
+	return &saved, nil
+}
+
+func (ws *WebhookService) RetrieveWebhooks(userId uuid.UUID) ([]domain.WebhookSubscriptionDTO, error) {
+	webhooks, err := ws.WHDBI.GetWebhooksByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookDTOs := make([]domain.WebhookSubscriptionDTO, 0, len(webhooks))
+	for _, wm := range webhooks {
+		webhookDTOs = append(webhookDTOs, convertWebhookModelToDTO(&wm))
+	}
+	return webhookDTOs, nil
+}
+
+func (ws *WebhookService) DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error {
+	return ws.WHDBI.DeleteWebhook(ctx, webhookId)
+}
+
+// RetrieveDeliveries is the delivery log of the webhook, the latest webhookLogLimit deliveries
+// newest first.
+func (ws *WebhookService) RetrieveDeliveries(webhookId uuid.UUID) ([]domain.WebhookDeliveryDTO, error) {
+	deliveries, err := ws.WHDBI.GetWebhookDeliveries(webhookId, webhookLogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryDTOs := make([]domain.WebhookDeliveryDTO, 0, len(deliveries))
+	for _, dm := range deliveries {
+		deliveryDTOs = append(deliveryDTOs, convertDeliveryModelToDTO(&dm))
+	}
+	return deliveryDTOs, nil
+}
+
+// Redeliver queues the payload of a delivery again as a new delivery, whatever the outcome of
+// the original. The event id in the payload stays the same, so receivers can tell it apart
+// from a new event.
+func (ws *WebhookService) Redeliver(deliveryId uuid.UUID) (*domain.WebhookDeliveryDTO, error) {
+	original, err := ws.WHDBI.GetWebhookDelivery(deliveryId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	dm := domain.WebhookDeliveryModel{
+		DeliveryId:    uuid.New(),
+		WebhookId:     original.WebhookId,
+		UserId:        original.UserId,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        domain.DELIVERY_PENDING,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	err = ws.WHDBI.AddWebhookDelivery(&dm)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := convertDeliveryModelToDTO(&dm)
+	return &delivery, nil
+}
+
+// HandleEvent is the event bus subscriber of the webhook events. It queues a delivery of the
+// event to every webhook of the user subscribed to it, sent by DeliverDue. The id of the payload
+// is the id of the domain event, the same when the event bus hands the event over again.
+func (ws *WebhookService) HandleEvent(event *domain.EventDTO) error {
+	webhookEvent := domain.WebhookEvent(event.Type)
+	webhooks, err := ws.WHDBI.GetWebhooksByUserId(event.UserId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	var payload []byte
+	for _, wm := range webhooks {
+		if !slices.Contains(wm.Events, webhookEvent) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(domain.WebhookPayloadDTO{Id: event.EventId, Event: webhookEvent, CreatedAt: event.CreatedAt, Data: event.Data})
+			if err != nil {
+				return err
+			}
+		}
+
+		dm := domain.WebhookDeliveryModel{
+			DeliveryId:    uuid.New(),
+			WebhookId:     wm.WebhookId,
+			UserId:        event.UserId,
+			Event:         webhookEvent,
+			Payload:       string(payload),
+			Status:        domain.DELIVERY_PENDING,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		err = ws.WHDBI.AddWebhookDelivery(&dm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue attempts the pending deliveries due at now. A failed attempt is retried after
+// webhookRetryDelay, doubling with every attempt, until webhookMaxAttempts have failed.
+func (ws *WebhookService) DeliverDue(now time.Time) error {
+	deliveries, err := ws.WHDBI.GetDueWebhookDeliveries(now.UnixMilli(), webhookBatchSize)
+	if err != nil {
+		return err
+	}
+
+	webhooks := map[uuid.UUID]domain.WebhookSubscriptionModel{}
+	for i := range deliveries {
+		dm := &deliveries[i]
+		wm, ok := webhooks[dm.WebhookId]
+		if !ok {
+			wm, err = ws.WHDBI.GetWebhook(dm.WebhookId)
+			if err != nil {
+				return err
+			}
+			webhooks[dm.WebhookId] = wm
+		}
+
+		err = ws.attempt(dm, &wm, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDeliveries sends the due deliveries every webhookPollInterval. It never returns and is
+// meant to run in its own goroutine.
+func (ws *WebhookService) RunDeliveries() {
+	for {
+		if err := ws.DeliverDue(time.Now()); err != nil {
+			log.Println("Error delivering webhooks:", err)
+		}
+		time.Sleep(webhookPollInterval)
+	}
+}
+
+func (ws *WebhookService) attempt(dm *domain.WebhookDeliveryModel, wm *domain.WebhookSubscriptionModel, now time.Time) error {
+	headers := map[string]string{"X-Webhook-Event": string(dm.Event), "X-Webhook-Delivery": dm.DeliveryId.String()}
+	status, err := ws.Channel.PostSigned(wm.URL, wm.Secret, headers, []byte(dm.Payload))
+
+	dm.Attempts++
+	dm.ResponseStatus = status
+	dm.UpdatedAt = now.UnixMilli()
+	switch {
+	case err == nil:
+		dm.Status = domain.DELIVERY_SUCCEEDED
+		dm.Error = ""
+	case dm.Attempts >= webhookMaxAttempts:
+		dm.Status = domain.DELIVERY_FAILED
+		dm.Error = err.Error()
+	default:
+		dm.Error = err.Error()
+		dm.NextAttemptAt = now.Add(webhookRetryDelay << (dm.Attempts - 1)).UnixMilli()
+	}
+	if err != nil {
+		log.Printf("Error delivering %s to webhook %v, attempt %d: %v\n", dm.Event, wm.WebhookId, dm.Attempts, err)
+	}
+	return ws.WHDBI.UpdateWebhookDelivery(dm)
+}
+
+func convertWebhookDTOToModel(from *domain.WebhookSubscriptionDTO) domain.WebhookSubscriptionModel {
+	return domain.WebhookSubscriptionModel{
+		WebhookId: from.WebhookId,
+		UserId:    from.UserId,
+		URL:       from.URL,
+		Events:    slices.Clone(from.Events),
+		Secret:    from.Secret,
+		CreatedAt: from.CreatedAt,
+	}
+}
+
+// convertWebhookModelToDTO leaves out the secret.
+func convertWebhookModelToDTO(from *domain.WebhookSubscriptionModel) domain.WebhookSubscriptionDTO {
+	return domain.WebhookSubscriptionDTO{
+		WebhookId: from.WebhookId,
+		UserId:    from.UserId,
+		URL:       from.URL,
+		Events:    slices.Clone(from.Events),
+		CreatedAt: from.CreatedAt,
+	}
+}
+
+func convertDeliveryModelToDTO(from *domain.WebhookDeliveryModel) domain.WebhookDeliveryDTO {
+	return domain.WebhookDeliveryDTO{
+		DeliveryId:     from.DeliveryId,
+		WebhookId:      from.WebhookId,
+		UserId:         from.UserId,
+		Event:          from.Event,
+		Payload:        json.RawMessage(from.Payload),
+		Status:         from.Status,
+		Attempts:       from.Attempts,
+		ResponseStatus: from.ResponseStatus,
+		Error:          from.Error,
+		NextAttemptAt:  from.NextAttemptAt,
+		CreatedAt:      from.CreatedAt,
+		UpdatedAt:      from.UpdatedAt,
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hld3/personal-finance-go/database"
+	"github.com/hld3/personal-finance-go/domain"
+	"github.com/hld3/personal-finance-go/notify"
+)
+
+func setUpWebhookModel(db *sql.DB) {
+	stmts := []string{
+		`create table webhook_subscription (
+			id integer primary key autoincrement,
+			webhook_id text not null unique,
+			user_id text not null,
+			url text not null,
+			events text not null,
+			secret text not null,
+			created_at integer not null
+		)`,
+		`create table webhook_delivery (
+			id integer primary key autoincrement,
+			delivery_id text not null unique,
+			webhook_id text not null,
+			user_id text not null,
+			event text not null,
+			payload text not null,
+			status integer not null,
+			attempts integer not null,
+			response_status integer not null,
+			error text not null,
+			next_attempt_at integer not null,
+			created_at integer not null,
+			updated_at integer not null
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		if err != nil {
+			log.Fatal("There was an error creating the webhook tables:", err)
+		}
+	}
+}
+
+// webhookReceiver answers with the queued status codes, then 200.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.requests = append(wr.requests, r)
+	wr.bodies = append(wr.bodies, body)
+	if len(wr.statuses) > 0 {
+		w.WriteHeader(wr.statuses[0])
+		wr.statuses = wr.statuses[1:]
+	}
+}
+
+func TestWebhooks_Integration(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpWebhookModel(db)
+	setUpOutboxModel(db)
+	udb := database.SQLManager{DB: db}
+	receiver := &webhookReceiver{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	webhookService := WebhookService{WHDBI: &udb, Channel: &notify.WebhookChannel{Client: server.Client()}}
+	eventBus := EventBus{OBDBI: &udb}
+	eventBus.Subscribe("webhooks", domain.EVENT_TRANSACTION_CREATED, webhookService.HandleEvent)
+	eventBus.Subscribe("webhooks", domain.EVENT_TRANSACTION_DELETED, webhookService.HandleEvent)
+	transactionService := TransactionService{UDBI: &udb, Events: &eventBus}
+
+	transaction := domain.TransactionDTOBuilder().Build()
+	events := []domain.WebhookEvent{domain.TRANSACTION_DELETED, domain.TRANSACTION_CREATED, domain.TRANSACTION_CREATED}
+	webhook := domain.WebhookSubscriptionDTO{UserId: transaction.UserId, URL: server.URL, Events: events}
+	saved, err := webhookService.AddWebhook(context.Background(), &domain.WebhookSubscriptionData{Webhook: webhook, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding the webhook:", err)
+	}
+	if len(saved.Secret) != 2*webhookSecretBytes || len(saved.Events) != 2 {
+		t.Fatalf("Unexpected webhook %+v", saved)
+	}
+	// not subscribed to transaction events.
+	profileHook := domain.WebhookSubscriptionDTO{UserId: transaction.UserId, URL: server.URL, Events: []domain.WebhookEvent{domain.USER_UPDATED}}
+	if _, err := webhookService.AddWebhook(context.Background(), &domain.WebhookSubscriptionData{Webhook: profileHook, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the webhook:", err)
+	}
+
+	if _, err := transactionService.AddTransaction(context.Background(), &domain.TransactionData{Transaction: transaction, Validator: validator.New()}); err != nil {
+		t.Fatal("Error adding the transaction:", err)
+	}
+
+	// the first attempt fails and is retried after webhookRetryDelay.
+	now := time.Now().Add(time.Second)
+	if err := eventBus.DispatchPending(now); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	if err := webhookService.DeliverDue(now); err != nil {
+		t.Fatal("Error delivering webhooks:", err)
+	}
+	deliveries, err := webhookService.RetrieveDeliveries(saved.WebhookId)
+	if err != nil {
+		t.Fatal("Error retrieving the deliveries:", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != domain.DELIVERY_PENDING || deliveries[0].Attempts != 1 || deliveries[0].ResponseStatus != http.StatusInternalServerError ||
+		deliveries[0].NextAttemptAt != now.Add(webhookRetryDelay).UnixMilli() {
+		t.Fatalf("Unexpected deliveries after the failed attempt %+v", deliveries)
+	}
+
+	if err := webhookService.DeliverDue(now.Add(webhookRetryDelay - time.Second)); err != nil {
+		t.Fatal("Error delivering webhooks:", err)
+	}
+	if len(receiver.requests) != 1 {
+		t.Fatalf("The delivery was retried before it was due, %d requests", len(receiver.requests))
+	}
+	if err := webhookService.DeliverDue(now.Add(webhookRetryDelay)); err != nil {
+		t.Fatal("Error delivering webhooks:", err)
+	}
+	delivery, err := udb.GetWebhookDelivery(deliveries[0].DeliveryId)
+	if err != nil {
+		t.Fatal("Error retrieving the delivery:", err)
+	}
+	if delivery.Status != domain.DELIVERY_SUCCEEDED || delivery.Attempts != 2 || delivery.ResponseStatus != http.StatusOK || delivery.Error != "" {
+		t.Fatalf("Unexpected delivery after the retry %+v", delivery)
+	}
+
+	if len(receiver.requests) != 2 {
+		t.Fatalf("Unexpected number of requests %d", len(receiver.requests))
+	}
+	request, body := receiver.requests[1], receiver.bodies[1]
+	timestamp, err := strconv.ParseInt(request.Header.Get(notify.TimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatal("Error reading the timestamp:", err)
+	}
+	if signature := request.Header.Get(notify.SignatureHeader); signature != notify.SignWebhook(saved.Secret, timestamp, body) {
+		t.Errorf("Wrong signature %q", signature)
+	}
+	if request.Header.Get("X-Webhook-Event") != string(domain.TRANSACTION_CREATED) || request.Header.Get("X-Webhook-Delivery") != delivery.DeliveryId.String() {
+		t.Errorf("Unexpected headers %v", request.Header)
+	}
+	var payload struct {
+		Id    uuid.UUID             `json:"id"`
+		Event domain.WebhookEvent   `json:"event"`
+		Data  domain.TransactionDTO `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal("Error reading the payload:", err)
+	}
+	if payload.Event != domain.TRANSACTION_CREATED || payload.Data.TransactionId != transaction.TransactionId || payload.Data.Amount != transaction.Amount {
+		t.Errorf("Unexpected payload %s", body)
+	}
+
+	if err := transactionService.DeleteTransaction(context.Background(), transaction.TransactionId); err != nil {
+		t.Fatal("Error deleting the transaction:", err)
+	}
+	if err := eventBus.DispatchPending(time.Now().Add(time.Second)); err != nil {
+		t.Fatal("Error dispatching events:", err)
+	}
+	redelivered, err := webhookService.Redeliver(delivery.DeliveryId)
+	if err != nil {
+		t.Fatal("Error redelivering:", err)
+	}
+	if redelivered.DeliveryId == delivery.DeliveryId || redelivered.Status != domain.DELIVERY_PENDING || redelivered.Attempts != 0 {
+		t.Fatalf("Unexpected redelivery %+v", redelivered)
+	}
+	if err := webhookService.DeliverDue(time.Now().Add(time.Second)); err != nil {
+		t.Fatal("Error delivering webhooks:", err)
+	}
+	if len(receiver.requests) != 4 {
+		t.Fatalf("Unexpected number of requests %d", len(receiver.requests))
+	}
+	bodies := map[string]string{}
+	for i, request := range receiver.requests[2:] {
+		bodies[request.Header.Get("X-Webhook-Delivery")] = string(receiver.bodies[2+i])
+	}
+	if bodies[redelivered.DeliveryId.String()] != string(body) {
+		t.Errorf("The redelivery did not resend the payload, got %s", bodies[redelivered.DeliveryId.String()])
+	}
+
+	deliveries, err = webhookService.RetrieveDeliveries(saved.WebhookId)
+	if err != nil {
+		t.Fatal("Error retrieving the deliveries:", err)
+	}
+	if len(deliveries) != 3 {
+		t.Fatalf("Unexpected delivery log %+v", deliveries)
+	}
+	for _, delivery := range deliveries {
+		if delivery.Status != domain.DELIVERY_SUCCEEDED {
+			t.Errorf("Delivery %v of %s not sent", delivery.DeliveryId, delivery.Event)
+		}
+	}
+
+	webhooks, err := webhookService.RetrieveWebhooks(transaction.UserId)
+	if err != nil {
+		t.Fatal("Error retrieving the webhooks:", err)
+	}
+	if len(webhooks) != 2 || webhooks[0].Secret != "" || webhooks[1].Secret != "" {
+		t.Errorf("Unexpected webhooks %+v", webhooks)
+	}
+}
+
+func TestWebhooks_GiveUp(t *testing.T) {
+	db := setUpTransactionModel()
+	defer db.Close()
+	setUpWebhookModel(db)
+	udb := database.SQLManager{DB: db}
+	receiver := &webhookReceiver{}
+	for i := 0; i < webhookMaxAttempts; i++ {
+		receiver.statuses = append(receiver.statuses, http.StatusServiceUnavailable)
+	}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	webhookService := WebhookService{WHDBI: &udb, Channel: &notify.WebhookChannel{Client: server.Client()}}
+
+	userId := uuid.New()
+	webhook := domain.WebhookSubscriptionDTO{UserId: userId, URL: server.URL, Events: []domain.WebhookEvent{domain.USER_UPDATED}, Secret: "0123456789abcdef"}
+	saved, err := webhookService.AddWebhook(context.Background(), &domain.WebhookSubscriptionData{Webhook: webhook, Validator: validator.New()})
+	if err != nil {
+		t.Fatal("Error adding the webhook:", err)
+	}
+	if saved.Secret != webhook.Secret {
+		t.Errorf("The given secret was replaced by %q", saved.Secret)
+	}
+	event := domain.EventDTO{EventId: uuid.New(), Type: domain.EVENT_USER_UPDATED, UserId: userId, AggregateId: userId, Data: json.RawMessage(`{}`)}
+	if err := webhookService.HandleEvent(&event); err != nil {
+		t.Fatal("Error handling the event:", err)
+	}
+
+	// deliveries keep milliseconds.
+	now := time.Now().Add(time.Second).Truncate(time.Millisecond)
+	delays := []time.Duration{}
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		if err := webhookService.DeliverDue(now); err != nil {
+			t.Fatal("Error delivering webhooks:", err)
+		}
+		deliveries, err := webhookService.RetrieveDeliveries(saved.WebhookId)
+		if err != nil {
+			t.Fatal("Error retrieving the deliveries:", err)
+		}
+		delivery := deliveries[0]
+		if delivery.Attempts != attempt {
+			t.Fatalf("Attempt %d not made, %+v", attempt, delivery)
+		}
+		if attempt == webhookMaxAttempts {
+			if delivery.Status != domain.DELIVERY_FAILED || delivery.ResponseStatus != http.StatusServiceUnavailable || delivery.Error == "" {
+				t.Errorf("Unexpected delivery after the last attempt %+v", delivery)
+			}
+			break
+		}
+		next := time.UnixMilli(delivery.NextAttemptAt)
+		delays = append(delays, next.Sub(now))
+		now = next
+	}
+
+	for i, delay := range delays {
+		if want := webhookRetryDelay << i; delay != want {
+			t.Errorf("Wrong delay before attempt %d: got %v, want %v", i+2, delay, want)
+		}
+	}
+	if err := webhookService.DeliverDue(now.Add(24 * time.Hour)); err != nil {
+		t.Fatal("Error delivering webhooks:", err)
+	}
+	if len(receiver.requests) != webhookMaxAttempts {
+		t.Errorf("Failed delivery attempted again, %d requests", len(receiver.requests))
+	}
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage keeps blobs as files under Dir, spread over subdirectories named by the first two
+// characters of the key.
+type LocalStorage struct {
+	Dir string
+}
+
+func (ls *LocalStorage) Put(key string, data []byte, contentType string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		log.Println("Error creating the attachment directory:", err)
+		return err
+	}
+
+	// write to a temporary file first so a failed write never leaves a partial blob behind.
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		log.Println("Error creating the attachment file:", err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Println("Error writing the attachment file:", err)
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (ls *LocalStorage) Get(key string) ([]byte, error) {
+	path, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		log.Println("Error reading the attachment file:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// Delete removes the blob, a missing blob is not an error.
+func (ls *LocalStorage) Delete(key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Println("Error deleting the attachment file:", err)
+		return err
+	}
+	return nil
+}
+
+func (ls *LocalStorage) path(key string) (string, error) {
+	if !validKey(key) || len(key) < 3 {
+		return "", errInvalidKey
+	}
+	return filepath.Join(ls.Dir, key[:2], key), nil
+}
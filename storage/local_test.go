@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ls := LocalStorage{Dir: t.TempDir()}
+	key := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	data := []byte("receipt")
+
+	err := ls.Put(key, data, "text/plain")
+	if err != nil {
+		t.Fatal("Error storing the blob:", err)
+	}
+	if _, err := os.Stat(filepath.Join(ls.Dir, "9f", key)); err != nil {
+		t.Error("Blob was not written to its directory:", err)
+	}
+
+	stored, err := ls.Get(key)
+	if err != nil || !bytes.Equal(stored, data) {
+		t.Fatalf("Wrong blob, got %q, err: %v", stored, err)
+	}
+
+	err = ls.Delete(key)
+	if err != nil {
+		t.Fatal("Error deleting the blob:", err)
+	}
+	if _, err := ls.Get(key); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound, got %v", err)
+	}
+	if err := ls.Delete(key); err != nil {
+		t.Errorf("Deleting a missing blob should not fail, got %v", err)
+	}
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	ls := LocalStorage{Dir: t.TempDir()}
+
+	for _, key := range []string{"", "ab", "../../etc/passwd", "ABCDEF"} {
+		if err := ls.Put(key, []byte("x"), ""); !errors.Is(err, errInvalidKey) {
+			t.Errorf("Expected errInvalidKey for %q, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Storage keeps blobs in a bucket of an S3 compatible service (AWS S3, MinIO, ...), using
+// path style requests signed with AWS signature version 4.
+type S3Storage struct {
+	Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	Region    string // defaults to us-east-1
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Client    *http.Client // defaults to a client with a 60 second timeout.
+}
+
+var defaultS3Client = &http.Client{Timeout: 60 * time.Second}
+
+func (s *S3Storage) Put(key string, data []byte, contentType string) error {
+	res, err := s.do(http.MethodPut, key, data, contentType)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s.statusError(res)
+	}
+	return nil
+}
+
+func (s *S3Storage) Get(key string) ([]byte, error) {
+	res, err := s.do(http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrBlobNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, s.statusError(res)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// Delete removes the blob, a missing blob is not an error.
+func (s *S3Storage) Delete(key string) error {
+	res, err := s.do(http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return s.statusError(res)
+	}
+	return nil
+}
+
+func (s *S3Storage) do(method string, key string, body []byte, contentType string) (*http.Response, error) {
+	if !validKey(key) {
+		return nil, errInvalidKey
+	}
+
+	objectURL := strings.TrimSuffix(s.Endpoint, "/") + "/" + url.PathEscape(s.Bucket) + "/" + key
+	req, err := http.NewRequest(method, objectURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	region := s.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	signV4(req, s.AccessKey, s.SecretKey, region, "s3", time.Now())
+
+	res, err := s.client().Do(req)
+	if err != nil {
+		log.Printf("Error calling the object storage, %s %s: %v\n", method, key, err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *S3Storage) client() *http.Client {
+	if s.Client == nil {
+		return defaultS3Client
+	}
+	return s.Client
+}
+
+func (s *S3Storage) statusError(res *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	log.Printf("Object storage returned %s: %s\n", res.Status, message)
+	return fmt.Errorf("object storage returned %s", res.Status)
+}
+
+// signV4 adds the X-Amz-Date and Authorization headers. The host and every X-Amz header already
+// on the request are signed, the payload hash is taken from X-Amz-Content-Sha256 when present.
+func signV4(req *http.Request, accessKey string, secretKey string, region string, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		emptyHash := sha256.Sum256(nil)
+		payloadHash = hex.EncodeToString(emptyHash[:])
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent encodes everything except the unreserved characters, as signature version 4 expects.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSignV4 checks the signer against the get-vanilla case of the AWS signature version 4 test suite.
+func TestSignV4(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal("Error building the request:", err)
+	}
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	signV4(req, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service", now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Wrong authorization header,\ngot  %s\nwant %s", got, want)
+	}
+}
+
+// s3StandIn is a minimal in-memory stand-in for an S3 compatible service.
+type s3StandIn struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	payloadHash := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(payloadHash[:]) {
+		http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = body
+	case http.MethodGet:
+		object, ok := s.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(object)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Storage_DefaultClientTimesOut(t *testing.T) {
+	s3 := S3Storage{}
+	if s3.client().Timeout == 0 {
+		t.Error("Expected the default client to have a timeout")
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	standIn := &s3StandIn{objects: map[string][]byte{}}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	s3 := S3Storage{Endpoint: server.URL, Bucket: "receipts", AccessKey: "access", SecretKey: "secret"}
+	key := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	data := []byte("%PDF-1.4 receipt")
+
+	err := s3.Put(key, data, "application/pdf")
+	if err != nil {
+		t.Fatal("Error storing the blob:", err)
+	}
+	if _, ok := standIn.objects["/receipts/"+key]; !ok {
+		t.Fatalf("Blob was not stored under its bucket, got %v", standIn.objects)
+	}
+
+	stored, err := s3.Get(key)
+	if err != nil || !bytes.Equal(stored, data) {
+		t.Fatalf("Wrong blob, got %q, err: %v", stored, err)
+	}
+
+	err = s3.Delete(key)
+	if err != nil {
+		t.Fatal("Error deleting the blob:", err)
+	}
+	if _, err := s3.Get(key); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound, got %v", err)
+	}
+
+	s3.AccessKey = "someone-else"
+	if err := s3.Put(key, data, "application/pdf"); err == nil {
+		t.Error("Expected an error when the storage refuses the request")
+	}
+}
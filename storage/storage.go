@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"errors"
+	"log"
+	"os"
+)
+
+// BlobStorageInterface keeps attachment contents. Keys are SHA-256 checksums in hex,
+// so the same contents are only ever stored once.
+type BlobStorageInterface interface {
+	Put(key string, data []byte, contentType string) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+}
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+var errInvalidKey = errors.New("invalid blob key")
+
+// ConnectStorage picks the blob storage from the environment. With ATTACHMENT_STORAGE=s3 the
+// S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY variables are used,
+// otherwise blobs are kept under ATTACHMENT_DIR on the local filesystem.
+func ConnectStorage() BlobStorageInterface {
+	if os.Getenv("ATTACHMENT_STORAGE") == "s3" {
+		s3 := S3Storage{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}
+		if s3.Endpoint == "" || s3.Bucket == "" {
+			log.Fatal("S3_ENDPOINT and S3_BUCKET are required for s3 attachment storage")
+		}
+		log.Println("Storing attachments in bucket", s3.Bucket)
+		return &s3
+	}
+
+	dir := os.Getenv("ATTACHMENT_DIR")
+	if dir == "" {
+		dir = "attachments"
+	}
+	log.Println("Storing attachments in", dir)
+	return &LocalStorage{Dir: dir}
+}
+
+// validKey only allows lowercase hex keys, they end up in file paths and URLs.
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
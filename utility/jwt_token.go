@@ -1,15 +1,31 @@
 package utility
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
-var jwtKey = []byte(os.Getenv("JWT_KEY"))
+// jwtKey is the key tokens are signed with. It is read when used, the environment is loaded from
+// the .env file after the packages are initialised, and tokens are neither signed nor verified
+// without one.
+func jwtKey() ([]byte, error) {
+	key := os.Getenv("JWT_KEY")
+	if key == "" {
+		return nil, errors.New("the JWT_KEY is not set")
+	}
+	return []byte(key), nil
+}
 
 func CreateJWTToken(userId string, duration time.Duration) (string, error) {
+	key, err := jwtKey()
+	if err != nil {
+		return "", err
+	}
+
 	expirationTime := time.Now().Add(duration)
 	claims := &jwt.StandardClaims{
 		Issuer:    os.Getenv("JWT_ISSUER"),
@@ -18,10 +34,30 @@ func CreateJWTToken(userId string, duration time.Duration) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		return "", err
 	}
 
 	return tokenString, nil
 }
+
+// ParseJWTToken verifies a token created by CreateJWTToken and returns its subject, the user id.
+func ParseJWTToken(tokenString string) (string, error) {
+	key, err := jwtKey()
+	if err != nil {
+		return "", err
+	}
+
+	claims := &jwt.StandardClaims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return key, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
@@ -24,7 +24,7 @@ func TestCreateJWTToken(t *testing.T) {
 	}
 
 	parsedToken, err := jwt.ParseWithClaims(token, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
+		return jwtKey()
 	})
 
 	if claims, ok := parsedToken.Claims.(*jwt.StandardClaims); ok && parsedToken.Valid {
@@ -41,3 +41,48 @@ func TestCreateJWTToken(t *testing.T) {
 		t.Error("There was an error parsing the token:", err)
 	}
 }
+
+func TestParseJWTToken(t *testing.T) {
+	userId := uuid.NewString()
+	token, err := CreateJWTToken(userId, time.Hour)
+	if err != nil {
+		t.Fatal("Failed to create token:", err)
+	}
+
+	subject, err := ParseJWTToken(token)
+	if err != nil || subject != userId {
+		t.Errorf("Expected user id %s, got %s: %v", userId, subject, err)
+	}
+
+	expired, err := CreateJWTToken(userId, -time.Minute)
+	if err != nil {
+		t.Fatal("Failed to create token:", err)
+	}
+	if _, err := ParseJWTToken(expired); err == nil {
+		t.Error("Expected an expired token to be rejected")
+	}
+	if _, err := ParseJWTToken(token + "x"); err == nil {
+		t.Error("Expected a tampered token to be rejected")
+	}
+}
+
+func TestParseJWTToken_EmptyKey(t *testing.T) {
+	// a token signed without a key is not the server's, whatever the key is set to.
+	claims := &jwt.StandardClaims{Subject: uuid.NewString(), ExpiresAt: time.Now().Add(time.Hour).Unix()}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(""))
+	if err != nil {
+		t.Fatal("Failed to sign the token:", err)
+	}
+	if _, err := ParseJWTToken(forged); err == nil {
+		t.Error("Expected a token signed with an empty key to be rejected")
+	}
+
+	// without a key nothing is signed or verified.
+	t.Setenv("JWT_KEY", "")
+	if _, err := CreateJWTToken(uuid.NewString(), time.Hour); err == nil {
+		t.Error("Expected no token to be signed without a key")
+	}
+	if _, err := ParseJWTToken(forged); err == nil {
+		t.Error("Expected an empty key to verify no token")
+	}
+}